@@ -313,6 +313,7 @@ func action(ctx *cli.Context, stdout, stderr io.Writer) error {
 			ctx.String("local-db-path"),
 			userAgent,
 			ctx.Bool("download-offline-databases"),
+			false,
 		)
 		if err != nil {
 			return err
@@ -11,6 +11,7 @@ import (
 
 	"deps.dev/util/resolve"
 	"deps.dev/util/resolve/dep"
+	"deps.dev/util/semver"
 	"github.com/google/osv-scanner/v2/internal/datasource"
 	"github.com/google/osv-scanner/v2/internal/identifiers"
 	"github.com/google/osv-scanner/v2/internal/remediation"
@@ -115,6 +116,7 @@ func autoChooseInPlacePatches(res remediation.InPlaceResult, maxUpgrades int, ou
 	for _, vuln := range res.Unfixable {
 		v := makeResultVuln(vuln)
 		v.Unactionable = true
+		v.RequiresMajorUpgrade = requiresMajorUpgrade(vuln)
 		outputResult.Vulnerabilities = append(outputResult.Vulnerabilities, v)
 	}
 	sortVulns(outputResult.Vulnerabilities)
@@ -282,8 +284,7 @@ func autoOverride(ctx context.Context, opts osvFixOptions, maxUpgrades int) erro
 	if opts.ManifestRW.System() == resolve.Maven {
 		// Update Maven registries based on the repositories defined in pom.xml,
 		// as well as the repositories merged from parent pom.xml.
-		// TODO: add registries defined in settings.xml
-		// https://github.com/google/osv-scanner/issues/1269
+		// Registries defined in settings.xml are added when the client is created.
 		specific, ok := manif.EcosystemSpecific.(manifest.MavenManifestSpecific)
 		if ok {
 			registries := make([]client.Registry, len(specific.Repositories))
@@ -436,6 +437,53 @@ func makeResultVuln(vuln resolution.Vulnerability) vulnOutput {
 	return v
 }
 
+// requiresMajorUpgrade returns true if the vulnerability is fixed in every affected version
+// of its package, but only by versions in a later major version than the one in the graph
+func requiresMajorUpgrade(vuln resolution.Vulnerability) bool {
+	if len(vuln.Subgraphs) == 0 {
+		return false
+	}
+
+	for _, sg := range vuln.Subgraphs {
+		vk := sg.Nodes[sg.Dependency].Version
+		sys := vk.Semver()
+		installed, err := sys.Parse(vk.Version)
+		if err != nil {
+			return false
+		}
+
+		var nextFix *semver.Version
+		for _, affected := range vuln.OSV.Affected {
+			if affected.Package.Name != vk.Name {
+				continue
+			}
+			for _, r := range affected.Ranges {
+				for _, event := range r.Events {
+					if event.Fixed == "" {
+						continue
+					}
+					fixed, err := sys.Parse(event.Fixed)
+					if err != nil || fixed.Compare(installed) <= 0 {
+						continue
+					}
+					if nextFix == nil || fixed.Compare(nextFix) < 0 {
+						nextFix = fixed
+					}
+				}
+			}
+		}
+
+		if nextFix == nil {
+			return false
+		}
+		if _, diff := installed.Difference(nextFix); diff != semver.DiffMajor {
+			return false
+		}
+	}
+
+	return true
+}
+
 func populateResultVulns(outputResult *fixOutput, res *resolution.Result, allPatches []resolution.Difference) {
 	// Resolution errors
 	for _, err := range res.Errors() {
@@ -459,6 +507,7 @@ func populateResultVulns(outputResult *fixOutput, res *resolution.Result, allPat
 	for _, vuln := range res.Vulns {
 		v := makeResultVuln(vuln)
 		v.Unactionable = true
+		v.RequiresMajorUpgrade = requiresMajorUpgrade(vuln)
 		vulns[v.ID] = v
 	}
 
@@ -467,6 +516,7 @@ func populateResultVulns(outputResult *fixOutput, res *resolution.Result, allPat
 		for _, vuln := range p.RemovedVulns {
 			if v, ok := vulns[vuln.OSV.ID]; ok {
 				v.Unactionable = false
+				v.RequiresMajorUpgrade = false
 				vulns[vuln.OSV.ID] = v
 			}
 		}
@@ -0,0 +1,109 @@
+package fix
+
+import (
+	"testing"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/v2/internal/resolution"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+func Test_requiresMajorUpgrade(t *testing.T) {
+	t.Parallel()
+
+	subgraph := func(name, version string) *resolution.DependencySubgraph {
+		return &resolution.DependencySubgraph{
+			Dependency: 1,
+			Nodes: map[resolve.NodeID]resolution.GraphNode{
+				1: {
+					Version: resolve.VersionKey{
+						PackageKey: resolve.PackageKey{System: resolve.NPM, Name: name},
+						Version:    version,
+					},
+				},
+			},
+		}
+	}
+
+	affected := func(name string, fixed ...string) osvschema.Affected {
+		events := []osvschema.Event{{Introduced: "0"}}
+		for _, f := range fixed {
+			events = append(events, osvschema.Event{Fixed: f})
+		}
+
+		return osvschema.Affected{
+			Package: osvschema.Package{Ecosystem: "npm", Name: name},
+			Ranges:  []osvschema.Range{{Type: osvschema.RangeSemVer, Events: events}},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		affected  []osvschema.Affected
+		subgraphs []*resolution.DependencySubgraph
+		want      bool
+	}{
+		{
+			name:      "fixed in the same major version",
+			affected:  []osvschema.Affected{affected("foo", "1.4.0")},
+			subgraphs: []*resolution.DependencySubgraph{subgraph("foo", "1.2.3")},
+			want:      false,
+		},
+		{
+			name:      "only fixed in a later major version",
+			affected:  []osvschema.Affected{affected("foo", "2.0.1")},
+			subgraphs: []*resolution.DependencySubgraph{subgraph("foo", "1.2.3")},
+			want:      true,
+		},
+		{
+			name:      "the next fix is in the same major version",
+			affected:  []osvschema.Affected{affected("foo", "3.0.0", "1.9.9")},
+			subgraphs: []*resolution.DependencySubgraph{subgraph("foo", "1.2.3")},
+			want:      false,
+		},
+		{
+			name:      "fixes of other packages are ignored",
+			affected:  []osvschema.Affected{affected("foo", "2.0.0"), affected("bar", "1.3.0")},
+			subgraphs: []*resolution.DependencySubgraph{subgraph("foo", "1.2.3")},
+			want:      true,
+		},
+		{
+			name:      "no fix",
+			affected:  []osvschema.Affected{affected("foo")},
+			subgraphs: []*resolution.DependencySubgraph{subgraph("foo", "1.2.3")},
+			want:      false,
+		},
+		{
+			name:     "one of the versions can be fixed without a major upgrade",
+			affected: []osvschema.Affected{affected("foo", "1.4.0", "2.0.1")},
+			subgraphs: []*resolution.DependencySubgraph{
+				subgraph("foo", "1.2.3"),
+				subgraph("foo", "2.0.0"),
+			},
+			want: false,
+		},
+		{
+			name:     "every version requires a major upgrade",
+			affected: []osvschema.Affected{affected("foo", "3.0.0")},
+			subgraphs: []*resolution.DependencySubgraph{
+				subgraph("foo", "1.2.3"),
+				subgraph("foo", "2.0.0"),
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			vuln := resolution.Vulnerability{
+				OSV:       osvschema.Vulnerability{ID: "OSV-1", Affected: tt.affected},
+				Subgraphs: tt.subgraphs,
+			}
+
+			if got := requiresMajorUpgrade(vuln); got != tt.want {
+				t.Errorf("requiresMajorUpgrade() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -23,9 +23,10 @@ type fixOutput struct {
 
 // vulnOutput represents a vulnerability that was found in a project.
 type vulnOutput struct {
-	ID           string          `json:"id"`                     // the OSV ID of the vulnerability.
-	Packages     []packageOutput `json:"packages"`               // the list of packages in the dependency graph this vuln affects.
-	Unactionable bool            `json:"unactionable,omitempty"` // true if no fix patch available, or if constraints would prevent one.
+	ID                   string          `json:"id"`                             // the OSV ID of the vulnerability.
+	Packages             []packageOutput `json:"packages"`                       // the list of packages in the dependency graph this vuln affects.
+	Unactionable         bool            `json:"unactionable,omitempty"`         // true if no fix patch available, or if constraints would prevent one.
+	RequiresMajorUpgrade bool            `json:"requiresMajorUpgrade,omitempty"` // true if unactionable, but fixed in a later major version of the affected packages.
 }
 
 // patchOutput represents an isolated patch to one or more dependencies that fixes one or more vulns.
@@ -94,6 +95,7 @@ func outputText(_ io.Writer, out fixOutput) error {
 		slog.Info("No dependency patches are possible")
 		slog.Info(fmt.Sprintf("REMAINING-VULNS: %d", nVulns))
 		slog.Info(fmt.Sprintf("UNFIXABLE-VULNS: %d", nVulns))
+		logMajorUpgradeVulns(out.Vulnerabilities)
 
 		return nil
 	}
@@ -133,10 +135,25 @@ func outputText(_ io.Writer, out fixOutput) error {
 		}
 	}
 	slog.Info(fmt.Sprintf("UNFIXABLE-VULNS: %d", nUnfixable))
+	logMajorUpgradeVulns(out.Vulnerabilities)
 
 	return nil
 }
 
+// logMajorUpgradeVulns lists the unfixable vulnerabilities that could be fixed by a major upgrade, if there are any
+func logMajorUpgradeVulns(vulns []vulnOutput) {
+	var ids []string
+	for _, v := range vulns {
+		if v.RequiresMajorUpgrade {
+			ids = append(ids, v.ID)
+		}
+	}
+
+	if len(ids) > 0 {
+		slog.Info("MAJOR-UPGRADE-VULN-IDS: " + strings.Join(ids, ","))
+	}
+}
+
 func outputJSON(w io.Writer, out fixOutput) error {
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
@@ -1,16 +1,22 @@
 package helper
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 	"time"
 
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/githubmatcher"
 	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/osvdev"
+	"github.com/google/osv-scanner/v2/internal/output"
 	"github.com/google/osv-scanner/v2/internal/reporter"
 	"github.com/google/osv-scanner/v2/internal/spdx"
 	"github.com/google/osv-scanner/v2/pkg/models"
@@ -24,6 +30,8 @@ import (
 var OfflineFlags = map[string]string{
 	"offline-vulnerabilities": "true",
 	"no-resolve":              "true",
+	"github-advisories":       "false",
+	"epss":                    "false",
 }
 
 // sets default port(8000) as a global variable
@@ -61,20 +69,47 @@ func GetScanGlobalFlags() []cli.Flag {
 			TakesFile: true,
 		},
 		&cli.StringFlag{
+			Name:      "base-config",
+			Usage:     "set a config file to merge into every config file that is found, such as one shared across repositories; ignored if --config is set",
+			TakesFile: true,
+			EnvVars:   []string{"OSV_SCANNER_BASE_CONFIG"},
+		},
+		&cli.StringSliceFlag{
 			Name:    "format",
 			Aliases: []string{"f"},
-			Usage:   "sets the output format; value can be: " + strings.Join(reporter.Format(), ", "),
-			Value:   "table",
-			Action: func(_ *cli.Context, s string) error {
-				if slices.Contains(reporter.Format(), s) {
+			Usage:   "sets the output format, which can be given multiple times along with an --output for each format; value can be: " + strings.Join(reporter.Format(), ", "),
+			Value:   cli.NewStringSlice("table"),
+			Action: func(_ *cli.Context, formats []string) error {
+				for _, s := range formats {
+					if !slices.Contains(reporter.Format(), s) {
+						return fmt.Errorf("unsupported output format \"%s\" - must be one of: %s", s, strings.Join(reporter.Format(), ", "))
+					}
+
 					if s != "vertical" && s != "table" && s != "markdown" {
 						cmdlogger.SendEverythingToStderr()
 					}
+				}
 
-					return nil
+				return nil
+			},
+		},
+		&cli.StringFlag{
+			Name:      "template",
+			Usage:     "formats the output using the given Go text/template file, instead of one of the built-in formats",
+			TakesFile: true,
+			Action: func(c *cli.Context, p string) error {
+				if c.IsSet("format") || c.Bool("serve") {
+					return errors.New("--template cannot be used with --format or --serve")
 				}
 
-				return fmt.Errorf("unsupported output format \"%s\" - must be one of: %s", s, strings.Join(reporter.Format(), ", "))
+				// parse the template upfront so mistakes are reported before scanning
+				if _, err := readOutputTemplate(p); err != nil {
+					return err
+				}
+
+				cmdlogger.SendEverythingToStderr()
+
+				return nil
 			},
 		},
 		&cli.BoolFlag{
@@ -89,9 +124,9 @@ func GetScanGlobalFlags() []cli.Flag {
 				return nil
 			},
 		},
-		&cli.StringFlag{
+		&cli.StringSliceFlag{
 			Name:      "output",
-			Usage:     "saves the result to the given file path",
+			Usage:     "saves the result to the given file path, which can be given multiple times to save the result in the --format at the same position to each file",
 			TakesFile: true,
 		},
 		&cli.StringFlag{
@@ -110,6 +145,41 @@ func GetScanGlobalFlags() []cli.Flag {
 				return nil
 			},
 		},
+		&cli.StringFlag{
+			Name:  "log-format",
+			Usage: "sets the format of the logs written during runtime; value can be: " + strings.Join(cmdlogger.Formats(), ", "),
+			Value: "text",
+			Action: func(_ *cli.Context, s string) error {
+				if !slices.Contains(cmdlogger.Formats(), s) {
+					return fmt.Errorf("unsupported log format \"%s\" - must be one of: %s", s, strings.Join(cmdlogger.Formats(), ", "))
+				}
+
+				if s == "json" {
+					cmdlogger.UseJSON()
+				}
+
+				return nil
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "no-progress",
+			Usage: "disables the progress updates that are shown on stderr when it is a terminal",
+		},
+		&cli.BoolFlag{
+			Name:  "quiet",
+			Usage: "only output the result if the scan finds vulnerabilities or license violations, and only log errors",
+			Action: func(_ *cli.Context, b bool) error {
+				if b {
+					cmdlogger.SetLevel(slog.LevelError)
+				}
+
+				return nil
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "quiet-print-empty",
+			Usage: "with --quiet, still output the result of scans that find nothing",
+		},
 		&cli.BoolFlag{
 			Name:  "offline",
 			Usage: "run in offline mode, disabling any features requiring network access",
@@ -149,6 +219,62 @@ func GetScanGlobalFlags() []cli.Flag {
 			Usage:  "sets the path that local databases should be stored",
 			Hidden: true,
 		},
+		&cli.StringFlag{
+			Name:  "osv-base-url",
+			Usage: "sets the base URL of the OSV API to query, for using a self-hosted mirror of api.osv.dev",
+			Action: func(_ *cli.Context, s string) error {
+				u, err := url.Parse(s)
+				if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					return fmt.Errorf("--osv-base-url must be an absolute http(s) URL, got %q", s)
+				}
+
+				return nil
+			},
+		},
+		&cli.StringSliceFlag{
+			Name:  "osv-header",
+			Usage: "sets a header to send with requests to the OSV API (e.g. \"Authorization: Bearer <token>\"); can be repeated",
+			Action: func(_ *cli.Context, headers []string) error {
+				_, err := parseHeaders(headers)
+
+				return err
+			},
+		},
+		&cli.IntFlag{
+			Name:  "osv-batch-size",
+			Usage: "sets the number of packages to query in each batch request to the OSV API",
+			Value: osvdev.MaxQueriesPerQueryBatchRequest,
+			Action: func(_ *cli.Context, i int) error {
+				if i < 1 || i > osvdev.MaxQueriesPerQueryBatchRequest {
+					return fmt.Errorf("--osv-batch-size must be between 1 and %d, got %d", osvdev.MaxQueriesPerQueryBatchRequest, i)
+				}
+
+				return nil
+			},
+		},
+		&cli.IntFlag{
+			Name:  "osv-max-concurrent-batches",
+			Usage: "sets the number of batch requests to make to the OSV API at once",
+			Value: osvdev.DefaultConfig().MaxConcurrentBatchRequests,
+			Action: func(_ *cli.Context, i int) error {
+				if i < 1 {
+					return fmt.Errorf("--osv-max-concurrent-batches must be at least 1, got %d", i)
+				}
+
+				return nil
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "github-advisories",
+			Usage: "also match packages against GitHub's security advisories, including private ones, using the token in $" + githubmatcher.TokenEnvVar,
+			Action: func(_ *cli.Context, b bool) error {
+				if b && os.Getenv(githubmatcher.TokenEnvVar) == "" {
+					return fmt.Errorf("--github-advisories requires a GitHub token to be set in $%s", githubmatcher.TokenEnvVar)
+				}
+
+				return nil
+			},
+		},
 		&cli.BoolFlag{
 			Name:  "no-resolve",
 			Usage: "disable transitive dependency resolution of manifest files",
@@ -157,6 +283,71 @@ func GetScanGlobalFlags() []cli.Flag {
 			Name:  "all-packages",
 			Usage: "when json output is selected, prints all packages",
 		},
+		&cli.BoolFlag{
+			Name:  "no-dev",
+			Usage: "exclude development dependencies (e.g. devDependencies, dev-dependencies) from the scan",
+		},
+		&cli.Float64Flag{
+			Name:  "fail-on-severity",
+			Usage: "only exit with a non-zero code for vulnerabilities with a CVSS score of at least this value (e.g. 7.0 for high and critical)",
+			Action: func(_ *cli.Context, f float64) error {
+				if f < 0 || f > 10 {
+					return fmt.Errorf("--fail-on-severity must be a CVSS score between 0 and 10, got %v", f)
+				}
+
+				return nil
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "epss",
+			Usage: "fetch the EPSS scores of the CVEs of each vulnerability from FIRST, to prioritize them by their likelihood of being exploited",
+		},
+		&cli.Float64Flag{
+			Name:  "min-epss",
+			Usage: "only exit with a non-zero code for vulnerabilities with an EPSS score of at least this probability (e.g. 0.1 for 10%); implies --epss",
+			Action: func(_ *cli.Context, f float64) error {
+				if f < 0 || f > 1 {
+					return fmt.Errorf("--min-epss must be a probability between 0 and 1, got %v", f)
+				}
+
+				return nil
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "kev",
+			Usage: "flag vulnerabilities that are in CISA's Known Exploited Vulnerabilities catalog, which is cached locally and refreshed daily",
+		},
+		&cli.BoolFlag{
+			Name:  "kev-only",
+			Usage: "only report vulnerabilities that are in CISA's Known Exploited Vulnerabilities catalog; implies --kev",
+		},
+		&cli.StringFlag{
+			Name:      "baseline",
+			Usage:     "only report vulnerabilities that are not in the given json output of a previous scan",
+			TakesFile: true,
+		},
+		&cli.StringSliceFlag{
+			Name:      "vex",
+			Usage:     "suppress vulnerabilities that the given OpenVEX or CycloneDX VEX document states do not affect their package",
+			TakesFile: true,
+		},
+		&cli.StringSliceFlag{
+			Name:      "extra-advisories",
+			Usage:     "also match packages against the OSV advisories in the given file or directory, such as internal advisories",
+			TakesFile: true,
+		},
+		&cli.BoolFlag{
+			Name:  "include-withdrawn",
+			Usage: "include vulnerabilities whose advisories have been withdrawn in the results",
+		},
+		&cli.BoolFlag{
+			Name:  "all-vulns",
+			Usage: "also list the vulnerabilities of each package that do not affect its version, separately to the findings; requires --offline-vulnerabilities",
+		},
+		&cli.BoolFlag{
+			Name:  "collapse-sources",
+			Usage: "collapse identical vulnerabilities of the same package version found in multiple sources into a single finding",
+		},
 		&cli.GenericFlag{
 			Name:  "licenses",
 			Usage: "report on licenses based on an allowlist",
@@ -164,6 +355,10 @@ func GetScanGlobalFlags() []cli.Flag {
 				allowList: "",
 			},
 		},
+		&cli.BoolFlag{
+			Name:  "allow-unknown-licenses",
+			Usage: "do not report packages with an unknown license as violating the --licenses allowlist",
+		},
 	}
 }
 
@@ -187,25 +382,154 @@ func ServeHTML(outputPath string) {
 	}
 }
 
-func PrintResult(stdout, stderr io.Writer, outputPath, format string, diffVulns *models.VulnerabilityResults) error {
-	termWidth := 0
-	var err error
-	if outputPath != "" { // Output is definitely a file
-		stdout, err = os.Create(outputPath)
-		if err != nil {
-			return fmt.Errorf("failed to create output file: %w", err)
+// Output is a format that the result of a scan is written in, along with
+// the path of the file that it is written to, which is empty for stdout
+type Output struct {
+	Format string
+	Path   string
+}
+
+// GetOutputs pairs each --format with the --output at the same position, so that the result of a
+// scan can be written in multiple formats at once, each to a file of its own
+func GetOutputs(context *cli.Context) ([]Output, error) {
+	formats := context.StringSlice("format")
+	paths := context.StringSlice("output")
+
+	if len(formats) == 1 && len(paths) <= 1 {
+		output := Output{Format: formats[0]}
+		if len(paths) == 1 {
+			output.Path = paths[0]
 		}
-	} else { // Output might be a terminal
-		if stdoutAsFile, ok := stdout.(*os.File); ok {
-			termWidth, _, err = term.GetSize(int(stdoutAsFile.Fd()))
-			if err != nil { // If output is not a terminal,
-				termWidth = 0
+
+		return []Output{output}, nil
+	}
+
+	if len(formats) != len(paths) {
+		return nil, errors.New("--format and --output must be given the same number of times to write multiple outputs")
+	}
+	if context.IsSet("template") || context.Bool("serve") {
+		return nil, errors.New("--template and --serve cannot be used with multiple outputs")
+	}
+
+	outputs := make([]Output, 0, len(formats))
+	for i, format := range formats {
+		if slices.ContainsFunc(outputs, func(o Output) bool { return o.Path == paths[i] }) {
+			return nil, fmt.Errorf("--output %s is given more than once", paths[i])
+		}
+
+		outputs = append(outputs, Output{Format: format, Path: paths[i]})
+	}
+
+	return outputs, nil
+}
+
+// PrintResults writes the result in each of the outputs.
+//
+// Scans with a cyclonedx output include every package and their ignored vulnerabilities for its
+// VEX statements, which are removed from the result written in the other formats, unless allPackages
+// (i.e. --all-packages) is set to keep the packages without anything to report
+func PrintResults(stdout, stderr io.Writer, outputs []Output, templatePath string, allPackages bool, diffVulns *models.VulnerabilityResults) error {
+	hasVEX := slices.ContainsFunc(outputs, func(o Output) bool { return o.Format == "cyclonedx" })
+
+	for _, output := range outputs {
+		vulns := diffVulns
+		if hasVEX && output.Format != "cyclonedx" {
+			vulns = withoutVEXData(diffVulns, allPackages)
+		}
+
+		if err := PrintResult(stdout, stderr, output.Path, output.Format, templatePath, vulns); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// withoutVEXData returns a copy of the result without the ignored vulnerabilities of each package,
+// or the packages (and sources) that have nothing to report, unless allPackages is set
+func withoutVEXData(vulnResult *models.VulnerabilityResults, allPackages bool) *models.VulnerabilityResults {
+	filtered := *vulnResult
+	filtered.Results = make([]models.PackageSource, 0, len(vulnResult.Results))
+
+	for _, source := range vulnResult.Results {
+		packages := make([]models.PackageVulns, 0, len(source.Packages))
+		for _, pkg := range source.Packages {
+			pkg.ExperimentalIgnoredVulnerabilities = nil
+
+			if allPackages || len(pkg.Vulnerabilities) > 0 || len(pkg.LicenseViolations) > 0 || len(pkg.ExperimentalUnaffectedVulnerabilities) > 0 {
+				packages = append(packages, pkg)
 			}
 		}
+
+		if len(packages) > 0 {
+			source.Packages = packages
+			filtered.Results = append(filtered.Results, source)
+		}
 	}
 
+	return &filtered
+}
+
+func PrintResult(stdout, stderr io.Writer, outputPath, format, templatePath string, diffVulns *models.VulnerabilityResults) error {
+	if outputPath != "" { // Output is definitely a file
+		return writeFileAtomically(outputPath, func(f io.Writer) error {
+			return printResult(f, stderr, 0, format, templatePath, diffVulns)
+		})
+	}
+
+	// Output might be a terminal
+	termWidth := 0
+	if stdoutAsFile, ok := stdout.(*os.File); ok {
+		var err error
+		termWidth, _, err = term.GetSize(int(stdoutAsFile.Fd()))
+		if err != nil { // If output is not a terminal,
+			termWidth = 0
+		}
+	}
+
+	return printResult(stdout, stderr, termWidth, format, templatePath, diffVulns)
+}
+
+// writeFileAtomically writes the file at path using write, which is done in a temporary file
+// that replaces the file once it has been written so that it is never seen partially written
+func writeFileAtomically(path string, write func(io.Writer) error) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	// the temporary file no longer exists once it has replaced the file
+	defer os.Remove(f.Name())
+
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	// temporary files are only readable by their owner, unlike files that are created normally
+	if err := f.Chmod(0644); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+func printResult(stdout, stderr io.Writer, termWidth int, format, templatePath string, diffVulns *models.VulnerabilityResults) error {
 	writer := stdout
 
+	if templatePath != "" {
+		templateText, err := readOutputTemplate(templatePath)
+		if err != nil {
+			return err
+		}
+
+		return reporter.PrintTemplateResult(diffVulns, templateText, writer)
+	}
+
 	if format == "gh-annotations" {
 		writer = stderr
 	}
@@ -213,6 +537,32 @@ func PrintResult(stdout, stderr io.Writer, outputPath, format string, diffVulns
 	return reporter.PrintResult(diffVulns, format, writer, termWidth)
 }
 
+// SkipPrintingResult returns true if --quiet is set and the scan did not find any
+// vulnerabilities or license violations, meaning nothing should be output
+func SkipPrintingResult(context *cli.Context, vulnResult *models.VulnerabilityResults) bool {
+	if !context.Bool("quiet") || context.Bool("quiet-print-empty") {
+		return false
+	}
+
+	// vulnerabilities are still output when they do not fail the scan, such as with --fail-on-severity
+	return len(vulnResult.Flatten()) == 0
+}
+
+// readOutputTemplate reads the custom output template at the given path,
+// ensuring that it can be parsed
+func readOutputTemplate(templatePath string) (string, error) {
+	b, err := os.ReadFile(templatePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read output template: %w", err)
+	}
+
+	if _, err := output.ParseOutputTemplate(string(b)); err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
 func GetScanLicensesAllowlist(context *cli.Context) ([]string, error) {
 	allowlist := strings.Split(context.Generic("licenses").(*licenseGenericFlag).String(), ",")
 
@@ -225,6 +575,10 @@ func GetScanLicensesAllowlist(context *cli.Context) ([]string, error) {
 		return []string{}, nil
 	}
 
+	for i, license := range allowlist {
+		allowlist[i] = string(spdx.Normalize(models.License(license)))
+	}
+
 	if unrecognized := spdx.Unrecognized(allowlist); len(unrecognized) > 0 {
 		return nil, fmt.Errorf("--licenses requires comma-separated spdx licenses. The following license(s) are not recognized as spdx: %s", strings.Join(unrecognized, ","))
 	}
@@ -237,12 +591,65 @@ func GetScanLicensesAllowlist(context *cli.Context) ([]string, error) {
 }
 
 func GetExperimentalScannerActions(context *cli.Context, scanLicensesAllowlist []string) osvscanner.ExperimentalScannerActions {
+	// the headers have already been validated by the flag
+	osvHeaders, _ := parseHeaders(context.StringSlice("osv-header"))
+
+	// the CycloneDX VEX output is an SBOM of every package that was scanned,
+	// with statements about the vulnerabilities that were ignored too
+	vex := slices.Contains(context.StringSlice("format"), "cyclonedx")
+
 	return osvscanner.ExperimentalScannerActions{
-		LocalDBPath:           context.String("local-db-path"),
-		DownloadDatabases:     context.Bool("download-offline-databases"),
-		CompareOffline:        context.Bool("offline-vulnerabilities"),
-		ShowAllPackages:       context.Bool("all-packages"),
-		ScanLicensesSummary:   context.IsSet("licenses"),
-		ScanLicensesAllowlist: scanLicensesAllowlist,
+		LocalDBPath:              context.String("local-db-path"),
+		DownloadDatabases:        context.Bool("download-offline-databases"),
+		CompareOffline:           context.Bool("offline-vulnerabilities"),
+		ShowAllPackages:          context.Bool("all-packages") || vex,
+		IncludeIgnored:           vex,
+		NoDevDependencies:        context.Bool("no-dev"),
+		FailOnSeverity:           context.Float64("fail-on-severity"),
+		EPSS:                     context.Bool("epss"),
+		MinEPSS:                  context.Float64("min-epss"),
+		KEV:                      context.Bool("kev"),
+		KEVOnly:                  context.Bool("kev-only"),
+		CollapseSources:          context.Bool("collapse-sources"),
+		BaselinePath:             context.String("baseline"),
+		VEXPaths:                 context.StringSlice("vex"),
+		ExtraAdvisoryPaths:       context.StringSlice("extra-advisories"),
+		IncludeWithdrawn:         context.Bool("include-withdrawn"),
+		AllVulns:                 context.Bool("all-vulns"),
+		ScanLicensesSummary:      context.IsSet("licenses"),
+		ScanLicensesAllowlist:    scanLicensesAllowlist,
+		ScanLicensesAllowUnknown: context.Bool("allow-unknown-licenses"),
+		OSVBaseURL:               context.String("osv-base-url"),
+		OSVHeaders:               osvHeaders,
+		OSVBatchSize:             context.Int("osv-batch-size"),
+		OSVMaxConcurrentBatches:  context.Int("osv-max-concurrent-batches"),
+		GitHubToken:              githubToken(context),
 	}
 }
+
+// githubToken returns the token for matching against GitHub's security advisories, which is
+// only read from the environment so that it does not end up in shell histories or CI logs
+func githubToken(context *cli.Context) string {
+	if !context.Bool("github-advisories") {
+		return ""
+	}
+
+	return os.Getenv(githubmatcher.TokenEnvVar)
+}
+
+// parseHeaders parses headers in the form of "Name: value"
+func parseHeaders(headers []string) (map[string]string, error) {
+	parsed := make(map[string]string, len(headers))
+	for _, header := range headers {
+		name, value, ok := strings.Cut(header, ":")
+		name = strings.TrimSpace(name)
+
+		if !ok || name == "" {
+			return nil, fmt.Errorf("header %q must be in the form of \"Name: value\"", header)
+		}
+
+		parsed[http.CanonicalHeaderKey(name)] = strings.TrimSpace(value)
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,233 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"github.com/urfave/cli/v2"
+)
+
+func TestParseHeaders(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		headers  []string
+		expected map[string]string
+		wantErr  bool
+	}{
+		{
+			headers:  []string{},
+			expected: map[string]string{},
+		},
+		{
+			headers: []string{"Authorization: Bearer abc:123", "x-team:security"},
+			expected: map[string]string{
+				"Authorization": "Bearer abc:123",
+				"X-Team":        "security",
+			},
+		},
+		{
+			headers: []string{"Authorization"},
+			wantErr: true,
+		},
+		{
+			headers: []string{": value"},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := parseHeaders(testCase.headers)
+
+		if (err != nil) != testCase.wantErr {
+			t.Errorf("parseHeaders(%v) error = %v, wantErr %v", testCase.headers, err, testCase.wantErr)
+		}
+
+		if !testCase.wantErr && !reflect.DeepEqual(actual, testCase.expected) {
+			t.Errorf("parseHeaders(%v) = %v, want %v", testCase.headers, actual, testCase.expected)
+		}
+	}
+}
+
+func TestSkipPrintingResult(t *testing.T) {
+	t.Parallel()
+
+	withPackage := func(pkg models.PackageVulns) *models.VulnerabilityResults {
+		return &models.VulnerabilityResults{
+			Results: []models.PackageSource{{Packages: []models.PackageVulns{pkg}}},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		vulnResult *models.VulnerabilityResults
+		want       bool
+	}{
+		{
+			name:       "not quiet",
+			args:       []string{},
+			vulnResult: &models.VulnerabilityResults{},
+			want:       false,
+		},
+		{
+			name:       "nothing found",
+			args:       []string{"--quiet"},
+			vulnResult: withPackage(models.PackageVulns{}),
+			want:       true,
+		},
+		{
+			name:       "nothing found, printing empty results",
+			args:       []string{"--quiet", "--quiet-print-empty"},
+			vulnResult: &models.VulnerabilityResults{},
+			want:       false,
+		},
+		{
+			name: "vulnerabilities found",
+			args: []string{"--quiet"},
+			vulnResult: withPackage(models.PackageVulns{
+				Vulnerabilities: []osvschema.Vulnerability{{ID: "GHSA-1"}},
+				Groups:          []models.GroupInfo{{IDs: []string{"GHSA-1"}}},
+			}),
+			want: false,
+		},
+		{
+			name:       "license violations found",
+			args:       []string{"--quiet"},
+			vulnResult: withPackage(models.PackageVulns{LicenseViolations: []models.License{"GPL-3.0"}}),
+			want:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			set := flag.NewFlagSet("test", flag.ContinueOnError)
+			set.Bool("quiet", false, "")
+			set.Bool("quiet-print-empty", false, "")
+			if err := set.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := SkipPrintingResult(cli.NewContext(nil, set, nil), tt.vulnResult); got != tt.want {
+				t.Errorf("SkipPrintingResult() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintResults_WithoutVEXData(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+						Vulnerabilities: []osvschema.Vulnerability{{ID: "GHSA-35jh-r3h4-6jhm"}},
+						Groups:          []models.GroupInfo{{IDs: []string{"GHSA-35jh-r3h4-6jhm"}}},
+						ExperimentalIgnoredVulnerabilities: []models.IgnoredVulnerability{
+							{Vulnerability: osvschema.Vulnerability{ID: "GHSA-p6mc-m468-83gw"}, State: "not_affected"},
+						},
+					},
+					{
+						Package: models.PackageInfo{Name: "left-pad", Version: "1.3.0", Ecosystem: "npm"},
+						ExperimentalIgnoredVulnerabilities: []models.IgnoredVulnerability{
+							{Vulnerability: osvschema.Vulnerability{ID: "GHSA-0000-0000-0000"}, State: "not_affected"},
+						},
+					},
+				},
+			},
+			{
+				Source:   models.SourceInfo{Path: "/path/to/other/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{{Package: models.PackageInfo{Name: "minimist", Version: "1.2.8", Ecosystem: "npm"}}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		allPackages bool
+		want        []string
+	}{
+		{
+			name: "only packages with vulnerabilities",
+			want: []string{"/path/to/package-lock.json: lodash"},
+		},
+		{
+			name:        "all packages",
+			allPackages: true,
+			want: []string{
+				"/path/to/package-lock.json: lodash",
+				"/path/to/package-lock.json: left-pad",
+				"/path/to/other/package-lock.json: minimist",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			outputs := []Output{
+				{Format: "cyclonedx", Path: filepath.Join(dir, "results.cdx.json")},
+				{Format: "json", Path: filepath.Join(dir, "results.json")},
+			}
+
+			if err := PrintResults(&bytes.Buffer{}, &bytes.Buffer{}, outputs, "", tt.allPackages, vulnResult); err != nil {
+				t.Fatalf("PrintResults() error = %v", err)
+			}
+
+			// the cyclonedx output still has every package, along with the ignored vulnerabilities
+			cdx, err := os.ReadFile(outputs[0].Path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range []string{"left-pad", "minimist", "GHSA-p6mc-m468-83gw"} {
+				if !strings.Contains(string(cdx), want) {
+					t.Errorf("cyclonedx output is missing %s", want)
+				}
+			}
+
+			content, err := os.ReadFile(outputs[1].Path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got models.VulnerabilityResults
+			if err := json.Unmarshal(content, &got); err != nil {
+				t.Fatal(err)
+			}
+
+			var packages []string
+			for _, source := range got.Results {
+				for _, pkg := range source.Packages {
+					packages = append(packages, source.Source.Path+": "+pkg.Package.Name)
+					if len(pkg.ExperimentalIgnoredVulnerabilities) > 0 {
+						t.Errorf("json output has the ignored vulnerabilities of %s", pkg.Package.Name)
+					}
+				}
+			}
+
+			if diff := cmp.Diff(tt.want, packages); diff != "" {
+				t.Errorf("json output packages mismatch (-want +got):\n%s", diff)
+			}
+
+			// the result itself is not changed
+			if n := len(vulnResult.Results[0].Packages[0].ExperimentalIgnoredVulnerabilities); n != 1 {
+				t.Errorf("PrintResults() changed the ignored vulnerabilities of the result, which has %d", n)
+			}
+		})
+	}
+}
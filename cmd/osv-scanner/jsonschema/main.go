@@ -0,0 +1,43 @@
+// Package jsonschema implements the json-schema command, which prints the JSON Schema of
+// the results written by the json output format.
+package jsonschema
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/google/osv-scanner/v2/internal/jsonschema"
+	"github.com/google/osv-scanner/v2/internal/version"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/urfave/cli/v2"
+)
+
+func Command(stdout io.Writer) *cli.Command {
+	return &cli.Command{
+		Name:        "json-schema",
+		Usage:       "prints the JSON Schema of the results written by the json output format",
+		Description: "prints the JSON Schema of the results written by the json output format, which is generated from the result model of this version of osv-scanner",
+		Action: func(_ *cli.Context) error {
+			return printSchema(stdout)
+		},
+	}
+}
+
+// Schema returns the JSON Schema of the results written by the json output format.
+//
+// The schema is identified by the version of osv-scanner, as its results can change between versions.
+func Schema() *jsonschema.Schema {
+	schema := jsonschema.For[models.VulnerabilityResults]()
+	schema.ID = "urn:osv-scanner:results:" + version.OSVVersion
+	schema.Title = "OSV-Scanner results"
+	schema.Description = "The results of a scan by osv-scanner v" + version.OSVVersion + ", as written by the json output format"
+
+	return schema
+}
+
+func printSchema(stdout io.Writer) error {
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(Schema())
+}
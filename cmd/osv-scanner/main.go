@@ -9,6 +9,7 @@ import (
 	"slices"
 
 	"github.com/google/osv-scanner/v2/cmd/osv-scanner/fix"
+	"github.com/google/osv-scanner/v2/cmd/osv-scanner/jsonschema"
 	"github.com/google/osv-scanner/v2/cmd/osv-scanner/scan"
 	"github.com/google/osv-scanner/v2/cmd/osv-scanner/update"
 	"github.com/google/osv-scanner/v2/internal/cmdlogger"
@@ -45,6 +46,7 @@ func run(args []string, stdout, stderr io.Writer) int {
 			scan.Command(stdout, stderr),
 			fix.Command(stdout, stderr),
 			update.Command(),
+			jsonschema.Command(stdout),
 		},
 		CustomAppHelpTemplate: getCustomHelpTemplate(),
 	}
@@ -73,6 +75,9 @@ func run(args []string, stdout, stderr io.Writer) int {
 		case errors.Is(err, osvscanner.ErrAPIFailed):
 			slog.Error(fmt.Sprintf("%v", err))
 			return 129
+		case errors.Is(err, osvscanner.ErrPartialScan):
+			// the files that could not be scanned will have already been warned about
+			return 3
 		}
 		slog.Error(fmt.Sprintf("%v", err))
 	}
@@ -145,7 +145,7 @@ func Test_run(t *testing.T) {
 		{
 			name: "all supported lockfiles in the directory should be checked",
 			args: []string{"", "./fixtures/locks-many-with-invalid"},
-			exit: 127,
+			exit: 3,
 		},
 		// only the files in the given directories are checked by default (no recursion)
 		{
@@ -238,11 +238,21 @@ func Test_run(t *testing.T) {
 			args: []string{"", "./fixtures/locks-test-ignore/package-lock.json"},
 			exit: 0,
 		},
+		{
+			name: "invalid --commit value",
+			args: []string{"", "--commit", "bd0d9bb", "./fixtures/locks-many/composer.lock"},
+			exit: 127,
+		},
 		{
 			name: "invalid --verbosity value",
 			args: []string{"", "--verbosity", "unknown", "./fixtures/locks-many/composer.lock"},
 			exit: 127,
 		},
+		{
+			name: "--direct-only and --transitive-only cannot be used together",
+			args: []string{"", "--direct-only", "--transitive-only", "./fixtures/locks-many/composer.lock"},
+			exit: 127,
+		},
 		{
 			name: "verbosity level = error",
 			args: []string{"", "--verbosity", "error", "--format", "table", "./fixtures/locks-many/composer.lock"},
@@ -413,9 +423,10 @@ func Test_run_LockfileWithExplicitParseAs(t *testing.T) {
 			exit: 1,
 		},
 		{
-			name: "files that error on parsing stop parsable files from being checked",
+			name: "files that error on parsing stop parsable files from being checked when strict",
 			args: []string{
 				"",
+				"--strict",
 				"-L",
 				"Cargo.lock:" + filepath.FromSlash("./fixtures/locks-insecure/my-package-lock.json"),
 				filepath.FromSlash("./fixtures/locks-insecure"),
@@ -430,7 +441,16 @@ func Test_run_LockfileWithExplicitParseAs(t *testing.T) {
 				"-L",
 				"package-lock.json:" + filepath.FromSlash("./fixtures/locks-many/yarn.lock"),
 			},
-			exit: 127,
+			exit: 128,
+		},
+		{
+			name: "patterns that do not match any files are warned about",
+			args: []string{
+				"",
+				"-L",
+				filepath.FromSlash("./fixtures/locks-many/**/*.nothing"),
+			},
+			exit: 128,
 		},
 		{
 			name: "\"apk-installed\" is supported",
@@ -509,7 +529,7 @@ func Test_run_LocalDatabases(t *testing.T) {
 		{
 			name: "all supported lockfiles in the directory should be checked",
 			args: []string{"", "--offline", "--download-offline-databases", "./fixtures/locks-many-with-invalid"},
-			exit: 127,
+			exit: 3,
 		},
 		{
 			name: "only the files in the given directories are checked by default (no recursion)",
@@ -589,6 +609,112 @@ func Test_run_LocalDatabases_AlwaysOffline(t *testing.T) {
 	}
 }
 
+func Test_run_Quiet(t *testing.T) {
+	tests := []cliTestCase{
+		{
+			name: "nothing is output when nothing is found",
+			args: []string{"", "--config=./fixtures/osv-scanner-ignore-packagist-config.toml", "--offline", "--quiet", "./fixtures/locks-many/composer.lock"},
+			exit: 0,
+		},
+		{
+			name: "nothing is output when nothing is found, even with json",
+			args: []string{"", "--config=./fixtures/osv-scanner-ignore-packagist-config.toml", "--offline", "--quiet", "--format", "json", "./fixtures/locks-many/composer.lock"},
+			exit: 0,
+		},
+		{
+			name: "the empty result is output with --quiet-print-empty",
+			args: []string{"", "--config=./fixtures/osv-scanner-ignore-packagist-config.toml", "--offline", "--quiet", "--quiet-print-empty", "--format", "json", "./fixtures/locks-many/composer.lock"},
+			exit: 0,
+		},
+		{
+			name: "errors are still logged",
+			args: []string{"", "--offline", "--quiet", "./fixtures/locks-empty/composer.lock"},
+			exit: 128,
+		},
+		{
+			name: "files that could not be parsed are logged as errors",
+			args: []string{
+				"",
+				"--config=./fixtures/osv-scanner-ignore-packagist-config.toml",
+				"--offline",
+				"--quiet",
+				"-L", "Cargo.lock:" + filepath.FromSlash("./fixtures/locks-insecure/my-package-lock.json"),
+				"./fixtures/locks-many/composer.lock",
+			},
+			exit: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testCli(t, tt)
+		})
+	}
+}
+
+func Test_run_MultipleOutputs(t *testing.T) {
+	dir := t.TempDir()
+
+	tc := cliTestCase{
+		name: "each format is written to its output",
+		args: []string{
+			"",
+			"--config=./fixtures/osv-scanner-ignore-packagist-config.toml",
+			"--offline",
+			"--format", "json", "--output", filepath.Join(dir, "results.json"),
+			"--format", "markdown", "--output", filepath.Join(dir, "results.md"),
+			"./fixtures/locks-many/composer.lock",
+		},
+		exit: 0,
+	}
+
+	testCli(t, tc)
+
+	for _, name := range []string{"results.json", "results.md"} {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("could not read output: %v", err)
+		}
+
+		testutility.NewSnapshot().MatchText(t, string(content))
+	}
+
+	// only the outputs themselves are written, without any temporary files being left behind
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("expected only the two outputs to be written, but got %d files", len(entries))
+	}
+}
+
+func Test_run_MultipleOutputs_Invalid(t *testing.T) {
+	tests := []cliTestCase{
+		{
+			name: "more formats than outputs",
+			args: []string{"", "--offline", "--format", "json", "--format", "sarif", "--output", "results.json", "./fixtures/locks-many/composer.lock"},
+			exit: 127,
+		},
+		{
+			name: "more outputs than formats",
+			args: []string{"", "--offline", "--output", "results.json", "--output", "results.sarif", "./fixtures/locks-many/composer.lock"},
+			exit: 127,
+		},
+		{
+			name: "the same output is given more than once",
+			args: []string{"", "--offline", "--format", "json", "--output", "results.json", "--format", "sarif", "--output", "results.json", "./fixtures/locks-many/composer.lock"},
+			exit: 127,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testCli(t, tt)
+		})
+	}
+}
+
 func Test_run_Licenses(t *testing.T) {
 	tests := []cliTestCase{
 		{
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 
 	"github.com/google/osv-scanner/v2/cmd/osv-scanner/internal/helper"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
 	"github.com/google/osv-scanner/v2/pkg/models"
 	"github.com/google/osv-scanner/v2/pkg/osvscanner"
 	"github.com/urfave/cli/v2"
@@ -19,6 +20,14 @@ var imageScanFlags = []cli.Flag{
 		Name:  "archive",
 		Usage: "input a local archive image (e.g. a tar file)",
 	},
+	&cli.BoolFlag{
+		Name:  "remote",
+		Usage: "pull the image directly from its registry rather than with docker",
+	},
+	&cli.StringFlag{
+		Name:  "platform",
+		Usage: "scan the image for the given platform (e.g. linux/arm64) if it is built for multiple platforms",
+	},
 }
 
 func Command(stdout, stderr io.Writer) *cli.Command {
@@ -35,13 +44,15 @@ func Command(stdout, stderr io.Writer) *cli.Command {
 }
 
 func action(context *cli.Context, stdout, stderr io.Writer) error {
-	format := context.String("format")
+	outputs, err := helper.GetOutputs(context)
+	if err != nil {
+		return err
+	}
 
-	outputPath := context.String("output")
 	serve := context.Bool("serve")
 	if serve {
-		format = "html"
-		if outputPath == "" {
+		outputs[0].Format = "html"
+		if outputs[0].Path == "" {
 			// Create a temporary directory
 			tmpDir, err := os.MkdirTemp("", "osv-scanner-result")
 			if err != nil {
@@ -51,7 +62,7 @@ func action(context *cli.Context, stdout, stderr io.Writer) error {
 
 			// Remove the created temporary directory after
 			defer os.RemoveAll(tmpDir)
-			outputPath = filepath.Join(tmpDir, "index.html")
+			outputs[0].Path = filepath.Join(tmpDir, "index.html")
 		}
 	}
 
@@ -63,31 +74,48 @@ func action(context *cli.Context, stdout, stderr io.Writer) error {
 	if context.Args().Len() == 0 {
 		return errors.New("please provide an image name or see the help document")
 	}
+	if context.Bool("archive") && (context.Bool("remote") || context.IsSet("platform")) {
+		return errors.New("--remote and --platform cannot be used with --archive")
+	}
 	scannerAction := osvscanner.ScannerActions{
 		Image:                      context.Args().First(),
 		ConfigOverridePath:         context.String("config"),
+		BaseConfigPath:             context.String("base-config"),
 		IsImageArchive:             context.Bool("archive"),
+		IsImageRemote:              context.Bool("remote"),
+		ImagePlatform:              context.String("platform"),
 		IncludeGitRoot:             context.Bool("include-git-root"),
 		ExperimentalScannerActions: helper.GetExperimentalScannerActions(context, scanLicensesAllowlist),
 	}
 
+	if !context.Bool("no-progress") && !context.Bool("quiet") {
+		cmdlogger.ShowProgress()
+	}
+
 	var vulnResult models.VulnerabilityResults
-	vulnResult, err = osvscanner.DoContainerScan(scannerAction)
+	vulnResult, err = osvscanner.DoContainerScanContext(context.Context, scannerAction)
 
 	if err != nil && !errors.Is(err, osvscanner.ErrVulnerabilitiesFound) {
 		return err
 	}
 
-	if errPrint := helper.PrintResult(stdout, stderr, outputPath, format, &vulnResult); errPrint != nil {
+	if helper.SkipPrintingResult(context, &vulnResult) {
+		// This may be nil.
+		return err
+	}
+
+	if errPrint := helper.PrintResults(stdout, stderr, outputs, context.String("template"), context.Bool("all-packages"), &vulnResult); errPrint != nil {
 		return fmt.Errorf("failed to write output: %w", errPrint)
 	}
 
 	// Auto-open outputted HTML file for users.
-	if outputPath != "" {
-		if serve {
-			helper.ServeHTML(outputPath)
-		} else if format == "html" {
-			slog.Info("HTML output available at: " + outputPath)
+	if serve {
+		helper.ServeHTML(outputs[0].Path)
+	} else {
+		for _, output := range outputs {
+			if output.Path != "" && output.Format == "html" {
+				slog.Info("HTML output available at: " + output.Path)
+			}
 		}
 	}
 
@@ -1,14 +1,20 @@
 package source
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/osv-scanner/v2/cmd/osv-scanner/internal/helper"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/incrementalmatcher"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
 	"github.com/google/osv-scanner/v2/pkg/models"
 	"github.com/google/osv-scanner/v2/pkg/osvscanner"
 	"github.com/urfave/cli/v2"
@@ -18,13 +24,28 @@ var projectScanFlags = []cli.Flag{
 	&cli.StringSliceFlag{
 		Name:      "lockfile",
 		Aliases:   []string{"L"},
-		Usage:     "scan package lockfile on this path",
+		Usage:     "scan package lockfile on this path (which can be a glob pattern, such as 'services/**/package-lock.json'), or - to read the lockfile from stdin",
 		TakesFile: true,
 	},
 	&cli.StringSliceFlag{
 		Name:      "sbom",
 		Aliases:   []string{"S"},
-		Usage:     "scan sbom file on this path, the sbom file name must follow the relevant spec",
+		Usage:     "scan sbom file on this path, or all the sbom files within this directory; the sbom file name must follow the relevant spec",
+		TakesFile: true,
+	},
+	&cli.BoolFlag{
+		Name:  "sbom-only",
+		Usage: "only scan sbom files when scanning directories, skipping lockfiles and manifests",
+		Value: false,
+	},
+	&cli.StringSliceFlag{
+		Name:      "python-env",
+		Usage:     "scan the packages installed in the Python environment (e.g. a virtualenv) or site-packages directory on this path",
+		TakesFile: true,
+	},
+	&cli.StringSliceFlag{
+		Name:      "archive",
+		Usage:     "scan the lockfiles and sbom files within the zip or tar archive on this path, without extracting it",
 		TakesFile: true,
 	},
 	&cli.BoolFlag{
@@ -33,6 +54,54 @@ var projectScanFlags = []cli.Flag{
 		Usage:   "check subdirectories",
 		Value:   false,
 	},
+	&cli.StringSliceFlag{
+		Name:  "skip-dir",
+		Usage: "skip directories with a name matching this pattern (e.g. node_modules) when scanning directories",
+	},
+	&cli.IntFlag{
+		Name:  "jobs",
+		Usage: "the number of files to extract at once when scanning directories (defaults to the number of CPUs)",
+		Action: func(_ *cli.Context, i int) error {
+			if i < 1 {
+				return fmt.Errorf("--jobs must be at least 1, got %d", i)
+			}
+
+			return nil
+		},
+	},
+	&cli.BoolFlag{
+		Name:  "strict",
+		Usage: "fail the scan if any file cannot be parsed, rather than warning about it and reporting the results of the rest of the files",
+	},
+	&cli.StringFlag{
+		Name:  "since",
+		Usage: "only scan directories with files that have changed since the given git ref (e.g. origin/main)",
+	},
+	&cli.BoolFlag{
+		Name:  "incremental",
+		Usage: "reuse the packages extracted by previous scans from files that have not changed since, and the vulnerabilities matched for them until the database is updated",
+	},
+	&cli.StringFlag{
+		Name:      "cache-dir",
+		Usage:     "directory to cache the packages extracted and vulnerabilities matched by incremental scans in; implies --incremental",
+		TakesFile: true,
+	},
+	&cli.DurationFlag{
+		Name:  "cache-ttl",
+		Usage: "how long incremental scans reuse the vulnerabilities matched by the OSV API for; implies --incremental",
+		Value: incrementalmatcher.DefaultMaxAge,
+		Action: func(_ *cli.Context, d time.Duration) error {
+			if d <= 0 {
+				return fmt.Errorf("--cache-ttl must be positive, got %s", d)
+			}
+
+			return nil
+		},
+	},
+	&cli.BoolFlag{
+		Name:  "no-cache",
+		Usage: "neither reuse nor cache the packages extracted and vulnerabilities matched by previous scans, overriding --incremental, --cache-dir and --cache-ttl",
+	},
 	&cli.BoolFlag{
 		Name:  "no-ignore",
 		Usage: "also scan files that would be ignored by .gitignore",
@@ -46,6 +115,63 @@ var projectScanFlags = []cli.Flag{
 		Name:  "no-call-analysis",
 		Usage: "disables call graph analysis",
 	},
+	&cli.StringSliceFlag{
+		Name:  "commit",
+		Usage: "scan the git commit with this full hash for vulnerabilities in the repository it is from",
+		Action: func(_ *cli.Context, commits []string) error {
+			for _, commit := range commits {
+				if !isFullCommitHash(commit) {
+					return fmt.Errorf("invalid commit \"%s\" - must be a full SHA-1 or SHA-256 commit hash", commit)
+				}
+			}
+
+			return nil
+		},
+	},
+	&cli.BoolFlag{
+		Name:  "hide-uncalled",
+		Usage: "remove vulnerabilities that call analysis determined are not called from the output, instead of listing them separately",
+		Value: false,
+	},
+	&cli.BoolFlag{
+		Name:  "dependency-paths",
+		Usage: "show the path through which each vulnerable package is depended on, for lockfiles that record their dependency graph",
+		Value: false,
+	},
+	&cli.BoolFlag{
+		Name:  "direct-only",
+		Usage: "only scan direct dependencies, along with packages that cannot be determined to be direct or transitive",
+		Value: false,
+	},
+	&cli.BoolFlag{
+		Name:  "transitive-only",
+		Usage: "only scan transitive dependencies, along with packages that cannot be determined to be direct or transitive",
+		Value: false,
+		Action: func(c *cli.Context, _ bool) error {
+			if c.Bool("direct-only") {
+				return errors.New("--transitive-only cannot be used with --direct-only")
+			}
+
+			return nil
+		},
+	},
+	&cli.BoolFlag{
+		Name:  "check-lockfile-drift",
+		Usage: "warn about lockfiles that are out of date with the manifest beside them, such as package.json",
+		Value: false,
+	},
+	&cli.StringFlag{
+		Name:  "manifest-resolution",
+		Usage: "how the version requirements of manifests are resolved to the version that is matched; value can be: " + strings.Join(osvscanner.ManifestResolutions, ", "),
+		Value: osvscanner.ManifestResolutionDeclared,
+		Action: func(_ *cli.Context, s string) error {
+			if !slices.Contains(osvscanner.ManifestResolutions, s) {
+				return fmt.Errorf("unsupported manifest-resolution \"%s\" - must be one of: %s", s, strings.Join(osvscanner.ManifestResolutions, ", "))
+			}
+
+			return nil
+		},
+	},
 	&cli.BoolFlag{
 		Name:  "include-git-root",
 		Usage: "include scanning git root (non-submoduled) repositories",
@@ -69,6 +195,18 @@ var projectScanFlags = []cli.Flag{
 	},
 }
 
+// isFullCommitHash returns true if the commit is a full SHA-1 or SHA-256 hash,
+// as commits cannot be matched against OSV by an abbreviated hash
+func isFullCommitHash(commit string) bool {
+	if len(commit) != 40 && len(commit) != 64 {
+		return false
+	}
+
+	_, err := hex.DecodeString(commit)
+
+	return err == nil
+}
+
 func Command(stdout, stderr io.Writer) *cli.Command {
 	flags := make([]cli.Flag, 0, len(projectScanFlags)+len(helper.GetScanGlobalFlags()))
 	flags = append(flags, projectScanFlags...)
@@ -87,13 +225,15 @@ func Command(stdout, stderr io.Writer) *cli.Command {
 }
 
 func action(context *cli.Context, stdout, stderr io.Writer) error {
-	format := context.String("format")
+	outputs, err := helper.GetOutputs(context)
+	if err != nil {
+		return err
+	}
 
-	outputPath := context.String("output")
 	serve := context.Bool("serve")
 	if serve {
-		format = "html"
-		if outputPath == "" {
+		outputs[0].Format = "html"
+		if outputs[0].Path == "" {
 			// Create a temporary directory
 			tmpDir, err := os.MkdirTemp("", "osv-scanner-result")
 			if err != nil {
@@ -103,7 +243,7 @@ func action(context *cli.Context, stdout, stderr io.Writer) error {
 
 			// Remove the created temporary directory after
 			defer os.RemoveAll(tmpDir)
-			outputPath = filepath.Join(tmpDir, "index.html")
+			outputs[0].Path = filepath.Join(tmpDir, "index.html")
 		}
 	}
 
@@ -116,41 +256,73 @@ func action(context *cli.Context, stdout, stderr io.Writer) error {
 
 	experimentalScannerActions := helper.GetExperimentalScannerActions(context, scanLicensesAllowlist)
 	// Add `source` specific experimental configs
+	experimentalScannerActions.HideUncalled = context.Bool("hide-uncalled")
+	experimentalScannerActions.ShowDependencyPaths = context.Bool("dependency-paths")
+	// configuring the cache is enough to use it, unless it is bypassed entirely
+	experimentalScannerActions.Incremental = (context.Bool("incremental") || context.IsSet("cache-dir") || context.IsSet("cache-ttl")) &&
+		!context.Bool("no-cache")
+	experimentalScannerActions.CacheDir = context.String("cache-dir")
+	experimentalScannerActions.CacheTTL = context.Duration("cache-ttl")
+	experimentalScannerActions.DirectOnly = context.Bool("direct-only")
+	experimentalScannerActions.TransitiveOnly = context.Bool("transitive-only")
+	experimentalScannerActions.CheckLockfileDrift = context.Bool("check-lockfile-drift")
+	experimentalScannerActions.ManifestResolution = context.String("manifest-resolution")
 	experimentalScannerActions.TransitiveScanningActions = osvscanner.TransitiveScanningActions{
 		Disabled:         context.Bool("no-resolve"),
 		NativeDataSource: context.String("data-source") == "native",
 		MavenRegistry:    context.String("maven-registry"),
 	}
 
-	scannerAction := osvscanner.ScannerActions{
-		LockfilePaths:              context.StringSlice("lockfile"),
-		SBOMPaths:                  context.StringSlice("sbom"),
-		Recursive:                  context.Bool("recursive"),
-		IncludeGitRoot:             context.Bool("include-git-root"),
-		NoIgnore:                   context.Bool("no-ignore"),
-		ConfigOverridePath:         context.String("config"),
-		DirectoryPaths:             context.Args().Slice(),
-		CallAnalysisStates:         callAnalysisStates,
-		ExperimentalScannerActions: experimentalScannerActions,
+	scanOptions := osvscanner.ScanOptions{
+		Lockfiles:             context.StringSlice("lockfile"),
+		SBOMs:                 context.StringSlice("sbom"),
+		Recursive:             context.Bool("recursive"),
+		IncludeGitRoot:        context.Bool("include-git-root"),
+		NoIgnore:              context.Bool("no-ignore"),
+		SBOMOnly:              context.Bool("sbom-only"),
+		SkipDirs:              context.StringSlice("skip-dir"),
+		Jobs:                  context.Int("jobs"),
+		Strict:                context.Bool("strict"),
+		ChangedSince:          context.String("since"),
+		ConfigPath:            context.String("config"),
+		BaseConfigPath:        context.String("base-config"),
+		Paths:                 context.Args().Slice(),
+		PythonEnvs:            context.StringSlice("python-env"),
+		Archives:              context.StringSlice("archive"),
+		Commits:               context.StringSlice("commit"),
+		CallAnalysis:          callAnalysisStates,
+		FailOnVulnerabilities: true,
+		Experimental:          experimentalScannerActions,
+	}
+
+	if !context.Bool("no-progress") && !context.Bool("quiet") {
+		cmdlogger.ShowProgress()
 	}
 
 	var vulnResult models.VulnerabilityResults
-	vulnResult, err = osvscanner.DoScan(scannerAction)
+	vulnResult, err = osvscanner.ScanContext(context.Context, scanOptions)
 
-	if err != nil && !errors.Is(err, osvscanner.ErrVulnerabilitiesFound) {
+	if err != nil && !errors.Is(err, osvscanner.ErrVulnerabilitiesFound) && !errors.Is(err, osvscanner.ErrPartialScan) {
 		return err
 	}
 
-	if errPrint := helper.PrintResult(stdout, stderr, outputPath, format, &vulnResult); errPrint != nil {
+	if helper.SkipPrintingResult(context, &vulnResult) {
+		// This may be nil.
+		return err
+	}
+
+	if errPrint := helper.PrintResults(stdout, stderr, outputs, context.String("template"), context.Bool("all-packages"), &vulnResult); errPrint != nil {
 		return fmt.Errorf("failed to write output: %w", errPrint)
 	}
 
 	// Auto-open outputted HTML file for users.
-	if outputPath != "" {
-		if serve {
-			helper.ServeHTML(outputPath)
-		} else if format == "html" {
-			slog.Info("HTML output available at: " + outputPath)
+	if serve {
+		helper.ServeHTML(outputs[0].Path)
+	} else {
+		for _, output := range outputs {
+			if output.Path != "" && output.Format == "html" {
+				slog.Info("HTML output available at: " + output.Path)
+			}
 		}
 	}
 
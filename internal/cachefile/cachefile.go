@@ -0,0 +1,50 @@
+// Package cachefile reads and writes the files that scans cache data in between runs.
+package cachefile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Dir returns the directory within cacheDir that caches of the given kind are stored in, creating it
+// if needed, with cacheDir defaulting to the user cache directory (or the temporary directory) if empty
+func Dir(cacheDir string, kind string) (string, error) {
+	if cacheDir == "" {
+		var err error
+		cacheDir, err = os.UserCacheDir()
+		if err != nil {
+			cacheDir = os.TempDir()
+		}
+	}
+
+	dir := filepath.Join(cacheDir, "osv-scanner", kind)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", fmt.Errorf("could not create %s: %w", dir, err)
+	}
+
+	return dir, nil
+}
+
+// Write writes the content to the cache file at path, which is done in a uniquely named temporary file
+// in the same directory that replaces the file once it has been written, so that a partially written
+// cache is never read and scans that are running at the same time do not write over each other
+func Write(path string, content []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// the temporary file no longer exists once it has replaced the file
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
@@ -54,6 +54,7 @@ func DiffVulnerabilityResults(oldRes, newRes models.VulnerabilityResults) models
 			groups := grouper.Group(grouper.ConvertVulnerabilityToIDAliases(resultPV.Vulnerabilities))
 			for i, group := range groups {
 				groups[i].MaxSeverity = output.MaxSeverity(group, *resultPV)
+				groups[i].FixedVersion = output.FixedVersion(group, *resultPV)
 			}
 			resultPV.Groups = groups
 		}
@@ -0,0 +1,243 @@
+// Package cpematcher matches packages that are only identified by CPEs, such as the
+// components of SBOMs from some commercial scanners, against OSV advisories that
+// reference the CPEs of the products that they affect.
+package cpematcher
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/utility/cpe"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// CPEsKey is the key of the database_specific field of an affected entry that lists
+// the CPEs of the product it affects, as OSV itself does not index advisories by CPE
+const CPEsKey = "cpes"
+
+// CPEMatcher implements the VulnerabilityMatcher interface by comparing the CPEs of packages
+// against the CPEs listed by the affected entries of a set of advisories.
+//
+// Packages whose CPEs do not identify a single product, because their vendor or product
+// is unspecified or because they refer to several products, are not matched.
+type CPEMatcher struct {
+	advisories []osvschema.Vulnerability
+}
+
+// New creates a matcher for the advisories with at least one affected entry that lists CPEs
+func New(advisories []osvschema.Vulnerability) *CPEMatcher {
+	matcher := &CPEMatcher{}
+	for _, advisory := range advisories {
+		if slices.ContainsFunc(advisory.Affected, func(a osvschema.Affected) bool { return len(AffectedCPEs(a)) > 0 }) {
+			matcher.advisories = append(matcher.advisories, advisory)
+		}
+	}
+
+	return matcher
+}
+
+// HasAdvisories returns true if any of the advisories of the matcher reference CPEs
+func (matcher *CPEMatcher) HasAdvisories() bool {
+	return len(matcher.advisories) > 0
+}
+
+func (matcher *CPEMatcher) MatchVulnerabilities(ctx context.Context, invs []*extractor.Inventory) ([][]*osvschema.Vulnerability, error) {
+	results := make([][]*osvschema.Vulnerability, 0, len(invs))
+
+	for _, inv := range invs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		pkg := imodels.FromInventory(inv)
+		target, ok := productOf(pkg)
+		if !ok {
+			results = append(results, nil)
+			continue
+		}
+
+		var vulns []*osvschema.Vulnerability
+		for i := range matcher.advisories {
+			if isAffected(matcher.advisories[i], target) {
+				vulns = append(vulns, &matcher.advisories[i])
+			}
+		}
+		results = append(results, vulns)
+	}
+
+	return results, nil
+}
+
+// productOf returns the product that the CPEs of the package identify, as long as they do so
+// unambiguously, with the version of the package if the CPEs do not specify one
+func productOf(pkg imodels.PackageInfo) (cpe.CPE, bool) {
+	cpes := pkg.CPEs()
+
+	var products []cpe.CPE
+	for _, str := range cpes {
+		parsed, ok := cpe.Parse(str)
+		if !ok {
+			continue
+		}
+		if isUnspecified(parsed.Vendor()) || isUnspecified(parsed.Product()) {
+			slog.Warn(fmt.Sprintf("Not matching %s by CPE, as %s does not specify a single vendor and product", pkg.Name(), str))
+
+			return nil, false
+		}
+
+		if !slices.ContainsFunc(products, func(p cpe.CPE) bool { return isSameProduct(p, parsed) }) {
+			products = append(products, parsed)
+		}
+	}
+
+	if len(products) == 0 {
+		if len(cpes) > 0 {
+			slog.Warn(fmt.Sprintf("Not matching %s by CPE, as none of its CPEs (%s) are valid", pkg.Name(), strings.Join(cpes, ", ")))
+		}
+
+		return nil, false
+	}
+
+	if len(products) > 1 {
+		slog.Warn(fmt.Sprintf("Not matching %s by CPE, as its CPEs refer to %d different products: %s", pkg.Name(), len(products), strings.Join(cpes, ", ")))
+
+		return nil, false
+	}
+
+	product := products[0]
+	if isUnspecified(product.Version()) {
+		product = slices.Clone(product)
+		product[3] = pkg.Version()
+	}
+
+	return product, true
+}
+
+// isUnspecified returns true if the CPE attribute matches any value or has no value,
+// and so cannot identify anything on its own
+func isUnspecified(value string) bool {
+	return value == "" || value == cpe.Any || value == cpe.NA
+}
+
+// isSameProduct returns true if the CPE of an advisory refers to the same product as the CPE of a package,
+// with the attributes that are ANY in the advisory matching every value
+func isSameProduct(advisory, pkg cpe.CPE) bool {
+	matches := func(advisory, pkg string) bool {
+		return advisory == cpe.Any || advisory == pkg
+	}
+
+	return matches(advisory.Part(), pkg.Part()) && matches(advisory.Vendor(), pkg.Vendor()) && matches(advisory.Product(), pkg.Product())
+}
+
+// AffectedCPEs returns the CPEs that are listed in the database_specific field of the affected entry
+func AffectedCPEs(affected osvschema.Affected) []string {
+	values, ok := affected.DatabaseSpecific[CPEsKey].([]any)
+	if !ok {
+		return nil
+	}
+
+	cpes := make([]string, 0, len(values))
+	for _, value := range values {
+		if str, ok := value.(string); ok {
+			cpes = append(cpes, str)
+		}
+	}
+
+	return cpes
+}
+
+// isAffected returns true if an affected entry of the advisory lists a CPE of the product,
+// and either that CPE or the entry's versions and ranges include the version of the product
+func isAffected(advisory osvschema.Vulnerability, product cpe.CPE) bool {
+	version := product.Version()
+
+	for _, affected := range advisory.Affected {
+		for _, str := range AffectedCPEs(affected) {
+			parsed, ok := cpe.Parse(str)
+			if !ok || !isSameProduct(parsed, product) {
+				continue
+			}
+
+			// without a version, the product is assumed to be vulnerable
+			// as false positives are better than false negatives here
+			if version == "" {
+				return true
+			}
+
+			if !isUnspecified(parsed.Version()) {
+				if cpe.CompareVersions(parsed.Version(), version) == 0 {
+					return true
+				}
+
+				continue
+			}
+
+			if slices.ContainsFunc(affected.Versions, func(v string) bool { return cpe.CompareVersions(v, version) == 0 }) {
+				return true
+			}
+
+			for _, r := range affected.Ranges {
+				if rangeContainsVersion(r, version) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// rangeContainsVersion checks if the version is within an "Ecosystem" or "Semver" range,
+// comparing the versions of the range events with CPE version semantics
+func rangeContainsVersion(r osvschema.Range, version string) bool {
+	if r.Type != osvschema.RangeEcosystem && r.Type != osvschema.RangeSemVer {
+		return false
+	}
+
+	events := slices.Clone(r.Events)
+	slices.SortStableFunc(events, func(a, b osvschema.Event) int {
+		// an introduced event of 0 means every version is affected, so it always comes first
+		switch {
+		case a.Introduced == "0" && b.Introduced != "0":
+			return -1
+		case b.Introduced == "0" && a.Introduced != "0":
+			return 1
+		}
+
+		return cpe.CompareVersions(eventVersion(a), eventVersion(b))
+	})
+
+	var affected bool
+	for _, e := range events {
+		switch {
+		case affected && e.Fixed != "":
+			affected = cpe.CompareVersions(version, e.Fixed) < 0
+		case affected && e.LastAffected != "":
+			affected = cpe.CompareVersions(version, e.LastAffected) <= 0
+		case affected && e.Limit != "":
+			affected = cpe.CompareVersions(version, e.Limit) < 0
+		case !affected && e.Introduced != "":
+			affected = e.Introduced == "0" || cpe.CompareVersions(version, e.Introduced) >= 0
+		}
+	}
+
+	return affected
+}
+
+func eventVersion(e osvschema.Event) string {
+	switch {
+	case e.Introduced != "":
+		return e.Introduced
+	case e.Fixed != "":
+		return e.Fixed
+	case e.Limit != "":
+		return e.Limit
+	}
+
+	return e.LastAffected
+}
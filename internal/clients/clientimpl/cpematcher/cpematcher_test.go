@@ -0,0 +1,202 @@
+package cpematcher_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/sbom/cdx"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/cpematcher"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+func advisory(id string, cpes []string, versions []string, events ...osvschema.Event) osvschema.Vulnerability {
+	// the cpes are decoded from JSON as a slice of any
+	values := make([]any, 0, len(cpes))
+	for _, cpe := range cpes {
+		values = append(values, cpe)
+	}
+
+	affected := osvschema.Affected{
+		Versions:         versions,
+		DatabaseSpecific: map[string]any{cpematcher.CPEsKey: values},
+	}
+	if len(events) > 0 {
+		affected.Ranges = []osvschema.Range{{Type: osvschema.RangeEcosystem, Events: events}}
+	}
+
+	return osvschema.Vulnerability{ID: id, Affected: []osvschema.Affected{affected}}
+}
+
+func inventory(version string, cpes ...string) *extractor.Inventory {
+	return &extractor.Inventory{
+		Name:      cpes[0],
+		Version:   version,
+		Metadata:  &cdx.Metadata{CPEs: cpes},
+		Extractor: cdx.Extractor{},
+	}
+}
+
+func TestCPEMatcher_MatchVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	advisories := []osvschema.Vulnerability{
+		advisory(
+			"ACME-1",
+			[]string{"cpe:2.3:a:acme:router_firmware:*:*:*:*:*:*:*:*"},
+			nil,
+			osvschema.Event{Introduced: "0"},
+			osvschema.Event{Fixed: "2.10.0"},
+		),
+		advisory(
+			"ACME-2",
+			[]string{"cpe:/a:acme:router_firmware:1.9.3"},
+			nil,
+		),
+		advisory(
+			"ACME-3",
+			[]string{"cpe:2.3:a:*:libfoo:*:*:*:*:*:*:*:*"},
+			[]string{"4.2"},
+		),
+		advisory(
+			"ACME-4",
+			[]string{"cpe:2.3:a:acme:router_firmware:*:*:*:*:*:*:*:*"},
+			nil,
+			osvschema.Event{Introduced: "3.0"},
+			osvschema.Event{LastAffected: "3.0.2b"},
+		),
+		// advisories that only affect packages by ecosystem and name are not used
+		{
+			ID: "ACME-5",
+			Affected: []osvschema.Affected{{
+				Package: osvschema.Package{Ecosystem: "npm", Name: "router_firmware"},
+				Ranges:  []osvschema.Range{{Type: osvschema.RangeEcosystem, Events: []osvschema.Event{{Introduced: "0"}}}},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		inv  *extractor.Inventory
+		want []string
+	}{
+		{
+			name: "version in range, compared numerically",
+			inv:  inventory("", "cpe:2.3:a:acme:router_firmware:2.9.0:*:*:*:*:*:*:*"),
+			want: []string{"ACME-1"},
+		},
+		{
+			name: "version after the fix, compared numerically",
+			inv:  inventory("", "cpe:2.3:a:acme:router_firmware:2.10.0:*:*:*:*:*:*:*"),
+			want: nil,
+		},
+		{
+			name: "version of the advisory cpe",
+			inv:  inventory("", "cpe:2.3:a:acme:router_firmware:1.9.3:*:*:*:*:*:*:*"),
+			want: []string{"ACME-1", "ACME-2"},
+		},
+		{
+			name: "trailing zeros are ignored",
+			inv:  inventory("", "cpe:2.3:a:acme:router_firmware:1.9.3.0:*:*:*:*:*:*:*"),
+			want: []string{"ACME-1", "ACME-2"},
+		},
+		{
+			name: "components are case insensitive",
+			inv:  inventory("", "cpe:2.3:a:ACME:Router_Firmware:1.0:*:*:*:*:*:*:*"),
+			want: []string{"ACME-1"},
+		},
+		{
+			name: "version of the package when the cpe does not have one",
+			inv:  inventory("3.0.2a", "cpe:2.3:a:acme:router_firmware:*:*:*:*:*:*:*:*"),
+			want: []string{"ACME-4"},
+		},
+		{
+			name: "alphabetic segments are compared lexically",
+			inv:  inventory("", "cpe:2.3:a:acme:router_firmware:3.0.2c:*:*:*:*:*:*:*"),
+			want: nil,
+		},
+		{
+			name: "advisory with any vendor",
+			inv:  inventory("", "cpe:2.3:a:example:libfoo:4.2:*:*:*:*:*:*:*"),
+			want: []string{"ACME-3"},
+		},
+		{
+			name: "different product",
+			inv:  inventory("", "cpe:2.3:a:acme:switch_firmware:1.0:*:*:*:*:*:*:*"),
+			want: nil,
+		},
+		{
+			name: "different part",
+			inv:  inventory("", "cpe:2.3:h:acme:router_firmware:1.0:*:*:*:*:*:*:*"),
+			want: nil,
+		},
+		{
+			name: "several cpes of the same product",
+			inv: inventory(
+				"",
+				"cpe:2.3:a:acme:router_firmware:2.0:*:*:*:*:*:*:*",
+				"cpe:/a:acme:router_firmware:2.0",
+			),
+			want: []string{"ACME-1"},
+		},
+		{
+			name: "ambiguous vendor",
+			inv:  inventory("", "cpe:2.3:a:*:router_firmware:1.0:*:*:*:*:*:*:*"),
+			want: nil,
+		},
+		{
+			name: "ambiguous cpes of different products",
+			inv: inventory(
+				"",
+				"cpe:2.3:a:acme:router_firmware:1.0:*:*:*:*:*:*:*",
+				"cpe:2.3:a:example:libfoo:4.2:*:*:*:*:*:*:*",
+			),
+			want: nil,
+		},
+		{
+			name: "invalid cpe",
+			inv:  inventory("1.0", "acme router firmware"),
+			want: nil,
+		},
+	}
+
+	matcher := cpematcher.New(advisories)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := matcher.MatchVulnerabilities(context.Background(), []*extractor.Inventory{tt.inv})
+			if err != nil {
+				t.Fatalf("MatchVulnerabilities() error = %v", err)
+			}
+
+			var ids []string
+			for _, vuln := range got[0] {
+				ids = append(ids, vuln.ID)
+			}
+
+			if diff := cmp.Diff(tt.want, ids); diff != "" {
+				t.Errorf("MatchVulnerabilities() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCPEMatcher_HasAdvisories(t *testing.T) {
+	t.Parallel()
+
+	withoutCPEs := osvschema.Vulnerability{
+		ID:       "GHSA-1",
+		Affected: []osvschema.Affected{{Package: osvschema.Package{Ecosystem: "npm", Name: "foo"}}},
+	}
+
+	if cpematcher.New([]osvschema.Vulnerability{withoutCPEs}).HasAdvisories() {
+		t.Errorf("HasAdvisories() = true for advisories without cpes")
+	}
+
+	withCPEs := advisory("ACME-1", []string{"cpe:2.3:a:acme:foo:*:*:*:*:*:*:*:*"}, []string{"1.0"})
+	if !cpematcher.New([]osvschema.Vulnerability{withoutCPEs, withCPEs}).HasAdvisories() {
+		t.Errorf("HasAdvisories() = false for advisories with cpes")
+	}
+}
@@ -0,0 +1,190 @@
+package epssmatcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/osv-scanner/v2/internal/utility/backoff"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+const (
+	// DefaultAPIURL is the URL of the EPSS API of FIRST
+	DefaultAPIURL = "https://api.first.org/data/v1/epss"
+
+	maxRetryAttempts = 4
+
+	// batchSize is the number of CVEs to request the scores of at once,
+	// which keeps the query string within the length that the API accepts
+	batchSize = 100
+)
+
+// FirstEPSSMatcher implements the EPSSMatcher interface with the EPSS API of FIRST.
+//
+// The scores of CVEs are cached for the lifetime of the matcher, including
+// the CVEs that do not have a score, so each CVE is only requested once.
+type FirstEPSSMatcher struct {
+	HTTPClient *http.Client
+	APIURL     string
+	UserAgent  string
+
+	mu sync.Mutex
+	// cache holds the score of each CVE that has been requested,
+	// which is nil for those that do not have a score
+	cache map[string]*models.EPSSScore
+}
+
+type epssResponse struct {
+	Data []struct {
+		CVE        string `json:"cve"`
+		EPSS       string `json:"epss"`
+		Percentile string `json:"percentile"`
+	} `json:"data"`
+}
+
+func (matcher *FirstEPSSMatcher) MatchEPSS(ctx context.Context, cves []string) (map[string]models.EPSSScore, error) {
+	matcher.mu.Lock()
+	defer matcher.mu.Unlock()
+
+	if matcher.cache == nil {
+		matcher.cache = make(map[string]*models.EPSSScore)
+	}
+
+	var uncached []string
+	for _, cve := range cves {
+		if _, ok := matcher.cache[cve]; !ok && !slices.Contains(uncached, cve) {
+			uncached = append(uncached, cve)
+		}
+	}
+
+	for batch := range slices.Chunk(uncached, batchSize) {
+		scores, err := matcher.makeRetryRequest(ctx, batch)
+		if err != nil {
+			return nil, fmt.Errorf("querying EPSS scores: %w", err)
+		}
+
+		for _, cve := range batch {
+			matcher.cache[cve] = nil
+		}
+		for _, score := range scores {
+			matcher.cache[score.CVE] = &score
+		}
+	}
+
+	results := make(map[string]models.EPSSScore)
+	for _, cve := range cves {
+		if score := matcher.cache[cve]; score != nil {
+			results[cve] = *score
+		}
+	}
+
+	return results, nil
+}
+
+// makeRetryRequest requests the scores of the CVEs, retrying if the request fails due to a server error
+func (matcher *FirstEPSSMatcher) makeRetryRequest(ctx context.Context, cves []string) ([]models.EPSSScore, error) {
+	var lastErr error
+
+	for i := range maxRetryAttempts {
+		// backoff between attempts, but not before the first
+		if i > 0 {
+			if err := backoff.Sleep(ctx, time.Duration(1<<(i-1))*time.Second); err != nil {
+				return nil, err
+			}
+		}
+
+		var scores []models.EPSSScore
+		scores, lastErr = matcher.makeRequest(ctx, cves)
+		if lastErr == nil {
+			return scores, nil
+		}
+
+		if errors.Is(lastErr, context.DeadlineExceeded) || errors.Is(lastErr, context.Canceled) || errors.As(lastErr, new(errClient)) {
+			return nil, lastErr
+		}
+
+		slog.Debug(fmt.Sprintf("EPSS request failed, retrying (attempt %d): %v", i+1, lastErr))
+	}
+
+	return nil, fmt.Errorf("max retries exceeded: %w", lastErr)
+}
+
+// errClient is returned by makeRequest for errors that retrying will not fix
+type errClient struct {
+	err error
+}
+
+func (e errClient) Error() string {
+	return e.err.Error()
+}
+
+func (e errClient) Unwrap() error {
+	return e.err
+}
+
+func (matcher *FirstEPSSMatcher) makeRequest(ctx context.Context, cves []string) ([]models.EPSSScore, error) {
+	query := url.Values{}
+	query.Set("cve", strings.Join(cves, ","))
+	query.Set("limit", strconv.Itoa(len(cves)))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, matcher.APIURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, errClient{err}
+	}
+
+	if matcher.UserAgent != "" {
+		req.Header.Set("User-Agent", matcher.UserAgent)
+	}
+
+	resp, err := matcher.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return nil, fmt.Errorf("rate limited: status=%q", resp.Status)
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return nil, errClient{fmt.Errorf("client error: status=%q body=%s", resp.Status, respBody)}
+	case resp.StatusCode >= 500:
+		return nil, fmt.Errorf("server error: status=%q body=%s", resp.Status, respBody)
+	}
+
+	var result epssResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, errClient{fmt.Errorf("unexpected response from the EPSS API: %w", err)}
+	}
+
+	scores := make([]models.EPSSScore, 0, len(result.Data))
+	for _, d := range result.Data {
+		probability, err := strconv.ParseFloat(d.EPSS, 64)
+		if err != nil {
+			return nil, errClient{fmt.Errorf("unexpected EPSS score %q for %s: %w", d.EPSS, d.CVE, err)}
+		}
+		percentile, err := strconv.ParseFloat(d.Percentile, 64)
+		if err != nil {
+			return nil, errClient{fmt.Errorf("unexpected EPSS percentile %q for %s: %w", d.Percentile, d.CVE, err)}
+		}
+
+		scores = append(scores, models.EPSSScore{CVE: d.CVE, Probability: probability, Percentile: percentile})
+	}
+
+	return scores, nil
+}
@@ -0,0 +1,131 @@
+package epssmatcher_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/epssmatcher"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+// scores are the responses of the fake EPSS API, by the CVE
+var scores = map[string]string{
+	"CVE-2021-44906": `{"cve": "CVE-2021-44906", "epss": "0.012340000", "percentile": "0.851200000", "date": "2025-01-01"}`,
+	"CVE-2021-23337": `{"cve": "CVE-2021-23337", "epss": "0.004560000", "percentile": "0.722100000", "date": "2025-01-01"}`,
+}
+
+func newFakeAPI(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		var data []string
+		for _, cve := range strings.Split(r.URL.Query().Get("cve"), ",") {
+			if score, ok := scores[cve]; ok {
+				data = append(data, score)
+			}
+		}
+		slices.Sort(data)
+
+		fmt.Fprintf(w, `{"status": "OK", "status-code": 200, "data": [%s]}`, strings.Join(data, ","))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestFirstEPSSMatcher_MatchEPSS(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := newFakeAPI(t, &requests)
+
+	matcher := &epssmatcher.FirstEPSSMatcher{
+		HTTPClient: server.Client(),
+		APIURL:     server.URL,
+	}
+
+	want := map[string]models.EPSSScore{
+		"CVE-2021-44906": {CVE: "CVE-2021-44906", Probability: 0.01234, Percentile: 0.8512},
+		"CVE-2021-23337": {CVE: "CVE-2021-23337", Probability: 0.00456, Percentile: 0.7221},
+	}
+
+	got, err := matcher.MatchEPSS(context.Background(), []string{"CVE-2021-44906", "CVE-2021-23337", "CVE-2000-0001"})
+	if err != nil {
+		t.Fatalf("MatchEPSS() error = %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("MatchEPSS() mismatch (-want +got):\n%s", diff)
+	}
+
+	// the scores are cached, including for the CVE that does not have one
+	got, err = matcher.MatchEPSS(context.Background(), []string{"CVE-2000-0001", "CVE-2021-44906"})
+	if err != nil {
+		t.Fatalf("MatchEPSS() error = %v", err)
+	}
+	if diff := cmp.Diff(map[string]models.EPSSScore{"CVE-2021-44906": want["CVE-2021-44906"]}, got); diff != "" {
+		t.Errorf("MatchEPSS() mismatch (-want +got):\n%s", diff)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("made %d requests, want 1", n)
+	}
+}
+
+func TestFirstEPSSMatcher_MatchEPSS_Batched(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := newFakeAPI(t, &requests)
+
+	matcher := &epssmatcher.FirstEPSSMatcher{
+		HTTPClient: server.Client(),
+		APIURL:     server.URL,
+	}
+
+	cves := []string{"CVE-2021-44906"}
+	for i := range 150 {
+		cves = append(cves, fmt.Sprintf("CVE-2000-%04d", i))
+	}
+
+	got, err := matcher.MatchEPSS(context.Background(), cves)
+	if err != nil {
+		t.Fatalf("MatchEPSS() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("MatchEPSS() returned %d scores, want 1", len(got))
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("made %d requests, want 2", n)
+	}
+}
+
+func TestFirstEPSSMatcher_MatchEPSS_ClientError(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	t.Cleanup(server.Close)
+
+	matcher := &epssmatcher.FirstEPSSMatcher{
+		HTTPClient: server.Client(),
+		APIURL:     server.URL,
+	}
+
+	if _, err := matcher.MatchEPSS(context.Background(), []string{"CVE-2021-44906"}); err == nil {
+		t.Errorf("MatchEPSS() error = nil, want an error")
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("made %d requests, want 1 as client errors are not retried", n)
+	}
+}
@@ -0,0 +1,158 @@
+package githubmatcher
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// githubEcosystems maps the ecosystems covered by GitHub's security advisory database
+// to their SecurityAdvisoryEcosystem in the GraphQL API
+var githubEcosystems = map[osvschema.Ecosystem]string{
+	osvschema.EcosystemCratesIO:      "RUST",
+	osvschema.EcosystemGitHubActions: "ACTIONS",
+	osvschema.EcosystemGo:            "GO",
+	osvschema.EcosystemHex:           "ERLANG",
+	osvschema.EcosystemMaven:         "MAVEN",
+	osvschema.EcosystemNPM:           "NPM",
+	osvschema.EcosystemNuGet:         "NUGET",
+	osvschema.EcosystemPackagist:     "COMPOSER",
+	osvschema.EcosystemPub:           "PUB",
+	osvschema.EcosystemPyPI:          "PIP",
+	osvschema.EcosystemRubyGems:      "RUBYGEMS",
+	osvschema.EcosystemSwiftURL:      "SWIFT",
+}
+
+type vulnerabilityNode struct {
+	VulnerableVersionRange string `json:"vulnerableVersionRange"`
+	FirstPatchedVersion    *struct {
+		Identifier string `json:"identifier"`
+	} `json:"firstPatchedVersion"`
+	Package struct {
+		Name string `json:"name"`
+	} `json:"package"`
+	Advisory struct {
+		GHSAID      string    `json:"ghsaId"`
+		Summary     string    `json:"summary"`
+		Description string    `json:"description"`
+		PublishedAt time.Time `json:"publishedAt"`
+		UpdatedAt   time.Time `json:"updatedAt"`
+		WithdrawnAt time.Time `json:"withdrawnAt"`
+		Identifiers []struct {
+			Type  string `json:"type"`
+			Value string `json:"value"`
+		} `json:"identifiers"`
+		References []struct {
+			URL string `json:"url"`
+		} `json:"references"`
+		CVSSSeverities struct {
+			CVSSV3 struct {
+				VectorString string `json:"vectorString"`
+			} `json:"cvssV3"`
+			CVSSV4 struct {
+				VectorString string `json:"vectorString"`
+			} `json:"cvssV4"`
+		} `json:"cvssSeverities"`
+	} `json:"advisory"`
+}
+
+// toOSVVulnerabilities converts the vulnerabilities of the package into OSV vulnerabilities,
+// combining those of the same advisory into one vulnerability with an affected entry for each range
+func toOSVVulnerabilities(pkg githubPackage, nodes []vulnerabilityNode) []osvschema.Vulnerability {
+	var vs []osvschema.Vulnerability
+
+	for _, node := range nodes {
+		affected := osvschema.Affected{
+			Package: osvschema.Package{Ecosystem: string(pkg.ecosystem), Name: pkg.name},
+			Ranges:  []osvschema.Range{parseVersionRange(node)},
+		}
+
+		i := slices.IndexFunc(vs, func(v osvschema.Vulnerability) bool { return v.ID == node.Advisory.GHSAID })
+		if i >= 0 {
+			vs[i].Affected = append(vs[i].Affected, affected)
+			continue
+		}
+
+		vs = append(vs, toOSVVulnerability(node, affected))
+	}
+
+	return vs
+}
+
+func toOSVVulnerability(node vulnerabilityNode, affected osvschema.Affected) osvschema.Vulnerability {
+	advisory := node.Advisory
+
+	v := osvschema.Vulnerability{
+		ID:        advisory.GHSAID,
+		Summary:   advisory.Summary,
+		Details:   advisory.Description,
+		Published: advisory.PublishedAt,
+		Modified:  advisory.UpdatedAt,
+		Withdrawn: advisory.WithdrawnAt,
+		Affected:  []osvschema.Affected{affected},
+		References: []osvschema.Reference{{
+			Type: osvschema.ReferenceAdvisory,
+			URL:  "https://github.com/advisories/" + advisory.GHSAID,
+		}},
+	}
+
+	for _, identifier := range advisory.Identifiers {
+		if identifier.Value != advisory.GHSAID {
+			v.Aliases = append(v.Aliases, identifier.Value)
+		}
+	}
+
+	for _, reference := range advisory.References {
+		if reference.URL != v.References[0].URL {
+			v.References = append(v.References, osvschema.Reference{Type: osvschema.ReferenceWeb, URL: reference.URL})
+		}
+	}
+
+	if vector := advisory.CVSSSeverities.CVSSV3.VectorString; vector != "" {
+		v.Severity = append(v.Severity, osvschema.Severity{Type: osvschema.SeverityCVSSV3, Score: vector})
+	}
+	if vector := advisory.CVSSSeverities.CVSSV4.VectorString; vector != "" {
+		v.Severity = append(v.Severity, osvschema.Severity{Type: osvschema.SeverityCVSSV4, Score: vector})
+	}
+
+	return v
+}
+
+// parseVersionRange converts the vulnerable version range of the node, which is made
+// of comma-separated constraints like ">= 1.0.0, < 1.2.6", into an ECOSYSTEM range
+func parseVersionRange(node vulnerabilityNode) osvschema.Range {
+	introduced := "0"
+	var fixed, lastAffected string
+
+	for _, constraint := range strings.Split(node.VulnerableVersionRange, ",") {
+		constraint = strings.TrimSpace(constraint)
+
+		switch {
+		case strings.HasPrefix(constraint, ">="):
+			introduced = strings.TrimSpace(strings.TrimPrefix(constraint, ">="))
+		case strings.HasPrefix(constraint, "<="):
+			lastAffected = strings.TrimSpace(strings.TrimPrefix(constraint, "<="))
+		case strings.HasPrefix(constraint, "<"):
+			fixed = strings.TrimSpace(strings.TrimPrefix(constraint, "<"))
+		case strings.HasPrefix(constraint, "="):
+			introduced = strings.TrimSpace(strings.TrimPrefix(constraint, "="))
+			lastAffected = introduced
+		}
+	}
+
+	if fixed == "" && lastAffected == "" && node.FirstPatchedVersion != nil {
+		fixed = node.FirstPatchedVersion.Identifier
+	}
+
+	events := []osvschema.Event{{Introduced: introduced}}
+	switch {
+	case fixed != "":
+		events = append(events, osvschema.Event{Fixed: fixed})
+	case lastAffected != "":
+		events = append(events, osvschema.Event{LastAffected: lastAffected})
+	}
+
+	return osvschema.Range{Type: osvschema.RangeEcosystem, Events: events}
+}
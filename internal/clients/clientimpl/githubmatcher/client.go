@@ -0,0 +1,257 @@
+package githubmatcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/osv-scanner/v2/internal/utility/backoff"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+const (
+	maxRetryAttempts = 4
+
+	// maxRateLimitWait is the longest that the matcher will wait for a rate limit to be lifted,
+	// past which the scan fails rather than appearing to hang
+	maxRateLimitWait = time.Minute
+
+	// pageSize is the number of vulnerabilities to request in each page, which is the most GitHub allows
+	pageSize = 100
+)
+
+// advisoriesQuery queries the vulnerabilities of a package, along with their advisories
+const advisoriesQuery = `query($ecosystem: SecurityAdvisoryEcosystem!, $package: String!, $first: Int!, $after: String) {
+  securityVulnerabilities(ecosystem: $ecosystem, package: $package, first: $first, after: $after) {
+    nodes {
+      vulnerableVersionRange
+      firstPatchedVersion { identifier }
+      package { name }
+      advisory {
+        ghsaId
+        summary
+        description
+        publishedAt
+        updatedAt
+        withdrawnAt
+        identifiers { type value }
+        references { url }
+        cvssSeverities {
+          cvssV3 { vectorString }
+          cvssV4 { vectorString }
+        }
+      }
+    }
+    pageInfo { hasNextPage endCursor }
+  }
+}`
+
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+type graphQLError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type advisoriesResponse struct {
+	Data struct {
+		SecurityVulnerabilities struct {
+			Nodes    []vulnerabilityNode `json:"nodes"`
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+		} `json:"securityVulnerabilities"`
+	} `json:"data"`
+	Errors []graphQLError `json:"errors"`
+}
+
+// errRateLimited is returned by makeRequest when GitHub rate limits a request
+type errRateLimited struct {
+	wait time.Duration
+}
+
+func (e errRateLimited) Error() string {
+	return fmt.Sprintf("rate limited by the GitHub API for %s", e.wait)
+}
+
+// queryAdvisories queries all the pages of the vulnerabilities of the package,
+// returning them as OSV vulnerabilities with one per advisory
+func (matcher *GitHubMatcher) queryAdvisories(ctx context.Context, pkg githubPackage) ([]osvschema.Vulnerability, error) {
+	var nodes []vulnerabilityNode
+
+	variables := map[string]any{
+		"ecosystem": githubEcosystems[pkg.ecosystem],
+		"package":   pkg.name,
+		"first":     pageSize,
+	}
+
+	for {
+		var resp advisoriesResponse
+		if err := matcher.makeRetryRequest(ctx, graphQLRequest{Query: advisoriesQuery, Variables: variables}, &resp); err != nil {
+			return nil, fmt.Errorf("querying GitHub advisories of %s/%s: %w", pkg.ecosystem, pkg.name, err)
+		}
+
+		nodes = append(nodes, resp.Data.SecurityVulnerabilities.Nodes...)
+
+		if !resp.Data.SecurityVulnerabilities.PageInfo.HasNextPage {
+			break
+		}
+		variables["after"] = resp.Data.SecurityVulnerabilities.PageInfo.EndCursor
+	}
+
+	return toOSVVulnerabilities(pkg, nodes), nil
+}
+
+// makeRetryRequest makes the request, waiting to retry it if it is rate limited or fails
+// due to a server error, and decodes the response into result
+func (matcher *GitHubMatcher) makeRetryRequest(ctx context.Context, request graphQLRequest, result *advisoriesResponse) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	var wait time.Duration
+
+	for i := range maxRetryAttempts {
+		if err := backoff.Sleep(ctx, wait); err != nil {
+			return err
+		}
+
+		lastErr = matcher.makeRequest(ctx, body, result)
+		if lastErr == nil {
+			return nil
+		}
+
+		var rateLimited errRateLimited
+		switch {
+		case errors.Is(lastErr, context.DeadlineExceeded) || errors.Is(lastErr, context.Canceled):
+			return lastErr
+		case errors.As(lastErr, &rateLimited):
+			if rateLimited.wait > maxRateLimitWait {
+				return fmt.Errorf("%w, which is longer than osv-scanner will wait", lastErr)
+			}
+			wait = rateLimited.wait
+			slog.Debug(fmt.Sprintf("GitHub API rate limit reached, waiting %s to retry (attempt %d)", wait, i+1))
+		case errors.As(lastErr, new(errClient)):
+			return lastErr
+		default:
+			wait = time.Duration(1<<i) * time.Second
+		}
+	}
+
+	return fmt.Errorf("max retries exceeded: %w", lastErr)
+}
+
+// errClient is returned by makeRequest for errors that retrying will not fix
+type errClient struct {
+	err error
+}
+
+func (e errClient) Error() string {
+	return e.err.Error()
+}
+
+func (e errClient) Unwrap() error {
+	return e.err
+}
+
+func (matcher *GitHubMatcher) makeRequest(ctx context.Context, body []byte, result *advisoriesResponse) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, matcher.APIURL, bytes.NewReader(body))
+	if err != nil {
+		return errClient{err}
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+matcher.Token)
+	if matcher.UserAgent != "" {
+		req.Header.Set("User-Agent", matcher.UserAgent)
+	}
+
+	resp, err := matcher.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if wait, ok := rateLimitWait(resp); ok {
+		return errRateLimited{wait: wait}
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return errClient{fmt.Errorf("the GitHub API rejected the token in $%s: status=%q", TokenEnvVar, resp.Status)}
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return errClient{fmt.Errorf("client error: status=%q body=%s", resp.Status, respBody)}
+	case resp.StatusCode >= 500:
+		return fmt.Errorf("server error: status=%q body=%s", resp.Status, respBody)
+	}
+
+	*result = advisoriesResponse{}
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return errClient{fmt.Errorf("unexpected response from the GitHub API: %w", err)}
+	}
+
+	for _, e := range result.Errors {
+		if e.Type == "RATE_LIMITED" {
+			return errRateLimited{wait: rateLimitReset(resp.Header)}
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		return errClient{fmt.Errorf("the GitHub API returned an error: %s", result.Errors[0].Message)}
+	}
+
+	return nil
+}
+
+// rateLimitWait determines if the response is GitHub rate limiting the request, in which case
+// how long to wait before retrying is returned, following
+// https://docs.github.com/en/rest/using-the-rest-api/best-practices-for-using-the-rest-api#handle-rate-limit-errors-appropriately
+func rateLimitWait(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		return rateLimitReset(resp.Header), true
+	}
+
+	// secondary rate limits without a Retry-After header should wait for at least a minute,
+	// while a 403 without any rate limit headers is the token not having access
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return time.Minute, true
+	}
+
+	return 0, false
+}
+
+// rateLimitReset is how long until the primary rate limit is reset, per the X-RateLimit-Reset header
+func rateLimitReset(header http.Header) time.Duration {
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Minute
+	}
+
+	return max(time.Until(time.Unix(reset, 0)), 0)
+}
@@ -0,0 +1,123 @@
+package githubmatcher
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scanner/v2/internal/clients/clientinterfaces"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/utility/vulns"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// DefaultAPIURL is the URL of the GitHub GraphQL API
+	DefaultAPIURL = "https://api.github.com/graphql"
+
+	// TokenEnvVar is the environment variable that the token for the GitHub API is read from
+	TokenEnvVar = "GITHUB_TOKEN"
+
+	// GitHub asks for requests to be made serially to avoid its secondary rate limits,
+	// so only a few packages are queried at once
+	maxConcurrentRequests = 4
+)
+
+// GitHubMatcher implements the VulnerabilityMatcher interface by merging the vulnerabilities
+// matched by another matcher with the advisories of GitHub's security advisory database,
+// including any that are only visible with the token.
+//
+// Advisories that are already in the results of the other matcher, either by their GHSA ID
+// or one of their aliases, are not added again.
+type GitHubMatcher struct {
+	// Matcher is the matcher whose results the advisories from GitHub are merged into
+	Matcher    clientinterfaces.VulnerabilityMatcher
+	HTTPClient *http.Client
+	// Token authenticates the requests to the GitHub API
+	Token     string
+	APIURL    string
+	UserAgent string
+}
+
+// githubPackage identifies a package of an ecosystem that is covered by GitHub's security advisory database
+type githubPackage struct {
+	ecosystem osvschema.Ecosystem
+	name      string
+}
+
+func (matcher *GitHubMatcher) MatchVulnerabilities(ctx context.Context, invs []*extractor.Inventory) ([][]*osvschema.Vulnerability, error) {
+	results, err := matcher.Matcher.MatchVulnerabilities(ctx, invs)
+	if err != nil {
+		return results, err
+	}
+
+	var pkgs []githubPackage
+	indexes := make(map[githubPackage]int)
+	for _, inv := range invs {
+		pkg := imodels.FromInventory(inv)
+
+		if _, ok := githubEcosystems[pkg.Ecosystem().Ecosystem]; !ok {
+			continue
+		}
+
+		gp := githubPackage{ecosystem: pkg.Ecosystem().Ecosystem, name: pkg.Name()}
+		if _, ok := indexes[gp]; !ok {
+			indexes[gp] = len(pkgs)
+			pkgs = append(pkgs, gp)
+		}
+	}
+
+	advisories := make([][]osvschema.Vulnerability, len(pkgs))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentRequests)
+
+	for i, gp := range pkgs {
+		g.Go(func() error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			var err error
+			advisories[i], err = matcher.queryAdvisories(ctx, gp)
+
+			return err
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	for i, inv := range invs {
+		pkg := imodels.FromInventory(inv)
+
+		j, ok := indexes[githubPackage{ecosystem: pkg.Ecosystem().Ecosystem, name: pkg.Name()}]
+		if !ok {
+			continue
+		}
+
+		for _, advisory := range advisories[j] {
+			if vulns.IsAffected(advisory, pkg) && !alreadyMatched(results[i], advisory) {
+				results[i] = append(results[i], &advisory)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// alreadyMatched checks if the advisory is one of the vulnerabilities,
+// or is an alias of (or aliased by) one of them
+func alreadyMatched(vs []*osvschema.Vulnerability, advisory osvschema.Vulnerability) bool {
+	ids := append([]string{advisory.ID}, advisory.Aliases...)
+
+	for _, v := range vs {
+		if slices.Contains(ids, v.ID) || slices.ContainsFunc(v.Aliases, func(alias string) bool { return slices.Contains(ids, alias) }) {
+			return true
+		}
+	}
+
+	return false
+}
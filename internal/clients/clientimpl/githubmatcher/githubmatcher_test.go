@@ -0,0 +1,201 @@
+package githubmatcher_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/githubmatcher"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/ecosystemmock"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// staticMatcher matches the vulnerabilities of each package by its name and version
+type staticMatcher map[string][]*osvschema.Vulnerability
+
+func (m staticMatcher) MatchVulnerabilities(_ context.Context, invs []*extractor.Inventory) ([][]*osvschema.Vulnerability, error) {
+	results := make([][]*osvschema.Vulnerability, len(invs))
+	for i, inv := range invs {
+		results[i] = m[inv.Name+"@"+inv.Version]
+	}
+
+	return results, nil
+}
+
+// advisories are the responses of the fake GitHub API, by the name of the package
+var advisories = map[string]string{
+	"minimist": `{"data": {"securityVulnerabilities": {
+		"nodes": [
+			{
+				"vulnerableVersionRange": "< 1.2.6",
+				"firstPatchedVersion": {"identifier": "1.2.6"},
+				"advisory": {
+					"ghsaId": "GHSA-xvch-5gv4-984h",
+					"summary": "Prototype Pollution in minimist",
+					"identifiers": [{"type": "GHSA", "value": "GHSA-xvch-5gv4-984h"}, {"type": "CVE", "value": "CVE-2021-44906"}]
+				}
+			},
+			{
+				"vulnerableVersionRange": ">= 1.0.0, <= 1.2.5",
+				"advisory": {
+					"ghsaId": "GHSA-priv-ate0-0001",
+					"summary": "Private advisory",
+					"identifiers": [{"type": "GHSA", "value": "GHSA-priv-ate0-0001"}],
+					"cvssSeverities": {"cvssV3": {"vectorString": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}}
+				}
+			},
+			{
+				"vulnerableVersionRange": "= 0.0.8",
+				"advisory": {
+					"ghsaId": "GHSA-not0-affe-cted",
+					"identifiers": [{"type": "GHSA", "value": "GHSA-not0-affe-cted"}]
+				}
+			}
+		],
+		"pageInfo": {"hasNextPage": false}
+	}}}`,
+	"left-pad": `{"data": {"securityVulnerabilities": {"nodes": [], "pageInfo": {"hasNextPage": false}}}}`,
+}
+
+func TestGitHubMatcher_MatchVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization header = %q, want %q", got, "Bearer secret")
+		}
+
+		var req struct {
+			Variables struct {
+				Ecosystem string `json:"ecosystem"`
+				Package   string `json:"package"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("could not decode request: %v", err)
+		}
+		if req.Variables.Ecosystem != "NPM" {
+			t.Errorf("ecosystem = %q, want NPM", req.Variables.Ecosystem)
+		}
+
+		_, _ = w.Write([]byte(advisories[req.Variables.Package]))
+	}))
+	defer server.Close()
+
+	matcher := githubmatcher.GitHubMatcher{
+		Matcher: staticMatcher{
+			// osv.dev only knows of the public advisory, under the CVE
+			"minimist@1.2.5": {{ID: "CVE-2021-44906", Aliases: []string{"GHSA-xvch-5gv4-984h"}}},
+		},
+		HTTPClient: http.DefaultClient,
+		Token:      "secret",
+		APIURL:     server.URL,
+	}
+
+	npm := ecosystemmock.Extractor{MockEcosystem: "npm"}
+	results, err := matcher.MatchVulnerabilities(context.Background(), []*extractor.Inventory{
+		{Name: "minimist", Version: "1.2.5", Extractor: npm},
+		{Name: "left-pad", Version: "1.3.0", Extractor: npm},
+		{Name: "minimist", Version: "1.2.6", Extractor: npm},
+		{Name: "lodash", Version: "4.17.21", Extractor: ecosystemmock.Extractor{MockEcosystem: "Debian:12"}},
+	})
+	if err != nil {
+		t.Fatalf("MatchVulnerabilities() error = %v", err)
+	}
+
+	var got [][]string
+	for _, vs := range results {
+		ids := []string{}
+		for _, v := range vs {
+			ids = append(ids, v.ID)
+		}
+		got = append(got, ids)
+	}
+
+	want := [][]string{
+		{"CVE-2021-44906", "GHSA-priv-ate0-0001"},
+		{},
+		{},
+		{},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("MatchVulnerabilities() mismatch (-want +got):\n%s", diff)
+	}
+
+	// each package should only be queried once, and unsupported ecosystems not at all
+	if n := requests.Load(); n != 2 {
+		t.Errorf("made %d requests, want 2", n)
+	}
+}
+
+func TestGitHubMatcher_MatchVulnerabilities_RateLimited(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(advisories["minimist"]))
+	}))
+	defer server.Close()
+
+	matcher := githubmatcher.GitHubMatcher{
+		Matcher:    staticMatcher{},
+		HTTPClient: http.DefaultClient,
+		Token:      "secret",
+		APIURL:     server.URL,
+	}
+
+	results, err := matcher.MatchVulnerabilities(context.Background(), []*extractor.Inventory{
+		{Name: "minimist", Version: "1.2.5", Extractor: ecosystemmock.Extractor{MockEcosystem: "npm"}},
+	})
+	if err != nil {
+		t.Fatalf("MatchVulnerabilities() error = %v", err)
+	}
+
+	if len(results[0]) != 2 {
+		t.Errorf("MatchVulnerabilities() matched %d vulnerabilities, want 2", len(results[0]))
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("made %d requests, want 2", n)
+	}
+}
+
+func TestGitHubMatcher_MatchVulnerabilities_RateLimitTooLong(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	matcher := githubmatcher.GitHubMatcher{
+		Matcher:    staticMatcher{},
+		HTTPClient: http.DefaultClient,
+		Token:      "secret",
+		APIURL:     server.URL,
+	}
+
+	_, err := matcher.MatchVulnerabilities(context.Background(), []*extractor.Inventory{
+		{Name: "minimist", Version: "1.2.5", Extractor: ecosystemmock.Extractor{MockEcosystem: "npm"}},
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "rate limited") {
+		t.Errorf("MatchVulnerabilities() error = %v, want it to be rate limited", err)
+	}
+}
@@ -0,0 +1,388 @@
+package incrementalmatcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scanner/v2/internal/cachefile"
+	"github.com/google/osv-scanner/v2/internal/clients/clientinterfaces"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/internal/version"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+const (
+	// DefaultMaxAge is how long the cached vulnerabilities of a package are reused for when
+	// the version of the database they were matched against cannot be checked, such as when
+	// querying the OSV API which is updated continuously
+	DefaultMaxAge = time.Hour
+
+	// CacheKind is the kind of cache that incremental scans are cached as, whose
+	// directory the matched vulnerabilities are cached in
+	CacheKind = "incremental"
+
+	cacheFileName = "matched_vulnerabilities.json"
+
+	// cacheVersion is incremented whenever the format of the cache changes,
+	// so that caches written by other versions of the scanner are discarded
+	cacheVersion = 1
+)
+
+// DatabaseVersionFunc returns an identifier of the version of the database of the ecosystem
+// that packages are matched against, which changes whenever the database is updated
+type DatabaseVersionFunc func(ctx context.Context, ecosystem osvschema.Ecosystem) (string, error)
+
+// IncrementalMatcher implements the VulnerabilityMatcher interface by reusing the vulnerabilities
+// that were matched by previous scans for the packages of source files that have not changed since,
+// only passing the packages of new or changed files to Matcher.
+//
+// Source files are identified by a hash of their content, and the vulnerabilities of a package are
+// only reused while the database they were matched against has not been updated, as reported by
+// DatabaseVersion, or otherwise for MaxAge after they were matched.
+type IncrementalMatcher struct {
+	// Matcher matches the packages whose vulnerabilities are not cached
+	Matcher clientinterfaces.VulnerabilityMatcher
+	// CachePath is the file that the matched vulnerabilities are cached in
+	CachePath string
+	// MatcherID identifies the database that Matcher matches against (e.g. the URL of the OSV API),
+	// with the cache being discarded if it was written for a different database
+	MatcherID string
+	// DatabaseVersion returns the version of the database, if Matcher matches against a local copy of it
+	DatabaseVersion DatabaseVersionFunc
+	MaxAge          time.Duration
+	// ScannerVersion is the version of the scanner that wrote the cache, with caches written by
+	// other versions being discarded as they may have extracted or matched packages differently
+	ScannerVersion string
+
+	dbVersions map[osvschema.Ecosystem]string
+}
+
+type cacheFile struct {
+	Version        int                     `json:"version"`
+	ScannerVersion string                  `json:"scanner_version"`
+	MatcherID      string                  `json:"matcher_id"`
+	Sources        map[string]cachedSource `json:"sources"`
+}
+
+// cachedSource is the source file that packages were extracted from, by its absolute path
+type cachedSource struct {
+	Hash     string          `json:"hash"`
+	Packages []cachedPackage `json:"packages"`
+}
+
+type cachedPackage struct {
+	Ecosystem       string                    `json:"ecosystem"`
+	Name            string                    `json:"name"`
+	Version         string                    `json:"version"`
+	Commit          string                    `json:"commit,omitempty"`
+	DatabaseVersion string                    `json:"database_version,omitempty"`
+	MatchedAt       time.Time                 `json:"matched_at"`
+	Vulnerabilities []osvschema.Vulnerability `json:"vulnerabilities"`
+}
+
+func (p cachedPackage) is(pkg imodels.PackageInfo) bool {
+	return p.Ecosystem == pkg.Ecosystem().String() &&
+		p.Name == pkg.Name() &&
+		p.Version == pkg.Version() &&
+		p.Commit == pkg.Commit()
+}
+
+// NewIncrementalMatcher creates a matcher that caches the vulnerabilities matched by matcher within cacheDir,
+// which defaults to the user cache directory if it is empty
+func NewIncrementalMatcher(cacheDir string, matcher clientinterfaces.VulnerabilityMatcher, matcherID string) (*IncrementalMatcher, error) {
+	cacheDir, err := cachefile.Dir(cacheDir, CacheKind)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IncrementalMatcher{
+		Matcher:        matcher,
+		CachePath:      filepath.Join(cacheDir, cacheFileName),
+		MatcherID:      matcherID,
+		MaxAge:         DefaultMaxAge,
+		ScannerVersion: version.OSVVersion,
+	}, nil
+}
+
+func (matcher *IncrementalMatcher) MatchVulnerabilities(ctx context.Context, invs []*extractor.Inventory) ([][]*osvschema.Vulnerability, error) {
+	cache := matcher.readCache()
+	now := time.Now()
+
+	// the hashes of the source files of the packages, which are empty for sources that are not files
+	hashes := make(map[string]string)
+	sources := make([]string, len(invs))
+
+	results := make([][]*osvschema.Vulnerability, len(invs))
+	var unmatched []int
+	for i, inv := range invs {
+		pkg := imodels.FromInventory(inv)
+		sources[i] = sourceOf(pkg, hashes)
+
+		if vulns, ok := matcher.lookup(ctx, cache, sources[i], hashes[sources[i]], pkg, now); ok {
+			results[i] = vulns
+			continue
+		}
+
+		unmatched = append(unmatched, i)
+	}
+
+	slog.Info(fmt.Sprintf(
+		"Reusing the cached vulnerabilities of %d of %d %s, which are from unchanged files",
+		len(invs)-len(unmatched),
+		len(invs),
+		output.Form(len(invs), "package", "packages"),
+	))
+
+	if len(unmatched) == 0 {
+		return results, nil
+	}
+
+	unmatchedInvs := make([]*extractor.Inventory, 0, len(unmatched))
+	for _, i := range unmatched {
+		unmatchedInvs = append(unmatchedInvs, invs[i])
+	}
+
+	res, err := matcher.Matcher.MatchVulnerabilities(ctx, unmatchedInvs)
+	if err != nil {
+		// incomplete results are not cached, as the packages would otherwise not be matched again
+		if res == nil {
+			return nil, err
+		}
+		for j, i := range unmatched {
+			if j < len(res) {
+				results[i] = res[j]
+			}
+		}
+
+		return results, err
+	}
+
+	for j, i := range unmatched {
+		results[i] = res[j]
+
+		if hashes[sources[i]] == "" {
+			continue
+		}
+
+		pkg := imodels.FromInventory(invs[i])
+		matcher.store(ctx, cache, sources[i], hashes[sources[i]], pkg, res[j], now)
+	}
+
+	if err := matcher.writeCache(cache); err != nil {
+		slog.Warn(fmt.Sprintf("Failed to cache matched vulnerabilities at %s: %v", matcher.CachePath, err))
+	}
+
+	return results, nil
+}
+
+// sourceOf returns the absolute path of the source file that the package is from,
+// hashing its content if it has not been hashed yet
+func sourceOf(pkg imodels.PackageInfo, hashes map[string]string) string {
+	source := pkg.Location()
+	if source == "" {
+		return ""
+	}
+
+	if abs, err := filepath.Abs(source); err == nil {
+		source = abs
+	}
+
+	if _, ok := hashes[source]; !ok {
+		hashes[source] = hashFile(source)
+	}
+
+	return source
+}
+
+// hashFile returns a hash of the content of the file, or an empty string if it cannot be read,
+// such as when the package is from within an archive rather than a file of its own
+func hashFile(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	if info, err := f.Stat(); err != nil || !info.Mode().IsRegular() {
+		return ""
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// lookup returns the cached vulnerabilities of the package, if its source file has not
+// changed and the database has not been updated since they were matched
+func (matcher *IncrementalMatcher) lookup(ctx context.Context, cache *cacheFile, source string, hash string, pkg imodels.PackageInfo, now time.Time) ([]*osvschema.Vulnerability, bool) {
+	if hash == "" {
+		return nil, false
+	}
+
+	cached, ok := cache.Sources[source]
+	if !ok || cached.Hash != hash {
+		return nil, false
+	}
+
+	for _, p := range cached.Packages {
+		if !p.is(pkg) {
+			continue
+		}
+
+		if !matcher.isFresh(ctx, p, pkg.Ecosystem().Ecosystem, now) {
+			return nil, false
+		}
+
+		vulns := make([]*osvschema.Vulnerability, len(p.Vulnerabilities))
+		for i := range p.Vulnerabilities {
+			vulns[i] = &p.Vulnerabilities[i]
+		}
+
+		return vulns, true
+	}
+
+	return nil, false
+}
+
+// isFresh returns true if the database that the vulnerabilities of the package
+// were matched against has not been updated since
+func (matcher *IncrementalMatcher) isFresh(ctx context.Context, p cachedPackage, ecosystem osvschema.Ecosystem, now time.Time) bool {
+	if matcher.DatabaseVersion != nil && ecosystem != "" {
+		version := matcher.databaseVersion(ctx, ecosystem)
+
+		return version != "" && version == p.DatabaseVersion
+	}
+
+	return now.Sub(p.MatchedAt) < matcher.MaxAge
+}
+
+// databaseVersion returns the version of the database of the ecosystem,
+// which is empty if it cannot be determined
+func (matcher *IncrementalMatcher) databaseVersion(ctx context.Context, ecosystem osvschema.Ecosystem) string {
+	if matcher.DatabaseVersion == nil || ecosystem == "" {
+		return ""
+	}
+
+	if version, ok := matcher.dbVersions[ecosystem]; ok {
+		return version
+	}
+
+	if matcher.dbVersions == nil {
+		matcher.dbVersions = make(map[osvschema.Ecosystem]string)
+	}
+
+	version, err := matcher.DatabaseVersion(ctx, ecosystem)
+	if err != nil {
+		slog.Debug(fmt.Sprintf("Could not determine the version of the %s database: %v", ecosystem, err))
+		version = ""
+	}
+	matcher.dbVersions[ecosystem] = version
+
+	return version
+}
+
+// store caches the vulnerabilities that were matched for the package, replacing the cached
+// packages of its source file if the file has changed since they were cached
+func (matcher *IncrementalMatcher) store(ctx context.Context, cache *cacheFile, source string, hash string, pkg imodels.PackageInfo, vulns []*osvschema.Vulnerability, now time.Time) {
+	cached := cache.Sources[source]
+	if cached.Hash != hash {
+		cached = cachedSource{Hash: hash}
+	}
+
+	p := cachedPackage{
+		Ecosystem:       pkg.Ecosystem().String(),
+		Name:            pkg.Name(),
+		Version:         pkg.Version(),
+		Commit:          pkg.Commit(),
+		DatabaseVersion: matcher.databaseVersion(ctx, pkg.Ecosystem().Ecosystem),
+		MatchedAt:       now,
+		Vulnerabilities: make([]osvschema.Vulnerability, 0, len(vulns)),
+	}
+	for _, vuln := range vulns {
+		p.Vulnerabilities = append(p.Vulnerabilities, *vuln)
+	}
+
+	replaced := false
+	for i := range cached.Packages {
+		if cached.Packages[i].is(pkg) {
+			cached.Packages[i] = p
+			replaced = true
+
+			break
+		}
+	}
+	if !replaced {
+		cached.Packages = append(cached.Packages, p)
+	}
+
+	cache.Sources[source] = cached
+}
+
+// readCache reads the cached vulnerabilities, returning an empty cache if there is no usable cache
+func (matcher *IncrementalMatcher) readCache() *cacheFile {
+	empty := &cacheFile{
+		Version:        cacheVersion,
+		ScannerVersion: matcher.ScannerVersion,
+		MatcherID:      matcher.MatcherID,
+		Sources:        make(map[string]cachedSource),
+	}
+
+	content, err := os.ReadFile(matcher.CachePath)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			slog.Warn(fmt.Sprintf("Could not read cached vulnerabilities at %s: %v", matcher.CachePath, err))
+		}
+
+		return empty
+	}
+
+	var cache cacheFile
+	if err := json.Unmarshal(content, &cache); err != nil {
+		slog.Warn(fmt.Sprintf("Cached vulnerabilities at %s are invalid, so are being discarded: %v", matcher.CachePath, err))
+
+		return empty
+	}
+
+	if cache.Version != cacheVersion ||
+		cache.ScannerVersion != matcher.ScannerVersion ||
+		cache.MatcherID != matcher.MatcherID ||
+		cache.Sources == nil {
+		slog.Debug("Discarding cached vulnerabilities at " + matcher.CachePath + ", as they were matched differently")
+
+		return empty
+	}
+
+	return &cache
+}
+
+// writeCache writes the cached vulnerabilities, leaving out those of the
+// source files that no longer exist
+func (matcher *IncrementalMatcher) writeCache(cache *cacheFile) error {
+	for source := range cache.Sources {
+		if _, err := os.Stat(source); errors.Is(err, fs.ErrNotExist) {
+			delete(cache.Sources, source)
+		}
+	}
+
+	content, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+
+	return cachefile.Write(matcher.CachePath, content)
+}
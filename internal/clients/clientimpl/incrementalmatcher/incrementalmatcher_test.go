@@ -0,0 +1,298 @@
+package incrementalmatcher_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/incrementalmatcher"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/ecosystemmock"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// countingMatcher matches the vulnerabilities of each package by its name and version,
+// recording the names of the packages that it was asked to match
+type countingMatcher struct {
+	vulns   map[string][]*osvschema.Vulnerability
+	matched []string
+	err     error
+}
+
+func (m *countingMatcher) MatchVulnerabilities(_ context.Context, invs []*extractor.Inventory) ([][]*osvschema.Vulnerability, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	results := make([][]*osvschema.Vulnerability, len(invs))
+	for i, inv := range invs {
+		m.matched = append(m.matched, inv.Name)
+		results[i] = m.vulns[inv.Name+"@"+inv.Version]
+	}
+
+	return results, nil
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("could not write %s: %v", path, err)
+	}
+}
+
+func newInventory(name string, version string, location string) *extractor.Inventory {
+	return &extractor.Inventory{
+		Name:      name,
+		Version:   version,
+		Locations: []string{location},
+		Extractor: ecosystemmock.Extractor{MockEcosystem: "npm"},
+	}
+}
+
+func newMatcher(t *testing.T, inner *countingMatcher) *incrementalmatcher.IncrementalMatcher {
+	t.Helper()
+
+	return &incrementalmatcher.IncrementalMatcher{
+		Matcher:   inner,
+		CachePath: filepath.Join(t.TempDir(), "cache.json"),
+		MatcherID: "test",
+		MaxAge:    time.Hour,
+	}
+}
+
+func vulnIDs(results [][]*osvschema.Vulnerability) [][]string {
+	ids := make([][]string, 0, len(results))
+	for _, vulns := range results {
+		pkgIDs := []string{}
+		for _, vuln := range vulns {
+			pkgIDs = append(pkgIDs, vuln.ID)
+		}
+		ids = append(ids, pkgIDs)
+	}
+
+	return ids
+}
+
+func TestIncrementalMatcher_MatchVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first", "package-lock.json")
+	second := filepath.Join(dir, "second", "package-lock.json")
+	_ = os.MkdirAll(filepath.Dir(first), 0750)
+	_ = os.MkdirAll(filepath.Dir(second), 0750)
+	writeFile(t, first, `{"lodash": "4.17.20"}`)
+	writeFile(t, second, `{"minimist": "1.2.5"}`)
+
+	inner := &countingMatcher{vulns: map[string][]*osvschema.Vulnerability{
+		"lodash@4.17.20": {{ID: "GHSA-35jh-r3h4-6jhm"}},
+		"minimist@1.2.5": {{ID: "GHSA-xvch-5gv4-984h"}},
+		"minimist@1.2.6": {},
+		"archived@1.0.0": {{ID: "GHSA-archived"}},
+	}}
+	matcher := newMatcher(t, inner)
+
+	invs := []*extractor.Inventory{
+		newInventory("lodash", "4.17.20", first),
+		newInventory("minimist", "1.2.5", second),
+		newInventory("archived", "1.0.0", filepath.Join(dir, "archive.zip:package-lock.json")),
+	}
+
+	results, err := matcher.MatchVulnerabilities(context.Background(), invs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := [][]string{{"GHSA-35jh-r3h4-6jhm"}, {"GHSA-xvch-5gv4-984h"}, {"GHSA-archived"}}
+	if diff := cmp.Diff(want, vulnIDs(results)); diff != "" {
+		t.Errorf("MatchVulnerabilities() mismatch (-want +got):\n%s", diff)
+	}
+
+	// only the packages of the changed file, and those that are not from a file, are matched again
+	writeFile(t, second, `{"minimist": "1.2.6"}`)
+	invs[1] = newInventory("minimist", "1.2.6", second)
+	inner.matched = nil
+
+	results, err = matcher.MatchVulnerabilities(context.Background(), invs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = [][]string{{"GHSA-35jh-r3h4-6jhm"}, {}, {"GHSA-archived"}}
+	if diff := cmp.Diff(want, vulnIDs(results)); diff != "" {
+		t.Errorf("MatchVulnerabilities() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"minimist", "archived"}, inner.matched); diff != "" {
+		t.Errorf("MatchVulnerabilities() matched packages mismatch (-want +got):\n%s", diff)
+	}
+
+	// the vulnerabilities of every package are reused once they have all been cached
+	inner.matched = nil
+	if _, err := matcher.MatchVulnerabilities(context.Background(), invs[:2]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(inner.matched) != 0 {
+		t.Errorf("MatchVulnerabilities() matched %v again, even though they are cached", inner.matched)
+	}
+}
+
+func TestIncrementalMatcher_MaxAge(t *testing.T) {
+	t.Parallel()
+
+	lockfile := filepath.Join(t.TempDir(), "package-lock.json")
+	writeFile(t, lockfile, `{}`)
+	invs := []*extractor.Inventory{newInventory("lodash", "4.17.20", lockfile)}
+
+	inner := &countingMatcher{}
+	matcher := newMatcher(t, inner)
+	matcher.MaxAge = 0
+
+	for range 2 {
+		if _, err := matcher.MatchVulnerabilities(context.Background(), invs); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if diff := cmp.Diff([]string{"lodash", "lodash"}, inner.matched); diff != "" {
+		t.Errorf("MatchVulnerabilities() matched packages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestIncrementalMatcher_DatabaseVersion(t *testing.T) {
+	t.Parallel()
+
+	lockfile := filepath.Join(t.TempDir(), "package-lock.json")
+	writeFile(t, lockfile, `{}`)
+	invs := []*extractor.Inventory{newInventory("lodash", "4.17.20", lockfile)}
+
+	inner := &countingMatcher{}
+	version := "v1"
+
+	match := func() {
+		t.Helper()
+
+		matcher := newMatcher(t, inner)
+		matcher.CachePath = filepath.Join(filepath.Dir(lockfile), "cache.json")
+		matcher.DatabaseVersion = func(_ context.Context, ecosystem osvschema.Ecosystem) (string, error) {
+			if ecosystem != osvschema.EcosystemNPM {
+				t.Errorf("unexpected ecosystem %s", ecosystem)
+			}
+
+			return version, nil
+		}
+
+		if _, err := matcher.MatchVulnerabilities(context.Background(), invs); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	match()
+	match()
+	if diff := cmp.Diff([]string{"lodash"}, inner.matched); diff != "" {
+		t.Errorf("MatchVulnerabilities() matched packages mismatch (-want +got):\n%s", diff)
+	}
+
+	// the cache is invalidated once the database is updated
+	version = "v2"
+	match()
+	if diff := cmp.Diff([]string{"lodash", "lodash"}, inner.matched); diff != "" {
+		t.Errorf("MatchVulnerabilities() matched packages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestIncrementalMatcher_DifferentMatcher(t *testing.T) {
+	t.Parallel()
+
+	lockfile := filepath.Join(t.TempDir(), "package-lock.json")
+	writeFile(t, lockfile, `{}`)
+	invs := []*extractor.Inventory{newInventory("lodash", "4.17.20", lockfile)}
+
+	inner := &countingMatcher{}
+	matcher := newMatcher(t, inner)
+	if _, err := matcher.MatchVulnerabilities(context.Background(), invs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	matcher.MatcherID = "other"
+	if _, err := matcher.MatchVulnerabilities(context.Background(), invs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"lodash", "lodash"}, inner.matched); diff != "" {
+		t.Errorf("MatchVulnerabilities() matched packages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestIncrementalMatcher_Errors(t *testing.T) {
+	t.Parallel()
+
+	lockfile := filepath.Join(t.TempDir(), "package-lock.json")
+	writeFile(t, lockfile, `{}`)
+	invs := []*extractor.Inventory{newInventory("lodash", "4.17.20", lockfile)}
+
+	inner := &countingMatcher{err: errors.New("API query failed")}
+	matcher := newMatcher(t, inner)
+
+	if _, err := matcher.MatchVulnerabilities(context.Background(), invs); err == nil {
+		t.Errorf("expected an error from the matcher")
+	}
+
+	// results that failed to be matched are not cached
+	inner.err = nil
+	if _, err := matcher.MatchVulnerabilities(context.Background(), invs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal([]string{"lodash"}, inner.matched) {
+		t.Errorf("MatchVulnerabilities() matched %v, want lodash", inner.matched)
+	}
+}
+
+func TestIncrementalMatcher_InvalidCache(t *testing.T) {
+	t.Parallel()
+
+	lockfile := filepath.Join(t.TempDir(), "package-lock.json")
+	writeFile(t, lockfile, `{}`)
+	invs := []*extractor.Inventory{newInventory("lodash", "4.17.20", lockfile)}
+
+	inner := &countingMatcher{}
+	matcher := newMatcher(t, inner)
+	writeFile(t, matcher.CachePath, `{"version": `)
+
+	if _, err := matcher.MatchVulnerabilities(context.Background(), invs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := matcher.MatchVulnerabilities(context.Background(), invs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"lodash"}, inner.matched); diff != "" {
+		t.Errorf("MatchVulnerabilities() matched packages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestIncrementalMatcher_DifferentScannerVersion(t *testing.T) {
+	t.Parallel()
+
+	lockfile := filepath.Join(t.TempDir(), "package-lock.json")
+	writeFile(t, lockfile, `{}`)
+	invs := []*extractor.Inventory{newInventory("lodash", "4.17.20", lockfile)}
+
+	inner := &countingMatcher{}
+	matcher := newMatcher(t, inner)
+
+	for _, version := range []string{"2.0.0", "2.0.0", "2.1.0"} {
+		matcher.ScannerVersion = version
+		if _, err := matcher.MatchVulnerabilities(context.Background(), invs); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// the cache written by 2.0.0 is discarded once the scanner is upgraded
+	if diff := cmp.Diff([]string{"lodash", "lodash"}, inner.matched); diff != "" {
+		t.Errorf("MatchVulnerabilities() matched packages mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,211 @@
+package kevmatcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+const (
+	// DefaultCatalogURL is the URL of the JSON feed of CISA's Known Exploited Vulnerabilities catalog
+	DefaultCatalogURL = "https://www.cisa.gov/sites/default/files/feeds/known_exploited_vulnerabilities.json"
+
+	// DefaultRefreshInterval is how old the cached catalog can be before it is downloaded again,
+	// which CISA usually updates at most a few times a day
+	DefaultRefreshInterval = 24 * time.Hour
+
+	cacheFileName = "known_exploited_vulnerabilities.json"
+)
+
+// CISAKEVMatcher implements the KEVMatcher interface with CISA's Known Exploited Vulnerabilities catalog.
+//
+// The catalog is cached at CachePath, and is only downloaded again once the cache is older than
+// RefreshInterval. If downloading it fails, the cached catalog is used regardless of its age.
+type CISAKEVMatcher struct {
+	HTTPClient *http.Client
+	URL        string
+	UserAgent  string
+	// CachePath is the file that the catalog is cached at
+	CachePath       string
+	RefreshInterval time.Duration
+	// Offline only uses the cached catalog, which must have been downloaded by a previous scan
+	Offline bool
+
+	mu      sync.Mutex
+	catalog map[string]models.KEVEntry
+}
+
+type kevCatalog struct {
+	Vulnerabilities []struct {
+		CVEID                      string `json:"cveID"`
+		VulnerabilityName          string `json:"vulnerabilityName"`
+		DateAdded                  string `json:"dateAdded"`
+		DueDate                    string `json:"dueDate"`
+		KnownRansomwareCampaignUse string `json:"knownRansomwareCampaignUse"`
+	} `json:"vulnerabilities"`
+}
+
+// NewCISAKEVMatcher creates a matcher that caches the catalog within cacheDir,
+// which defaults to the user cache directory if it is empty
+func NewCISAKEVMatcher(cacheDir string, userAgent string, offline bool) (*CISAKEVMatcher, error) {
+	if cacheDir == "" {
+		var err error
+		cacheDir, err = os.UserCacheDir()
+		if err != nil {
+			cacheDir = os.TempDir()
+		}
+	}
+
+	cacheDir = filepath.Join(cacheDir, "osv-scanner", "cisa-kev")
+	if err := os.MkdirAll(cacheDir, 0750); err != nil {
+		return nil, fmt.Errorf("could not create %s: %w", cacheDir, err)
+	}
+
+	return &CISAKEVMatcher{
+		HTTPClient:      http.DefaultClient,
+		URL:             DefaultCatalogURL,
+		UserAgent:       userAgent,
+		CachePath:       filepath.Join(cacheDir, cacheFileName),
+		RefreshInterval: DefaultRefreshInterval,
+		Offline:         offline,
+	}, nil
+}
+
+func (matcher *CISAKEVMatcher) MatchKEV(ctx context.Context, cves []string) (map[string]models.KEVEntry, error) {
+	matcher.mu.Lock()
+	defer matcher.mu.Unlock()
+
+	if matcher.catalog == nil {
+		catalog, err := matcher.loadCatalog(ctx)
+		if err != nil {
+			return nil, err
+		}
+		matcher.catalog = catalog
+	}
+
+	results := make(map[string]models.KEVEntry)
+	for _, cve := range cves {
+		if entry, ok := matcher.catalog[cve]; ok {
+			results[cve] = entry
+		}
+	}
+
+	return results, nil
+}
+
+// loadCatalog reads the catalog from the cache, downloading it first if the cache is missing or stale
+func (matcher *CISAKEVMatcher) loadCatalog(ctx context.Context) (map[string]models.KEVEntry, error) {
+	info, statErr := os.Stat(matcher.CachePath)
+
+	if matcher.Offline {
+		if statErr != nil {
+			if errors.Is(statErr, fs.ErrNotExist) {
+				return nil, errors.New("the CISA KEV catalog has not been cached yet, so cannot be used offline")
+			}
+
+			return nil, statErr
+		}
+
+		return readCatalog(matcher.CachePath)
+	}
+
+	if statErr == nil && time.Since(info.ModTime()) < matcher.RefreshInterval {
+		slog.Debug("Using cached CISA KEV catalog from " + matcher.CachePath)
+
+		return readCatalog(matcher.CachePath)
+	}
+
+	if err := matcher.downloadCatalog(ctx); err != nil {
+		if statErr != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("downloading CISA KEV catalog: %w", err)
+		}
+
+		slog.Warn(fmt.Sprintf("Could not refresh the CISA KEV catalog, using the copy cached on %s: %v", info.ModTime().Format(time.DateOnly), err))
+	}
+
+	return readCatalog(matcher.CachePath)
+}
+
+// downloadCatalog downloads the catalog to the cache, replacing any existing copy once it has been validated
+func (matcher *CISAKEVMatcher) downloadCatalog(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, matcher.URL, nil)
+	if err != nil {
+		return err
+	}
+
+	if matcher.UserAgent != "" {
+		req.Header.Set("User-Agent", matcher.UserAgent)
+	}
+
+	resp, err := matcher.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %q", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if _, err := parseCatalog(body); err != nil {
+		return err
+	}
+
+	// write to a temporary file first so that a partially written catalog is never read
+	tmp := matcher.CachePath + ".tmp"
+	if err := os.WriteFile(tmp, body, 0600); err != nil {
+		return fmt.Errorf("could not cache catalog: %w", err)
+	}
+
+	return os.Rename(tmp, matcher.CachePath)
+}
+
+func readCatalog(path string) (map[string]models.KEVEntry, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read CISA KEV catalog: %w", err)
+	}
+
+	catalog, err := parseCatalog(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w (cached at %s)", err, path)
+	}
+
+	return catalog, nil
+}
+
+func parseCatalog(body []byte) (map[string]models.KEVEntry, error) {
+	var parsed kevCatalog
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid CISA KEV catalog: %w", err)
+	}
+
+	catalog := make(map[string]models.KEVEntry, len(parsed.Vulnerabilities))
+	for _, v := range parsed.Vulnerabilities {
+		catalog[v.CVEID] = models.KEVEntry{
+			CVE:                        v.CVEID,
+			VulnerabilityName:          v.VulnerabilityName,
+			DateAdded:                  v.DateAdded,
+			DueDate:                    v.DueDate,
+			KnownRansomwareCampaignUse: v.KnownRansomwareCampaignUse == "Known",
+		}
+	}
+
+	return catalog, nil
+}
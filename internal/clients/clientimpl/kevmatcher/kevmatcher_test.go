@@ -0,0 +1,194 @@
+package kevmatcher_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/kevmatcher"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+const catalog = `{
+  "title": "CISA Catalog of Known Exploited Vulnerabilities",
+  "catalogVersion": "2025.01.01",
+  "count": 2,
+  "vulnerabilities": [
+    {
+      "cveID": "CVE-2021-44228",
+      "vendorProject": "Apache",
+      "product": "Log4j2",
+      "vulnerabilityName": "Apache Log4j2 Remote Code Execution Vulnerability",
+      "dateAdded": "2021-12-10",
+      "dueDate": "2021-12-24",
+      "knownRansomwareCampaignUse": "Known"
+    },
+    {
+      "cveID": "CVE-2022-22965",
+      "vendorProject": "VMware",
+      "product": "Spring Framework",
+      "vulnerabilityName": "Spring Framework JDK 9+ Remote Code Execution Vulnerability",
+      "dateAdded": "2022-04-04",
+      "dueDate": "2022-04-25",
+      "knownRansomwareCampaignUse": "Unknown"
+    }
+  ]
+}`
+
+var log4shell = models.KEVEntry{
+	CVE:                        "CVE-2021-44228",
+	VulnerabilityName:          "Apache Log4j2 Remote Code Execution Vulnerability",
+	DateAdded:                  "2021-12-10",
+	DueDate:                    "2021-12-24",
+	KnownRansomwareCampaignUse: true,
+}
+
+func newFakeFeed(t *testing.T, status int, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			_, _ = w.Write([]byte(catalog))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func newMatcher(t *testing.T, server *httptest.Server) *kevmatcher.CISAKEVMatcher {
+	t.Helper()
+
+	matcher, err := kevmatcher.NewCISAKEVMatcher(t.TempDir(), "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matcher.HTTPClient = server.Client()
+	matcher.URL = server.URL
+
+	return matcher
+}
+
+func TestCISAKEVMatcher_MatchKEV(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	matcher := newMatcher(t, newFakeFeed(t, http.StatusOK, &requests))
+
+	got, err := matcher.MatchKEV(context.Background(), []string{"CVE-2021-44228", "CVE-2000-0001"})
+	if err != nil {
+		t.Fatalf("MatchKEV() error = %v", err)
+	}
+	if diff := cmp.Diff(map[string]models.KEVEntry{"CVE-2021-44228": log4shell}, got); diff != "" {
+		t.Errorf("MatchKEV() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := os.Stat(matcher.CachePath); err != nil {
+		t.Errorf("catalog was not cached: %v", err)
+	}
+
+	// the catalog is only loaded once per matcher
+	if _, err := matcher.MatchKEV(context.Background(), []string{"CVE-2022-22965"}); err != nil {
+		t.Fatalf("MatchKEV() error = %v", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("made %d requests, want 1", n)
+	}
+}
+
+func TestCISAKEVMatcher_MatchKEV_FreshCache(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	matcher := newMatcher(t, newFakeFeed(t, http.StatusOK, &requests))
+
+	if err := os.WriteFile(matcher.CachePath, []byte(catalog), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := matcher.MatchKEV(context.Background(), []string{"CVE-2021-44228"})
+	if err != nil {
+		t.Fatalf("MatchKEV() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("MatchKEV() returned %d entries, want 1", len(got))
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("made %d requests, want none as the cache is fresh", n)
+	}
+}
+
+func TestCISAKEVMatcher_MatchKEV_StaleCache(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	matcher := newMatcher(t, newFakeFeed(t, http.StatusInternalServerError, &requests))
+
+	if err := os.WriteFile(matcher.CachePath, []byte(catalog), 0600); err != nil {
+		t.Fatal(err)
+	}
+	stale := time.Now().Add(-2 * kevmatcher.DefaultRefreshInterval)
+	if err := os.Chtimes(matcher.CachePath, stale, stale); err != nil {
+		t.Fatal(err)
+	}
+
+	// the stale cache is used as the catalog could not be refreshed
+	got, err := matcher.MatchKEV(context.Background(), []string{"CVE-2021-44228"})
+	if err != nil {
+		t.Fatalf("MatchKEV() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("MatchKEV() returned %d entries, want 1", len(got))
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("made %d requests, want 1 to refresh the stale cache", n)
+	}
+}
+
+func TestCISAKEVMatcher_MatchKEV_DownloadFails(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	matcher := newMatcher(t, newFakeFeed(t, http.StatusInternalServerError, &requests))
+
+	if _, err := matcher.MatchKEV(context.Background(), []string{"CVE-2021-44228"}); err == nil {
+		t.Errorf("MatchKEV() error = nil, want an error as there is no cached catalog")
+	}
+}
+
+func TestCISAKEVMatcher_MatchKEV_Offline(t *testing.T) {
+	t.Parallel()
+
+	cacheDir := t.TempDir()
+
+	matcher, err := kevmatcher.NewCISAKEVMatcher(cacheDir, "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matcher.URL = "http://127.0.0.1:0/unreachable"
+
+	if _, err := matcher.MatchKEV(context.Background(), []string{"CVE-2021-44228"}); err == nil {
+		t.Errorf("MatchKEV() error = nil, want an error as there is no cached catalog")
+	}
+
+	err = os.WriteFile(filepath.Join(cacheDir, "osv-scanner", "cisa-kev", "known_exploited_vulnerabilities.json"), []byte(catalog), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := matcher.MatchKEV(context.Background(), []string{"CVE-2021-44228"})
+	if err != nil {
+		t.Fatalf("MatchKEV() error = %v", err)
+	}
+	if diff := cmp.Diff(map[string]models.KEVEntry{"CVE-2021-44228": log4shell}, got); diff != "" {
+		t.Errorf("MatchKEV() mismatch (-want +got):\n%s", diff)
+	}
+}
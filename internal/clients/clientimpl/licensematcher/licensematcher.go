@@ -2,11 +2,15 @@ package licensematcher
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"strings"
 
 	depsdevpb "deps.dev/api/v3"
 	"github.com/google/osv-scalibr/clients/datasource"
 	"github.com/google/osv-scanner/v2/internal/depsdev"
 	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/spdx"
 	"github.com/google/osv-scanner/v2/pkg/models"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc/codes"
@@ -42,6 +46,13 @@ func (matcher *DepsDevLicenseMatcher) MatchLicenses(ctx context.Context, package
 
 	for i, license := range licenses {
 		packages[i].Licenses = license
+
+		for _, l := range license {
+			if unrecognized := spdx.UnrecognizedTerms(l); len(unrecognized) > 0 {
+				pkg := packages[i].PackageInfo
+				slog.Debug(fmt.Sprintf("license %q of %s/%s/%s is not a recognized spdx license, and can be overridden in the config", strings.Join(unrecognized, ", "), pkg.Ecosystem(), pkg.Name(), pkg.Version()))
+			}
+		}
 	}
 
 	return nil
@@ -74,7 +85,7 @@ func (matcher *DepsDevLicenseMatcher) makeVersionRequest(ctx context.Context, qu
 			}
 			ls := make([]models.License, len(resp.GetLicenses()))
 			for j, license := range resp.GetLicenses() {
-				ls[j] = models.License(license)
+				ls[j] = spdx.Normalize(models.License(license))
 			}
 			if len(ls) == 0 {
 				// The deps.dev API will return an
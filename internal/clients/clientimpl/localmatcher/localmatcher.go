@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"os"
 	"path"
+	"time"
 
 	"github.com/google/osv-scalibr/extractor"
 	"github.com/google/osv-scanner/v2/internal/imodels"
@@ -27,24 +28,42 @@ type LocalMatcher struct {
 	failedDBs map[osvschema.Ecosystem]error
 	// userAgent sets the user agent requests for db zips are made with
 	userAgent string
+	// includeWithdrawn matches vulnerabilities whose advisories have been withdrawn too
+	includeWithdrawn bool
 }
 
-func NewLocalMatcher(localDBPath string, userAgent string, downloadDB bool) (*LocalMatcher, error) {
+func NewLocalMatcher(localDBPath string, userAgent string, downloadDB bool, includeWithdrawn bool) (*LocalMatcher, error) {
 	dbBasePath, err := setupLocalDBDirectory(localDBPath)
 	if err != nil {
 		return nil, fmt.Errorf("could not create %s: %w", dbBasePath, err)
 	}
 
 	return &LocalMatcher{
-		dbBasePath: dbBasePath,
-		dbs:        make(map[osvschema.Ecosystem]*ZipDB),
-		downloadDB: downloadDB,
-		userAgent:  userAgent,
-		failedDBs:  make(map[osvschema.Ecosystem]error),
+		dbBasePath:       dbBasePath,
+		dbs:              make(map[osvschema.Ecosystem]*ZipDB),
+		downloadDB:       downloadDB,
+		userAgent:        userAgent,
+		failedDBs:        make(map[osvschema.Ecosystem]error),
+		includeWithdrawn: includeWithdrawn,
 	}, nil
 }
 
 func (matcher *LocalMatcher) MatchVulnerabilities(ctx context.Context, invs []*extractor.Inventory) ([][]*osvschema.Vulnerability, error) {
+	return matcher.match(ctx, invs, VulnerabilitiesAffectingPackage)
+}
+
+// MatchAllVulnerabilities is like MatchVulnerabilities, but returns every vulnerability
+// of each package regardless of whether it affects the version of the package
+func (matcher *LocalMatcher) MatchAllVulnerabilities(ctx context.Context, invs []*extractor.Inventory) ([][]*osvschema.Vulnerability, error) {
+	return matcher.match(ctx, invs, VulnerabilitiesOfPackage)
+}
+
+// match finds the vulnerabilities of each package in the database of its ecosystem using find
+func (matcher *LocalMatcher) match(
+	ctx context.Context,
+	invs []*extractor.Inventory,
+	find func(allVulns []osvschema.Vulnerability, pkg imodels.PackageInfo) []*osvschema.Vulnerability,
+) ([][]*osvschema.Vulnerability, error) {
 	results := make([][]*osvschema.Vulnerability, 0, len(invs))
 
 	for _, inv := range invs {
@@ -73,12 +92,44 @@ func (matcher *LocalMatcher) MatchVulnerabilities(ctx context.Context, invs []*e
 			continue
 		}
 
-		results = append(results, VulnerabilitiesAffectingPackage(db.Vulnerabilities(false), pkg))
+		results = append(results, find(db.Vulnerabilities(matcher.includeWithdrawn), pkg))
 	}
 
 	return results, nil
 }
 
+// DatabaseTimestamp returns when the least up to date of the databases that have been
+// loaded was last modified, or the zero time if no databases have been loaded
+func (matcher *LocalMatcher) DatabaseTimestamp() time.Time {
+	var timestamp time.Time
+	for _, db := range matcher.dbs {
+		if timestamp.IsZero() || db.Modified.Before(timestamp) {
+			timestamp = db.Modified
+		}
+	}
+
+	return timestamp
+}
+
+// DatabaseVersion returns an identifier of the copy of the database of the ecosystem that packages are
+// matched against, which changes whenever a new copy of it is downloaded.
+//
+// The database is only loaded if databases are being downloaded, as the copy may be updated when it is.
+func (matcher *LocalMatcher) DatabaseVersion(ctx context.Context, eco osvschema.Ecosystem) (string, error) {
+	if matcher.downloadDB {
+		if _, err := matcher.loadDBFromCache(ctx, ecosystem.Parsed{Ecosystem: eco}); err != nil {
+			return "", err
+		}
+	}
+
+	info, err := os.Stat(path.Join(matcher.dbBasePath, string(eco), "all.zip"))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d-%d", info.Size(), info.ModTime().UnixNano()), nil
+}
+
 // LoadEcosystem tries to preload the ecosystem into the cache, and returns an error if the ecosystem
 // cannot be loaded.
 func (matcher *LocalMatcher) LoadEcosystem(ctx context.Context, ecosystem ecosystem.Parsed) error {
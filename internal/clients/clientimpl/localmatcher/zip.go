@@ -13,9 +13,11 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/google/osv-scanner/v2/internal/imodels"
 	"github.com/google/osv-scanner/v2/internal/utility/vulns"
@@ -33,31 +35,49 @@ type ZipDB struct {
 	StoredAt string
 	// the vulnerabilities that are loaded into this database
 	vulnerabilities []osvschema.Vulnerability
+	// when the most recently modified vulnerability in this database was modified,
+	// which is how up to date the database is
+	Modified time.Time
 	// User agent to query with
 	UserAgent string
 }
 
 var ErrOfflineDatabaseNotFound = errors.New("no offline version of the OSV database is available")
+var ErrOfflineDatabaseCorrupted = errors.New("the offline version of the OSV database is corrupted")
 
-func fetchRemoteArchiveCRC32CHash(ctx context.Context, url string) (uint32, error) {
+var errMissingCRC32CHash = errors.New("could not find crc32c= checksum")
+
+// zipDBManifest describes the archive that was downloaded, so that the cached
+// copy can be verified when it is loaded and compared against the remote
+type zipDBManifest struct {
+	ETag   string `json:"etag,omitempty"`
+	Size   int    `json:"size"`
+	CRC32C uint32 `json:"crc32c"`
+}
+
+func fetchRemoteArchiveHeaders(ctx context.Context, url string) (http.Header, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
 
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("db host returned %s", resp.Status)
+		return nil, fmt.Errorf("db host returned %s", resp.Status)
 	}
 
-	for _, value := range resp.Header.Values("X-Goog-Hash") {
+	return resp.Header, nil
+}
+
+func parseCRC32CHash(header http.Header) (uint32, error) {
+	for _, value := range header.Values("X-Goog-Hash") {
 		if strings.HasPrefix(value, "crc32c=") {
 			value = strings.TrimPrefix(value, "crc32c=")
 			out, err := base64.StdEncoding.DecodeString(value)
@@ -70,32 +90,165 @@ func fetchRemoteArchiveCRC32CHash(ctx context.Context, url string) (uint32, erro
 		}
 	}
 
-	return 0, errors.New("could not find crc32c= checksum")
+	return 0, errMissingCRC32CHash
 }
 
 func fetchLocalArchiveCRC32CHash(data []byte) uint32 {
 	return crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli))
 }
 
-func (db *ZipDB) fetchZip(ctx context.Context) ([]byte, error) {
+func (db *ZipDB) manifestPath() string {
+	return db.StoredAt + ".manifest.json"
+}
+
+// readCache reads the cached archive along with its manifest (if there is one),
+// returning an ErrOfflineDatabaseCorrupted error if the archive does not match
+// its manifest or cannot be read, such as if it was not downloaded completely
+func (db *ZipDB) readCache() ([]byte, *zipDBManifest, error) {
 	cache, err := os.ReadFile(db.StoredAt)
 
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var manifest *zipDBManifest
+
+	content, err := os.ReadFile(db.manifestPath())
+
+	// archives without a manifest might have been downloaded manually
+	if err == nil {
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			return nil, nil, fmt.Errorf("%w: could not parse manifest: %w", ErrOfflineDatabaseCorrupted, err)
+		}
+
+		if manifest.Size != len(cache) || manifest.CRC32C != fetchLocalArchiveCRC32CHash(cache) {
+			return nil, nil, fmt.Errorf("%w: archive does not match its manifest", ErrOfflineDatabaseCorrupted)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, nil, err
+	}
+
+	if _, err := zip.NewReader(bytes.NewReader(cache), int64(len(cache))); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrOfflineDatabaseCorrupted, err)
+	}
+
+	return cache, manifest, nil
+}
+
+// isCacheCurrent determines if the cached archive is the same as the remote one,
+// preferring the checksum of the archive and otherwise falling back to its ETag
+func isCacheCurrent(cache []byte, manifest *zipDBManifest, remote http.Header) (bool, error) {
+	remoteHash, err := parseCRC32CHash(remote)
+
+	if err == nil {
+		return fetchLocalArchiveCRC32CHash(cache) == remoteHash, nil
+	}
+
+	if errors.Is(err, errMissingCRC32CHash) && manifest != nil && manifest.ETag != "" {
+		return remote.Get("ETag") == manifest.ETag, nil
+	}
+
+	return false, err
+}
+
+// verifyArchive checks that the downloaded archive is complete and uncorrupted
+func verifyArchive(resp *http.Response, body []byte) error {
+	if resp.ContentLength >= 0 && int64(len(body)) != resp.ContentLength {
+		return fmt.Errorf("expected %d bytes but got %d", resp.ContentLength, len(body))
+	}
+
+	remoteHash, err := parseCRC32CHash(resp.Header)
+
+	if err == nil && remoteHash != fetchLocalArchiveCRC32CHash(body) {
+		return errors.New("checksum does not match")
+	}
+
+	if err != nil && !errors.Is(err, errMissingCRC32CHash) {
+		return err
+	}
+
+	if _, err := zip.NewReader(bytes.NewReader(body), int64(len(body))); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeCache saves the archive along with its manifest, writing the archive to
+// a temporary file first so that an interrupted write does not corrupt the cache
+func (db *ZipDB) writeCache(body []byte, etag string) error {
+	if err := os.MkdirAll(path.Dir(db.StoredAt), 0750); err != nil {
+		return err
+	}
+
+	manifest, err := json.Marshal(zipDBManifest{
+		ETag:   etag,
+		Size:   len(body),
+		CRC32C: fetchLocalArchiveCRC32CHash(body),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	//nolint:gosec // being world readable is fine
+	if err := os.WriteFile(db.StoredAt+".tmp", body, 0644); err != nil {
+		return err
+	}
+
+	if err := os.Rename(db.StoredAt+".tmp", db.StoredAt); err != nil {
+		return err
+	}
+
+	//nolint:gosec // being world readable is fine
+	return os.WriteFile(db.manifestPath(), manifest, 0644)
+}
+
+func (db *ZipDB) fetchZip(ctx context.Context) ([]byte, error) {
+	cache, manifest, err := db.readCache()
+
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		slog.Warn(fmt.Sprintf("Cached database at %s is invalid: %v", db.StoredAt, err))
+	}
+
 	if db.Offline {
+		if errors.Is(err, ErrOfflineDatabaseCorrupted) {
+			return nil, err
+		}
+
 		if err != nil {
 			return nil, ErrOfflineDatabaseNotFound
 		}
 
+		slog.Debug(fmt.Sprintf("Using cached copy of the %s database at %s", db.Name, db.StoredAt))
+
 		return cache, nil
 	}
 
 	if err == nil {
-		remoteHash, err := fetchRemoteArchiveCRC32CHash(ctx, db.ArchiveURL)
+		remote, err := fetchRemoteArchiveHeaders(ctx, db.ArchiveURL)
+
+		// if the host cannot be reached, the cached copy is still better than nothing
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			slog.Warn(fmt.Sprintf("Could not check for updates to the %s database, using cached copy: %v", db.Name, err))
+
+			return cache, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		current, err := isCacheCurrent(cache, manifest, remote)
 
 		if err != nil {
 			return nil, err
 		}
 
-		if fetchLocalArchiveCRC32CHash(cache) == remoteHash {
+		if current {
+			slog.Debug(fmt.Sprintf("Using cached copy of the %s database at %s, as it is up to date", db.Name, db.StoredAt))
+
 			return cache, nil
 		}
 	}
@@ -129,14 +282,11 @@ func (db *ZipDB) fetchZip(ctx context.Context) ([]byte, error) {
 		return nil, fmt.Errorf("could not read OSV database archive from response: %w", err)
 	}
 
-	err = os.MkdirAll(path.Dir(db.StoredAt), 0750)
-
-	if err == nil {
-		//nolint:gosec // being world readable is fine
-		err = os.WriteFile(db.StoredAt, body, 0644)
+	if err := verifyArchive(resp, body); err != nil {
+		return nil, fmt.Errorf("downloaded OSV database archive is corrupted: %w", err)
 	}
 
-	if err != nil {
+	if err := db.writeCache(body, resp.Header.Get("ETag")); err != nil {
 		slog.Warn(fmt.Sprintf("Failed to save database to %s: %v", db.StoredAt, err))
 	}
 
@@ -170,14 +320,17 @@ func (db *ZipDB) loadZipFile(zipFile *zip.File) {
 	}
 
 	db.vulnerabilities = append(db.vulnerabilities, vulnerability)
+	if vulnerability.Modified.After(db.Modified) {
+		db.Modified = vulnerability.Modified
+	}
 }
 
 // load fetches a zip archive of the OSV database and loads known vulnerabilities
 // from it (which are assumed to be in json files following the OSV spec).
 //
-// Internally, the archive is cached along with the date that it was fetched
-// so that a new version of the archive is only downloaded if it has been
-// modified, per HTTP caching standards.
+// Internally, the archive is cached along with a manifest of its size, checksum
+// and ETag, so that a new version of the archive is only downloaded if it has
+// been modified (or the cached copy has been corrupted).
 func (db *ZipDB) load(ctx context.Context) error {
 	db.vulnerabilities = []osvschema.Vulnerability{}
 
@@ -235,6 +388,19 @@ func (db *ZipDB) Vulnerabilities(includeWithdrawn bool) []osvschema.Vulnerabilit
 	return vulnerabilities
 }
 
+// VulnerabilitiesOfPackage returns the vulnerabilities that affect any version of the package
+func VulnerabilitiesOfPackage(allVulns []osvschema.Vulnerability, pkg imodels.PackageInfo) []*osvschema.Vulnerability {
+	var vulnerabilities []*osvschema.Vulnerability
+
+	for _, vulnerability := range allVulns {
+		if vulns.AffectsPackage(vulnerability, pkg) && !vulns.Include(vulnerabilities, vulnerability) {
+			vulnerabilities = append(vulnerabilities, &vulnerability)
+		}
+	}
+
+	return vulnerabilities
+}
+
 // TODO: Move this to another file.
 func VulnerabilitiesAffectingPackage(allVulns []osvschema.Vulnerability, pkg imodels.PackageInfo) []*osvschema.Vulnerability {
 	var vulnerabilities []*osvschema.Vulnerability
@@ -15,7 +15,10 @@ import (
 	"path"
 	"reflect"
 	"sort"
+	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/localmatcher"
 	"github.com/google/osv-scanner/v2/internal/testutility"
@@ -190,6 +193,34 @@ func TestNewZippedDB_Offline_WithCache(t *testing.T) {
 	expectDBToHaveOSVs(t, db, osvs)
 }
 
+func TestNewZippedDB_Modified(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+
+	ts := createZipServer(t, func(_ http.ResponseWriter, _ *http.Request) {
+		t.Errorf("a server request was made when running offline")
+	})
+
+	latest := time.Date(2025, time.March, 4, 12, 30, 0, 0, time.UTC)
+
+	cacheWrite(t, determineStoredAtPath(testDir, "my-db"), zipOSVs(t, map[string]osvschema.Vulnerability{
+		"GHSA-1.json": {ID: "GHSA-1", Modified: latest.AddDate(0, -1, 0)},
+		"GHSA-2.json": {ID: "GHSA-2", Modified: latest},
+		"GHSA-3.json": {ID: "GHSA-3"},
+	}))
+
+	db, err := localmatcher.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, userAgent, true)
+
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	if !db.Modified.Equal(latest) {
+		t.Errorf("expected db to have been modified at %v but got %v", latest, db.Modified)
+	}
+}
+
 func TestNewZippedDB_BadZip(t *testing.T) {
 	t.Parallel()
 
@@ -441,3 +472,163 @@ func TestNewZippedDB_FileChecks(t *testing.T) {
 
 	expectDBToHaveOSVs(t, db, osvs)
 }
+
+func TestNewZippedDB_Offline_WithCorruptedCache(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+
+	ts := createZipServer(t, func(_ http.ResponseWriter, _ *http.Request) {
+		t.Errorf("a server request was made when running offline")
+	})
+
+	cache := zipOSVs(t, map[string]osvschema.Vulnerability{
+		"GHSA-1.json": {ID: "GHSA-1"},
+		"GHSA-2.json": {ID: "GHSA-2"},
+	})
+
+	storedAt := determineStoredAtPath(testDir, "my-db")
+
+	// simulate a download that was cut short after the manifest was written
+	cacheWrite(t, storedAt, cache[:len(cache)/2])
+	cacheWriteBad(t, storedAt+".manifest.json", `{"size": `+strconv.Itoa(len(cache))+`, "crc32c": 1}`)
+
+	_, err := localmatcher.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, userAgent, true)
+
+	if !errors.Is(err, localmatcher.ErrOfflineDatabaseCorrupted) {
+		t.Errorf("expected \"%v\" error but got \"%v\"", localmatcher.ErrOfflineDatabaseCorrupted, err)
+	}
+}
+
+func TestNewZippedDB_Online_WithCorruptedCache(t *testing.T) {
+	t.Parallel()
+
+	osvs := []osvschema.Vulnerability{
+		{ID: "GHSA-1"},
+		{ID: "GHSA-2"},
+	}
+
+	testDir := testutility.CreateTestDir(t)
+
+	cache := zipOSVs(t, map[string]osvschema.Vulnerability{
+		"GHSA-1.json": {ID: "GHSA-1"},
+		"GHSA-2.json": {ID: "GHSA-2"},
+	})
+
+	ts := createZipServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Add("x-goog-hash", "crc32c="+computeCRC32CHash(t, cache))
+
+		_, _ = w.Write(cache)
+	})
+
+	storedAt := determineStoredAtPath(testDir, "my-db")
+
+	cacheWrite(t, storedAt, cache[:len(cache)/2])
+	cacheWriteBad(t, storedAt+".manifest.json", `{"size": `+strconv.Itoa(len(cache))+`, "crc32c": 1}`)
+
+	db, err := localmatcher.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, userAgent, false)
+
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	expectDBToHaveOSVs(t, db, osvs)
+
+	// the corrupted cache should have been replaced
+	db, err = localmatcher.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, userAgent, true)
+
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	expectDBToHaveOSVs(t, db, osvs)
+}
+
+func TestNewZippedDB_Online_WithCorruptedDownload(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+
+	ts := createZipServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		z := zipOSVs(t, map[string]osvschema.Vulnerability{
+			"GHSA-1.json": {ID: "GHSA-1"},
+			"GHSA-2.json": {ID: "GHSA-2"},
+		})
+
+		w.Header().Add("x-goog-hash", "crc32c="+computeCRC32CHash(t, z))
+
+		_, _ = w.Write(z[:len(z)-10])
+	})
+
+	_, err := localmatcher.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, userAgent, false)
+
+	if err == nil {
+		t.Errorf("expected an error but did not get one")
+	}
+
+	if _, err := os.Stat(determineStoredAtPath(testDir, "my-db")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the corrupted download to not be cached")
+	}
+}
+
+func TestNewZippedDB_Online_WithCacheAndMatchingETag(t *testing.T) {
+	t.Parallel()
+
+	osvs := []osvschema.Vulnerability{
+		{ID: "GHSA-1"},
+		{ID: "GHSA-2"},
+	}
+
+	testDir := testutility.CreateTestDir(t)
+
+	var requests atomic.Int32
+	ts := createZipServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) > 1 && r.Method != http.MethodHead {
+			t.Errorf("unexpected %s request", r.Method)
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+
+		_, _ = w.Write(zipOSVs(t, map[string]osvschema.Vulnerability{
+			"GHSA-1.json": {ID: "GHSA-1"},
+			"GHSA-2.json": {ID: "GHSA-2"},
+		}))
+	})
+
+	for range 2 {
+		db, err := localmatcher.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, userAgent, false)
+
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		expectDBToHaveOSVs(t, db, osvs)
+	}
+}
+
+func TestNewZippedDB_Online_WithCacheAndUnreachableHost(t *testing.T) {
+	t.Parallel()
+
+	osvs := []osvschema.Vulnerability{
+		{ID: "GHSA-1"},
+		{ID: "GHSA-2"},
+	}
+
+	testDir := testutility.CreateTestDir(t)
+
+	ts := createZipServer(t, func(_ http.ResponseWriter, _ *http.Request) {})
+	ts.Close()
+
+	cacheWrite(t, determineStoredAtPath(testDir, "my-db"), zipOSVs(t, map[string]osvschema.Vulnerability{
+		"GHSA-1.json": {ID: "GHSA-1"},
+		"GHSA-2.json": {ID: "GHSA-2"},
+	}))
+
+	db, err := localmatcher.NewZippedDB(context.Background(), testDir, "my-db", ts.URL, userAgent, false)
+
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	expectDBToHaveOSVs(t, db, osvs)
+}
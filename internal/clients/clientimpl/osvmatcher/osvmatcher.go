@@ -3,10 +3,13 @@ package osvmatcher
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/osv-scalibr/extractor"
 	"github.com/google/osv-scalibr/log"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
 	"github.com/google/osv-scanner/v2/internal/imodels"
 	"github.com/google/osv-scanner/v2/internal/osvdev"
 	"github.com/ossf/osv-schema/bindings/go/osvschema"
@@ -57,6 +60,14 @@ func (matcher *OSVMatcher) MatchVulnerabilities(ctx context.Context, pkgs []*ext
 		}
 	}
 
+	total := 0
+	for _, resp := range batchResp.Results {
+		total += len(resp.Vulns)
+	}
+
+	var retrieved atomic.Int64
+	defer cmdlogger.ClearProgress()
+
 	vulnerabilities := make([][]*osvschema.Vulnerability, len(batchResp.Results))
 	g, ctx := errgroup.WithContext(ctx)
 	g.SetLimit(maxConcurrentRequests)
@@ -76,6 +87,8 @@ func (matcher *OSVMatcher) MatchVulnerabilities(ctx context.Context, pkgs []*ext
 				}
 				vulnerabilities[batchIdx][resultIdx] = vuln
 
+				cmdlogger.Progress(fmt.Sprintf("Retrieved %d of %d vulnerabilities", retrieved.Add(1), total))
+
 				return nil
 			})
 		}
@@ -0,0 +1,41 @@
+package versionlister
+
+import (
+	"context"
+	"fmt"
+
+	depsdevpb "deps.dev/api/v3"
+	"github.com/google/osv-scalibr/clients/datasource"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// DepsDevVersionLister implements the VersionLister interface with a deps.dev client,
+// which caches the versions of each package it is asked about.
+type DepsDevVersionLister struct {
+	Client *datasource.CachedInsightsClient
+}
+
+func (lister *DepsDevVersionLister) ListVersions(ctx context.Context, ecosystem osvschema.Ecosystem, name string) ([]string, error) {
+	system, ok := depsdev.System[ecosystem]
+	if !ok {
+		return nil, fmt.Errorf("deps.dev does not support the %s ecosystem", ecosystem)
+	}
+
+	resp, err := lister.Client.GetPackage(ctx, &depsdevpb.GetPackageRequest{
+		PackageKey: &depsdevpb.PackageKey{
+			System: system,
+			Name:   name,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]string, 0, len(resp.GetVersions()))
+	for _, v := range resp.GetVersions() {
+		versions = append(versions, v.GetVersionKey().GetVersion())
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,12 @@
+package clientinterfaces
+
+import (
+	"context"
+
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+type EPSSMatcher interface {
+	// MatchEPSS returns the EPSS scores of the CVEs, by their ID, leaving out any that do not have a score
+	MatchEPSS(ctx context.Context, cves []string) (map[string]models.EPSSScore, error)
+}
@@ -0,0 +1,12 @@
+package clientinterfaces
+
+import (
+	"context"
+
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+type KEVMatcher interface {
+	// MatchKEV returns the entries of the CVEs that are known to be exploited, by their ID
+	MatchKEV(ctx context.Context, cves []string) (map[string]models.KEVEntry, error)
+}
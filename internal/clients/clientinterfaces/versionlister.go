@@ -0,0 +1,12 @@
+package clientinterfaces
+
+import (
+	"context"
+
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+type VersionLister interface {
+	// ListVersions returns the versions of the package that have been published to its registry
+	ListVersions(ctx context.Context, ecosystem osvschema.Ecosystem, name string) ([]string, error)
+}
@@ -10,6 +10,8 @@ var levels = []string{
 	"error",
 	"warn",
 	"info",
+	"verbose",
+	"debug",
 }
 
 func Levels() []string {
@@ -24,6 +26,8 @@ func ParseLevel(text string) (slog.Level, error) {
 		return slog.LevelWarn, nil
 	case "info":
 		return slog.LevelInfo, nil
+	case "verbose", "debug":
+		return slog.LevelDebug, nil
 	default:
 		return slog.LevelInfo, fmt.Errorf("invalid verbosity level \"%s\" - must be one of: %s", text, strings.Join(Levels(), ", "))
 	}
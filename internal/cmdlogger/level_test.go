@@ -17,6 +17,8 @@ func TestParseVerbosityLevel_GivenValidLevels(t *testing.T) {
 		{input: "error", level: slog.LevelError},
 		{input: "warn", level: slog.LevelWarn},
 		{input: "info", level: slog.LevelInfo},
+		{input: "verbose", level: slog.LevelDebug},
+		{input: "debug", level: slog.LevelDebug},
 	}
 
 	for _, tt := range tests {
@@ -7,6 +7,15 @@ import (
 	"log/slog"
 )
 
+var formats = []string{
+	"text",
+	"json",
+}
+
+func Formats() []string {
+	return formats
+}
+
 // SendEverythingToStderr tells the logger (if its in use) to send all logs
 // to stderr regardless of their level.
 //
@@ -42,11 +51,23 @@ func SetLevel(level slog.Leveler) {
 	}
 }
 
+// UseJSON tells the logger (if its in use) to write all logs to stderr as JSON
+// objects, one per line, rather than as plain text.
+func UseJSON() {
+	l, ok := slog.Default().Handler().(*CmdLogger)
+
+	if ok {
+		l.UseJSON()
+	}
+}
+
 type CmdLogger struct {
 	stdout             io.Writer
 	stderr             io.Writer
 	hasErrored         bool
 	everythingToStderr bool
+	json               slog.Handler
+	progress           *progress
 	Level              slog.Leveler
 }
 
@@ -59,8 +80,16 @@ func (c *CmdLogger) SendEverythingToStderr() {
 	c.everythingToStderr = true
 }
 
+// UseJSON tells the logger to write all logs to stderr as JSON objects, one
+// per line, so that they can be consumed by other tools.
+func (c *CmdLogger) UseJSON() {
+	c.json = slog.NewJSONHandler(c.stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
+}
+
+// writer returns where logs of the given level should be written, with debug
+// logs always going to stderr as they're only useful when troubleshooting
 func (c *CmdLogger) writer(level slog.Level) io.Writer {
-	if c.everythingToStderr || level == slog.LevelError {
+	if c.everythingToStderr || level == slog.LevelError || level < slog.LevelInfo {
 		return c.stderr
 	}
 
@@ -75,11 +104,18 @@ func (c *CmdLogger) Enabled(_ context.Context, level slog.Level) bool {
 	return level >= c.Level.Level()
 }
 
-func (c *CmdLogger) Handle(_ context.Context, record slog.Record) error {
+func (c *CmdLogger) Handle(ctx context.Context, record slog.Record) error {
 	if record.Level == slog.LevelError {
 		c.hasErrored = true
 	}
 
+	c.progress.pause()
+	defer c.progress.resume()
+
+	if c.json != nil {
+		return c.json.Handle(ctx, record)
+	}
+
 	_, err := fmt.Fprint(c.writer(record.Level), record.Message+"\n")
 
 	return err
@@ -0,0 +1,83 @@
+package cmdlogger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+)
+
+func TestCmdLogger_DebugLogsAreWrittenToStderr(t *testing.T) {
+	t.Parallel()
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	logger := cmdlogger.New(stdout, stderr)
+	logger.Level = slog.LevelDebug
+
+	log := slog.New(&logger)
+	log.Info("info message")
+	log.Debug("debug message")
+
+	if got := stdout.String(); got != "info message\n" {
+		t.Errorf("stdout = %q, want %q", got, "info message\n")
+	}
+	if got := stderr.String(); got != "debug message\n" {
+		t.Errorf("stderr = %q, want %q", got, "debug message\n")
+	}
+}
+
+func TestCmdLogger_UseJSON(t *testing.T) {
+	t.Parallel()
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	logger := cmdlogger.New(stdout, stderr)
+	logger.Level = slog.LevelDebug
+	logger.UseJSON()
+
+	log := slog.New(&logger)
+	log.Debug("debug message")
+	log.Error("error message")
+
+	if stdout.Len() != 0 {
+		t.Errorf("expected nothing to be written to stdout, got %q", stdout.String())
+	}
+
+	if !logger.HasErrored() {
+		t.Errorf("expected logger to have errored")
+	}
+
+	var got []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(stderr.Bytes()), []byte("\n")) {
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("log line %q is not valid JSON: %v", line, err)
+		}
+		got = append(got, entry)
+	}
+
+	want := []struct{ level, msg string }{
+		{level: "DEBUG", msg: "debug message"},
+		{level: "ERROR", msg: "error message"},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d log lines, want %d", len(got), len(want))
+	}
+
+	for i, w := range want {
+		if got[i]["level"] != w.level || got[i]["msg"] != w.msg {
+			t.Errorf("log line %d = %v, want level %q and msg %q", i, got[i], w.level, w.msg)
+		}
+	}
+
+	if logger.Enabled(context.Background(), slog.LevelDebug-1) {
+		t.Errorf("expected logs below the level to be disabled")
+	}
+}
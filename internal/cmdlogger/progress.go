@@ -0,0 +1,137 @@
+package cmdlogger
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// progressInterval is the minimum time between redraws of the progress line,
+// so that frequent updates do not slow down the scan
+const progressInterval = 100 * time.Millisecond
+
+// clearLine returns the cursor to the start of the line and erases it
+const clearLine = "\r\x1b[K"
+
+// ShowProgress tells the logger (if its in use) to show progress updates on
+// stderr, assuming that it is a terminal.
+func ShowProgress() {
+	l, ok := slog.Default().Handler().(*CmdLogger)
+
+	if ok {
+		l.ShowProgress()
+	}
+}
+
+// Progress updates the progress line shown by the logger (if its in use and
+// showing progress) with the given message.
+func Progress(msg string) {
+	l, ok := slog.Default().Handler().(*CmdLogger)
+
+	if ok {
+		l.Progress(msg)
+	}
+}
+
+// ClearProgress removes the progress line shown by the logger (if its in use
+// and showing progress), such as once there's nothing left to report.
+func ClearProgress() {
+	l, ok := slog.Default().Handler().(*CmdLogger)
+
+	if ok {
+		l.ClearProgress()
+	}
+}
+
+// progress is a single line on a terminal that is redrawn with each update
+type progress struct {
+	mu    sync.Mutex
+	w     io.Writer
+	line  string
+	drawn time.Time
+}
+
+// ShowProgress tells the logger to show progress updates on stderr, which is
+// only done if stderr is a terminal and logs are not being written as JSON.
+func (c *CmdLogger) ShowProgress() {
+	f, ok := c.stderr.(*os.File)
+
+	if !ok || !term.IsTerminal(int(f.Fd())) || c.json != nil {
+		return
+	}
+
+	c.progress = &progress{w: c.stderr}
+}
+
+// Progress updates the progress line with the given message, if the logger is
+// showing progress.
+func (c *CmdLogger) Progress(msg string) {
+	if c.progress == nil {
+		return
+	}
+
+	c.progress.mu.Lock()
+	defer c.progress.mu.Unlock()
+
+	c.progress.line = msg
+
+	if time.Since(c.progress.drawn) >= progressInterval {
+		c.progress.draw()
+	}
+}
+
+// ClearProgress removes the progress line, if the logger is showing progress.
+func (c *CmdLogger) ClearProgress() {
+	if c.progress == nil {
+		return
+	}
+
+	c.progress.mu.Lock()
+	defer c.progress.mu.Unlock()
+
+	c.progress.erase()
+	c.progress.line = ""
+}
+
+func (p *progress) draw() {
+	if p.line == "" {
+		return
+	}
+
+	_, _ = fmt.Fprint(p.w, clearLine+p.line)
+	p.drawn = time.Now()
+}
+
+func (p *progress) erase() {
+	if p.line == "" {
+		return
+	}
+
+	_, _ = fmt.Fprint(p.w, clearLine)
+}
+
+// pause erases the progress line so that a log can be written, which must be
+// followed by a call to resume once the log has been written
+func (p *progress) pause() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	p.erase()
+}
+
+// resume redraws the progress line after a log has been written
+func (p *progress) resume() {
+	if p == nil {
+		return
+	}
+
+	p.draw()
+	p.mu.Unlock()
+}
@@ -0,0 +1,59 @@
+package cmdlogger
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func TestCmdLogger_ShowProgress_NotATerminal(t *testing.T) {
+	t.Parallel()
+
+	stderr := &bytes.Buffer{}
+	logger := New(&bytes.Buffer{}, stderr)
+
+	logger.ShowProgress()
+	logger.Progress("scanning")
+	logger.ClearProgress()
+
+	if logger.progress != nil {
+		t.Errorf("expected progress to not be shown when stderr is not a terminal")
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("expected nothing to be written to stderr, got %q", stderr.String())
+	}
+}
+
+func TestCmdLogger_Progress(t *testing.T) {
+	t.Parallel()
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	logger := New(stdout, stderr)
+	logger.progress = &progress{w: stderr}
+
+	log := slog.New(&logger)
+
+	logger.Progress("scanned 1 file")
+	log.Info("found a lockfile")
+	log.Error("something went wrong")
+
+	// updates are only drawn periodically, but are still redrawn after each log
+	logger.Progress("scanned 2 files")
+	log.Info("found another lockfile")
+	logger.ClearProgress()
+
+	want := clearLine + "scanned 1 file" +
+		clearLine + clearLine + "scanned 1 file" +
+		clearLine + "something went wrong\n" + clearLine + "scanned 1 file" +
+		clearLine + clearLine + "scanned 2 files" +
+		clearLine
+
+	if got := stderr.String(); got != want {
+		t.Errorf("stderr = %q, want %q", got, want)
+	}
+
+	if got := stdout.String(); got != "found a lockfile\nfound another lockfile\n" {
+		t.Errorf("stdout = %q, want %q", got, "found a lockfile\nfound another lockfile\n")
+	}
+}
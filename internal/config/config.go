@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
@@ -11,7 +12,12 @@ import (
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/google/osv-scalibr/semantic"
 	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/utility/cpe"
+	"github.com/google/osv-scanner/v2/internal/utility/purl"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/package-url/packageurl-go"
 )
 
 const osvScannerConfigName = "osv-scanner.toml"
@@ -19,6 +25,8 @@ const osvScannerConfigName = "osv-scanner.toml"
 type Manager struct {
 	// Override to replace all other configs
 	OverrideConfig *Config
+	// Base config to merge into every other config, such as one shared across repositories
+	BaseConfig *Config
 	// Config to use if no config file is found alongside manifests
 	DefaultConfig Config
 	// Cache to store loaded configs
@@ -29,15 +37,204 @@ type Config struct {
 	IgnoredVulns      []IgnoreEntry          `toml:"IgnoredVulns"`
 	PackageOverrides  []PackageOverrideEntry `toml:"PackageOverrides"`
 	GoVersionOverride string                 `toml:"GoVersionOverride"`
+	// OSVAPI configures the OSV API that is queried, which is only supported
+	// in the config file passed with --config as it applies to the whole scan
+	OSVAPI OSVAPIConfig `toml:"OSVAPI"`
 	// The path to config file that this config was loaded from,
 	// set by the scanner after having successfully parsed the file
 	LoadPath string `toml:"-"`
 }
 
+type OSVAPIConfig struct {
+	// BaseURL of a self-hosted OSV API to query instead of api.osv.dev
+	BaseURL string `toml:"baseURL"`
+	// Headers to send with each request, such as for authentication
+	Headers map[string]string `toml:"headers"`
+}
+
 type IgnoreEntry struct {
 	ID          string    `toml:"id"`
 	IgnoreUntil time.Time `toml:"ignoreUntil"`
 	Reason      string    `toml:"reason"`
+	// Package optionally limits the ignore to the vulnerability in specific packages,
+	// with it applying to every package that is affected if empty.
+	Package IgnorePackageScope `toml:"package"`
+	// State and Justification are the VEX analysis of the ignored vulnerability, which are
+	// reported by the CycloneDX output, e.g. state = "not_affected", justification = "code_not_reachable"
+	State         string `toml:"state"`
+	Justification string `toml:"justification"`
+}
+
+// vexStates maps the VEX states that an ignore can have to the CycloneDX analysis state they are,
+// which are the states of CycloneDX along with the equivalent statuses of OpenVEX
+var vexStates = map[string]string{
+	"not_affected":           "not_affected",
+	"false_positive":         "false_positive",
+	"in_triage":              "in_triage",
+	"exploitable":            "exploitable",
+	"resolved":               "resolved",
+	"resolved_with_pedigree": "resolved_with_pedigree",
+	"under_investigation":    "in_triage",
+	"affected":               "exploitable",
+	"fixed":                  "resolved",
+}
+
+// vexJustifications are the CycloneDX justifications of why a vulnerability does not affect a package
+var vexJustifications = []string{
+	"code_not_present",
+	"code_not_reachable",
+	"requires_configuration",
+	"requires_dependency",
+	"requires_environment",
+	"protected_by_compiler",
+	"protected_at_runtime",
+	"protected_at_perimeter",
+	"protected_by_mitigating_control",
+}
+
+// VEXState returns the CycloneDX analysis state of the ignored vulnerability, which defaults to
+// not_affected if the ignore only has a justification, or otherwise in_triage as the vulnerability
+// has been ignored without saying why it does not affect the package
+func (e IgnoreEntry) VEXState() string {
+	if state, ok := vexStates[e.State]; ok {
+		return state
+	}
+
+	if e.Justification != "" {
+		return "not_affected"
+	}
+
+	return "in_triage"
+}
+
+// IgnorePackageScope limits an ignore to the packages that match all of its set fields
+type IgnorePackageScope struct {
+	Name      string `toml:"name"`
+	Ecosystem string `toml:"ecosystem"`
+	// Versions is a constraint on the version of the package such as "< 1.2.3",
+	// with multiple comparisons being separated by commas, e.g. ">= 1.0.0, < 1.2.3"
+	Versions string `toml:"versions"`
+	// PURL identifies the component by its package URL, matching every version
+	// of the component if the version is left out, e.g. "pkg:conan/openssl"
+	PURL string `toml:"purl"`
+	// CPE identifies the component by one of the CPEs it has in the SBOM it was in,
+	// with attributes that are ANY matching every value, e.g. "cpe:2.3:a:openssl:openssl:1.1.1:*:*:*:*:*:*:*"
+	CPE string `toml:"cpe"`
+}
+
+func (s IgnorePackageScope) matches(pkgVulns models.PackageVulns) bool {
+	pkg := pkgVulns.Package
+
+	if s.Name != "" && s.Name != pkg.Name {
+		return false
+	}
+	// Like with package overrides, the ecosystem can either be
+	// the full ecosystem with its suffix, or just the base ecosystem
+	baseEcosystem, _, _ := strings.Cut(pkg.Ecosystem, ":")
+	if s.Ecosystem != "" && s.Ecosystem != pkg.Ecosystem && s.Ecosystem != baseEcosystem {
+		return false
+	}
+	if s.Versions != "" && !versionSatisfies(pkg.Version, baseEcosystem, s.Versions) {
+		return false
+	}
+	if s.PURL != "" && !purlMatches(s.PURL, pkg) {
+		return false
+	}
+	if s.CPE != "" && !slices.ContainsFunc(pkgVulns.CPEs, func(c string) bool { return cpe.Matches(s.CPE, c) }) {
+		return false
+	}
+
+	return true
+}
+
+// purlMatches checks if the package URL identifies the package, ignoring any qualifiers
+// and matching every version of the package if the package URL does not have one
+func purlMatches(pattern string, pkg models.PackageInfo) bool {
+	want, err := packageurl.FromString(pattern)
+	if err != nil {
+		return false
+	}
+
+	return purl.Matches(want, pkg)
+}
+
+// versionComparison is a single comparison within a version constraint, such as "< 1.2.3"
+type versionComparison struct {
+	operator string
+	version  string
+}
+
+var versionOperators = []string{">=", "<=", "!=", "==", ">", "<", "="}
+
+func parseVersionConstraint(constraint string) ([]versionComparison, error) {
+	var comparisons []versionComparison
+
+	for _, part := range strings.Split(constraint, ",") {
+		part = strings.TrimSpace(part)
+		comparison := versionComparison{operator: "="}
+
+		for _, op := range versionOperators {
+			if strings.HasPrefix(part, op) {
+				comparison.operator = op
+				part = strings.TrimSpace(strings.TrimPrefix(part, op))
+
+				break
+			}
+		}
+
+		if part == "" {
+			return nil, fmt.Errorf("invalid version constraint %q: comparison is missing a version", constraint)
+		}
+
+		comparison.version = part
+		comparisons = append(comparisons, comparison)
+	}
+
+	return comparisons, nil
+}
+
+// versionSatisfies checks if the version meets every comparison of the constraint,
+// treating versions that cannot be compared as not meeting it so that the
+// vulnerability is still reported
+func versionSatisfies(version, ecosystem, constraint string) bool {
+	comparisons, err := parseVersionConstraint(constraint)
+	if err != nil {
+		return false
+	}
+
+	v, err := semantic.Parse(version, ecosystem)
+	if err != nil {
+		return false
+	}
+
+	for _, comparison := range comparisons {
+		cmp, err := v.CompareStr(comparison.version)
+		if err != nil {
+			return false
+		}
+
+		var ok bool
+		switch comparison.operator {
+		case ">=":
+			ok = cmp >= 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case "<":
+			ok = cmp < 0
+		case "!=":
+			ok = cmp != 0
+		default:
+			ok = cmp == 0
+		}
+
+		if !ok {
+			return false
+		}
+	}
+
+	return true
 }
 
 type PackageOverrideEntry struct {
@@ -82,8 +279,11 @@ type License struct {
 	Ignore   bool     `toml:"ignore"`
 }
 
-func (c *Config) ShouldIgnore(vulnID string) (bool, IgnoreEntry) {
-	index := slices.IndexFunc(c.IgnoredVulns, func(e IgnoreEntry) bool { return e.ID == vulnID })
+// ShouldIgnore determines if the given vulnerability should be ignored for the given package
+func (c *Config) ShouldIgnore(vulnID string, pkg models.PackageVulns) (bool, IgnoreEntry) {
+	index := slices.IndexFunc(c.IgnoredVulns, func(e IgnoreEntry) bool {
+		return e.ID == vulnID && e.Package.matches(pkg)
+	})
 	if index == -1 {
 		return false, IgnoreEntry{}
 	}
@@ -149,6 +349,38 @@ func (c *Manager) UseOverride(configPath string) error {
 	return nil
 }
 
+// UseBase updates the Manager to merge the config at the given path into the
+// config files that are loaded when calling Get, including using it on its own
+// when there is no config file
+func (c *Manager) UseBase(configPath string) error {
+	config, configErr := tryLoadConfig(configPath)
+	if configErr != nil {
+		return configErr
+	}
+	c.BaseConfig = &config
+
+	return nil
+}
+
+// AddIgnores updates the Manager to also ignore the given vulnerabilities in every config
+// that is returned by Get, in addition to those ignored by the config files themselves
+func (c *Manager) AddIgnores(entries ...IgnoreEntry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	if c.OverrideConfig != nil {
+		c.OverrideConfig.IgnoredVulns = append(c.OverrideConfig.IgnoredVulns, entries...)
+
+		return
+	}
+
+	if c.BaseConfig == nil {
+		c.BaseConfig = &Config{}
+	}
+	c.BaseConfig.IgnoredVulns = append(c.BaseConfig.IgnoredVulns, entries...)
+}
+
 // Get returns the appropriate config to use based on the targetPath
 func (c *Manager) Get(targetPath string) Config {
 	if c.OverrideConfig != nil {
@@ -160,6 +392,10 @@ func (c *Manager) Get(targetPath string) Config {
 		// TODO: This can happen when target is not a file (e.g. Docker container, git hash...etc.)
 		// Figure out a more robust way to load config from non files
 		// r.PrintErrorf("Can't find config path: %s\n", err)
+		if c.BaseConfig != nil {
+			return *c.BaseConfig
+		}
+
 		return Config{}
 	}
 
@@ -171,19 +407,62 @@ func (c *Manager) Get(targetPath string) Config {
 	config, configErr := tryLoadConfig(configPath)
 	if configErr == nil {
 		slog.Info("Loaded filter from: " + config.LoadPath)
+
+		if c.BaseConfig != nil {
+			config = config.mergeBase(*c.BaseConfig)
+		}
 	} else {
 		// anything other than the config file not existing is most likely due to an invalid config file
 		if !errors.Is(configErr, os.ErrNotExist) {
 			slog.Error(fmt.Sprintf("Ignored invalid config file at %s because: %v", configPath, configErr))
 		}
-		// If config doesn't exist, use the default config
-		config = c.DefaultConfig
+		// If config doesn't exist, use the base config or otherwise the default config
+		if c.BaseConfig != nil {
+			config = *c.BaseConfig
+		} else {
+			config = c.DefaultConfig
+		}
 	}
 	c.ConfigMap[configPath] = config
 
 	return config
 }
 
+// mergeBase returns this config merged with the base config, with this config
+// taking precedence:
+//   - ignores and package overrides of both configs are used, with those from this
+//     config being matched first. Ignores in the base config for the same vulnerability
+//     and packages as an ignore in this config are dropped
+//   - GoVersionOverride of the base config is only used if it is not set in this config
+//
+// OSVAPI is not merged, as it is only read from the config that applies to the whole scan.
+func (c Config) mergeBase(base Config) Config {
+	type ignoreKey struct {
+		id    string
+		scope IgnorePackageScope
+	}
+	seen := make(map[ignoreKey]struct{}, len(c.IgnoredVulns))
+	for _, vuln := range c.IgnoredVulns {
+		seen[ignoreKey{id: vuln.ID, scope: vuln.Package}] = struct{}{}
+	}
+
+	merged := c
+	merged.IgnoredVulns = slices.Clone(c.IgnoredVulns)
+	for _, vuln := range base.IgnoredVulns {
+		if _, ok := seen[ignoreKey{id: vuln.ID, scope: vuln.Package}]; !ok {
+			merged.IgnoredVulns = append(merged.IgnoredVulns, vuln)
+		}
+	}
+
+	merged.PackageOverrides = slices.Concat(c.PackageOverrides, base.PackageOverrides)
+
+	if merged.GoVersionOverride == "" {
+		merged.GoVersionOverride = base.GoVersionOverride
+	}
+
+	return merged
+}
+
 // Finds the containing folder of `target`, then appends osvScannerConfigName
 func normalizeConfigLoadPath(target string) (string, error) {
 	stat, err := os.Stat(target)
@@ -220,20 +499,77 @@ func tryLoadConfig(configPath string) (Config, error) {
 			return Config{}, fmt.Errorf("unknown keys in config file: %s", strings.Join(keys, ", "))
 		}
 
+		if err := config.validateIgnores(); err != nil {
+			return Config{}, err
+		}
+
 		config.LoadPath = configPath
 		config.warnAboutDuplicates()
+		config.warnAboutExpiredIgnores()
 	}
 
 	return config, err
 }
 
+// expiredIgnores returns the ignore entries that are no longer applied
+// because their ignoreUntil date has passed
+func (c *Config) expiredIgnores() []IgnoreEntry {
+	var expired []IgnoreEntry
+
+	for _, vuln := range c.IgnoredVulns {
+		if !shouldIgnoreTimestamp(vuln.IgnoreUntil) {
+			expired = append(expired, vuln)
+		}
+	}
+
+	return expired
+}
+
+func (c *Config) warnAboutExpiredIgnores() {
+	for _, vuln := range c.expiredIgnores() {
+		slog.Warn(fmt.Sprintf("warning: %s has an ignore for %s that expired on %s - it will no longer be ignored, so consider removing it", c.LoadPath, vuln.ID, vuln.IgnoreUntil.Format(time.DateOnly)))
+	}
+}
+
 func (c *Config) warnAboutDuplicates() {
-	seen := make(map[string]struct{})
+	// ignores for the same vulnerability are only duplicates if they are scoped to the same packages
+	type ignoreKey struct {
+		id    string
+		scope IgnorePackageScope
+	}
+	seen := make(map[ignoreKey]struct{})
 
 	for _, vuln := range c.IgnoredVulns {
-		if _, ok := seen[vuln.ID]; ok {
+		key := ignoreKey{id: vuln.ID, scope: vuln.Package}
+		if _, ok := seen[key]; ok {
 			slog.Warn(fmt.Sprintf("warning: %s has multiple ignores for %s - only the first will be used!", c.LoadPath, vuln.ID))
 		}
-		seen[vuln.ID] = struct{}{}
+		seen[key] = struct{}{}
+	}
+}
+
+func (c *Config) validateIgnores() error {
+	for _, vuln := range c.IgnoredVulns {
+		if _, ok := vexStates[vuln.State]; vuln.State != "" && !ok {
+			return fmt.Errorf("ignore for %s: invalid state %q - must be one of: %s", vuln.ID, vuln.State, strings.Join(slices.Sorted(maps.Keys(vexStates)), ", "))
+		}
+
+		if vuln.Justification != "" && !slices.Contains(vexJustifications, vuln.Justification) {
+			return fmt.Errorf("ignore for %s: invalid justification %q - must be one of: %s", vuln.ID, vuln.Justification, strings.Join(vexJustifications, ", "))
+		}
+
+		if vuln.Justification != "" && vuln.VEXState() != "not_affected" {
+			return fmt.Errorf("ignore for %s: a justification can only be given for vulnerabilities that are not_affected", vuln.ID)
+		}
+
+		if vuln.Package.Versions == "" {
+			continue
+		}
+
+		if _, err := parseVersionConstraint(vuln.Package.Versions); err != nil {
+			return fmt.Errorf("ignore for %s: %w", vuln.ID, err)
+		}
 	}
+
+	return nil
 }
@@ -2,17 +2,21 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/google/osv-scalibr/extractor"
 	"github.com/google/osv-scalibr/extractor/filesystem/osv"
 
 	"github.com/google/osv-scanner/v2/internal/imodels"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/ecosystemmock"
+	"github.com/google/osv-scanner/v2/pkg/models"
 )
 
 // Attempts to normalize any file paths in the given `output` so that they can
@@ -244,11 +248,31 @@ func TestTryLoadConfig_UnknownKeys(t *testing.T) {
 	}
 }
 
+func TestTryLoadConfig_InvalidVersionConstraint(t *testing.T) {
+	t.Parallel()
+
+	c, err := tryLoadConfig("./fixtures/invalid-ignore-versions.toml")
+
+	if diff := cmp.Diff(Config{}, c); diff != "" {
+		t.Errorf("tryLoadConfig() mismatch (-want +got):\n%s", diff)
+	}
+	if err == nil {
+		t.Fatal("tryLoadConfig() did not return an error")
+	}
+
+	wantMsg := `ignore for GHSA-123: invalid version constraint ">= 1.0.0, <": comparison is missing a version`
+	if err.Error() != wantMsg {
+		t.Errorf("tryLoadConfig() error = '%v', want '%s'", err, wantMsg)
+	}
+}
+
 func TestConfig_ShouldIgnore(t *testing.T) {
 	t.Parallel()
 
 	type args struct {
 		vulnID string
+		pkg    models.PackageInfo
+		cpes   []string
 	}
 	tests := []struct {
 		name      string
@@ -341,13 +365,239 @@ func TestConfig_ShouldIgnore(t *testing.T) {
 				Reason:      "",
 			},
 		},
+		// entry is scoped to a different package
+		{
+			name: "",
+			config: Config{
+				IgnoredVulns: []IgnoreEntry{
+					{
+						ID:      "GHSA-123",
+						Package: IgnorePackageScope{Name: "lodash", Ecosystem: "npm"},
+					},
+				},
+			},
+			args: args{
+				vulnID: "GHSA-123",
+				pkg:    models.PackageInfo{Name: "underscore", Version: "1.0.0", Ecosystem: "npm"},
+			},
+			wantOk:    false,
+			wantEntry: IgnoreEntry{},
+		},
+		// entry is scoped to the base ecosystem of the package
+		{
+			name: "",
+			config: Config{
+				IgnoredVulns: []IgnoreEntry{
+					{
+						ID:      "DSA-123",
+						Package: IgnorePackageScope{Name: "openssl", Ecosystem: "Debian"},
+					},
+				},
+			},
+			args: args{
+				vulnID: "DSA-123",
+				pkg:    models.PackageInfo{Name: "openssl", Version: "3.0.11-1", Ecosystem: "Debian:12"},
+			},
+			wantOk: true,
+			wantEntry: IgnoreEntry{
+				ID:      "DSA-123",
+				Package: IgnorePackageScope{Name: "openssl", Ecosystem: "Debian"},
+			},
+		},
+		// package version is within the constraint
+		{
+			name: "",
+			config: Config{
+				IgnoredVulns: []IgnoreEntry{
+					{
+						ID:      "GHSA-123",
+						Package: IgnorePackageScope{Name: "lodash", Versions: ">= 4.0.0, < 4.17.21"},
+					},
+				},
+			},
+			args: args{
+				vulnID: "GHSA-123",
+				pkg:    models.PackageInfo{Name: "lodash", Version: "4.17.4", Ecosystem: "npm"},
+			},
+			wantOk: true,
+			wantEntry: IgnoreEntry{
+				ID:      "GHSA-123",
+				Package: IgnorePackageScope{Name: "lodash", Versions: ">= 4.0.0, < 4.17.21"},
+			},
+		},
+		// package version is outside the constraint
+		{
+			name: "",
+			config: Config{
+				IgnoredVulns: []IgnoreEntry{
+					{
+						ID:      "GHSA-123",
+						Package: IgnorePackageScope{Name: "lodash", Versions: ">= 4.0.0, < 4.17.21"},
+					},
+				},
+			},
+			args: args{
+				vulnID: "GHSA-123",
+				pkg:    models.PackageInfo{Name: "lodash", Version: "3.10.1", Ecosystem: "npm"},
+			},
+			wantOk:    false,
+			wantEntry: IgnoreEntry{},
+		},
+		// package version is an exact match
+		{
+			name: "",
+			config: Config{
+				IgnoredVulns: []IgnoreEntry{
+					{
+						ID:      "GHSA-123",
+						Package: IgnorePackageScope{Versions: "1.2.3"},
+					},
+				},
+			},
+			args: args{
+				vulnID: "GHSA-123",
+				pkg:    models.PackageInfo{Name: "lib", Version: "1.2.3", Ecosystem: "Go"},
+			},
+			wantOk: true,
+			wantEntry: IgnoreEntry{
+				ID:      "GHSA-123",
+				Package: IgnorePackageScope{Versions: "1.2.3"},
+			},
+		},
+		// package version cannot be parsed, so the vulnerability is still reported
+		{
+			name: "",
+			config: Config{
+				IgnoredVulns: []IgnoreEntry{
+					{
+						ID:      "GHSA-123",
+						Package: IgnorePackageScope{Versions: "< 2.0.0"},
+					},
+				},
+			},
+			args: args{
+				vulnID: "GHSA-123",
+				pkg:    models.PackageInfo{Name: "lib", Version: "1.0.0", Ecosystem: "NotAnEcosystem"},
+			},
+			wantOk:    false,
+			wantEntry: IgnoreEntry{},
+		},
+		// first entry that matches the package is used
+		{
+			name: "",
+			config: Config{
+				IgnoredVulns: []IgnoreEntry{
+					{
+						ID:      "GHSA-123",
+						Reason:  "only used in tests",
+						Package: IgnorePackageScope{Name: "mocha"},
+					},
+					{
+						ID:      "GHSA-123",
+						Reason:  "not reachable",
+						Package: IgnorePackageScope{Name: "lodash"},
+					},
+				},
+			},
+			args: args{
+				vulnID: "GHSA-123",
+				pkg:    models.PackageInfo{Name: "lodash", Version: "4.17.4", Ecosystem: "npm"},
+			},
+			wantOk: true,
+			wantEntry: IgnoreEntry{
+				ID:      "GHSA-123",
+				Reason:  "not reachable",
+				Package: IgnorePackageScope{Name: "lodash"},
+			},
+		},
+		// package url without a version matches every version of the package
+		{
+			name: "",
+			config: Config{
+				IgnoredVulns: []IgnoreEntry{
+					{
+						ID:      "CVE-2024-0727",
+						Package: IgnorePackageScope{PURL: "pkg:conan/openssl"},
+					},
+				},
+			},
+			args: args{
+				vulnID: "CVE-2024-0727",
+				pkg:    models.PackageInfo{Name: "openssl", Version: "3.0.12", Ecosystem: "ConanCenter"},
+			},
+			wantOk: true,
+			wantEntry: IgnoreEntry{
+				ID:      "CVE-2024-0727",
+				Package: IgnorePackageScope{PURL: "pkg:conan/openssl"},
+			},
+		},
+		// package url with a version only matches that version
+		{
+			name: "",
+			config: Config{
+				IgnoredVulns: []IgnoreEntry{
+					{
+						ID:      "CVE-2024-0727",
+						Package: IgnorePackageScope{PURL: "pkg:conan/openssl@3.0.13"},
+					},
+				},
+			},
+			args: args{
+				vulnID: "CVE-2024-0727",
+				pkg:    models.PackageInfo{Name: "openssl", Version: "3.0.12", Ecosystem: "ConanCenter"},
+			},
+			wantOk:    false,
+			wantEntry: IgnoreEntry{},
+		},
+		// cpe matches one of the cpes of the package, after normalizing the version
+		{
+			name: "",
+			config: Config{
+				IgnoredVulns: []IgnoreEntry{
+					{
+						ID:      "CVE-2024-0727",
+						Package: IgnorePackageScope{CPE: "cpe:2.3:a:openssl:openssl:v3.0.0:*:*:*:*:*:*:*"},
+					},
+				},
+			},
+			args: args{
+				vulnID: "CVE-2024-0727",
+				pkg:    models.PackageInfo{Name: "openssl", Version: "3.0", Ecosystem: "ConanCenter"},
+				cpes:   []string{"cpe:/a:example:openssl-fork:3.0", "cpe:2.3:a:OpenSSL:openssl:3.0:*:*:*:*:*:*:*"},
+			},
+			wantOk: true,
+			wantEntry: IgnoreEntry{
+				ID:      "CVE-2024-0727",
+				Package: IgnorePackageScope{CPE: "cpe:2.3:a:openssl:openssl:v3.0.0:*:*:*:*:*:*:*"},
+			},
+		},
+		// cpe does not match packages without it, even if they have the same name
+		{
+			name: "",
+			config: Config{
+				IgnoredVulns: []IgnoreEntry{
+					{
+						ID:      "CVE-2024-0727",
+						Package: IgnorePackageScope{CPE: "cpe:2.3:a:openssl:openssl:3.0:*:*:*:*:*:*:*"},
+					},
+				},
+			},
+			args: args{
+				vulnID: "CVE-2024-0727",
+				pkg:    models.PackageInfo{Name: "openssl", Version: "3.0", Ecosystem: "ConanCenter"},
+				cpes:   []string{"cpe:2.3:a:example:openssl-fork:3.0:*:*:*:*:*:*:*"},
+			},
+			wantOk:    false,
+			wantEntry: IgnoreEntry{},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			gotOk, gotEntry := tt.config.ShouldIgnore(tt.args.vulnID)
+			pkg := models.PackageVulns{Package: tt.args.pkg, CPEs: tt.args.cpes}
+			gotOk, gotEntry := tt.config.ShouldIgnore(tt.args.vulnID, pkg)
 			if gotOk != tt.wantOk {
 				t.Errorf("ShouldIgnore() gotOk = %v, wantOk %v", gotOk, tt.wantOk)
 			}
@@ -358,6 +608,112 @@ func TestConfig_ShouldIgnore(t *testing.T) {
 	}
 }
 
+func TestTryLoadConfig_InvalidVEXAnalysis(t *testing.T) {
+	t.Parallel()
+
+	c, err := tryLoadConfig("./fixtures/invalid-ignore-vex.toml")
+
+	if diff := cmp.Diff(Config{}, c); diff != "" {
+		t.Errorf("tryLoadConfig() mismatch (-want +got):\n%s", diff)
+	}
+	if err == nil {
+		t.Fatal("tryLoadConfig() did not return an error")
+	}
+
+	wantMsg := "ignore for GHSA-123: a justification can only be given for vulnerabilities that are not_affected"
+	if err.Error() != wantMsg {
+		t.Errorf("tryLoadConfig() error = '%v', want '%s'", err, wantMsg)
+	}
+}
+
+func TestConfig_validateIgnores_VEXAnalysis(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		entry   IgnoreEntry
+		wantErr bool
+	}{
+		{
+			name:  "no analysis",
+			entry: IgnoreEntry{ID: "GHSA-1"},
+		},
+		{
+			name:  "cyclonedx state with a justification",
+			entry: IgnoreEntry{ID: "GHSA-1", State: "not_affected", Justification: "code_not_present"},
+		},
+		{
+			name:  "openvex status",
+			entry: IgnoreEntry{ID: "GHSA-1", State: "under_investigation"},
+		},
+		{
+			name:  "only a justification",
+			entry: IgnoreEntry{ID: "GHSA-1", Justification: "requires_environment"},
+		},
+		{
+			name:    "unknown state",
+			entry:   IgnoreEntry{ID: "GHSA-1", State: "ignored"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown justification",
+			entry:   IgnoreEntry{ID: "GHSA-1", State: "not_affected", Justification: "not_used"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := Config{IgnoredVulns: []IgnoreEntry{tt.entry}}
+			if err := config.validateIgnores(); (err != nil) != tt.wantErr {
+				t.Errorf("validateIgnores() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIgnoreEntry_VEXState(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		entry IgnoreEntry
+		want  string
+	}{
+		{IgnoreEntry{}, "in_triage"},
+		{IgnoreEntry{Justification: "code_not_reachable"}, "not_affected"},
+		{IgnoreEntry{State: "false_positive"}, "false_positive"},
+		{IgnoreEntry{State: "under_investigation"}, "in_triage"},
+		{IgnoreEntry{State: "affected"}, "exploitable"},
+		{IgnoreEntry{State: "fixed"}, "resolved"},
+	}
+	for _, tt := range tests {
+		if got := tt.entry.VEXState(); got != tt.want {
+			t.Errorf("%+v.VEXState() = %q, want %q", tt.entry, got, tt.want)
+		}
+	}
+}
+
+func TestConfig_expiredIgnores(t *testing.T) {
+	t.Parallel()
+
+	past := time.Now().Add(-time.Hour).Round(time.Second)
+
+	config := Config{
+		IgnoredVulns: []IgnoreEntry{
+			{ID: "GHSA-1", IgnoreUntil: time.Time{}},
+			{ID: "GHSA-2", IgnoreUntil: past, Reason: "snoozed"},
+			{ID: "GHSA-3", IgnoreUntil: time.Now().Add(time.Hour).Round(time.Second)},
+		},
+	}
+
+	want := []IgnoreEntry{{ID: "GHSA-2", IgnoreUntil: past, Reason: "snoozed"}}
+
+	if got := config.expiredIgnores(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expiredIgnores() = %v, want %v", got, want)
+	}
+}
+
 func TestConfig_ShouldIgnorePackage(t *testing.T) {
 	t.Parallel()
 
@@ -1242,3 +1598,115 @@ func TestConfig_ShouldOverridePackageLicense(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_mergeBase(t *testing.T) {
+	t.Parallel()
+
+	base := Config{
+		IgnoredVulns: []IgnoreEntry{
+			{ID: "GHSA-1", Reason: "from base"},
+			{ID: "GHSA-2", Reason: "from base"},
+			{ID: "GHSA-2", Reason: "from base, for lib1", Package: IgnorePackageScope{Name: "lib1"}},
+		},
+		PackageOverrides: []PackageOverrideEntry{
+			{Name: "lib1", Ignore: true, Reason: "from base"},
+		},
+		GoVersionOverride: "1.20.0",
+		LoadPath:          "/shared/osv-scanner.toml",
+	}
+
+	local := Config{
+		IgnoredVulns: []IgnoreEntry{
+			{ID: "GHSA-2", Reason: "from local"},
+			{ID: "GHSA-3", Reason: "from local"},
+		},
+		PackageOverrides: []PackageOverrideEntry{
+			{Name: "lib1", License: License{Override: []string{"MIT"}}, Reason: "from local"},
+		},
+		GoVersionOverride: "1.21.0",
+		LoadPath:          "/repo/osv-scanner.toml",
+	}
+
+	want := Config{
+		IgnoredVulns: []IgnoreEntry{
+			{ID: "GHSA-2", Reason: "from local"},
+			{ID: "GHSA-3", Reason: "from local"},
+			{ID: "GHSA-1", Reason: "from base"},
+			{ID: "GHSA-2", Reason: "from base, for lib1", Package: IgnorePackageScope{Name: "lib1"}},
+		},
+		PackageOverrides: []PackageOverrideEntry{
+			{Name: "lib1", License: License{Override: []string{"MIT"}}, Reason: "from local"},
+			{Name: "lib1", Ignore: true, Reason: "from base"},
+		},
+		GoVersionOverride: "1.21.0",
+		LoadPath:          "/repo/osv-scanner.toml",
+	}
+
+	if diff := cmp.Diff(want, local.mergeBase(base)); diff != "" {
+		t.Errorf("mergeBase() mismatch (-want +got):\n%s", diff)
+	}
+
+	// neither config should have been modified
+	if len(local.IgnoredVulns) != 2 || len(base.IgnoredVulns) != 3 {
+		t.Errorf("mergeBase() modified the configs being merged")
+	}
+
+	// the base config is used as is when there is nothing to merge it with
+	if diff := cmp.Diff(base, Config{}.mergeBase(base), cmpopts.IgnoreFields(Config{}, "LoadPath")); diff != "" {
+		t.Errorf("mergeBase() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestManager_Get_BaseConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	writeConfig := func(path string, content string) {
+		t.Helper()
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeConfig(filepath.Join(dir, "base.toml"), "[[IgnoredVulns]]\nid = \"GHSA-base\"\n")
+	writeConfig(filepath.Join(dir, "repo", "osv-scanner.toml"), "[[IgnoredVulns]]\nid = \"GHSA-local\"\n")
+	if err := os.MkdirAll(filepath.Join(dir, "other"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := Manager{ConfigMap: make(map[string]Config)}
+	if err := manager.UseBase(filepath.Join(dir, "base.toml")); err != nil {
+		t.Fatalf("UseBase() error = %v", err)
+	}
+
+	ids := func(c Config) []string {
+		var ids []string
+		for _, vuln := range c.IgnoredVulns {
+			ids = append(ids, vuln.ID)
+		}
+
+		return ids
+	}
+
+	if diff := cmp.Diff([]string{"GHSA-local", "GHSA-base"}, ids(manager.Get(filepath.Join(dir, "repo")))); diff != "" {
+		t.Errorf("Get() with a config file mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"GHSA-base"}, ids(manager.Get(filepath.Join(dir, "other")))); diff != "" {
+		t.Errorf("Get() without a config file mismatch (-want +got):\n%s", diff)
+	}
+
+	// an override replaces every other config, including the base config
+	if err := manager.UseOverride(filepath.Join(dir, "repo", "osv-scanner.toml")); err != nil {
+		t.Fatalf("UseOverride() error = %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"GHSA-local"}, ids(manager.Get(filepath.Join(dir, "other")))); diff != "" {
+		t.Errorf("Get() with an override mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -13,12 +13,21 @@ import (
 )
 
 const (
-	commentPrefix   = "#"
-	gitDir          = ".git"
-	gitignoreFile   = ".gitignore"
-	infoExcludeFile = gitDir + "/info/exclude"
+	commentPrefix        = "#"
+	gitDir               = ".git"
+	gitignoreFile        = ".gitignore"
+	osvScannerIgnoreFile = ".osvscannerignore"
+	infoExcludeFile      = gitDir + "/info/exclude"
 )
 
+// repoIgnoreFiles are the ignore files that are read from each dir of a git repo,
+// in ascending order of priority, so .osvscannerignore patterns take precedence
+var repoIgnoreFiles = []string{gitignoreFile, osvScannerIgnoreFile}
+
+// plainDirIgnoreFiles are the ignore files that are read from dirs that are not
+// within a git repo, where .gitignore files are meaningless
+var plainDirIgnoreFiles = []string{osvScannerIgnoreFile}
+
 // readIgnoreFile reads a specific git ignore file.
 func readIgnoreFile(fs billy.Filesystem, path []string, ignoreFile string) (ps []gitignore.Pattern, err error) {
 	f, err := fs.Open(fs.Join(append(path, ignoreFile)...))
@@ -39,6 +48,21 @@ func readIgnoreFile(fs billy.Filesystem, path []string, ignoreFile string) (ps [
 	return
 }
 
+// readIgnoreFiles reads each of the given ignore files in a specific dir,
+// returning their patterns in the same order as the files and skipping those that do not exist.
+func readIgnoreFiles(fs billy.Filesystem, path []string, ignoreFiles []string) ([]gitignore.Pattern, error) {
+	var ps []gitignore.Pattern
+	for _, ignoreFile := range ignoreFiles {
+		newPs, err := readIgnoreFile(fs, path, ignoreFile)
+		if err != nil && !os.IsNotExist(err) {
+			return ps, err
+		}
+		ps = append(ps, newPs...)
+	}
+
+	return ps, nil
+}
+
 // ReadPatterns reads the .git/info/exclude and then the gitignore patterns
 // recursively traversing through the directory structure. The result is in
 // the ascending order of priority (last higher).
@@ -55,7 +79,11 @@ func ReadPatterns(fs billy.Filesystem, path []string) (ps []gitignore.Pattern, e
 //
 // Optionally a list of existing patterns to ignore can be supplied.
 func ReadPatternsIgnoringDirs(fs billy.Filesystem, path []string, accumulatedPs []gitignore.Pattern) (ps []gitignore.Pattern, err error) {
-	ps, err = readIgnoreFile(fs, path, gitignoreFile)
+	return readPatternsIgnoringDirs(fs, path, accumulatedPs, repoIgnoreFiles)
+}
+
+func readPatternsIgnoringDirs(fs billy.Filesystem, path []string, accumulatedPs []gitignore.Pattern, ignoreFiles []string) (ps []gitignore.Pattern, err error) {
+	ps, err = readIgnoreFiles(fs, path, ignoreFiles)
 	if err != nil {
 		return ps, err
 	}
@@ -75,7 +103,7 @@ func ReadPatternsIgnoringDirs(fs billy.Filesystem, path []string, accumulatedPs
 			childPath = append(childPath, fi.Name())
 			if !matcherForThisDir.Match(childPath, fi.IsDir()) {
 				var subps []gitignore.Pattern
-				subps, err = ReadPatternsIgnoringDirs(fs, childPath, accumulatedPs)
+				subps, err = readPatternsIgnoringDirs(fs, childPath, accumulatedPs, ignoreFiles)
 				if err != nil {
 					return ps, err
 				}
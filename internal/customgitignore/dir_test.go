@@ -627,6 +627,69 @@ func TestParsingGitRepoWithoutGitignoreFiles(t *testing.T) {
 	}
 }
 
+func TestParsingOsvScannerIgnoreFilesFromGitRepo(t *testing.T) {
+	t.Parallel()
+
+	gitRepo := setupGitRepo(t)
+
+	// context: dir_a has a .osvscannerignore file that adds a pattern,
+	// and negates a pattern from its .gitignore file
+	writeGitignore(t, gitRepo, filepath.FromSlash("dir_a/.osvscannerignore"), "DIR_A_OSVSCANNERIGNORE\n"+"!DIR_A_GITIGNORE")
+
+	patterns, _, err := customgitignore.ParseGitIgnores(gitRepo, true)
+	if err != nil {
+		t.Fatalf("could not read gitignore patterns for test: %v", err)
+	}
+
+	matcher := gitignore.NewMatcher(patterns)
+
+	// expect ./dir_a/.osvscannerignore to be processed
+	if !matcher.Match([]string{".", "dir_a", "DIR_A_OSVSCANNERIGNORE"}, false) {
+		t.Errorf("Expected dir_a/DIR_A_OSVSCANNERIGNORE to be ignored by ./dir_a/.osvscannerignore")
+	}
+
+	// expect the patterns of ./dir_a/.osvscannerignore to be relative to dir_a
+	if matcher.Match([]string{".", "parallel_a", "DIR_A_OSVSCANNERIGNORE"}, false) {
+		t.Errorf("Expected parallel_a/DIR_A_OSVSCANNERIGNORE not to be ignored by ./dir_a/.osvscannerignore")
+	}
+
+	// expect ./dir_a/.osvscannerignore to take precedence over ./dir_a/.gitignore
+	if matcher.Match([]string{".", "dir_a", "DIR_A_GITIGNORE"}, false) {
+		t.Errorf("Expected dir_a/DIR_A_GITIGNORE to be un-ignored by ./dir_a/.osvscannerignore")
+	}
+
+	// expect the other .gitignore files to still be processed
+	if !matcher.Match([]string{".", "ROOT_GITIGNORE"}, false) {
+		t.Errorf("Expected ROOT_GITIGNORE to be ignored by repository-root .gitignore")
+	}
+}
+
+func TestParsingOsvScannerIgnoreFileFromPlainDir(t *testing.T) {
+	t.Parallel()
+
+	plainDir := setupPlainDirWithGitignores(t)
+	writeGitignore(t, plainDir, ".osvscannerignore", "ROOT_OSVSCANNERIGNORE")
+
+	patterns, rootPath, err := customgitignore.ParseGitIgnores(plainDir, true)
+	if err != nil {
+		t.Fatalf("could not read gitignore patterns for test: %v", err)
+	}
+
+	wantRootPath, err := filepath.Abs(plainDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rootPath != wantRootPath {
+		t.Errorf("Expected patterns to be relative to %s, but got %s", wantRootPath, rootPath)
+	}
+
+	// expect only the .osvscannerignore file to be processed,
+	// because .gitignores are meaningless in a non git-repo
+	if len(patterns) != 1 || !hasPatternContaining(patterns, "ROOT_OSVSCANNERIGNORE") {
+		t.Errorf("Expected patterns to only contain ROOT_OSVSCANNERIGNORE from ./.osvscannerignore, got %v", patterns)
+	}
+}
+
 func setupGitRepo(t *testing.T) string {
 	t.Helper()
 
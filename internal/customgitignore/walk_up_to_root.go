@@ -19,16 +19,13 @@ import (
 //
 // Because this function finds the enclosing git repo, it
 // returns that as its second argument, which may be
-// `path`, or a parent of `path`, or "" if there's no
-// enclosing git repo.
+// `path`, or a parent of `path`, or `path` itself if there's
+// no enclosing git repo, allowing a caller to know what the
+// returned patterns are relative to.
 //
-// Because it detects the root of any enclosing git repo
-// if path doesn't exist the root, it returns this repoPath
-// or "" if there is none.
-//
-// It also returns the path to the root of the git-repo,
-// or "" if this path isn't within a git repo, allowing
-// a caller to know how .gitignore files were parsed.
+// Wherever a .gitignore file is read, a .osvscannerignore file
+// in the same dir is also read, with its patterns taking precedence
+// over those of the .gitignore file.
 //
 // The actual parsing is intended to be similar to how tools
 // like rg work, but means that `path` may not necessarily be
@@ -38,6 +35,7 @@ import (
 // `path` is a plain dir:
 //
 //   - .gitignore files are ignored
+//   - only read the .osvscannerignore file in this dir
 //
 // `path` is a file:
 //
@@ -90,10 +88,10 @@ func ParseGitIgnores(path string, recursive bool) ([]gitignore.Pattern, string,
 		return ps, "", err
 	}
 
-	// not in a git repo; do not read .gitignore files
-	// (and ignore recursive setting)
+	// not in a git repo; do not read .gitignore files,
+	// but still read any .osvscannerignore files
 	if errors.Is(err, git.ErrRepositoryNotExists) {
-		return ps, "", nil
+		return parsePlainDirIgnores(path)
 	}
 
 	// inside a git repo
@@ -136,10 +134,10 @@ func ParseGitIgnores(path string, recursive bool) ([]gitignore.Pattern, string,
 	// Read children's subdirs
 	//
 	if recursive {
-		newPs, err = ReadPatternsIgnoringDirs(fs, toGoGitPath(pathRel), ps)
+		newPs, err = readPatternsIgnoringDirs(fs, toGoGitPath(pathRel), ps, repoIgnoreFiles)
 	} else {
-		// only read single .gitignore file in this dir
-		newPs, err = readIgnoreFile(fs, toGoGitPath(pathRel), gitignoreFile)
+		// only read the ignore files in this dir
+		newPs, err = readIgnoreFiles(fs, toGoGitPath(pathRel), repoIgnoreFiles)
 	}
 
 	if err != nil && !os.IsNotExist(err) {
@@ -151,6 +149,25 @@ func ParseGitIgnores(path string, recursive bool) ([]gitignore.Pattern, string,
 	return ps, repoRootPath, nil
 }
 
+// parsePlainDirIgnores reads the .osvscannerignore file of a dir that is not
+// within a git repo, treating the dir as the root that patterns are relative to.
+//
+// Without a git repo there is no root to find the ignore files of parent dirs from,
+// so only the file in the dir itself is read, regardless of the recursive setting.
+func parsePlainDirIgnores(path string) ([]gitignore.Pattern, string, error) {
+	pathAbs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ps, err := readIgnoreFiles(osfs.New(pathAbs), []string{"."}, plainDirIgnoreFiles)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, "", err
+	}
+
+	return ps, pathAbs, nil
+}
+
 // Recursively walk up the directory tree processing .gitignore files as we go.
 // Once we reach the git-root dir, process it but don't recurse any further.
 func readIgnoreFilesFromParents(fs billy.Filesystem, pathRel string, pathGitRoot string) ([]gitignore.Pattern, error) {
@@ -161,8 +178,8 @@ func readIgnoreFilesFromParents(fs billy.Filesystem, pathRel string, pathGitRoot
 
 	pathAbs := filepath.Join(pathGitRoot, pathRel)
 
-	// read .gitignore
-	newPs, err := readIgnoreFile(fs, toGoGitPath(pathRel), gitignoreFile)
+	// read .gitignore and .osvscannerignore
+	newPs, err := readIgnoreFiles(fs, toGoGitPath(pathRel), repoIgnoreFiles)
 	if err != nil {
 		return ps, err
 	}
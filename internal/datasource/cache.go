@@ -5,6 +5,7 @@ import (
 	"encoding/gob"
 	"maps"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -27,17 +28,47 @@ func gobUnmarshal(b []byte, v any) error {
 	return dec.Decode(v)
 }
 
+type requestCacheError struct {
+	err     error
+	expires time.Time
+}
+
 type requestCacheCall[V any] struct {
 	wg  sync.WaitGroup
 	val V
 	err error
 }
 
+// RequestCacheStats contains counters of how calls to RequestCache.Get were served.
+type RequestCacheStats struct {
+	Hits    int64 // Calls served from the cache.
+	Misses  int64 // Calls that had to compute the value.
+	Dedupes int64 // Calls that waited on an in-flight computation for the same key.
+}
+
+// Add returns the sum of s and other.
+func (s RequestCacheStats) Add(other RequestCacheStats) RequestCacheStats {
+	return RequestCacheStats{
+		Hits:    s.Hits + other.Hits,
+		Misses:  s.Misses + other.Misses,
+		Dedupes: s.Dedupes + other.Dedupes,
+	}
+}
+
 // RequestCache is a map to cache the results of expensive functions that are called concurrently.
 type RequestCache[K comparable, V any] struct {
 	cache map[K]V
 	calls map[K]*requestCacheCall[V]
 	mu    sync.Mutex
+
+	// negative caching of errors, disabled unless errMatch is set
+	errCache map[K]requestCacheError
+	errMatch func(error) bool
+	errTTL   time.Duration
+
+	hits    atomic.Int64
+	misses  atomic.Int64
+	dedupes atomic.Int64
 }
 
 func NewRequestCache[K comparable, V any]() *RequestCache[K, V] {
@@ -47,6 +78,16 @@ func NewRequestCache[K comparable, V any]() *RequestCache[K, V] {
 	}
 }
 
+// CacheErrors makes Get also cache the errors for which match returns true, for the duration ttl.
+// Other errors are never cached.
+func (rq *RequestCache[K, V]) CacheErrors(match func(error) bool, ttl time.Duration) {
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+	rq.errCache = make(map[K]requestCacheError)
+	rq.errMatch = match
+	rq.errTTL = ttl
+}
+
 // Get gets the value from the cache map if it's cached, otherwise it will call fn to get the value and cache it.
 // fn will only ever be called once for a key, even if there are multiple simultaneous calls to Get before the first call is finished.
 func (rq *RequestCache[K, V]) Get(key K, fn func() (V, error)) (V, error) {
@@ -54,12 +95,28 @@ func (rq *RequestCache[K, V]) Get(key K, fn func() (V, error)) (V, error) {
 	rq.mu.Lock()
 	if v, ok := rq.cache[key]; ok {
 		rq.mu.Unlock()
+		rq.hits.Add(1)
+
 		return v, nil
 	}
 
+	// Try get it from the error cache.
+	if e, ok := rq.errCache[key]; ok {
+		if time.Now().Before(e.expires) {
+			rq.mu.Unlock()
+			rq.hits.Add(1)
+
+			var zero V
+
+			return zero, e.err
+		}
+		delete(rq.errCache, key)
+	}
+
 	// See if there is already a pending request for this key.
 	if c, ok := rq.calls[key]; ok {
 		rq.mu.Unlock()
+		rq.dedupes.Add(1)
 		c.wg.Wait()
 
 		return c.val, c.err
@@ -70,6 +127,7 @@ func (rq *RequestCache[K, V]) Get(key K, fn func() (V, error)) (V, error) {
 	c.wg.Add(1)
 	rq.calls[key] = c
 	rq.mu.Unlock()
+	rq.misses.Add(1)
 
 	c.val, c.err = fn()
 	rq.mu.Lock()
@@ -81,6 +139,8 @@ func (rq *RequestCache[K, V]) Get(key K, fn func() (V, error)) (V, error) {
 	// Store value in regular cache.
 	if c.err == nil {
 		rq.cache[key] = c.val
+	} else if rq.errMatch != nil && rq.errMatch(c.err) {
+		rq.errCache[key] = requestCacheError{err: c.err, expires: time.Now().Add(rq.errTTL)}
 	}
 
 	// Remove the completed call now that it's cached.
@@ -105,3 +165,12 @@ func (rq *RequestCache[K, V]) SetMap(m map[K]V) {
 	defer rq.mu.Unlock()
 	rq.cache = maps.Clone(m)
 }
+
+// Stats gets the current hit, miss, and dedupe counts of the cache.
+func (rq *RequestCache[K, V]) Stats() RequestCacheStats {
+	return RequestCacheStats{
+		Hits:    rq.hits.Load(),
+		Misses:  rq.misses.Load(),
+		Dedupes: rq.dedupes.Load(),
+	}
+}
@@ -1,10 +1,12 @@
 package datasource_test
 
 import (
+	"errors"
 	"maps"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/osv-scanner/v2/internal/datasource"
 )
@@ -45,6 +47,14 @@ func TestRequestCache(t *testing.T) {
 		}
 	}
 
+	stats := requestCache.Stats()
+	if stats.Misses != numKeys {
+		t.Errorf("RequestCache Stats Misses was %d, expected %d", stats.Misses, numKeys)
+	}
+	if total := stats.Hits + stats.Misses + stats.Dedupes; total != numKeys*requestsPerKey {
+		t.Errorf("RequestCache Stats counted %d calls, expected %d", total, numKeys*requestsPerKey)
+	}
+
 	cacheMap := requestCache.GetMap()
 	if len(cacheMap) != numKeys {
 		t.Errorf("RequestCache GetMap length was %d, expected %d", len(cacheMap), numKeys)
@@ -85,3 +95,40 @@ func TestRequestCacheSetMap(t *testing.T) {
 		t.Errorf("GetMap() got %v, want %v", gotMap, want)
 	}
 }
+
+func TestRequestCacheErrors(t *testing.T) {
+	t.Parallel()
+
+	errNotFound := errors.New("not found")
+	errOther := errors.New("other")
+
+	requestCache := datasource.NewRequestCache[string, string]()
+	requestCache.CacheErrors(func(err error) bool { return errors.Is(err, errNotFound) }, time.Hour)
+
+	calls := make(map[string]int)
+	fns := map[string]func() (string, error){
+		"missing": func() (string, error) { calls["missing"]++; return "", errNotFound },
+		"broken":  func() (string, error) { calls["broken"]++; return "", errOther },
+	}
+
+	for range 3 {
+		for k, fn := range fns {
+			_, err := requestCache.Get(k, fn)
+			if err == nil {
+				t.Errorf("Get(%v) returned no error", k)
+			}
+		}
+	}
+
+	// Only the matching error should have been cached.
+	if calls["missing"] != 1 {
+		t.Errorf("Get(missing) function called %d times, expected 1", calls["missing"])
+	}
+	if calls["broken"] != 3 {
+		t.Errorf("Get(broken) function called %d times, expected 3", calls["broken"])
+	}
+
+	if m := requestCache.GetMap(); len(m) != 0 {
+		t.Errorf("GetMap() got %v, want errors to be excluded", m)
+	}
+}
@@ -2,16 +2,25 @@ package datasource
 
 import (
 	"context"
+	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	pb "deps.dev/api/v3"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
+// maxBatchConcurrency is the maximum number of concurrent requests made by the batch lookup methods.
+const maxBatchConcurrency = 100
+
 // CachedInsightsClient is a wrapper for InsightsClient that caches requests.
 type CachedInsightsClient struct {
 	pb.InsightsClient
@@ -19,9 +28,22 @@ type CachedInsightsClient struct {
 	// cache fields
 	mu                sync.Mutex
 	cacheTimestamp    *time.Time
+	cacheTTL          time.Duration
 	packageCache      *RequestCache[packageKey, *pb.Package]
 	versionCache      *RequestCache[versionKey, *pb.Version]
 	requirementsCache *RequestCache[versionKey, *pb.Requirements]
+	projectCache      *RequestCache[string, *pb.Project]
+
+	// request options
+	callTimeout    time.Duration
+	maxRetries     int
+	retryBaseDelay time.Duration
+	notFoundTTL    time.Duration
+
+	// connection options
+	certPool   *x509.CertPool
+	skipVerify bool
+	plaintext  bool
 }
 
 // Comparable types to use as map keys for cache.
@@ -51,12 +73,92 @@ func makeVersionKey(k *pb.VersionKey) versionKey {
 	}
 }
 
-func NewCachedInsightsClient(addr string, userAgent string) (*CachedInsightsClient, error) {
-	certPool, err := x509.SystemCertPool()
+// makeProjectKey normalizes a project identifier (e.g. "https://GitHub.com/google/osv-scanner.git")
+// to the host and repository path form used by deps.dev (e.g. "github.com/google/osv-scanner").
+func makeProjectKey(k *pb.ProjectKey) string {
+	id := k.GetId()
+	if _, after, ok := strings.Cut(id, "://"); ok {
+		id = after
+	}
+	id = strings.TrimSuffix(strings.TrimSuffix(id, "/"), ".git")
+
+	host, path, _ := strings.Cut(id, "/")
+	if path == "" {
+		return strings.ToLower(host)
+	}
+
+	return strings.ToLower(host) + "/" + path
+}
+
+// ClientOption configures optional behaviour of a CachedInsightsClient.
+type ClientOption func(*CachedInsightsClient)
+
+// WithCallTimeout sets a timeout applied to each individual deps.dev RPC.
+// A non-positive timeout means calls only use the deadline of the caller's context.
+func WithCallTimeout(timeout time.Duration) ClientOption {
+	return func(c *CachedInsightsClient) {
+		c.callTimeout = timeout
+	}
+}
+
+// WithNotFoundCache makes the client remember NotFound responses for the duration ttl,
+// so that repeated lookups of missing packages do not re-query deps.dev.
+// This is disabled by default, since a missing package may be published at any time.
+func WithNotFoundCache(ttl time.Duration) ClientOption {
+	return func(c *CachedInsightsClient) {
+		c.notFoundTTL = ttl
+	}
+}
+
+// WithCertPool sets the certificate pool used to verify the server, instead of the system cert pool.
+// This is useful for self-hosted deps.dev mirrors using a private CA.
+func WithCertPool(pool *x509.CertPool) ClientOption {
+	return func(c *CachedInsightsClient) {
+		c.certPool = pool
+	}
+}
+
+// WithInsecureSkipVerify disables verification of the server's TLS certificate.
+func WithInsecureSkipVerify() ClientOption {
+	return func(c *CachedInsightsClient) {
+		c.skipVerify = true
+	}
+}
+
+// WithPlaintext connects to the server without TLS.
+// This should only be used for mirrors within a trusted network.
+func WithPlaintext() ClientOption {
+	return func(c *CachedInsightsClient) {
+		c.plaintext = true
+	}
+}
+
+func NewCachedInsightsClient(addr string, userAgent string, opts ...ClientOption) (*CachedInsightsClient, error) {
+	c := &CachedInsightsClient{
+		cacheTTL:          cacheExpiry,
+		maxRetries:        defaultMaxRetries,
+		retryBaseDelay:    defaultRetryBaseDelay,
+		packageCache:      NewRequestCache[packageKey, *pb.Package](),
+		versionCache:      NewRequestCache[versionKey, *pb.Version](),
+		requirementsCache: NewRequestCache[versionKey, *pb.Requirements](),
+		projectCache:      NewRequestCache[string, *pb.Project](),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.notFoundTTL > 0 {
+		isNotFound := func(err error) bool { return status.Code(err) == codes.NotFound }
+		c.packageCache.CacheErrors(isNotFound, c.notFoundTTL)
+		c.versionCache.CacheErrors(isNotFound, c.notFoundTTL)
+		c.requirementsCache.CacheErrors(isNotFound, c.notFoundTTL)
+		c.projectCache.CacheErrors(isNotFound, c.notFoundTTL)
+	}
+
+	creds, err := c.transportCredentials()
 	if err != nil {
-		return nil, fmt.Errorf("getting system cert pool: %w", err)
+		return nil, err
 	}
-	creds := credentials.NewClientTLSFromCert(certPool, "")
 	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
 
 	if userAgent != "" {
@@ -67,29 +169,101 @@ func NewCachedInsightsClient(addr string, userAgent string) (*CachedInsightsClie
 	if err != nil {
 		return nil, fmt.Errorf("dialling %q: %w", addr, err)
 	}
+	c.InsightsClient = pb.NewInsightsClient(conn)
 
-	return &CachedInsightsClient{
-		InsightsClient:    pb.NewInsightsClient(conn),
-		packageCache:      NewRequestCache[packageKey, *pb.Package](),
-		versionCache:      NewRequestCache[versionKey, *pb.Version](),
-		requirementsCache: NewRequestCache[versionKey, *pb.Requirements](),
-	}, nil
+	return c, nil
+}
+
+// transportCredentials gets the credentials to dial the server with, based on the connection options.
+func (c *CachedInsightsClient) transportCredentials() (credentials.TransportCredentials, error) {
+	if c.plaintext {
+		return insecure.NewCredentials(), nil
+	}
+
+	if c.skipVerify {
+		// #nosec G402 -- explicitly requested by the user
+		return credentials.NewTLS(&tls.Config{InsecureSkipVerify: true}), nil
+	}
+
+	certPool := c.certPool
+	if certPool == nil {
+		var err error
+		certPool, err = x509.SystemCertPool()
+		if err != nil {
+			return nil, fmt.Errorf("getting system cert pool: %w", err)
+		}
+	}
+
+	return credentials.NewClientTLSFromCert(certPool, ""), nil
+}
+
+// SetCacheTTL sets how long a persisted cache remains valid.
+// Caches loaded with LoadFromFile that are older than ttl are discarded.
+func (c *CachedInsightsClient) SetCacheTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cacheTTL = ttl
 }
 
 func (c *CachedInsightsClient) GetPackage(ctx context.Context, in *pb.GetPackageRequest, opts ...grpc.CallOption) (*pb.Package, error) {
 	return c.packageCache.Get(makePackageKey(in.GetPackageKey()), func() (*pb.Package, error) {
-		return c.InsightsClient.GetPackage(ctx, in, opts...)
+		return callWithRetry(ctx, c, func(ctx context.Context) (*pb.Package, error) {
+			return c.InsightsClient.GetPackage(ctx, in, opts...)
+		})
 	})
 }
 
 func (c *CachedInsightsClient) GetVersion(ctx context.Context, in *pb.GetVersionRequest, opts ...grpc.CallOption) (*pb.Version, error) {
 	return c.versionCache.Get(makeVersionKey(in.GetVersionKey()), func() (*pb.Version, error) {
-		return c.InsightsClient.GetVersion(ctx, in, opts...)
+		return callWithRetry(ctx, c, func(ctx context.Context) (*pb.Version, error) {
+			return c.InsightsClient.GetVersion(ctx, in, opts...)
+		})
 	})
 }
 
 func (c *CachedInsightsClient) GetRequirements(ctx context.Context, in *pb.GetRequirementsRequest, opts ...grpc.CallOption) (*pb.Requirements, error) {
 	return c.requirementsCache.Get(makeVersionKey(in.GetVersionKey()), func() (*pb.Requirements, error) {
-		return c.InsightsClient.GetRequirements(ctx, in, opts...)
+		return callWithRetry(ctx, c, func(ctx context.Context) (*pb.Requirements, error) {
+			return c.InsightsClient.GetRequirements(ctx, in, opts...)
+		})
+	})
+}
+
+func (c *CachedInsightsClient) GetProject(ctx context.Context, in *pb.GetProjectRequest, opts ...grpc.CallOption) (*pb.Project, error) {
+	key := makeProjectKey(in.GetProjectKey())
+
+	return c.projectCache.Get(key, func() (*pb.Project, error) {
+		req := &pb.GetProjectRequest{ProjectKey: &pb.ProjectKey{Id: key}}
+		return callWithRetry(ctx, c, func(ctx context.Context) (*pb.Project, error) {
+			return c.InsightsClient.GetProject(ctx, req, opts...)
+		})
 	})
 }
+
+// Stats gets the combined cache statistics of every request type cached by the client.
+func (c *CachedInsightsClient) Stats() RequestCacheStats {
+	return c.packageCache.Stats().
+		Add(c.versionCache.Stats()).
+		Add(c.requirementsCache.Stats()).
+		Add(c.projectCache.Stats())
+}
+
+// GetVersionBatch looks up the versions of all the given keys, making the uncached requests concurrently.
+// The returned versions and errors are in the same order as keys.
+// A failed lookup does not prevent the others from completing; its error is reported at the corresponding index.
+func (c *CachedInsightsClient) GetVersionBatch(ctx context.Context, keys []*pb.VersionKey) ([]*pb.Version, []error) {
+	versions := make([]*pb.Version, len(keys))
+	errs := make([]error, len(keys))
+
+	var g errgroup.Group
+	g.SetLimit(maxBatchConcurrency)
+	for i, k := range keys {
+		g.Go(func() error {
+			versions[i], errs[i] = c.GetVersion(ctx, &pb.GetVersionRequest{VersionKey: k})
+			return nil
+		})
+	}
+	_ = g.Wait() // errors are reported per key
+
+	return versions, errs
+}
@@ -1,6 +1,8 @@
 package datasource
 
 import (
+	"encoding/gob"
+	"os"
 	"time"
 
 	pb "deps.dev/api/v3"
@@ -12,6 +14,7 @@ type depsdevAPICache struct {
 	PackageCache      map[packageKey][]byte
 	VersionCache      map[versionKey][]byte
 	RequirementsCache map[versionKey][]byte
+	ProjectCache      map[string][]byte
 }
 
 func protoMarshalCache[K comparable, V proto.Message](protoMap map[K]V) (map[K][]byte, error) {
@@ -67,6 +70,10 @@ func (c *CachedInsightsClient) GobEncode() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	cache.ProjectCache, err = protoMarshalCache(c.projectCache.GetMap())
+	if err != nil {
+		return nil, err
+	}
 
 	return gobMarshal(cache)
 }
@@ -77,14 +84,18 @@ func (c *CachedInsightsClient) GobDecode(b []byte) error {
 		return err
 	}
 
-	if cache.Timestamp != nil && time.Since(*cache.Timestamp) >= cacheExpiry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ttl := c.cacheTTL
+	if ttl <= 0 {
+		ttl = cacheExpiry
+	}
+	if cache.Timestamp != nil && time.Since(*cache.Timestamp) >= ttl {
 		// Cache expired
 		return nil
 	}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	c.cacheTimestamp = cache.Timestamp
 
 	var pkgMap map[packageKey]*pb.Package
@@ -102,9 +113,38 @@ func (c *CachedInsightsClient) GobDecode(b []byte) error {
 		return err
 	}
 
+	var projMap map[string]*pb.Project
+	if err := protoUnmarshalCache(cache.ProjectCache, &projMap); err != nil {
+		return err
+	}
+
 	c.packageCache.SetMap(pkgMap)
 	c.versionCache.SetMap(verMap)
 	c.requirementsCache.SetMap(reqMap)
+	c.projectCache.SetMap(projMap)
 
 	return nil
 }
+
+// SaveToFile writes the cached deps.dev responses to the file at path.
+func (c *CachedInsightsClient) SaveToFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return gob.NewEncoder(f).Encode(c)
+}
+
+// LoadFromFile loads cached deps.dev responses previously written by SaveToFile.
+// If the saved cache is older than the client's cache TTL, it is ignored.
+func (c *CachedInsightsClient) LoadFromFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return gob.NewDecoder(f).Decode(c)
+}
@@ -0,0 +1,54 @@
+package datasource_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "deps.dev/api/v3"
+)
+
+func TestCachedInsightsClient_SaveLoadFile(t *testing.T) {
+	t.Parallel()
+
+	pkgKey := &pb.PackageKey{System: pb.System_NPM, Name: "foo"}
+	fake := &fakeInsightsClient{
+		packages: map[string]*pb.Package{
+			"foo": {PackageKey: pkgKey, Versions: []*pb.Package_Version{{VersionKey: &pb.VersionKey{System: pb.System_NPM, Name: "foo", Version: "1.0.0"}}}},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "insights.cache")
+	saved := newTestInsightsClient(t, fake)
+	if _, err := saved.GetPackage(context.Background(), &pb.GetPackageRequest{PackageKey: pkgKey}); err != nil {
+		t.Fatalf("GetPackage() error: %v", err)
+	}
+	if err := saved.SaveToFile(path); err != nil {
+		t.Fatalf("SaveToFile() error: %v", err)
+	}
+
+	// The loaded client has no packages available, so anything returned must come from the cache.
+	loaded := newTestInsightsClient(t, &fakeInsightsClient{})
+	if err := loaded.LoadFromFile(path); err != nil {
+		t.Fatalf("LoadFromFile() error: %v", err)
+	}
+	got, err := loaded.GetPackage(context.Background(), &pb.GetPackageRequest{PackageKey: pkgKey})
+	if err != nil {
+		t.Fatalf("GetPackage() after load error: %v", err)
+	}
+	if v := got.GetVersions(); len(v) != 1 || v[0].GetVersionKey().GetVersion() != "1.0.0" {
+		t.Errorf("GetPackage() after load got versions %v, want [1.0.0]", v)
+	}
+
+	// A cache older than the TTL must be discarded.
+	expired := newTestInsightsClient(t, &fakeInsightsClient{})
+	expired.SetCacheTTL(time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if err := expired.LoadFromFile(path); err != nil {
+		t.Fatalf("LoadFromFile() error: %v", err)
+	}
+	if _, err := expired.GetPackage(context.Background(), &pb.GetPackageRequest{PackageKey: pkgKey}); err == nil {
+		t.Errorf("GetPackage() after loading expired cache returned no error, want cache to be discarded")
+	}
+}
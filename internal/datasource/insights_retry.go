@@ -0,0 +1,86 @@
+package datasource
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultMaxRetries     = 3
+	defaultRetryBaseDelay = 500 * time.Millisecond
+)
+
+// WithRetry sets how many times a deps.dev RPC that failed with a transient error is retried,
+// and the base delay of the exponential backoff between attempts.
+// A maxRetries of 0 disables retrying.
+func WithRetry(maxRetries int, baseDelay time.Duration) ClientOption {
+	return func(c *CachedInsightsClient) {
+		c.maxRetries = maxRetries
+		c.retryBaseDelay = baseDelay
+	}
+}
+
+// isRetryable reports whether err is a gRPC status that could succeed if the request is tried again.
+func isRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff returns the delay before the given retry attempt (starting at 0),
+// doubling the base delay each attempt and adding up to the same amount of random jitter.
+func (c *CachedInsightsClient) backoff(attempt int) time.Duration {
+	delay := c.retryBaseDelay << attempt
+	if delay <= 0 {
+		return 0
+	}
+
+	// we do not need to use a cryptographically secure random jitter, this is just to spread out the retry requests
+	// #nosec G404
+	return delay + rand.N(delay)
+}
+
+// callContext returns the context to use for a single RPC, applying the configured call timeout.
+func (c *CachedInsightsClient) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.callTimeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, c.callTimeout)
+}
+
+// callWithRetry calls rpc, retrying with exponential backoff while it fails with a retryable error.
+// Retrying stops early if waiting for the next attempt would exceed the deadline of ctx.
+func callWithRetry[V any](ctx context.Context, c *CachedInsightsClient, rpc func(context.Context) (V, error)) (V, error) {
+	for attempt := 0; ; attempt++ {
+		v, err := func() (V, error) {
+			ctx, cancel := c.callContext(ctx)
+			defer cancel()
+
+			return rpc(ctx)
+		}()
+		if err == nil || !isRetryable(err) || attempt >= c.maxRetries {
+			return v, err
+		}
+
+		delay := c.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return v, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return v, err
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package datasource_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "deps.dev/api/v3"
+	"github.com/google/osv-scanner/v2/internal/datasource"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeInsightsClient serves GetPackage and GetVersion requests from fixed maps.
+type fakeInsightsClient struct {
+	pb.InsightsClient
+
+	packages map[string]*pb.Package
+	versions map[string]*pb.Version // keyed by name@version
+}
+
+func (f *fakeInsightsClient) GetPackage(_ context.Context, in *pb.GetPackageRequest, _ ...grpc.CallOption) (*pb.Package, error) {
+	if p, ok := f.packages[in.GetPackageKey().GetName()]; ok {
+		return p, nil
+	}
+
+	return nil, errors.New("package not found")
+}
+
+func (f *fakeInsightsClient) GetVersion(_ context.Context, in *pb.GetVersionRequest, _ ...grpc.CallOption) (*pb.Version, error) {
+	vk := in.GetVersionKey()
+	if v, ok := f.versions[vk.GetName()+"@"+vk.GetVersion()]; ok {
+		return v, nil
+	}
+
+	return nil, errors.New("version not found")
+}
+
+// hangingInsightsClient hangs on the first GetPackage request until its context is done,
+// then serves subsequent requests from the embedded fake.
+type hangingInsightsClient struct {
+	*fakeInsightsClient
+
+	calls atomic.Int32
+}
+
+func (h *hangingInsightsClient) GetPackage(ctx context.Context, in *pb.GetPackageRequest, opts ...grpc.CallOption) (*pb.Package, error) {
+	if h.calls.Add(1) == 1 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	return h.fakeInsightsClient.GetPackage(ctx, in, opts...)
+}
+
+func newTestInsightsClient(t *testing.T, fake pb.InsightsClient, opts ...datasource.ClientOption) *datasource.CachedInsightsClient {
+	t.Helper()
+
+	c, err := datasource.NewCachedInsightsClient("localhost:0", "", opts...)
+	if err != nil {
+		t.Fatalf("NewCachedInsightsClient() error: %v", err)
+	}
+	c.InsightsClient = fake
+
+	return c
+}
+
+func TestCachedInsightsClient_GetVersionBatch(t *testing.T) {
+	t.Parallel()
+
+	vk := func(name, version string) *pb.VersionKey {
+		return &pb.VersionKey{System: pb.System_NPM, Name: name, Version: version}
+	}
+	fake := &fakeInsightsClient{
+		versions: map[string]*pb.Version{
+			"foo@1.0.0": {VersionKey: vk("foo", "1.0.0")},
+			"bar@2.0.0": {VersionKey: vk("bar", "2.0.0")},
+		},
+	}
+	c := newTestInsightsClient(t, fake)
+
+	keys := []*pb.VersionKey{vk("bar", "2.0.0"), vk("missing", "1.0.0"), vk("foo", "1.0.0"), vk("bar", "2.0.0")}
+	versions, errs := c.GetVersionBatch(context.Background(), keys)
+	if len(versions) != len(keys) || len(errs) != len(keys) {
+		t.Fatalf("GetVersionBatch() returned %d versions and %d errors, want %d of each", len(versions), len(errs), len(keys))
+	}
+
+	for i, k := range keys {
+		if k.GetName() == "missing" {
+			if errs[i] == nil {
+				t.Errorf("GetVersionBatch() key %d (%v) returned no error, want not found", i, k)
+			}
+
+			continue
+		}
+		if errs[i] != nil {
+			t.Errorf("GetVersionBatch() key %d (%v) returned error: %v", i, k, errs[i])
+			continue
+		}
+		if got := versions[i].GetVersionKey(); got.GetName() != k.GetName() || got.GetVersion() != k.GetVersion() {
+			t.Errorf("GetVersionBatch() key %d got %v, want %v", i, got, k)
+		}
+	}
+}
+
+func TestCachedInsightsClient_CallTimeout(t *testing.T) {
+	t.Parallel()
+
+	pkgKey := &pb.PackageKey{System: pb.System_NPM, Name: "foo"}
+	fake := &hangingInsightsClient{
+		fakeInsightsClient: &fakeInsightsClient{
+			packages: map[string]*pb.Package{"foo": {PackageKey: pkgKey}},
+		},
+	}
+	c := newTestInsightsClient(t, fake, datasource.WithCallTimeout(10*time.Millisecond))
+	req := &pb.GetPackageRequest{PackageKey: pkgKey}
+
+	if _, err := c.GetPackage(context.Background(), req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetPackage() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// The timed out request must not have been cached.
+	got, err := c.GetPackage(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetPackage() retry error: %v", err)
+	}
+	if got.GetPackageKey().GetName() != "foo" {
+		t.Errorf("GetPackage() retry got %v, want foo", got)
+	}
+}
+
+// flakyInsightsClient fails GetRequirements requests with the given errors in order before succeeding.
+type flakyInsightsClient struct {
+	pb.InsightsClient
+
+	errs  []error
+	calls atomic.Int32
+}
+
+func (f *flakyInsightsClient) GetRequirements(_ context.Context, in *pb.GetRequirementsRequest, _ ...grpc.CallOption) (*pb.Requirements, error) {
+	i := int(f.calls.Add(1)) - 1
+	if i < len(f.errs) {
+		return nil, f.errs[i]
+	}
+
+	return &pb.Requirements{}, nil
+}
+
+func TestCachedInsightsClient_Retry(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantErr   codes.Code
+		wantCalls int32
+	}{
+		{
+			name:      "transient_errors",
+			errs:      []error{status.Error(codes.Unavailable, "unavailable"), status.Error(codes.ResourceExhausted, "slow down")},
+			wantErr:   codes.OK,
+			wantCalls: 3,
+		},
+		{
+			name:      "permanent_error",
+			errs:      []error{status.Error(codes.InvalidArgument, "bad request")},
+			wantErr:   codes.InvalidArgument,
+			wantCalls: 1,
+		},
+		{
+			name: "retries_exhausted",
+			errs: []error{
+				status.Error(codes.Unavailable, "1"),
+				status.Error(codes.Unavailable, "2"),
+				status.Error(codes.Unavailable, "3"),
+			},
+			wantErr:   codes.Unavailable,
+			wantCalls: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fake := &flakyInsightsClient{errs: tt.errs}
+			c := newTestInsightsClient(t, fake, datasource.WithRetry(2, time.Millisecond))
+			_, err := c.GetRequirements(context.Background(), &pb.GetRequirementsRequest{
+				VersionKey: &pb.VersionKey{System: pb.System_NPM, Name: "foo", Version: "1.0.0"},
+			})
+			if got := status.Code(err); got != tt.wantErr {
+				t.Errorf("GetRequirements() error code = %v, want %v", got, tt.wantErr)
+			}
+			if got := fake.calls.Load(); got != tt.wantCalls {
+				t.Errorf("GetRequirements() made %d calls, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+type testInsightsServer struct {
+	pb.UnimplementedInsightsServer
+}
+
+func (testInsightsServer) GetPackage(_ context.Context, in *pb.GetPackageRequest) (*pb.Package, error) {
+	return &pb.Package{PackageKey: in.GetPackageKey()}, nil
+}
+
+func TestCachedInsightsClient_Plaintext(t *testing.T) {
+	t.Parallel()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	pb.RegisterInsightsServer(srv, testInsightsServer{})
+	go srv.Serve(lis) //nolint:errcheck
+	t.Cleanup(srv.Stop)
+
+	c, err := datasource.NewCachedInsightsClient(lis.Addr().String(), "", datasource.WithPlaintext())
+	if err != nil {
+		t.Fatalf("NewCachedInsightsClient() error: %v", err)
+	}
+
+	pkgKey := &pb.PackageKey{System: pb.System_NPM, Name: "foo"}
+	got, err := c.GetPackage(context.Background(), &pb.GetPackageRequest{PackageKey: pkgKey})
+	if err != nil {
+		t.Fatalf("GetPackage() error: %v", err)
+	}
+	if got.GetPackageKey().GetName() != "foo" {
+		t.Errorf("GetPackage() got %v, want foo", got)
+	}
+}
+
+// projectInsightsClient records the project IDs requested from GetProject.
+type projectInsightsClient struct {
+	pb.InsightsClient
+
+	mu  sync.Mutex
+	ids []string
+}
+
+func (p *projectInsightsClient) GetProject(_ context.Context, in *pb.GetProjectRequest, _ ...grpc.CallOption) (*pb.Project, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ids = append(p.ids, in.GetProjectKey().GetId())
+
+	return &pb.Project{ProjectKey: in.GetProjectKey(), StarsCount: 42}, nil
+}
+
+func TestCachedInsightsClient_GetProject(t *testing.T) {
+	t.Parallel()
+
+	fake := &projectInsightsClient{}
+	c := newTestInsightsClient(t, fake)
+
+	for _, id := range []string{
+		"github.com/google/osv-scanner",
+		"https://GitHub.com/google/osv-scanner.git",
+		"GITHUB.COM/google/osv-scanner/",
+	} {
+		got, err := c.GetProject(context.Background(), &pb.GetProjectRequest{ProjectKey: &pb.ProjectKey{Id: id}})
+		if err != nil {
+			t.Fatalf("GetProject(%q) error: %v", id, err)
+		}
+		if got.GetStarsCount() != 42 {
+			t.Errorf("GetProject(%q) got %v, want 42 stars", id, got)
+		}
+	}
+
+	if want := []string{"github.com/google/osv-scanner"}; !slices.Equal(fake.ids, want) {
+		t.Errorf("GetProject() requested IDs %v, want %v", fake.ids, want)
+	}
+}
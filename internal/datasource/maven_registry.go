@@ -27,6 +27,7 @@ type MavenRegistryAPIClient struct {
 	defaultRegistry MavenRegistry                  // The default registry that we are making requests
 	registries      []MavenRegistry                // Additional registries specified to fetch projects
 	registryAuths   map[string]*HTTPAuthentication // Authentication for the registries keyed by registry ID. From settings.xml
+	mirrors         []MavenSettingsXMLMirror       // Mirrors to use in place of registries. From settings.xml
 
 	// Cache fields
 	mu             *sync.Mutex
@@ -50,9 +51,15 @@ type MavenRegistry struct {
 }
 
 func NewMavenRegistryAPIClient(registry MavenRegistry) (*MavenRegistryAPIClient, error) {
+	// TODO: allow for manual specification of settings files
+	globalSettings, userSettings := LoadMavenSettings()
+	mirrors := MavenMirrors(globalSettings, userSettings)
+
 	if registry.URL == "" {
 		registry.URL = MavenCentral
 		registry.ID = "central"
+		// Maven Central is only used if it is not mirrored, such as by an internal repository manager
+		registry = MirroredRegistry(mirrors, registry)
 	}
 	u, err := url.Parse(registry.URL)
 	if err != nil {
@@ -60,17 +67,22 @@ func NewMavenRegistryAPIClient(registry MavenRegistry) (*MavenRegistryAPIClient,
 	}
 	registry.Parsed = u
 
-	// TODO: allow for manual specification of settings files
-	globalSettings := ParseMavenSettings(globalMavenSettingsFile())
-	userSettings := ParseMavenSettings(userMavenSettingsFile())
-
-	return &MavenRegistryAPIClient{
+	client := &MavenRegistryAPIClient{
 		// We assume only downloading releases is allowed on the default registry.
 		defaultRegistry: registry,
 		mu:              &sync.Mutex{},
 		responses:       NewRequestCache[string, response](),
 		registryAuths:   MakeMavenAuth(globalSettings, userSettings),
-	}, nil
+		mirrors:         mirrors,
+	}
+
+	for _, reg := range MavenProfileRegistries(globalSettings, userSettings) {
+		if err := client.AddRegistry(reg); err != nil {
+			return nil, fmt.Errorf("invalid Maven registry %s in settings.xml: %w", reg.ID, err)
+		}
+	}
+
+	return client, nil
 }
 
 // WithoutRegistries makes MavenRegistryAPIClient including its cache but not registries.
@@ -80,11 +92,19 @@ func (m *MavenRegistryAPIClient) WithoutRegistries() *MavenRegistryAPIClient {
 		mu:              m.mu,
 		cacheTimestamp:  m.cacheTimestamp,
 		responses:       m.responses,
+		registryAuths:   m.registryAuths,
+		mirrors:         m.mirrors,
 	}
 }
 
-// AddRegistry adds the given registry to the list of registries if it has not been added.
+// AddRegistry adds the given registry to the list of registries if it has not been added,
+// using the mirror of the registry in its place if one is defined in settings.xml.
 func (m *MavenRegistryAPIClient) AddRegistry(registry MavenRegistry) error {
+	registry = MirroredRegistry(m.mirrors, registry)
+	if registry.ID == m.defaultRegistry.ID && registry.URL == m.defaultRegistry.URL {
+		return nil
+	}
+
 	for _, reg := range m.registries {
 		if reg.ID == registry.ID {
 			return nil
@@ -3,6 +3,8 @@ package datasource
 import (
 	"context"
 	"net/url"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -300,3 +302,34 @@ func TestMultipleRegistry(t *testing.T) {
 		t.Errorf("GetVersions(%s, %s):\ngot %v\nwant %v\n", "org.example", "x.y.z", gotVersions, wantVersions)
 	}
 }
+
+func TestMavenRegistryAPIClient_SettingsMirror(t *testing.T) {
+	mirror := testutility.NewMockHTTPServer(t)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	if err := os.MkdirAll(filepath.Join(home, ".m2"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	settings := `<settings><mirrors><mirror><id>nexus</id><url>` + mirror.URL + `</url><mirrorOf>central</mirrorOf></mirror></mirrors></settings>`
+	if err := os.WriteFile(filepath.Join(home, ".m2", "settings.xml"), []byte(settings), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewMavenRegistryAPIClient(MavenRegistry{ReleasesEnabled: true})
+	if err != nil {
+		t.Fatalf("NewMavenRegistryAPIClient() error = %v", err)
+	}
+	mirror.SetResponse(t, "org/example/x.y.z/1.0.0/x.y.z-1.0.0.pom", []byte(`
+	<project>
+	  <groupId>org.example</groupId>
+	  <artifactId>x.y.z</artifactId>
+	  <version>1.0.0</version>
+	</project>
+	`))
+
+	if _, err := client.GetProject(context.Background(), "org.example", "x.y.z", "1.0.0"); err != nil {
+		t.Fatalf("GetProject() did not fetch the project from the mirror: %v", err)
+	}
+}
@@ -2,21 +2,26 @@ package datasource
 
 import (
 	"encoding/xml"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"unicode"
 
 	"github.com/google/osv-scanner/v2/internal/cachedregexp"
 )
 
-// Maven settings.xml file parsing for registry authentication.
+// Maven settings.xml file parsing for registry authentication, mirrors and repositories.
 // https://maven.apache.org/settings.html
 
 type MavenSettingsXML struct {
-	Servers []MavenSettingsXMLServer `xml:"servers>server"`
+	Servers        []MavenSettingsXMLServer  `xml:"servers>server"`
+	Mirrors        []MavenSettingsXMLMirror  `xml:"mirrors>mirror"`
+	Profiles       []MavenSettingsXMLProfile `xml:"profiles>profile"`
+	ActiveProfiles []string                  `xml:"activeProfiles>activeProfile"`
 }
 
 type MavenSettingsXMLServer struct {
@@ -25,6 +30,29 @@ type MavenSettingsXMLServer struct {
 	Password string `xml:"password"`
 }
 
+// MavenSettingsXMLMirror is a registry that is used in place of the
+// registries with IDs matching MirrorOf, such as "central" or "*"
+type MavenSettingsXMLMirror struct {
+	ID       string `xml:"id"`
+	URL      string `xml:"url"`
+	MirrorOf string `xml:"mirrorOf"`
+}
+
+type MavenSettingsXMLProfile struct {
+	ID              string                       `xml:"id"`
+	ActiveByDefault bool                         `xml:"activation>activeByDefault"`
+	Repositories    []MavenSettingsXMLRepository `xml:"repositories>repository"`
+}
+
+type MavenSettingsXMLRepository struct {
+	ID  string `xml:"id"`
+	URL string `xml:"url"`
+	// ReleasesEnabled and SnapshotsEnabled are only "false" if releases or
+	// snapshots respectively are not to be fetched from the repository
+	ReleasesEnabled  string `xml:"releases>enabled"`
+	SnapshotsEnabled string `xml:"snapshots>enabled"`
+}
+
 func ParseMavenSettings(path string) MavenSettingsXML {
 	f, err := os.Open(path)
 	if err != nil {
@@ -62,10 +90,24 @@ func ParseMavenSettings(path string) MavenSettingsXML {
 		settings.Servers[i].Username = re.ReplaceAllStringFunc(settings.Servers[i].Username, replFn)
 		settings.Servers[i].Password = re.ReplaceAllStringFunc(settings.Servers[i].Password, replFn)
 	}
+	for i := range settings.Mirrors {
+		settings.Mirrors[i].URL = re.ReplaceAllStringFunc(settings.Mirrors[i].URL, replFn)
+	}
+	for i := range settings.Profiles {
+		for j := range settings.Profiles[i].Repositories {
+			settings.Profiles[i].Repositories[j].URL = re.ReplaceAllStringFunc(settings.Profiles[i].Repositories[j].URL, replFn)
+		}
+	}
 
 	return settings
 }
 
+// LoadMavenSettings parses the global and user settings.xml files, which are
+// treated as being empty if they do not exist or cannot be parsed
+func LoadMavenSettings() (globalSettings, userSettings MavenSettingsXML) {
+	return ParseMavenSettings(globalMavenSettingsFile()), ParseMavenSettings(userMavenSettingsFile())
+}
+
 // TODO: How to use with virtual filesystem + environment variables.
 func globalMavenSettingsFile() string {
 	// ${maven.home}/conf/settings.xml
@@ -122,3 +164,95 @@ func MakeMavenAuth(globalSettings, userSettings MavenSettingsXML) map[string]*HT
 
 	return auth
 }
+
+// MavenMirrors returns the mirrors defined in the settings, with those of
+// the user settings first as they take precedence over the global settings
+func MavenMirrors(globalSettings, userSettings MavenSettingsXML) []MavenSettingsXMLMirror {
+	return append(slices.Clone(userSettings.Mirrors), globalSettings.Mirrors...)
+}
+
+// MirroredRegistry returns the registry with the URL and ID of the mirror that is used in place of it,
+// or the registry as is if it has no mirror. Like Maven, a mirror of the ID of the registry takes
+// precedence over mirrors of patterns, otherwise the first matching mirror is used.
+//
+// The ID of the mirror is used so that the credentials of the server of the mirror are used for it.
+func MirroredRegistry(mirrors []MavenSettingsXMLMirror, registry MavenRegistry) MavenRegistry {
+	if registry.ID == "" {
+		// registries without an ID have been given explicitly, rather than
+		// coming from a pom.xml or settings.xml, so are not mirrored
+		return registry
+	}
+
+	i := slices.IndexFunc(mirrors, func(m MavenSettingsXMLMirror) bool { return m.MirrorOf == registry.ID })
+	if i < 0 {
+		i = slices.IndexFunc(mirrors, func(m MavenSettingsXMLMirror) bool { return isMirrorOf(m.MirrorOf, registry) })
+	}
+	if i < 0 {
+		return registry
+	}
+
+	registry.ID = mirrors[i].ID
+	registry.URL = mirrors[i].URL
+	registry.Parsed = nil
+
+	return registry
+}
+
+// isMirrorOf checks if the registry matches the mirrorOf of a mirror, which is a comma-separated list of
+// repository IDs and "*", "external:*" or "external:http:*", with IDs prefixed with "!" being excluded.
+// https://maven.apache.org/guides/mini/guide-mirror-settings.html#advanced-mirror-specification
+func isMirrorOf(mirrorOf string, registry MavenRegistry) bool {
+	matched := false
+
+	for _, pattern := range strings.Split(mirrorOf, ",") {
+		pattern = strings.TrimSpace(pattern)
+
+		switch {
+		case pattern == "!"+registry.ID:
+			return false
+		case pattern == "*", pattern == registry.ID:
+			matched = true
+		case pattern == "external:*":
+			matched = matched || !isLocalRegistry(registry.URL)
+		case pattern == "external:http:*":
+			matched = matched || (strings.HasPrefix(registry.URL, "http:") && !isLocalRegistry(registry.URL))
+		}
+	}
+
+	return matched
+}
+
+// isLocalRegistry checks if the registry is on the local machine, which mirrors of external registries do not apply to
+func isLocalRegistry(registryURL string) bool {
+	u, err := url.Parse(registryURL)
+	if err != nil {
+		return false
+	}
+
+	return u.Scheme == "file" || u.Hostname() == "localhost" || u.Hostname() == "127.0.0.1"
+}
+
+// MavenProfileRegistries returns the repositories of the profiles in the settings that are active,
+// either by default or by being listed in activeProfiles, with those of the user settings first
+func MavenProfileRegistries(globalSettings, userSettings MavenSettingsXML) []MavenRegistry {
+	var registries []MavenRegistry
+
+	for _, settings := range []MavenSettingsXML{userSettings, globalSettings} {
+		for _, profile := range settings.Profiles {
+			if !profile.ActiveByDefault && !slices.Contains(settings.ActiveProfiles, profile.ID) {
+				continue
+			}
+
+			for _, repo := range profile.Repositories {
+				registries = append(registries, MavenRegistry{
+					URL:              repo.URL,
+					ID:               repo.ID,
+					ReleasesEnabled:  repo.ReleasesEnabled != "false",
+					SnapshotsEnabled: repo.SnapshotsEnabled != "false",
+				})
+			}
+		}
+	}
+
+	return registries
+}
@@ -114,3 +114,94 @@ func TestMakeMavenAuth(t *testing.T) {
 		t.Errorf("MakeMavenAuth() (-want +got):\n%s", diff)
 	}
 }
+
+func TestParseMavenSettings_MirrorsAndProfiles(t *testing.T) {
+	t.Setenv("MAVEN_SETTINGS_TEST_HOST", "nexus.example.com")
+
+	settings := datasource.ParseMavenSettings("./fixtures/maven_settings/settings-mirrors.xml")
+
+	wantMirrors := []datasource.MavenSettingsXMLMirror{
+		{
+			ID:       "nexus",
+			URL:      "https://nexus.example.com/repository/maven-public",
+			MirrorOf: "*,!internal-snapshots",
+		},
+	}
+	if diff := cmp.Diff(wantMirrors, datasource.MavenMirrors(datasource.MavenSettingsXML{}, settings)); diff != "" {
+		t.Errorf("MavenMirrors() (-want +got):\n%s", diff)
+	}
+
+	wantRegistries := []datasource.MavenRegistry{
+		{
+			URL:              "https://nexus.example.com/repository/snapshots",
+			ID:               "internal-snapshots",
+			ReleasesEnabled:  false,
+			SnapshotsEnabled: true,
+		},
+		{
+			URL:              "https://extra.example.com/maven2",
+			ID:               "extra",
+			ReleasesEnabled:  true,
+			SnapshotsEnabled: false,
+		},
+	}
+	if diff := cmp.Diff(wantRegistries, datasource.MavenProfileRegistries(datasource.MavenSettingsXML{}, settings)); diff != "" {
+		t.Errorf("MavenProfileRegistries() (-want +got):\n%s", diff)
+	}
+}
+
+func TestMirroredRegistry(t *testing.T) {
+	t.Parallel()
+
+	mirrors := []datasource.MavenSettingsXMLMirror{
+		{ID: "external", URL: "https://external.example.com", MirrorOf: "external:*,!excluded"},
+		{ID: "central-mirror", URL: "https://central.example.com", MirrorOf: "central"},
+		{ID: "http", URL: "https://http.example.com", MirrorOf: "external:http:*"},
+	}
+
+	tests := []struct {
+		name     string
+		registry datasource.MavenRegistry
+		want     string
+	}{
+		{
+			name:     "mirror of the id takes precedence",
+			registry: datasource.MavenRegistry{ID: "central", URL: datasource.MavenCentral},
+			want:     "central-mirror",
+		},
+		{
+			name:     "external",
+			registry: datasource.MavenRegistry{ID: "other", URL: "https://other.example.com"},
+			want:     "external",
+		},
+		{
+			name:     "excluded",
+			registry: datasource.MavenRegistry{ID: "excluded", URL: "https://excluded.example.com"},
+			want:     "excluded",
+		},
+		{
+			name:     "excluded from the first mirror but not the second",
+			registry: datasource.MavenRegistry{ID: "excluded", URL: "http://excluded.example.com"},
+			want:     "http",
+		},
+		{
+			name:     "local",
+			registry: datasource.MavenRegistry{ID: "local", URL: "http://localhost:8081/maven2"},
+			want:     "local",
+		},
+		{
+			name:     "no id",
+			registry: datasource.MavenRegistry{URL: "https://explicit.example.com"},
+			want:     "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := datasource.MirroredRegistry(mirrors, tt.registry); got.ID != tt.want {
+				t.Errorf("MirroredRegistry() = %s (%s), want %s", got.ID, got.URL, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package depgraph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+type cargoLockPackage struct {
+	Name         string   `toml:"name"`
+	Version      string   `toml:"version"`
+	Source       string   `toml:"source"`
+	Dependencies []string `toml:"dependencies"`
+}
+
+type cargoLockfile struct {
+	Packages []cargoLockPackage `toml:"package"`
+}
+
+// parseCargoLock reads the graph of a Cargo.lock, where the packages of the workspace
+// itself are the ones without a source
+func parseCargoLock(path string) (*Graph, error) {
+	var lockfile cargoLockfile
+	if _, err := toml.DecodeFile(path, &lockfile); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+
+	versions := make(map[string][]string)
+	for _, pkg := range lockfile.Packages {
+		versions[pkg.Name] = append(versions[pkg.Name], pkg.Version)
+	}
+
+	// dependencies are just the name of the package unless there are multiple versions
+	// of it, in which case they are "<name> <version>", optionally followed by the source
+	resolveDep := func(dependency string) (Package, bool) {
+		fields := strings.Fields(dependency)
+		if len(fields) == 0 {
+			return Package{}, false
+		}
+		if len(fields) > 1 {
+			return Package{Name: fields[0], Version: fields[1]}, true
+		}
+		if len(versions[fields[0]]) != 1 {
+			return Package{}, false
+		}
+
+		return Package{Name: fields[0], Version: versions[fields[0]][0]}, true
+	}
+
+	local := make(map[Package]bool)
+	for _, pkg := range lockfile.Packages {
+		if pkg.Source == "" {
+			local[Package{Name: pkg.Name, Version: pkg.Version}] = true
+		}
+	}
+
+	g := newGraph()
+	for _, pkg := range lockfile.Packages {
+		from := Package{Name: pkg.Name, Version: pkg.Version}
+
+		for _, dependency := range pkg.Dependencies {
+			to, ok := resolveDep(dependency)
+			if !ok || local[to] {
+				continue
+			}
+
+			if local[from] {
+				g.addDirect(to)
+			} else {
+				g.addDependency(from, to)
+			}
+		}
+	}
+
+	return g, nil
+}
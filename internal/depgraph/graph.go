@@ -0,0 +1,121 @@
+// Package depgraph reconstructs the dependency graphs that are recorded by lockfiles,
+// so that the path through which a package is depended on can be reported.
+package depgraph
+
+import (
+	"cmp"
+	"errors"
+	"maps"
+	"path/filepath"
+	"slices"
+)
+
+// ErrUnsupported is returned for lockfiles that do not record the graph of their dependencies
+var ErrUnsupported = errors.New("dependency graphs cannot be read from this type of lockfile")
+
+// Package is a particular version of a package within a dependency graph
+type Package struct {
+	Name    string
+	Version string
+}
+
+func (p Package) String() string {
+	return p.Name + "@" + p.Version
+}
+
+func comparePackages(a, b Package) int {
+	return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Version, b.Version))
+}
+
+// Graph is the graph of dependencies recorded by a lockfile
+type Graph struct {
+	// direct are the packages that the project itself depends on
+	direct map[Package]struct{}
+	// dependencies are the packages that each package directly depends on
+	dependencies map[Package]map[Package]struct{}
+}
+
+func newGraph() *Graph {
+	return &Graph{
+		direct:       make(map[Package]struct{}),
+		dependencies: make(map[Package]map[Package]struct{}),
+	}
+}
+
+func (g *Graph) addDirect(pkg Package) {
+	g.direct[pkg] = struct{}{}
+}
+
+func (g *Graph) addDependency(from, to Package) {
+	if from == to {
+		return
+	}
+
+	if g.dependencies[from] == nil {
+		g.dependencies[from] = make(map[Package]struct{})
+	}
+	g.dependencies[from][to] = struct{}{}
+}
+
+// ShortestPath returns the shortest path through which the project depends on the
+// given package, starting with a direct dependency and ending with the package itself.
+//
+// Nil is returned if the package is not depended on by the project.
+func (g *Graph) ShortestPath(pkg Package) []Package {
+	parents := make(map[Package]Package)
+	seen := make(map[Package]bool)
+
+	// packages are visited in order so that the same path is always chosen
+	queue := slices.SortedFunc(maps.Keys(g.direct), comparePackages)
+	for _, p := range queue {
+		seen[p] = true
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current == pkg {
+			path := []Package{current}
+			for {
+				parent, ok := parents[current]
+				if !ok {
+					break
+				}
+				path = append(path, parent)
+				current = parent
+			}
+			slices.Reverse(path)
+
+			return path
+		}
+
+		for _, dep := range slices.SortedFunc(maps.Keys(g.dependencies[current]), comparePackages) {
+			if seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			parents[dep] = current
+			queue = append(queue, dep)
+		}
+	}
+
+	return nil
+}
+
+// Parse reads the dependency graph recorded by the lockfile at the given path,
+// returning ErrUnsupported if the lockfile is not of a type that records its graph.
+func Parse(path string) (*Graph, error) {
+	switch filepath.Base(path) {
+	case "package-lock.json":
+		return parseNpmLock(path)
+	case "pnpm-lock.yaml":
+		return parsePnpmLock(path)
+	case "yarn.lock":
+		return parseYarnLock(path)
+	case "Cargo.lock":
+		return parseCargoLock(path)
+	}
+
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,99 @@
+package depgraph_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/depgraph"
+)
+
+func TestGraph_ShortestPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		pkg  depgraph.Package
+		want []string
+	}{
+		{
+			path: "testdata/npm/package-lock.json",
+			pkg:  depgraph.Package{Name: "express", Version: "4.18.2"},
+			want: []string{"express@4.18.2"},
+		},
+		{
+			path: "testdata/npm/package-lock.json",
+			pkg:  depgraph.Package{Name: "qs", Version: "6.11.0"},
+			want: []string{"body-parser@1.20.2", "qs@6.11.0"},
+		},
+		{
+			path: "testdata/npm/package-lock.json",
+			pkg:  depgraph.Package{Name: "body-parser", Version: "1.20.1"},
+			want: []string{"express@4.18.2", "body-parser@1.20.1"},
+		},
+		{
+			path: "testdata/npm/package-lock.json",
+			pkg:  depgraph.Package{Name: "left-pad", Version: "1.3.0"},
+			want: nil,
+		},
+		{
+			path: "testdata/pnpm/pnpm-lock.yaml",
+			pkg:  depgraph.Package{Name: "js-tokens", Version: "4.0.0"},
+			want: []string{"react@18.2.0", "loose-envify@1.4.0", "js-tokens@4.0.0"},
+		},
+		{
+			path: "testdata/pnpm/pnpm-lock.yaml",
+			pkg:  depgraph.Package{Name: "ansi-regex", Version: "5.0.1"},
+			want: []string{"string-width@4.2.3", "ansi-regex@5.0.1"},
+		},
+		{
+			path: "testdata/yarn/yarn.lock",
+			pkg:  depgraph.Package{Name: "lodash", Version: "4.17.21"},
+			want: []string{"lodash@4.17.21"},
+		},
+		{
+			path: "testdata/yarn/yarn.lock",
+			pkg:  depgraph.Package{Name: "ansi-regex", Version: "5.0.1"},
+			want: []string{"string-width@4.2.3", "strip-ansi@6.0.1", "ansi-regex@5.0.1"},
+		},
+		{
+			path: "testdata/cargo/Cargo.lock",
+			pkg:  depgraph.Package{Name: "h2", Version: "0.3.26"},
+			want: []string{"reqwest@0.11.27", "hyper@0.14.28", "h2@0.3.26"},
+		},
+		{
+			path: "testdata/cargo/Cargo.lock",
+			pkg:  depgraph.Package{Name: "time", Version: "0.1.45"},
+			want: []string{"time@0.1.45"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path+"/"+tt.pkg.String(), func(t *testing.T) {
+			t.Parallel()
+
+			g, err := depgraph.Parse(tt.path)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			var got []string
+			for _, pkg := range g.ShortestPath(tt.pkg) {
+				got = append(got, pkg.String())
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ShortestPath() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParse_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	for _, path := range []string{"testdata/yarn-classic/yarn.lock", "testdata/requirements.txt"} {
+		if _, err := depgraph.Parse(path); !errors.Is(err, depgraph.ErrUnsupported) {
+			t.Errorf("Parse(%q) error = %v, want %v", path, err, depgraph.ErrUnsupported)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package depgraph
+
+import (
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/v2/internal/resolution/depfile"
+	"github.com/google/osv-scanner/v2/internal/resolution/lockfile"
+)
+
+func parseNpmLock(path string) (*Graph, error) {
+	file, err := depfile.OpenLocalDepFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	resolved, err := lockfile.NpmReadWriter{}.Read(file)
+	if err != nil {
+		return nil, err
+	}
+
+	pkgOf := func(id resolve.NodeID) Package {
+		vk := resolved.Nodes[id].Version
+		return Package{Name: vk.Name, Version: vk.Version}
+	}
+
+	// the root of the graph is the project itself
+	g := newGraph()
+	for _, edge := range resolved.Edges {
+		if edge.From == 0 {
+			g.addDirect(pkgOf(edge.To))
+		} else {
+			g.addDependency(pkgOf(edge.From), pkgOf(edge.To))
+		}
+	}
+
+	return g, nil
+}
@@ -0,0 +1,123 @@
+package depgraph
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/google/osv-scanner/v2/internal/utility/npmname"
+	"gopkg.in/yaml.v3"
+)
+
+type pnpmLockPackage struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+}
+
+type pnpmLockImporterDependency struct {
+	Version string `yaml:"version"`
+}
+
+type pnpmLockImporter struct {
+	Dependencies         map[string]pnpmLockImporterDependency `yaml:"dependencies"`
+	OptionalDependencies map[string]pnpmLockImporterDependency `yaml:"optionalDependencies"`
+	DevDependencies      map[string]pnpmLockImporterDependency `yaml:"devDependencies"`
+}
+
+type pnpmLockSnapshot struct {
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+}
+
+type pnpmLockfile struct {
+	Version   string                      `yaml:"lockfileVersion"`
+	Importers map[string]pnpmLockImporter `yaml:"importers"`
+	Packages  map[string]pnpmLockPackage  `yaml:"packages"`
+	Snapshots map[string]pnpmLockSnapshot `yaml:"snapshots"`
+}
+
+// parsePnpmLock reads the graph of a v9 pnpm-lock.yaml, which records the dependencies
+// of each workspace package as importers and of each package as snapshots
+func parsePnpmLock(path string) (*Graph, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var lockfile pnpmLockfile
+	if err := yaml.Unmarshal(b, &lockfile); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+
+	if v, err := strconv.ParseFloat(lockfile.Version, 64); err != nil || v < 9.0 {
+		return nil, ErrUnsupported
+	}
+
+	// pkgOf returns the package of a snapshot, and if the snapshot is actually of a package
+	pkgOf := func(id string) (Package, bool) {
+		if id == "" {
+			return Package{}, false
+		}
+
+		// snapshot ids include any peer dependencies, e.g. "react-dom@18.2.0(react@18.2.0)"
+		pkgID, _, _ := strings.Cut(id, "(")
+		pkg := Package{}
+
+		if name, version, ok := npmname.Split(pkgID); ok {
+			pkg = Package{Name: name, Version: version}
+		}
+
+		if p, ok := lockfile.Packages[pkgID]; ok {
+			pkg.Name = cmp.Or(p.Name, pkg.Name)
+			pkg.Version = cmp.Or(p.Version, pkg.Version)
+		}
+
+		return pkg, pkg.Name != "" && pkg.Version != ""
+	}
+
+	g := newGraph()
+	for _, imp := range lockfile.Importers {
+		for _, deps := range []map[string]pnpmLockImporterDependency{imp.Dependencies, imp.OptionalDependencies, imp.DevDependencies} {
+			for name, dep := range deps {
+				if pkg, ok := pkgOf(pnpmSnapshotID(name, dep.Version)); ok {
+					g.addDirect(pkg)
+				}
+			}
+		}
+	}
+
+	for id, snap := range lockfile.Snapshots {
+		from, ok := pkgOf(id)
+		if !ok {
+			continue
+		}
+
+		for _, deps := range []map[string]string{snap.Dependencies, snap.OptionalDependencies} {
+			for name, version := range deps {
+				if to, ok := pkgOf(pnpmSnapshotID(name, version)); ok {
+					g.addDependency(from, to)
+				}
+			}
+		}
+	}
+
+	return g, nil
+}
+
+// pnpmSnapshotID gets the id of the snapshot a dependency resolves to, returning ""
+// if the dependency is not a snapshot (e.g. a linked workspace package)
+func pnpmSnapshotID(name, version string) string {
+	if strings.HasPrefix(version, "link:") {
+		return ""
+	}
+
+	// aliased dependencies have their version as the id of the actual package (e.g. "string-width@4.2.3")
+	peerless, _, _ := strings.Cut(version, "(")
+	if name, _, ok := npmname.Split(peerless); ok && !strings.Contains(name, ":") {
+		return version
+	}
+
+	return name + "@" + version
+}
@@ -0,0 +1,103 @@
+package depgraph
+
+import (
+	"os"
+	"strings"
+
+	"github.com/google/osv-scanner/v2/internal/utility/npmname"
+	"gopkg.in/yaml.v3"
+)
+
+type yarnBerryPackage struct {
+	Version              string            `yaml:"version"`
+	Resolution           string            `yaml:"resolution"`
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+}
+
+// pkg returns the package that was resolved, naming it after the resolution to handle
+// aliases (e.g. "string-width-cjs@npm:string-width@^4.2.0") and returning false if it
+// is part of the project itself rather than having been installed
+func (p yarnBerryPackage) pkg() (Package, bool) {
+	name, reference, ok := npmname.Split(p.Resolution)
+	if !ok {
+		return Package{}, false
+	}
+
+	for _, protocol := range []string{"workspace:", "link:", "portal:"} {
+		if strings.HasPrefix(reference, protocol) {
+			return Package{}, false
+		}
+	}
+
+	return Package{Name: name, Version: p.Version}, true
+}
+
+// parseYarnLock reads the graph of a Yarn v2+ ("Berry") yarn.lock, which records the
+// dependencies of both workspace packages and installed packages by their descriptor.
+//
+// Classic yarn.lock files do not record which packages the project directly depends on,
+// so their graph cannot be read from the lockfile alone.
+func parseYarnLock(path string) (*Graph, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var lockfile map[string]yarnBerryPackage
+	if err := yaml.Unmarshal(b, &lockfile); err != nil {
+		return nil, ErrUnsupported
+	}
+	if _, ok := lockfile["__metadata"]; !ok {
+		return nil, ErrUnsupported
+	}
+	delete(lockfile, "__metadata")
+
+	// each entry can be for multiple descriptors that resolved to the same package,
+	// e.g. "lodash@npm:^4.17.20, lodash@npm:^4.17.21"
+	byDescriptor := make(map[string]yarnBerryPackage)
+	for descriptors, entry := range lockfile {
+		for _, descriptor := range strings.Split(descriptors, ", ") {
+			byDescriptor[descriptor] = entry
+		}
+	}
+
+	resolveDep := func(name, rng string) (Package, bool) {
+		entry, ok := byDescriptor[name+"@"+rng]
+		if !ok {
+			// older lockfiles leave out the default "npm:" protocol from the ranges of dependencies
+			entry, ok = byDescriptor[name+"@npm:"+rng]
+		}
+		if !ok {
+			return Package{}, false
+		}
+
+		return entry.pkg()
+	}
+
+	g := newGraph()
+	for _, entry := range lockfile {
+		from, installed := entry.pkg()
+		isWorkspace := strings.Contains(entry.Resolution, "@workspace:")
+		if !installed && !isWorkspace {
+			continue
+		}
+
+		for _, deps := range []map[string]string{entry.Dependencies, entry.OptionalDependencies} {
+			for name, rng := range deps {
+				to, ok := resolveDep(name, rng)
+				if !ok {
+					continue
+				}
+
+				if isWorkspace {
+					g.addDirect(to)
+				} else {
+					g.addDependency(from, to)
+				}
+			}
+		}
+	}
+
+	return g, nil
+}
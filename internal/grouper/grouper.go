@@ -22,25 +22,40 @@ func hasAliasIntersection(v1, v2 IDAliases) bool {
 
 // Group groups vulnerabilities by aliases.
 func Group(vulns []IDAliases) []models.GroupInfo {
-	// Mapping of `vulns` index to a group ID. A group ID is just another index in the `vulns` slice.
-	groups := make([]int, len(vulns))
+	// Mapping of `vulns` index to the index of another vulnerability in the same group,
+	// with the smallest index of each group mapping to itself and being used as the group ID.
+	parents := make([]int, len(vulns))
 
 	// Initially make every vulnerability its own group.
 	for i := range vulns {
-		groups[i] = i
+		parents[i] = i
+	}
+
+	find := func(i int) int {
+		for parents[i] != i {
+			i = parents[i]
+		}
+
+		return i
 	}
 
-	// Do a pair-wise (n^2) comparison and merge all intersecting vulns.
+	// Do a pair-wise (n^2) comparison and merge all intersecting vulns, including vulns that
+	// are only related through others (e.g. a GHSA and a PYSEC that each alias the same CVE).
 	for i := range vulns {
 		for j := i + 1; j < len(vulns); j++ {
 			if hasAliasIntersection(vulns[i], vulns[j]) {
 				// Merge the two groups. Use the smaller index as the representative ID.
-				groups[i] = min(groups[i], groups[j])
-				groups[j] = groups[i]
+				gi, gj := find(i), find(j)
+				parents[max(gi, gj)] = min(gi, gj)
 			}
 		}
 	}
 
+	groups := make([]int, len(vulns))
+	for i := range vulns {
+		groups[i] = find(i)
+	}
+
 	// Extract groups into the final result structure.
 	extractedGroups := map[int][]string{}
 	extractedAliases := map[int][]string{}
@@ -55,8 +70,10 @@ func Group(vulns []IDAliases) []models.GroupInfo {
 
 	result := make([]models.GroupInfo, 0, len(sortedKeys))
 	for _, key := range sortedKeys {
-		// Sort the strings so they are always in the same order
+		// Sort the strings so they are always in the same order, with the canonical ID first,
+		// and dedup the IDs of vulnerabilities that were returned more than once
 		slices.SortFunc(extractedGroups[key], identifiers.IDSortFunc)
+		extractedGroups[key] = slices.Compact(extractedGroups[key])
 
 		// Add IDs to aliases
 		extractedAliases[key] = append(extractedAliases[key], extractedGroups[key]...)
@@ -72,6 +72,23 @@ func TestGroup(t *testing.T) {
 	v10 := grouper.IDAliases{
 		ID: "UNRELATED-4",
 	}
+	// Should be grouped transitively, even when only related through later vulns.
+	v11 := grouper.IDAliases{
+		ID:      "OSV-1",
+		Aliases: []string{"CVE-5"},
+	}
+	v12 := grouper.IDAliases{
+		ID: "GHSA-1",
+	}
+	v13 := grouper.IDAliases{
+		ID:      "PYSEC-1",
+		Aliases: []string{"GHSA-1"},
+	}
+	v14 := grouper.IDAliases{
+		ID:      "CVE-5",
+		Aliases: []string{"PYSEC-1"},
+	}
+
 	for _, tc := range []struct {
 		vulns []grouper.IDAliases
 		want  []models.GroupInfo
@@ -130,6 +147,29 @@ func TestGroup(t *testing.T) {
 				},
 			},
 		},
+		{
+			vulns: []grouper.IDAliases{
+				v11, v12, v13, v14,
+			},
+			want: []models.GroupInfo{
+				{
+					IDs:     []string{v14.ID, v11.ID, v13.ID, v12.ID},
+					Aliases: []string{v14.ID, v12.ID, v11.ID, v13.ID},
+				},
+			},
+		},
+		{
+			// the same vulnerability can be returned more than once
+			vulns: []grouper.IDAliases{
+				v1, v2, v1,
+			},
+			want: []models.GroupInfo{
+				{
+					IDs:     []string{v1.ID, v2.ID},
+					Aliases: []string{v1.ID, v2.ID},
+				},
+			},
+		},
 		{
 			vulns: []grouper.IDAliases{
 				v9, v10,
@@ -9,14 +9,19 @@ import (
 	"github.com/google/osv-scalibr/extractor/filesystem/language/golang/gobinary"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/java/archive"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/python/wheelegg"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/rust/cargoauditable"
 	"github.com/google/osv-scalibr/extractor/filesystem/os/apk"
 	"github.com/google/osv-scalibr/extractor/filesystem/os/dpkg"
 	"github.com/google/osv-scalibr/extractor/filesystem/os/rpm"
 	"github.com/google/osv-scalibr/extractor/filesystem/sbom/cdx"
-	"github.com/google/osv-scalibr/extractor/filesystem/sbom/spdx"
+	scalibrspdx "github.com/google/osv-scalibr/extractor/filesystem/sbom/spdx"
 	"github.com/google/osv-scanner/v2/internal/cachedregexp"
 	"github.com/google/osv-scanner/v2/internal/imodels/ecosystem"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/conda/environmentyml"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/javascript/nodemodules"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/python/sitepackages"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/rust/cargotoml"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/sbom/spdx"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/vcs/gitrepo"
 	"github.com/google/osv-scanner/v2/internal/utility/purl"
 	"github.com/google/osv-scanner/v2/internal/utility/semverlike"
@@ -42,11 +47,28 @@ var osExtractors = map[string]struct{}{
 	rpm.Extractor{}.Name():  {},
 }
 
+// rpmEcosystems maps the ID of the OS that rpm packages were installed on to its OSV ecosystem,
+// as Fedora and other distros that use rpm do not have their own ecosystem
+var rpmEcosystems = map[string]osvschema.Ecosystem{
+	"rhel":      osvschema.EcosystemRedHat,
+	"rocky":     osvschema.EcosystemRockyLinux,
+	"almalinux": osvschema.EcosystemAlmaLinux,
+}
+
 var artifactExtractors = map[string]struct{}{
 	nodemodules.Extractor{}.Name(): {},
 	gobinary.Extractor{}.Name():    {},
 	archive.Extractor{}.Name():     {},
 	wheelegg.Extractor{}.Name():    {},
+	cargoauditable.Name:            {},
+	sitepackages.Name:              {},
+}
+
+// manifestExtractors extract the version requirements declared by a manifest rather than
+// the exact versions that have been resolved, so their packages are reported separately
+var manifestExtractors = map[string]struct{}{
+	cargotoml.Extractor{}.Name():      {},
+	environmentyml.Extractor{}.Name(): {},
 }
 
 // PackageInfo provides getter functions for commonly used fields of inventory
@@ -112,6 +134,10 @@ func (pkg *PackageInfo) Ecosystem() ecosystem.Parsed {
 		ecosystemStr = pkg.purlCache.Ecosystem
 	}
 
+	if metadata, ok := pkg.Inventory.Metadata.(*rpm.Metadata); ok && ecosystemStr == "" {
+		ecosystemStr = string(rpmEcosystems[metadata.OSID])
+	}
+
 	// TODO: Maybe cache this parse result
 	eco, err := ecosystem.Parse(ecosystemStr)
 	if err != nil {
@@ -149,6 +175,12 @@ func (pkg *PackageInfo) Version() string {
 		}
 	}
 
+	// RPM versions are compared by their epoch before their version and release,
+	// which the version of the inventory does not include
+	if metadata, ok := pkg.Inventory.Metadata.(*rpm.Metadata); ok && metadata.Epoch > 0 {
+		return fmt.Sprintf("%d:%s", metadata.Epoch, pkg.Inventory.Version)
+	}
+
 	return pkg.Inventory.Version
 }
 
@@ -182,6 +214,8 @@ func (pkg *PackageInfo) SourceType() SourceType {
 		return SourceTypeGit
 	} else if _, ok := artifactExtractors[extractorName]; ok {
 		return SourceTypeArtifact
+	} else if _, ok := manifestExtractors[extractorName]; ok {
+		return SourceTypeManifest
 	}
 
 	return SourceTypeProjectPackage
@@ -195,6 +229,30 @@ func (pkg *PackageInfo) DepGroups() []string {
 	return []string{}
 }
 
+// CPEs returns the CPEs that identify the package in the SBOM it was extracted from, if any
+func (pkg *PackageInfo) CPEs() []string {
+	switch metadata := pkg.Inventory.Metadata.(type) {
+	case *cdx.Metadata:
+		return metadata.CPEs
+	case *scalibrspdx.Metadata:
+		return metadata.CPEs
+	case *spdx.Metadata:
+		return metadata.CPEs
+	}
+
+	return nil
+}
+
+// EditableSource returns the directory that the package was installed from,
+// if it is an editable install in a Python environment
+func (pkg *PackageInfo) EditableSource() string {
+	if metadata, ok := pkg.Inventory.Metadata.(*sitepackages.Metadata); ok {
+		return metadata.EditableSource
+	}
+
+	return ""
+}
+
 func (pkg *PackageInfo) OSPackageName() string {
 	if metadata, ok := pkg.Inventory.Metadata.(*apk.Metadata); ok {
 		return metadata.PackageName
@@ -229,8 +287,14 @@ type PackageScanResult struct {
 	PackageInfo PackageInfo
 	// TODO: Use osvschema.Vulnerability instead
 	Vulnerabilities []*osvschema.Vulnerability
-	Licenses        []models.License
-	LayerDetails    *extractor.LayerDetails
+	// UnaffectedVulnerabilities are the vulnerabilities of the package that do not affect
+	// its version, which are only matched when all vulnerabilities are requested
+	UnaffectedVulnerabilities []*osvschema.Vulnerability
+	Licenses                  []models.License
+	LayerDetails              *extractor.LayerDetails
+	// DirectnessUnknown is set when packages are filtered by whether they are direct dependencies,
+	// but it could not be determined for the package
+	DirectnessUnknown bool
 
 	// TODO(v2):
 	// SourceAnalysis *SourceAnalysis
@@ -248,4 +312,5 @@ const (
 	SourceTypeArtifact
 	SourceTypeSBOM
 	SourceTypeGit
+	SourceTypeManifest
 )
@@ -0,0 +1,105 @@
+package imodels_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/os/rpm"
+	"github.com/google/osv-scalibr/extractor/filesystem/sbom/cdx"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/sbom/spdx"
+)
+
+func TestPackageInfo_RPM(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		metadata      *rpm.Metadata
+		wantVersion   string
+		wantEcosystem string
+	}{
+		{
+			name:          "red hat without an epoch",
+			metadata:      &rpm.Metadata{PackageName: "openssl-libs", OSID: "rhel"},
+			wantVersion:   "3.0.7-27.el9",
+			wantEcosystem: "Red Hat",
+		},
+		{
+			name:          "rocky linux with an epoch",
+			metadata:      &rpm.Metadata{PackageName: "openssl-libs", Epoch: 1, OSID: "rocky"},
+			wantVersion:   "1:3.0.7-27.el9",
+			wantEcosystem: "Rocky Linux",
+		},
+		{
+			name:          "almalinux",
+			metadata:      &rpm.Metadata{PackageName: "openssl-libs", Epoch: 1, OSID: "almalinux"},
+			wantVersion:   "1:3.0.7-27.el9",
+			wantEcosystem: "AlmaLinux",
+		},
+		{
+			name:          "distro without an ecosystem",
+			metadata:      &rpm.Metadata{PackageName: "openssl-libs", Epoch: 1, OSID: "fedora"},
+			wantVersion:   "1:3.0.7-27.el9",
+			wantEcosystem: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pkg := imodels.FromInventory(&extractor.Inventory{
+				Name:      "openssl-libs",
+				Version:   "3.0.7-27.el9",
+				Metadata:  tt.metadata,
+				Extractor: rpm.Extractor{},
+			})
+
+			if got := pkg.Version(); got != tt.wantVersion {
+				t.Errorf("Version() = %q, want %q", got, tt.wantVersion)
+			}
+			if got := pkg.Ecosystem().String(); got != tt.wantEcosystem {
+				t.Errorf("Ecosystem() = %q, want %q", got, tt.wantEcosystem)
+			}
+		})
+	}
+}
+
+func TestPackageInfo_CPEs(t *testing.T) {
+	t.Parallel()
+
+	cpe := "cpe:2.3:a:openssl:openssl:3.0.7:*:*:*:*:*:*:*"
+
+	tests := []struct {
+		name string
+		inv  *extractor.Inventory
+		want []string
+	}{
+		{
+			name: "cyclonedx",
+			inv:  &extractor.Inventory{Name: cpe, Metadata: &cdx.Metadata{CPEs: []string{cpe}}, Extractor: cdx.Extractor{}},
+			want: []string{cpe},
+		},
+		{
+			name: "spdx",
+			inv:  &extractor.Inventory{Name: cpe, Metadata: &spdx.Metadata{CPEs: []string{cpe}}, Extractor: spdx.Extractor{}},
+			want: []string{cpe},
+		},
+		{
+			name: "lockfile",
+			inv:  &extractor.Inventory{Name: "openssl", Metadata: &rpm.Metadata{PackageName: "openssl"}, Extractor: rpm.Extractor{}},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pkg := imodels.FromInventory(tt.inv)
+			if got := pkg.CPEs(); !slices.Equal(got, tt.want) {
+				t.Errorf("CPEs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
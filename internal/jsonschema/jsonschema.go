@@ -0,0 +1,197 @@
+// Package jsonschema generates JSON Schemas that describe how Go types are encoded by encoding/json.
+package jsonschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect of generated schemas
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema, with only the keywords needed to describe Go types
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+type generator struct {
+	defs map[string]*Schema
+}
+
+// For generates the schema of the JSON encoding of values of type T.
+//
+// Named struct types are described in the "$defs" of the schema, using their package
+// and type name (e.g. "models.PackageInfo"). Types with a custom MarshalJSON method are
+// described using their fields, but without any of them being required, as the method
+// could leave out any field.
+func For[T any]() *Schema {
+	g := generator{defs: make(map[string]*Schema)}
+
+	schema := g.schemaOf(reflect.TypeFor[T]())
+	schema.Schema = Draft
+	if len(g.defs) > 0 {
+		schema.Defs = g.defs
+	}
+
+	return schema
+}
+
+func defName(t reflect.Type) string {
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+
+	return pkg + "." + t.Name()
+}
+
+func (g *generator) schemaOf(t reflect.Type) *Schema {
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	// types encoded as text have no structure, even if they are structs
+	if t.Implements(textMarshalerType) && !t.Implements(jsonMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Pointer:
+		return g.schemaOf(t.Elem())
+	case reflect.Slice, reflect.Array:
+		// byte slices are encoded as base64 strings
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string"}
+		}
+
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+
+		name := defName(t)
+		if _, ok := g.defs[name]; !ok {
+			// the definition is added before it is generated in case the type is recursive
+			g.defs[name] = &Schema{}
+			*g.defs[name] = *g.structSchema(t)
+		}
+
+		return &Schema{Ref: "#/$defs/" + name}
+	case reflect.Interface:
+		// any value could be encoded
+		return &Schema{}
+	case reflect.Complex64, reflect.Complex128, reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Invalid:
+		// these cannot be encoded
+	}
+
+	return &Schema{}
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	hasCustomMarshaler := t.Implements(jsonMarshalerType)
+
+	g.addFields(schema, t, hasCustomMarshaler)
+
+	return schema
+}
+
+// addFields adds the fields of the struct t to the schema, including those of
+// embedded structs which are encoded as if they were fields of t
+func (g *generator) addFields(schema *Schema, t reflect.Type, hasCustomMarshaler bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		omitEmpty := strings.Contains(","+opts+",", ",omitempty,")
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(schema, ft, hasCustomMarshaler)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := g.schemaOf(field.Type)
+
+		// values that can be nil are encoded as null, unless they are omitted
+		if !omitEmpty {
+			switch field.Type.Kind() {
+			case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+				prop = nullable(prop)
+			default:
+			}
+		}
+
+		schema.Properties[name] = prop
+
+		if !omitEmpty && !hasCustomMarshaler {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func nullable(schema *Schema) *Schema {
+	switch typ := schema.Type.(type) {
+	case string:
+		schema.Type = []string{typ, "null"}
+
+		return schema
+	case nil:
+		// schemas without a type already allow anything to be encoded
+		if schema.Ref == "" {
+			return schema
+		}
+	}
+
+	return &Schema{AnyOf: []*Schema{schema, {Type: "null"}}}
+}
@@ -0,0 +1,102 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/jsonschema"
+)
+
+type level int
+
+type node struct {
+	Name     string  `json:"name"`
+	Children []*node `json:"children,omitempty"`
+}
+
+type base struct {
+	ID string `json:"id"`
+}
+
+type marshaled struct {
+	Value string `json:"value"`
+}
+
+func (m marshaled) MarshalJSON() ([]byte, error) {
+	if m.Value == "" {
+		return []byte("{}"), nil
+	}
+
+	type raw marshaled
+
+	return json.Marshal(raw(m))
+}
+
+type example struct {
+	base
+
+	Level     level             `json:"level"`
+	Score     float64           `json:"score,omitempty"`
+	Tags      []string          `json:"tags"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Created   time.Time         `json:"created"`
+	Tree      *node             `json:"tree"`
+	Custom    marshaled         `json:"custom"`
+	Anything  any               `json:"anything,omitempty"`
+	Untagged  bool
+	Ignored   string `json:"-"`
+	unexposed string
+}
+
+func TestFor(t *testing.T) {
+	t.Parallel()
+
+	got := jsonschema.For[example]()
+
+	want := &jsonschema.Schema{
+		Schema: jsonschema.Draft,
+		Ref:    "#/$defs/jsonschema_test.example",
+		Defs: map[string]*jsonschema.Schema{
+			"jsonschema_test.example": {
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"id":      {Type: "string"},
+					"level":   {Type: "integer"},
+					"score":   {Type: "number"},
+					"tags":    {Type: []string{"array", "null"}, Items: &jsonschema.Schema{Type: "string"}},
+					"labels":  {Type: "object", AdditionalProperties: &jsonschema.Schema{Type: "string"}},
+					"created": {Type: "string", Format: "date-time"},
+					"tree": {AnyOf: []*jsonschema.Schema{
+						{Ref: "#/$defs/jsonschema_test.node"},
+						{Type: "null"},
+					}},
+					"custom":   {Ref: "#/$defs/jsonschema_test.marshaled"},
+					"anything": {},
+					"Untagged": {Type: "boolean"},
+				},
+				Required: []string{"id", "level", "tags", "created", "tree", "custom", "Untagged"},
+			},
+			"jsonschema_test.node": {
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"name":     {Type: "string"},
+					"children": {Type: "array", Items: &jsonschema.Schema{Ref: "#/$defs/jsonschema_test.node"}},
+				},
+				Required: []string{"name"},
+			},
+			// the fields of types with a custom MarshalJSON might not be encoded
+			"jsonschema_test.marshaled": {
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"value": {Type: "string"},
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("For() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -4,11 +4,16 @@ import "github.com/google/osv-scanner/v2/internal/version"
 
 type ClientConfig struct {
 	MaxConcurrentBatchRequests int
+	// MaxQueriesPerBatchRequest is the number of queries to send in each batch request,
+	// which cannot be more than MaxQueriesPerQueryBatchRequest
+	MaxQueriesPerBatchRequest  int
 	MaxRetryAttempts           int
 	JitterMultiplier           float64
 	BackoffDurationExponential float64
 	BackoffDurationMultiplier  float64
 	UserAgent                  string
+	// Headers are additional headers to send with each request, such as for authenticating with a mirror
+	Headers map[string]string
 }
 
 // DefaultConfig make a default client config
@@ -20,5 +25,6 @@ func DefaultConfig() ClientConfig {
 		BackoffDurationMultiplier:  1,
 		UserAgent:                  "osv-scanner_scan/" + version.OSVVersion,
 		MaxConcurrentBatchRequests: 10,
+		MaxQueriesPerBatchRequest:  MaxQueriesPerQueryBatchRequest,
 	}
 }
@@ -7,11 +7,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"math"
 	"math/rand/v2"
 	"net/http"
+	"strconv"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/utility/backoff"
 	"github.com/ossf/osv-schema/bindings/go/osvschema"
 	"golang.org/x/sync/errgroup"
 )
@@ -24,9 +29,12 @@ const (
 	// DetermineVersionEndpoint is the URL for posting determineversion queries to OSV.
 	DetermineVersionEndpoint = "/v1experimental/determineversion"
 
-	// MaxQueriesPerQueryBatchRequest is a limit set in osv.dev's API, so is not configurable
+	// MaxQueriesPerQueryBatchRequest is a limit set in osv.dev's API, so batches cannot be configured to be any larger
 	MaxQueriesPerQueryBatchRequest = 1000
 
+	// maxRetryAfter is the longest that a Retry-After header will be respected for
+	maxRetryAfter = time.Minute
+
 	DefaultBaseURL = "https://api.osv.dev"
 )
 
@@ -45,16 +53,24 @@ func DefaultClient() *OSVClient {
 	}
 }
 
+// setHeaders sets the user agent and any custom headers (such as for authentication) on the request
+func (c *OSVClient) setHeaders(req *http.Request) {
+	if c.Config.UserAgent != "" {
+		req.Header.Set("User-Agent", c.Config.UserAgent)
+	}
+	for name, value := range c.Config.Headers {
+		req.Header.Set(name, value)
+	}
+}
+
 // GetVulnByID is an interface to this endpoint: https://google.github.io/osv.dev/get-v1-vulns/
 func (c *OSVClient) GetVulnByID(ctx context.Context, id string) (*osvschema.Vulnerability, error) {
-	resp, err := c.makeRetryRequest(func(client *http.Client) (*http.Response, error) {
+	resp, err := c.makeRetryRequest(ctx, func(client *http.Client) (*http.Response, error) {
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseHostURL+GetEndpoint+"/"+id, nil)
 		if err != nil {
 			return nil, err
 		}
-		if c.Config.UserAgent != "" {
-			req.Header.Set("User-Agent", c.Config.UserAgent)
-		}
+		c.setHeaders(req)
 
 		return client.Do(req)
 	})
@@ -82,9 +98,16 @@ func (c *OSVClient) GetVulnByID(ctx context.Context, id string) (*osvschema.Vuln
 // See if next_page_token field in the response is fully filled out to determine if there are extra pages remaining
 func (c *OSVClient) QueryBatch(ctx context.Context, queries []*Query) (*BatchedResponse, error) {
 	// API has a limit of how many queries are in one batch
-	queryChunks := chunkBy(queries, MaxQueriesPerQueryBatchRequest)
+	batchSize := c.Config.MaxQueriesPerBatchRequest
+	if batchSize <= 0 || batchSize > MaxQueriesPerQueryBatchRequest {
+		batchSize = MaxQueriesPerQueryBatchRequest
+	}
+	queryChunks := chunkBy(queries, batchSize)
 	totalOsvRespBatched := make([][]MinimalResponse, len(queryChunks))
 
+	var completed atomic.Int64
+	defer cmdlogger.ClearProgress()
+
 	g, errGrpCtx := errgroup.WithContext(ctx)
 	g.SetLimit(c.Config.MaxConcurrentBatchRequests)
 	for batchIndex, queries := range queryChunks {
@@ -100,7 +123,7 @@ func (c *OSVClient) QueryBatch(ctx context.Context, queries []*Query) (*BatchedR
 				return nil
 			}
 
-			resp, err := c.makeRetryRequest(func(client *http.Client) (*http.Response, error) {
+			resp, err := c.makeRetryRequest(errGrpCtx, func(client *http.Client) (*http.Response, error) {
 				// Make sure request buffer is inside retry, if outside
 				// http request would finish the buffer, and retried requests would be empty
 				requestBuf := bytes.NewBuffer(requestBytes)
@@ -109,9 +132,7 @@ func (c *OSVClient) QueryBatch(ctx context.Context, queries []*Query) (*BatchedR
 					return nil, err
 				}
 				req.Header.Set("Content-Type", "application/json")
-				if c.Config.UserAgent != "" {
-					req.Header.Set("User-Agent", c.Config.UserAgent)
-				}
+				c.setHeaders(req)
 
 				return client.Do(req)
 			})
@@ -130,6 +151,9 @@ func (c *OSVClient) QueryBatch(ctx context.Context, queries []*Query) (*BatchedR
 			// Store batch results in the corresponding index to maintain original query order.
 			totalOsvRespBatched[batchIndex] = osvResp.Results
 
+			remaining := len(queryChunks) - int(completed.Add(1))
+			cmdlogger.Progress(fmt.Sprintf("Querying OSV: %d of %d batches remaining", remaining, len(queryChunks)))
+
 			return nil
 		})
 	}
@@ -159,7 +183,7 @@ func (c *OSVClient) Query(ctx context.Context, query *Query) (*Response, error)
 		return nil, err
 	}
 
-	resp, err := c.makeRetryRequest(func(client *http.Client) (*http.Response, error) {
+	resp, err := c.makeRetryRequest(ctx, func(client *http.Client) (*http.Response, error) {
 		requestBuf := bytes.NewBuffer(requestBytes)
 		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseHostURL+QueryEndpoint, requestBuf)
 		if err != nil {
@@ -167,9 +191,7 @@ func (c *OSVClient) Query(ctx context.Context, query *Query) (*Response, error)
 		}
 
 		req.Header.Set("Content-Type", "application/json")
-		if c.Config.UserAgent != "" {
-			req.Header.Set("User-Agent", c.Config.UserAgent)
-		}
+		c.setHeaders(req)
 
 		return client.Do(req)
 	})
@@ -195,7 +217,7 @@ func (c *OSVClient) ExperimentalDetermineVersion(ctx context.Context, query *Det
 		return nil, err
 	}
 
-	resp, err := c.makeRetryRequest(func(client *http.Client) (*http.Response, error) {
+	resp, err := c.makeRetryRequest(ctx, func(client *http.Client) (*http.Response, error) {
 		// Make sure request buffer is inside retry, if outside
 		// http request would finish the buffer, and retried requests would be empty
 		requestBuf := bytes.NewBuffer(requestBytes)
@@ -204,9 +226,7 @@ func (c *OSVClient) ExperimentalDetermineVersion(ctx context.Context, query *Det
 			return nil, err
 		}
 		req.Header.Set("Content-Type", "application/json")
-		if c.Config.UserAgent != "" {
-			req.Header.Set("User-Agent", c.Config.UserAgent)
-		}
+		c.setHeaders(req)
 
 		return client.Do(req)
 	})
@@ -226,24 +246,36 @@ func (c *OSVClient) ExperimentalDetermineVersion(ctx context.Context, query *Det
 }
 
 // makeRetryRequest will return an error on both network errors, and if the response is not 200
-func (c *OSVClient) makeRetryRequest(action func(client *http.Client) (*http.Response, error)) (*http.Response, error) {
+//
+// The delay between attempts is cut short if ctx is done, in which case the error of ctx is returned
+func (c *OSVClient) makeRetryRequest(ctx context.Context, action func(client *http.Client) (*http.Response, error)) (*http.Response, error) {
 	var resp *http.Response
 	var err error
 	var lastErr error
+	var retryAfter time.Duration
 
 	for i := range c.Config.MaxRetryAttempts {
 		// rand is initialized with a random number (since go1.20), and is also safe to use concurrently
 		// we do not need to use a cryptographically secure random jitter, this is just to spread out the retry requests
 		// #nosec G404
 		jitterAmount := (rand.Float64() * float64(c.Config.JitterMultiplier) * float64(i))
-		time.Sleep(
-			time.Duration(math.Pow(float64(i), c.Config.BackoffDurationExponential)*c.Config.BackoffDurationMultiplier*1000)*time.Millisecond +
-				time.Duration(jitterAmount*1000)*time.Millisecond)
+		delay := time.Duration(math.Pow(float64(i), c.Config.BackoffDurationExponential)*c.Config.BackoffDurationMultiplier*1000)*time.Millisecond +
+			time.Duration(jitterAmount*1000)*time.Millisecond
+		// If we have been told how long to wait by the server, wait at least that long
+		if err := backoff.Sleep(ctx, max(delay, retryAfter)); err != nil {
+			return nil, err
+		}
+		retryAfter = 0
 
+		start := time.Now()
 		resp, err = action(c.HTTPClient)
 
-		// Don't retry, since deadline has already been exceeded
-		if errors.Is(err, context.DeadlineExceeded) {
+		if err == nil && resp.Request != nil {
+			slog.Debug(fmt.Sprintf("%s %s responded with %q in %s (attempt %d)", resp.Request.Method, resp.Request.URL.Path, resp.Status, time.Since(start).Round(time.Millisecond), i+1))
+		}
+
+		// Don't retry, since deadline has already been exceeded or the request was cancelled
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
 			return nil, err
 		}
 
@@ -267,6 +299,7 @@ func (c *OSVClient) makeRetryRequest(action func(client *http.Client) (*http.Res
 
 		// Special case for too many requests, it should try again after a delay.
 		if resp.StatusCode == http.StatusTooManyRequests {
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
 			lastErr = fmt.Errorf("attempt %d: too many requests: status=%q body=%s", i+1, resp.Status, errBody)
 			continue
 		}
@@ -277,13 +310,31 @@ func (c *OSVClient) makeRetryRequest(action func(client *http.Client) (*http.Res
 			return nil, fmt.Errorf("client error: status=%q body=%s", resp.Status, errBody)
 		}
 
-		// Most likely a 500 >= error
+		// Most likely a 500 >= error, which can also come with a Retry-After header
+		// if the server is temporarily unavailable
+		if resp.StatusCode == http.StatusServiceUnavailable {
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		}
 		lastErr = fmt.Errorf("server error: status=%q body=%s", resp.Status, errBody)
 	}
 
 	return nil, fmt.Errorf("max retries exceeded: %w", lastErr)
 }
 
+// parseRetryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or an HTTP date, returning 0 if it is missing or invalid
+func parseRetryAfter(value string) time.Duration {
+	var retryAfter time.Duration
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		retryAfter = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		retryAfter = time.Until(date)
+	}
+
+	return min(max(retryAfter, 0), maxRetryAfter)
+}
+
 // From: https://stackoverflow.com/a/72408490
 func chunkBy[T any](items []T, chunkSize int) [][]T {
 	chunks := make([][]T, 0, (len(items)/chunkSize)+1)
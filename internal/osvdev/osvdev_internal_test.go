@@ -1,10 +1,15 @@
 package osvdev
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -35,6 +40,11 @@ func TestOSVClient_makeRetryRequest(t *testing.T) {
 			},
 			wantAttempts: 1,
 		},
+		{
+			name:         "too many requests then success",
+			statusCodes:  []int{http.StatusTooManyRequests, http.StatusOK},
+			wantAttempts: 2,
+		},
 		{
 			name:         "server error then success",
 			statusCodes:  []int{http.StatusInternalServerError, http.StatusOK},
@@ -77,7 +87,7 @@ func TestOSVClient_makeRetryRequest(t *testing.T) {
 			}))
 			defer server.Close()
 
-			resp, err := client.makeRetryRequest(func(hc *http.Client) (*http.Response, error) {
+			resp, err := client.makeRetryRequest(context.Background(), func(hc *http.Client) (*http.Response, error) {
 				//nolint:noctx // because this is test code
 				return hc.Get(server.URL)
 			})
@@ -107,3 +117,188 @@ func TestOSVClient_makeRetryRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestOSVClient_CustomBaseURLAndHeaders(t *testing.T) {
+	t.Parallel()
+
+	var paths []string
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("got Authorization header %q, want %q", got, "Bearer secret")
+		}
+		if got := r.Header.Get("User-Agent"); got != "osv-scanner-test" {
+			t.Errorf("got User-Agent header %q, want %q", got, "osv-scanner-test")
+		}
+
+		switch r.URL.Path {
+		case GetEndpoint + "/GHSA-1":
+			_, _ = w.Write([]byte(`{"id": "GHSA-1"}`))
+		case QueryBatchEndpoint:
+			_, _ = w.Write([]byte(`{"results": [{"vulns": [{"id": "GHSA-1"}]}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := DefaultClient()
+	client.BaseHostURL = server.URL
+	client.Config.UserAgent = "osv-scanner-test"
+	client.Config.Headers = map[string]string{"Authorization": "Bearer secret"}
+
+	vuln, err := client.GetVulnByID(context.Background(), "GHSA-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if vuln.ID != "GHSA-1" {
+		t.Errorf("got vulnerability %q, want %q", vuln.ID, "GHSA-1")
+	}
+
+	resp, err := client.QueryBatch(context.Background(), []*Query{{Commit: "abc"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Results) != 1 || len(resp.Results[0].Vulns) != 1 {
+		t.Errorf("got unexpected batch response: %v", resp)
+	}
+
+	want := []string{"GET " + GetEndpoint + "/GHSA-1", "POST " + QueryBatchEndpoint}
+	if diff := cmp.Diff(want, paths); diff != "" {
+		t.Errorf("unexpected requests (-want +got):\n%s", diff)
+	}
+}
+
+func TestOSVClient_QueryBatch_BatchSize(t *testing.T) {
+	t.Parallel()
+
+	var batchSizes []int
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var query BatchedQuery
+		if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+
+		mu.Lock()
+		batchSizes = append(batchSizes, len(query.Queries))
+		mu.Unlock()
+
+		// echo back the commit of each query as a vulnerability, so the order can be checked
+		var resp BatchedResponse
+		for _, q := range query.Queries {
+			resp.Results = append(resp.Results, MinimalResponse{Vulns: []MinimalVulnerability{{ID: q.Commit}}})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := DefaultClient()
+	client.BaseHostURL = server.URL
+	client.Config.MaxQueriesPerBatchRequest = 2
+	client.Config.MaxConcurrentBatchRequests = 1
+
+	var queries []*Query
+	for i := range 5 {
+		queries = append(queries, &Query{Commit: strconv.Itoa(i)})
+	}
+
+	resp, err := client.QueryBatch(context.Background(), queries)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff([]int{2, 2, 1}, batchSizes); diff != "" {
+		t.Errorf("unexpected batch sizes (-want +got):\n%s", diff)
+	}
+
+	var ids []string
+	for _, result := range resp.Results {
+		ids = append(ids, result.Vulns[0].ID)
+	}
+	if diff := cmp.Diff([]string{"0", "1", "2", "3", "4"}, ids); diff != "" {
+		t.Errorf("unexpected results (-want +got):\n%s", diff)
+	}
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "missing", value: "", want: 0},
+		{name: "invalid", value: "soon", want: 0},
+		{name: "seconds", value: "5", want: 5 * time.Second},
+		{name: "negative seconds", value: "-5", want: 0},
+		{name: "too long", value: "3600", want: maxRetryAfter},
+		{name: "date in the past", value: "Wed, 21 Oct 2015 07:28:00 GMT", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := parseRetryAfter(tt.value); got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseRetryAfter_FutureDate(t *testing.T) {
+	t.Parallel()
+
+	got := parseRetryAfter(time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat))
+
+	// the date only has a precision of seconds
+	if got < 28*time.Second || got > 30*time.Second {
+		t.Errorf("parseRetryAfter() = %v, want about 30s", got)
+	}
+}
+
+func TestOSVClient_makeRetryRequest_RetryAfterCancelled(t *testing.T) {
+	t.Parallel()
+
+	client := DefaultClient()
+	client.Config.JitterMultiplier = 0
+	client.Config.BackoffDurationMultiplier = 0
+
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.makeRetryRequest(ctx, func(hc *http.Client) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return hc.Do(req)
+	})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("makeRetryRequest() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if attempts != 1 {
+		t.Errorf("got %d attempts, want 1", attempts)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("makeRetryRequest() waited %v after the context was done", elapsed)
+	}
+}
@@ -23,3 +23,17 @@ func PrintCycloneDXResults(vulnResult *models.VulnerabilityResults, cycloneDXVer
 
 	return errors.Join(err, errors.Join(errs...))
 }
+
+// PrintCycloneDXVEXResults writes results to the provided writer as a CycloneDX 1.5 SBOM of the packages,
+// with their vulnerabilities (including those that were ignored) as VEX statements
+func PrintCycloneDXVEXResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	resultsByPurl, errs := purl.Group(vulnResult.Results)
+
+	bom := sbom.ToCycloneDX15VEXBom(resultsByPurl)
+	encoder := cyclonedx.NewBOMEncoder(outputWriter, cyclonedx.BOMFileFormatJSON)
+	encoder.SetPretty(true)
+
+	err := encoder.Encode(bom)
+
+	return errors.Join(err, errors.Join(errs...))
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/google/osv-scanner/v2/internal/output"
 	"github.com/google/osv-scanner/v2/internal/testutility"
 	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
 )
 
 func TestPrintCycloneDX14Results_WithVulnerabilities(t *testing.T) {
@@ -110,3 +111,75 @@ func TestPrintCycloneDX15Results_WithMixedIssues(t *testing.T) {
 		testutility.NewSnapshot().MatchText(t, outputWriter.String())
 	})
 }
+
+func TestPrintCycloneDXVEXResults_WithVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	testOutputWithVulnerabilities(t, func(t *testing.T, args outputTestCaseArgs) {
+		t.Helper()
+
+		outputWriter := &bytes.Buffer{}
+		err := output.PrintCycloneDXVEXResults(args.vulnResult, outputWriter)
+
+		if err != nil {
+			t.Errorf("%v", err)
+		}
+
+		testutility.NewSnapshot().MatchText(t, outputWriter.String())
+	})
+}
+
+func TestPrintCycloneDXVEXResults_WithAnalysis(t *testing.T) {
+	t.Parallel()
+
+	ignored := models.IgnoredVulnerability{
+		Vulnerability: osvschema.Vulnerability{ID: "GHSA-3"},
+		Reason:        "Only used by our build tooling",
+		State:         "not_affected",
+		Justification: "requires_environment",
+	}
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "path/to/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+						Vulnerabilities: []osvschema.Vulnerability{{ID: "GHSA-1"}, {ID: "GHSA-2"}},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"GHSA-1"}},
+							{
+								IDs:                  []string{"GHSA-2"},
+								ExperimentalAnalysis: map[string]models.AnalysisInfo{"GHSA-2": {Called: false}},
+							},
+						},
+						ExperimentalIgnoredVulnerabilities: []models.IgnoredVulnerability{ignored},
+					},
+					{
+						Package:                            models.PackageInfo{Name: "minimist", Version: "1.2.5", Ecosystem: "npm"},
+						Vulnerabilities:                    []osvschema.Vulnerability{{ID: "GHSA-3"}},
+						Groups:                             []models.GroupInfo{{IDs: []string{"GHSA-3"}}},
+						ExperimentalIgnoredVulnerabilities: []models.IgnoredVulnerability{},
+					},
+					{
+						Package:                            models.PackageInfo{Name: "chalk", Version: "4.1.2", Ecosystem: "npm"},
+						ExperimentalIgnoredVulnerabilities: []models.IgnoredVulnerability{ignored},
+					},
+					{
+						Package: models.PackageInfo{Name: "semver", Version: "7.6.0", Ecosystem: "npm"},
+					},
+				},
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	err := output.PrintCycloneDXVEXResults(vulnResult, outputWriter)
+
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+
+	testutility.NewSnapshot().MatchText(t, outputWriter.String())
+}
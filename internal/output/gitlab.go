@@ -0,0 +1,327 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/osv-scanner/v2/internal/identifiers"
+	"github.com/google/osv-scanner/v2/internal/version"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/google/uuid"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// gitLabSchemaVersion is the version of the GitLab security report schema that reports conform to
+// https://gitlab.com/gitlab-org/security-products/security-report-schemas
+const gitLabSchemaVersion = "15.0.7"
+
+// gitLabTimeFormat is the format of timestamps in the scan block of the report
+const gitLabTimeFormat = "2006-01-02T15:04:05"
+
+type gitLabReport struct {
+	Version         string                 `json:"version"`
+	Vulnerabilities []gitLabVulnerability  `json:"vulnerabilities"`
+	DependencyFiles []gitLabDependencyFile `json:"dependency_files"`
+	Scan            gitLabScan             `json:"scan"`
+}
+
+type gitLabVulnerability struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Severity    string             `json:"severity"`
+	Solution    string             `json:"solution,omitempty"`
+	Identifiers []gitLabIdentifier `json:"identifiers"`
+	Links       []gitLabLink       `json:"links,omitempty"`
+	Location    gitLabLocation     `json:"location"`
+}
+
+type gitLabIdentifier struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	URL   string `json:"url,omitempty"`
+}
+
+type gitLabLink struct {
+	URL string `json:"url"`
+}
+
+type gitLabLocation struct {
+	File       string           `json:"file"`
+	Dependency gitLabDependency `json:"dependency"`
+}
+
+type gitLabDependency struct {
+	Package gitLabPackage `json:"package"`
+	Version string        `json:"version"`
+}
+
+type gitLabPackage struct {
+	Name string `json:"name"`
+}
+
+type gitLabDependencyFile struct {
+	Path           string             `json:"path"`
+	PackageManager string             `json:"package_manager"`
+	Dependencies   []gitLabDependency `json:"dependencies"`
+}
+
+type gitLabVendor struct {
+	Name string `json:"name"`
+}
+
+type gitLabScanner struct {
+	ID      string       `json:"id"`
+	Name    string       `json:"name"`
+	Version string       `json:"version"`
+	Vendor  gitLabVendor `json:"vendor"`
+}
+
+type gitLabScan struct {
+	Analyzer  gitLabScanner `json:"analyzer"`
+	Scanner   gitLabScanner `json:"scanner"`
+	Type      string        `json:"type"`
+	StartTime string        `json:"start_time"`
+	EndTime   string        `json:"end_time"`
+	Status    string        `json:"status"`
+}
+
+// PrintGitLabReport prints a GitLab dependency scanning report to outputWriter
+func PrintGitLabReport(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	return printGitLabReport(vulnResult, outputWriter, time.Now())
+}
+
+func printGitLabReport(vulnResult *models.VulnerabilityResults, outputWriter io.Writer, now time.Time) error {
+	workingDir := mustGetWorkingDirectory()
+	groupFixedVersions := GroupFixedVersions(vulnResult.Flatten())
+
+	report := gitLabReport{
+		Version:         gitLabSchemaVersion,
+		Vulnerabilities: []gitLabVulnerability{},
+		DependencyFiles: []gitLabDependencyFile{},
+		Scan:            newGitLabScan(now),
+	}
+
+	for _, source := range vulnResult.Results {
+		file := gitLabFilePath(workingDir, source.Source.Path)
+		dependencyFile := gitLabDependencyFile{
+			Path:         file,
+			Dependencies: []gitLabDependency{},
+		}
+
+		for _, pv := range source.Packages {
+			dependency := gitLabDependency{
+				Package: gitLabPackage{Name: pv.Package.Name},
+				Version: pv.Package.Version,
+			}
+			if pv.Package.Commit != "" {
+				dependency.Version = pv.Package.Commit
+			}
+			if dependencyFile.PackageManager == "" {
+				dependencyFile.PackageManager = gitLabPackageManager(pv.Package.Ecosystem)
+			}
+			dependencyFile.Dependencies = append(dependencyFile.Dependencies, dependency)
+
+			for _, group := range pv.Groups {
+				// groups without any IDs are license violations rather than vulnerabilities
+				if len(group.IDs) == 0 {
+					continue
+				}
+				fixedVersions := groupFixedVersions[source.Source.String()+":"+group.IndexString()]
+				report.Vulnerabilities = append(
+					report.Vulnerabilities,
+					newGitLabVulnerability(pv, group, fixedVersions, gitLabLocation{file, dependency}),
+				)
+			}
+		}
+
+		// the package manager is required, even for sources without any known ecosystem
+		if dependencyFile.PackageManager == "" {
+			dependencyFile.PackageManager = source.Source.Type
+		}
+		report.DependencyFiles = append(report.DependencyFiles, dependencyFile)
+	}
+
+	encoder := json.NewEncoder(outputWriter)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
+
+func newGitLabScan(now time.Time) gitLabScan {
+	scanner := gitLabScanner{
+		ID:      "osv-scanner",
+		Name:    "OSV-Scanner",
+		Version: version.OSVVersion,
+		Vendor:  gitLabVendor{Name: "Google"},
+	}
+	timestamp := now.UTC().Format(gitLabTimeFormat)
+
+	return gitLabScan{
+		Analyzer:  scanner,
+		Scanner:   scanner,
+		Type:      "dependency_scanning",
+		StartTime: timestamp,
+		EndTime:   timestamp,
+		Status:    "success",
+	}
+}
+
+func newGitLabVulnerability(
+	pv models.PackageVulns,
+	group models.GroupInfo,
+	fixedVersions []string,
+	location gitLabLocation,
+) gitLabVulnerability {
+	ids := slices.Clone(group.IDs)
+	slices.SortFunc(ids, identifiers.IDSortFunc)
+	displayID := ids[0]
+
+	// Pick the "best" description from the group, in the same way as the SARIF output
+	slices.SortFunc(ids, identifiers.IDSortFuncForDescription)
+	name, description := displayID, ""
+	for _, id := range ids {
+		v := findVulnByID(pv.Vulnerabilities, id)
+		if v == nil {
+			continue
+		}
+		description = v.Details
+		if v.Summary != "" {
+			name = fmt.Sprintf("%s: %s", displayID, v.Summary)
+			break
+		}
+	}
+
+	aliases := slices.Clone(group.Aliases)
+	if len(aliases) == 0 {
+		aliases = slices.Clone(group.IDs)
+	}
+	slices.SortFunc(aliases, identifiers.IDSortFunc)
+	ids = slices.Clone(group.IDs)
+	slices.Sort(ids)
+
+	gitLabIdentifiers := make([]gitLabIdentifier, 0, len(aliases))
+	links := make([]gitLabLink, 0, len(ids))
+	for _, id := range aliases {
+		gitLabIdentifiers = append(gitLabIdentifiers, gitLabIdentifier{
+			Type:  gitLabIdentifierType(id),
+			Name:  id,
+			Value: id,
+			URL:   "https://osv.dev/" + id,
+		})
+	}
+	for _, id := range ids {
+		links = append(links, gitLabLink{URL: "https://osv.dev/" + id})
+	}
+
+	if group.IsKnownExploited() {
+		name = "[" + knownExploitedLabel + "] " + name
+		links = append(links, gitLabLink{URL: "https://www.cisa.gov/known-exploited-vulnerabilities-catalog?search_api_fulltext=" + group.ExperimentalKEV.CVE})
+	}
+
+	solution := ""
+	if len(fixedVersions) > 0 {
+		solution = fmt.Sprintf("Upgrade %s to version %s or later", pv.Package.Name, strings.Join(fixedVersions, ", "))
+	}
+
+	// the id of a finding must be stable across scans so that GitLab can track it
+	key := location.File + ":" + pv.Package.Name + "@" + location.Dependency.Version + ":" + group.IndexString()
+
+	return gitLabVulnerability{
+		ID:          uuid.NewSHA1(uuid.NameSpaceURL, []byte(key)).String(),
+		Name:        name,
+		Description: description,
+		Severity:    gitLabSeverity(group.MaxSeverity),
+		Solution:    solution,
+		Identifiers: gitLabIdentifiers,
+		Links:       links,
+		Location:    location,
+	}
+}
+
+func findVulnByID(vs []osvschema.Vulnerability, id string) *osvschema.Vulnerability {
+	for i := range vs {
+		if vs[i].ID == id {
+			return &vs[i]
+		}
+	}
+
+	return nil
+}
+
+// gitLabFilePath gets the path of a source relative to the working directory, which is
+// expected to be the root of the project being scanned as GitLab requires
+func gitLabFilePath(workingDir string, path string) string {
+	rel, err := filepath.Rel(workingDir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = path
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// gitLabIdentifierType gets the type of identifier based on the prefix of the ID
+// (e.g. "cve" for CVE-2021-3121, and "ghsa" for GHSA-c3h9-896r-86jm)
+func gitLabIdentifierType(id string) string {
+	prefix, _, found := strings.Cut(id, "-")
+	if !found {
+		return "osv"
+	}
+
+	return strings.ToLower(prefix)
+}
+
+// gitLabSeverity maps a CVSS score to a GitLab severity level using the
+// qualitative severity rating scale of CVSS
+func gitLabSeverity(maxSeverity string) string {
+	score, err := strconv.ParseFloat(maxSeverity, 64)
+	if err != nil || score < 0 {
+		return "Unknown"
+	}
+
+	switch {
+	case score == 0:
+		return "Info"
+	case score < 4:
+		return "Low"
+	case score < 7:
+		return "Medium"
+	case score < 9:
+		return "High"
+	default:
+		return "Critical"
+	}
+}
+
+// gitLabPackageManager gets the name of the package manager that GitLab uses for an ecosystem
+func gitLabPackageManager(ecosystem string) string {
+	switch osvschema.Ecosystem(ecosystem) {
+	case osvschema.EcosystemNPM:
+		return "npm"
+	case osvschema.EcosystemPyPI:
+		return "pip"
+	case osvschema.EcosystemGo:
+		return "go"
+	case osvschema.EcosystemCratesIO:
+		return "cargo"
+	case osvschema.EcosystemMaven:
+		return "maven"
+	case osvschema.EcosystemPackagist:
+		return "composer"
+	case osvschema.EcosystemRubyGems:
+		return "bundler"
+	case osvschema.EcosystemNuGet:
+		return "nuget"
+	case osvschema.EcosystemPub:
+		return "pub"
+	}
+
+	return strings.ToLower(ecosystem)
+}
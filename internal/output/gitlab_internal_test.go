@@ -0,0 +1,159 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/osv-scanner/v2/internal/testutility"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+func Test_printGitLabReport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args models.VulnerabilityResults
+		want testutility.Snapshot
+	}{
+		{
+			name: "no sources",
+			args: models.VulnerabilityResults{Results: []models.PackageSource{}},
+			want: testutility.NewSnapshot(),
+		},
+		{
+			name: "vulnerabilities",
+			args: testutility.LoadJSONFixtureWithWindowsReplacements[models.VulnerabilityResults](t,
+				"fixtures/test-vuln-results-a.json",
+				map[string]string{
+					"/path/to/sub-rust-project/Cargo.lock": "D:\\\\path\\\\to\\\\sub-rust-project\\\\Cargo.lock",
+					"/path/to/go.mod":                      "D:\\\\path\\\\to\\\\go.mod",
+				},
+			),
+			want: testutility.NewSnapshot().WithWindowsReplacements(
+				map[string]string{
+					"D:/path/to": "/path/to",
+				},
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			bufOut := bytes.Buffer{}
+			err := printGitLabReport(&tt.args, &bufOut, time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC))
+			if err != nil {
+				t.Errorf("Error writing GitLab output: %s", err)
+			}
+			tt.want.MatchText(t, bufOut.String())
+		})
+	}
+}
+
+// Test_printGitLabReport_Schema checks that the report has the fields that are
+// required by the GitLab dependency scanning report schema, with valid values
+func Test_printGitLabReport_Schema(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := testutility.LoadJSONFixture[models.VulnerabilityResults](t, "fixtures/test-vuln-results-a.json")
+
+	bufOut := bytes.Buffer{}
+	if err := printGitLabReport(&vulnResult, &bufOut, time.Now()); err != nil {
+		t.Fatalf("Error writing GitLab output: %s", err)
+	}
+
+	var report map[string]any
+	if err := json.Unmarshal(bufOut.Bytes(), &report); err != nil {
+		t.Fatalf("GitLab output is not valid JSON: %s", err)
+	}
+
+	requireFields(t, "report", report, "version", "vulnerabilities", "dependency_files", "scan")
+
+	scan := report["scan"].(map[string]any)
+	requireFields(t, "scan", scan, "analyzer", "scanner", "type", "start_time", "end_time", "status")
+	requireFields(t, "scan.analyzer", scan["analyzer"].(map[string]any), "id", "name", "version", "vendor")
+	requireFields(t, "scan.scanner", scan["scanner"].(map[string]any), "id", "name", "version", "vendor")
+	if scan["type"] != "dependency_scanning" {
+		t.Errorf("scan.type is %q, expected \"dependency_scanning\"", scan["type"])
+	}
+	for _, field := range []string{"start_time", "end_time"} {
+		if _, err := time.Parse(gitLabTimeFormat, scan[field].(string)); err != nil {
+			t.Errorf("scan.%s is not in the expected format: %s", field, err)
+		}
+	}
+
+	vulnerabilities := report["vulnerabilities"].([]any)
+	if len(vulnerabilities) == 0 {
+		t.Fatalf("expected the report to have vulnerabilities")
+	}
+
+	severities := []string{"Info", "Unknown", "Low", "Medium", "High", "Critical"}
+	for _, v := range vulnerabilities {
+		vuln := v.(map[string]any)
+		requireFields(t, "vulnerability", vuln, "id", "identifiers", "location")
+
+		if !slices.Contains(severities, vuln["severity"].(string)) {
+			t.Errorf("vulnerability %s has invalid severity %q", vuln["id"], vuln["severity"])
+		}
+
+		ids := vuln["identifiers"].([]any)
+		if len(ids) == 0 {
+			t.Errorf("vulnerability %s has no identifiers", vuln["id"])
+		}
+		for _, id := range ids {
+			requireFields(t, "vulnerability.identifiers", id.(map[string]any), "type", "name", "value")
+		}
+
+		location := vuln["location"].(map[string]any)
+		requireFields(t, "vulnerability.location", location, "file", "dependency")
+		requireFields(t, "vulnerability.location.dependency", location["dependency"].(map[string]any), "package")
+	}
+
+	for _, f := range report["dependency_files"].([]any) {
+		requireFields(t, "dependency_files", f.(map[string]any), "path", "package_manager", "dependencies")
+	}
+}
+
+func requireFields(t *testing.T, name string, obj map[string]any, fields ...string) {
+	t.Helper()
+
+	for _, field := range fields {
+		v, ok := obj[field]
+		if !ok || v == nil || v == "" {
+			t.Errorf("%s is missing required field %q", name, field)
+		}
+	}
+}
+
+func Test_gitLabSeverity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		maxSeverity string
+		want        string
+	}{
+		{maxSeverity: "", want: "Unknown"},
+		{maxSeverity: "not-a-score", want: "Unknown"},
+		{maxSeverity: "0", want: "Info"},
+		{maxSeverity: "0.1", want: "Low"},
+		{maxSeverity: "3.9", want: "Low"},
+		{maxSeverity: "4", want: "Medium"},
+		{maxSeverity: "6.9", want: "Medium"},
+		{maxSeverity: "7.5", want: "High"},
+		{maxSeverity: "9", want: "Critical"},
+		{maxSeverity: "10", want: "Critical"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.maxSeverity, func(t *testing.T) {
+			t.Parallel()
+
+			if got := gitLabSeverity(tt.maxSeverity); got != tt.want {
+				t.Errorf("gitLabSeverity(%q) = %q, want %q", tt.maxSeverity, got, tt.want)
+			}
+		})
+	}
+}
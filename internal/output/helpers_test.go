@@ -2005,3 +2005,33 @@ func testOutputWithMixedIssues(t *testing.T, run outputTestRunner) {
 		})
 	}
 }
+
+// directnessUnknownResults has a package that was kept by --direct-only as whether
+// it is a direct dependency could not be determined, alongside a direct dependency
+func directnessUnknownResults() *models.VulnerabilityResults {
+	return &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "path/to/gradle.lockfile", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:                       models.PackageInfo{Name: "com.example:unknown", Version: "1.0.0", Ecosystem: "Maven"},
+						ExperimentalDirectnessUnknown: true,
+						Vulnerabilities:               []osvschema.Vulnerability{{ID: "OSV-1"}},
+						Groups:                        []models.GroupInfo{{IDs: []string{"OSV-1"}}},
+					},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "path/to/pom.xml", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "com.example:direct", Version: "2.0.0", Ecosystem: "Maven"},
+						Vulnerabilities: []osvschema.Vulnerability{{ID: "OSV-2"}},
+						Groups:          []models.GroupInfo{{IDs: []string{"OSV-2"}}},
+					},
+				},
+			},
+		},
+	}
+}
@@ -57,6 +57,17 @@ func buildVulnTableEntryArgument(element VulnResult, isHidden bool) VulnTableEnt
 	}
 }
 
+// countEcosystemVulns counts the vulnerabilities across all the sources of an ecosystem
+// that are displayed to users
+func countEcosystemVulns(ecosystem EcosystemResult) int {
+	count := 0
+	for _, source := range ecosystem.Sources {
+		count += source.VulnCount.AnalysisCount.Regular
+	}
+
+	return count
+}
+
 func hasOSResult(ecosystems []EcosystemResult) bool {
 	for _, ecosystem := range ecosystems {
 		if ecosystem.IsOS {
@@ -87,6 +98,8 @@ func PrintHTMLResults(vulnResult *models.VulnerabilityResults, outputWriter io.W
 		"buildVulnTableEntryArgument": buildVulnTableEntryArgument,
 		"formatLicense":               formatLicense,
 		"hasOSResult":                 hasOSResult,
+		"countEcosystemVulns":         countEcosystemVulns,
+		"form":                        Form,
 		"GetShortCommit":              results.GetShortCommit,
 	}
 
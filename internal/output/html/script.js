@@ -23,7 +23,7 @@ function showBaseImageLayer(imageID) {
   const detailsElement = document.getElementById(detailElementID);
 
   const icon = document.querySelector(
-    `#base-image-summary-${imageID} .material-icons`
+    `#base-image-summary-${imageID} .icon`
   ); // Select the icon within the row
 
   const hidBlock = detailsElement.classList.toggle("hide-block");
@@ -34,12 +34,60 @@ function showPackageDetails(detailsId) {
   const detailsElement = document.getElementById(
     `table-tr-${detailsId}-details`
   );
-  const icon = document.querySelector(`#table-tr-${detailsId} .material-icons`); // Select the icon within the row
+  const icon = document.querySelector(`#table-tr-${detailsId} .icon`); // Select the icon within the row
 
   const hidBlock = detailsElement.classList.toggle("hide-block");
   icon.classList.toggle("expanded", !hidBlock);
 }
 
+function sortTable(header) {
+  const table = header.closest("table");
+  const columnIndex = header.cellIndex;
+  const ascending = header.dataset.sortDirection !== "asc";
+
+  table.querySelectorAll("th.sortable").forEach(th => {
+    delete th.dataset.sortDirection;
+  });
+  header.dataset.sortDirection = ascending ? "asc" : "desc";
+
+  // Each row is followed by its (collapsible) details row, which has to be kept with it
+  const rows = Array.from(table.rows).filter(row =>
+    row.classList.contains("table-tr")
+  );
+  const sortValue = row => {
+    const cell = row.cells[columnIndex];
+    return cell.dataset.sortValue ?? cell.textContent.trim();
+  };
+
+  rows.sort((a, b) => {
+    const aValue = sortValue(a);
+    const bValue = sortValue(b);
+    const aNumber = parseFloat(aValue);
+    const bNumber = parseFloat(bValue);
+
+    let order;
+    if (!isNaN(aNumber) && !isNaN(bNumber)) {
+      order = aNumber - bNumber;
+    } else if (isNaN(aNumber) !== isNaN(bNumber)) {
+      // Always put values without a score (e.g. "N/A") last
+      return isNaN(aNumber) ? 1 : -1;
+    } else {
+      order = aValue.localeCompare(bValue, undefined, { numeric: true });
+    }
+
+    return ascending ? order : -order;
+  });
+
+  const body = header.parentNode.parentNode;
+  rows.forEach(row => {
+    const details = row.nextElementSibling;
+    body.appendChild(row);
+    if (details && details.classList.contains("table-tr-details")) {
+      body.appendChild(details);
+    }
+  });
+}
+
 function openVulnInNewTab(inputString) {
   const osvURL = `https://osv.dev/${inputString}`;
   const tabs = document.getElementById("tabs");
@@ -89,8 +137,8 @@ function openVulnInNewTab(inputString) {
   newTabButton.appendChild(newTabTextContainer);
 
   const closeIcon = document.createElement("span");
-  closeIcon.className = "material-icons";
-  closeIcon.textContent = "close";
+  closeIcon.className = "icon";
+  closeIcon.textContent = "\u2715";
   // Add the onclick function to the close icon
   closeIcon.onclick = event => {
     event.stopPropagation(); // Prevent the click from opening the tab
@@ -193,7 +241,7 @@ function showAndHideParentSections() {
         packageRow.classList.add("hide-block");
         packageDetails.classList.add("hide-block");
         const icon = document.querySelector(
-          `#${packageRow.id} .material-icons`
+          `#${packageRow.id} .icon`
         );
         icon.classList.remove("expanded"); // Rotate back to 0 degrees
       });
@@ -2,9 +2,13 @@ package output_test
 
 import (
 	"bytes"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/internal/testutility"
+	"github.com/google/osv-scanner/v2/pkg/models"
 )
 
 func TestPrintHTMLResults_WithVulnerabilities(t *testing.T) {
@@ -51,3 +55,33 @@ func TestPrintHTMLResults_WithMixedIssues(t *testing.T) {
 		}
 	})
 }
+
+func TestPrintHTMLResults_SelfContained(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := testutility.LoadJSONFixture[models.VulnerabilityResults](t, "fixtures/test-vuln-results-a.json")
+
+	outputWriter := &bytes.Buffer{}
+	if err := output.PrintHTMLResults(&vulnResult, outputWriter); err != nil {
+		t.Fatalf("Error writing HTML output: %s", err)
+	}
+
+	report := outputWriter.String()
+
+	// the report should not load any external resources, so it can be viewed offline
+	externalResource := regexp.MustCompile(`(?i)(<link\b|<img\b|src=["']?https?:|url\(\s*["']?https?:|@import)`)
+	if match := externalResource.FindString(report); match != "" {
+		t.Errorf("expected HTML output to be self-contained, but found %q", match)
+	}
+
+	for _, want := range []string{
+		`href="https://osv.dev/GO-2021-0053"`,
+		"Panic due to improper input validation in github.com/gogo/protobuf",
+		"SEMVER: introduced 0, fixed 1.3.2",
+		`onclick="sortTable(this)"`,
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("expected HTML output to contain %q", want)
+		}
+	}
+}
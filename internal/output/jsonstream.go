@@ -0,0 +1,75 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+// flusher is implemented by writers that buffer their output, such as bufio.Writer
+type flusher interface {
+	Flush() error
+}
+
+// PrintJSONStreamResults writes results to the provided writer as a JSON array with an
+// object for each source, which is written (and flushed) as soon as it has been encoded
+// rather than marshaling all the results at once.
+//
+// The array is always closed, so the output remains valid JSON even if writing is
+// interrupted by the context being cancelled, in which case the error of the context
+// is returned once the array has been closed.
+func PrintJSONStreamResults(ctx context.Context, vulnResult *models.VulnerabilityResults, outputWriter io.Writer) (err error) {
+	if _, err := io.WriteString(outputWriter, "["); err != nil {
+		return err
+	}
+
+	defer func() {
+		_, closeErr := io.WriteString(outputWriter, "\n]\n")
+		if closeErr == nil {
+			closeErr = flush(outputWriter)
+		}
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	for i, source := range vulnResult.Results {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// each source is encoded in full before it is written,
+		// so that an error does not leave an incomplete object
+		b, err := json.MarshalIndent(source, "  ", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode results of %s: %w", source.Source, err)
+		}
+
+		separator := "\n  "
+		if i > 0 {
+			separator = ",\n  "
+		}
+		if _, err := io.WriteString(outputWriter, separator); err != nil {
+			return err
+		}
+		if _, err := outputWriter.Write(b); err != nil {
+			return err
+		}
+		if err := flush(outputWriter); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func flush(w io.Writer) error {
+	if f, ok := w.(flusher); ok {
+		return f.Flush()
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package output_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/internal/testutility"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+func TestPrintJSONStreamResults_WithVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	testOutputWithVulnerabilities(t, func(t *testing.T, args outputTestCaseArgs) {
+		t.Helper()
+
+		outputWriter := &bytes.Buffer{}
+		err := output.PrintJSONStreamResults(context.Background(), args.vulnResult, outputWriter)
+
+		if err != nil {
+			t.Errorf("Error writing JSON stream output: %s", err)
+		}
+
+		var sources []models.PackageSource
+		if err := json.Unmarshal(outputWriter.Bytes(), &sources); err != nil {
+			t.Errorf("JSON stream output is not valid JSON: %s", err)
+		}
+		if len(sources) != len(args.vulnResult.Results) {
+			t.Errorf("expected %d sources, got %d", len(args.vulnResult.Results), len(sources))
+		}
+
+		testutility.NewSnapshot().MatchText(t, outputWriter.String())
+	})
+}
+
+// interruptingWriter cancels the context once the first source has been written
+type interruptingWriter struct {
+	bytes.Buffer
+
+	cancel  context.CancelFunc
+	flushes int
+}
+
+func (w *interruptingWriter) Flush() error {
+	w.flushes++
+	w.cancel()
+
+	return nil
+}
+
+func TestPrintJSONStreamResults_Interrupted(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{Source: models.SourceInfo{Path: "path/to/my/first/lockfile", Type: "lockfile"}},
+			{Source: models.SourceInfo{Path: "path/to/my/second/lockfile", Type: "lockfile"}},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	outputWriter := &interruptingWriter{cancel: cancel}
+	err := output.PrintJSONStreamResults(ctx, vulnResult, outputWriter)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled error, got %v", err)
+	}
+
+	var sources []models.PackageSource
+	if err := json.Unmarshal(outputWriter.Bytes(), &sources); err != nil {
+		t.Fatalf("interrupted JSON stream output is not valid JSON: %s\n%s", err, outputWriter.String())
+	}
+	if len(sources) != 1 || sources[0].Source.Path != "path/to/my/first/lockfile" {
+		t.Errorf("expected only the first source to be written, got %v", sources)
+	}
+	// once after the first source, and once after the array is closed
+	if outputWriter.flushes != 2 {
+		t.Errorf("expected output to be flushed 2 times, got %d", outputWriter.flushes)
+	}
+}
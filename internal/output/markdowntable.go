@@ -1,23 +1,49 @@
 package output
 
 import (
+	"fmt"
 	"io"
+	"path/filepath"
+	"slices"
+	"strings"
 
+	"github.com/google/osv-scanner/v2/internal/identifiers"
+	"github.com/google/osv-scanner/v2/internal/utility/results"
+	"github.com/google/osv-scanner/v2/internal/utility/severity"
 	"github.com/google/osv-scanner/v2/pkg/models"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
 )
 
-// PrintMarkdownTableResults prints the osv scan results into a human friendly table.
+// markdownSource is the vulnerabilities of a single source, rendered as their own table
+type markdownSource struct {
+	path string
+	rows []table.Row
+}
+
+// PrintMarkdownTableResults prints the osv scan results as markdown, with a table
+// of the vulnerabilities for each source, making it suitable for PR comments.
 func PrintMarkdownTableResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) {
 	text.DisableColors()
 
-	outputTable := table.NewWriter()
-	outputTable.SetOutputMirror(outputWriter)
-	outputTable = tableBuilder(outputTable, vulnResult)
+	sources := markdownSourcesBuilder(vulnResult, true, false)
+	uncalledSources := markdownSourcesBuilder(vulnResult, false, false)
+	unimportantSources := markdownSourcesBuilder(vulnResult, true, true)
 
-	if outputTable.Length() != 0 {
-		outputTable.RenderMarkdown()
+	if len(sources)+len(uncalledSources)+len(unimportantSources) == 0 {
+		fmt.Fprintf(outputWriter, "No vulnerabilities found\n\n")
+	}
+
+	printMarkdownSources(outputWriter, sources)
+
+	if len(uncalledSources) != 0 {
+		fmt.Fprintf(outputWriter, "## Uncalled vulnerabilities\n\n")
+		printMarkdownSources(outputWriter, uncalledSources)
+	}
+
+	if len(unimportantSources) != 0 {
+		fmt.Fprintf(outputWriter, "## Unimportant vulnerabilities\n\n")
+		printMarkdownSources(outputWriter, unimportantSources)
 	}
 
 	licenseConfig := vulnResult.ExperimentalAnalysisConfig.Licenses
@@ -41,3 +67,97 @@ func PrintMarkdownTableResults(vulnResult *models.VulnerabilityResults, outputWr
 		}
 	}
 }
+
+func printMarkdownSources(outputWriter io.Writer, sources []markdownSource) {
+	for _, source := range sources {
+		fmt.Fprintf(outputWriter, "### %s\n\n", source.path)
+
+		outputTable := table.NewWriter()
+		outputTable.SetOutputMirror(outputWriter)
+		outputTable.AppendHeader(table.Row{"Package", "Version", "Ecosystem", "Vulnerability", "Severity"})
+		outputTable.AppendRows(source.rows)
+		outputTable.RenderMarkdown()
+
+		fmt.Fprintln(outputWriter)
+	}
+}
+
+// markdownSourcesBuilder builds the rows of the vulnerabilities of each source, skipping
+// sources that do not have any vulnerabilities that match the given analysis
+func markdownSourcesBuilder(vulnResult *models.VulnerabilityResults, calledVulns bool, unimportantVulns bool) []markdownSource {
+	sources := []markdownSource{}
+	workingDir := mustGetWorkingDirectory()
+
+	for _, sourceRes := range vulnResult.Results {
+		sourcePath := sourceRes.Source.Path
+		if rel, err := filepath.Rel(workingDir, sourcePath); err == nil { // Simplify the path if possible
+			sourcePath = rel
+		}
+
+		rows := []table.Row{}
+		for _, pkg := range sourceRes.Packages {
+			// Ensure that groups are sorted consistently using the first ID in each group
+			groups := slices.Clone(pkg.Groups)
+			slices.SortFunc(groups, func(a, b models.GroupInfo) int {
+				return identifiers.IDSortFunc(a.IDs[0], b.IDs[0])
+			})
+
+			for _, group := range groups {
+				if !(group.IsCalled() == calledVulns && group.IsGroupUnimportant() == unimportantVulns) {
+					continue
+				}
+
+				var links []string
+				for _, vuln := range group.IDs {
+					links = append(links, fmt.Sprintf("[%s](%s%s)", vuln, OSVBaseVulnerabilityURL, vuln))
+
+					// For container scanning results, if there is a DSA, then skip printing its sub-CVEs.
+					if strings.Split(vuln, "-")[0] == "DSA" {
+						break
+					}
+				}
+
+				if group.IsKnownExploited() {
+					links = append(links, "**"+knownExploitedLabel+"**")
+				}
+
+				name, version, ecosystem := pkg.Package.Name, pkg.Package.Version, pkg.Package.Ecosystem
+				if ecosystem == "" && pkg.Package.Commit != "" {
+					name = results.PkgToString(pkg.Package)
+					version, ecosystem = pkg.Package.Commit, "GIT"
+				}
+				if pkg.ExperimentalDirectnessUnknown {
+					name += " " + directnessUnknownLabel
+				}
+
+				rows = append(rows, table.Row{
+					name,
+					version,
+					ecosystem,
+					strings.Join(links, "\n"),
+					markdownSeverity(group.MaxSeverity),
+				})
+			}
+		}
+
+		if len(rows) != 0 {
+			sources = append(sources, markdownSource{path: sourcePath, rows: rows})
+		}
+	}
+
+	return sources
+}
+
+// markdownSeverity formats the max CVSS score of a vulnerability group along with its rating
+func markdownSeverity(maxSeverity string) string {
+	if maxSeverity == "" {
+		return string(severity.UnknownRating)
+	}
+
+	rating, err := severity.CalculateRating(maxSeverity)
+	if err != nil {
+		return maxSeverity
+	}
+
+	return fmt.Sprintf("%s (%s)", rating, maxSeverity)
+}
@@ -46,3 +46,12 @@ func TestPrintMarkdownTableResults_WithMixedIssues(t *testing.T) {
 		testutility.NewSnapshot().MatchText(t, outputWriter.String())
 	})
 }
+
+func TestPrintMarkdownTableResults_WithDirectnessUnknown(t *testing.T) {
+	t.Parallel()
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintMarkdownTableResults(directnessUnknownResults(), outputWriter)
+
+	testutility.NewSnapshot().MatchText(t, outputWriter.String())
+}
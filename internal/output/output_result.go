@@ -66,6 +66,9 @@ type PackageResult struct {
 	VulnCount         VulnCount
 	Licenses          []models.License
 	LicenseViolations []models.License
+	// DirectnessUnknown is set if the package was kept by --direct-only or --transitive-only
+	// because it could not be determined to be a direct or transitive dependency
+	DirectnessUnknown bool
 }
 
 // VulnResult represents a single vulnerability.
@@ -74,12 +77,16 @@ type VulnResult struct {
 	GroupIDs []string
 	Aliases  []string
 	// Description is either the Vulnerability.Summary (default) or the Vulnerability.Details.
-	Description      string
+	Description string
+	// AffectedRanges describes the ranges of the vulnerability that affect the package.
+	AffectedRanges   []string
 	IsFixable        bool
 	FixedVersion     string
 	VulnAnalysisType VulnAnalysisType
 	SeverityRating   severity.Rating
 	SeverityScore    string
+	// KnownExploited is whether the vulnerability is in CISA's Known Exploited Vulnerabilities catalog
+	KnownExploited bool
 }
 
 type ImageInfo struct {
@@ -238,7 +245,14 @@ func buildResult(ecosystemMap map[string][]SourceResult, resultCount VulnCount,
 	ecosystemResults = append(ecosystemResults, osResults...)
 
 	vulnTypeSummary := getVulnTypeSummary(ecosystemResults)
+	// The same package found in multiple sources of a project (such as a component
+	// shared by several SBOMs) is only counted once, along with its vulnerabilities,
+	// while each source of a container is a separate install of its packages
 	packageTypeCount := getPackageTypeCount(ecosystemResults)
+	if imageMetadata == nil {
+		packageTypeCount = getUniquePackageTypeCount(ecosystemResults)
+		vulnTypeSummary, resultCount = getUniqueVulnCount(ecosystemResults)
+	}
 
 	result.Ecosystems = ecosystemResults
 	result.VulnTypeSummary = vulnTypeSummary
@@ -470,6 +484,7 @@ func processPackage(vulnPkg models.PackageVulns) PackageResult {
 		VulnCount:         count,
 		Licenses:          vulnPkg.Licenses,
 		LicenseViolations: vulnPkg.LicenseViolations,
+		DirectnessUnknown: vulnPkg.ExperimentalDirectnessUnknown,
 	}
 
 	return packageResult
@@ -496,9 +511,10 @@ func processVulnGroups(vulnPkg models.PackageVulns) (map[string]VulnResult, map[
 		}
 
 		vuln := VulnResult{
-			ID:       representID,
-			GroupIDs: group.IDs,
-			Aliases:  aliases,
+			ID:             representID,
+			GroupIDs:       group.IDs,
+			Aliases:        aliases,
+			KnownExploited: group.IsKnownExploited(),
 		}
 
 		vuln.SeverityScore = group.MaxSeverity
@@ -533,6 +549,7 @@ func updateVuln(vulnMap map[string]VulnResult, vulnPkg models.PackageVulns) {
 			if outputVuln.Description == "" {
 				outputVuln.Description = vuln.Details
 			}
+			outputVuln.AffectedRanges = getAffectedRanges(vuln.Affected, vulnPkg.Package.Name, vulnPkg.Package.Ecosystem)
 			vulnMap[vuln.ID] = outputVuln
 		}
 	}
@@ -588,6 +605,75 @@ func getNextFixVersion(allAffected []osvschema.Affected, installedVersion string
 	return hasFixedVersion, minFixVersion
 }
 
+// FixedVersion returns the lowest version of the package that fixes all of the vulnerabilities
+// in the group, UnfixedDescription if none of them have a fixed version, or an empty string
+// if the version of the package cannot be compared (e.g. because it is a commit)
+func FixedVersion(group models.GroupInfo, pkg models.PackageVulns) string {
+	var groupVulns []osvschema.Vulnerability
+	for _, vuln := range pkg.Vulnerabilities {
+		if slices.Contains(group.IDs, vuln.ID) {
+			groupVulns = append(groupVulns, vuln)
+		}
+	}
+
+	return nextFixVersionOfAll(groupVulns, pkg.Package)
+}
+
+// nextFixVersionOfAll finds the next fixed version of the package that fixes all of the given
+// vulnerabilities which have one, which is the highest of their next fixed versions as aliases
+// can disagree on which version fixed the vulnerability
+func nextFixVersionOfAll(allVulns []osvschema.Vulnerability, pkg models.PackageInfo) string {
+	ecosystemPrefix := strings.Split(pkg.Ecosystem, ":")[0]
+	fixedVersion := UnfixedDescription
+
+	for _, vuln := range allVulns {
+		fixable, version := getNextFixVersion(vuln.Affected, pkg.Version, pkg.Name, pkg.Ecosystem)
+		if version == VersionUnsupported {
+			return ""
+		}
+		if !fixable {
+			continue
+		}
+
+		if fixedVersion == UnfixedDescription {
+			fixedVersion = version
+		} else if order, _ := semantic.MustParse(version, ecosystemPrefix).CompareStr(fixedVersion); order > 0 {
+			fixedVersion = version
+		}
+	}
+
+	return fixedVersion
+}
+
+// getAffectedRanges describes each of the affected ranges of the given package,
+// e.g. "ECOSYSTEM: introduced 0, fixed 1.2.3"
+func getAffectedRanges(allAffected []osvschema.Affected, installedPackage string, ecosystem string) []string {
+	var ranges []string
+	for _, affected := range allAffected {
+		if affected.Package.Name != installedPackage || affected.Package.Ecosystem != ecosystem {
+			continue
+		}
+		for _, affectedRange := range affected.Ranges {
+			var events []string
+			for _, event := range affectedRange.Events {
+				switch {
+				case event.Introduced != "":
+					events = append(events, "introduced "+event.Introduced)
+				case event.Fixed != "":
+					events = append(events, "fixed "+event.Fixed)
+				case event.LastAffected != "":
+					events = append(events, "last affected "+event.LastAffected)
+				case event.Limit != "":
+					events = append(events, "limit "+event.Limit)
+				}
+			}
+			ranges = append(ranges, fmt.Sprintf("%s: %s", affectedRange.Type, strings.Join(events, ", ")))
+		}
+	}
+
+	return ranges
+}
+
 // calculatePackageFixedVersion determines the highest version that resolves the most known vulnerabilities for a package.
 func calculatePackageFixedVersion(ecosystem string, allVulns []VulnResult) string {
 	ecosystemPrefix := strings.Split(ecosystem, ":")[0]
@@ -750,6 +836,87 @@ func getPackageTypeCount(result []EcosystemResult) AnalysisCount {
 	return packageCount
 }
 
+// uniquePackageKey identifies a package regardless of the source it was found in
+type uniquePackageKey struct {
+	ecosystem, name, version, commit string
+}
+
+func newUniquePackageKey(ecosystem EcosystemResult, pkg PackageResult) uniquePackageKey {
+	return uniquePackageKey{ecosystem: ecosystem.Name, name: pkg.Name, version: pkg.InstalledVersion, commit: pkg.Commit}
+}
+
+// getUniquePackageTypeCount counts the packages with issues like getPackageTypeCount,
+// except that packages with the same name and version in an ecosystem are only counted once
+func getUniquePackageTypeCount(result []EcosystemResult) AnalysisCount {
+	regular := make(map[uniquePackageKey]struct{})
+	hidden := make(map[uniquePackageKey]struct{})
+
+	for _, ecosystem := range result {
+		for _, source := range ecosystem.Sources {
+			for _, pkg := range source.Packages {
+				key := newUniquePackageKey(ecosystem, pkg)
+				if len(pkg.RegularVulns) != 0 {
+					regular[key] = struct{}{}
+				}
+				if len(pkg.HiddenVulns) != 0 {
+					hidden[key] = struct{}{}
+				}
+			}
+		}
+	}
+
+	return AnalysisCount{Regular: len(regular), Hidden: len(hidden)}
+}
+
+// getUniqueVulnCount counts the vulnerabilities like getVulnTypeSummary and the counts of each source,
+// except that a vulnerability of packages with the same name and version in an ecosystem is only counted once
+func getUniqueVulnCount(result []EcosystemResult) (VulnTypeSummary, VulnCount) {
+	type vulnKey struct {
+		pkg uniquePackageKey
+		id  string
+	}
+
+	var regular, hidden []VulnResult
+	var summary VulnTypeSummary
+	seen := make(map[vulnKey]struct{})
+
+	for _, ecosystem := range result {
+		for _, source := range ecosystem.Sources {
+			for _, pkg := range source.Packages {
+				key := newUniquePackageKey(ecosystem, pkg)
+
+				for _, vuln := range pkg.RegularVulns {
+					if _, ok := seen[vulnKey{pkg: key, id: vuln.ID}]; ok {
+						continue
+					}
+					seen[vulnKey{pkg: key, id: vuln.ID}] = struct{}{}
+
+					regular = append(regular, vuln)
+					if ecosystem.IsOS {
+						summary.OS++
+					} else {
+						summary.Project++
+					}
+				}
+
+				for _, vuln := range pkg.HiddenVulns {
+					if _, ok := seen[vulnKey{pkg: key, id: vuln.ID}]; ok {
+						continue
+					}
+					seen[vulnKey{pkg: key, id: vuln.ID}] = struct{}{}
+
+					hidden = append(hidden, vuln)
+				}
+			}
+		}
+	}
+
+	summary.All = summary.OS + summary.Project
+	summary.Hidden = len(hidden)
+
+	return summary, calculateCount(regular, hidden)
+}
+
 // calculateCount calculates the vulnerability counts based on the provided
 // lists of regular and hidden vulnerabilities.
 func calculateCount(regularVulnList, hiddenVulnList []VulnResult) VulnCount {
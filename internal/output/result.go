@@ -45,13 +45,7 @@ func (pss *pkgSourceSet) StableKeys() []pkgWithSource {
 }
 
 func (pss *pkgSourceSet) MarshalJSON() ([]byte, error) {
-	res := []pkgWithSource{}
-
-	for v := range *pss {
-		res = append(res, v)
-	}
-
-	return json.Marshal(res)
+	return json.Marshal(pss.StableKeys())
 }
 
 func (pss *pkgSourceSet) UnmarshalJSON(data []byte) error {
@@ -115,6 +109,8 @@ type groupedSARIFFinding struct {
 	// AliasedIDList contains all aliased IDs, including ones that are not OSV (e.g. CVE IDs)
 	// Sorted by idSortFunc, therefore the first element will be the display ID
 	AliasedIDList []string
+	// KEV is the entry of the finding in CISA's Known Exploited Vulnerabilities catalog, if it is in it
+	KEV *models.KEVEntry `json:",omitempty"`
 }
 
 // mapIDsToGroupedSARIFFinding creates a map over all vulnerability IDs, with aliased vuln IDs
@@ -142,6 +138,9 @@ func mapIDsToGroupedSARIFFinding(vulns *models.VulnerabilityResults) map[string]
 						AliasedVulns: make(map[string]osvschema.Vulnerability),
 					}
 				}
+				if gi.IsKnownExploited() {
+					data.KEV = gi.ExperimentalKEV
+				}
 				// Point all the IDs of the same group to the same data, either newly created or existing
 				for _, id := range gi.IDs {
 					results[id] = data
@@ -1,9 +1,12 @@
 package output
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"log"
+	"maps"
 	"path/filepath"
 	"slices"
 	"strconv"
@@ -228,6 +231,20 @@ func createSARIFHelpText(gv *groupedSARIFFinding) string {
 	return helpText.String()
 }
 
+// sarifFingerprint computes a fingerprint of a finding that is stable across scans, so that
+// tools like GitHub code scanning can track it even as the lockfile changes. This is deliberately
+// only based on the package and vulnerability, and not the version or location of the package.
+func sarifFingerprint(pkg models.PackageInfo, vulnID string) string {
+	name := pkg.Name
+	if name == "" {
+		// packages without a name can only be identified by their commit
+		name = pkg.Commit
+	}
+	hash := sha256.Sum256([]byte(pkg.Ecosystem + "\x00" + name + "\x00" + vulnID))
+
+	return hex.EncodeToString(hash[:])
+}
+
 // PrintSARIFReport prints SARIF output to outputWriter
 func PrintSARIFReport(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
 	report, err := sarif.New(sarif.Version210)
@@ -315,16 +332,39 @@ func PrintSARIFReport(vulnResult *models.VulnerabilityResults, outputWriter io.W
 				alsoKnownAsStr = fmt.Sprintf(" (also known as '%s')", strings.Join(gv.AliasedIDList[1:], "', '"))
 			}
 
+			fixedVersionStr := ""
+			switch fixedVersion := nextFixVersionOfAll(slices.Collect(maps.Values(gv.AliasedVulns)), pws.Package); fixedVersion {
+			case "":
+			case UnfixedDescription:
+				fixedVersionStr = " " + UnfixedDescription + "."
+			default:
+				fixedVersionStr = fmt.Sprintf(" Fixed in version '%s'.", fixedVersion)
+			}
+
+			// findings that are known to be exploited are raised as errors to make them stand out
+			level, knownExploitedStr := "warning", ""
+			if gv.KEV != nil {
+				level = "error"
+				knownExploitedStr = fmt.Sprintf(" %s is known to be exploited, per CISA's Known Exploited Vulnerabilities catalog.", gv.KEV.CVE)
+			}
+
 			run.CreateResultForRule(gv.DisplayID).
-				WithLevel("warning").
+				WithLevel(level).
 				WithMessage(
 					sarif.NewTextMessage(
 						fmt.Sprintf(
-							"Package '%s' is vulnerable to '%s'%s.",
+							"Package '%s' is vulnerable to '%s'%s.%s%s",
 							results.PkgToString(pws.Package),
 							gv.DisplayID,
 							alsoKnownAsStr,
+							fixedVersionStr,
+							knownExploitedStr,
 						))).
+				WithPartialFingerPrints(map[string]any{
+					// this replaces the hash of the line that GitHub would otherwise compute,
+					// which changes whenever an unrelated line of the lockfile is changed
+					"primaryLocationLineHash": sarifFingerprint(pws.Package, gv.DisplayID),
+				}).
 				AddLocation(
 					sarif.NewLocationWithPhysicalLocation(
 						sarif.NewPhysicalLocation().
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/google/osv-scanner/v2/internal/testutility"
+	"github.com/google/osv-scanner/v2/pkg/models"
 )
 
 func Test_createSARIFHelpText(t *testing.T) {
@@ -35,3 +36,29 @@ func Test_createSARIFHelpText(t *testing.T) {
 		})
 	}
 }
+
+func Test_sarifFingerprint(t *testing.T) {
+	t.Parallel()
+
+	pkg := models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"}
+	want := sarifFingerprint(pkg, "GHSA-35jh-r3h4-6jhm")
+
+	// the version changes when the lockfile is updated without fixing the vulnerability,
+	// and should not result in a new finding
+	if got := sarifFingerprint(models.PackageInfo{Name: "lodash", Version: "4.17.19", Ecosystem: "npm"}, "GHSA-35jh-r3h4-6jhm"); got != want {
+		t.Errorf("fingerprint changed with the version of the package: got %s, want %s", got, want)
+	}
+
+	for _, tt := range []struct {
+		pkg    models.PackageInfo
+		vulnID string
+	}{
+		{pkg: models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"}, vulnID: "GHSA-p6mc-m468-83gw"},
+		{pkg: models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "Packagist"}, vulnID: "GHSA-35jh-r3h4-6jhm"},
+		{pkg: models.PackageInfo{Name: "lodash-es", Version: "4.17.20", Ecosystem: "npm"}, vulnID: "GHSA-35jh-r3h4-6jhm"},
+	} {
+		if got := sarifFingerprint(tt.pkg, tt.vulnID); got == want {
+			t.Errorf("expected fingerprint of %s in %s@%s (%s) to be different", tt.vulnID, tt.pkg.Name, tt.pkg.Version, tt.pkg.Ecosystem)
+		}
+	}
+}
@@ -2,11 +2,13 @@ package output_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"testing"
 
 	"github.com/google/osv-scanner/v2/internal/output"
 	"github.com/google/osv-scanner/v2/internal/testutility"
 	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
 )
 
 func TestGroupFixedVersions(t *testing.T) {
@@ -150,3 +152,68 @@ func TestPrintSARIFReport_WithMixedIssues(t *testing.T) {
 			}).MatchText(t, outputWriter.String())
 	})
 }
+
+func TestPrintSARIFReport_WithKnownExploited(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "path/to/pom.xml", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package: models.PackageInfo{Name: "org.apache.logging.log4j:log4j-core", Version: "2.14.1", Ecosystem: "Maven"},
+						Vulnerabilities: []osvschema.Vulnerability{
+							{ID: "GHSA-jfh8-c2jp-5v3q", Aliases: []string{"CVE-2021-44228"}},
+							{ID: "GHSA-7rjr-3q55-vv33", Aliases: []string{"CVE-2021-45046"}},
+						},
+						Groups: []models.GroupInfo{
+							{
+								IDs:             []string{"GHSA-jfh8-c2jp-5v3q"},
+								Aliases:         []string{"CVE-2021-44228", "GHSA-jfh8-c2jp-5v3q"},
+								ExperimentalKEV: &models.KEVEntry{CVE: "CVE-2021-44228", DateAdded: "2021-12-10"},
+							},
+							{
+								IDs:     []string{"GHSA-7rjr-3q55-vv33"},
+								Aliases: []string{"CVE-2021-45046", "GHSA-7rjr-3q55-vv33"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	if err := output.PrintSARIFReport(vulnResult, outputWriter); err != nil {
+		t.Fatalf("Error writing SARIF output: %s", err)
+	}
+
+	var report struct {
+		Runs []struct {
+			Results []struct {
+				RuleID  string `json:"ruleId"`
+				Level   string `json:"level"`
+				Message struct {
+					Text string `json:"text"`
+				} `json:"message"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(outputWriter.Bytes(), &report); err != nil {
+		t.Fatalf("Error parsing SARIF output: %s", err)
+	}
+
+	levels := map[string]string{}
+	for _, result := range report.Runs[0].Results {
+		levels[result.RuleID] = result.Level
+	}
+
+	// known exploited findings are raised as errors, while the rest stay as warnings
+	if levels["CVE-2021-44228"] != "error" {
+		t.Errorf("level of CVE-2021-44228 = %q, want %q", levels["CVE-2021-44228"], "error")
+	}
+	if levels["CVE-2021-45046"] != "warning" {
+		t.Errorf("level of CVE-2021-45046 = %q, want %q", levels["CVE-2021-45046"], "warning")
+	}
+}
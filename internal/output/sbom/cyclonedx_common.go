@@ -17,23 +17,12 @@ func buildCycloneDXBom(uniquePackages map[string]models.PackageVulns) *cyclonedx
 	vulnerabilities := make(map[string]cyclonedx.Vulnerability)
 
 	for packageURL, packageDetail := range uniquePackages {
-		component := cyclonedx.Component{}
-
-		component.Type = libraryComponentType
-		component.BOMRef = packageURL
-		component.PackageURL = packageURL
-		component.Name = packageDetail.Package.Name
-		component.Version = packageDetail.Package.Version
-
-		fillLicenses(&component, packageDetail)
 		addVulnerabilities(vulnerabilities, packageDetail)
 
-		components = append(components, component)
+		components = append(components, buildComponent(packageURL, packageDetail))
 	}
 
-	slices.SortFunc(components, func(a, b cyclonedx.Component) int {
-		return strings.Compare(a.PackageURL, b.PackageURL)
-	})
+	sortComponents(components)
 
 	for _, vulnerability := range vulnerabilities {
 		bomVulnerabilities = append(bomVulnerabilities, vulnerability)
@@ -49,6 +38,26 @@ func buildCycloneDXBom(uniquePackages map[string]models.PackageVulns) *cyclonedx
 	return bom
 }
 
+func buildComponent(packageURL string, packageDetail models.PackageVulns) cyclonedx.Component {
+	component := cyclonedx.Component{}
+
+	component.Type = libraryComponentType
+	component.BOMRef = packageURL
+	component.PackageURL = packageURL
+	component.Name = packageDetail.Package.Name
+	component.Version = packageDetail.Package.Version
+
+	fillLicenses(&component, packageDetail)
+
+	return component
+}
+
+func sortComponents(components []cyclonedx.Component) {
+	slices.SortFunc(components, func(a, b cyclonedx.Component) int {
+		return strings.Compare(a.PackageURL, b.PackageURL)
+	})
+}
+
 func fillLicenses(component *cyclonedx.Component, packageDetail models.PackageVulns) {
 	licenses := make(cyclonedx.Licenses, len(packageDetail.Licenses))
 
@@ -69,20 +78,42 @@ func addVulnerabilities(vulnerabilities map[string]cyclonedx.Vulnerability, pack
 		}
 
 		// It doesn't exists yet, lets add it
-		vulnerabilities[vulnerability.ID] = cyclonedx.Vulnerability{
-			ID:          vulnerability.ID,
-			Updated:     formatDateIfExists(vulnerability.Modified),
-			Published:   formatDateIfExists(vulnerability.Published),
-			Rejected:    formatDateIfExists(vulnerability.Withdrawn),
-			References:  buildReferences(vulnerability),
-			Description: vulnerability.Summary,
-			Detail:      vulnerability.Details,
-			Affects:     buildAffectedPackages(vulnerability),
-			Ratings:     buildRatings(vulnerability),
-			Advisories:  buildAdvisories(vulnerability),
-			Credits:     buildCredits(vulnerability),
+		bomVulnerability := buildVulnerability(vulnerability, packageDetail)
+		bomVulnerability.Affects = buildAffectedPackages(vulnerability)
+		vulnerabilities[vulnerability.ID] = bomVulnerability
+	}
+}
+
+func buildVulnerability(vulnerability osvschema.Vulnerability, packageDetail models.PackageVulns) cyclonedx.Vulnerability {
+	return cyclonedx.Vulnerability{
+		ID:          vulnerability.ID,
+		Updated:     formatDateIfExists(vulnerability.Modified),
+		Published:   formatDateIfExists(vulnerability.Published),
+		Rejected:    formatDateIfExists(vulnerability.Withdrawn),
+		References:  buildReferences(vulnerability),
+		Description: vulnerability.Summary,
+		Detail:      vulnerability.Details,
+		Ratings:     buildRatings(vulnerability),
+		Advisories:  buildAdvisories(vulnerability),
+		Credits:     buildCredits(vulnerability),
+		Properties:  buildKEVProperties(packageDetail, vulnerability.ID),
+	}
+}
+
+// buildKEVProperties flags the vulnerability if its group is in CISA's Known Exploited Vulnerabilities catalog
+func buildKEVProperties(packageDetail models.PackageVulns, id string) *[]cyclonedx.Property {
+	for _, group := range packageDetail.Groups {
+		if !group.IsKnownExploited() || !slices.Contains(group.IDs, id) {
+			continue
+		}
+
+		return &[]cyclonedx.Property{
+			{Name: "osv-scanner:cisa-kev:cve", Value: group.ExperimentalKEV.CVE},
+			{Name: "osv-scanner:cisa-kev:date-added", Value: group.ExperimentalKEV.DateAdded},
 		}
 	}
+
+	return nil
 }
 
 func formatDateIfExists(date time.Time) string {
@@ -0,0 +1,107 @@
+package sbom
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/CycloneDX/cyclonedx-go"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// vexStatement identifies the VEX statements that are the same other than the components they are about,
+// which are combined into a single vulnerability that affects all of the components
+type vexStatement struct {
+	id            string
+	state         cyclonedx.ImpactAnalysisState
+	justification cyclonedx.ImpactAnalysisJustification
+	detail        string
+}
+
+// ToCycloneDX15VEXBom creates a CycloneDX 1.5 BOM of the packages, with the vulnerabilities of
+// each package (including those that were ignored) being VEX statements about its component
+//
+// Vulnerabilities that were found are in triage, unless call analysis found that they are not
+// called, while those that were ignored have the state and justification of their ignore.
+func ToCycloneDX15VEXBom(uniquePackages map[string]models.PackageVulns) *cyclonedx.BOM {
+	bom := cyclonedx.NewBOM()
+	bom.JSONSchema = cycloneDx15Schema
+	bom.SpecVersion = cyclonedx.SpecVersion1_5
+
+	components := make([]cyclonedx.Component, 0, len(uniquePackages))
+	vulnerabilities := make(map[vexStatement]cyclonedx.Vulnerability)
+
+	addStatement := func(packageURL string, packageDetail models.PackageVulns, vulnerability osvschema.Vulnerability, statement vexStatement) {
+		bomVulnerability, exists := vulnerabilities[statement]
+		if !exists {
+			bomVulnerability = buildVulnerability(vulnerability, packageDetail)
+			bomVulnerability.Affects = &[]cyclonedx.Affects{}
+			bomVulnerability.Analysis = &cyclonedx.VulnerabilityAnalysis{
+				State:         statement.state,
+				Justification: statement.justification,
+				Detail:        statement.detail,
+			}
+		}
+
+		*bomVulnerability.Affects = append(*bomVulnerability.Affects, cyclonedx.Affects{Ref: packageURL})
+		vulnerabilities[statement] = bomVulnerability
+	}
+
+	for packageURL, packageDetail := range uniquePackages {
+		components = append(components, buildComponent(packageURL, packageDetail))
+
+		for _, vulnerability := range packageDetail.Vulnerabilities {
+			addStatement(packageURL, packageDetail, vulnerability, findingStatement(vulnerability.ID, packageDetail))
+		}
+
+		for _, ignored := range packageDetail.ExperimentalIgnoredVulnerabilities {
+			addStatement(packageURL, packageDetail, ignored.Vulnerability, vexStatement{
+				id:            ignored.Vulnerability.ID,
+				state:         cyclonedx.ImpactAnalysisState(ignored.State),
+				justification: cyclonedx.ImpactAnalysisJustification(ignored.Justification),
+				detail:        ignored.Reason,
+			})
+		}
+	}
+
+	sortComponents(components)
+
+	bomVulnerabilities := make([]cyclonedx.Vulnerability, 0, len(vulnerabilities))
+	for _, vulnerability := range vulnerabilities {
+		slices.SortFunc(*vulnerability.Affects, func(a, b cyclonedx.Affects) int {
+			return strings.Compare(a.Ref, b.Ref)
+		})
+		bomVulnerabilities = append(bomVulnerabilities, vulnerability)
+	}
+
+	slices.SortFunc(bomVulnerabilities, func(a, b cyclonedx.Vulnerability) int {
+		return cmp.Or(
+			strings.Compare(a.ID, b.ID),
+			strings.Compare(string(a.Analysis.State), string(b.Analysis.State)),
+			strings.Compare(string(a.Analysis.Justification), string(b.Analysis.Justification)),
+			strings.Compare(a.Analysis.Detail, b.Analysis.Detail),
+		)
+	})
+
+	bom.Components = &components
+	bom.Vulnerabilities = &bomVulnerabilities
+
+	return bom
+}
+
+// findingStatement is the VEX statement about a vulnerability that was found in the package
+func findingStatement(id string, packageDetail models.PackageVulns) vexStatement {
+	for _, group := range packageDetail.Groups {
+		if slices.Contains(group.IDs, id) && !group.IsCalled() {
+			return vexStatement{
+				id:            id,
+				state:         cyclonedx.IASNotAffected,
+				justification: cyclonedx.IAJCodeNotReachable,
+				detail:        "Call analysis found that the vulnerable code is not called",
+			}
+		}
+	}
+
+	return vexStatement{id: id, state: cyclonedx.IASInTriage}
+}
@@ -1,12 +1,15 @@
 package output
 
 import (
+	"cmp"
 	"fmt"
 	"io"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/google/osv-scanner/v2/internal/grouper"
 	"github.com/google/osv-scanner/v2/internal/identifiers"
 	"github.com/google/osv-scanner/v2/internal/imodels/ecosystem"
 	depgroups "github.com/google/osv-scanner/v2/internal/utility/depgroup"
@@ -19,6 +22,13 @@ import (
 	"github.com/jedib0t/go-pretty/v6/text"
 )
 
+// knownExploitedLabel flags the findings that are in CISA's Known Exploited Vulnerabilities catalog
+const knownExploitedLabel = "KNOWN EXPLOITED (CISA KEV)"
+
+// directnessUnknownLabel flags the packages that were kept by --direct-only or --transitive-only,
+// as they could not be determined to be either direct or transitive dependencies
+const directnessUnknownLabel = "(directness unknown)"
+
 // OSVBaseVulnerabilityURL is the base URL for detailed vulnerability views.
 // Copied in from osv package to avoid referencing the osv package unnecessarily
 const OSVBaseVulnerabilityURL = "https://osv.dev/"
@@ -70,37 +80,64 @@ func newTable(outputWriter io.Writer, terminalWidth int) table.Writer {
 }
 
 func tableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
-	outputTable.AppendHeader(table.Row{"OSV URL", "CVSS", "Ecosystem", "Package", "Version", "Source"})
-	rows := tableBuilderInner(vulnResult, true, false)
-	for _, elem := range rows {
-		outputTable.AppendRow(elem.row, table.RowConfig{AutoMerge: elem.shouldMerge})
+	rowsBuilder := tableBuilderInner
+	if len(vulnResult.ExperimentalAggregatedFindings) > 0 {
+		rowsBuilder = aggregatedTableBuilderInner
 	}
 
-	uncalledRows := tableBuilderInner(vulnResult, false, false)
-	if len(uncalledRows) != 0 {
-		outputTable.AppendSeparator()
-		outputTable.AppendRow(table.Row{"Uncalled vulnerabilities"})
-		outputTable.AppendSeparator()
+	rows := rowsBuilder(vulnResult, true, false)
+	uncalledRows := rowsBuilder(vulnResult, false, false)
+	unimportantRows := rowsBuilder(vulnResult, true, true)
+	unaffectedRows := unaffectedTableBuilderInner(vulnResult)
 
-		for _, elem := range uncalledRows {
-			outputTable.AppendRow(elem.row, table.RowConfig{AutoMerge: elem.shouldMerge})
-		}
+	// the EPSS column is only shown if scores were requested, which is when at least one row has one
+	hasEPSS := slices.ContainsFunc(slices.Concat(rows, uncalledRows, unimportantRows), func(elem tbInnerResponse) bool {
+		return elem.epss != nil
+	})
+
+	header := table.Row{"OSV URL", "CVSS", "Ecosystem", "Package", "Version", "Fixed Version", "Source"}
+	if hasEPSS {
+		header = slices.Insert(header, 2, any("EPSS"))
 	}
+	outputTable.AppendHeader(header)
 
-	unimportantRows := tableBuilderInner(vulnResult, true, true)
-	if len(unimportantRows) != 0 {
-		outputTable.AppendSeparator()
-		outputTable.AppendRow(table.Row{"Unimportant vulnerabilities"})
-		outputTable.AppendSeparator()
+	appendRows := func(title string, rows []tbInnerResponse) {
+		if len(rows) == 0 {
+			return
+		}
 
-		for _, elem := range unimportantRows {
-			outputTable.AppendRow(elem.row, table.RowConfig{AutoMerge: elem.shouldMerge})
+		if title != "" {
+			outputTable.AppendSeparator()
+			outputTable.AppendRow(table.Row{title})
+			outputTable.AppendSeparator()
+		}
+
+		for _, elem := range rows {
+			row := elem.row
+			if hasEPSS {
+				row = slices.Insert(slices.Clone(row), 2, any(tableEPSS(elem.epss)))
+			}
+			outputTable.AppendRow(row, table.RowConfig{AutoMerge: elem.shouldMerge})
 		}
 	}
 
+	appendRows("", rows)
+	appendRows("Uncalled vulnerabilities", uncalledRows)
+	appendRows("Unimportant vulnerabilities", unimportantRows)
+	appendRows("Vulnerabilities not affecting the installed version", unaffectedRows)
+
 	return outputTable
 }
 
+// tableEPSS formats the probability of the EPSS score as a percentage, or is empty if there is no score
+func tableEPSS(score *models.EPSSScore) string {
+	if score == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%.2f%%", score.Probability*100)
+}
+
 func printContainerScanningResult(result Result, outputWriter io.Writer, terminalWidth int) {
 	// Add a newline to separate results from logs.
 	fmt.Fprintln(outputWriter)
@@ -210,73 +247,256 @@ func printLicenseSummary(licenseSummary LicenseSummary, outputWriter io.Writer,
 type tbInnerResponse struct {
 	row         table.Row
 	shouldMerge bool
+	// pkgName and score are the name of the package and the severity of the row
+	// (which is -1 if it is unknown), which are used to sort the rows
+	pkgName string
+	score   float64
+	// epss is the EPSS score of the row if it has one, which is shown in its own column
+	// and takes precedence over the severity when sorting
+	epss *models.EPSSScore
 }
 
 func tableBuilderInner(vulnResult *models.VulnerabilityResults, calledVulns bool, unimportantVulns bool) []tbInnerResponse {
-	allOutputRows := []tbInnerResponse{}
+	pkgRows := [][]tbInnerResponse{}
 	workingDir := mustGetWorkingDirectory()
 
 	for _, sourceRes := range vulnResult.Results {
 		for _, pkg := range sourceRes.Packages {
-			source := sourceRes.Source
-			sourcePath, err := filepath.Rel(workingDir, source.Path)
-			if err == nil { // Simplify the path if possible
-				source.Path = sourcePath
-			}
-
 			// Ensure that groups are sorted consistently using the first ID in each group
 			slices.SortFunc(pkg.Groups, func(a, b models.GroupInfo) int {
 				return identifiers.IDSortFunc(a.IDs[0], b.IDs[0])
 			})
 
+			rows := []tbInnerResponse{}
+
 			// Merge groups into the same row
 			for _, group := range pkg.Groups {
 				if !(group.IsCalled() == calledVulns && group.IsGroupUnimportant() == unimportantVulns) {
 					continue
 				}
 
-				outputRow := table.Row{}
-				shouldMerge := false
+				rows = append(rows, tableBuilderRow(pkg.Package, pkg.DepGroups, pkg.DependencyPath, pkg.ExperimentalDirectnessUnknown, group, pkg.Vulnerabilities, tableSourcePath(workingDir, sourceRes.Source)))
+			}
 
-				var links []string
+			if len(rows) > 0 {
+				pkgRows = append(pkgRows, rows)
+			}
+		}
+	}
 
-				for _, vuln := range group.IDs {
-					links = append(links, OSVBaseVulnerabilityURL+text.Bold.Sprintf("%s", vuln))
+	return sortTableRows(pkgRows)
+}
 
-					// For container scanning results, if there is a DSA, then skip printing its sub-CVEs.
-					if strings.Split(vuln, "-")[0] == "DSA" {
-						break
-					}
-				}
+// unaffectedTableBuilderInner builds the rows of the vulnerabilities of each package
+// that do not affect the version of the package that is being used
+func unaffectedTableBuilderInner(vulnResult *models.VulnerabilityResults) []tbInnerResponse {
+	pkgRows := [][]tbInnerResponse{}
+	workingDir := mustGetWorkingDirectory()
 
-				outputRow = append(outputRow, strings.Join(links, "\n"))
-				outputRow = append(outputRow, group.MaxSeverity)
+	for _, sourceRes := range vulnResult.Results {
+		for _, pkg := range sourceRes.Packages {
+			if len(pkg.ExperimentalUnaffectedVulnerabilities) == 0 {
+				continue
+			}
 
-				if pkg.Package.Ecosystem == "" && pkg.Package.Commit != "" {
-					pkgCommitStr := results.PkgToString(pkg.Package)
-					outputRow = append(outputRow, "GIT", pkgCommitStr, pkgCommitStr)
-					shouldMerge = true
-				} else {
-					name := pkg.Package.Name
-					// TODO(#1646): Migrate this earlier to the result struct directly
-					if depgroups.IsDevGroup(ecosystem.MustParse(pkg.Package.Ecosystem).Ecosystem, pkg.DepGroups) {
-						name += " (dev)"
-					}
-					outputRow = append(outputRow, pkg.Package.Ecosystem, name, pkg.Package.Version)
-				}
+			groups := grouper.Group(grouper.ConvertVulnerabilityToIDAliases(pkg.ExperimentalUnaffectedVulnerabilities))
+			slices.SortFunc(groups, func(a, b models.GroupInfo) int {
+				return identifiers.IDSortFunc(a.IDs[0], b.IDs[0])
+			})
 
-				outputRow = append(outputRow, source.Path)
-				allOutputRows = append(allOutputRows, tbInnerResponse{
-					row:         outputRow,
-					shouldMerge: shouldMerge,
-				})
+			unaffected := models.PackageVulns{Vulnerabilities: pkg.ExperimentalUnaffectedVulnerabilities}
+			rows := make([]tbInnerResponse, 0, len(groups))
+			for _, group := range groups {
+				group.MaxSeverity = MaxSeverity(group, unaffected)
+				rows = append(rows, tableBuilderRow(pkg.Package, pkg.DepGroups, nil, pkg.ExperimentalDirectnessUnknown, group, unaffected.Vulnerabilities, tableSourcePath(workingDir, sourceRes.Source)))
 			}
+
+			pkgRows = append(pkgRows, rows)
 		}
 	}
 
+	return sortTableRows(pkgRows)
+}
+
+// sortTableRows orders the rows of each package by severity, and then flattens them with the
+// packages that have the most severe vulnerabilities first, keeping the rows of each package together.
+// Rows with EPSS scores are ordered by their likelihood of being exploited ahead of their severity.
+// Packages are otherwise ordered by name, and rows by the order they were built in.
+func sortTableRows(pkgRows [][]tbInnerResponse) []tbInnerResponse {
+	epss := func(row tbInnerResponse) float64 {
+		if row.epss == nil {
+			return -1
+		}
+
+		return row.epss.Probability
+	}
+
+	for _, rows := range pkgRows {
+		slices.SortStableFunc(rows, func(a, b tbInnerResponse) int {
+			return cmp.Or(
+				cmp.Compare(epss(b), epss(a)),
+				cmp.Compare(b.score, a.score),
+			)
+		})
+	}
+
+	// the rows are sorted, so the first row of each package is its most likely to be exploited or most severe
+	slices.SortStableFunc(pkgRows, func(a, b []tbInnerResponse) int {
+		return cmp.Or(
+			cmp.Compare(epss(b[0]), epss(a[0])),
+			cmp.Compare(b[0].score, a[0].score),
+			cmp.Compare(a[0].pkgName, b[0].pkgName),
+		)
+	})
+
+	allOutputRows := []tbInnerResponse{}
+	for _, rows := range pkgRows {
+		allOutputRows = append(allOutputRows, rows...)
+	}
+
 	return allOutputRows
 }
 
+// aggregatedTableBuilderInner builds the rows of findings that have been aggregated across
+// sources, with a single row listing all the sources of each finding
+func aggregatedTableBuilderInner(vulnResult *models.VulnerabilityResults, calledVulns bool, unimportantVulns bool) []tbInnerResponse {
+	pkgRows := [][]tbInnerResponse{}
+	pkgIndexes := map[models.PackageInfo]int{}
+	workingDir := mustGetWorkingDirectory()
+
+	// the vulnerabilities of each package across all its sources, which determine the
+	// qualitative severity of its findings that do not have a CVSS score
+	pkgVulns := map[models.PackageInfo][]osvschema.Vulnerability{}
+	// packages are flagged if their directness is unknown in any of their sources
+	directnessUnknown := map[models.PackageInfo]bool{}
+	for _, sourceRes := range vulnResult.Results {
+		for _, pkg := range sourceRes.Packages {
+			pkgVulns[pkg.Package] = append(pkgVulns[pkg.Package], pkg.Vulnerabilities...)
+			directnessUnknown[pkg.Package] = directnessUnknown[pkg.Package] || pkg.ExperimentalDirectnessUnknown
+		}
+	}
+
+	for _, finding := range vulnResult.ExperimentalAggregatedFindings {
+		group := finding.GroupInfo
+		if !(group.IsCalled() == calledVulns && group.IsGroupUnimportant() == unimportantVulns) {
+			continue
+		}
+
+		sourcePaths := make([]string, 0, len(finding.Sources))
+		for _, source := range finding.Sources {
+			sourcePaths = append(sourcePaths, tableSourcePath(workingDir, source))
+		}
+
+		row := tableBuilderRow(finding.Package, finding.DepGroups, nil, directnessUnknown[finding.Package], group, pkgVulns[finding.Package], strings.Join(sourcePaths, "\n"))
+
+		// group the findings of each package together, like they would be without aggregation
+		i, ok := pkgIndexes[finding.Package]
+		if !ok {
+			i = len(pkgRows)
+			pkgIndexes[finding.Package] = i
+			pkgRows = append(pkgRows, []tbInnerResponse{})
+		}
+		pkgRows[i] = append(pkgRows[i], row)
+	}
+
+	return sortTableRows(pkgRows)
+}
+
+// tableSourcePath simplifies the path of the source to be relative to the working directory, if possible
+func tableSourcePath(workingDir string, source models.SourceInfo) string {
+	sourcePath, err := filepath.Rel(workingDir, source.Path)
+	if err != nil {
+		return source.Path
+	}
+
+	return sourcePath
+}
+
+// tableBuilderRow builds the row of a group of vulnerabilities, using vulns to determine the
+// qualitative severity of the group if it does not have a CVSS score.
+func tableBuilderRow(pkg models.PackageInfo, depGroups []string, dependencyPath []string, directnessUnknown bool, group models.GroupInfo, vulns []osvschema.Vulnerability, sources string) tbInnerResponse {
+	outputRow := table.Row{}
+	shouldMerge := false
+	pkgName := pkg.Name
+	sev, score := tableSeverity(group, vulns)
+
+	var links []string
+
+	for _, vuln := range group.IDs {
+		links = append(links, OSVBaseVulnerabilityURL+text.Bold.Sprintf("%s", vuln))
+
+		// For container scanning results, if there is a DSA, then skip printing its sub-CVEs.
+		if strings.Split(vuln, "-")[0] == "DSA" {
+			break
+		}
+	}
+
+	if group.IsKnownExploited() {
+		links = append(links, text.FgHiRed.Sprint(knownExploitedLabel))
+	}
+
+	outputRow = append(outputRow, strings.Join(links, "\n"))
+	outputRow = append(outputRow, sev)
+
+	if pkg.Ecosystem == "" && pkg.Commit != "" {
+		pkgCommitStr := results.PkgToString(pkg)
+		outputRow = append(outputRow, "GIT", pkgCommitStr, pkgCommitStr)
+		shouldMerge = true
+		pkgName = pkgCommitStr
+	} else {
+		name := pkg.Name
+		// TODO(#1646): Migrate this earlier to the result struct directly
+		if depgroups.IsDevGroup(ecosystem.MustParse(pkg.Ecosystem).Ecosystem, depGroups) {
+			name += " (dev)"
+		}
+		if directnessUnknown {
+			name += " " + directnessUnknownLabel
+		}
+		// the last package in the path is the package itself
+		if len(dependencyPath) > 1 {
+			name += "\nvia " + strings.Join(dependencyPath[:len(dependencyPath)-1], " > ")
+		}
+		outputRow = append(outputRow, pkg.Ecosystem, name, pkg.Version)
+	}
+
+	fixedVersion := group.FixedVersion
+	if fixedVersion == "" {
+		fixedVersion = "--"
+	}
+	outputRow = append(outputRow, fixedVersion, sources)
+
+	return tbInnerResponse{
+		row:         outputRow,
+		shouldMerge: shouldMerge,
+		pkgName:     pkgName,
+		score:       score,
+		epss:        group.ExperimentalEPSS,
+	}
+}
+
+// tableSeverity returns the severity to display for the group along with its score, which is
+// the CVSS score of the group if it has one, or otherwise the qualitative severity of its vulnerabilities
+func tableSeverity(group models.GroupInfo, vulns []osvschema.Vulnerability) (string, float64) {
+	if score, err := strconv.ParseFloat(group.MaxSeverity, 64); err == nil {
+		return group.MaxSeverity, score
+	}
+
+	score := -1.0
+	for _, vuln := range vulns {
+		if slices.Contains(group.IDs, vuln.ID) {
+			score = max(score, severity.CalculateVulnerabilityScore(vuln))
+		}
+	}
+
+	if score < 0 {
+		return "", -1
+	}
+
+	rating, _ := severity.CalculateRating(strconv.FormatFloat(score, 'f', 1, 64))
+
+	return string(rating), score
+}
+
 func MaxSeverity(group models.GroupInfo, pkg models.PackageVulns) string {
 	var maxSeverity float64 = -1
 	for _, vulnID := range group.IDs {
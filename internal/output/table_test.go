@@ -6,7 +6,9 @@ import (
 
 	"github.com/google/osv-scanner/v2/internal/output"
 	"github.com/google/osv-scanner/v2/internal/testutility"
+	"github.com/google/osv-scanner/v2/pkg/models"
 	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
 )
 
 func TestPrintTableResults_StandardTerminalWidth_WithVulnerabilities(t *testing.T) {
@@ -125,3 +127,290 @@ func TestPrintTableResults_NoTerminalWidth_WithMixedIssues(t *testing.T) {
 		testutility.NewSnapshot().MatchText(t, outputWriter.String())
 	})
 }
+
+func TestPrintTableResults_WithAggregatedFindings(t *testing.T) {
+	t.Parallel()
+
+	testOutputWithVulnerabilities(t, func(t *testing.T, args outputTestCaseArgs) {
+		t.Helper()
+
+		vulnResult := *args.vulnResult
+		vulnResult.ExperimentalAggregatedFindings = vulnResult.Aggregate()
+
+		outputWriter := &bytes.Buffer{}
+		output.PrintTableResults(&vulnResult, outputWriter, 0)
+
+		testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
+	})
+}
+
+// severitySortedResults has findings without CVSS scores whose rows are sorted by
+// the qualitative severity of their vulnerabilities
+func severitySortedResults() *models.VulnerabilityResults {
+	newPackage := func(name string, vulns ...osvschema.Vulnerability) models.PackageVulns {
+		pkg := models.PackageVulns{
+			Package:         models.PackageInfo{Name: name, Version: "1.0.0", Ecosystem: "npm"},
+			Vulnerabilities: vulns,
+		}
+		for _, vuln := range vulns {
+			pkg.Groups = append(pkg.Groups, models.GroupInfo{
+				IDs:         []string{vuln.ID},
+				MaxSeverity: output.MaxSeverity(models.GroupInfo{IDs: []string{vuln.ID}}, pkg),
+			})
+		}
+
+		return pkg
+	}
+	cvss := func(id string, vector string) osvschema.Vulnerability {
+		return osvschema.Vulnerability{
+			ID:       id,
+			Severity: []osvschema.Severity{{Type: osvschema.SeverityCVSSV3, Score: vector}},
+		}
+	}
+
+	return &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "path/to/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					newPackage("alpha", osvschema.Vulnerability{ID: "OSV-1"}),
+					newPackage("bravo",
+						cvss("OSV-2", "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:L/I:N/A:N"),
+						cvss("OSV-3", "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"),
+					),
+					newPackage("charlie", osvschema.Vulnerability{
+						ID:               "OSV-4",
+						DatabaseSpecific: map[string]any{"severity": "HIGH"},
+					}),
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "path/to/other/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					newPackage("alpha", cvss("OSV-5", "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:L/I:N/A:N")),
+				},
+			},
+		},
+	}
+}
+
+func TestPrintTableResults_SortedBySeverity(t *testing.T) {
+	t.Parallel()
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintTableResults(severitySortedResults(), outputWriter, 0)
+
+	testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
+}
+
+func TestPrintTableResults_SortedBySeverity_WithAggregatedFindings(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := severitySortedResults()
+	vulnResult.ExperimentalAggregatedFindings = vulnResult.Aggregate()
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintTableResults(vulnResult, outputWriter, 0)
+
+	testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
+}
+
+func TestPrintTableResults_WithDependencyPaths(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "path/to/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "qs", Version: "6.11.0", Ecosystem: "npm"},
+						DependencyPath:  []string{"express@4.18.2", "body-parser@1.20.1", "qs@6.11.0"},
+						Vulnerabilities: []osvschema.Vulnerability{{ID: "OSV-1"}},
+						Groups:          []models.GroupInfo{{IDs: []string{"OSV-1"}}},
+					},
+					{
+						Package:         models.PackageInfo{Name: "express", Version: "4.18.2", Ecosystem: "npm"},
+						Vulnerabilities: []osvschema.Vulnerability{{ID: "OSV-2"}},
+						Groups:          []models.GroupInfo{{IDs: []string{"OSV-2"}}},
+					},
+				},
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintTableResults(vulnResult, outputWriter, 0)
+
+	testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
+}
+
+func TestPrintTableResults_WithDirectnessUnknown(t *testing.T) {
+	t.Parallel()
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintTableResults(directnessUnknownResults(), outputWriter, 0)
+
+	testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
+}
+
+func TestPrintTableResults_WithEPSS(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "path/to/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "alpha", Version: "1.0.0", Ecosystem: "npm"},
+						Vulnerabilities: []osvschema.Vulnerability{{ID: "OSV-1"}, {ID: "OSV-2"}},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"OSV-1"}, MaxSeverity: "9.8"},
+							{IDs: []string{"OSV-2"}, MaxSeverity: "5.3", ExperimentalEPSS: &models.EPSSScore{CVE: "CVE-2", Probability: 0.0123}},
+						},
+					},
+					{
+						Package:         models.PackageInfo{Name: "bravo", Version: "1.0.0", Ecosystem: "npm"},
+						Vulnerabilities: []osvschema.Vulnerability{{ID: "OSV-3"}},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"OSV-3"}, MaxSeverity: "4.0", ExperimentalEPSS: &models.EPSSScore{CVE: "CVE-3", Probability: 0.9712}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintTableResults(vulnResult, outputWriter, 0)
+
+	testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
+}
+
+func TestPrintTableResults_WithKnownExploited(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "path/to/pom.xml", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "org.apache.logging.log4j:log4j-core", Version: "2.14.1", Ecosystem: "Maven"},
+						Vulnerabilities: []osvschema.Vulnerability{{ID: "GHSA-jfh8-c2jp-5v3q"}, {ID: "GHSA-7rjr-3q55-vv33"}},
+						Groups: []models.GroupInfo{
+							{
+								IDs:             []string{"GHSA-jfh8-c2jp-5v3q"},
+								MaxSeverity:     "10.0",
+								ExperimentalKEV: &models.KEVEntry{CVE: "CVE-2021-44228", DateAdded: "2021-12-10"},
+							},
+							{IDs: []string{"GHSA-7rjr-3q55-vv33"}, MaxSeverity: "9.0"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintTableResults(vulnResult, outputWriter, 0)
+
+	testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
+}
+
+func TestPrintTableResults_WithUnaffectedVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "path/to/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "lodash", Version: "4.17.21", Ecosystem: "npm"},
+						Vulnerabilities: []osvschema.Vulnerability{{ID: "OSV-1"}},
+						Groups:          []models.GroupInfo{{IDs: []string{"OSV-1"}}},
+						ExperimentalUnaffectedVulnerabilities: []osvschema.Vulnerability{
+							{ID: "OSV-2", Aliases: []string{"CVE-2"}},
+							{
+								ID:       "CVE-2",
+								Aliases:  []string{"OSV-2"},
+								Severity: []osvschema.Severity{{Type: osvschema.SeverityCVSSV3, Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}},
+							},
+						},
+					},
+					{
+						Package: models.PackageInfo{Name: "minimist", Version: "1.2.8", Ecosystem: "npm"},
+						ExperimentalUnaffectedVulnerabilities: []osvschema.Vulnerability{
+							{ID: "OSV-3"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintTableResults(vulnResult, outputWriter, 0)
+
+	testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
+}
+
+func TestPrintTableResults_WithFixedVersions(t *testing.T) {
+	t.Parallel()
+
+	affected := func(events ...osvschema.Event) []osvschema.Affected {
+		return []osvschema.Affected{{
+			Package: osvschema.Package{Name: "lodash", Ecosystem: "npm"},
+			Ranges:  []osvschema.Range{{Type: osvschema.RangeSemVer, Events: events}},
+		}}
+	}
+
+	pkg := models.PackageVulns{
+		Package: models.PackageInfo{Name: "lodash", Version: "4.17.10", Ecosystem: "npm"},
+		Vulnerabilities: []osvschema.Vulnerability{
+			{
+				ID: "OSV-1",
+				Affected: affected(
+					osvschema.Event{Introduced: "0"},
+					osvschema.Event{Fixed: "4.17.5"},
+					osvschema.Event{Introduced: "4.17.7"},
+					osvschema.Event{Fixed: "4.17.21"},
+				),
+			},
+			{
+				ID:       "OSV-2",
+				Aliases:  []string{"OSV-3"},
+				Affected: affected(osvschema.Event{Introduced: "0"}, osvschema.Event{Fixed: "4.17.12"}),
+			},
+			{
+				ID:       "OSV-3",
+				Aliases:  []string{"OSV-2"},
+				Affected: affected(osvschema.Event{Introduced: "0"}, osvschema.Event{Fixed: "4.17.11"}),
+			},
+			{
+				ID:       "OSV-4",
+				Affected: affected(osvschema.Event{Introduced: "0"}),
+			},
+		},
+	}
+	for _, group := range []models.GroupInfo{{IDs: []string{"OSV-1"}}, {IDs: []string{"OSV-2", "OSV-3"}}, {IDs: []string{"OSV-4"}}} {
+		group.FixedVersion = output.FixedVersion(group, pkg)
+		pkg.Groups = append(pkg.Groups, group)
+	}
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source:   models.SourceInfo{Path: "path/to/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{pkg},
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintTableResults(vulnResult, outputWriter, 0)
+
+	testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
+}
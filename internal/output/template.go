@@ -0,0 +1,59 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/google/osv-scanner/v2/internal/utility/results"
+	"github.com/google/osv-scanner/v2/internal/utility/severity"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+// templateFuncs are the helper functions that are available to custom output templates
+var templateFuncs = template.FuncMap{
+	// osvURL returns the osv.dev URL of the vulnerability with the given ID
+	"osvURL": func(id string) string {
+		return OSVBaseVulnerabilityURL + id
+	},
+	// severityRating returns the rating of a CVSS score, such as the max severity of a group
+	"severityRating": func(score string) string {
+		if score == "" {
+			return string(severity.UnknownRating)
+		}
+
+		rating, err := severity.CalculateRating(score)
+		if err != nil {
+			return string(severity.UnknownRating)
+		}
+
+		return string(rating)
+	},
+	// formatSeverity formats a CVSS score along with its rating, e.g. "HIGH (7.5)"
+	"formatSeverity": markdownSeverity,
+	// vulnScore returns the highest severity score of a vulnerability, or -1 if it has none
+	"vulnScore": severity.CalculateVulnerabilityScore,
+	"pkgString": results.PkgToString,
+	"join":      strings.Join,
+}
+
+// ParseOutputTemplate parses the text of a custom output template, making the
+// helper functions for formatting the results available to it.
+func ParseOutputTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse output template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// PrintTemplateResults prints the results by executing the given template with them
+func PrintTemplateResults(vulnResult *models.VulnerabilityResults, tmpl *template.Template, outputWriter io.Writer) error {
+	if err := tmpl.Execute(outputWriter, vulnResult); err != nil {
+		return fmt.Errorf("failed to execute output template: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/internal/testutility"
+)
+
+const testOutputTemplate = `{{- range .Results }}
+{{ .Source }}
+{{- range .Packages }}
+  {{ pkgString .Package }} ({{ .Package.Ecosystem }})
+  {{- range .Groups }}
+    {{ join .IDs ", " }}: {{ formatSeverity .MaxSeverity }} [{{ severityRating .MaxSeverity }}] {{ osvURL (index .IDs 0) }}
+  {{- end }}
+  {{- range .Vulnerabilities }}
+    {{ .ID }} scores {{ vulnScore . }}
+  {{- end }}
+{{- end }}
+{{- end }}
+`
+
+func TestPrintTemplateResults_WithVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := output.ParseOutputTemplate(testOutputTemplate)
+	if err != nil {
+		t.Fatalf("Error parsing template: %s", err)
+	}
+
+	testOutputWithVulnerabilities(t, func(t *testing.T, args outputTestCaseArgs) {
+		t.Helper()
+
+		outputWriter := &bytes.Buffer{}
+		err := output.PrintTemplateResults(args.vulnResult, tmpl, outputWriter)
+
+		if err != nil {
+			t.Errorf("Error writing template output: %s", err)
+		}
+
+		testutility.NewSnapshot().MatchText(t, outputWriter.String())
+	})
+}
+
+func TestParseOutputTemplate_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := output.ParseOutputTemplate("{{ range .Results }}")
+
+	if err == nil {
+		t.Errorf("Expected an error parsing an incomplete template")
+	}
+
+	_, err = output.ParseOutputTemplate("{{ notAFunction .Results }}")
+
+	if err == nil {
+		t.Errorf("Expected an error parsing a template using an unknown function")
+	}
+}
+
+func TestPrintTemplateResults_ExecutionError(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := output.ParseOutputTemplate("{{ .NotAField }}")
+	if err != nil {
+		t.Fatalf("Error parsing template: %s", err)
+	}
+
+	testOutputWithVulnerabilities(t, func(t *testing.T, args outputTestCaseArgs) {
+		t.Helper()
+
+		err := output.PrintTemplateResults(args.vulnResult, tmpl, &bytes.Buffer{})
+
+		if err == nil {
+			t.Errorf("Expected an error executing a template using an unknown field")
+		}
+	})
+}
@@ -187,9 +187,14 @@ func printVerticalVulnerabilitiesForPackages(packages []PackageResult, out io.Wr
 			state = strings.ToLower(getFilteredVulnReasons(vulns))
 		}
 
+		name := text.FgYellow.Sprintf("%s@%s", pkg.Name, pkg.InstalledVersion)
+		if pkg.DirectnessUnknown {
+			name += " " + directnessUnknownLabel
+		}
+
 		fmt.Fprintf(out,
 			"  %s %s\n",
-			text.FgYellow.Sprintf("%s@%s", pkg.Name, pkg.InstalledVersion),
+			name,
 			text.FgRed.Sprintf("has the following %s vulnerabilities:", state),
 		)
 
@@ -276,5 +281,9 @@ func describe(vulnerability VulnResult) string {
 
 	description += " (" + OSVBaseVulnerabilityURL + vulnerability.ID + ")"
 
+	if vulnerability.KnownExploited {
+		description = text.FgHiRed.Sprintf("[%s] ", knownExploitedLabel) + description
+	}
+
 	return description
 }
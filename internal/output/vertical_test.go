@@ -47,3 +47,12 @@ func TestPrintVerticalResults_WithMixedIssues(t *testing.T) {
 		testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
 	})
 }
+
+func TestPrintVerticalResults_WithDirectnessUnknown(t *testing.T) {
+	t.Parallel()
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintVerticalResults(directnessUnknownResults(), outputWriter)
+
+	testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
+}
@@ -16,12 +16,26 @@ type cycloneDXReporter struct {
 }
 
 func (r *cycloneDXReporter) PrintResult(vulnerabilityResults *models.VulnerabilityResults) error {
-	errs := output.PrintCycloneDXResults(vulnerabilityResults, r.version, r.writer)
+	warnAboutPackageURLErrors(output.PrintCycloneDXResults(vulnerabilityResults, r.version, r.writer))
+
+	return nil
+}
+
+// cycloneDXVEXReporter reports the results as a CycloneDX SBOM with VEX statements about its components
+type cycloneDXVEXReporter struct {
+	writer io.Writer
+}
+
+func (r *cycloneDXVEXReporter) PrintResult(vulnerabilityResults *models.VulnerabilityResults) error {
+	warnAboutPackageURLErrors(output.PrintCycloneDXVEXResults(vulnerabilityResults, r.writer))
+
+	return nil
+}
+
+func warnAboutPackageURLErrors(errs error) {
 	if errs != nil {
 		for _, err := range strings.Split(errs.Error(), "\n") {
 			slog.Warn(fmt.Sprintf("Failed to parse package URL: %v", err))
 		}
 	}
-
-	return nil
 }
@@ -7,7 +7,7 @@ import (
 	"github.com/google/osv-scanner/v2/pkg/models"
 )
 
-var format = []string{"table", "html", "vertical", "json", "markdown", "sarif", "gh-annotations", "cyclonedx-1-4", "cyclonedx-1-5"}
+var format = []string{"table", "html", "vertical", "json", "json-stream", "markdown", "sarif", "gh-annotations", "cyclonedx", "cyclonedx-1-4", "cyclonedx-1-5", "gitlab"}
 
 func Format() []string {
 	return format
@@ -19,6 +19,8 @@ func newResultPrinter(format string, writer io.Writer, terminalWidth int) (resul
 		return &htmlReporter{writer}, nil
 	case "json":
 		return &jsonReporter{writer}, nil
+	case "json-stream":
+		return &jsonStreamReporter{writer}, nil
 	case "vertical":
 		return &verticalReporter{writer, terminalWidth}, nil
 	case "table":
@@ -29,10 +31,14 @@ func newResultPrinter(format string, writer io.Writer, terminalWidth int) (resul
 		return &sarifReporter{writer}, nil
 	case "gh-annotations":
 		return &ghAnnotationsReporter{writer}, nil
+	case "cyclonedx":
+		return &cycloneDXVEXReporter{writer}, nil
 	case "cyclonedx-1-4":
 		return &cycloneDXReporter{writer, models.CycloneDXVersion14}, nil
 	case "cyclonedx-1-5":
 		return &cycloneDXReporter{writer, models.CycloneDXVersion15}, nil
+	case "gitlab":
+		return &gitlabReporter{writer}, nil
 	default:
 		return nil, fmt.Errorf("%v is not a valid format", format)
 	}
@@ -0,0 +1,23 @@
+package reporter
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+type jsonStreamReporter struct {
+	writer io.Writer
+}
+
+func (r *jsonStreamReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	// stop writing sources when interrupted, so that the array can still be closed
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return output.PrintJSONStreamResults(ctx, vulnResult, r.writer)
+}
@@ -3,6 +3,7 @@ package reporter
 import (
 	"io"
 
+	"github.com/google/osv-scanner/v2/internal/output"
 	"github.com/google/osv-scanner/v2/pkg/models"
 )
 
@@ -26,3 +27,21 @@ func PrintResult(
 
 	return r.PrintResult(vulnResult)
 }
+
+// PrintTemplateResult prints the models.VulnerabilityResults using the given
+// text/template, rather than one of the built-in formats
+func PrintTemplateResult(
+	vulnResult *models.VulnerabilityResults,
+	templateText string,
+	writer io.Writer,
+) error {
+	tmpl, err := output.ParseOutputTemplate(templateText)
+
+	if err != nil {
+		return err
+	}
+
+	r := &templateReporter{writer, tmpl}
+
+	return r.PrintResult(vulnResult)
+}
@@ -17,16 +17,18 @@ type tableReporter struct {
 }
 
 func (r *tableReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	// the markdown output reports when there are no vulnerabilities itself
+	if r.markdown {
+		output.PrintMarkdownTableResults(vulnResult, r.writer)
+		return nil
+	}
+
 	if len(vulnResult.Results) == 0 && vulnResult.LicenseSummary == nil && !cmdlogger.HasErrored() {
 		fmt.Fprintf(r.writer, "No issues found\n")
 		return nil
 	}
 
-	if r.markdown {
-		output.PrintMarkdownTableResults(vulnResult, r.writer)
-	} else {
-		output.PrintTableResults(vulnResult, r.writer, r.terminalWidth)
-	}
+	output.PrintTableResults(vulnResult, r.writer, r.terminalWidth)
 
 	return nil
 }
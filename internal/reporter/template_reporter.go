@@ -0,0 +1,18 @@
+package reporter
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+type templateReporter struct {
+	writer   io.Writer
+	template *template.Template
+}
+
+func (r *templateReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	return output.PrintTemplateResults(vulnResult, r.template, r.writer)
+}
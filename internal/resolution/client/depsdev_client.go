@@ -1,9 +1,6 @@
 package client
 
 import (
-	"encoding/gob"
-	"os"
-
 	"deps.dev/util/resolve"
 	"github.com/google/osv-scanner/v2/internal/datasource"
 )
@@ -28,21 +25,9 @@ func NewDepsDevClient(addr string, userAgent string) (*DepsDevClient, error) {
 func (d *DepsDevClient) AddRegistries(_ []Registry) error { return nil }
 
 func (d *DepsDevClient) WriteCache(path string) error {
-	f, err := os.Create(path + depsDevCacheExt)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	return gob.NewEncoder(f).Encode(d.c)
+	return d.c.SaveToFile(path + depsDevCacheExt)
 }
 
 func (d *DepsDevClient) LoadCache(path string) error {
-	f, err := os.Open(path + depsDevCacheExt)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	return gob.NewDecoder(f).Decode(&d.c)
+	return d.c.LoadFromFile(path + depsDevCacheExt)
 }
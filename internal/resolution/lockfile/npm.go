@@ -1,6 +1,9 @@
 package lockfile
 
 import (
+	"crypto/sha1" //nolint:gosec // only used to format the sha1 shasum of older packages
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,8 +16,14 @@ import (
 	"github.com/google/osv-scanner/v2/internal/datasource"
 	"github.com/google/osv-scanner/v2/internal/resolution/depfile"
 	"github.com/google/osv-scanner/v2/internal/resolution/manifest"
+	"github.com/tidwall/gjson"
 )
 
+// ErrNpmMissingIntegrity is returned when the registry does not provide the integrity
+// of a version that a package is being updated to, as writing the lockfile without
+// it would leave the lockfile invalid
+var ErrNpmMissingIntegrity = errors.New("registry did not provide an integrity hash")
+
 type NpmReadWriter struct{}
 
 func (NpmReadWriter) System() resolve.System { return resolve.NPM }
@@ -177,6 +186,20 @@ func (rw NpmReadWriter) reVersionAliasedDeps(deps map[string]npmDependencyVersio
 	}
 }
 
+// npmIntegrity returns the subresource integrity of a package version from its registry data,
+// falling back to the sha1 shasum for older packages that were published without one
+func npmIntegrity(npmData gjson.Result, pkg string) (string, error) {
+	if integrity := npmData.Get("dist.integrity").String(); integrity != "" {
+		return integrity, nil
+	}
+
+	if b, err := hex.DecodeString(npmData.Get("dist.shasum").String()); err == nil && len(b) == sha1.Size {
+		return "sha1-" + base64.StdEncoding.EncodeToString(b), nil
+	}
+
+	return "", fmt.Errorf("%w for %s@%s", ErrNpmMissingIntegrity, pkg, npmData.Get("version").String())
+}
+
 func (rw NpmReadWriter) Write(original depfile.DepFile, output io.Writer, patches []DependencyPatch) error {
 	var buf strings.Builder
 	_, err := io.Copy(&buf, original)
@@ -2,6 +2,7 @@ package lockfile_test
 
 import (
 	"bytes"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -215,3 +216,55 @@ func TestNpmWrite(t *testing.T) {
 	}
 	testutility.NewSnapshot().WithCRLFReplacement().MatchText(t, buf.String())
 }
+
+func TestNpmWriteMissingIntegrity(t *testing.T) {
+	t.Parallel()
+
+	for _, lockfileDir := range []string{"npm_v1", "npm_v2"} {
+		t.Run(lockfileDir, func(t *testing.T) {
+			t.Parallel()
+
+			// Set up mock npm registry that does not provide the integrity of the new version
+			srv := testutility.NewMockHTTPServer(t)
+			srv.SetResponseFromFile(t, "/@fake-registry%2fa/1.2.4", "./fixtures/npm_registry_no_integrity/@fake-registry-a-1.2.4.json")
+
+			dir := testutility.CreateTestDir(t)
+			b, err := os.ReadFile(filepath.Join("fixtures", lockfileDir, "package-lock.json"))
+			if err != nil {
+				t.Fatalf("could not read test file: %v", err)
+			}
+			file := filepath.Join(dir, "package-lock.json")
+			if err := os.WriteFile(file, b, 0600); err != nil {
+				t.Fatalf("could not copy test file: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, ".npmrc"), []byte("registry="+srv.URL), 0600); err != nil {
+				t.Fatalf("failed writing npmrc file: %v", err)
+			}
+
+			patches := []lockfile.DependencyPatch{
+				{
+					Pkg: resolve.PackageKey{
+						System: resolve.NPM,
+						Name:   "@fake-registry/a",
+					},
+					OrigVersion: "1.2.3",
+					NewVersion:  "1.2.4",
+				},
+			}
+
+			err = lockfile.Overwrite(lockfile.NpmReadWriter{}, file, patches)
+			if !errors.Is(err, lockfile.ErrNpmMissingIntegrity) {
+				t.Errorf("expected ErrNpmMissingIntegrity, got %v", err)
+			}
+
+			// the lockfile should be left untouched rather than written without the integrity
+			got, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("could not read test file: %v", err)
+			}
+			if !bytes.Equal(b, got) {
+				t.Errorf("package-lock.json was modified despite the error")
+			}
+		})
+	}
+}
@@ -142,7 +142,11 @@ func (rw NpmReadWriter) modifyPackageLockDependenciesRecurse(lockJSON, path stri
 				}
 				lockJSON, _ = sjson.Set(lockJSON, pkgPath+".version", newVersion)
 				lockJSON, _ = sjson.Set(lockJSON, pkgPath+".resolved", npmData.Get("dist.tarball").String())
-				lockJSON, _ = sjson.Set(lockJSON, pkgPath+".integrity", npmData.Get("dist.integrity").String())
+				integrity, err := npmIntegrity(npmData, pkg)
+				if err != nil {
+					return lockJSON, err
+				}
+				lockJSON, _ = sjson.Set(lockJSON, pkgPath+".integrity", integrity)
 				// formatting & padding to output for the correct level at this depth
 				pretty := fmt.Sprintf("|@pretty:{\"prefix\": %q}", strings.Repeat(" ", 4*depth+2))
 				reqs := npmData.Get("dependencies" + pretty)
@@ -265,7 +265,11 @@ func (rw NpmReadWriter) updatePackage(jsonText, jsonPath, packageName, newVersio
 		case "resolved":
 			pkgText, _ = sjson.Set(pkgText, "resolved", npmData.Get("dist.tarball").String())
 		case "integrity":
-			pkgText, _ = sjson.Set(pkgText, "integrity", npmData.Get("dist.integrity").String())
+			integrity, err := npmIntegrity(npmData, packageName)
+			if err != nil {
+				return "", err
+			}
+			pkgText, _ = sjson.Set(pkgText, "integrity", integrity)
 		case "bin":
 			// the api formats the paths as "./path/to", while package-lock.json seem to use "path/to"
 			// TODO: smarter way for indentation
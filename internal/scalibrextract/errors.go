@@ -1,8 +1,26 @@
 package scalibrextract
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var ErrIncompatibleFileFormat = errors.New("file format is incompatible, but this is expected")
 var ErrNotImplemented = errors.New("not implemented")
 var ErrWrongExtractor = errors.New("this extractor did not create this inventory")
 var ErrExtractorNotFound = errors.New("could not determine extractor suitable to this file")
+var ErrExtractorPanicked = errors.New("extractor panicked")
+
+// ExtractionError is returned when an extractor fails to extract a file, such as because it is malformed
+type ExtractionError struct {
+	Extractor string
+	Err       error
+}
+
+func (e *ExtractionError) Error() string {
+	return fmt.Sprintf("(extracting as %s) %v", e.Extractor, e.Err)
+}
+
+func (e *ExtractionError) Unwrap() error {
+	return e.Err
+}
@@ -3,6 +3,7 @@ package scalibrextract
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -92,27 +93,115 @@ func extractWithExtractor(ctx context.Context, localPath string, info fs.FileInf
 		return nil, err
 	}
 
-	invs, err := ext.Extract(ctx, si)
+	invs, err := extract(ctx, si, ext)
 	if err != nil {
-		return nil, fmt.Errorf("(extracting as %s) %w", ext.Name(), err)
+		return nil, &ExtractionError{Extractor: ext.Name(), Err: err}
 	}
 
 	for i := range invs {
-		// Set parent extractor
-		invs[i].Extractor = ext
-
 		// Make Location relative to the scan root as we are performing local scanning
 		for i2 := range invs[i].Locations {
 			invs[i].Locations[i2] = filepath.Join(rootDir, invs[i].Locations[i2])
 		}
 	}
 
+	return finalizeInventories(invs, ext), nil
+}
+
+// ExtractReaderWithExtractor extracts the contents of r with the extractor passed in,
+// for lockfiles that do not exist on disk (such as those piped through stdin).
+//
+// The logicalPath is used as the location of the extracted packages and in error messages,
+// while any other files needed by the extractor are opened relative to the working directory.
+func ExtractReaderWithExtractor(ctx context.Context, logicalPath string, r io.Reader, ext filesystem.Extractor) ([]*extractor.Inventory, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	si := &filesystem.ScanInput{
+		FS:     os.DirFS(wd).(scalibrfs.FS),
+		Path:   logicalPath,
+		Root:   wd,
+		Reader: r,
+	}
+
+	invs, err := extract(ctx, si, ext)
+	if err != nil {
+		return nil, &ExtractionError{Extractor: ext.Name(), Err: err}
+	}
+
+	return finalizeInventories(invs, ext), nil
+}
+
+// ExtractFSWithExtractors attempts to extract the file at the given path within fsys
+// by choosing the extractor which passes the FileRequired test, for lockfiles that are
+// not on disk (such as those within archives).
+//
+// The locations of the extracted packages are relative to the root of fsys, which is
+// also what any other files needed by the extractor are opened relative to.
+//
+// If no extractors are found, then ErrExtractorNotFound is returned.
+func ExtractFSWithExtractors(ctx context.Context, fsys scalibrfs.FS, path string, extractors []filesystem.Extractor) ([]*extractor.Inventory, error) {
+	info, err := fs.Stat(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*extractor.Inventory{}
+	extractorFound := false
+	for _, ext := range extractors {
+		if !ext.FileRequired(simplefileapi.New(path, info)) {
+			continue
+		}
+		extractorFound = true
+
+		f, err := fsys.Open(path)
+		if err != nil {
+			return nil, err
+		}
+
+		invs, err := extract(ctx, &filesystem.ScanInput{FS: fsys, Path: path, Reader: f, Info: info}, ext)
+		f.Close()
+		if err != nil {
+			return nil, &ExtractionError{Extractor: ext.Name(), Err: err}
+		}
+
+		result = append(result, finalizeInventories(invs, ext)...)
+	}
+
+	if !extractorFound {
+		return nil, ErrExtractorNotFound
+	}
+
+	return result, nil
+}
+
+// extract runs the extractor on the scan input, returning an error if the extractor panics
+// (e.g. on a malformed file) so that a single file cannot crash the whole scan
+func extract(ctx context.Context, si *filesystem.ScanInput, ext filesystem.Extractor) (invs []*extractor.Inventory, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			invs, err = nil, fmt.Errorf("%w: %v", ErrExtractorPanicked, r)
+		}
+	}()
+
+	return ext.Extract(ctx, si)
+}
+
+// finalizeInventories sets the extractor of the inventories, and sorts and deduplicates them
+func finalizeInventories(invs []*extractor.Inventory, ext filesystem.Extractor) []*extractor.Inventory {
+	for i := range invs {
+		// Set parent extractor
+		invs[i].Extractor = ext
+	}
+
 	slices.SortFunc(invs, inventorySort)
 	invsCompact := slices.CompactFunc(invs, func(a, b *extractor.Inventory) bool {
 		return inventorySort(a, b) == 0
 	})
 
-	return invsCompact, nil
+	return invsCompact
 }
 
 func createScanInput(path string, root string, fileInfo fs.FileInfo) (*filesystem.ScanInput, error) {
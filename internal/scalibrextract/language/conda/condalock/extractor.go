@@ -0,0 +1,197 @@
+// Package condalock extracts conda-lock.yml files.
+package condalock
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/osv"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"gopkg.in/yaml.v3"
+)
+
+// Name is the unique name of this extractor.
+const Name = "conda/condalock"
+
+// Metadata holds the conda specific information of a locked package.
+type Metadata struct {
+	// Manager is the package manager that installs the package, either "conda" or "pip"
+	Manager string
+	// Channel is the conda channel the package was locked from, which is empty for pip packages
+	Channel string
+	// Platforms are the platforms (e.g. linux-64, osx-arm64) that the package is locked for
+	Platforms    []string
+	DepGroupVals []string
+}
+
+var _ osv.DepGroups = Metadata{}
+
+// DepGroups return the dependency groups property in the metadata
+func (m Metadata) DepGroups() []string {
+	return m.DepGroupVals
+}
+
+type condaLockPackage struct {
+	Name     string `yaml:"name"`
+	Version  string `yaml:"version"`
+	Manager  string `yaml:"manager"`
+	Platform string `yaml:"platform"`
+	URL      string `yaml:"url"`
+	Category string `yaml:"category"`
+}
+
+type condaLockFile struct {
+	Version int                `yaml:"version"`
+	Package []condaLockPackage `yaml:"package"`
+}
+
+// Extractor extracts conda and pip packages from the unified lockfiles written by conda-lock.
+//
+// Packages installed by pip are in the PyPI ecosystem. There is no OSV ecosystem for conda
+// packages, so these are extracted without one.
+type Extractor struct{}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for conda-lock.yml files, including those named
+// for a specific environment (e.g. dev.conda-lock.yml)
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	base := filepath.Base(fapi.Path())
+
+	return base == "conda-lock.yml" || strings.HasSuffix(base, ".conda-lock.yml")
+}
+
+// Extract extracts packages from conda-lock.yml files passed through the scan input.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	var lockfile condaLockFile
+	if err := yaml.NewDecoder(input.Reader).Decode(&lockfile); err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+	if lockfile.Version != 1 {
+		return nil, fmt.Errorf("could not extract from %s: unsupported lockfile version %d", input.Path, lockfile.Version)
+	}
+
+	// packages are locked separately for each platform, so they are
+	// merged to avoid reporting the same package multiple times
+	type pkgKey struct{ manager, name, version string }
+	packages := make(map[pkgKey]*extractor.Inventory)
+	var order []pkgKey
+	for _, pkg := range lockfile.Package {
+		if pkg.Name == "" || pkg.Version == "" {
+			continue
+		}
+
+		groups := []string{}
+		if pkg.Category != "" && pkg.Category != "main" {
+			groups = []string{pkg.Category}
+		}
+
+		key := pkgKey{pkg.Manager, pkg.Name, pkg.Version}
+		if existing, ok := packages[key]; ok {
+			metadata := existing.Metadata.(*Metadata)
+			if !slices.Contains(metadata.Platforms, pkg.Platform) {
+				metadata.Platforms = append(metadata.Platforms, pkg.Platform)
+			}
+			// a package that is in the main category for any platform is not in a group
+			if len(groups) == 0 {
+				metadata.DepGroupVals = []string{}
+			}
+
+			continue
+		}
+
+		metadata := &Metadata{
+			Manager:      pkg.Manager,
+			Platforms:    []string{pkg.Platform},
+			DepGroupVals: groups,
+		}
+		if pkg.Manager == "conda" {
+			metadata.Channel = channelFromURL(pkg.URL)
+		}
+
+		packages[key] = &extractor.Inventory{
+			Name:      pkg.Name,
+			Version:   pkg.Version,
+			Locations: []string{input.Path},
+			Metadata:  metadata,
+		}
+		order = append(order, key)
+	}
+
+	result := make([]*extractor.Inventory, 0, len(order))
+	for _, key := range order {
+		result = append(result, packages[key])
+	}
+
+	return result, nil
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(i *extractor.Inventory) *purl.PackageURL {
+	if isPip(i) {
+		return &purl.PackageURL{
+			Type:    purl.TypePyPi,
+			Name:    strings.ToLower(i.Name),
+			Version: i.Version,
+		}
+	}
+
+	return &purl.PackageURL{
+		Type:    purl.TypeConda,
+		Name:    i.Name,
+		Version: i.Version,
+	}
+}
+
+// Ecosystem returns the OSV ecosystem ('PyPI') of packages installed by pip, and an
+// empty string for conda packages as they do not have an ecosystem.
+func (e Extractor) Ecosystem(i *extractor.Inventory) string {
+	if isPip(i) {
+		return string(osvschema.EcosystemPyPI)
+	}
+
+	return ""
+}
+
+func isPip(i *extractor.Inventory) bool {
+	m, ok := i.Metadata.(*Metadata)
+
+	return ok && m.Manager == "pip"
+}
+
+// channelFromURL gets the channel of a conda package from the url it was locked to,
+// which is in the form of `<channel>/<platform>/<filename>`
+// (e.g. https://conda.anaconda.org/conda-forge/linux-64/numpy-1.26.4-py312heda63a1_0.conda)
+func channelFromURL(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return ""
+	}
+
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(segments) < 3 {
+		return ""
+	}
+
+	return path.Join(segments[:len(segments)-2]...)
+}
@@ -0,0 +1,118 @@
+package condalock_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/conda/condalock"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "invalid yaml",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/not-yaml.txt",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "could not extract from"},
+		},
+		{
+			Name: "unsupported lockfile version",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/unsupported-version.yml",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "unsupported lockfile version 2"},
+		},
+		{
+			Name: "conda and pip packages",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/conda-lock.yml",
+			},
+			WantInventory: []*extractor.Inventory{
+				{
+					Name:      "numpy",
+					Version:   "1.26.4",
+					Locations: []string{"testdata/conda-lock.yml"},
+					Metadata: &condalock.Metadata{
+						Manager:      "conda",
+						Channel:      "conda-forge",
+						Platforms:    []string{"linux-64", "osx-arm64"},
+						DepGroupVals: []string{},
+					},
+				},
+				{
+					Name:      "openssl",
+					Version:   "3.2.1",
+					Locations: []string{"testdata/conda-lock.yml"},
+					Metadata: &condalock.Metadata{
+						Manager:      "conda",
+						Channel:      "pkgs/main",
+						Platforms:    []string{"linux-64"},
+						DepGroupVals: []string{},
+					},
+				},
+				{
+					Name:      "pytest",
+					Version:   "8.0.2",
+					Locations: []string{"testdata/conda-lock.yml"},
+					Metadata: &condalock.Metadata{
+						Manager:      "conda",
+						Channel:      "conda-forge",
+						Platforms:    []string{"linux-64"},
+						DepGroupVals: []string{"dev"},
+					},
+				},
+				{
+					Name:      "requests",
+					Version:   "2.31.0",
+					Locations: []string{"testdata/conda-lock.yml"},
+					Metadata: &condalock.Metadata{
+						Manager:      "pip",
+						Platforms:    []string{"linux-64", "osx-arm64"},
+						DepGroupVals: []string{},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := condalock.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got, cmpopts.SortSlices(extracttest.InventoryCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
+
+func TestExtractor_Ecosystem(t *testing.T) {
+	t.Parallel()
+
+	extr := condalock.Extractor{}
+
+	if got := extr.Ecosystem(&extractor.Inventory{Metadata: &condalock.Metadata{Manager: "pip"}}); got != "PyPI" {
+		t.Errorf("Ecosystem() of pip package = %q, want %q", got, "PyPI")
+	}
+	if got := extr.Ecosystem(&extractor.Inventory{Metadata: &condalock.Metadata{Manager: "conda"}}); got != "" {
+		t.Errorf("Ecosystem() of conda package = %q, want %q", got, "")
+	}
+}
@@ -0,0 +1,188 @@
+// Package environmentyml extracts conda environment.yml files, including their pip dependencies.
+package environmentyml
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/python/requirements"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"gopkg.in/yaml.v3"
+)
+
+// Name is the unique name of this extractor.
+const Name = "conda/environmentyml"
+
+// Metadata holds the conda specific information of a package.
+type Metadata struct {
+	// Channel is the channel the package is required from (e.g. `conda-forge::numpy`),
+	// which is empty if the package can come from any channel of the environment
+	Channel string
+	// Build is the build string the package is pinned to (e.g. `numpy=1.26.4=py312heda63a1_0`)
+	Build string
+}
+
+// a spec for a package, in the MatchSpec format of `[channel::]name[version[build]]`,
+// where the version and build are separated by either `=` or whitespace
+// https://docs.conda.io/projects/conda-build/en/stable/resources/package-spec.html
+var reMatchSpec = cachedregexp.MustCompile(`^(?:([^:\s]+)::)?([A-Za-z0-9_.-]+)\s*(?:(==|>=|=|~=|\s)\s*([^=\s]+)(?:[=\s]\s*(\S+))?)?$`)
+
+// We currently don't handle the following version constraints.
+// * Version wildcards, other than a trailing `.*` (*)
+// * Less than and not equal to (<, !)
+// * Multiple constraints (",", "|")
+var reUnsupportedVersion = cachedregexp.MustCompile(`\*|<|!|,|\|`)
+
+type environmentDependency struct {
+	spec string
+	pip  []string
+}
+
+func (d *environmentDependency) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&d.spec)
+	}
+
+	var nested struct {
+		Pip []string `yaml:"pip"`
+	}
+	if err := value.Decode(&nested); err != nil {
+		return err
+	}
+	d.pip = nested.Pip
+
+	return nil
+}
+
+type environmentFile struct {
+	Dependencies []environmentDependency `yaml:"dependencies"`
+}
+
+// Extractor extracts conda packages and pip requirements from conda environment.yml files.
+//
+// Packages in the nested `pip:` section are parsed as requirements.txt lines, and are in
+// the PyPI ecosystem. There is no OSV ecosystem for conda packages, so these are extracted
+// without one.
+type Extractor struct{}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for environment.yml files
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	base := filepath.Base(fapi.Path())
+
+	return base == "environment.yml" || base == "environment.yaml"
+}
+
+// Extract extracts packages from environment.yml files passed through the scan input.
+func (e Extractor) Extract(ctx context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	var env environmentFile
+	if err := yaml.NewDecoder(input.Reader).Decode(&env); err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	packages := make([]*extractor.Inventory, 0, len(env.Dependencies))
+	var pipLines []string
+	for _, dep := range env.Dependencies {
+		pipLines = append(pipLines, dep.pip...)
+		if dep.spec == "" {
+			continue
+		}
+
+		pkg := parseMatchSpec(dep.spec)
+		if pkg == nil {
+			continue
+		}
+		pkg.Locations = []string{input.Path}
+		packages = append(packages, pkg)
+	}
+
+	if len(pipLines) > 0 {
+		pipPackages, err := extractPipRequirements(ctx, input, pipLines)
+		if err != nil {
+			return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+		}
+		packages = append(packages, pipPackages...)
+	}
+
+	return packages, nil
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(i *extractor.Inventory) *purl.PackageURL {
+	if _, ok := i.Metadata.(*requirements.Metadata); ok {
+		return requirements.Extractor{}.ToPURL(i)
+	}
+
+	return &purl.PackageURL{
+		Type:    purl.TypeConda,
+		Name:    i.Name,
+		Version: i.Version,
+	}
+}
+
+// Ecosystem returns the OSV ecosystem ('PyPI') of pip requirements, and an empty
+// string for conda packages as they do not have an ecosystem.
+func (e Extractor) Ecosystem(i *extractor.Inventory) string {
+	if _, ok := i.Metadata.(*requirements.Metadata); ok {
+		return string(osvschema.EcosystemPyPI)
+	}
+
+	return ""
+}
+
+// extractPipRequirements extracts the requirements of the `pip:` section, which pip is given
+// as a requirements file in the same directory as the environment.yml.
+func extractPipRequirements(ctx context.Context, input *filesystem.ScanInput, lines []string) ([]*extractor.Inventory, error) {
+	return requirements.Extractor{}.Extract(ctx, &filesystem.ScanInput{
+		FS:     input.FS,
+		Path:   input.Path,
+		Root:   input.Root,
+		Info:   input.Info,
+		Reader: strings.NewReader(strings.Join(lines, "\n")),
+	})
+}
+
+// parseMatchSpec parses a conda package spec, returning nil if the spec is for a package
+// without a version or that has a version constraint that is not supported.
+func parseMatchSpec(spec string) *extractor.Inventory {
+	match := reMatchSpec.FindStringSubmatch(strings.TrimSpace(spec))
+	if match == nil {
+		return nil
+	}
+	channel, name, version, build := match[1], match[2], match[4], match[5]
+
+	// a fuzzy version such as `=1.26` or `1.26.*` matches any version starting with it,
+	// the lowest of which is the version itself
+	version = strings.TrimSuffix(version, ".*")
+	if version == "" || reUnsupportedVersion.MatchString(version) {
+		return nil
+	}
+
+	return &extractor.Inventory{
+		Name:    name,
+		Version: version,
+		Metadata: &Metadata{
+			Channel: channel,
+			Build:   build,
+		},
+	}
+}
@@ -0,0 +1,111 @@
+package environmentyml_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/conda/environmentyml"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/python/requirements"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "invalid yaml",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/not-yaml.txt",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "could not extract from"},
+		},
+		{
+			Name: "no pip dependencies",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/no-pip.yml",
+			},
+			WantInventory: []*extractor.Inventory{
+				condaPackage("python", "3.12", "", "", "testdata/no-pip.yml"),
+			},
+		},
+		{
+			Name: "conda and pip dependencies",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/environment.yml",
+			},
+			WantInventory: []*extractor.Inventory{
+				condaPackage("python", "3.11", "", "", "testdata/environment.yml"),
+				condaPackage("numpy", "1.26.4", "", "py311h64a7726_0", "testdata/environment.yml"),
+				condaPackage("pandas", "2.1.0", "conda-forge", "", "testdata/environment.yml"),
+				condaPackage("scipy", "1.11", "", "", "testdata/environment.yml"),
+				condaPackage("scikit-learn", "1.3.2", "", "py311hc009520_1", "testdata/environment.yml"),
+				condaPackage("matplotlib", "3.8", "", "", "testdata/environment.yml"),
+				{
+					Name:      "requests",
+					Version:   "2.31.0",
+					Locations: []string{"testdata/environment.yml"},
+					Metadata: &requirements.Metadata{
+						HashCheckingModeValues: []string{},
+						VersionComparator:      "==",
+					},
+				},
+				{
+					Name:      "flask",
+					Version:   "2.0",
+					Locations: []string{"testdata/environment.yml"},
+					Metadata: &requirements.Metadata{
+						HashCheckingModeValues: []string{},
+						VersionComparator:      ">=",
+						Marker:                 `python_version >= "3.8"`,
+					},
+				},
+				{
+					Name:      "click",
+					Version:   "8.1.7",
+					Locations: []string{"testdata/environment.yml:testdata/requirements.txt"},
+					Metadata: &requirements.Metadata{
+						HashCheckingModeValues: []string{},
+						VersionComparator:      "==",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := environmentyml.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got, cmpopts.SortSlices(extracttest.InventoryCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
+
+func condaPackage(name, version, channel, build, location string) *extractor.Inventory {
+	return &extractor.Inventory{
+		Name:      name,
+		Version:   version,
+		Locations: []string{location},
+		Metadata: &environmentyml.Metadata{
+			Channel: channel,
+			Build:   build,
+		},
+	}
+}
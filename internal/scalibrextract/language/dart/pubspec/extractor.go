@@ -0,0 +1,158 @@
+// Package pubspec extracts pubspec.lock files, only scanning hosted packages against the Pub ecosystem.
+package pubspec
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/dart/pubspec"
+	"github.com/google/osv-scalibr/extractor/filesystem/osv"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"gopkg.in/yaml.v3"
+)
+
+// Name is the unique name of this extractor.
+const Name = pubspec.Name
+
+// Metadata holds the pubspec.lock specific information of a locked package.
+type Metadata struct {
+	// Source is where the package is from, such as "hosted", "git", "path", or "sdk"
+	Source       string
+	DepGroupVals []string
+}
+
+var _ osv.DepGroups = Metadata{}
+
+// DepGroups return the dependency groups property in the metadata
+func (m Metadata) DepGroups() []string {
+	return m.DepGroupVals
+}
+
+type pubspecLockDescription struct {
+	URL         string `yaml:"url"`
+	ResolvedRef string `yaml:"resolved-ref"`
+}
+
+var _ yaml.Unmarshaler = &pubspecLockDescription{}
+
+// UnmarshalYAML decodes the description of a package, which is just the name of the
+// sdk for packages from an sdk, rather than a map like for other sources
+func (pld *pubspecLockDescription) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	type description pubspecLockDescription
+
+	return value.Decode((*description)(pld))
+}
+
+type pubspecLockPackage struct {
+	Dependency  string                 `yaml:"dependency"`
+	Description pubspecLockDescription `yaml:"description"`
+	Source      string                 `yaml:"source"`
+	Version     string                 `yaml:"version"`
+}
+
+type pubspecLockfile struct {
+	Packages map[string]pubspecLockPackage `yaml:"packages"`
+}
+
+// Extractor extracts the packages of pubspec.lock files.
+//
+// Only packages that are hosted on a package repository are in the Pub ecosystem, as packages
+// from git repositories may have been changed from what was published, and path and sdk packages
+// are part of the project or the sdk. Packages from git repositories are scanned by the commit
+// that they are locked to instead, while path and sdk packages are extracted without an ecosystem
+// so that they are recorded, but are not scanned.
+type Extractor struct {
+	actualExtractor pubspec.Extractor
+}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for pubspec.lock files
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	return e.actualExtractor.FileRequired(fapi)
+}
+
+// Extract extracts packages from pubspec.lock files passed through the scan input.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	var lockfile pubspecLockfile
+	if err := yaml.NewDecoder(input.Reader).Decode(&lockfile); err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	packages := make([]*extractor.Inventory, 0, len(lockfile.Packages))
+	for name, pkg := range lockfile.Packages {
+		depGroups := []string{}
+		if slices.Contains(strings.Fields(pkg.Dependency), "dev") {
+			depGroups = []string{"dev"}
+		}
+
+		inv := &extractor.Inventory{
+			Name:      name,
+			Version:   pkg.Version,
+			Locations: []string{input.Path},
+			Metadata: &Metadata{
+				Source:       pkg.Source,
+				DepGroupVals: depGroups,
+			},
+		}
+
+		if pkg.Source == "git" && pkg.Description.ResolvedRef != "" {
+			inv.SourceCode = &extractor.SourceCodeIdentifier{
+				Repo:   pkg.Description.URL,
+				Commit: pkg.Description.ResolvedRef,
+			}
+		}
+
+		packages = append(packages, inv)
+	}
+
+	return packages, nil
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(i *extractor.Inventory) *purl.PackageURL {
+	if !isHosted(i) {
+		return nil
+	}
+
+	return e.actualExtractor.ToPURL(i)
+}
+
+// Ecosystem returns the OSV ecosystem ('Pub') of hosted packages, and an empty
+// string for all other packages as they cannot be matched against the ecosystem.
+func (e Extractor) Ecosystem(i *extractor.Inventory) string {
+	if !isHosted(i) {
+		return ""
+	}
+
+	return string(osvschema.EcosystemPub)
+}
+
+// isHosted returns true if the package is from a package repository such as pub.dev,
+// which older lockfiles that do not have a source are assumed to be
+func isHosted(i *extractor.Inventory) bool {
+	m, ok := i.Metadata.(*Metadata)
+
+	return !ok || m.Source == "" || m.Source == "hosted"
+}
@@ -0,0 +1,109 @@
+package pubspec_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/dart/pubspec"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "invalid yaml",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/not-yaml.txt",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "could not extract from"},
+		},
+		{
+			Name: "mixed sources",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/mixed-sources.lock",
+			},
+			WantInventory: []*extractor.Inventory{
+				lockedPackage("async", "2.11.0", "hosted"),
+				lockedPackage("build_runner", "2.4.9", "hosted", "dev"),
+				lockedPackage("flutter", "0.0.0", "sdk"),
+				lockedPackage("flutter_localizations", "0.0.0", "sdk"),
+				lockedPackage("http", "1.2.1", "hosted"),
+				lockedPackage("shared_ui", "0.1.0", "path"),
+				{
+					Name:      "window_manager",
+					Version:   "0.3.8",
+					Locations: []string{"testdata/mixed-sources.lock"},
+					SourceCode: &extractor.SourceCodeIdentifier{
+						Repo:   "https://github.com/leanflutter/window_manager.git",
+						Commit: "88487257cbafc501599ab4f82ec343b46acec020",
+					},
+					Metadata: &pubspec.Metadata{Source: "git", DepGroupVals: []string{}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := pubspec.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got, cmpopts.SortSlices(extracttest.InventoryCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
+
+func TestExtractor_Ecosystem(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{source: "hosted", want: "Pub"},
+		{source: "", want: "Pub"},
+		{source: "git", want: ""},
+		{source: "path", want: ""},
+		{source: "sdk", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			t.Parallel()
+
+			inv := lockedPackage("http", "1.2.1", tt.source)
+			if got := (pubspec.Extractor{}).Ecosystem(inv); got != tt.want {
+				t.Errorf("Ecosystem() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func lockedPackage(name, version, source string, depGroups ...string) *extractor.Inventory {
+	if depGroups == nil {
+		depGroups = []string{}
+	}
+
+	return &extractor.Inventory{
+		Name:      name,
+		Version:   version,
+		Locations: []string{"testdata/mixed-sources.lock"},
+		Metadata:  &pubspec.Metadata{Source: source, DepGroupVals: depGroups},
+	}
+}
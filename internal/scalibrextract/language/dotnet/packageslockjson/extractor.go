@@ -0,0 +1,136 @@
+// Package packageslockjson extracts NuGet packages.lock.json files.
+package packageslockjson
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/dotnet/packageslockjson"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+)
+
+// Name is the unique name of this extractor.
+const Name = packageslockjson.Name
+
+// Metadata holds the information of a package extracted from a packages.lock.json file.
+type Metadata struct {
+	// IsTransitive is true if the package is only depended on through other
+	// packages, in every target framework that it is used in
+	IsTransitive bool
+	// TargetFrameworks are the target framework monikers (e.g. "net8.0") that
+	// the package is resolved for
+	TargetFrameworks []string
+}
+
+type lockPackage struct {
+	// Type is either "Direct", "Transitive", "CentralTransitive" or "Project"
+	Type     string `json:"type"`
+	Resolved string `json:"resolved"`
+}
+
+type lockfile struct {
+	// Dependencies are the packages of each target framework moniker
+	Dependencies map[string]map[string]lockPackage `json:"dependencies"`
+}
+
+// Extractor extracts NuGet packages from packages.lock.json files.
+//
+// The packages of every target framework are flattened into a single list, with
+// packages that are resolved for multiple target frameworks being reported once.
+// Project references are skipped as they are part of the solution itself.
+type Extractor struct {
+	actualExtractor packageslockjson.Extractor
+}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for packages.lock.json files
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	return e.actualExtractor.FileRequired(fapi)
+}
+
+// Extract extracts packages from packages.lock.json files passed through the scan input.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	var parsed lockfile
+	if err := json.NewDecoder(input.Reader).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	// the target frameworks are sorted so that the output is deterministic
+	frameworks := make([]string, 0, len(parsed.Dependencies))
+	for framework := range parsed.Dependencies {
+		frameworks = append(frameworks, framework)
+	}
+	slices.Sort(frameworks)
+
+	packages := make(map[string]*extractor.Inventory)
+	for _, framework := range frameworks {
+		for name, pkg := range parsed.Dependencies[framework] {
+			if pkg.Type == "Project" || pkg.Resolved == "" {
+				continue
+			}
+
+			isTransitive := pkg.Type != "Direct"
+
+			// package names are case-insensitive in NuGet
+			key := strings.ToLower(name) + "@" + pkg.Resolved
+			if existing, ok := packages[key]; ok {
+				metadata := existing.Metadata.(*Metadata)
+				metadata.IsTransitive = metadata.IsTransitive && isTransitive
+				metadata.TargetFrameworks = append(metadata.TargetFrameworks, framework)
+
+				continue
+			}
+
+			packages[key] = &extractor.Inventory{
+				Name:      name,
+				Version:   pkg.Resolved,
+				Locations: []string{input.Path},
+				Metadata: &Metadata{
+					IsTransitive:     isTransitive,
+					TargetFrameworks: []string{framework},
+				},
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(packages))
+	for key := range packages {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	result := make([]*extractor.Inventory, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, packages[key])
+	}
+
+	return result, nil
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(i *extractor.Inventory) *purl.PackageURL {
+	return e.actualExtractor.ToPURL(i)
+}
+
+// Ecosystem returns the OSV ecosystem ('NuGet') of the software extracted by this extractor.
+func (e Extractor) Ecosystem(i *extractor.Inventory) string {
+	return e.actualExtractor.Ecosystem(i)
+}
@@ -0,0 +1,91 @@
+package packageslockjson_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/dotnet/packageslockjson"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "invalid json",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/not-json.txt",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "could not extract from"},
+		},
+		{
+			Name: "multiple target frameworks",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/multiple-frameworks.json",
+			},
+			WantInventory: []*extractor.Inventory{
+				{
+					Name:      "newtonsoft.json",
+					Version:   "13.0.1",
+					Locations: []string{"testdata/multiple-frameworks.json"},
+					Metadata: &packageslockjson.Metadata{
+						IsTransitive:     false,
+						TargetFrameworks: []string{"net6.0", "net8.0"},
+					},
+				},
+				{
+					Name:      "Serilog",
+					Version:   "2.10.0",
+					Locations: []string{"testdata/multiple-frameworks.json"},
+					Metadata: &packageslockjson.Metadata{
+						IsTransitive:     true,
+						TargetFrameworks: []string{"net6.0"},
+					},
+				},
+				{
+					Name:      "Serilog",
+					Version:   "3.1.1",
+					Locations: []string{"testdata/multiple-frameworks.json"},
+					Metadata: &packageslockjson.Metadata{
+						IsTransitive:     false,
+						TargetFrameworks: []string{"net8.0"},
+					},
+				},
+				{
+					Name:      "System.Text.Encodings.Web",
+					Version:   "4.7.2",
+					Locations: []string{"testdata/multiple-frameworks.json"},
+					Metadata: &packageslockjson.Metadata{
+						IsTransitive:     true,
+						TargetFrameworks: []string{"net6.0", "net8.0"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := packageslockjson.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
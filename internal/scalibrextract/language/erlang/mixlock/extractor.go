@@ -0,0 +1,178 @@
+// Package mixlock extracts mix.lock files, parsing the Elixir terms that they are written in.
+package mixlock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/erlang/mixlock"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// Name is the unique name of this extractor.
+const Name = mixlock.Name
+
+// publicHexRepository is the name of the repository of the packages on hex.pm,
+// rather than one of the private repositories of an organization
+const publicHexRepository = "hexpm"
+
+var errNotAMap = errors.New("the lockfile is not a map")
+
+// Metadata holds the mix.lock specific information of a locked package.
+type Metadata struct {
+	// HexRepository is the Hex repository that the package is from, which is "hexpm"
+	// for public packages or "hexpm:<organization>" for private packages, and is
+	// empty for packages from git repositories
+	HexRepository string
+}
+
+// Extractor extracts the packages of mix.lock files.
+//
+// Only the packages from the public Hex repository are in the Hex ecosystem.
+// Packages from git repositories are scanned by the commit they are locked to
+// instead, while packages from private Hex repositories are extracted without
+// an ecosystem, as they can only be matched against unrelated public packages.
+type Extractor struct {
+	actualExtractor mixlock.Extractor
+}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for mix.lock files
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	return e.actualExtractor.FileRequired(fapi)
+}
+
+// Extract extracts packages from mix.lock files passed through the scan input.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	content, err := io.ReadAll(input.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	term, err := parseTerm(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	entries, ok := term.([]termPair)
+	if !ok {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, errNotAMap)
+	}
+
+	packages := make([]*extractor.Inventory, 0, len(entries))
+	for _, entry := range entries {
+		// mix writes the name of each dependency as an atom with quotes (e.g. "plug": {...})
+		var name string
+		switch key := entry.Key.(type) {
+		case termAtom:
+			name = string(key)
+		case string:
+			name = key
+		default:
+			continue
+		}
+
+		if inv := toInventory(name, entry.Value); inv != nil {
+			inv.Locations = []string{input.Path}
+			packages = append(packages, inv)
+		}
+	}
+
+	return packages, nil
+}
+
+// toInventory converts the lock of a dependency to an inventory, returning nil
+// for locks that are not from Hex or a git repository.
+//
+// The locks of Hex packages are of the form
+// {:hex, :name, "version", "checksum", [build tools], [dependencies], "repository", "checksum"},
+// though lockfiles written by older versions of mix do not have all of the elements,
+// while the locks of git dependencies are of the form {:git, "url", "commit", [options]}.
+func toInventory(dependency string, lock any) *extractor.Inventory {
+	elements, ok := lock.(termTuple)
+	if !ok || len(elements) < 3 {
+		return nil
+	}
+
+	switch elements[0] {
+	case termAtom("hex"):
+		name, ok := elements[1].(termAtom)
+		version, isString := elements[2].(string)
+		if !ok || !isString {
+			return nil
+		}
+
+		repository := publicHexRepository
+		if len(elements) > 6 {
+			if repo, ok := elements[6].(string); ok {
+				repository = repo
+			}
+		}
+
+		return &extractor.Inventory{
+			Name:     string(name),
+			Version:  version,
+			Metadata: &Metadata{HexRepository: repository},
+		}
+	case termAtom("git"):
+		repo, ok := elements[1].(string)
+		commit, isString := elements[2].(string)
+		if !ok || !isString {
+			return nil
+		}
+
+		return &extractor.Inventory{
+			Name: dependency,
+			SourceCode: &extractor.SourceCodeIdentifier{
+				Repo:   repo,
+				Commit: commit,
+			},
+			Metadata: &Metadata{},
+		}
+	default:
+		return nil
+	}
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(i *extractor.Inventory) *purl.PackageURL {
+	if !isPublicHex(i) {
+		return nil
+	}
+
+	return e.actualExtractor.ToPURL(i)
+}
+
+// Ecosystem returns the OSV ecosystem ('Hex') of packages from the public Hex
+// repository, and an empty string for all other packages.
+func (e Extractor) Ecosystem(i *extractor.Inventory) string {
+	if !isPublicHex(i) {
+		return ""
+	}
+
+	return string(osvschema.EcosystemHex)
+}
+
+func isPublicHex(i *extractor.Inventory) bool {
+	m, ok := i.Metadata.(*Metadata)
+
+	return !ok || m.HexRepository == publicHexRepository
+}
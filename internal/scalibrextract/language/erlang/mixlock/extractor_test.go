@@ -0,0 +1,129 @@
+package mixlock_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/erlang/mixlock"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "not a map",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/list.lock",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "the lockfile is not a map"},
+		},
+		{
+			Name: "unterminated map",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/unterminated.lock",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "line 3: expected \"}\" but reached the end of the file"},
+		},
+		{
+			Name: "hex and git dependencies",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/mix.lock",
+			},
+			WantInventory: []*extractor.Inventory{
+				hexPackage("acme_auth", "0.4.2", "hexpm:acme"),
+				hexPackage("castore", "1.0.5", "hexpm"),
+				hexPackage("ecto_sql", "3.11.1", "hexpm"),
+				gitPackage("heroicons", "https://github.com/tailwindlabs/heroicons.git", "88ab3a0d790e6a47404cba02800a6b25d2afae50"),
+				// the name of the package on Hex is used, rather than the name of the dependency
+				hexPackage("jason", "1.4.1", "hexpm"),
+				hexPackage("phoenix", "1.7.10", "hexpm"),
+				hexPackage("plug", "1.14.0", "hexpm"),
+				// older versions of mix did not record the repository
+				hexPackage("poison", "3.1.0", "hexpm"),
+				gitPackage("tailwind", "https://github.com/phoenixframework/tailwind.git", "8b3f4e1a2c3d5b7f9e1a3c5d7b9f1e3a5c7d9b1f"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := mixlock.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got, cmpopts.SortSlices(extracttest.InventoryCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
+
+func TestExtractor_Ecosystem(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		inv  *extractor.Inventory
+		want string
+	}{
+		{
+			name: "public hex package",
+			inv:  hexPackage("plug", "1.14.0", "hexpm"),
+			want: "Hex",
+		},
+		{
+			name: "private hex package",
+			inv:  hexPackage("acme_auth", "0.4.2", "hexpm:acme"),
+			want: "",
+		},
+		{
+			name: "git package",
+			inv:  gitPackage("tailwind", "https://github.com/phoenixframework/tailwind.git", "8b3f4e1"),
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := (mixlock.Extractor{}).Ecosystem(tt.inv); got != tt.want {
+				t.Errorf("Ecosystem() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func hexPackage(name, version, repository string) *extractor.Inventory {
+	return &extractor.Inventory{
+		Name:      name,
+		Version:   version,
+		Locations: []string{"testdata/mix.lock"},
+		Metadata:  &mixlock.Metadata{HexRepository: repository},
+	}
+}
+
+func gitPackage(name, repo, commit string) *extractor.Inventory {
+	return &extractor.Inventory{
+		Name: name,
+		SourceCode: &extractor.SourceCodeIdentifier{
+			Repo:   repo,
+			Commit: commit,
+		},
+		Locations: []string{"testdata/mix.lock"},
+		Metadata:  &mixlock.Metadata{},
+	}
+}
@@ -0,0 +1,334 @@
+package mixlock
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The Elixir terms that can be in a mix.lock file are represented as:
+//   - maps (%{...}) and keyword lists ([key: value]) as []termPair
+//   - tuples ({...}) as termTuple
+//   - lists ([...]) as termList
+//   - atoms (:name, :"name", true, false, and nil) as termAtom
+//   - strings ("...") as string
+//   - numbers as termNumber
+type (
+	termAtom   string
+	termNumber string
+	termTuple  []any
+	termList   []any
+	termPair   struct {
+		Key   any
+		Value any
+	}
+)
+
+// termParser parses the subset of Elixir terms that can be written with literals,
+// which is what mix writes to mix.lock files with Kernel.inspect/2
+type termParser struct {
+	input string
+	pos   int
+}
+
+// parseTerm parses the single Elixir term in the input
+func parseTerm(input string) (any, error) {
+	p := &termParser{input: input}
+
+	term, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.skipSpace(); p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q after the end of the term", p.peekRune())
+	}
+
+	return term, nil
+}
+
+func (p *termParser) errorf(format string, args ...any) error {
+	line := strings.Count(p.input[:p.pos], "\n") + 1
+
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func (p *termParser) peekRune() rune {
+	r, _ := utf8.DecodeRuneInString(p.input[p.pos:])
+
+	return r
+}
+
+// skipSpace skips whitespace and comments
+func (p *termParser) skipSpace() {
+	for p.pos < len(p.input) {
+		switch r := p.peekRune(); {
+		case r == '#':
+			for p.pos < len(p.input) && p.input[p.pos] != '\n' {
+				p.pos++
+			}
+		case unicode.IsSpace(r):
+			p.pos += utf8.RuneLen(r)
+		default:
+			return
+		}
+	}
+}
+
+// consume skips the given token if it is next, returning whether it was
+func (p *termParser) consume(token string) bool {
+	p.skipSpace()
+
+	if strings.HasPrefix(p.input[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+
+	return false
+}
+
+func (p *termParser) expect(token string) error {
+	if !p.consume(token) {
+		if p.pos >= len(p.input) {
+			return p.errorf("expected %q but reached the end of the file", token)
+		}
+
+		return p.errorf("expected %q but found %q", token, p.peekRune())
+	}
+
+	return nil
+}
+
+func (p *termParser) parseValue() (any, error) {
+	p.skipSpace()
+
+	if p.pos >= len(p.input) {
+		return nil, p.errorf("expected a term but reached the end of the file")
+	}
+
+	switch r := p.peekRune(); {
+	case strings.HasPrefix(p.input[p.pos:], "%{"):
+		p.pos += 2
+		return p.parseEntries("}", true)
+	case r == '{':
+		p.pos++
+		values, err := p.parseSequence("}")
+		return termTuple(values), err
+	case r == '[':
+		p.pos++
+		return p.parseList()
+	case r == '"':
+		return p.parseString()
+	case r == ':':
+		p.pos++
+		return p.parseAtom()
+	case r == '-' || unicode.IsDigit(r):
+		return p.parseNumber(), nil
+	case isIdentifierStart(r):
+		name := p.parseIdentifier()
+		switch name {
+		case "true", "false", "nil":
+			return termAtom(name), nil
+		}
+
+		return nil, p.errorf("unexpected identifier %q", name)
+	default:
+		return nil, p.errorf("unexpected %q", r)
+	}
+}
+
+// parseSequence parses comma separated values up to the closing token,
+// which may be preceded by a trailing comma
+func (p *termParser) parseSequence(closing string) ([]any, error) {
+	var values []any
+
+	for !p.consume(closing) {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		if !p.consume(",") {
+			if err := p.expect(closing); err != nil {
+				return nil, err
+			}
+
+			break
+		}
+	}
+
+	return values, nil
+}
+
+// parseList parses a list, which is a keyword list if it starts with a keyword
+func (p *termParser) parseList() (any, error) {
+	start := p.pos
+	if _, ok := p.parseKeyword(); ok {
+		p.pos = start
+		return p.parseEntries("]", false)
+	}
+
+	p.pos = start
+	values, err := p.parseSequence("]")
+
+	return termList(values), err
+}
+
+// parseKeyword parses the key of a keyword pair (`key:` or `"key":`), returning false
+// if the next token is not one
+func (p *termParser) parseKeyword() (termAtom, bool) {
+	p.skipSpace()
+	start := p.pos
+
+	var key string
+	if p.pos < len(p.input) && p.input[p.pos] == '"' {
+		str, err := p.parseString()
+		if err != nil {
+			p.pos = start
+			return "", false
+		}
+		key = str
+	} else if p.pos < len(p.input) && isIdentifierStart(p.peekRune()) {
+		key = p.parseIdentifier()
+	} else {
+		return "", false
+	}
+
+	// the colon of a keyword must be followed by whitespace, otherwise it is an atom
+	if strings.HasPrefix(p.input[p.pos:], ":") && p.pos+1 < len(p.input) && unicode.IsSpace(rune(p.input[p.pos+1])) {
+		p.pos++
+		return termAtom(key), true
+	}
+
+	p.pos = start
+
+	return "", false
+}
+
+// parseEntries parses the pairs of a map or a keyword list up to the closing token,
+// where only maps can have pairs that are separated with "=>"
+func (p *termParser) parseEntries(closing string, isMap bool) ([]termPair, error) {
+	pairs := []termPair{}
+
+	for !p.consume(closing) {
+		var pair termPair
+
+		if key, ok := p.parseKeyword(); ok {
+			pair.Key = key
+		} else {
+			if !isMap {
+				return nil, p.errorf("expected a keyword in keyword list")
+			}
+
+			key, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("=>"); err != nil {
+				return nil, err
+			}
+			pair.Key = key
+		}
+
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		pair.Value = value
+		pairs = append(pairs, pair)
+
+		if !p.consume(",") {
+			if err := p.expect(closing); err != nil {
+				return nil, err
+			}
+
+			break
+		}
+	}
+
+	return pairs, nil
+}
+
+func (p *termParser) parseString() (string, error) {
+	start := p.pos
+
+	// find the closing quote, skipping over escaped characters
+	end := p.pos + 1
+	for ; end < len(p.input); end++ {
+		if p.input[end] == '\\' {
+			end++
+			continue
+		}
+		if p.input[end] == '"' {
+			break
+		}
+	}
+
+	if end >= len(p.input) {
+		return "", p.errorf("unterminated string")
+	}
+
+	p.pos = end + 1
+
+	if str, err := strconv.Unquote(p.input[start:p.pos]); err == nil {
+		return str, nil
+	}
+
+	// Elixir has escape sequences that Go does not (e.g. "\#{"), so rather
+	// than failing the string is used as is, as mix.lock files do not need them
+	return p.input[start+1 : end], nil
+}
+
+func (p *termParser) parseAtom() (termAtom, error) {
+	if p.pos < len(p.input) && p.input[p.pos] == '"' {
+		str, err := p.parseString()
+		return termAtom(str), err
+	}
+
+	if p.pos >= len(p.input) || !isIdentifierStart(p.peekRune()) {
+		return "", p.errorf("expected an atom")
+	}
+
+	return termAtom(p.parseIdentifier()), nil
+}
+
+func (p *termParser) parseIdentifier() string {
+	start := p.pos
+	for p.pos < len(p.input) {
+		r := p.peekRune()
+		if !isIdentifierStart(r) && !unicode.IsDigit(r) && r != '@' {
+			break
+		}
+		p.pos += utf8.RuneLen(r)
+	}
+
+	// identifiers can end with a question or exclamation mark
+	if p.pos < len(p.input) && (p.input[p.pos] == '?' || p.input[p.pos] == '!') {
+		p.pos++
+	}
+
+	return p.input[start:p.pos]
+}
+
+// parseNumber parses an integer or float, which are kept as they are written
+func (p *termParser) parseNumber() termNumber {
+	start := p.pos
+	p.pos++
+
+	for p.pos < len(p.input) {
+		r := p.peekRune()
+		if r != '.' && r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			break
+		}
+		p.pos++
+	}
+
+	return termNumber(p.input[start:p.pos])
+}
+
+func isIdentifierStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
@@ -0,0 +1,151 @@
+// Package gomod extracts go.mod files, applying their replace and exclude directives.
+package gomod
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/golang/gomod"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// Name is the unique name of this extractor.
+const Name = gomod.Name
+
+// Extractor extracts Go packages from go.mod files, including the stdlib version
+// by using the top level go version.
+//
+// Replace directives are applied so that the version of each module is the one that
+// Go would actually build, and modules that are replaced with a local directory are
+// kept under their original name without a version, so they are not scanned.
+// Module versions that are excluded are never built, so they are skipped.
+type Extractor struct {
+	actualExtractor gomod.Extractor
+}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for go.mod files
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	return e.actualExtractor.FileRequired(fapi)
+}
+
+// Extract extracts packages from go.mod files passed through the scan input.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	b, err := io.ReadAll(input.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", input.Path, err)
+	}
+	mod, err := modfile.Parse(input.Path, b, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	packages := make([]*extractor.Inventory, 0, len(mod.Require)+1)
+	for _, require := range mod.Require {
+		if isExcluded(mod.Exclude, require.Mod) {
+			continue
+		}
+
+		built := replacedVersion(mod.Replace, require.Mod)
+		packages = append(packages, &extractor.Inventory{
+			Name:      built.Path,
+			Version:   strings.TrimPrefix(built.Version, "v"),
+			Locations: []string{input.Path},
+		})
+	}
+
+	// Add the Go stdlib as an explicit dependency.
+	if mod.Go != nil && mod.Go.Version != "" {
+		packages = append(packages, &extractor.Inventory{
+			Name:      "stdlib",
+			Version:   mod.Go.Version,
+			Locations: []string{input.Path},
+		})
+	}
+
+	return dedupe(packages), nil
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(i *extractor.Inventory) *purl.PackageURL {
+	return e.actualExtractor.ToPURL(i)
+}
+
+// Ecosystem returns the OSV ecosystem ('Go') of the software extracted by this extractor.
+func (e Extractor) Ecosystem(i *extractor.Inventory) string {
+	return e.actualExtractor.Ecosystem(i)
+}
+
+// replacedVersion gets the module version that will be built in place of the required one,
+// preferring a replace directive that is specifically for the version over one for all versions.
+//
+// Modules replaced by a local directory are returned with their original path and no version,
+// as the contents of the directory cannot be matched against any published version.
+func replacedVersion(replaces []*modfile.Replace, mod module.Version) module.Version {
+	var found *modfile.Replace
+	for _, r := range replaces {
+		if r.Old.Path != mod.Path {
+			continue
+		}
+		if r.Old.Version == mod.Version {
+			found = r
+			break
+		}
+		if r.Old.Version == "" {
+			found = r
+		}
+	}
+
+	switch {
+	case found == nil:
+		return mod
+	case found.New.Version == "":
+		return module.Version{Path: mod.Path}
+	default:
+		return found.New
+	}
+}
+
+// isExcluded returns true if the module version has been excluded by an exclude directive
+func isExcluded(excludes []*modfile.Exclude, mod module.Version) bool {
+	return slices.ContainsFunc(excludes, func(e *modfile.Exclude) bool {
+		return e.Mod == mod
+	})
+}
+
+// dedupe removes packages that have the same name and version, which can be
+// the result of multiple modules being replaced with the same module version.
+func dedupe(packages []*extractor.Inventory) []*extractor.Inventory {
+	type pkgKey struct{ name, version string }
+	seen := make(map[pkgKey]bool)
+
+	return slices.DeleteFunc(packages, func(pkg *extractor.Inventory) bool {
+		key := pkgKey{pkg.Name, pkg.Version}
+		if seen[key] {
+			return true
+		}
+		seen[key] = true
+
+		return false
+	})
+}
@@ -0,0 +1,99 @@
+package gomod_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/golang/gomod"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "invalid go.mod",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/not-go-mod.txt",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "could not extract from"},
+		},
+		{
+			Name: "version replacements",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/replace-version.mod",
+			},
+			WantInventory: []*extractor.Inventory{
+				goPackage("example.com/fork/toml", "1.2.0", "testdata/replace-version.mod"),
+				goPackage("golang.org/x/net", "0.23.0", "testdata/replace-version.mod"),
+				goPackage("golang.org/x/text", "0.14.0", "testdata/replace-version.mod"),
+				goPackage("gopkg.in/yaml.v3", "3.0.0", "testdata/replace-version.mod"),
+				goPackage("stdlib", "1.21.4", "testdata/replace-version.mod"),
+			},
+		},
+		{
+			Name: "path replacements",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/replace-path.mod",
+			},
+			WantInventory: []*extractor.Inventory{
+				goPackage("example.com/app/api", "", "testdata/replace-path.mod"),
+				goPackage("github.com/google/uuid", "", "testdata/replace-path.mod"),
+				goPackage("golang.org/x/net", "0.17.0", "testdata/replace-path.mod"),
+				goPackage("stdlib", "1.22", "testdata/replace-path.mod"),
+			},
+		},
+		{
+			Name: "replacements resulting in duplicates",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/replace-duplicate.mod",
+			},
+			WantInventory: []*extractor.Inventory{
+				goPackage("example.com/fork/net", "0.23.0", "testdata/replace-duplicate.mod"),
+			},
+		},
+		{
+			Name: "excluded versions",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/exclude.mod",
+			},
+			WantInventory: []*extractor.Inventory{
+				goPackage("github.com/pkg/errors", "0.9.1", "testdata/exclude.mod"),
+				goPackage("stdlib", "1.21", "testdata/exclude.mod"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := gomod.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got, cmpopts.SortSlices(extracttest.InventoryCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
+
+func goPackage(name, version, location string) *extractor.Inventory {
+	return &extractor.Inventory{
+		Name:      name,
+		Version:   version,
+		Locations: []string{location},
+	}
+}
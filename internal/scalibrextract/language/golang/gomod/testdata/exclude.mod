@@ -0,0 +1,13 @@
+module example.com/app
+
+go 1.21
+
+require (
+	github.com/pkg/errors v0.9.1
+	golang.org/x/crypto v0.14.0
+)
+
+exclude (
+	github.com/pkg/errors v0.8.0
+	golang.org/x/crypto v0.14.0
+)
@@ -0,0 +1,8 @@
+module example.com/app
+
+require (
+	golang.org/x/net v0.17.0
+	example.com/fork/net v0.23.0
+)
+
+replace golang.org/x/net => example.com/fork/net v0.23.0
@@ -0,0 +1,21 @@
+module example.com/app
+
+go 1.21.4
+
+require (
+	github.com/BurntSushi/toml v1.0.0
+	golang.org/x/net v0.17.0
+	golang.org/x/text v0.13.0
+	gopkg.in/yaml.v3 v3.0.0
+)
+
+replace (
+	// replaces every version of the module
+	golang.org/x/net => golang.org/x/net v0.23.0
+	// replaces only the specific version of the module
+	golang.org/x/text v0.13.0 => golang.org/x/text v0.14.0
+	// has no effect as the version is not the one that is required
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c => gopkg.in/yaml.v3 v3.0.1
+	// replaces the module with a fork
+	github.com/BurntSushi/toml v1.0.0 => example.com/fork/toml v1.2.0
+)
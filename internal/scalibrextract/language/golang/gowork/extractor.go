@@ -0,0 +1,187 @@
+// Package gowork extracts Go workspace (go.work) files, merging the dependencies
+// of every module that is used by the workspace.
+package gowork
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/golang/gomod"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// Name is the unique name of this extractor.
+const Name = "go/gowork"
+
+// Extractor extracts Go packages from go.work files.
+//
+// The go.mod of each module in a `use` directive is read, and their requirements are
+// merged by selecting the highest required version of each module (as Go does with
+// minimal version selection). Replace directives in the go.work take precedence over
+// those in the go.mod files of the modules.
+type Extractor struct {
+	actualExtractor gomod.Extractor
+}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for go.work files
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	return filepath.Base(fapi.Path()) == "go.work"
+}
+
+// Extract extracts packages from go.work files passed through the scan input.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	b, err := io.ReadAll(input.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", input.Path, err)
+	}
+	work, err := modfile.ParseWork(input.Path, b, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	dir := path.Dir(filepath.ToSlash(input.Path))
+	workspaceModules := make(map[string]bool)
+	selected := make(map[string]string)
+	var moduleReplaces []*modfile.Replace
+	for _, use := range work.Use {
+		modPath := path.Join(dir, filepath.ToSlash(use.Path), "go.mod")
+		mod, err := parseGoMod(input.FS, modPath)
+		if err != nil {
+			return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+		}
+
+		if mod.Module != nil {
+			workspaceModules[mod.Module.Mod.Path] = true
+		}
+		for _, require := range mod.Require {
+			if v, ok := selected[require.Mod.Path]; !ok || semver.Compare(require.Mod.Version, v) > 0 {
+				selected[require.Mod.Path] = require.Mod.Version
+			}
+		}
+		moduleReplaces = append(moduleReplaces, mod.Replace...)
+	}
+
+	packages := make([]*extractor.Inventory, 0, len(selected)+1)
+	for _, modulePath := range slices.Sorted(maps.Keys(selected)) {
+		// modules of the workspace are always used from the workspace itself
+		if workspaceModules[modulePath] {
+			continue
+		}
+
+		mod := module.Version{Path: modulePath, Version: selected[modulePath]}
+		r := findReplace(work.Replace, mod)
+		if r == nil {
+			r = findReplace(moduleReplaces, mod)
+		}
+		switch {
+		case r == nil:
+		case r.New.Version == "":
+			// modules replaced by a local directory cannot be matched against any published version
+			mod.Version = ""
+		default:
+			mod = r.New
+		}
+
+		packages = append(packages, &extractor.Inventory{
+			Name:      mod.Path,
+			Version:   strings.TrimPrefix(mod.Version, "v"),
+			Locations: []string{input.Path},
+		})
+	}
+
+	// Add the Go stdlib as an explicit dependency.
+	if work.Go != nil && work.Go.Version != "" {
+		packages = append(packages, &extractor.Inventory{
+			Name:      "stdlib",
+			Version:   work.Go.Version,
+			Locations: []string{input.Path},
+		})
+	}
+
+	return dedupe(packages), nil
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(i *extractor.Inventory) *purl.PackageURL {
+	return e.actualExtractor.ToPURL(i)
+}
+
+// Ecosystem returns the OSV ecosystem ('Go') of the software extracted by this extractor.
+func (e Extractor) Ecosystem(i *extractor.Inventory) string {
+	return e.actualExtractor.Ecosystem(i)
+}
+
+func parseGoMod(fsys fs.FS, p string) (*modfile.File, error) {
+	if fsys == nil {
+		return nil, fmt.Errorf("no filesystem available to open %s", p)
+	}
+
+	b, err := fs.ReadFile(fsys, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return modfile.Parse(p, b, nil)
+}
+
+// findReplace gets the replace directive that applies to the given module version,
+// preferring one that is specifically for the version over one for all versions.
+func findReplace(replaces []*modfile.Replace, mod module.Version) *modfile.Replace {
+	var found *modfile.Replace
+	for _, r := range replaces {
+		if r.Old.Path != mod.Path {
+			continue
+		}
+		if r.Old.Version == mod.Version {
+			return r
+		}
+		if r.Old.Version == "" {
+			found = r
+		}
+	}
+
+	return found
+}
+
+// dedupe removes packages that have the same name and version, which can be
+// the result of multiple modules being replaced with the same module version.
+func dedupe(packages []*extractor.Inventory) []*extractor.Inventory {
+	type pkgKey struct{ name, version string }
+	seen := make(map[pkgKey]bool)
+
+	return slices.DeleteFunc(packages, func(pkg *extractor.Inventory) bool {
+		key := pkgKey{pkg.Name, pkg.Version}
+		if seen[key] {
+			return true
+		}
+		seen[key] = true
+
+		return false
+	})
+}
@@ -0,0 +1,77 @@
+package gowork_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/golang/gowork"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "invalid go.work",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/not-go-work.txt",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "could not extract from"},
+		},
+		{
+			Name: "missing module",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/missing-module/go.work",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "could not extract from"},
+		},
+		{
+			Name: "workspace",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/workspace/go.work",
+			},
+			WantInventory: []*extractor.Inventory{
+				goPackage("github.com/google/uuid", "1.6.0"),
+				goPackage("github.com/pkg/errors", "0.9.1"),
+				goPackage("github.com/spf13/cobra", ""),
+				goPackage("golang.org/x/net", "0.23.0"),
+				goPackage("golang.org/x/text", "0.14.0"),
+				goPackage("golang.org/x/tools", "0.21.0"),
+				goPackage("stdlib", "1.22.5"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := gowork.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got, cmpopts.SortSlices(extracttest.InventoryCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
+
+func goPackage(name, version string) *extractor.Inventory {
+	return &extractor.Inventory{
+		Name:      name,
+		Version:   version,
+		Locations: []string{"testdata/workspace/go.work"},
+	}
+}
@@ -0,0 +1,11 @@
+module example.com/app/api
+
+go 1.22
+
+require (
+	golang.org/x/net v0.23.0
+	golang.org/x/text v0.13.0
+	github.com/google/uuid v1.3.0
+)
+
+replace github.com/google/uuid => github.com/google/uuid v1.6.0
@@ -0,0 +1,11 @@
+module example.com/app
+
+go 1.22
+
+require (
+	example.com/app/api v0.0.0
+	github.com/pkg/errors v0.8.0
+	golang.org/x/net v0.17.0
+)
+
+replace example.com/app/api => ./api
@@ -0,0 +1,11 @@
+module example.com/app/tools
+
+go 1.22
+
+require (
+	github.com/spf13/cobra v1.8.0
+	golang.org/x/tools v0.21.0
+	golang.org/x/net v0.20.0
+)
+
+replace github.com/spf13/cobra => ../../forks/cobra
@@ -0,0 +1,279 @@
+// Package effectivepom extracts the effective POMs output by the Maven `help:effective-pom` goal.
+package effectivepom
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/javalockfile"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/pomxml"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+)
+
+// Name is the unique name of this extractor.
+const Name = "java/effectivepom"
+
+// the maximum number of times placeholders will be interpolated, in case properties refer to each other
+const maxInterpolationDepth = 10
+
+var placeholderRegexp = cachedregexp.MustCompile(`\$\{([^}]+)\}`)
+
+type pomDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Scope      string `xml:"scope"`
+	Type       string `xml:"type"`
+	Classifier string `xml:"classifier"`
+}
+
+type pomProperties map[string]string
+
+func (p *pomProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*p = pomProperties{}
+
+	for {
+		t, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch tt := t.(type) {
+		case xml.StartElement:
+			var s string
+			if err := d.DecodeElement(&s, &tt); err != nil {
+				return err
+			}
+			(*p)[tt.Name.Local] = strings.TrimSpace(s)
+		case xml.EndElement:
+			if tt.Name == start.Name {
+				return nil
+			}
+		}
+	}
+}
+
+type pomProject struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Parent     struct {
+		GroupID string `xml:"groupId"`
+		Version string `xml:"version"`
+	} `xml:"parent"`
+	Properties   pomProperties   `xml:"properties"`
+	Dependencies []pomDependency `xml:"dependencies>dependency"`
+}
+
+// property gets the value of a property that can be referenced by placeholders in the project
+func (p pomProject) property(name string) (string, bool) {
+	switch name {
+	case "project.groupId", "pom.groupId":
+		return p.GroupID, p.GroupID != ""
+	case "project.artifactId", "pom.artifactId":
+		return p.ArtifactID, p.ArtifactID != ""
+	case "project.version", "pom.version", "version":
+		return p.Version, p.Version != ""
+	case "project.parent.groupId":
+		return p.Parent.GroupID, p.Parent.GroupID != ""
+	case "project.parent.version":
+		return p.Parent.Version, p.Parent.Version != ""
+	}
+
+	v, ok := p.Properties[name]
+
+	return v, ok
+}
+
+// interpolate replaces all the ${property} placeholders in s,
+// returning false if any of them could not be resolved.
+func (p pomProject) interpolate(s string) (string, bool) {
+	for range maxInterpolationDepth {
+		if !placeholderRegexp.MatchString(s) {
+			return s, true
+		}
+
+		resolved := true
+		s = placeholderRegexp.ReplaceAllStringFunc(s, func(placeholder string) string {
+			v, ok := p.property(placeholderRegexp.FindStringSubmatch(placeholder)[1])
+			if !ok {
+				resolved = false
+				return placeholder
+			}
+
+			return v
+		})
+		if !resolved {
+			return s, false
+		}
+	}
+
+	return s, !placeholderRegexp.MatchString(s)
+}
+
+// Extractor extracts Maven packages from effective POMs, such as those written by
+// `mvn help:effective-pom -Doutput=effective-pom.xml`.
+//
+// As inheritance and dependency management have been applied to an effective POM,
+// the versions of its dependencies are those that are actually used by the build.
+// Effective POMs of multi-module projects, which contain a <project> for each module,
+// are also supported.
+type Extractor struct {
+	actualExtractor pomxml.Extractor
+}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for effective-pom.xml files
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	return filepath.Base(fapi.Path()) == "effective-pom.xml"
+}
+
+// Extract extracts packages from effective POMs passed through the scan input.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	projects, err := decodeProjects(input.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	packages := make(map[string]*extractor.Inventory)
+	var order []string
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			pkg := toInventory(project, dep)
+			if pkg == nil {
+				continue
+			}
+			pkg.Locations = []string{input.Path}
+
+			key := pkg.Name + "@" + pkg.Version
+			if existing, ok := packages[key]; ok {
+				// a dependency that is in the default scope of any module is not in a group
+				if len(pkg.Metadata.(*javalockfile.Metadata).DepGroupVals) == 0 {
+					existing.Metadata.(*javalockfile.Metadata).DepGroupVals = []string{}
+				}
+
+				continue
+			}
+			packages[key] = pkg
+			order = append(order, key)
+		}
+	}
+
+	result := make([]*extractor.Inventory, 0, len(order))
+	for _, key := range order {
+		result = append(result, packages[key])
+	}
+
+	return result, nil
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(i *extractor.Inventory) *purl.PackageURL {
+	return e.actualExtractor.ToPURL(i)
+}
+
+// Ecosystem returns the OSV ecosystem ('Maven') of the software extracted by this extractor.
+func (e Extractor) Ecosystem(i *extractor.Inventory) string {
+	return e.actualExtractor.Ecosystem(i)
+}
+
+// decodeProjects decodes every <project> in an effective POM, which is either
+// the root element or within a <projects> element for multi-module projects
+func decodeProjects(r io.Reader) ([]pomProject, error) {
+	var projects []pomProject
+
+	d := xml.NewDecoder(r)
+	for {
+		t, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := t.(xml.StartElement)
+		if !ok || start.Name.Local != "project" {
+			continue
+		}
+
+		var project pomProject
+		if err := d.DecodeElement(&project, &start); err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+
+	if len(projects) == 0 {
+		return nil, errors.New("no <project> element found")
+	}
+
+	return projects, nil
+}
+
+func toInventory(project pomProject, dep pomDependency) *extractor.Inventory {
+	name := dep.GroupID + ":" + dep.ArtifactID
+
+	version, ok := project.interpolate(strings.TrimSpace(dep.Version))
+	if !ok {
+		slog.Warn(fmt.Sprintf("Failed to resolve version %q of %s in %s", dep.Version, name, project.GroupID+":"+project.ArtifactID))
+		return nil
+	}
+	if version = lowestVersion(version); version == "" {
+		return nil
+	}
+
+	metadata := &javalockfile.Metadata{
+		ArtifactID:   dep.ArtifactID,
+		GroupID:      dep.GroupID,
+		Type:         dep.Type,
+		Classifier:   dep.Classifier,
+		DepGroupVals: []string{},
+	}
+	// Only append non-default scope (compile is the default scope).
+	if scope := strings.TrimSpace(dep.Scope); scope != "" && scope != "compile" {
+		metadata.DepGroupVals = []string{scope}
+	}
+
+	return &extractor.Inventory{
+		Name:     name,
+		Version:  version,
+		Metadata: metadata,
+	}
+}
+
+// lowestVersion gets the lower bound of a version range (e.g. "[1.2,2.0)"), returning
+// the version as-is if it's not a range, or an empty string if the range has no lower bound.
+//
+// See https://maven.apache.org/pom.html#dependency-version-requirement-specification
+func lowestVersion(version string) string {
+	if !strings.HasPrefix(version, "[") && !strings.HasPrefix(version, "(") {
+		return version
+	}
+
+	lower, _, _ := strings.Cut(version[1:], ",")
+
+	return strings.TrimSpace(strings.TrimRight(lower, ")]"))
+}
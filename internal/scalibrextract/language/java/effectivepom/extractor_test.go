@@ -0,0 +1,88 @@
+package effectivepom_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/javalockfile"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/java/effectivepom"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "invalid xml",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/not-xml.txt",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "could not extract from"},
+		},
+		{
+			Name: "properties and ranges",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/effective-pom.xml",
+			},
+			WantInventory: []*extractor.Inventory{
+				mavenPackage("testdata/effective-pom.xml", "com.fasterxml.jackson.core", "jackson-databind", "2.15.2"),
+				mavenPackage("testdata/effective-pom.xml", "com.example", "shared", "2.0.0"),
+				mavenPackage("testdata/effective-pom.xml", "org.apache.commons", "commons-lang3", "3.12.0"),
+				mavenPackage("testdata/effective-pom.xml", "org.junit.jupiter", "junit-jupiter", "5.10.0", "test"),
+			},
+		},
+		{
+			Name: "multiple modules",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/multi-module.xml",
+			},
+			WantInventory: []*extractor.Inventory{
+				mavenPackage("testdata/multi-module.xml", "junit", "junit", "4.13.2", "test"),
+				mavenPackage("testdata/multi-module.xml", "com.google.guava", "guava", "32.1.3-jre"),
+				mavenPackage("testdata/multi-module.xml", "com.example", "core", "1.0.0"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := effectivepom.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got, cmpopts.SortSlices(extracttest.InventoryCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
+
+func mavenPackage(location, groupID, artifactID, version string, groups ...string) *extractor.Inventory {
+	if groups == nil {
+		groups = []string{}
+	}
+
+	return &extractor.Inventory{
+		Name:      groupID + ":" + artifactID,
+		Version:   version,
+		Locations: []string{location},
+		Metadata: &javalockfile.Metadata{
+			ArtifactID:   artifactID,
+			GroupID:      groupID,
+			DepGroupVals: groups,
+		},
+	}
+}
@@ -0,0 +1,230 @@
+// Package gradleversioncatalog extracts Gradle version catalog (libs.versions.toml) files.
+package gradleversioncatalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/gradlelockfile"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/javalockfile"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+)
+
+// Name is the unique name of this extractor.
+const Name = "java/gradleversioncatalog"
+
+// catalogVersion is a version declaration, which can either be a plain version string,
+// a reference to an entry in the [versions] table, or a rich version.
+//
+// See https://docs.gradle.org/current/userguide/rich_versions.html
+type catalogVersion struct {
+	Ref      string
+	Strictly string
+	Require  string
+	Prefer   string
+}
+
+func (v *catalogVersion) UnmarshalTOML(data any) error {
+	switch data := data.(type) {
+	case string:
+		v.Require = data
+		return nil
+	case map[string]any:
+		return unmarshalStrings(data, map[string]*string{
+			"ref":      &v.Ref,
+			"strictly": &v.Strictly,
+			"require":  &v.Require,
+			"prefer":   &v.Prefer,
+		})
+	default:
+		return errors.New("invalid format for version catalog version")
+	}
+}
+
+// resolve gets the concrete version that is declared, dereferencing it against versions if needed,
+// returning an empty string if there is no such version (e.g. for dynamic versions like "1.+")
+func (v catalogVersion) resolve(versions map[string]catalogVersion) string {
+	if v.Ref != "" {
+		ref, ok := versions[v.Ref]
+		if !ok || ref.Ref != "" {
+			return ""
+		}
+
+		return ref.resolve(versions)
+	}
+
+	for _, version := range []string{v.Prefer, v.Strictly, v.Require} {
+		if version != "" && !strings.ContainsAny(version, "[]()+,") {
+			return version
+		}
+	}
+
+	return ""
+}
+
+type catalogLibrary struct {
+	Group   string
+	Name    string
+	Version catalogVersion
+}
+
+func (l *catalogLibrary) UnmarshalTOML(data any) error {
+	switch data := data.(type) {
+	case string:
+		// "group:name:version", where the version is optional
+		parts := strings.SplitN(data, ":", 3)
+		if len(parts) < 2 {
+			return fmt.Errorf("invalid library notation %q", data)
+		}
+		l.Group, l.Name = parts[0], parts[1]
+		if len(parts) == 3 {
+			l.Version.Require = parts[2]
+		}
+
+		return nil
+	case map[string]any:
+		var module string
+		if err := unmarshalStrings(data, map[string]*string{"module": &module, "group": &l.Group, "name": &l.Name}); err != nil {
+			return err
+		}
+		if module != "" {
+			var ok bool
+			if l.Group, l.Name, ok = strings.Cut(module, ":"); !ok {
+				return fmt.Errorf("invalid library module %q", module)
+			}
+		}
+		if version, ok := data["version"]; ok {
+			return l.Version.UnmarshalTOML(version)
+		}
+
+		return nil
+	default:
+		return errors.New("invalid format for version catalog library")
+	}
+}
+
+type catalogPlugin struct {
+	ID      string
+	Version catalogVersion
+}
+
+func (p *catalogPlugin) UnmarshalTOML(data any) error {
+	switch data := data.(type) {
+	case string:
+		// "id:version"
+		p.ID, p.Version.Require, _ = strings.Cut(data, ":")
+		return nil
+	case map[string]any:
+		if err := unmarshalStrings(data, map[string]*string{"id": &p.ID}); err != nil {
+			return err
+		}
+		if version, ok := data["version"]; ok {
+			return p.Version.UnmarshalTOML(version)
+		}
+
+		return nil
+	default:
+		return errors.New("invalid format for version catalog plugin")
+	}
+}
+
+func unmarshalStrings(data map[string]any, fields map[string]*string) error {
+	for key, dst := range fields {
+		v, ok := data[key]
+		if !ok {
+			continue
+		}
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("invalid type for key %q: expected string, got %T", key, v)
+		}
+		*dst = s
+	}
+
+	return nil
+}
+
+type versionCatalog struct {
+	Versions  map[string]catalogVersion `toml:"versions"`
+	Libraries map[string]catalogLibrary `toml:"libraries"`
+	Plugins   map[string]catalogPlugin  `toml:"plugins"`
+}
+
+// Extractor extracts Maven packages from Gradle version catalogs.
+//
+// Libraries without a version (e.g. those whose version is managed by a platform)
+// or with a dynamic version are skipped, and plugins are reported as their
+// plugin marker artifact (i.e. "<id>:<id>.gradle.plugin").
+type Extractor struct {
+	actualExtractor gradlelockfile.Extractor
+}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for Gradle version catalog files, such as gradle/libs.versions.toml
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	return strings.HasSuffix(filepath.Base(fapi.Path()), ".versions.toml")
+}
+
+// Extract extracts packages from version catalogs passed through the scan input.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	var catalog versionCatalog
+	if _, err := toml.NewDecoder(input.Reader).Decode(&catalog); err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	packages := make([]*extractor.Inventory, 0, len(catalog.Libraries)+len(catalog.Plugins))
+	add := func(groupID, artifactID string, version catalogVersion) {
+		v := version.resolve(catalog.Versions)
+		if groupID == "" || artifactID == "" || v == "" {
+			return
+		}
+
+		packages = append(packages, &extractor.Inventory{
+			Name:      groupID + ":" + artifactID,
+			Version:   v,
+			Locations: []string{input.Path},
+			Metadata: &javalockfile.Metadata{
+				ArtifactID: artifactID,
+				GroupID:    groupID,
+			},
+		})
+	}
+
+	for _, lib := range catalog.Libraries {
+		add(lib.Group, lib.Name, lib.Version)
+	}
+	for _, p := range catalog.Plugins {
+		add(p.ID, p.ID+".gradle.plugin", p.Version)
+	}
+
+	return packages, nil
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(i *extractor.Inventory) *purl.PackageURL {
+	return e.actualExtractor.ToPURL(i)
+}
+
+// Ecosystem returns the OSV ecosystem ('Maven') of the software extracted by this extractor.
+func (e Extractor) Ecosystem(i *extractor.Inventory) string {
+	return e.actualExtractor.Ecosystem(i)
+}
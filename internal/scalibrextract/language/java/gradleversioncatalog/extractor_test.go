@@ -0,0 +1,82 @@
+package gradleversioncatalog_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/javalockfile"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/java/gradleversioncatalog"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "invalid toml",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/not-toml.txt",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "could not extract from"},
+		},
+		{
+			Name: "no libraries",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/empty.versions.toml",
+			},
+			WantInventory: []*extractor.Inventory{},
+		},
+		{
+			Name: "libraries and plugins",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/libs.versions.toml",
+			},
+			WantInventory: []*extractor.Inventory{
+				mavenPackage("org.apache.commons", "commons-lang3", "3.14.0"),
+				mavenPackage("org.junit.jupiter", "junit-jupiter", "5.10.0"),
+				mavenPackage("org.junit", "junit-bom", "5.10.0"),
+				mavenPackage("com.google.guava", "guava", "32.1.3-jre"),
+				mavenPackage("com.fasterxml.jackson.core", "jackson-databind", "2.16.1"),
+				mavenPackage("org.jetbrains.kotlin.jvm", "org.jetbrains.kotlin.jvm.gradle.plugin", "1.9.22"),
+				mavenPackage("com.diffplug.spotless", "com.diffplug.spotless.gradle.plugin", "6.25.0"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := gradleversioncatalog.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got, cmpopts.SortSlices(extracttest.InventoryCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
+
+func mavenPackage(groupID, artifactID, version string) *extractor.Inventory {
+	return &extractor.Inventory{
+		Name:      groupID + ":" + artifactID,
+		Version:   version,
+		Locations: []string{"testdata/libs.versions.toml"},
+		Metadata: &javalockfile.Metadata{
+			ArtifactID: artifactID,
+			GroupID:    groupID,
+		},
+	}
+}
@@ -0,0 +1,174 @@
+// Package mvndependencytree extracts the output of the Maven `dependency:tree` goal.
+package mvndependencytree
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/javalockfile"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/pomxml"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+)
+
+// Name is the unique name of this extractor.
+const Name = "java/mvndependencytree"
+
+// Extractor extracts Maven packages from the text output of `mvn dependency:tree`,
+// such as that written by `mvn dependency:tree -DoutputFile=dependency-tree.txt`.
+//
+// As the tree is fully resolved by Maven, this includes transitive dependencies
+// with the versions and scopes that are actually used by the build.
+type Extractor struct {
+	actualExtractor pomxml.Extractor
+}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for dependency-tree.txt files
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	return filepath.Base(fapi.Path()) == "dependency-tree.txt"
+}
+
+// Extract extracts packages from dependency trees passed through the scan input.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	packages := make(map[string]*extractor.Inventory)
+	// the order the packages were first seen in, so that the output is deterministic
+	var order []string
+
+	scanner := bufio.NewScanner(input.Reader)
+	for scanner.Scan() {
+		pkg, depth := parseLine(scanner.Text())
+		// the roots of the tree are the project (or its modules) themselves
+		if pkg == nil || depth == 0 {
+			continue
+		}
+		pkg.Locations = []string{input.Path}
+
+		metadata := pkg.Metadata.(*javalockfile.Metadata)
+		metadata.IsTransitive = depth > 1
+
+		key := pkg.Name + "@" + pkg.Version
+		existing, ok := packages[key]
+		if !ok {
+			packages[key] = pkg
+			order = append(order, key)
+
+			continue
+		}
+
+		// the same package can be depended on by multiple modules with different scopes,
+		// in which case it's only in a group if it is in that group for every module
+		existingMetadata := existing.Metadata.(*javalockfile.Metadata)
+		existingMetadata.IsTransitive = existingMetadata.IsTransitive && metadata.IsTransitive
+		switch {
+		case len(existingMetadata.DepGroupVals) == 0:
+		case len(metadata.DepGroupVals) == 0:
+			existingMetadata.DepGroupVals = []string{}
+		default:
+			for _, group := range metadata.DepGroupVals {
+				if !slices.Contains(existingMetadata.DepGroupVals, group) {
+					existingMetadata.DepGroupVals = append(existingMetadata.DepGroupVals, group)
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	result := make([]*extractor.Inventory, 0, len(order))
+	for _, key := range order {
+		result = append(result, packages[key])
+	}
+
+	return result, nil
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(i *extractor.Inventory) *purl.PackageURL {
+	return e.actualExtractor.ToPURL(i)
+}
+
+// Ecosystem returns the OSV ecosystem ('Maven') of the software extracted by this extractor.
+func (e Extractor) Ecosystem(i *extractor.Inventory) string {
+	return e.actualExtractor.Ecosystem(i)
+}
+
+// parseLine parses a line of the dependency tree, returning the package on
+// that line along with its depth in the tree (where the root has a depth of 0).
+//
+// Lines that are not part of the tree (e.g. other Maven output), or which are for
+// dependencies that were omitted from the resolution, return a nil package.
+func parseLine(line string) (*extractor.Inventory, int) {
+	// the tree is prefixed with the log level when not written directly to a file
+	line = strings.TrimPrefix(line, "[INFO] ")
+
+	coordinates := strings.TrimLeft(line, " |+-\\")
+	prefix := line[:len(line)-len(coordinates)]
+	if prefix != "" && !strings.HasSuffix(prefix, "+- ") && !strings.HasSuffix(prefix, "\\- ") {
+		return nil, 0
+	}
+	depth := len(prefix) / 3
+
+	// the coordinates can be followed by notes such as "(optional)", and dependencies that were
+	// omitted in favor of another version are wrapped in parentheses when using -Dverbose
+	coordinates, _, _ = strings.Cut(coordinates, " ")
+	if coordinates == "" || strings.HasPrefix(coordinates, "(") {
+		return nil, 0
+	}
+
+	// <groupId>:<artifactId>:<type>[:<classifier>]:<version>[:<scope>], with the root not having a scope
+	parts := strings.Split(coordinates, ":")
+	if depth == 0 {
+		if len(parts) < 4 {
+			return nil, 0
+		}
+		parts = append(parts, "")
+	}
+
+	var groupID, artifactID, typ, classifier, version, scope string
+	switch len(parts) {
+	case 5:
+		groupID, artifactID, typ, version, scope = parts[0], parts[1], parts[2], parts[3], parts[4]
+	case 6:
+		groupID, artifactID, typ, classifier, version, scope = parts[0], parts[1], parts[2], parts[3], parts[4], parts[5]
+	default:
+		return nil, 0
+	}
+
+	metadata := &javalockfile.Metadata{
+		ArtifactID:   artifactID,
+		GroupID:      groupID,
+		Type:         typ,
+		Classifier:   classifier,
+		DepGroupVals: []string{},
+	}
+	// Only append non-default scope (compile is the default scope).
+	if scope != "" && scope != "compile" {
+		metadata.DepGroupVals = []string{scope}
+	}
+
+	return &extractor.Inventory{
+		Name:     groupID + ":" + artifactID,
+		Version:  version,
+		Metadata: metadata,
+	}, depth
+}
@@ -0,0 +1,102 @@
+package mvndependencytree_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/javalockfile"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/java/mvndependencytree"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "maven output",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/dependency-tree.txt",
+			},
+			WantInventory: []*extractor.Inventory{
+				mavenPackage("testdata/dependency-tree.txt", "org.springframework:spring-core:jar:5.3.20", false),
+				mavenPackage("testdata/dependency-tree.txt", "org.springframework:spring-jcl:jar:5.3.20", true),
+				mavenPackage("testdata/dependency-tree.txt", "com.google.guava:guava:jar:31.1-jre", false),
+				mavenPackage("testdata/dependency-tree.txt", "com.google.guava:failureaccess:jar:1.0.1", true),
+				{
+					Name:      "io.netty:netty-transport-native-epoll",
+					Version:   "4.1.94.Final",
+					Locations: []string{"testdata/dependency-tree.txt"},
+					Metadata: &javalockfile.Metadata{
+						ArtifactID:   "netty-transport-native-epoll",
+						GroupID:      "io.netty",
+						Type:         "jar",
+						Classifier:   "linux-x86_64",
+						DepGroupVals: []string{"runtime"},
+					},
+				},
+				mavenPackage("testdata/dependency-tree.txt", "javax.servlet:javax.servlet-api:jar:4.0.1", false, "provided"),
+				mavenPackage("testdata/dependency-tree.txt", "junit:junit:jar:4.13.2", false, "test"),
+				mavenPackage("testdata/dependency-tree.txt", "org.hamcrest:hamcrest-core:jar:1.3", false),
+				mavenPackage("testdata/dependency-tree.txt", "com.example:core:jar:1.0.0-SNAPSHOT", false),
+			},
+		},
+		{
+			Name: "output file",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/output-file.txt",
+			},
+			WantInventory: []*extractor.Inventory{
+				mavenPackage("testdata/output-file.txt", "org.apache.commons:commons-text:jar:1.10.0", false),
+				mavenPackage("testdata/output-file.txt", "org.apache.commons:commons-lang3:jar:3.12.0", true),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := mvndependencytree.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got, cmpopts.SortSlices(extracttest.InventoryCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
+
+// mavenPackage creates the expected inventory for a "<groupId>:<artifactId>:<type>:<version>" coordinate
+func mavenPackage(location, coordinate string, transitive bool, groups ...string) *extractor.Inventory {
+	parts := strings.Split(coordinate, ":")
+	groupID, artifactID, typ, version := parts[0], parts[1], parts[2], parts[3]
+	if groups == nil {
+		groups = []string{}
+	}
+
+	return &extractor.Inventory{
+		Name:      groupID + ":" + artifactID,
+		Version:   version,
+		Locations: []string{location},
+		Metadata: &javalockfile.Metadata{
+			ArtifactID:   artifactID,
+			GroupID:      groupID,
+			Type:         typ,
+			DepGroupVals: groups,
+			IsTransitive: transitive,
+		},
+	}
+}
@@ -0,0 +1,144 @@
+// Package bunlockb extracts bun.lockb binary lockfiles.
+package bunlockb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/javascript/yarnlock"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+)
+
+// Name is the unique name of this extractor.
+const Name = "javascript/bunlockb"
+
+// ErrBunNotFound is returned when the bun executable needed to decode bun.lockb files cannot be found.
+var ErrBunNotFound = errors.New("bun is required to extract bun.lockb files, but could not be found")
+
+// Extractor extracts npm packages from bun.lockb files.
+//
+// The binary lockfile format is not documented, so this uses bun itself to
+// print the lockfile in the yarn.lock v1 format, which is then extracted.
+type Extractor struct {
+	// BunPath is the path of the bun executable, defaulting to looking up "bun" in the PATH
+	BunPath string
+
+	actualExtractor yarnlock.Extractor
+}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for bun.lockb files
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	return filepath.Base(fapi.Path()) == "bun.lockb"
+}
+
+// Extract extracts packages from bun.lockb files passed through the scan input.
+func (e Extractor) Extract(ctx context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	yarnLock, err := e.printYarnLock(ctx, input.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	return e.parseYarnLock(ctx, input, yarnLock)
+}
+
+// printYarnLock runs bun to print the lockfile read from r in the yarn.lock v1 format.
+func (e Extractor) printYarnLock(ctx context.Context, r io.Reader) ([]byte, error) {
+	bunPath := e.BunPath
+	if bunPath == "" {
+		bunPath = "bun"
+	}
+	bunPath, err := exec.LookPath(bunPath)
+	if err != nil {
+		return nil, ErrBunNotFound
+	}
+
+	// bun needs the lockfile to be on disk with the .lockb extension,
+	// which is not guaranteed for the scan input (e.g. when reading from a virtual filesystem)
+	dir, err := os.MkdirTemp("", "osv-scanner-bunlockb-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	lockPath := filepath.Join(dir, "bun.lockb")
+	f, err := os.Create(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(f, r)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, bunPath, lockPath)
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run bun: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
+
+// parseYarnLock extracts the packages of the yarn.lock printed by bun,
+// skipping workspace packages as they are part of the project itself.
+func (e Extractor) parseYarnLock(ctx context.Context, input *filesystem.ScanInput, yarnLock []byte) ([]*extractor.Inventory, error) {
+	var filtered bytes.Buffer
+	skipping := false
+	scanner := bufio.NewScanner(bytes.NewReader(yarnLock))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "#") {
+			// the start of a new package
+			skipping = strings.Contains(line, "@workspace:")
+		}
+		if !skipping {
+			filtered.WriteString(line + "\n")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	yarnInput := *input
+	yarnInput.Reader = &filtered
+
+	return e.actualExtractor.Extract(ctx, &yarnInput)
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(i *extractor.Inventory) *purl.PackageURL {
+	return e.actualExtractor.ToPURL(i)
+}
+
+// Ecosystem returns the OSV ecosystem ('npm') of the software extracted by this extractor.
+func (e Extractor) Ecosystem(i *extractor.Inventory) string {
+	return e.actualExtractor.Ecosystem(i)
+}
@@ -0,0 +1,75 @@
+package bunlockb
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/testing/extracttest"
+)
+
+func TestExtractor_Extract_NoBun(t *testing.T) {
+	t.Parallel()
+
+	extr := Extractor{BunPath: "definitely-not-bun-osv-scanner"}
+
+	scanInput := extracttest.GenerateScanInputMock(t, extracttest.ScanInputMockConfig{
+		Path: "testdata/fake.lockb",
+	})
+	defer extracttest.CloseTestScanInput(t, scanInput)
+
+	_, err := extr.Extract(context.Background(), &scanInput)
+	if !errors.Is(err, ErrBunNotFound) {
+		t.Errorf("%s.Extract() error = %v, want %v", extr.Name(), err, ErrBunNotFound)
+	}
+}
+
+func TestExtractor_parseYarnLock(t *testing.T) {
+	t.Parallel()
+
+	const path = "testdata/bun-yarn-output.lock"
+	yarnLock, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read fixture: %v", err)
+	}
+
+	extr := Extractor{}
+	scanInput := extracttest.GenerateScanInputMock(t, extracttest.ScanInputMockConfig{Path: path})
+	defer extracttest.CloseTestScanInput(t, scanInput)
+
+	got, err := extr.parseYarnLock(context.Background(), &scanInput, yarnLock)
+	if err != nil {
+		t.Fatalf("parseYarnLock() error: %v", err)
+	}
+
+	want := []*extractor.Inventory{
+		{
+			Name:       "ansi-regex",
+			Version:    "5.0.1",
+			Locations:  []string{path},
+			SourceCode: &extractor.SourceCodeIdentifier{},
+		},
+		{
+			Name:      "is-number",
+			Version:   "7.0.0",
+			Locations: []string{path},
+			SourceCode: &extractor.SourceCodeIdentifier{
+				Commit: "98e8ff1da1a89f93d1397a24d7413ed15421c139",
+			},
+		},
+		{
+			Name:       "string-width",
+			Version:    "4.2.3",
+			Locations:  []string{path},
+			SourceCode: &extractor.SourceCodeIdentifier{},
+		},
+	}
+
+	if diff := cmp.Diff(want, got, cmpopts.SortSlices(extracttest.InventoryCmpLess)); diff != "" {
+		t.Errorf("parseYarnLock() diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,246 @@
+// Package pnpmlock extracts pnpm-lock.yaml files, adding support for the v9 lockfile format.
+package pnpmlock
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/javascript/pnpmlock"
+	"github.com/google/osv-scalibr/extractor/filesystem/osv"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+	"github.com/google/osv-scanner/v2/internal/utility/npmname"
+	"gopkg.in/yaml.v3"
+)
+
+// Name is the unique name of this extractor.
+const Name = pnpmlock.Name
+
+type pnpmLockResolution struct {
+	Tarball string `yaml:"tarball"`
+	Commit  string `yaml:"commit"`
+}
+
+type pnpmLockPackage struct {
+	Resolution pnpmLockResolution `yaml:"resolution"`
+	Name       string             `yaml:"name"`
+	Version    string             `yaml:"version"`
+}
+
+type pnpmLockImporterDependency struct {
+	Specifier string `yaml:"specifier"`
+	Version   string `yaml:"version"`
+}
+
+type pnpmLockImporter struct {
+	Dependencies         map[string]pnpmLockImporterDependency `yaml:"dependencies"`
+	OptionalDependencies map[string]pnpmLockImporterDependency `yaml:"optionalDependencies"`
+	DevDependencies      map[string]pnpmLockImporterDependency `yaml:"devDependencies"`
+}
+
+type pnpmLockSnapshot struct {
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+	Optional             bool              `yaml:"optional"`
+}
+
+type pnpmLockfileV9 struct {
+	Version   string                      `yaml:"lockfileVersion"`
+	Importers map[string]pnpmLockImporter `yaml:"importers"`
+	Packages  map[string]pnpmLockPackage  `yaml:"packages"`
+	Snapshots map[string]pnpmLockSnapshot `yaml:"snapshots"`
+}
+
+// Extractor extracts pnpm-lock.yaml files.
+//
+// Lockfiles older than v9 are extracted by the osv-scalibr pnpmlock extractor,
+// while v9 lockfiles are parsed using their importers and snapshots so that
+// transitive dependencies and their dependency groups are correctly reported.
+type Extractor struct {
+	actualExtractor pnpmlock.Extractor
+}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for pnpm-lock.yaml files outside of node_modules
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	return e.actualExtractor.FileRequired(fapi)
+}
+
+// Extract extracts packages from pnpm-lock.yaml files passed through the scan input.
+func (e Extractor) Extract(ctx context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	b, err := io.ReadAll(input.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	var lockfile pnpmLockfileV9
+	if err := yaml.Unmarshal(b, &lockfile); err != nil || !isV9(lockfile.Version) {
+		// Let the original extractor handle (and report errors for) everything that is not a v9 lockfile
+		actualInput := *input
+		actualInput.Reader = bytes.NewReader(b)
+
+		return e.actualExtractor.Extract(ctx, &actualInput)
+	}
+
+	return parsePnpmLockV9(lockfile, input.Path), nil
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(i *extractor.Inventory) *purl.PackageURL {
+	return e.actualExtractor.ToPURL(i)
+}
+
+// Ecosystem returns the OSV ecosystem ('npm') of the software extracted by this extractor.
+func (e Extractor) Ecosystem(i *extractor.Inventory) string {
+	return e.actualExtractor.Ecosystem(i)
+}
+
+func isV9(version string) bool {
+	v, err := strconv.ParseFloat(version, 64)
+	return err == nil && v >= 9.0
+}
+
+// snapshotID gets the id of the snapshot a dependency resolves to, given its name and resolved version,
+// returning "" if the dependency is not a snapshot (e.g. a linked workspace package).
+func snapshotID(name, version string) string {
+	if strings.HasPrefix(version, "link:") {
+		return ""
+	}
+
+	// aliased dependencies have their version as the id of the actual package (e.g. "string-width@4.2.3")
+	if aliased, _, ok := npmname.Split(peerlessID(version)); ok && !strings.Contains(aliased, ":") {
+		return version
+	}
+
+	return name + "@" + version
+}
+
+// peerlessID removes the peer dependency suffixes from a snapshot id,
+// e.g. "react-dom@18.2.0(react@18.2.0)" becomes "react-dom@18.2.0"
+func peerlessID(id string) string {
+	id, _, _ = strings.Cut(id, "(")
+	return id
+}
+
+var codeLoadURLRegexp = cachedregexp.MustCompile(`https://codeload\.github\.com(?:/[\w-.]+){2}/tar\.gz/(\w+)$`)
+
+func parsePnpmLockV9(lockfile pnpmLockfileV9, path string) []*extractor.Inventory {
+	// Find the snapshots reachable from production and development roots of every importer (i.e. workspace package)
+	prodRoots := []string{}
+	devRoots := []string{}
+	for _, imp := range lockfile.Importers {
+		for name, dep := range imp.Dependencies {
+			prodRoots = append(prodRoots, snapshotID(name, dep.Version))
+		}
+		for name, dep := range imp.OptionalDependencies {
+			prodRoots = append(prodRoots, snapshotID(name, dep.Version))
+		}
+		for name, dep := range imp.DevDependencies {
+			devRoots = append(devRoots, snapshotID(name, dep.Version))
+		}
+	}
+	prodReachable := reachableSnapshots(lockfile.Snapshots, prodRoots)
+	devReachable := reachableSnapshots(lockfile.Snapshots, devRoots)
+
+	type pkgKey struct{ name, version string }
+	packages := make(map[pkgKey]*extractor.Inventory)
+	optional := make(map[pkgKey]bool)
+	prod := make(map[pkgKey]bool)
+
+	for id, snap := range lockfile.Snapshots {
+		pkgID := peerlessID(id)
+		name, version, _ := npmname.Split(pkgID)
+
+		pkg := lockfile.Packages[pkgID]
+		if pkg.Name != "" {
+			name = pkg.Name
+		}
+		if pkg.Version != "" {
+			version = pkg.Version
+		}
+		if name == "" || version == "" || strings.HasPrefix(version, "file:") {
+			continue
+		}
+
+		commit := pkg.Resolution.Commit
+		if matched := codeLoadURLRegexp.FindStringSubmatch(pkg.Resolution.Tarball); matched != nil {
+			commit = matched[1]
+		}
+
+		key := pkgKey{name, version}
+		if _, ok := packages[key]; !ok {
+			packages[key] = &extractor.Inventory{
+				Name:      name,
+				Version:   version,
+				Locations: []string{path},
+				SourceCode: &extractor.SourceCodeIdentifier{
+					Commit: commit,
+				},
+			}
+			optional[key] = true
+		}
+
+		// a package is only dev or optional if every snapshot of it is
+		optional[key] = optional[key] && snap.Optional
+		prod[key] = prod[key] || prodReachable[id] || !devReachable[id]
+	}
+
+	invs := make([]*extractor.Inventory, 0, len(packages))
+	for key, inv := range packages {
+		depGroups := []string{}
+		if !prod[key] {
+			depGroups = append(depGroups, "dev")
+		}
+		if optional[key] {
+			depGroups = append(depGroups, "optional")
+		}
+		inv.Metadata = osv.DepGroupMetadata{DepGroupVals: depGroups}
+		invs = append(invs, inv)
+	}
+
+	return invs
+}
+
+// reachableSnapshots gets the ids of all the snapshots that are reachable from the given roots.
+func reachableSnapshots(snapshots map[string]pnpmLockSnapshot, roots []string) map[string]bool {
+	seen := make(map[string]bool)
+	queue := slices.Clone(roots)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		snap := snapshots[id]
+		for name, version := range snap.Dependencies {
+			queue = append(queue, snapshotID(name, version))
+		}
+		for name, version := range snap.OptionalDependencies {
+			queue = append(queue, snapshotID(name, version))
+		}
+	}
+
+	return seen
+}
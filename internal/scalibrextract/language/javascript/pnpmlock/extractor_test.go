@@ -0,0 +1,120 @@
+package pnpmlock_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/osv"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/javascript/pnpmlock"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "invalid yaml",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/not-yaml.txt",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "could not extract from"},
+		},
+		{
+			Name: "v5 lockfile",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/one-package-dev.yaml",
+			},
+			WantInventory: []*extractor.Inventory{
+				{
+					Name:       "acorn",
+					Version:    "8.7.0",
+					Locations:  []string{"testdata/one-package-dev.yaml"},
+					SourceCode: &extractor.SourceCodeIdentifier{},
+					Metadata:   osv.DepGroupMetadata{DepGroupVals: []string{}},
+				},
+			},
+		},
+		{
+			Name: "v9 commits",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/commits.v9.yaml",
+			},
+			WantInventory: []*extractor.Inventory{
+				{
+					Name:      "ansi-regex",
+					Version:   "6.0.1",
+					Locations: []string{"testdata/commits.v9.yaml"},
+					SourceCode: &extractor.SourceCodeIdentifier{
+						Commit: "02fa893d619d3da85411acc8fd4e2eea0e95a9d9",
+					},
+					Metadata: osv.DepGroupMetadata{DepGroupVals: []string{}},
+				},
+				{
+					Name:      "is-number",
+					Version:   "7.0.0",
+					Locations: []string{"testdata/commits.v9.yaml"},
+					SourceCode: &extractor.SourceCodeIdentifier{
+						Commit: "98e8ff1da1a89f93d1397a24d7413ed15421c139",
+					},
+					Metadata: osv.DepGroupMetadata{DepGroupVals: []string{}},
+				},
+			},
+		},
+		{
+			Name: "v9 workspace with peer dependencies",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/workspace.v9.yaml",
+			},
+			WantInventory: []*extractor.Inventory{
+				v9Package("@testing-library/react", "15.0.7", "dev"),
+				v9Package("ansi-regex", "5.0.1"),
+				v9Package("fsevents", "2.3.3", "optional"),
+				v9Package("js-tokens", "4.0.0"),
+				v9Package("loose-envify", "1.4.0"),
+				v9Package("react", "18.2.0"),
+				v9Package("react-dom", "18.2.0"),
+				v9Package("string-width", "4.2.3"),
+				v9Package("typescript", "5.4.5", "dev"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := pnpmlock.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got, cmpopts.SortSlices(extracttest.InventoryCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
+
+func v9Package(name, version string, groups ...string) *extractor.Inventory {
+	if groups == nil {
+		groups = []string{}
+	}
+
+	return &extractor.Inventory{
+		Name:       name,
+		Version:    version,
+		Locations:  []string{"testdata/workspace.v9.yaml"},
+		SourceCode: &extractor.SourceCodeIdentifier{},
+		Metadata:   osv.DepGroupMetadata{DepGroupVals: groups},
+	}
+}
@@ -0,0 +1,175 @@
+// Package yarnlock extracts yarn.lock files, adding support for the lockfiles of Yarn v2 and later ("Berry").
+package yarnlock
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/javascript/yarnlock"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+	"github.com/google/osv-scanner/v2/internal/utility/npmname"
+	"gopkg.in/yaml.v3"
+)
+
+// Name is the unique name of this extractor.
+const Name = yarnlock.Name
+
+type yarnBerryPackage struct {
+	Version    string `yaml:"version"`
+	Resolution string `yaml:"resolution"`
+}
+
+// Extractor extracts yarn.lock files.
+//
+// Classic (v1) lockfiles are extracted by the osv-scalibr yarnlock extractor,
+// while Berry lockfiles are parsed as the YAML they are so that packages are
+// named after what they resolve to, and workspace packages are skipped.
+type Extractor struct {
+	actualExtractor yarnlock.Extractor
+}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for yarn.lock files outside of node_modules
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	return e.actualExtractor.FileRequired(fapi)
+}
+
+// Extract extracts packages from yarn.lock files passed through the scan input.
+func (e Extractor) Extract(ctx context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	b, err := io.ReadAll(input.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	// classic lockfiles are not valid YAML, and Berry lockfiles always have a metadata block
+	var lockfile map[string]yarnBerryPackage
+	if err := yaml.Unmarshal(b, &lockfile); err != nil || !isBerry(lockfile) {
+		actualInput := *input
+		actualInput.Reader = bytes.NewReader(b)
+
+		return e.actualExtractor.Extract(ctx, &actualInput)
+	}
+
+	return parseYarnBerryLock(lockfile, input.Path), nil
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(i *extractor.Inventory) *purl.PackageURL {
+	return e.actualExtractor.ToPURL(i)
+}
+
+// Ecosystem returns the OSV ecosystem ('npm') of the software extracted by this extractor.
+func (e Extractor) Ecosystem(i *extractor.Inventory) string {
+	return e.actualExtractor.Ecosystem(i)
+}
+
+func isBerry(lockfile map[string]yarnBerryPackage) bool {
+	_, ok := lockfile["__metadata"]
+	return ok
+}
+
+// localProtocols are the protocols of packages which are part of the project
+// itself (or are otherwise on disk), rather than having been installed from a registry
+var localProtocols = []string{"workspace", "link", "portal"}
+
+var protocolRegexp = cachedregexp.MustCompile(`^([\w+]+):`)
+
+// Berry always records the exact commit that git dependencies are resolved to,
+// while github tarballs are resolved to a codeload url with the commit in its path
+var (
+	commitRegexp      = cachedregexp.MustCompile(`#commit[:=](\w+)$`)
+	codeLoadURLRegexp = cachedregexp.MustCompile(`https://codeload\.github\.com(?:/[\w-.]+){2}/tar\.gz/(\w+)$`)
+)
+
+func tryExtractCommit(reference string) string {
+	if matched := commitRegexp.FindStringSubmatch(reference); matched != nil {
+		return matched[1]
+	}
+	if matched := codeLoadURLRegexp.FindStringSubmatch(reference); matched != nil {
+		return matched[1]
+	}
+
+	return ""
+}
+
+func parseYarnBerryLock(lockfile map[string]yarnBerryPackage, path string) []*extractor.Inventory {
+	type pkgKey struct{ name, version string }
+	packages := make(map[pkgKey]*extractor.Inventory)
+
+	// the descriptors are sorted so that the output is deterministic, and so that
+	// patched packages come after the package being patched
+	for _, descriptor := range slices.Sorted(maps.Keys(lockfile)) {
+		if descriptor == "__metadata" {
+			continue
+		}
+		pkg := lockfile[descriptor]
+
+		// the resolution is used rather than the descriptor as it has the actual
+		// name of aliased packages (e.g. "string-width-cjs@npm:string-width@^4.2.0")
+		name, reference, ok := npmname.Split(pkg.Resolution)
+		if !ok || pkg.Version == "" {
+			continue
+		}
+
+		protocol := ""
+		if matched := protocolRegexp.FindStringSubmatch(reference); matched != nil {
+			protocol = matched[1]
+		}
+		if slices.Contains(localProtocols, protocol) {
+			continue
+		}
+
+		// patched packages have their own entry alongside the one for the package
+		// being patched, which has the same name and version
+		key := pkgKey{name, pkg.Version}
+		if _, ok := packages[key]; ok {
+			continue
+		}
+
+		commit := ""
+		if protocol != "patch" {
+			commit = tryExtractCommit(reference)
+		}
+
+		packages[key] = &extractor.Inventory{
+			Name:      name,
+			Version:   pkg.Version,
+			Locations: []string{path},
+			SourceCode: &extractor.SourceCodeIdentifier{
+				Commit: commit,
+			},
+		}
+	}
+
+	invs := make([]*extractor.Inventory, 0, len(packages))
+	for _, inv := range packages {
+		invs = append(invs, inv)
+	}
+
+	slices.SortFunc(invs, func(a, b *extractor.Inventory) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Version, b.Version))
+	})
+
+	return invs
+}
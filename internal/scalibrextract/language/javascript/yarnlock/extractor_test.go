@@ -0,0 +1,113 @@
+package yarnlock_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/javascript/yarnlock"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "v1 lockfile",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/one-package.v1.lock",
+			},
+			WantInventory: []*extractor.Inventory{
+				{
+					Name:       "balanced-match",
+					Version:    "1.0.2",
+					Locations:  []string{"testdata/one-package.v1.lock"},
+					SourceCode: &extractor.SourceCodeIdentifier{},
+				},
+			},
+		},
+		{
+			Name: "berry lockfile without packages",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/empty.v4.lock",
+			},
+			WantInventory: []*extractor.Inventory{},
+		},
+		{
+			Name: "berry lockfile",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/berry.v4.lock",
+			},
+			WantInventory: []*extractor.Inventory{
+				{
+					Name:       "@types/node",
+					Version:    "20.11.30",
+					Locations:  []string{"testdata/berry.v4.lock"},
+					SourceCode: &extractor.SourceCodeIdentifier{},
+				},
+				{
+					Name:      "is-positive",
+					Version:   "3.1.0",
+					Locations: []string{"testdata/berry.v4.lock"},
+					SourceCode: &extractor.SourceCodeIdentifier{
+						Commit: "97edff6f525f192a3f83cea1944765f769ae2678",
+					},
+				},
+				{
+					Name:       "lodash",
+					Version:    "4.17.21",
+					Locations:  []string{"testdata/berry.v4.lock"},
+					SourceCode: &extractor.SourceCodeIdentifier{},
+				},
+				{
+					Name:       "resolve",
+					Version:    "1.22.8",
+					Locations:  []string{"testdata/berry.v4.lock"},
+					SourceCode: &extractor.SourceCodeIdentifier{},
+				},
+				{
+					Name:       "string-width",
+					Version:    "4.2.3",
+					Locations:  []string{"testdata/berry.v4.lock"},
+					SourceCode: &extractor.SourceCodeIdentifier{},
+				},
+				{
+					Name:       "typescript",
+					Version:    "5.4.3",
+					Locations:  []string{"testdata/berry.v4.lock"},
+					SourceCode: &extractor.SourceCodeIdentifier{},
+				},
+				{
+					Name:       "undici-types",
+					Version:    "5.26.5",
+					Locations:  []string{"testdata/berry.v4.lock"},
+					SourceCode: &extractor.SourceCodeIdentifier{},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := yarnlock.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Package flakelock extracts the locked git inputs of Nix flake.lock files.
+package flakelock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+)
+
+// Name is the unique name of this extractor.
+const Name = "nix/flakelock"
+
+type flakeLockLocked struct {
+	Type  string `json:"type"`
+	Owner string `json:"owner"`
+	Repo  string `json:"repo"`
+	Host  string `json:"host"`
+	URL   string `json:"url"`
+	Rev   string `json:"rev"`
+}
+
+type flakeLockNode struct {
+	Locked *flakeLockLocked `json:"locked"`
+}
+
+type flakeLockFile struct {
+	Nodes map[string]flakeLockNode `json:"nodes"`
+	Root  string                   `json:"root"`
+}
+
+// Extractor extracts the git commits that the inputs of a Nix flake are locked to,
+// so that they can be matched against git ranges.
+//
+// Inputs that are not locked to a commit of a git repository (e.g. path and tarball inputs) are skipped.
+type Extractor struct{}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for flake.lock files
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	return filepath.Base(fapi.Path()) == "flake.lock"
+}
+
+// Extract extracts the locked inputs of flake.lock files passed through the scan input.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	var lockfile flakeLockFile
+	if err := json.NewDecoder(input.Reader).Decode(&lockfile); err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	root := lockfile.Root
+	if root == "" {
+		root = "root"
+	}
+
+	type inputKey struct{ repo, commit string }
+	seen := make(map[inputKey]bool)
+
+	packages := make([]*extractor.Inventory, 0, len(lockfile.Nodes))
+	// iterate in order so that the name of duplicated inputs is deterministic
+	for _, name := range slices.Sorted(maps.Keys(lockfile.Nodes)) {
+		node := lockfile.Nodes[name]
+		if name == root || node.Locked == nil || node.Locked.Rev == "" {
+			continue
+		}
+
+		repo := repoURL(*node.Locked)
+		if repo == "" {
+			continue
+		}
+
+		// the same input is often locked multiple times (e.g. nixpkgs being an input of many flakes)
+		key := inputKey{repo, node.Locked.Rev}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		packages = append(packages, &extractor.Inventory{
+			Name: name,
+			SourceCode: &extractor.SourceCodeIdentifier{
+				Repo:   repo,
+				Commit: node.Locked.Rev,
+			},
+			Locations: []string{input.Path},
+		})
+	}
+
+	return packages, nil
+}
+
+// repoURL gets the url of the git repository that an input is locked to,
+// returning an empty string if the input is not a git repository.
+func repoURL(locked flakeLockLocked) string {
+	forgeURL := func(defaultHost string) string {
+		host := locked.Host
+		if host == "" {
+			host = defaultHost
+		}
+
+		return "https://" + host + "/" + locked.Owner + "/" + locked.Repo
+	}
+
+	switch locked.Type {
+	case "github":
+		return forgeURL("github.com")
+	case "gitlab":
+		return forgeURL("gitlab.com")
+	case "sourcehut":
+		return forgeURL("git.sr.ht")
+	case "git":
+		return locked.URL
+	default:
+		return ""
+	}
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(_ *extractor.Inventory) *purl.PackageURL {
+	return nil
+}
+
+// Ecosystem returns an empty string as all inventories are commit hashes
+func (e Extractor) Ecosystem(_ *extractor.Inventory) string {
+	return ""
+}
@@ -0,0 +1,71 @@
+package flakelock_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/nix/flakelock"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "invalid json",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/not-json.txt",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "could not extract from"},
+		},
+		{
+			Name: "locked inputs",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/flake.lock",
+			},
+			WantInventory: []*extractor.Inventory{
+				flakeInput("flake-utils", "https://github.com/numtide/flake-utils", "b1d9ab70662946ef0850d488da1c9019f3a9752a"),
+				flakeInput("libgit", "https://git.example.com/libgit.git", "2c1b1a4af58d4ac7ba9b5c68855e8cd7bd7a9a5b"),
+				flakeInput("nixpkgs", "https://github.com/NixOS/nixpkgs", "d8fe5e6c92d0d190646fb9f1056741a229980089"),
+				flakeInput("systems", "https://github.com/nix-systems/default", "da67096a3b9bf56a91d16901293e51ba5b49a27e"),
+				flakeInput("tool", "https://gitlab.example.com/team/tool", "0f8c3e3bb1a5f8e1d6e8d6e9f1c2a3b4c5d6e7f8"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := flakelock.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got, cmpopts.SortSlices(extracttest.InventoryCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
+
+func flakeInput(name, repo, commit string) *extractor.Inventory {
+	return &extractor.Inventory{
+		Name: name,
+		SourceCode: &extractor.SourceCodeIdentifier{
+			Repo:   repo,
+			Commit: commit,
+		},
+		Locations: []string{"testdata/flake.lock"},
+	}
+}
@@ -0,0 +1,110 @@
+// Package composerlock extracts composer.lock files, skipping packages from path repositories.
+package composerlock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/php/composerlock"
+	"github.com/google/osv-scalibr/extractor/filesystem/osv"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+)
+
+// Name is the unique name of this extractor.
+const Name = composerlock.Name
+
+type composerPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Dist    struct {
+		Type      string `json:"type"`
+		Reference string `json:"reference"`
+	} `json:"dist"`
+}
+
+// isLocal returns true if the package is from a path repository (i.e. it is part of
+// the project itself, such as in a monorepo), rather than from a package registry or VCS.
+//
+// See https://getcomposer.org/doc/05-repositories.md#path
+func (p composerPackage) isLocal() bool {
+	return p.Dist.Type == "path"
+}
+
+type composerLock struct {
+	Packages    []composerPackage `json:"packages"`
+	PackagesDev []composerPackage `json:"packages-dev"`
+}
+
+// Extractor extracts Packagist packages from composer.lock files.
+//
+// Packages from path repositories are skipped as they are local to the project,
+// so would only ever be matched against the advisories of an unrelated package.
+type Extractor struct {
+	actualExtractor composerlock.Extractor
+}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for composer.lock files
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	return e.actualExtractor.FileRequired(fapi)
+}
+
+// Extract extracts packages from composer.lock files passed through the scan input.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	var lockfile composerLock
+	if err := json.NewDecoder(input.Reader).Decode(&lockfile); err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	packages := make([]*extractor.Inventory, 0, len(lockfile.Packages)+len(lockfile.PackagesDev))
+	add := func(pkgs []composerPackage, depGroups []string) {
+		for _, pkg := range pkgs {
+			if pkg.isLocal() {
+				continue
+			}
+
+			packages = append(packages, &extractor.Inventory{
+				Name:      pkg.Name,
+				Version:   pkg.Version,
+				Locations: []string{input.Path},
+				SourceCode: &extractor.SourceCodeIdentifier{
+					Commit: pkg.Dist.Reference,
+				},
+				Metadata: osv.DepGroupMetadata{
+					DepGroupVals: depGroups,
+				},
+			})
+		}
+	}
+
+	add(lockfile.Packages, []string{})
+	add(lockfile.PackagesDev, []string{"dev"})
+
+	return packages, nil
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(i *extractor.Inventory) *purl.PackageURL {
+	return e.actualExtractor.ToPURL(i)
+}
+
+// Ecosystem returns the OSV ecosystem ('Packagist') of the software extracted by this extractor.
+func (e Extractor) Ecosystem(i *extractor.Inventory) string {
+	return e.actualExtractor.Ecosystem(i)
+}
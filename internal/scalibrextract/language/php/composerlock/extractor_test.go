@@ -0,0 +1,74 @@
+package composerlock_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/osv"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/php/composerlock"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "invalid json",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/not-json.txt",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "could not extract from"},
+		},
+		{
+			Name: "path repositories",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/path-repositories.json",
+			},
+			WantInventory: []*extractor.Inventory{
+				{
+					Name:      "monolog/monolog",
+					Version:   "3.5.0",
+					Locations: []string{"testdata/path-repositories.json"},
+					SourceCode: &extractor.SourceCodeIdentifier{
+						Commit: "c915e2634718dbc8a4a15c61b0e62e7a44e14448",
+					},
+					Metadata: osv.DepGroupMetadata{DepGroupVals: []string{}},
+				},
+				{
+					Name:      "phpunit/phpunit",
+					Version:   "10.5.5",
+					Locations: []string{"testdata/path-repositories.json"},
+					SourceCode: &extractor.SourceCodeIdentifier{
+						Commit: "ed21115d505b4b4f7dc7b5651464e19a2c7f7856",
+					},
+					Metadata: osv.DepGroupMetadata{DepGroupVals: []string{"dev"}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := composerlock.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got, cmpopts.SortSlices(extracttest.InventoryCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
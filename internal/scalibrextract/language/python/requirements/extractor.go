@@ -0,0 +1,354 @@
+// Package requirements extracts requirements.txt files, following any other requirements
+// and constraints files they include.
+package requirements
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/python/requirements"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+
+	scalibrfs "github.com/google/osv-scalibr/fs"
+)
+
+// Name is the unique name of this extractor.
+const Name = requirements.Name
+
+// Metadata holds the parsing information for a requirement.
+type Metadata struct {
+	// HashCheckingModeValues are the values of the --hash options of the requirement
+	HashCheckingModeValues []string
+	// VersionComparator is the comparator the version was specified with, e.g. ==, ~=, >=
+	VersionComparator string
+	// Marker is the PEP 508 environment marker of the requirement (e.g. `python_version < "3.9"`),
+	// which is empty if the requirement applies to all environments
+	Marker string
+}
+
+var (
+	// https://github.com/pypa/pip/blob/72a32e/src/pip/_internal/req/req_file.py#L492
+	reComment = cachedregexp.MustCompile(`(^|\s+)#.*$`)
+	// https://github.com/pypa/pip/blob/72a32e/src/pip/_internal/req/req_file.py#L503
+	reEnvVar = cachedregexp.MustCompile(`\$\{[A-Z0-9_]+\}`)
+	// per-requirement options, which can only come after the requirement itself
+	rePerRequirementOptions = cachedregexp.MustCompile(`(?:^|\s)(?:--hash|--global-option|--config-settings|-C)(?:[=\s]|$).*$`)
+	reHashOption            = cachedregexp.MustCompile(`--hash[=\s]\s*(\S+)`)
+	reWhitespace            = cachedregexp.MustCompile(`\s`)
+	reExtras                = cachedregexp.MustCompile(`\[[^\[\]]*\]`)
+	// We currently don't handle the following constraints.
+	// * Version wildcards (*)
+	// * Less than (<)
+	// * Multiple constraints (,)
+	reUnsupportedConstraints = cachedregexp.MustCompile(`\*|<[^=]|,`)
+	// https://packaging.python.org/en/latest/specifications/name-normalization/#name-format
+	reValidName      = cachedregexp.MustCompile(`(?i)^[a-z0-9]([a-z0-9._-]*[a-z0-9])?$`)
+	reNameSeparators = cachedregexp.MustCompile(`[-_.]+`)
+)
+
+// Extractor extracts PyPI packages from requirements.txt files.
+type Extractor struct {
+	actualExtractor requirements.Extractor
+}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for .txt files with "requirements" in their name
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	return e.actualExtractor.FileRequired(fapi)
+}
+
+// Extract extracts packages from requirements.txt files passed through the scan input,
+// along with those from any requirements files it includes using -r.
+//
+// Constraints files included using -c do not add any packages, but instead pin the
+// versions of the requirements that are constrained by them.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	packages, includes, err := parseRequirementsFile(input.Reader, input.Path, false)
+	if err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	var constraints []*extractor.Inventory
+
+	// includes are parsed breadth-first, with files only being parsed once
+	// to avoid duplicates and prevent infinite loops from circular includes
+	seen := map[include]bool{{filepath.ToSlash(input.Path), false}: true}
+	for len(includes) > 0 {
+		inc := includes[0]
+		includes = includes[1:]
+		if seen[inc] {
+			continue
+		}
+		seen[inc] = true
+
+		pkgs, newIncludes, err := openAndParseRequirementsFile(input.FS, inc)
+		if err != nil {
+			slog.Warn(fmt.Sprintf("could not extract from %s (included by %s): %v", inc.path, input.Path, err))
+			continue
+		}
+		includes = append(includes, newIncludes...)
+
+		if inc.constraint {
+			constraints = append(constraints, pkgs...)
+			continue
+		}
+		for _, pkg := range pkgs {
+			// Note the path through which we refer to this requirements.txt file.
+			pkg.Locations[0] = input.Path + ":" + pkg.Locations[0]
+		}
+		packages = append(packages, pkgs...)
+	}
+
+	return applyConstraints(packages, constraints), nil
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(i *extractor.Inventory) *purl.PackageURL {
+	return e.actualExtractor.ToPURL(i)
+}
+
+// Ecosystem returns the OSV ecosystem ('PyPI') of the software extracted by this extractor.
+func (e Extractor) Ecosystem(i *extractor.Inventory) string {
+	return e.actualExtractor.Ecosystem(i)
+}
+
+// include is a requirements or constraints file that has been included by another requirements file
+type include struct {
+	path       string
+	constraint bool
+}
+
+func openAndParseRequirementsFile(fsys scalibrfs.FS, inc include) ([]*extractor.Inventory, []include, error) {
+	if fsys == nil {
+		return nil, nil, fmt.Errorf("no filesystem available to open %s", inc.path)
+	}
+
+	f, err := fsys.Open(inc.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	return parseRequirementsFile(f, inc.path, inc.constraint)
+}
+
+// parseRequirementsFile parses the requirements of the file at p, returning them
+// along with the requirements and constraints files that it includes.
+//
+// Everything that is included by a constraints file is also a constraint.
+func parseRequirementsFile(r io.Reader, p string, constraint bool) ([]*extractor.Inventory, []include, error) {
+	lines, err := readLogicalLines(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var packages []*extractor.Inventory
+	var includes []include
+	for _, line := range lines {
+		if strings.HasPrefix(line, "-") {
+			if inc, ok := parseInclude(line); ok {
+				// includes are relative to the directory of the file that is including them
+				inc.path = path.Join(path.Dir(filepath.ToSlash(p)), filepath.ToSlash(inc.path))
+				inc.constraint = inc.constraint || constraint
+				includes = append(includes, inc)
+			}
+
+			// all other global options (such as editable installs) are not supported
+			// https://pip.pypa.io/en/stable/reference/requirements-file-format/#global-options
+			continue
+		}
+
+		if pkg := parseRequirement(line); pkg != nil {
+			pkg.Locations = []string{p}
+			packages = append(packages, pkg)
+		}
+	}
+
+	return packages, includes, nil
+}
+
+// readLogicalLines reads the lines of a requirements file, joining lines that end
+// with a backslash and removing comments and empty lines.
+func readLogicalLines(r io.Reader) ([]string, error) {
+	var lines []string
+	var current strings.Builder
+	flush := func() {
+		line := strings.TrimSpace(current.String())
+		current.Reset()
+
+		// Ignore lines using env variables
+		if line != "" && !reEnvVar.MatchString(line) {
+			lines = append(lines, line)
+		}
+	}
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		l := reComment.ReplaceAllString(s.Text(), "")
+		if strings.HasSuffix(l, `\`) {
+			current.WriteString(strings.TrimSuffix(l, `\`) + " ")
+			continue
+		}
+		current.WriteString(l)
+		flush()
+	}
+	flush()
+
+	return lines, s.Err()
+}
+
+// parseInclude gets the requirements or constraints file included by a -r or -c option,
+// returning false if the option is not an include.
+func parseInclude(line string) (include, bool) {
+	for _, opt := range []struct {
+		long, short string
+		constraint  bool
+	}{
+		{"--requirement", "-r", false},
+		{"--constraint", "-c", true},
+	} {
+		rest, ok := strings.CutPrefix(line, opt.long)
+		if ok {
+			if rest != "" && rest[0] != '=' && rest[0] != ' ' && rest[0] != '\t' {
+				return include{}, false
+			}
+			rest = strings.TrimPrefix(rest, "=")
+		} else if rest, ok = strings.CutPrefix(line, opt.short); !ok {
+			continue
+		}
+
+		if rest = strings.TrimSpace(rest); rest == "" {
+			return include{}, false
+		}
+
+		return include{path: rest, constraint: opt.constraint}, true
+	}
+
+	return include{}, false
+}
+
+// parseRequirement parses a requirement specifier line into a package, returning nil
+// if the requirement is not for a package on an index (e.g. direct references such as
+// local paths and urls) or has a version specifier that is not supported.
+//
+// The version of the package will be empty if the requirement does not specify one.
+func parseRequirement(line string) *extractor.Inventory {
+	hashes := []string{}
+	for _, match := range reHashOption.FindAllStringSubmatch(line, -1) {
+		hashes = append(hashes, match[1])
+	}
+	line = rePerRequirementOptions.ReplaceAllString(line, "")
+
+	line, marker, _ := strings.Cut(line, ";")
+	if strings.Contains(line, "@") || strings.Contains(line, "/") {
+		return nil
+	}
+	line = reExtras.ReplaceAllString(reWhitespace.ReplaceAllString(line, ""), "")
+
+	name, version, comparator := getLowestVersion(line)
+	if name == "" && !reUnsupportedConstraints.MatchString(line) {
+		name = line
+	}
+	if !reValidName.MatchString(name) {
+		return nil
+	}
+
+	return &extractor.Inventory{
+		Name:    name,
+		Version: version,
+		Metadata: &Metadata{
+			HashCheckingModeValues: hashes,
+			VersionComparator:      comparator,
+			Marker:                 strings.TrimSpace(marker),
+		},
+	}
+}
+
+func getLowestVersion(s string) (name, version, comparator string) {
+	if reUnsupportedConstraints.MatchString(s) {
+		return "", "", ""
+	}
+
+	for _, sep := range []string{"===", "==", ">=", "<=", "~="} {
+		if name, version, ok := strings.Cut(s, sep); ok {
+			// For all other separators the lowest version is the one we found.
+			return name, version, sep
+		}
+	}
+
+	return "", "", ""
+}
+
+// normalizeName normalizes a package name as per https://peps.python.org/pep-0503/#normalized-names
+func normalizeName(name string) string {
+	return strings.ToLower(reNameSeparators.ReplaceAllLiteralString(name, "-"))
+}
+
+// applyConstraints pins the versions of packages which have been constrained to an exact version
+// (or which otherwise have no version), dropping any packages that are still without a version
+// as well as the duplicates of packages that are required by multiple requirements files.
+func applyConstraints(packages []*extractor.Inventory, constraints []*extractor.Inventory) []*extractor.Inventory {
+	pinned := make(map[string]*extractor.Inventory)
+	for _, c := range constraints {
+		if c.Version == "" {
+			continue
+		}
+		name := normalizeName(c.Name)
+		existing, ok := pinned[name]
+		if !ok || (!isExact(existing) && isExact(c)) {
+			pinned[name] = c
+		}
+	}
+
+	type pkgKey struct{ name, version string }
+	seen := make(map[pkgKey]bool)
+	result := make([]*extractor.Inventory, 0, len(packages))
+	for _, pkg := range packages {
+		name := normalizeName(pkg.Name)
+		if c, ok := pinned[name]; ok && (pkg.Version == "" || (isExact(c) && !isExact(pkg))) {
+			pkg.Version = c.Version
+			pkg.Metadata.(*Metadata).VersionComparator = c.Metadata.(*Metadata).VersionComparator
+		}
+		if pkg.Version == "" {
+			continue
+		}
+
+		key := pkgKey{name, pkg.Version}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, pkg)
+	}
+
+	return result
+}
+
+// isExact returns true if the package was required or constrained to an exact version
+func isExact(pkg *extractor.Inventory) bool {
+	comparator := pkg.Metadata.(*Metadata).VersionComparator
+
+	return comparator == "==" || comparator == "==="
+}
@@ -0,0 +1,127 @@
+package requirements_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/python/requirements"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "hashes and markers",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/hashes-and-markers.txt",
+			},
+			WantInventory: []*extractor.Inventory{
+				{
+					Name:      "certifi",
+					Version:   "2024.8.30",
+					Locations: []string{"testdata/hashes-and-markers.txt"},
+					Metadata: &requirements.Metadata{
+						HashCheckingModeValues: []string{
+							"sha256:922820b53db7a7257ffbda3f597266d435245903d80737e34f8a45ff3e3230d8",
+							"sha256:bec941d2aa8195e248a60b31ff9f0558284cf01a52591ceda73ea9afffd69fd9",
+						},
+						VersionComparator: "==",
+					},
+				},
+				{
+					Name:      "django-Crispy-forms",
+					Version:   "2.1",
+					Locations: []string{"testdata/hashes-and-markers.txt"},
+					Metadata: &requirements.Metadata{
+						HashCheckingModeValues: []string{"sha256:4d7ec431933ad4d4b5c5a6de4a584d24613c52aa48700fa2cfa1b7e2c69ad2e5"},
+						VersionComparator:      "==",
+					},
+				},
+				requirement("zope.interface", "6.1", "testdata/hashes-and-markers.txt", ""),
+				{
+					Name:      "importlib-metadata",
+					Version:   "8.5.0",
+					Locations: []string{"testdata/hashes-and-markers.txt"},
+					Metadata: &requirements.Metadata{
+						HashCheckingModeValues: []string{"sha256:45e54197d28b7a7f1559e60b95e7c567032b602131fbd588f1497f47880aa68b"},
+						VersionComparator:      "==",
+						Marker:                 `python_version < "3.10"`,
+					},
+				},
+				requirement("pywin32", "306", "testdata/hashes-and-markers.txt", `sys_platform == "win32"`),
+				requirement("requests", "2.32.3", "testdata/hashes-and-markers.txt", ""),
+			},
+		},
+		{
+			Name: "editable installs and options",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/editable-and-options.txt",
+			},
+			WantInventory: []*extractor.Inventory{
+				requirement("attrs", "24.2.0", "testdata/editable-and-options.txt", ""),
+			},
+		},
+		{
+			Name: "includes",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/includes.txt",
+			},
+			WantInventory: []*extractor.Inventory{
+				requirement("flask", "3.0.3", "testdata/includes.txt", ""),
+				requirement("click", "8.1.7", "testdata/includes.txt:testdata/nested/base.txt", ""),
+				requirement("jinja2", "3.1.4", "testdata/includes.txt:testdata/nested/common.txt", ""),
+			},
+		},
+		{
+			Name: "constraints",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/constrained.txt",
+			},
+			WantInventory: []*extractor.Inventory{
+				requirement("requests", "2.32.3", "testdata/constrained.txt", ""),
+				requirement("flask", "3.0.3", "testdata/constrained.txt", ""),
+				requirement("Jinja2", "3.1.4", "testdata/constrained.txt", ""),
+				requirement("attrs", "24.2.0", "testdata/constrained.txt", ""),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := requirements.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got, cmpopts.SortSlices(extracttest.InventoryCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
+
+func requirement(name, version, location, marker string) *extractor.Inventory {
+	return &extractor.Inventory{
+		Name:      name,
+		Version:   version,
+		Locations: []string{location},
+		Metadata: &requirements.Metadata{
+			HashCheckingModeValues: []string{},
+			VersionComparator:      "==",
+			Marker:                 marker,
+		},
+	}
+}
@@ -0,0 +1,202 @@
+// Package sitepackages extracts the Python packages installed in the site-packages
+// directory of an environment, from the metadata of each distribution.
+package sitepackages
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/python/wheelegg"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+)
+
+// Name is the unique name of this extractor.
+const Name = "python/sitepackages"
+
+// Metadata holds the information of a package installed in site-packages.
+type Metadata struct {
+	// EditableSource is the directory that the package was installed from
+	// if it is an editable (i.e. development) install
+	EditableSource string
+}
+
+// directURL is the direct_url.json file recorded by pip for packages that were not
+// installed from an index, per https://packaging.python.org/en/latest/specifications/direct-url/
+type directURL struct {
+	URL     string `json:"url"`
+	DirInfo struct {
+		Editable bool `json:"editable"`
+	} `json:"dir_info"`
+}
+
+// Extractor extracts PyPI packages from the .dist-info and .egg-info directories
+// of a site-packages directory, along with the .egg-link files of editable installs.
+type Extractor struct {
+	actualExtractor wheelegg.Extractor
+}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// IsSitePackages returns true if the directory is one that pip installs packages into
+func IsSitePackages(dir string) bool {
+	base := filepath.Base(dir)
+
+	return base == "site-packages" || base == "dist-packages"
+}
+
+// FileRequired returns true for the metadata of distributions that are directly
+// within a site-packages directory, and the .egg-link files of editable installs
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	p := filepath.ToSlash(fapi.Path())
+	dir := path.Dir(p)
+
+	switch {
+	case path.Ext(p) == ".egg-link":
+		return IsSitePackages(dir)
+	case path.Base(p) == "METADATA":
+		return strings.HasSuffix(dir, ".dist-info") && IsSitePackages(path.Dir(dir))
+	case path.Base(p) == "PKG-INFO":
+		return strings.HasSuffix(dir, ".egg-info") && IsSitePackages(path.Dir(dir))
+	}
+
+	return false
+}
+
+// Extract extracts packages from the metadata files passed through the scan input.
+func (e Extractor) Extract(ctx context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	if path.Ext(filepath.ToSlash(input.Path)) == ".egg-link" {
+		return e.extractEggLink(ctx, input)
+	}
+
+	invs, err := e.actualExtractor.Extract(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &Metadata{}
+	if path.Base(filepath.ToSlash(input.Path)) == "METADATA" {
+		metadata.EditableSource, err = editableSource(input.FS, path.Join(path.Dir(filepath.ToSlash(input.Path)), "direct_url.json"))
+		if err != nil {
+			return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+		}
+	}
+
+	for _, inv := range invs {
+		inv.Metadata = metadata
+	}
+
+	return invs, nil
+}
+
+// extractEggLink extracts the package that an .egg-link file links to, which was
+// installed with "setup.py develop" (or an older version of pip) and so does not
+// have a .dist-info directory, from the PKG-INFO in the .egg-info of its source.
+func (e Extractor) extractEggLink(ctx context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	scanner := bufio.NewScanner(input.Reader)
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, errors.Join(scanner.Err(), errors.New("file is empty")))
+	}
+
+	// the source is usually an absolute path, but is relative to the site-packages directory otherwise
+	source := strings.TrimSpace(scanner.Text())
+	if !filepath.IsAbs(source) {
+		source = filepath.Join(input.Root, filepath.Dir(input.Path), source)
+	}
+
+	rel, err := filepath.Rel(input.Root, source)
+	if err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	pkgInfos, err := fs.Glob(input.FS, path.Join(filepath.ToSlash(rel), "*.egg-info", "PKG-INFO"))
+	if err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	// the source no longer being there means the package cannot be imported anymore either
+	if len(pkgInfos) == 0 {
+		return []*extractor.Inventory{}, nil
+	}
+
+	f, err := input.FS.Open(pkgInfos[0])
+	if err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+	defer f.Close()
+
+	invs, err := e.actualExtractor.Extract(ctx, &filesystem.ScanInput{
+		FS:     input.FS,
+		Path:   pkgInfos[0],
+		Root:   input.Root,
+		Reader: f,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	for _, inv := range invs {
+		inv.Locations = []string{input.Path}
+		inv.Metadata = &Metadata{EditableSource: source}
+	}
+
+	return invs, nil
+}
+
+// editableSource reads the directory that a package was installed from
+// out of its direct_url.json, if it exists and is for an editable install
+func editableSource(fsys fs.FS, directURLPath string) (string, error) {
+	content, err := fs.ReadFile(fsys, directURLPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var du directURL
+	if err := json.Unmarshal(content, &du); err != nil {
+		return "", fmt.Errorf("invalid direct_url.json: %w", err)
+	}
+
+	if !du.DirInfo.Editable {
+		return "", nil
+	}
+
+	if u, err := url.Parse(du.URL); err == nil && u.Scheme == "file" {
+		return filepath.FromSlash(u.Path), nil
+	}
+
+	return du.URL, nil
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(i *extractor.Inventory) *purl.PackageURL {
+	return e.actualExtractor.ToPURL(i)
+}
+
+// Ecosystem returns the OSV ecosystem ('PyPI') of the software extracted by this extractor.
+func (e Extractor) Ecosystem(i *extractor.Inventory) string {
+	return e.actualExtractor.Ecosystem(i)
+}
@@ -0,0 +1,150 @@
+package sitepackages_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/simplefileapi"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/python/sitepackages"
+)
+
+func TestExtractor_FileRequired(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "venv/lib/python3.12/site-packages/requests-2.32.3.dist-info/METADATA", want: true},
+		{path: "/usr/lib/python3/dist-packages/six-1.16.0.egg-info/PKG-INFO", want: true},
+		{path: "venv/lib/python3.12/site-packages/mypkg.egg-link", want: true},
+		{path: "venv/lib/python3.12/site-packages/requests-2.32.3.dist-info/RECORD", want: false},
+		{path: "venv/lib/python3.12/site-packages/setuptools/_vendor/packaging-24.2.dist-info/METADATA", want: false},
+		{path: "dist/requests-2.32.3.dist-info/METADATA", want: false},
+		{path: "src/mypkg.egg-info/PKG-INFO", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			got := sitepackages.Extractor{}.FileRequired(simplefileapi.New(filepath.FromSlash(tt.path), nil))
+			if got != tt.want {
+				t.Errorf("FileRequired(%s) got = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "dist-info",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/site-packages/requests-2.32.3.dist-info/METADATA",
+			},
+			WantInventory: []*extractor.Inventory{
+				{
+					Name:      "requests",
+					Version:   "2.32.3",
+					Locations: []string{"testdata/site-packages/requests-2.32.3.dist-info/METADATA"},
+					Metadata:  &sitepackages.Metadata{},
+				},
+			},
+		},
+		{
+			Name: "editable dist-info",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/site-packages/mypkg-0.1.0.dist-info/METADATA",
+			},
+			WantInventory: []*extractor.Inventory{
+				{
+					Name:      "mypkg",
+					Version:   "0.1.0",
+					Locations: []string{"testdata/site-packages/mypkg-0.1.0.dist-info/METADATA"},
+					Metadata:  &sitepackages.Metadata{EditableSource: filepath.FromSlash("/home/user/projects/mypkg")},
+				},
+			},
+		},
+		{
+			Name: "egg-info",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/site-packages/six-1.16.0.egg-info/PKG-INFO",
+			},
+			WantInventory: []*extractor.Inventory{
+				{
+					Name:      "six",
+					Version:   "1.16.0",
+					Locations: []string{"testdata/site-packages/six-1.16.0.egg-info/PKG-INFO"},
+					Metadata:  &sitepackages.Metadata{},
+				},
+			},
+		},
+		{
+			Name: "egg-link",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/site-packages/legacy.egg-link",
+			},
+			WantInventory: []*extractor.Inventory{
+				{
+					Name:      "legacy",
+					Version:   "2.0.0",
+					Locations: []string{"testdata/site-packages/legacy.egg-link"},
+					Metadata:  &sitepackages.Metadata{EditableSource: absPath(t, "testdata/src/legacy")},
+				},
+			},
+		},
+		{
+			Name: "egg-link to a missing source",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/site-packages/missing.egg-link",
+			},
+			WantInventory: []*extractor.Inventory{},
+		},
+		{
+			Name: "no name",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/site-packages/broken-1.0.dist-info/METADATA",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "wheelegg.parse"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := sitepackages.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got, cmpopts.SortSlices(extracttest.InventoryCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
+
+func absPath(t *testing.T, p string) string {
+	t.Helper()
+
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return abs
+}
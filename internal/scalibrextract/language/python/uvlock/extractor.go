@@ -0,0 +1,219 @@
+// Package uvlock extracts uv.lock files, determining the dependency groups of packages
+// by walking the dependency graph from the project's own packages.
+package uvlock
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/python/uvlock"
+	"github.com/google/osv-scalibr/extractor/filesystem/osv"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+)
+
+// Name is the unique name of this extractor.
+const Name = uvlock.Name
+
+type uvLockPackageSource struct {
+	Registry string `toml:"registry"`
+	Git      string `toml:"git"`
+	URL      string `toml:"url"`
+	Virtual  string `toml:"virtual"`
+	Editable string `toml:"editable"`
+}
+
+type uvLockDependency struct {
+	Name    string   `toml:"name"`
+	Version string   `toml:"version"`
+	Extra   []string `toml:"extra"`
+}
+
+type uvLockPackage struct {
+	Name                 string                        `toml:"name"`
+	Version              string                        `toml:"version"`
+	Source               uvLockPackageSource           `toml:"source"`
+	Dependencies         []uvLockDependency            `toml:"dependencies"`
+	OptionalDependencies map[string][]uvLockDependency `toml:"optional-dependencies"`
+	DevDependencies      map[string][]uvLockDependency `toml:"dev-dependencies"`
+}
+
+// isProject returns true if the package is (a workspace member of) the project being locked,
+// rather than one of its dependencies
+func (p uvLockPackage) isProject() bool {
+	return p.Source.Virtual != "" || p.Source.Editable != ""
+}
+
+type uvLockFile struct {
+	Version  int             `toml:"version"`
+	Packages []uvLockPackage `toml:"package"`
+}
+
+// Extractor extracts PyPI packages from uv.lock files.
+type Extractor struct {
+	actualExtractor uvlock.Extractor
+}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for uv.lock files
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	return e.actualExtractor.FileRequired(fapi)
+}
+
+// Extract extracts packages from uv.lock files passed through the scan input.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	var lockfile uvLockFile
+	if _, err := toml.NewDecoder(input.Reader).Decode(&lockfile); err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	g := newGraph(lockfile.Packages)
+
+	// walk the graph from the project packages to find out which group(s) each package belongs to
+	prod := make(map[int]bool)
+	dev := make(map[int]bool)
+	optional := make(map[int]map[string]bool)
+	for _, pkg := range lockfile.Packages {
+		if !pkg.isProject() {
+			continue
+		}
+		g.walk(pkg.Dependencies, func(j int) { prod[j] = true })
+		for group, deps := range pkg.OptionalDependencies {
+			g.walk(deps, func(j int) {
+				if optional[j] == nil {
+					optional[j] = make(map[string]bool)
+				}
+				optional[j][group] = true
+			})
+		}
+		for _, deps := range pkg.DevDependencies {
+			g.walk(deps, func(j int) { dev[j] = true })
+		}
+	}
+
+	packages := make([]*extractor.Inventory, 0, len(lockfile.Packages))
+	for i, pkg := range lockfile.Packages {
+		if pkg.isProject() {
+			continue
+		}
+
+		inv := &extractor.Inventory{
+			Name:      pkg.Name,
+			Version:   pkg.Version,
+			Locations: []string{input.Path},
+		}
+
+		if _, commit, _ := strings.Cut(pkg.Source.Git, "#"); commit != "" {
+			inv.SourceCode = &extractor.SourceCodeIdentifier{
+				Commit: commit,
+			}
+		}
+
+		depGroups := []string{}
+		if !prod[i] {
+			depGroups = slices.Sorted(maps.Keys(optional[i]))
+			if dev[i] {
+				depGroups = append(depGroups, "dev")
+			}
+		}
+		inv.Metadata = osv.DepGroupMetadata{
+			DepGroupVals: depGroups,
+		}
+
+		packages = append(packages, inv)
+	}
+
+	return packages, nil
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(i *extractor.Inventory) *purl.PackageURL {
+	return e.actualExtractor.ToPURL(i)
+}
+
+// Ecosystem returns the OSV ecosystem ('PyPI') of the software extracted by this extractor.
+func (e Extractor) Ecosystem(i *extractor.Inventory) string {
+	return e.actualExtractor.Ecosystem(i)
+}
+
+// graph allows looking up the packages that dependencies of a uv.lock refer to
+type graph struct {
+	packages []uvLockPackage
+	byName   map[string][]int
+}
+
+func newGraph(packages []uvLockPackage) graph {
+	g := graph{packages: packages, byName: make(map[string][]int)}
+	for i, pkg := range packages {
+		g.byName[pkg.Name] = append(g.byName[pkg.Name], i)
+	}
+
+	return g
+}
+
+// resolve gets the indexes of the packages a dependency refers to.
+// The version is only present when the lockfile contains multiple versions of a package,
+// in which case it is used to pick the right one.
+func (g graph) resolve(dep uvLockDependency) []int {
+	if dep.Version == "" {
+		return g.byName[dep.Name]
+	}
+
+	for _, i := range g.byName[dep.Name] {
+		if g.packages[i].Version == dep.Version {
+			return []int{i}
+		}
+	}
+
+	return nil
+}
+
+// walk calls visit for every package that is transitively depended on by deps,
+// including the dependencies of any requested extras.
+func (g graph) walk(deps []uvLockDependency, visit func(int)) {
+	type extraKey struct {
+		pkg   int
+		extra string
+	}
+	seen := make(map[int]bool)
+	seenExtras := make(map[extraKey]bool)
+	queue := slices.Clone(deps)
+	for len(queue) > 0 {
+		dep := queue[0]
+		queue = queue[1:]
+
+		for _, i := range g.resolve(dep) {
+			pkg := g.packages[i]
+			for _, extra := range dep.Extra {
+				if k := (extraKey{i, extra}); !seenExtras[k] {
+					seenExtras[k] = true
+					queue = append(queue, pkg.OptionalDependencies[extra]...)
+				}
+			}
+			if seen[i] {
+				continue
+			}
+			seen[i] = true
+			visit(i)
+			queue = append(queue, pkg.Dependencies...)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package uvlock_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/osv"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/python/uvlock"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "invalid toml",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/not-toml.txt",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "could not extract from"},
+		},
+		{
+			Name: "source git",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/source-git.lock",
+			},
+			WantInventory: []*extractor.Inventory{
+				{
+					Name:      "ruff",
+					Version:   "0.8.1",
+					Locations: []string{"testdata/source-git.lock"},
+					SourceCode: &extractor.SourceCodeIdentifier{
+						Commit: "84748be16341b76e073d117329f7f5f4ee2941ad",
+					},
+					Metadata: osv.DepGroupMetadata{DepGroupVals: []string{}},
+				},
+			},
+		},
+		{
+			Name: "groups and sources",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/groups-and-sources.lock",
+			},
+			WantInventory: []*extractor.Inventory{
+				uvPackage("certifi", "2024.8.30"),
+				uvPackage("click", "8.1.7", "cli"),
+				uvPackage("iniconfig", "2.0.0", "dev"),
+				uvPackage("pysocks", "1.7.1"),
+				uvPackage("pytest", "8.3.3", "dev"),
+				uvPackage("requests", "2.32.3"),
+				uvPackage("ruff", "0.8.1", "dev"),
+				{
+					Name:      "tomli",
+					Version:   "2.0.2",
+					Locations: []string{"testdata/groups-and-sources.lock"},
+					SourceCode: &extractor.SourceCodeIdentifier{
+						Commit: "3ec6775a77c3e7a3c0b4ac6d4d969c3bd7223b13",
+					},
+					Metadata: osv.DepGroupMetadata{DepGroupVals: []string{}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := uvlock.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got, cmpopts.SortSlices(extracttest.InventoryCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
+
+func uvPackage(name, version string, groups ...string) *extractor.Inventory {
+	if groups == nil {
+		groups = []string{}
+	}
+
+	return &extractor.Inventory{
+		Name:      name,
+		Version:   version,
+		Locations: []string{"testdata/groups-and-sources.lock"},
+		Metadata:  osv.DepGroupMetadata{DepGroupVals: groups},
+	}
+}
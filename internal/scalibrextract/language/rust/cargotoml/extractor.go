@@ -0,0 +1,330 @@
+// Package cargotoml extracts the dependencies declared in Cargo.toml manifests,
+// for crates (such as libraries) that do not have a Cargo.lock.
+package cargotoml
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/rust/cargotoml"
+	"github.com/google/osv-scalibr/extractor/filesystem/osv"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+)
+
+// Name is the unique name of this extractor.
+const Name = cargotoml.Name
+
+var shaPattern = cachedregexp.MustCompile("^[0-9a-f]{40}$")
+
+// Metadata holds the requirement that a crate was declared with, along with its dependency groups.
+type Metadata struct {
+	// Requirement is the version requirement of the crate (e.g. "^1.2"),
+	// which the lowest satisfying version is reported from
+	Requirement  string
+	DepGroupVals []string
+}
+
+var _ osv.DepGroups = Metadata{}
+
+// DepGroups returns the dependency groups that the crate belongs to.
+func (m Metadata) DepGroups() []string {
+	return m.DepGroupVals
+}
+
+type cargoTomlDependency struct {
+	Version   string
+	Git       string
+	Rev       string
+	Package   string
+	Workspace bool
+}
+
+// UnmarshalTOML parses a dependency from a Cargo.toml file, which can either be
+// a version requirement string or a table (e.g. `{ version = "1.0", features = [...] }`)
+func (d *cargoTomlDependency) UnmarshalTOML(data any) error {
+	switch data := data.(type) {
+	case string:
+		d.Version = data
+		return nil
+	case map[string]any:
+		for key, dst := range map[string]*string{"version": &d.Version, "git": &d.Git, "rev": &d.Rev, "package": &d.Package} {
+			if v, ok := data[key]; ok {
+				s, ok := v.(string)
+				if !ok {
+					return fmt.Errorf("invalid type for key %q: expected string, got %T", key, v)
+				}
+				*dst = s
+			}
+		}
+		if v, ok := data["workspace"]; ok {
+			b, ok := v.(bool)
+			if !ok {
+				return fmt.Errorf("invalid type for key %q: expected bool, got %T", "workspace", v)
+			}
+			d.Workspace = b
+		}
+
+		return nil
+	default:
+		return errors.New("invalid format for Cargo.toml dependency")
+	}
+}
+
+type cargoTomlDependencies struct {
+	Dependencies      map[string]cargoTomlDependency `toml:"dependencies"`
+	DevDependencies   map[string]cargoTomlDependency `toml:"dev-dependencies"`
+	BuildDependencies map[string]cargoTomlDependency `toml:"build-dependencies"`
+}
+
+type cargoTomlWorkspace struct {
+	Dependencies map[string]cargoTomlDependency `toml:"dependencies"`
+}
+
+type cargoTomlFile struct {
+	cargoTomlDependencies
+	Target    map[string]cargoTomlDependencies `toml:"target"`
+	Workspace *cargoTomlWorkspace              `toml:"workspace"`
+}
+
+// Extractor extracts crates.io packages from Cargo.toml manifests.
+//
+// As the versions in a manifest are requirements rather than the exact version
+// that is used, the lowest version satisfying each requirement is reported.
+// Manifests that have a Cargo.lock (either beside them or at the root of their
+// workspace) are skipped, as the lockfile is the more accurate source.
+type Extractor struct {
+	actualExtractor cargotoml.Extractor
+}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for Cargo.toml files
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	return e.actualExtractor.FileRequired(fapi)
+}
+
+// Extract extracts packages from Cargo.toml files passed through the scan input.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	var manifest cargoTomlFile
+	if _, err := toml.NewDecoder(input.Reader).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	dir := path.Dir(filepath.ToSlash(input.Path))
+	workspaceDir, workspace := dir, manifest.Workspace
+	if workspace == nil {
+		var err error
+		workspaceDir, workspace, err = findWorkspace(input.FS, dir)
+		if err != nil {
+			return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+		}
+	}
+
+	if hasLockfile(input.FS, dir) || (workspace != nil && hasLockfile(input.FS, workspaceDir)) {
+		return []*extractor.Inventory{}, nil
+	}
+
+	var packages []*extractor.Inventory
+	add := func(deps map[string]cargoTomlDependency, group string) {
+		for name, dep := range deps {
+			if dep.Workspace && workspace != nil {
+				if wsDep, ok := workspace.Dependencies[name]; ok {
+					dep = wsDep
+				}
+			}
+			if inv := toInventory(name, dep, input.Path, group); inv != nil {
+				packages = append(packages, inv)
+			}
+		}
+	}
+
+	tables := []cargoTomlDependencies{manifest.cargoTomlDependencies}
+	for _, target := range manifest.Target {
+		tables = append(tables, target)
+	}
+	for _, t := range tables {
+		add(t.Dependencies, "")
+		add(t.DevDependencies, "dev")
+		add(t.BuildDependencies, "build")
+	}
+
+	return mergeGroups(packages), nil
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(i *extractor.Inventory) *purl.PackageURL {
+	return e.actualExtractor.ToPURL(i)
+}
+
+// Ecosystem returns the OSV ecosystem ('crates.io') of the software extracted by this extractor.
+func (e Extractor) Ecosystem(i *extractor.Inventory) string {
+	return e.actualExtractor.Ecosystem(i)
+}
+
+func toInventory(name string, dep cargoTomlDependency, location string, group string) *extractor.Inventory {
+	if dep.Package != "" {
+		name = dep.Package
+	}
+
+	var srcCode *extractor.SourceCodeIdentifier
+	if dep.Git != "" && shaPattern.MatchString(dep.Rev) {
+		srcCode = &extractor.SourceCodeIdentifier{
+			Repo:   dep.Git,
+			Commit: dep.Rev,
+		}
+	}
+
+	version := lowestVersion(dep.Version)
+
+	// Skip dependencies (e.g. path dependencies) that have nothing that can be matched against
+	if version == "" && srcCode == nil {
+		return nil
+	}
+
+	groups := []string{}
+	if group != "" {
+		groups = append(groups, group)
+	}
+
+	return &extractor.Inventory{
+		Name:       name,
+		Version:    version,
+		Locations:  []string{location},
+		SourceCode: srcCode,
+		Metadata:   Metadata{Requirement: dep.Version, DepGroupVals: groups},
+	}
+}
+
+// mergeGroups combines packages that are depended on in multiple tables (e.g. as both
+// a regular and a dev dependency), with a regular dependency taking precedence over all groups.
+func mergeGroups(packages []*extractor.Inventory) []*extractor.Inventory {
+	type pkgKey struct{ name, version string }
+	merged := make(map[pkgKey]*extractor.Inventory)
+	result := make([]*extractor.Inventory, 0, len(packages))
+	for _, pkg := range packages {
+		key := pkgKey{pkg.Name, pkg.Version}
+		existing, ok := merged[key]
+		if !ok {
+			merged[key] = pkg
+			result = append(result, pkg)
+
+			continue
+		}
+
+		existingMetadata := existing.Metadata.(Metadata)
+		existingGroups := existingMetadata.DepGroupVals
+		groups := pkg.Metadata.(Metadata).DepGroupVals
+		switch {
+		case len(existingGroups) == 0:
+		case len(groups) == 0:
+			existingGroups = []string{}
+		default:
+			for _, g := range groups {
+				if !slices.Contains(existingGroups, g) {
+					existingGroups = append(existingGroups, g)
+				}
+			}
+		}
+		existingMetadata.DepGroupVals = existingGroups
+		existing.Metadata = existingMetadata
+	}
+
+	return result
+}
+
+// lowestVersion returns the lowest version that satisfies a Cargo version requirement,
+// padded to a full "major.minor.patch" version, or an empty string if there is no such
+// version that can be reasonably determined (e.g. for "*" or upper-bound only requirements).
+func lowestVersion(req string) string {
+	for _, comparator := range strings.Split(req, ",") {
+		comparator = strings.TrimSpace(comparator)
+		if strings.HasPrefix(comparator, "<") || (strings.HasPrefix(comparator, ">") && !strings.HasPrefix(comparator, ">=")) {
+			continue
+		}
+		v := strings.TrimSpace(strings.TrimLeft(comparator, "^~=>"))
+		if v == "" || v == "*" {
+			continue
+		}
+
+		parts := strings.Split(v, ".")
+		for i, p := range parts {
+			if p == "*" || p == "x" || p == "X" {
+				parts = parts[:i]
+				break
+			}
+		}
+		if len(parts) == 0 {
+			continue
+		}
+		for len(parts) < 3 {
+			parts = append(parts, "0")
+		}
+
+		return strings.Join(parts, ".")
+	}
+
+	return ""
+}
+
+// findWorkspace looks for the workspace that the manifest in dir is a member of,
+// returning the directory of the workspace root and its definition, if one is found.
+func findWorkspace(fsys fs.FS, dir string) (string, *cargoTomlWorkspace, error) {
+	if fsys == nil {
+		return "", nil, nil
+	}
+
+	for dir != "." && dir != "/" {
+		dir = path.Dir(dir)
+
+		f, err := fsys.Open(path.Join(dir, "Cargo.toml"))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", nil, err
+		}
+
+		var manifest cargoTomlFile
+		_, err = toml.NewDecoder(f).Decode(&manifest)
+		f.Close()
+		if err != nil {
+			return "", nil, fmt.Errorf("could not parse workspace manifest %s: %w", path.Join(dir, "Cargo.toml"), err)
+		}
+		if manifest.Workspace != nil {
+			return dir, manifest.Workspace, nil
+		}
+	}
+
+	return "", nil, nil
+}
+
+func hasLockfile(fsys fs.FS, dir string) bool {
+	if fsys == nil {
+		return false
+	}
+	_, err := fs.Stat(fsys, path.Join(dir, "Cargo.lock"))
+
+	return err == nil
+}
@@ -0,0 +1,112 @@
+package cargotoml_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/rust/cargotoml"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "invalid toml",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/not-toml.txt",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "could not extract from"},
+		},
+		{
+			Name: "dependency tables",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/dependency-tables.toml",
+			},
+			WantInventory: []*extractor.Inventory{
+				cargoPackage("serde", "1.0.195", "1.0.195", "testdata/dependency-tables.toml"),
+				cargoPackage("regex", "~1.10", "1.10.0", "testdata/dependency-tables.toml"),
+				cargoPackage("tokio", "1", "1.0.0", "testdata/dependency-tables.toml"),
+				cargoPackage("rand", "^0.8.5", "0.8.5", "testdata/dependency-tables.toml"),
+				{
+					Name:      "git-crate",
+					Version:   "",
+					Locations: []string{"testdata/dependency-tables.toml"},
+					SourceCode: &extractor.SourceCodeIdentifier{
+						Repo:   "https://github.com/example/git-crate",
+						Commit: "b0b2d46e3a7ad8a4a9cda41c32a4b3c4a32c6f6d",
+					},
+					Metadata: cargotoml.Metadata{DepGroupVals: []string{}},
+				},
+				cargoPackage("pinned", "=2.3.4", "2.3.4", "testdata/dependency-tables.toml"),
+				cargoPackage("bounded", ">=1.2, <2", "1.2.0", "testdata/dependency-tables.toml"),
+				cargoPackage("criterion", "0.5", "0.5.0", "testdata/dependency-tables.toml", "dev"),
+				cargoPackage("cc", "1.0.83", "1.0.83", "testdata/dependency-tables.toml", "build"),
+				cargoPackage("winapi", "0.3.9", "0.3.9", "testdata/dependency-tables.toml"),
+			},
+		},
+		{
+			Name: "workspace member",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/workspace/member/Cargo.toml",
+			},
+			WantInventory: []*extractor.Inventory{
+				cargoPackage("anyhow", "1.0.79", "1.0.79", "testdata/workspace/member/Cargo.toml"),
+				cargoPackage("thiserror", "1.0.56", "1.0.56", "testdata/workspace/member/Cargo.toml"),
+				cargoPackage("itoa", "1.0.10", "1.0.10", "testdata/workspace/member/Cargo.toml"),
+			},
+		},
+		{
+			Name: "workspace root",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/workspace/Cargo.toml",
+			},
+			WantInventory: []*extractor.Inventory{},
+		},
+		{
+			Name: "workspace with lockfile",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/workspace-locked/member/Cargo.toml",
+			},
+			WantInventory: []*extractor.Inventory{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := cargotoml.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got, cmpopts.SortSlices(extracttest.InventoryCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
+
+func cargoPackage(name, requirement, version, location string, groups ...string) *extractor.Inventory {
+	if groups == nil {
+		groups = []string{}
+	}
+
+	return &extractor.Inventory{
+		Name:      name,
+		Version:   version,
+		Locations: []string{location},
+		Metadata:  cargotoml.Metadata{Requirement: requirement, DepGroupVals: groups},
+	}
+}
@@ -0,0 +1,186 @@
+// Package packageresolved extracts the pinned packages of Swift Package Manager Package.resolved files.
+package packageresolved
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// Name is the unique name of this extractor.
+const Name = "swift/packageresolved"
+
+type packageResolvedState struct {
+	Branch   string `json:"branch"`
+	Revision string `json:"revision"`
+	Version  string `json:"version"`
+}
+
+type packageResolvedPin struct {
+	// Identity, Kind, and Location are used by version 2 and 3 of the format
+	Identity string `json:"identity"`
+	Kind     string `json:"kind"`
+	Location string `json:"location"`
+
+	// Package and RepositoryURL are used by version 1 of the format
+	Package       string `json:"package"`
+	RepositoryURL string `json:"repositoryURL"`
+
+	State packageResolvedState `json:"state"`
+}
+
+type packageResolvedFile struct {
+	Version int `json:"version"`
+
+	// Pins are at the top level from version 2 of the format
+	Pins []packageResolvedPin `json:"pins"`
+
+	// Object has the pins in version 1 of the format
+	Object *struct {
+		Pins []packageResolvedPin `json:"pins"`
+	} `json:"object"`
+}
+
+// Extractor extracts the packages that a Package.resolved file pins, which are scanned as
+// SwiftURL packages named after the url of their repository.
+//
+// Packages that are pinned to a branch or a commit rather than to a version are scanned by
+// the commit that they are resolved to instead, while packages from registries and local
+// repositories are skipped as they cannot be matched against either.
+type Extractor struct{}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for Package.resolved files
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	return filepath.Base(fapi.Path()) == "Package.resolved"
+}
+
+// Extract extracts the pinned packages of Package.resolved files passed through the scan input.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	var resolved packageResolvedFile
+	if err := json.NewDecoder(input.Reader).Decode(&resolved); err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	pins := resolved.Pins
+	if resolved.Object != nil {
+		pins = append(pins, resolved.Object.Pins...)
+	}
+
+	packages := make([]*extractor.Inventory, 0, len(pins))
+	for _, pin := range pins {
+		location := pin.Location
+		if location == "" {
+			location = pin.RepositoryURL
+		}
+
+		// only packages from git repositories can be matched, which
+		// are the only kind of package in version 1 of the format
+		if pin.Kind != "" && pin.Kind != "remoteSourceControl" {
+			continue
+		}
+
+		name := packageName(location)
+		if name == "" {
+			continue
+		}
+
+		inv := &extractor.Inventory{
+			Name:      name,
+			Version:   pin.State.Version,
+			Locations: []string{input.Path},
+		}
+
+		if inv.Version == "" {
+			if pin.State.Revision == "" {
+				continue
+			}
+
+			inv.SourceCode = &extractor.SourceCodeIdentifier{
+				Repo:   location,
+				Commit: pin.State.Revision,
+			}
+		}
+
+		packages = append(packages, inv)
+	}
+
+	return packages, nil
+}
+
+// packageName gets the name of the SwiftURL package of the git repository at the
+// location, which is its url without the scheme and the ".git" suffix, returning
+// an empty string if the location is not a url.
+//
+// Both urls (e.g. "https://github.com/apple/swift-nio.git") and scp-like
+// addresses (e.g. "git@github.com:apple/swift-nio.git") are supported.
+func packageName(location string) string {
+	var name string
+	if _, rest, ok := strings.Cut(location, "://"); ok {
+		name = rest
+	} else if user, rest, ok := strings.Cut(location, "@"); ok && !strings.Contains(user, "/") {
+		// scp-like addresses separate the host from the path with a colon
+		name = strings.Replace(rest, ":", "/", 1)
+	} else {
+		return ""
+	}
+
+	// drop any user info, such as the "git@" of ssh urls
+	if host, rest, ok := strings.Cut(name, "/"); ok {
+		if i := strings.LastIndex(host, "@"); i >= 0 {
+			name = host[i+1:] + "/" + rest
+		}
+	}
+
+	name = strings.TrimSuffix(strings.TrimSuffix(name, "/"), ".git")
+	if !strings.Contains(name, "/") {
+		return ""
+	}
+
+	return name
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(inv *extractor.Inventory) *purl.PackageURL {
+	if inv.Version == "" {
+		return nil
+	}
+
+	return &purl.PackageURL{
+		Type:      purl.TypeSwift,
+		Namespace: path.Dir(inv.Name),
+		Name:      path.Base(inv.Name),
+		Version:   inv.Version,
+	}
+}
+
+// Ecosystem returns the OSV ecosystem of the inventory, which is empty for
+// packages that are pinned to a commit as they are matched by their commit
+func (e Extractor) Ecosystem(inv *extractor.Inventory) string {
+	if inv.Version == "" {
+		return ""
+	}
+
+	return string(osvschema.EcosystemSwiftURL)
+}
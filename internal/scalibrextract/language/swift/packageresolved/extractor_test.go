@@ -0,0 +1,120 @@
+package packageresolved_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/swift/packageresolved"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "invalid json",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/not-json.txt",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "could not extract from"},
+		},
+		{
+			Name: "version 1",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/v1/Package.resolved",
+			},
+			WantInventory: []*extractor.Inventory{
+				versionPin("github.com/Alamofire/Alamofire", "5.6.1", "testdata/v1/Package.resolved"),
+				versionPin("github.com/SnapKit/SnapKit", "5.6.0", "testdata/v1/Package.resolved"),
+				commitPin(
+					"github.com/SwiftyBeaver/SwiftyBeaver",
+					"https://github.com/SwiftyBeaver/SwiftyBeaver",
+					"12b5acf96d98f91d50de447369bd18df74600f1a",
+					"testdata/v1/Package.resolved",
+				),
+			},
+		},
+		{
+			Name: "version 2",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/v2/Package.resolved",
+			},
+			WantInventory: []*extractor.Inventory{
+				versionPin("github.com/apple/swift-log", "1.5.2", "testdata/v2/Package.resolved"),
+				versionPin("github.com/apple/swift-nio", "2.54.0", "testdata/v2/Package.resolved"),
+				commitPin(
+					"github.com/vapor/vapor",
+					"https://github.com/vapor/vapor",
+					"d8141a2ff0cbc2ad6919f4e8e8fe6d9b31c8ab7a",
+					"testdata/v2/Package.resolved",
+				),
+			},
+		},
+		{
+			Name: "version 3, with local and registry packages",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/v3/Package.resolved",
+			},
+			WantInventory: []*extractor.Inventory{
+				versionPin("github.com/apple/swift-argument-parser", "1.3.1", "testdata/v3/Package.resolved"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := packageresolved.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got, cmpopts.SortSlices(extracttest.InventoryCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
+
+func TestExtractor_Ecosystem(t *testing.T) {
+	t.Parallel()
+
+	extr := packageresolved.Extractor{}
+
+	if got := extr.Ecosystem(versionPin("github.com/apple/swift-nio", "2.54.0", "")); got != "SwiftURL" {
+		t.Errorf("Ecosystem() = %q, want SwiftURL for packages pinned to a version", got)
+	}
+	if got := extr.Ecosystem(commitPin("github.com/vapor/vapor", "https://github.com/vapor/vapor", "d8141a2", "")); got != "" {
+		t.Errorf("Ecosystem() = %q, want no ecosystem for packages pinned to a commit", got)
+	}
+}
+
+func versionPin(name, version, location string) *extractor.Inventory {
+	return &extractor.Inventory{
+		Name:      name,
+		Version:   version,
+		Locations: []string{location},
+	}
+}
+
+func commitPin(name, repo, commit, location string) *extractor.Inventory {
+	return &extractor.Inventory{
+		Name: name,
+		SourceCode: &extractor.SourceCodeIdentifier{
+			Repo:   repo,
+			Commit: commit,
+		},
+		Locations: []string{location},
+	}
+}
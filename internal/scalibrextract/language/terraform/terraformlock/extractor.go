@@ -0,0 +1,177 @@
+// Package terraformlock extracts the provider versions locked by Terraform .terraform.lock.hcl files.
+package terraformlock
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// Name is the unique name of this extractor.
+const Name = "terraform/terraformlock"
+
+var (
+	reProviderBlock = cachedregexp.MustCompile(`^provider\s+"([^"]+)"\s*\{$`)
+	reVersion       = cachedregexp.MustCompile(`^version\s*=\s*"([^"]*)"$`)
+)
+
+// publicRegistries are the hostnames of the public provider registries, whose providers
+// must be published from a GitHub repository named terraform-provider-<type>
+var publicRegistries = map[string]bool{
+	"registry.terraform.io": true,
+	"registry.opentofu.org": true,
+}
+
+// Metadata holds the address of the provider that a package was extracted from.
+type Metadata struct {
+	// Address is the source address of the provider (e.g. "registry.terraform.io/hashicorp/aws")
+	Address string
+	// Public is true if the provider is from a public registry, meaning that it is the Go module
+	// of the repository that the provider is published from
+	Public bool
+}
+
+// Extractor extracts the versions of the providers locked by .terraform.lock.hcl files.
+//
+// Providers are built from Go modules, so those from the public registries are reported as the Go module
+// of the repository that they are published from (e.g. github.com/hashicorp/terraform-provider-aws for
+// registry.terraform.io/hashicorp/aws), as this is what advisories for providers are published for.
+// Providers from other registries do not have a known source, so they do not have an ecosystem.
+type Extractor struct{}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for .terraform.lock.hcl files
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	return filepath.Base(fapi.Path()) == ".terraform.lock.hcl"
+}
+
+// Extract extracts the locked providers of .terraform.lock.hcl files passed through the scan input.
+//
+// Lock files are always generated by Terraform (or OpenTofu) in the same format, so they are parsed
+// line by line rather than as arbitrary HCL, with the hashes of each provider being skipped.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	var packages []*extractor.Inventory
+
+	var provider *extractor.Inventory
+	providerLine := 0
+	depth := 0
+	lineNumber := 0
+
+	s := bufio.NewScanner(input.Reader)
+	for s.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(s.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		if depth == 0 {
+			match := reProviderBlock.FindStringSubmatch(line)
+			if match == nil {
+				return nil, fmt.Errorf("could not extract from %s: line %d: expected a provider block", input.Path, lineNumber)
+			}
+
+			provider = toInventory(match[1], input.Path)
+			providerLine = lineNumber
+			depth = 1
+
+			continue
+		}
+
+		// only the arguments of the provider itself are needed, rather than those
+		// within nested structures such as the list of hashes
+		if match := reVersion.FindStringSubmatch(line); depth == 1 && match != nil {
+			provider.Version = match[1]
+		}
+
+		depth += strings.Count(line, "{") + strings.Count(line, "[")
+		depth -= strings.Count(line, "}") + strings.Count(line, "]")
+
+		if depth == 0 {
+			if provider.Version != "" {
+				packages = append(packages, provider)
+			}
+			provider = nil
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	if provider != nil {
+		return nil, fmt.Errorf("could not extract from %s: line %d: the provider block is not closed", input.Path, providerLine)
+	}
+
+	return packages, nil
+}
+
+// toInventory creates the package of the provider with the given address, which is
+// the Go module of the provider if its repository is known
+func toInventory(address string, location string) *extractor.Inventory {
+	metadata := &Metadata{Address: address}
+	name := address
+
+	parts := strings.Split(address, "/")
+	// addresses without a hostname are from the default registry
+	if len(parts) == 2 {
+		parts = append([]string{"registry.terraform.io"}, parts...)
+	}
+
+	if len(parts) == 3 && publicRegistries[strings.ToLower(parts[0])] {
+		metadata.Public = true
+		name = path.Join("github.com", parts[1], "terraform-provider-"+parts[2])
+	}
+
+	return &extractor.Inventory{
+		Name:      name,
+		Locations: []string{location},
+		Metadata:  metadata,
+	}
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(i *extractor.Inventory) *purl.PackageURL {
+	if m, ok := i.Metadata.(*Metadata); !ok || !m.Public {
+		return nil
+	}
+
+	return &purl.PackageURL{
+		Type:    purl.TypeGolang,
+		Name:    i.Name,
+		Version: i.Version,
+	}
+}
+
+// Ecosystem returns the OSV ecosystem ('Go') of the providers from the public registries,
+// or an empty string for those from other registries
+func (e Extractor) Ecosystem(i *extractor.Inventory) string {
+	if m, ok := i.Metadata.(*Metadata); ok && m.Public {
+		return string(osvschema.EcosystemGo)
+	}
+
+	return ""
+}
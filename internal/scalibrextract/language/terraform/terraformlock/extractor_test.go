@@ -0,0 +1,106 @@
+package terraformlock_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/terraform/terraformlock"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "not hcl",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/not-hcl.txt",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "line 1: expected a provider block"},
+		},
+		{
+			Name: "unterminated provider block",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/unterminated.lock.hcl",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "line 4: the provider block is not closed"},
+		},
+		{
+			Name: "no providers",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/empty.lock.hcl",
+			},
+			WantInventory: nil,
+		},
+		{
+			Name: "providers",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/.terraform.lock.hcl",
+			},
+			WantInventory: []*extractor.Inventory{
+				provider("github.com/hashicorp/terraform-provider-aws", "5.31.0", "registry.terraform.io/hashicorp/aws", true),
+				provider("github.com/integrations/terraform-provider-github", "6.0.0", "registry.opentofu.org/integrations/github", true),
+				provider("github.com/mongey/terraform-provider-kafka", "0.7.1", "registry.terraform.io/mongey/kafka", true),
+				provider("terraform.example.com/platform/internal", "1.2.3", "terraform.example.com/platform/internal", false),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := terraformlock.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got, cmpopts.SortSlices(extracttest.InventoryCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
+
+func TestExtractor_Ecosystem(t *testing.T) {
+	t.Parallel()
+
+	extr := terraformlock.Extractor{}
+
+	public := provider("github.com/hashicorp/terraform-provider-aws", "5.31.0", "registry.terraform.io/hashicorp/aws", true)
+	if got := extr.Ecosystem(public); got != "Go" {
+		t.Errorf("Ecosystem() = %q, want %q", got, "Go")
+	}
+	want := &purl.PackageURL{Type: purl.TypeGolang, Name: "github.com/hashicorp/terraform-provider-aws", Version: "5.31.0"}
+	if diff := cmp.Diff(want, extr.ToPURL(public)); diff != "" {
+		t.Errorf("ToPURL() diff (-want +got):\n%s", diff)
+	}
+
+	private := provider("terraform.example.com/platform/internal", "1.2.3", "terraform.example.com/platform/internal", false)
+	if got := extr.Ecosystem(private); got != "" {
+		t.Errorf("Ecosystem() = %q, want an empty ecosystem", got)
+	}
+	if got := extr.ToPURL(private); got != nil {
+		t.Errorf("ToPURL() = %v, want nil", got)
+	}
+}
+
+func provider(name string, version string, address string, public bool) *extractor.Inventory {
+	return &extractor.Inventory{
+		Name:      name,
+		Version:   version,
+		Locations: []string{"testdata/.terraform.lock.hcl"},
+		Metadata:  &terraformlock.Metadata{Address: address, Public: public},
+	}
+}
@@ -0,0 +1,31 @@
+# This file is maintained automatically by "terraform init".
+# Manual edits may be lost in future updates.
+
+provider "registry.terraform.io/hashicorp/aws" {
+  version     = "5.31.0"
+  constraints = ">= 4.0.0, ~> 5.31"
+  hashes = [
+    "h1:ltxyuBWIy9cq0kIKDJH1jeWJy/y7XJLjS4QrsQK4plA=",
+    "zh:0cdb9c2083bf0902442384f7309367791e4640581652dda456f2d6d7abf0de8d",
+    "zh:13d8a4c1f1e2d1b4e7d5f3c7b1f1a6b1d3e2a8d1c6f3e2b1a4d5c6e7f8a9b0c1",
+  ]
+}
+
+provider "registry.opentofu.org/integrations/github" {
+  version = "6.0.0"
+  hashes = [
+    "h1:3FHe1QJ0j3o1cGD2JM4Qm7AncUM2ZDLaXwbxhnSD6kU=",
+  ]
+}
+
+provider "registry.terraform.io/mongey/kafka" {
+  version = "0.7.1"
+}
+
+provider "terraform.example.com/platform/internal" {
+  version     = "1.2.3"
+  constraints = "1.2.3"
+  hashes = [
+    "h1:c7Dk0Nw5D8Jvnl6hFTrg3wWjoSG3cGxNeSPBLkkSOno=",
+  ]
+}
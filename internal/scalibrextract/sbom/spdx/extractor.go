@@ -0,0 +1,267 @@
+// Package spdx extracts software dependencies from SPDX SBOMs, including SPDX 3.0 JSON-LD documents.
+package spdx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	scalibrspdx "github.com/google/osv-scalibr/extractor/filesystem/sbom/spdx"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+)
+
+// Name is the unique name of this extractor.
+const Name = scalibrspdx.Name
+
+// Metadata holds the information of a package extracted from an SPDX 3.0 document.
+type Metadata struct {
+	PURL *purl.PackageURL
+	CPEs []string
+	// IsTransitive is true if the package is only depended on through other packages
+	IsTransitive bool
+}
+
+// spdx3JSONExtensions are the extensions used by SPDX 3.0 JSON-LD documents,
+// with `.spdx.json` also being used by SPDX 2 JSON documents
+var spdx3JSONExtensions = []string{".spdx.json", ".spdx3.json", ".spdx.jsonld"}
+
+type spdx3Document struct {
+	Context json.RawMessage `json:"@context"`
+	Graph   []spdx3Element  `json:"@graph"`
+}
+
+type spdx3ExternalIdentifier struct {
+	Type       string `json:"externalIdentifierType"`
+	Identifier string `json:"identifier"`
+}
+
+type spdx3Element struct {
+	Type               string                    `json:"type"`
+	SpdxID             string                    `json:"spdxId"`
+	Name               string                    `json:"name"`
+	PackageVersion     string                    `json:"software_packageVersion"`
+	PackageURL         string                    `json:"software_packageUrl"`
+	ExternalIdentifier []spdx3ExternalIdentifier `json:"externalIdentifier"`
+	RootElement        []string                  `json:"rootElement"`
+	From               string                    `json:"from"`
+	To                 []string                  `json:"to"`
+	RelationshipType   string                    `json:"relationshipType"`
+}
+
+// Extractor extracts software dependencies from SPDX SBOMs.
+//
+// SPDX 3.0 JSON-LD documents are a graph of elements rather than a list of packages,
+// so these are extracted by following the relationships from the root elements of the
+// document to determine which packages are direct dependencies. All other SPDX
+// documents are extracted by the osv-scalibr SPDX extractor.
+type Extractor struct{}
+
+var _ filesystem.Extractor = Extractor{}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for SPDX SBOMs in any of the supported formats.
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	return isSPDX3JSONPath(fapi.Path()) || scalibrspdx.Extractor{}.FileRequired(fapi)
+}
+
+// Extract extracts packages from SPDX SBOMs passed through the scan input.
+func (e Extractor) Extract(ctx context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	if !isSPDX3JSONPath(input.Path) {
+		return scalibrspdx.Extractor{}.Extract(ctx, input)
+	}
+
+	data, err := io.ReadAll(input.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	var doc spdx3Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	// SPDX 2 documents do not have a JSON-LD context
+	if len(doc.Context) == 0 {
+		if !strings.HasSuffix(strings.ToLower(input.Path), ".spdx.json") {
+			return nil, fmt.Errorf("could not extract from %s: not an SPDX 3.0 JSON-LD document", input.Path)
+		}
+
+		spdx2Input := *input
+		spdx2Input.Reader = bytes.NewReader(data)
+
+		return scalibrspdx.Extractor{}.Extract(ctx, &spdx2Input)
+	}
+
+	return extractSPDX3(doc, input.Path), nil
+}
+
+func extractSPDX3(doc spdx3Document, path string) []*extractor.Inventory {
+	var roots []string
+	edges := make(map[string][]string)
+	for _, el := range doc.Graph {
+		switch el.Type {
+		case "software_Sbom", "SpdxDocument":
+			roots = append(roots, el.RootElement...)
+		case "Relationship":
+			if isDependencyRelationship(el.RelationshipType) {
+				edges[el.From] = append(edges[el.From], el.To...)
+			}
+		}
+	}
+
+	depths := elementDepths(roots, edges)
+
+	var result []*extractor.Inventory
+	for _, el := range doc.Graph {
+		if el.Type != "software_Package" {
+			continue
+		}
+
+		metadata := &Metadata{}
+		if el.PackageURL != "" {
+			metadata.PURL = parsePURL(el.PackageURL)
+		}
+		for _, id := range el.ExternalIdentifier {
+			switch id.Type {
+			case "packageUrl":
+				if metadata.PURL == nil {
+					metadata.PURL = parsePURL(id.Identifier)
+				}
+			case "cpe23", "cpe22":
+				metadata.CPEs = append(metadata.CPEs, id.Identifier)
+			}
+		}
+
+		// packages can only be matched against vulnerabilities by their PURL or CPE
+		if metadata.PURL == nil && len(metadata.CPEs) == 0 {
+			continue
+		}
+
+		inv := &extractor.Inventory{
+			Name:      el.Name,
+			Version:   el.PackageVersion,
+			Locations: []string{path},
+			Metadata:  metadata,
+		}
+		if metadata.PURL != nil {
+			inv.Name = metadata.PURL.Name
+			if metadata.PURL.Version == "" {
+				metadata.PURL.Version = el.PackageVersion
+			}
+			inv.Version = metadata.PURL.Version
+		} else if inv.Name == "" {
+			inv.Name = metadata.CPEs[0]
+		}
+
+		// packages that cannot be reached from the roots of the document
+		// are assumed to be direct, as there is nothing to suggest otherwise
+		if depth, ok := depths[el.SpdxID]; ok {
+			metadata.IsTransitive = depth > 1
+		}
+
+		result = append(result, inv)
+	}
+
+	return result
+}
+
+// elementDepths returns the shortest number of relationships needed to reach
+// each element from the roots of the document, which are at a depth of 0
+func elementDepths(roots []string, edges map[string][]string) map[string]int {
+	depths := make(map[string]int)
+	queue := make([]string, 0, len(roots))
+	for _, root := range roots {
+		if _, ok := depths[root]; !ok {
+			depths[root] = 0
+			queue = append(queue, root)
+		}
+	}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		for _, to := range edges[id] {
+			if _, ok := depths[to]; ok {
+				continue
+			}
+			depths[to] = depths[id] + 1
+			queue = append(queue, to)
+		}
+	}
+
+	return depths
+}
+
+// isDependencyRelationship returns true for the relationship types that link a package
+// to what it is made up of, accepting both the SPDX 3.0 (e.g. "dependsOn") and SPDX 2
+// (e.g. "DEPENDS_ON") spellings
+func isDependencyRelationship(relationshipType string) bool {
+	switch strings.ToLower(strings.ReplaceAll(relationshipType, "_", "")) {
+	case "contains", "dependson":
+		return true
+	}
+
+	return false
+}
+
+func parsePURL(s string) *purl.PackageURL {
+	p, err := purl.FromString(s)
+	if err != nil {
+		return nil
+	}
+
+	return &p
+}
+
+func isSPDX3JSONPath(path string) bool {
+	path = strings.ToLower(filepath.ToSlash(path))
+	for _, ext := range spdx3JSONExtensions {
+		if strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ToPURL converts an inventory created by this extractor into a PURL.
+func (e Extractor) ToPURL(i *extractor.Inventory) *purl.PackageURL {
+	if m, ok := i.Metadata.(*Metadata); ok {
+		return m.PURL
+	}
+
+	return scalibrspdx.Extractor{}.ToPURL(i)
+}
+
+// Ecosystem returns the OSV Ecosystem of the software extracted by this extractor.
+func (e Extractor) Ecosystem(i *extractor.Inventory) string {
+	if m, ok := i.Metadata.(*Metadata); ok {
+		if m.PURL == nil {
+			return ""
+		}
+
+		// This is the same heuristic as the osv-scalibr extractor, as the actual
+		// ecosystem is determined from the PURL when converting the inventory
+		return m.PURL.Type
+	}
+
+	return scalibrspdx.Extractor{}.Ecosystem(i)
+}
@@ -0,0 +1,155 @@
+package spdx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	scalibrspdx "github.com/google/osv-scalibr/extractor/filesystem/sbom/spdx"
+	"github.com/google/osv-scalibr/extractor/filesystem/simplefileapi"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/sbom/spdx"
+)
+
+func TestExtractor_FileRequired(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "app.spdx.json", want: true},
+		{path: "path/to/app.spdx3.json", want: true},
+		{path: "app.spdx.jsonld", want: true},
+		{path: "app.SPDX.JSONLD", want: true},
+		{path: "app.spdx", want: true},
+		{path: "app.spdx.yml", want: true},
+		{path: "app.json", want: false},
+		{path: "app.jsonld", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			got := spdx.Extractor{}.FileRequired(simplefileapi.New(tt.path, nil))
+			if got != tt.want {
+				t.Errorf("FileRequired(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "invalid json",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/not-json.spdx3.json",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "could not extract from"},
+		},
+		{
+			Name: "not an spdx 3 document",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/not-spdx3.spdx.jsonld",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "not an SPDX 3.0 JSON-LD document"},
+		},
+		{
+			Name: "spdx 3 document",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/app.spdx.json",
+			},
+			WantInventory: []*extractor.Inventory{
+				{
+					Name:      "express",
+					Version:   "4.17.1",
+					Locations: []string{"testdata/app.spdx.json"},
+					Metadata: &spdx.Metadata{
+						PURL: &purl.PackageURL{Type: purl.TypeNPM, Name: "express", Version: "4.17.1"},
+					},
+				},
+				{
+					Name:      "qs",
+					Version:   "6.7.0",
+					Locations: []string{"testdata/app.spdx.json"},
+					Metadata: &spdx.Metadata{
+						PURL:         &purl.PackageURL{Type: purl.TypeNPM, Name: "qs", Version: "6.7.0"},
+						IsTransitive: true,
+					},
+				},
+				{
+					Name:      "openssl",
+					Version:   "3.0.7",
+					Locations: []string{"testdata/app.spdx.json"},
+					Metadata: &spdx.Metadata{
+						CPEs: []string{"cpe:2.3:a:openssl:openssl:3.0.7:*:*:*:*:*:*:*"},
+					},
+				},
+				{
+					Name:      "django",
+					Version:   "4.2.1",
+					Locations: []string{"testdata/app.spdx.json"},
+					Metadata: &spdx.Metadata{
+						PURL: &purl.PackageURL{Type: purl.TypePyPi, Name: "django", Version: "4.2.1"},
+					},
+				},
+			},
+		},
+		{
+			Name: "spdx 2 document",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/spdx2.spdx.json",
+			},
+			WantInventory: []*extractor.Inventory{
+				{
+					Name:      "lodash",
+					Locations: []string{"testdata/spdx2.spdx.json"},
+					Metadata: &scalibrspdx.Metadata{
+						PURL: &purl.PackageURL{Type: purl.TypeNPM, Name: "lodash", Version: "4.17.20"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := spdx.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(context.Background(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantInventory, got, cmpopts.SortSlices(extracttest.InventoryCmpLess), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
+
+func TestExtractor_ToPURL(t *testing.T) {
+	t.Parallel()
+
+	extr := spdx.Extractor{}
+	want := &purl.PackageURL{Type: purl.TypeNPM, Name: "qs", Version: "6.7.0"}
+
+	if got := extr.ToPURL(&extractor.Inventory{Metadata: &spdx.Metadata{PURL: want}}); got != want {
+		t.Errorf("ToPURL() of spdx 3 package = %v, want %v", got, want)
+	}
+	if got := extr.ToPURL(&extractor.Inventory{Metadata: &scalibrspdx.Metadata{PURL: want}}); got != want {
+		t.Errorf("ToPURL() of spdx 2 package = %v, want %v", got, want)
+	}
+}
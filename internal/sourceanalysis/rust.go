@@ -38,6 +38,12 @@ func rustAnalysis(pkgs []models.PackageVulns, source models.SourceInfo) {
 		return
 	}
 
+	rustBinaryAnalysis(pkgs, binaryPaths)
+}
+
+// rustBinaryAnalysis marks which of the vulnerabilities in pkgs are called by the given
+// rust binaries and libraries, based on the functions listed in their DWARF debug information
+func rustBinaryAnalysis(pkgs []models.PackageVulns, binaryPaths []string) {
 	// This map stores 3 states for each vuln ID
 	// - There is function level vuln info, but it **wasn't** called   (false)
 	// - There is function level vuln info, and it **is** called    (true)
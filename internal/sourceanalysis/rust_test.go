@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/osv-scanner/v2/internal/testutility"
 	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
 )
 
 func Test_extractRlibArchive(t *testing.T) {
@@ -104,3 +105,61 @@ func Test_rustBuildSource(t *testing.T) {
 		}
 	}
 }
+
+func Test_rustBinaryAnalysis(t *testing.T) {
+	t.Parallel()
+
+	affects := func(function string) []osvschema.Affected {
+		return []osvschema.Affected{{
+			EcosystemSpecific: map[string]any{
+				"affects": map[string]any{"functions": []any{function}},
+			},
+		}}
+	}
+
+	pkgs := []models.PackageVulns{
+		{
+			Package: models.PackageInfo{Name: "test-rust-2", Ecosystem: "crates.io"},
+			Vulnerabilities: []osvschema.Vulnerability{
+				{ID: "RUSTSEC-CALLED", Affected: affects("core::panicking::panic")},
+				{ID: "RUSTSEC-UNCALLED", Affected: affects("test_rust_2::never_called")},
+				{ID: "RUSTSEC-NO-FUNCTIONS"},
+			},
+			Groups: []models.GroupInfo{
+				{IDs: []string{"RUSTSEC-CALLED"}},
+				{IDs: []string{"RUSTSEC-UNCALLED"}},
+				{IDs: []string{"RUSTSEC-NO-FUNCTIONS"}},
+			},
+		},
+	}
+
+	rustBinaryAnalysis(pkgs, []string{"fixtures-rust/objs/test-rust-2"})
+
+	want := []map[string]models.AnalysisInfo{
+		{"RUSTSEC-CALLED": {Called: true}},
+		{"RUSTSEC-UNCALLED": {Called: false}},
+		{},
+	}
+	for i, group := range pkgs[0].Groups {
+		if !reflect.DeepEqual(group.ExperimentalAnalysis, want[i]) {
+			t.Errorf("group %v analysis = %v, want %v", group.IDs, group.ExperimentalAnalysis, want[i])
+		}
+	}
+}
+
+func Test_isRustBinary(t *testing.T) {
+	t.Parallel()
+
+	crate := models.PackageVulns{Package: models.PackageInfo{Name: "serde", Ecosystem: "crates.io"}}
+	goModule := models.PackageVulns{Package: models.PackageInfo{Name: "github.com/google/uuid", Ecosystem: "Go"}}
+
+	if !isRustBinary([]models.PackageVulns{crate, crate}) {
+		t.Errorf("isRustBinary() of crates = false, want true")
+	}
+	if isRustBinary([]models.PackageVulns{crate, goModule}) {
+		t.Errorf("isRustBinary() of a crate and Go module = true, want false")
+	}
+	if isRustBinary(nil) {
+		t.Errorf("isRustBinary() of no packages = true, want false")
+	}
+}
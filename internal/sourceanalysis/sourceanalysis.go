@@ -24,8 +24,11 @@ func vulnsFromAllPkgs(pkgs []models.PackageVulns) ([]osvschema.Vulnerability, ma
 	return vulns, flatVulns
 }
 
-// Run runs the language specific analyzers on the code given packages and source info
-func Run(source models.SourceInfo, pkgs []models.PackageVulns, callAnalysis map[string]bool) {
+// Run runs the language specific analyzers on the code given packages and source info.
+//
+// Binaries are only analyzed for source scans, as the paths of artifacts found within a
+// container image are not paths on disk.
+func Run(source models.SourceInfo, pkgs []models.PackageVulns, callAnalysis map[string]bool, sourceScan bool) {
 	// GoVulnCheck
 	if source.Type == "lockfile" && filepath.Base(source.Path) == "go.mod" && callAnalysis["go"] {
 		goAnalysis(pkgs, source)
@@ -34,4 +37,25 @@ func Run(source models.SourceInfo, pkgs []models.PackageVulns, callAnalysis map[
 	if source.Type == "lockfile" && filepath.Base(source.Path) == "Cargo.lock" && callAnalysis["rust"] {
 		rustAnalysis(pkgs, source)
 	}
+
+	// Rust binaries built with cargo-auditable can be analyzed directly when they include debug information
+	if sourceScan && source.Type == "artifact" && isRustBinary(pkgs) && callAnalysis["rust"] {
+		rustBinaryAnalysis(pkgs, []string{source.Path})
+	}
+}
+
+// isRustBinary returns true if the packages are all crates, as is the case for the
+// packages embedded in a cargo-auditable binary
+func isRustBinary(pkgs []models.PackageVulns) bool {
+	if len(pkgs) == 0 {
+		return false
+	}
+
+	for _, pv := range pkgs {
+		if pv.Package.Ecosystem != string(osvschema.EcosystemCratesIO) {
+			return false
+		}
+	}
+
+	return true
 }
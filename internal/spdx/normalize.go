@@ -0,0 +1,147 @@
+package spdx
+
+import (
+	"strings"
+
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+// aliases maps common (lowercased) variants of license names to their spdx identifier
+var aliases = map[string]models.License{
+	"apache 2":                           "Apache-2.0",
+	"apache 2.0":                         "Apache-2.0",
+	"apache license 2.0":                 "Apache-2.0",
+	"apache license, version 2.0":        "Apache-2.0",
+	"apache license version 2.0":         "Apache-2.0",
+	"apache software license 2.0":        "Apache-2.0",
+	"apache-2":                           "Apache-2.0",
+	"apache2":                            "Apache-2.0",
+	"apache2.0":                          "Apache-2.0",
+	"asl 2.0":                            "Apache-2.0",
+	"asl-2.0":                            "Apache-2.0",
+	"asl2":                               "Apache-2.0",
+	"mit license":                        "MIT",
+	"the mit license":                    "MIT",
+	"expat":                              "MIT",
+	"isc license":                        "ISC",
+	"bsd 2-clause":                       "BSD-2-Clause",
+	"bsd-2":                              "BSD-2-Clause",
+	"2-clause bsd":                       "BSD-2-Clause",
+	"simplified bsd":                     "BSD-2-Clause",
+	"bsd 3-clause":                       "BSD-3-Clause",
+	"bsd-3":                              "BSD-3-Clause",
+	"3-clause bsd":                       "BSD-3-Clause",
+	"new bsd":                            "BSD-3-Clause",
+	"modified bsd":                       "BSD-3-Clause",
+	"revised bsd":                        "BSD-3-Clause",
+	"gplv2":                              "GPL-2.0-only",
+	"gpl v2":                             "GPL-2.0-only",
+	"gpl-2":                              "GPL-2.0-only",
+	"gplv2+":                             "GPL-2.0-or-later",
+	"gplv2 or later":                     "GPL-2.0-or-later",
+	"gplv3":                              "GPL-3.0-only",
+	"gpl v3":                             "GPL-3.0-only",
+	"gpl-3":                              "GPL-3.0-only",
+	"gplv3+":                             "GPL-3.0-or-later",
+	"gplv3 or later":                     "GPL-3.0-or-later",
+	"lgplv2.1":                           "LGPL-2.1-only",
+	"lgplv2.1+":                          "LGPL-2.1-or-later",
+	"lgplv3":                             "LGPL-3.0-only",
+	"lgplv3+":                            "LGPL-3.0-or-later",
+	"mpl 2.0":                            "MPL-2.0",
+	"mpl2":                               "MPL-2.0",
+	"mozilla public license 2.0":         "MPL-2.0",
+	"epl 1.0":                            "EPL-1.0",
+	"eclipse public license 1.0":         "EPL-1.0",
+	"epl 2.0":                            "EPL-2.0",
+	"eclipse public license 2.0":         "EPL-2.0",
+	"boost software license 1.0":         "BSL-1.0",
+	"python software foundation license": "PSF-2.0",
+	"psf":                                "PSF-2.0",
+	"zlib license":                       "Zlib",
+	"the unlicense":                      "Unlicense",
+	"cc0 1.0":                            "CC0-1.0",
+	"cc0":                                "CC0-1.0",
+	"artistic 2.0":                       "Artistic-2.0",
+}
+
+// operatorPattern matches the operators of an spdx license expression, which are
+// accepted in any case as not every source uses uppercase operators
+var operatorPattern = cachedregexp.MustCompile(`(?i)\s+(AND|OR|WITH)\s+`)
+
+// Normalize replaces common variants of license names (such as "Apache 2.0") in the
+// given license expression with their spdx identifier, so they can be matched against
+// an allowlist. Licenses that are not a known variant are returned unchanged.
+func Normalize(license models.License) models.License {
+	expr := strings.TrimSpace(string(license))
+
+	// the license might be a name that includes an operator, like "GPLv2 or later"
+	if id, ok := lookupAlias(expr); ok {
+		return id
+	}
+
+	terms, operators := splitExpression(expr)
+
+	var sb strings.Builder
+	for i, term := range terms {
+		if i > 0 {
+			sb.WriteString(" " + operators[i-1] + " ")
+		}
+
+		opening, id, closing := trimBrackets(term)
+		if alias, ok := lookupAlias(id); ok {
+			id = string(alias)
+		}
+		sb.WriteString(opening + id + closing)
+	}
+
+	return models.License(sb.String())
+}
+
+// UnrecognizedTerms returns the licenses in the given license expression that are
+// not spdx identifiers, ignoring the exceptions of WITH expressions.
+func UnrecognizedTerms(license models.License) []string {
+	terms, operators := splitExpression(strings.TrimSpace(string(license)))
+
+	var ids []string
+	for i, term := range terms {
+		if i > 0 && operators[i-1] == "WITH" {
+			continue
+		}
+
+		_, id, _ := trimBrackets(term)
+		ids = append(ids, id)
+	}
+
+	return Unrecognized(ids)
+}
+
+func lookupAlias(name string) (models.License, bool) {
+	id, ok := aliases[strings.Join(strings.Fields(strings.ToLower(name)), " ")]
+
+	return id, ok
+}
+
+// splitExpression splits the license expression into its terms, and the uppercased
+// operators between each of them
+func splitExpression(expr string) (terms []string, operators []string) {
+	last := 0
+	for _, match := range operatorPattern.FindAllStringSubmatchIndex(expr, -1) {
+		terms = append(terms, expr[last:match[0]])
+		operators = append(operators, strings.ToUpper(expr[match[2]:match[3]]))
+		last = match[1]
+	}
+
+	return append(terms, expr[last:]), operators
+}
+
+// trimBrackets splits the term into its opening brackets, the license, and its closing brackets
+func trimBrackets(term string) (opening string, id string, closing string) {
+	id = strings.TrimLeft(term, "( ")
+	opening = term[:len(term)-len(id)]
+	id = strings.TrimRight(id, ") ")
+	closing = term[len(opening)+len(id):]
+
+	return strings.ReplaceAll(opening, " ", ""), id, strings.ReplaceAll(closing, " ", "")
+}
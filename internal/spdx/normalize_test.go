@@ -0,0 +1,99 @@
+package spdx_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/osv-scanner/v2/internal/spdx"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		license models.License
+		want    models.License
+	}{
+		{
+			name:    "spdx identifier",
+			license: "Apache-2.0",
+			want:    "Apache-2.0",
+		}, {
+			name:    "common variants",
+			license: "Apache 2.0",
+			want:    "Apache-2.0",
+		}, {
+			name:    "variants are matched regardless of case and spacing",
+			license: "  apache   LICENSE, version 2.0 ",
+			want:    "Apache-2.0",
+		}, {
+			name:    "abbreviations",
+			license: "ASL 2.0",
+			want:    "Apache-2.0",
+		}, {
+			name:    "variants that include an operator",
+			license: "GPLv2 or later",
+			want:    "GPL-2.0-or-later",
+		}, {
+			name:    "terms of an expression",
+			license: "MIT License or ASL 2.0",
+			want:    "MIT OR Apache-2.0",
+		}, {
+			name:    "terms of an expression with brackets",
+			license: "(The MIT License OR Apache 2.0) AND New BSD",
+			want:    "(MIT OR Apache-2.0) AND BSD-3-Clause",
+		}, {
+			name:    "with expressions",
+			license: "GPLv2 with Classpath-exception-2.0",
+			want:    "GPL-2.0-only WITH Classpath-exception-2.0",
+		}, {
+			name:    "unknown licenses are unchanged",
+			license: "Some Custom License",
+			want:    "Some Custom License",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := spdx.Normalize(tt.license); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.license, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnrecognizedTerms(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		license models.License
+		want    []string
+	}{
+		{
+			name:    "spdx identifier",
+			license: "MIT",
+			want:    nil,
+		}, {
+			name:    "spdx expression",
+			license: "(MIT OR Apache-2.0) AND GPL-2.0-only WITH Classpath-exception-2.0",
+			want:    nil,
+		}, {
+			name:    "unknown license",
+			license: "UNKNOWN",
+			want:    nil,
+		}, {
+			name:    "unrecognized terms",
+			license: "(MIT OR non-standard) AND Custom License",
+			want:    []string{"non-standard", "Custom License"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := spdx.UnrecognizedTerms(tt.license); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnrecognizedTerms(%q) = %v, want %v", tt.license, got, tt.want)
+			}
+		})
+	}
+}
@@ -131,6 +131,16 @@ func removeUntestableLines(t *testing.T, str string) string {
 	return str
 }
 
+// removeScannerInfo removes the scanner info from json output, as it describes
+// the run of the scanner (such as how long it took) rather than its results
+func removeScannerInfo(t *testing.T, str string) string {
+	t.Helper()
+
+	re := cachedregexp.MustCompile(`,\n\s*"experimental_scanner_info": \{[^{}]*\}`)
+
+	return re.ReplaceAllLiteralString(str, "")
+}
+
 // normalizeSnapshot applies a series of normalizes to the buffer from a std stream like stdout and stderr
 func normalizeSnapshot(t *testing.T, str string) string {
 	t.Helper()
@@ -142,6 +152,7 @@ func normalizeSnapshot(t *testing.T, str string) string {
 		normalizeUserCacheDirectory,
 		normalizeErrors,
 		removeUntestableLines,
+		removeScannerInfo,
 	} {
 		str = normalizer(t, str)
 	}
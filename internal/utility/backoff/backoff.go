@@ -0,0 +1,24 @@
+// Package backoff provides helpers for waiting in between attempts of requests that are retried.
+package backoff
+
+import (
+	"context"
+	"time"
+)
+
+// Sleep waits for the duration, returning the error of ctx early if it is done first
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
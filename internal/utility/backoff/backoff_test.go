@@ -0,0 +1,41 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/osv-scanner/v2/internal/utility/backoff"
+)
+
+func TestSleep(t *testing.T) {
+	t.Parallel()
+
+	if err := backoff.Sleep(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("Sleep() error = %v, want nil", err)
+	}
+
+	if err := backoff.Sleep(context.Background(), 0); err != nil {
+		t.Errorf("Sleep() error = %v, want nil", err)
+	}
+}
+
+func TestSleep_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	if err := backoff.Sleep(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("Sleep() error = %v, want %v", err, context.Canceled)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Sleep() took %s after ctx was cancelled", elapsed)
+	}
+
+	if err := backoff.Sleep(ctx, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("Sleep() error = %v, want %v", err, context.Canceled)
+	}
+}
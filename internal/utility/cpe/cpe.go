@@ -0,0 +1,211 @@
+// Package cpe parses and compares CPE names of components, in either the CPE 2.3
+// formatted string binding or the CPE 2.2 URI binding
+package cpe
+
+import (
+	"math/big"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+)
+
+// attributes are the attributes of a CPE, in the order they appear in both bindings
+var attributes = []string{
+	"part", "vendor", "product", "version", "update", "edition",
+	"language", "sw_edition", "target_sw", "target_hw", "other",
+}
+
+// Any is the logical value of attributes that match every value
+const Any = "*"
+
+// NA is the logical value of attributes that have no meaningful value
+const NA = "-"
+
+// CPE is the normalized attributes of a CPE name, with any that were left out being Any
+type CPE []string
+
+func (c CPE) Part() string    { return c[0] }
+func (c CPE) Vendor() string  { return c[1] }
+func (c CPE) Product() string { return c[2] }
+func (c CPE) Version() string { return c[3] }
+
+// Parse splits a CPE into its attributes, supporting both the 2.3 formatted string
+// binding (e.g. "cpe:2.3:a:openssl:openssl:1.1.1:*:*:*:*:*:*:*") and the 2.2 URI binding
+// (e.g. "cpe:/a:openssl:openssl:1.1.1"), with any attributes that are left out being ANY
+func Parse(cpe string) (CPE, bool) {
+	var values []string
+
+	switch {
+	case strings.HasPrefix(cpe, "cpe:2.3:"):
+		values = splitCPE(strings.TrimPrefix(cpe, "cpe:2.3:"))
+	case strings.HasPrefix(cpe, "cpe:/"):
+		for _, value := range strings.Split(strings.TrimPrefix(cpe, "cpe:/"), ":") {
+			if unescaped, err := url.PathUnescape(value); err == nil {
+				value = unescaped
+			}
+			values = append(values, value)
+		}
+	default:
+		return nil, false
+	}
+
+	if len(values) > len(attributes) {
+		return nil, false
+	}
+
+	attrs := make(CPE, len(attributes))
+	for i := range attrs {
+		attrs[i] = Any
+		if i < len(values) {
+			attrs[i] = normalizeValue(attributes[i], values[i])
+		}
+	}
+
+	return attrs, true
+}
+
+// splitCPE splits the attributes of a formatted string on the colons that are not escaped
+func splitCPE(s string) []string {
+	var values []string
+	var value strings.Builder
+
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			value.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ':':
+			values = append(values, value.String())
+			value.Reset()
+		default:
+			value.WriteRune(r)
+		}
+	}
+
+	return append(values, value.String())
+}
+
+// normalizeValue normalizes an attribute so that equivalent values can be compared,
+// which for versions means comparing "v1.2.0" the same as "1.2"
+func normalizeValue(attribute, value string) string {
+	value = strings.ToLower(value)
+
+	if value == "" {
+		return Any
+	}
+
+	if attribute == "version" || attribute == "update" {
+		if len(value) > 1 && value[0] == 'v' && value[1] >= '0' && value[1] <= '9' {
+			value = value[1:]
+		}
+		// drop zero components at the end, so that "1.2.0" is treated the same as "1.2"
+		value = cachedregexp.MustCompile(`(\.0+)+$`).ReplaceAllString(value, "")
+	}
+
+	return value
+}
+
+// Matches checks if the CPE identifies the same component as the pattern,
+// with any attributes of the pattern that are ANY matching every value
+func Matches(pattern, cpe string) bool {
+	patternAttrs, ok := Parse(pattern)
+	if !ok {
+		return false
+	}
+
+	cpeAttrs, ok := Parse(cpe)
+	if !ok {
+		return false
+	}
+
+	for i, attr := range patternAttrs {
+		if attr != Any && attr != cpeAttrs[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CompareVersions compares two CPE versions, which are made up of numeric and alphabetic
+// segments that are compared numerically or lexically respectively, returning -1, 0, or 1.
+//
+// Versions that have more segments than the other are greater, unless the extra segments are
+// all zero, so that 1.2 and 1.2.0 are equal whereas 1.2 is less than 1.2.1 and 1.2a
+func CompareVersions(a, b string) int {
+	as, bs := versionSegments(a), versionSegments(b)
+
+	for i := range max(len(as), len(bs)) {
+		var sa, sb string
+		if i < len(as) {
+			sa = as[i]
+		}
+		if i < len(bs) {
+			sb = bs[i]
+		}
+
+		if c := compareSegments(sa, sb); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+func compareSegments(a, b string) int {
+	na, aIsNum := new(big.Int).SetString(a, 10)
+	nb, bIsNum := new(big.Int).SetString(b, 10)
+
+	switch {
+	// missing segments are equal to zero, but less than any alphabetic segment
+	case a == "" && bIsNum:
+		return -nb.Sign()
+	case b == "" && aIsNum:
+		return na.Sign()
+	case aIsNum && bIsNum:
+		return na.Cmp(nb)
+	// numeric segments sort after alphabetic ones, e.g. 1.0.beta < 1.0.1
+	case aIsNum && b != "":
+		return 1
+	case bIsNum && a != "":
+		return -1
+	}
+
+	return strings.Compare(a, b)
+}
+
+// versionSegments splits a version into runs of either digits or letters,
+// discarding the punctuation that separates them
+func versionSegments(version string) []string {
+	var segments []string
+	var segment strings.Builder
+
+	flush := func() {
+		if segment.Len() > 0 {
+			segments = append(segments, segment.String())
+			segment.Reset()
+		}
+	}
+
+	lastWasDigit := false
+	for _, r := range strings.ToLower(version) {
+		isDigit := unicode.IsDigit(r)
+		if !isDigit && !unicode.IsLetter(r) {
+			flush()
+			continue
+		}
+		if segment.Len() > 0 && isDigit != lastWasDigit {
+			flush()
+		}
+		segment.WriteRune(r)
+		lastWasDigit = isDigit
+	}
+	flush()
+
+	return segments
+}
@@ -0,0 +1,57 @@
+package cpe_test
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/v2/internal/utility/cpe"
+)
+
+func TestMatches(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		cpe     string
+		want    bool
+	}{
+		{"cpe:2.3:a:openssl:openssl:1.1.1:*:*:*:*:*:*:*", "cpe:2.3:a:openssl:openssl:1.1.1:*:*:*:*:*:*:*", true},
+		{"cpe:2.3:a:openssl:openssl:*:*:*:*:*:*:*:*", "cpe:2.3:a:openssl:openssl:1.1.1:*:*:*:*:*:*:*", true},
+		{"cpe:2.3:a:openssl:openssl:1.1.1:*:*:*:*:*:*:*", "cpe:2.3:a:openssl:openssl:1.1.2:*:*:*:*:*:*:*", false},
+		{"cpe:2.3:a:openssl:openssl:1.1.0:*:*:*:*:*:*:*", "cpe:2.3:a:openssl:openssl:V1.1:*:*:*:*:*:*:*", true},
+		{"cpe:2.3:a:openssl:openssl:1.1.1:*:*:*:*:*:*:*", "cpe:/a:openssl:openssl:1.1.1", true},
+		{"cpe:/a:openssl:openssl", "cpe:2.3:a:openssl:openssl:1.1.1:*:*:*:*:*:*:*", true},
+		{"cpe:2.3:a:vendor:my\\:lib:1.0:*:*:*:*:*:*:*", "cpe:/a:vendor:my%3alib:1.0", true},
+		{"cpe:2.3:a:openssl:openssl:1.1.1:-:*:*:*:*:*:*", "cpe:2.3:a:openssl:openssl:1.1.1:beta1:*:*:*:*:*:*", false},
+		{"not-a-cpe", "cpe:2.3:a:openssl:openssl:1.1.1:*:*:*:*:*:*:*", false},
+	}
+	for _, tt := range tests {
+		if got := cpe.Matches(tt.pattern, tt.cpe); got != tt.want {
+			t.Errorf("Matches(%q, %q) = %v, want %v", tt.pattern, tt.cpe, got, tt.want)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2", "1.2.0", 0},
+		{"1.2", "1.2.1", -1},
+		{"1.10", "1.9", 1},
+		{"1.2", "1.2a", -1},
+		{"1.2a", "1.2b", -1},
+		{"1.0.beta", "1.0.1", -1},
+		{"2.4-r1", "2.4-r10", -1},
+		{"20220614", "20220614", 0},
+		{"3.0.2C", "3.0.2b", 1},
+	}
+	for _, tt := range tests {
+		if got := cpe.CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -10,7 +10,7 @@ import (
 func IsDevGroup(sys osvschema.Ecosystem, groups []string) bool {
 	var dev string
 	switch sys {
-	case osvschema.EcosystemPackagist, osvschema.EcosystemNPM, osvschema.EcosystemPyPI, osvschema.EcosystemPub:
+	case osvschema.EcosystemPackagist, osvschema.EcosystemNPM, osvschema.EcosystemPyPI, osvschema.EcosystemPub, osvschema.EcosystemCratesIO:
 		dev = "dev"
 	case osvschema.EcosystemConanCenter:
 		dev = "build-requires"
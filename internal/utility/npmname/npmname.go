@@ -0,0 +1,18 @@
+// Package npmname parses the "<name>@<reference>" strings that JavaScript lockfiles use to
+// identify packages, whose names start with "@" themselves if the package is scoped.
+package npmname
+
+import "strings"
+
+// Split splits s into the name of the package and the reference that follows it, e.g.
+// "@babel/core@npm:7.24.0" becomes "@babel/core" and "npm:7.24.0", returning false if
+// s is not of the form "<name>@<reference>"
+func Split(s string) (string, string, bool) {
+	// skip the first character, which is "@" for scoped packages
+	i := strings.Index(s[min(1, len(s)):], "@") + 1
+	if i <= 0 {
+		return "", "", false
+	}
+
+	return s[:i], s[i+1:], true
+}
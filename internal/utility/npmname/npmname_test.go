@@ -0,0 +1,37 @@
+package npmname_test
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/v2/internal/utility/npmname"
+)
+
+func TestSplit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s             string
+		wantName      string
+		wantReference string
+		wantOk        bool
+	}{
+		{s: "lodash@npm:4.17.21", wantName: "lodash", wantReference: "npm:4.17.21", wantOk: true},
+		{s: "@babel/core@npm:7.24.0", wantName: "@babel/core", wantReference: "npm:7.24.0", wantOk: true},
+		{s: "string-width-cjs@npm:string-width@^4.2.0", wantName: "string-width-cjs", wantReference: "npm:string-width@^4.2.0", wantOk: true},
+		{s: "react-dom@18.2.0", wantName: "react-dom", wantReference: "18.2.0", wantOk: true},
+		{s: "lodash", wantOk: false},
+		{s: "@babel/core", wantOk: false},
+		{s: "@", wantOk: false},
+		{s: "", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			t.Parallel()
+
+			name, reference, ok := npmname.Split(tt.s)
+			if name != tt.wantName || reference != tt.wantReference || ok != tt.wantOk {
+				t.Errorf("Split(%q) = %q, %q, %v, want %q, %q, %v", tt.s, name, reference, ok, tt.wantName, tt.wantReference, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package purl
+
+import (
+	"strings"
+
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/package-url/packageurl-go"
+)
+
+// Matches checks if the package URL identifies the package, ignoring any qualifiers
+// and matching every version of the package if the package URL does not have one
+func Matches(want packageurl.PackageURL, pkg models.PackageInfo) bool {
+	got, err := FromPackage(pkg)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(want.Type, got.Type) &&
+		want.Namespace == got.Namespace &&
+		want.Name == got.Name &&
+		(want.Version == "" || want.Version == got.Version)
+}
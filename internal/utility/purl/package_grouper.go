@@ -41,6 +41,8 @@ func Group(packageSources []models.PackageSource) (map[string]models.PackageVuln
 					Groups:            slices.Clone(pkg.Groups),
 					Licenses:          slices.Clone(pkg.Licenses),
 					LicenseViolations: slices.Clone(pkg.LicenseViolations),
+
+					ExperimentalIgnoredVulnerabilities: slices.Clone(pkg.ExperimentalIgnoredVulnerabilities),
 				}
 
 				uniquePackages[packageURL.ToString()] = newPackageVuln
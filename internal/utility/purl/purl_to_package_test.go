@@ -75,6 +75,83 @@ func TestPURLToPackage(t *testing.T) {
 				Ecosystem: string(osvschema.EcosystemAlpine),
 			},
 		},
+		{
+			name: "valid PURL nuget",
+			args: args{
+				purl: "pkg:nuget/Newtonsoft.Json@12.0.1",
+			},
+			want: models.PackageInfo{
+				Name:      "Newtonsoft.Json",
+				Version:   "12.0.1",
+				Ecosystem: string(osvschema.EcosystemNuGet),
+			},
+		},
+		{
+			name: "valid PURL gem",
+			args: args{
+				purl: "pkg:gem/rails@7.0.4",
+			},
+			want: models.PackageInfo{
+				Name:      "rails",
+				Version:   "7.0.4",
+				Ecosystem: string(osvschema.EcosystemRubyGems),
+			},
+		},
+		{
+			name: "valid PURL pypi",
+			args: args{
+				purl: "pkg:pypi/django@4.1.2",
+			},
+			want: models.PackageInfo{
+				Name:      "django",
+				Version:   "4.1.2",
+				Ecosystem: string(osvschema.EcosystemPyPI),
+			},
+		},
+		{
+			name: "valid PURL scoped npm",
+			args: args{
+				purl: "pkg:npm/%40babel/core@7.20.0",
+			},
+			want: models.PackageInfo{
+				Name:      "@babel/core",
+				Version:   "7.20.0",
+				Ecosystem: string(osvschema.EcosystemNPM),
+			},
+		},
+		{
+			name: "valid PURL composer",
+			args: args{
+				purl: "pkg:composer/symfony/yaml@7.0.0",
+			},
+			want: models.PackageInfo{
+				Name:      "symfony/yaml",
+				Version:   "7.0.0",
+				Ecosystem: string(osvschema.EcosystemPackagist),
+			},
+		},
+		{
+			name: "valid PURL generic",
+			args: args{
+				purl: "pkg:generic/openssl@3.0.7",
+			},
+			want: models.PackageInfo{
+				Name:      "openssl",
+				Version:   "3.0.7",
+				Ecosystem: string(osvschema.EcosystemOSSFuzz),
+			},
+		},
+		{
+			name: "PURL with an unknown type",
+			args: args{
+				purl: "pkg:bitbucket/birkenfeld/pygments-main@244fd47e07d1014f0aed9c",
+			},
+			want: models.PackageInfo{
+				Name:      "birkenfeld/pygments-main",
+				Version:   "244fd47e07d1014f0aed9c",
+				Ecosystem: "",
+			},
+		},
 		{
 			name: "invalid PURL",
 			args: args{
@@ -96,3 +96,42 @@ func CalculateRating(score string) (Rating, error) {
 
 	return Rating(rating), err
 }
+
+// qualitativeScores are the lowest CVSS scores of each qualitative severity rating, which are
+// used as the score of vulnerabilities that only have a qualitative severity from their database
+var qualitativeScores = map[string]float64{
+	"CRITICAL": 9.0,
+	"HIGH":     7.0,
+	"MODERATE": 4.0,
+	"MEDIUM":   4.0,
+	"LOW":      0.1,
+}
+
+// CalculateVulnerabilityScore calculates the highest score of a vulnerability from its CVSS
+// vectors, falling back to the qualitative severity in its `database_specific` fields (such as
+// those of GitHub advisories) when there are no vectors. -1 is returned if neither can be used.
+func CalculateVulnerabilityScore(vuln osvschema.Vulnerability) float64 {
+	if score, _, err := CalculateOverallScore(vuln.Severity); err == nil && score >= 0 {
+		return score
+	}
+
+	score := qualitativeScore(vuln.DatabaseSpecific)
+	for _, affected := range vuln.Affected {
+		score = max(score, qualitativeScore(affected.DatabaseSpecific), qualitativeScore(affected.EcosystemSpecific))
+	}
+
+	return score
+}
+
+func qualitativeScore(specific map[string]any) float64 {
+	sev, ok := specific["severity"].(string)
+	if !ok {
+		return -1
+	}
+	score, ok := qualitativeScores[strings.ToUpper(sev)]
+	if !ok {
+		return -1
+	}
+
+	return score
+}
@@ -90,3 +90,74 @@ func TestSeverity_CalculateScore(t *testing.T) {
 		})
 	}
 }
+
+func TestSeverity_CalculateVulnerabilityScore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		vuln osvschema.Vulnerability
+		want float64
+	}{
+		{
+			name: "no severity",
+			vuln: osvschema.Vulnerability{},
+			want: -1,
+		},
+		{
+			name: "CVSS v3 vector",
+			vuln: osvschema.Vulnerability{
+				Severity: []osvschema.Severity{
+					{Type: osvschema.SeverityCVSSV3, Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H"},
+				},
+				DatabaseSpecific: map[string]any{"severity": "LOW"},
+			},
+			want: 7.5,
+		},
+		{
+			name: "database specific severity",
+			vuln: osvschema.Vulnerability{
+				DatabaseSpecific: map[string]any{"severity": "MODERATE"},
+			},
+			want: 4.0,
+		},
+		{
+			name: "affected database specific severity",
+			vuln: osvschema.Vulnerability{
+				Affected: []osvschema.Affected{
+					{DatabaseSpecific: map[string]any{"severity": "low"}},
+					{EcosystemSpecific: map[string]any{"severity": "critical"}},
+				},
+			},
+			want: 9.0,
+		},
+		{
+			name: "unknown database specific severity",
+			vuln: osvschema.Vulnerability{
+				DatabaseSpecific: map[string]any{"severity": "unimportant"},
+			},
+			want: -1,
+		},
+		{
+			name: "invalid vector with database specific severity",
+			vuln: osvschema.Vulnerability{
+				Severity: []osvschema.Severity{
+					{Type: osvschema.SeverityCVSSV3, Score: "CVSS:3.1/AV:N"},
+				},
+				DatabaseSpecific: map[string]any{"severity": "HIGH"},
+			},
+			want: 7.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := severity.CalculateVulnerabilityScore(tt.vuln)
+			if math.Round(10*got) != math.Round(10*tt.want) {
+				t.Errorf("CalculateVulnerabilityScore() = %.1f, want %.1f", got, tt.want)
+			}
+		})
+	}
+}
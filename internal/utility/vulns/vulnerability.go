@@ -125,6 +125,18 @@ func AffectsEcosystem(v osvschema.Vulnerability, ecosystemAffected ecosystem.Par
 	return false
 }
 
+// AffectsPackage returns true if the vulnerability affects any version of the package
+func AffectsPackage(v osvschema.Vulnerability, pkg imodels.PackageInfo) bool {
+	for _, affected := range v.Affected {
+		if ecosystem.MustParse(affected.Package.Ecosystem).Equal(pkg.Ecosystem()) &&
+			affected.Package.Name == pkg.Name() {
+			return true
+		}
+	}
+
+	return false
+}
+
 func IsAffected(v osvschema.Vulnerability, pkg imodels.PackageInfo) bool {
 	for _, affected := range v.Affected {
 		// Assume vulnerability has already been validated
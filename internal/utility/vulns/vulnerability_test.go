@@ -127,6 +127,71 @@ func TestOSV_AffectsEcosystem(t *testing.T) {
 	}
 }
 
+func TestOSV_AffectsPackage(t *testing.T) {
+	t.Parallel()
+
+	pkg := imodels.FromInventory(
+		&extractor.Inventory{
+			Name:    "my-package",
+			Version: "2.0.0",
+			Extractor: ecosystemmock.Extractor{
+				MockEcosystem: string(osvschema.EcosystemNPM),
+			},
+		},
+	)
+
+	tests := []struct {
+		name     string
+		affected []osvschema.Affected
+		want     bool
+	}{
+		{
+			name:     "no affected",
+			affected: nil,
+			want:     false,
+		},
+		{
+			name: "different ecosystem",
+			affected: []osvschema.Affected{
+				{Package: osvschema.Package{Ecosystem: string(osvschema.EcosystemPyPI), Name: "my-package"}},
+			},
+			want: false,
+		},
+		{
+			name: "different name",
+			affected: []osvschema.Affected{
+				{Package: osvschema.Package{Ecosystem: string(osvschema.EcosystemNPM), Name: "other-package"}},
+			},
+			want: false,
+		},
+		{
+			name: "version is not affected",
+			affected: []osvschema.Affected{
+				{
+					Package: osvschema.Package{Ecosystem: string(osvschema.EcosystemNPM), Name: "my-package"},
+					Ranges: []osvschema.Range{
+						buildSemverAffectsRange(
+							osvschema.Event{Introduced: "0"},
+							osvschema.Event{Fixed: "1.0.0"},
+						),
+					},
+				},
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := vulns.AffectsPackage(buildOSVWithAffected(tt.affected...), pkg); got != tt.want {
+				t.Errorf("AffectsPackage() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestOSV_IsAffected_AffectsWithEcosystem_DifferentEcosystem(t *testing.T) {
 	t.Parallel()
 
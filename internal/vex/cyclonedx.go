@@ -0,0 +1,97 @@
+package vex
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CycloneDX/cyclonedx-go"
+)
+
+func parseCycloneDX(content []byte) (Statements, error) {
+	var bom cyclonedx.BOM
+	if err := cyclonedx.NewBOMDecoder(bytes.NewReader(content), cyclonedx.BOMFileFormatJSON).Decode(&bom); err != nil {
+		return nil, err
+	}
+
+	if bom.Vulnerabilities == nil {
+		return nil, nil
+	}
+
+	// the vulnerabilities affect the components by their BOM reference,
+	// which are usually the package URL of the component
+	componentPURLs := make(map[string]string)
+	if bom.Components != nil {
+		collectComponentPURLs(*bom.Components, componentPURLs)
+	}
+
+	statements := make(Statements, 0, len(*bom.Vulnerabilities))
+	for _, vuln := range *bom.Vulnerabilities {
+		if vuln.Analysis == nil || vuln.Affects == nil {
+			continue
+		}
+
+		statement := Statement{
+			Vulnerability: vuln.ID,
+			State:         string(vuln.Analysis.State),
+			Justification: string(vuln.Analysis.Justification),
+			Detail:        vuln.Analysis.Detail,
+		}
+
+		if vuln.References != nil {
+			for _, reference := range *vuln.References {
+				statement.Aliases = append(statement.Aliases, reference.ID)
+			}
+		}
+
+		for _, affects := range *vuln.Affects {
+			if p, ok := parseProduct(affectedPURL(affects.Ref, componentPURLs)); ok {
+				statement.Products = append(statement.Products, p)
+			}
+		}
+
+		timestamp := vuln.Analysis.LastUpdated
+		if timestamp == "" {
+			timestamp = vuln.Analysis.FirstIssued
+		}
+		if timestamp != "" {
+			t, err := time.Parse(time.RFC3339, timestamp)
+			if err != nil {
+				return nil, fmt.Errorf("analysis of %s has an invalid timestamp: %w", vuln.ID, err)
+			}
+			statement.Timestamp = t
+		}
+
+		statements = append(statements, statement)
+	}
+
+	return statements, nil
+}
+
+func collectComponentPURLs(components []cyclonedx.Component, purls map[string]string) {
+	for _, component := range components {
+		if component.BOMRef != "" && component.PackageURL != "" {
+			purls[component.BOMRef] = component.PackageURL
+		}
+		if component.Components != nil {
+			collectComponentPURLs(*component.Components, purls)
+		}
+	}
+}
+
+// affectedPURL returns the package URL of the component that is referenced by an affects of a vulnerability,
+// which can also be a BOM-Link to a component in another BOM (e.g. "urn:cdx:<serial>/1#<bom-ref>")
+func affectedPURL(ref string, componentPURLs map[string]string) string {
+	if strings.HasPrefix(ref, "urn:cdx:") {
+		if _, fragment, ok := strings.Cut(ref, "#"); ok {
+			ref = fragment
+		}
+	}
+
+	if p, ok := componentPURLs[ref]; ok {
+		return p
+	}
+
+	return ref
+}
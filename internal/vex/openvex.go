@@ -0,0 +1,147 @@
+package vex
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// openVEXStates maps the statuses of OpenVEX to the CycloneDX analysis state they are
+var openVEXStates = map[string]string{
+	"not_affected":        "not_affected",
+	"affected":            "exploitable",
+	"fixed":               "resolved",
+	"under_investigation": "in_triage",
+}
+
+// openVEXJustifications maps the justifications of OpenVEX to the closest CycloneDX justification
+var openVEXJustifications = map[string]string{
+	"component_not_present":                             "code_not_present",
+	"vulnerable_code_not_present":                       "code_not_present",
+	"vulnerable_code_not_in_execute_path":               "code_not_reachable",
+	"vulnerable_code_cannot_be_controlled_by_adversary": "requires_environment",
+	"inline_mitigations_already_exist":                  "protected_by_mitigating_control",
+}
+
+// openVEXDocument is an OpenVEX document, as specified by https://github.com/openvex/spec
+type openVEXDocument struct {
+	Timestamp  string             `json:"timestamp"`
+	Statements []openVEXStatement `json:"statements"`
+}
+
+type openVEXStatement struct {
+	Vulnerability   openVEXVulnerability `json:"vulnerability"`
+	Products        []openVEXProduct     `json:"products"`
+	Status          string               `json:"status"`
+	Justification   string               `json:"justification"`
+	ImpactStatement string               `json:"impact_statement"`
+	StatusNotes     string               `json:"status_notes"`
+	Timestamp       string               `json:"timestamp"`
+}
+
+// openVEXVulnerability is the vulnerability of a statement, which is
+// just its name in documents from before v0.2.0 of the spec
+type openVEXVulnerability struct {
+	ID      string   `json:"@id"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+func (v *openVEXVulnerability) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &v.Name); err == nil {
+		return nil
+	}
+
+	type vulnerability openVEXVulnerability
+
+	return json.Unmarshal(data, (*vulnerability)(v))
+}
+
+// openVEXProduct is a product of a statement, which is just its ID in documents from before
+// v0.2.0 of the spec, and can have subcomponents that the statement is about within it
+type openVEXProduct struct {
+	ID          string `json:"@id"`
+	Identifiers struct {
+		PURL string `json:"purl"`
+	} `json:"identifiers"`
+	Subcomponents []openVEXProduct `json:"subcomponents"`
+}
+
+func (p *openVEXProduct) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &p.ID); err == nil {
+		return nil
+	}
+
+	type product openVEXProduct
+
+	return json.Unmarshal(data, (*product)(p))
+}
+
+// purls returns the package URLs of the product and its subcomponents
+func (p openVEXProduct) purls() []string {
+	purls := []string{p.ID, p.Identifiers.PURL}
+	for _, subcomponent := range p.Subcomponents {
+		purls = append(purls, subcomponent.purls()...)
+	}
+
+	return purls
+}
+
+func parseOpenVEX(content []byte) (Statements, error) {
+	var doc openVEXDocument
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+
+	statements := make(Statements, 0, len(doc.Statements))
+	for i, s := range doc.Statements {
+		state, ok := openVEXStates[s.Status]
+		if !ok {
+			return nil, fmt.Errorf("statement %d has an invalid status %q", i, s.Status)
+		}
+
+		id := s.Vulnerability.Name
+		if id == "" {
+			id = s.Vulnerability.ID
+		}
+		if id == "" {
+			return nil, fmt.Errorf("statement %d is not about a vulnerability", i)
+		}
+
+		statement := Statement{
+			Vulnerability: id,
+			Aliases:       s.Vulnerability.Aliases,
+			State:         state,
+			Justification: openVEXJustifications[s.Justification],
+			Detail:        s.ImpactStatement,
+		}
+		if statement.Detail == "" {
+			statement.Detail = s.StatusNotes
+		}
+
+		for _, product := range s.Products {
+			for _, id := range product.purls() {
+				if p, ok := parseProduct(id); ok {
+					statement.Products = append(statement.Products, p)
+				}
+			}
+		}
+
+		// statements inherit the timestamp of the document if they do not have their own
+		timestamp := s.Timestamp
+		if timestamp == "" {
+			timestamp = doc.Timestamp
+		}
+		if timestamp != "" {
+			t, err := time.Parse(time.RFC3339, timestamp)
+			if err != nil {
+				return nil, fmt.Errorf("statement %d has an invalid timestamp: %w", i, err)
+			}
+			statement.Timestamp = t
+		}
+
+		statements = append(statements, statement)
+	}
+
+	return statements, nil
+}
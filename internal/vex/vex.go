@@ -0,0 +1,132 @@
+// Package vex reads VEX (Vulnerability Exploitability eXchange) documents, which state whether
+// products are affected by vulnerabilities, so that findings that do not affect them can be suppressed.
+package vex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/google/osv-scanner/v2/internal/utility/purl"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/package-url/packageurl-go"
+)
+
+// Statement is a statement of a VEX document about whether a vulnerability affects some components
+type Statement struct {
+	// Vulnerability is the ID of the vulnerability, with Aliases being any other IDs it has
+	Vulnerability string
+	Aliases       []string
+	// Products are the package URLs of the components the statement is about, which match
+	// every version of the component if they do not have a version
+	Products []packageurl.PackageURL
+	// State is the CycloneDX analysis state of the vulnerability, such as "not_affected"
+	State string
+	// Justification is the CycloneDX justification of why the vulnerability does not affect the components
+	Justification string
+	// Detail explains the state, such as why the vulnerability does not affect the components
+	Detail string
+	// Document is the path of the VEX document that the statement is from
+	Document string
+	// Timestamp is when the statement was made, if it is known
+	Timestamp time.Time
+}
+
+// suppressingStates are the CycloneDX analysis states of vulnerabilities that do not need to be reported
+var suppressingStates = []string{"not_affected", "false_positive", "resolved", "resolved_with_pedigree"}
+
+// Suppresses returns true if the statement is that the vulnerability does not affect
+// its components, or that it has been fixed in them
+func (s Statement) Suppresses() bool {
+	return slices.Contains(suppressingStates, s.State)
+}
+
+func (s Statement) isAbout(pkg models.PackageInfo, ids []string) bool {
+	if !slices.Contains(ids, s.Vulnerability) && !slices.ContainsFunc(s.Aliases, func(alias string) bool { return slices.Contains(ids, alias) }) {
+		return false
+	}
+
+	return slices.ContainsFunc(s.Products, func(product packageurl.PackageURL) bool {
+		return purl.Matches(product, pkg)
+	})
+}
+
+// Statements are the statements of any number of VEX documents, from the earliest to the latest
+type Statements []Statement
+
+// Find returns the latest statement about any of the IDs of a vulnerability in the package,
+// which is the one that supersedes any earlier statements about it
+func (s Statements) Find(pkg models.PackageInfo, ids []string) (Statement, bool) {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i].isAbout(pkg, ids) {
+			return s[i], true
+		}
+	}
+
+	return Statement{}, false
+}
+
+// document has the fields that identify the format of a VEX document
+type document struct {
+	Context   string `json:"@context"`
+	BOMFormat string `json:"bomFormat"`
+}
+
+// Load reads the statements of the VEX documents at the given paths, which can be either
+// OpenVEX or CycloneDX JSON documents, returning them from the earliest to the latest
+//
+// Statements without a timestamp are treated as being earlier than those that have one,
+// and are otherwise in the order of the documents they are from.
+func Load(paths ...string) (Statements, error) {
+	var statements Statements
+
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read VEX document: %w", err)
+		}
+
+		var doc document
+		if err := json.Unmarshal(content, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse VEX document %s: %w", path, err)
+		}
+
+		var docStatements Statements
+		switch {
+		case doc.BOMFormat == "CycloneDX":
+			docStatements, err = parseCycloneDX(content)
+		case doc.Context != "":
+			docStatements, err = parseOpenVEX(content)
+		default:
+			err = errors.New("unknown format, which must be either OpenVEX or CycloneDX JSON")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse VEX document %s: %w", path, err)
+		}
+
+		for i := range docStatements {
+			docStatements[i].Document = path
+		}
+		statements = append(statements, docStatements...)
+	}
+
+	slices.SortStableFunc(statements, func(a, b Statement) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+
+	return statements, nil
+}
+
+// parseProduct parses the package URL of a product of a statement, returning false if
+// the product is not identified by one as it cannot be matched against packages
+func parseProduct(id string) (packageurl.PackageURL, bool) {
+	product, err := packageurl.FromString(id)
+	if err != nil {
+		return packageurl.PackageURL{}, false
+	}
+
+	return product, true
+}
@@ -0,0 +1,249 @@
+package vex_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/vex"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/package-url/packageurl-go"
+)
+
+func mustParsePURL(t *testing.T, s string) packageurl.PackageURL {
+	t.Helper()
+
+	p, err := packageurl.FromString(s)
+	if err != nil {
+		t.Fatalf("invalid purl %q: %v", s, err)
+	}
+
+	return p
+}
+
+func mustParseTime(t *testing.T, s string) time.Time {
+	t.Helper()
+
+	tm, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatalf("invalid time %q: %v", s, err)
+	}
+
+	return tm
+}
+
+func TestLoad_OpenVEX(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join("testdata", "openvex.json")
+
+	got, err := vex.Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := vex.Statements{
+		{
+			Vulnerability: "CVE-2021-23337",
+			Aliases:       []string{"GHSA-35jh-r3h4-6jhm"},
+			Products: []packageurl.PackageURL{
+				mustParsePURL(t, "pkg:oci/app@sha256%3Aabc"),
+				mustParsePURL(t, "pkg:npm/lodash@4.17.20"),
+			},
+			State:         "not_affected",
+			Justification: "code_not_reachable",
+			Detail:        "template() is never called",
+			Document:      path,
+			Timestamp:     mustParseTime(t, "2024-05-01T00:00:00Z"),
+		},
+		{
+			Vulnerability: "CVE-2021-44906",
+			Products:      []packageurl.PackageURL{mustParsePURL(t, "pkg:npm/minimist")},
+			State:         "in_triage",
+			Document:      path,
+			Timestamp:     mustParseTime(t, "2024-05-01T00:00:00Z"),
+		},
+		{
+			Vulnerability: "CVE-2022-25883",
+			Products:      []packageurl.PackageURL{mustParsePURL(t, "pkg:npm/semver@7.3.7")},
+			State:         "resolved",
+			Detail:        "Patched in our fork",
+			Document:      path,
+			Timestamp:     mustParseTime(t, "2024-06-01T00:00:00Z"),
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Load() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoad_OpenVEXLegacy(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join("testdata", "openvex-v0.0.1.json")
+
+	got, err := vex.Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := vex.Statements{
+		{
+			Vulnerability: "CVE-2021-44906",
+			Products:      []packageurl.PackageURL{mustParsePURL(t, "pkg:npm/minimist@1.2.5")},
+			State:         "not_affected",
+			Justification: "protected_by_mitigating_control",
+			Document:      path,
+			Timestamp:     mustParseTime(t, "2023-01-01T00:00:00Z"),
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Load() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoad_CycloneDX(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join("testdata", "cyclonedx.json")
+
+	got, err := vex.Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// statements without a timestamp come first
+	want := vex.Statements{
+		{
+			Vulnerability: "CVE-2022-25883",
+			Products:      []packageurl.PackageURL{mustParsePURL(t, "pkg:npm/semver@7.3.7")},
+			State:         "false_positive",
+			Detail:        "Not the same semver",
+			Document:      path,
+		},
+		{
+			Vulnerability: "GHSA-35jh-r3h4-6jhm",
+			Aliases:       []string{"CVE-2021-23337"},
+			Products:      []packageurl.PackageURL{mustParsePURL(t, "pkg:npm/lodash@4.17.20")},
+			State:         "exploitable",
+			Document:      path,
+			Timestamp:     mustParseTime(t, "2024-07-01T00:00:00Z"),
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Load() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []string{
+		filepath.Join("testdata", "does-not-exist.json"),
+		filepath.Join("testdata", "unknown.json"),
+	}
+
+	for _, path := range tests {
+		t.Run(path, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := vex.Load(path); err == nil {
+				t.Errorf("expected an error loading %s", path)
+			}
+		})
+	}
+}
+
+func TestStatements_Find(t *testing.T) {
+	t.Parallel()
+
+	statements, err := vex.Load(
+		filepath.Join("testdata", "openvex.json"),
+		filepath.Join("testdata", "openvex-v0.0.1.json"),
+		filepath.Join("testdata", "cyclonedx.json"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lodash := models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"}
+	minimist := models.PackageInfo{Name: "minimist", Version: "1.2.5", Ecosystem: "npm"}
+
+	tests := []struct {
+		name       string
+		pkg        models.PackageInfo
+		ids        []string
+		wantFound  bool
+		wantState  string
+		suppresses bool
+	}{
+		{
+			name:       "latest statement supersedes earlier ones",
+			pkg:        lodash,
+			ids:        []string{"CVE-2021-23337"},
+			wantFound:  true,
+			wantState:  "exploitable",
+			suppresses: false,
+		},
+		{
+			name:       "matches by alias of the statement",
+			pkg:        lodash,
+			ids:        []string{"GHSA-35jh-r3h4-6jhm"},
+			wantFound:  true,
+			wantState:  "exploitable",
+			suppresses: false,
+		},
+		{
+			name:       "product without a version matches every version",
+			pkg:        minimist,
+			ids:        []string{"CVE-2021-44906"},
+			wantFound:  true,
+			wantState:  "in_triage",
+			suppresses: false,
+		},
+		{
+			name:      "different version is not matched",
+			pkg:       models.PackageInfo{Name: "lodash", Version: "4.17.21", Ecosystem: "npm"},
+			ids:       []string{"CVE-2021-23337"},
+			wantFound: false,
+		},
+		{
+			name:      "different vulnerability is not matched",
+			pkg:       lodash,
+			ids:       []string{"CVE-2020-28500"},
+			wantFound: false,
+		},
+		{
+			name:       "statements with a timestamp supersede those without",
+			pkg:        models.PackageInfo{Name: "semver", Version: "7.3.7", Ecosystem: "npm"},
+			ids:        []string{"GHSA-c2qf-rxjj-qqgw", "CVE-2022-25883"},
+			wantFound:  true,
+			wantState:  "resolved",
+			suppresses: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, found := statements.Find(tt.pkg, tt.ids)
+			if found != tt.wantFound {
+				t.Fatalf("Find() found = %v, want %v", found, tt.wantFound)
+			}
+			if !found {
+				return
+			}
+			if got.State != tt.wantState {
+				t.Errorf("Find() state = %q, want %q", got.State, tt.wantState)
+			}
+			if got.Suppresses() != tt.suppresses {
+				t.Errorf("Suppresses() = %v, want %v", got.Suppresses(), tt.suppresses)
+			}
+		})
+	}
+}
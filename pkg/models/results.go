@@ -3,6 +3,7 @@ package models
 import (
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/google/osv-scalibr/extractor"
 	"github.com/ossf/osv-schema/bindings/go/osvschema"
@@ -14,6 +15,26 @@ type VulnerabilityResults struct {
 	ExperimentalAnalysisConfig ExperimentalAnalysisConfig `json:"experimental_config"`
 	ImageMetadata              *ImageMetadata             `json:"image_metadata,omitempty"`
 	LicenseSummary             []LicenseCount             `json:"license_summary,omitempty"`
+	// ExperimentalAggregatedFindings has the vulnerabilities of Results with identical
+	// findings across sources collapsed together, if this was requested
+	ExperimentalAggregatedFindings []AggregatedFinding `json:"experimental_aggregated_findings,omitempty"`
+	// ExperimentalScannerInfo describes the run of the scanner that produced the results
+	ExperimentalScannerInfo *ScannerInfo `json:"experimental_scanner_info,omitempty"`
+}
+
+// ScannerInfo is the provenance of the results of a scan
+type ScannerInfo struct {
+	// Version is the version of osv-scanner that performed the scan
+	Version string `json:"version"`
+	// OSVDatabaseTimestamp is when the least up to date of the local databases that
+	// vulnerabilities were matched against was last modified, if scanning offline
+	OSVDatabaseTimestamp *time.Time `json:"osv_database_timestamp,omitempty"`
+	// PackagesScanned is the number of packages that vulnerabilities were matched against
+	PackagesScanned int `json:"packages_scanned"`
+	// SourcesScanned is the number of sources (such as lockfiles) that the packages were found in
+	SourcesScanned int `json:"sources_scanned"`
+	// ElapsedSeconds is how long the scan took, not including writing the results
+	ElapsedSeconds float64 `json:"elapsed_seconds"`
 }
 
 type LicenseCount struct {
@@ -61,6 +82,53 @@ func (vulns *VulnerabilityResults) Flatten() []VulnerabilityFlattened {
 	return results
 }
 
+// Aggregate collapses the identical vulnerability findings of every source into one finding,
+// listing all the sources that the vulnerable package (at the same version) was found in.
+// Findings are ordered by when they first appear in the results.
+func (vulns *VulnerabilityResults) Aggregate() []AggregatedFinding {
+	results := []AggregatedFinding{}
+	indexes := make(map[string]int)
+	for _, res := range vulns.Results {
+		for _, pkg := range res.Packages {
+			for _, group := range pkg.Groups {
+				key := strings.Join([]string{
+					pkg.Package.Ecosystem,
+					pkg.Package.Name,
+					pkg.Package.Version,
+					pkg.Package.Commit,
+					strings.Join(pkg.DepGroups, ","),
+					group.IndexString(),
+				}, ":")
+
+				idx, ok := indexes[key]
+				if !ok {
+					idx = len(results)
+					indexes[key] = idx
+					results = append(results, AggregatedFinding{
+						Package:   pkg.Package,
+						DepGroups: pkg.DepGroups,
+						GroupInfo: group,
+					})
+				}
+				if !slices.Contains(results[idx].Sources, res.Source) {
+					results[idx].Sources = append(results[idx].Sources, res.Source)
+				}
+			}
+		}
+	}
+
+	return results
+}
+
+// AggregatedFinding is a vulnerability (group) found for a package at a specific version,
+// along with every source the package was found in
+type AggregatedFinding struct {
+	Package   PackageInfo  `json:"package"`
+	DepGroups []string     `json:"dependency_groups,omitempty"`
+	GroupInfo GroupInfo    `json:"group"`
+	Sources   []SourceInfo `json:"sources"`
+}
+
 func getGroupInfoForVuln(groups []GroupInfo, vulnID string) GroupInfo {
 	// groupIdx should never be -1 since vulnerabilities should always be in one group
 	groupIdx := slices.IndexFunc(groups, func(g GroupInfo) bool { return slices.Contains(g.IDs, vulnID) })
@@ -108,12 +176,44 @@ type License string
 // PackageVulns grouped by package
 // TODO: rename this to be Package as it now includes license information too.
 type PackageVulns struct {
-	Package           PackageInfo               `json:"package"`
-	DepGroups         []string                  `json:"dependency_groups,omitempty"`
-	Vulnerabilities   []osvschema.Vulnerability `json:"vulnerabilities,omitempty"`
-	Groups            []GroupInfo               `json:"groups,omitempty"`
-	Licenses          []License                 `json:"licenses,omitempty"`
-	LicenseViolations []License                 `json:"license_violations,omitempty"`
+	Package   PackageInfo `json:"package"`
+	DepGroups []string    `json:"dependency_groups,omitempty"`
+	// CPEs identify the package in the SBOM it was extracted from, if any
+	CPEs []string `json:"cpes,omitempty"`
+	// EditableSource is the directory that the package was installed from, if it is
+	// an editable install in a Python environment
+	EditableSource string `json:"editable_source,omitempty"`
+	// DependencyPath is the shortest path of packages (as "name@version") through which the
+	// package is depended on, starting with a direct dependency and ending with the package itself
+	DependencyPath []string `json:"dependency_path,omitempty"`
+	// ExperimentalDirectnessUnknown is set when the scan only included direct or transitive
+	// dependencies, but the package could not be determined to be either, so was kept
+	ExperimentalDirectnessUnknown bool                      `json:"experimental_directness_unknown,omitempty"`
+	Vulnerabilities               []osvschema.Vulnerability `json:"vulnerabilities,omitempty"`
+	Groups                        []GroupInfo               `json:"groups,omitempty"`
+	// ExperimentalUnaffectedVulnerabilities are the advisories of the package that do not affect
+	// its version, which are only included when all vulnerabilities are requested for auditing,
+	// and are not findings of the scan
+	ExperimentalUnaffectedVulnerabilities []osvschema.Vulnerability `json:"experimental_unaffected_vulnerabilities,omitempty"`
+	// ExperimentalIgnoredVulnerabilities are the vulnerabilities of the package that were ignored by
+	// the config, which are only included when they are requested (such as for VEX statements),
+	// along with those that were suppressed by a VEX document
+	ExperimentalIgnoredVulnerabilities []IgnoredVulnerability `json:"experimental_ignored_vulnerabilities,omitempty"`
+	Licenses                           []License              `json:"licenses,omitempty"`
+	LicenseViolations                  []License              `json:"license_violations,omitempty"`
+}
+
+// IgnoredVulnerability is a vulnerability that was ignored by the config, along with why it was ignored
+type IgnoredVulnerability struct {
+	Vulnerability osvschema.Vulnerability `json:"vulnerability"`
+	Reason        string                  `json:"reason,omitempty"`
+	// State is the CycloneDX analysis state of the vulnerability, such as "not_affected"
+	State string `json:"state"`
+	// Justification is the CycloneDX justification of why the vulnerability does not affect the package
+	Justification string `json:"justification,omitempty"`
+	// VEXDocument is the path of the VEX document that the vulnerability was suppressed by,
+	// if it was not ignored by the config
+	VEXDocument string `json:"vex_document,omitempty"`
 }
 
 type GroupInfo struct {
@@ -124,6 +224,41 @@ type GroupInfo struct {
 	// Map of Vulnerability IDs to AnalysisInfo
 	ExperimentalAnalysis map[string]AnalysisInfo `json:"experimental_analysis,omitempty"`
 	MaxSeverity          string                  `json:"max_severity"`
+	// FixedVersion is the lowest version of the package that fixes all of the vulnerabilities,
+	// or "No fix available" if none of them have been fixed yet
+	FixedVersion string `json:"fixed_version,omitempty"`
+	// ExperimentalEPSS is the EPSS score of the CVE of the group that is most likely to be exploited,
+	// which is only set if EPSS scores were requested and one of the group's aliases has a score
+	ExperimentalEPSS *EPSSScore `json:"experimental_epss,omitempty"`
+	// ExperimentalKEV is the entry of one of the group's CVE aliases in CISA's Known Exploited Vulnerabilities
+	// catalog, which is only set if KEV flagging was requested and the group is known to be exploited
+	ExperimentalKEV *KEVEntry `json:"experimental_kev,omitempty"`
+}
+
+// KEVEntry is an entry of CISA's Known Exploited Vulnerabilities catalog,
+// from https://www.cisa.gov/known-exploited-vulnerabilities-catalog
+type KEVEntry struct {
+	CVE               string `json:"cve"`
+	VulnerabilityName string `json:"vulnerability_name,omitempty"`
+	// DateAdded is when the CVE was added to the catalog, as YYYY-MM-DD
+	DateAdded string `json:"date_added"`
+	// DueDate is when US federal agencies are required to have remediated the CVE by, as YYYY-MM-DD
+	DueDate                    string `json:"due_date,omitempty"`
+	KnownRansomwareCampaignUse bool   `json:"known_ransomware_campaign_use"`
+}
+
+// EPSSScore is the Exploit Prediction Scoring System score of a CVE, from https://www.first.org/epss/
+type EPSSScore struct {
+	CVE string `json:"cve"`
+	// Probability is the likelihood of the CVE being exploited in the next 30 days, from 0 to 1
+	Probability float64 `json:"probability"`
+	// Percentile is the proportion of all scored CVEs whose probability is equal to or lower than this one's
+	Percentile float64 `json:"percentile"`
+}
+
+// IsKnownExploited returns true if the group is in CISA's Known Exploited Vulnerabilities catalog
+func (groupInfo *GroupInfo) IsKnownExploited() bool {
+	return groupInfo.ExperimentalKEV != nil
 }
 
 // IsCalled returns true if any analysis performed determines that the vulnerability is being called
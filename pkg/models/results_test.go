@@ -121,3 +121,43 @@ func TestFlatten(t *testing.T) {
 		t.Errorf("Flatten() returned unexpected result (-want +got):\n%s", diff)
 	}
 }
+
+func TestAggregate(t *testing.T) {
+	t.Parallel()
+
+	// When there are no vulnerabilities
+	vulns := models.VulnerabilityResults{Results: []models.PackageSource{}}
+	if diff := cmp.Diff([]models.AggregatedFinding{}, vulns.Aggregate()); diff != "" {
+		t.Errorf("Aggregate() returned unexpected result (-want +got):\n%s", diff)
+	}
+
+	// When the same package version has the same vulnerability in multiple sources
+	group := models.GroupInfo{IDs: []string{"CVE-2021-1234"}}
+	otherGroup := models.GroupInfo{IDs: []string{"CVE-2022-5678"}}
+	pkg := models.PackageVulns{
+		Package: models.PackageInfo{Name: "package", Version: "1.0.0", Ecosystem: "npm"},
+		Groups:  []models.GroupInfo{group},
+	}
+	otherVersion := models.PackageVulns{
+		Package: models.PackageInfo{Name: "package", Version: "2.0.0", Ecosystem: "npm"},
+		Groups:  []models.GroupInfo{group, otherGroup},
+	}
+	first := models.SourceInfo{Path: "first/package-lock.json", Type: "lockfile"}
+	second := models.SourceInfo{Path: "second/package-lock.json", Type: "lockfile"}
+	third := models.SourceInfo{Path: "third/package-lock.json", Type: "lockfile"}
+	vulns = models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{Source: first, Packages: []models.PackageVulns{pkg}},
+			{Source: second, Packages: []models.PackageVulns{otherVersion}},
+			{Source: third, Packages: []models.PackageVulns{pkg, otherVersion}},
+		},
+	}
+	expected := []models.AggregatedFinding{
+		{Package: pkg.Package, GroupInfo: group, Sources: []models.SourceInfo{first, third}},
+		{Package: otherVersion.Package, GroupInfo: group, Sources: []models.SourceInfo{second, third}},
+		{Package: otherVersion.Package, GroupInfo: otherGroup, Sources: []models.SourceInfo{second, third}},
+	}
+	if diff := cmp.Diff(expected, vulns.Aggregate()); diff != "" {
+		t.Errorf("Aggregate() returned unexpected result (-want +got):\n%s", diff)
+	}
+}
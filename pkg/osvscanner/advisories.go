@@ -0,0 +1,174 @@
+package osvscanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/cpematcher"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/imodels/ecosystem"
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/internal/utility/vulns"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// readExtraAdvisories reads the OSV advisories at the given paths, which are either advisory
+// files or directories whose JSON files (including those in any subdirectories) are advisories
+func readExtraAdvisories(paths []string) ([]osvschema.Vulnerability, error) {
+	var advisories []osvschema.Vulnerability
+	seen := make(map[string]string)
+
+	add := func(path string) error {
+		advisory, err := readAdvisory(path)
+		if err != nil {
+			return err
+		}
+
+		if other, ok := seen[advisory.ID]; ok {
+			return fmt.Errorf("advisory %s is in both %s and %s", advisory.ID, other, path)
+		}
+		seen[advisory.ID] = path
+		advisories = append(advisories, advisory)
+
+		return nil
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read advisories: %w", err)
+		}
+
+		if !info.IsDir() {
+			if err := add(path); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !strings.EqualFold(filepath.Ext(p), ".json") {
+				return nil
+			}
+
+			return add(p)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(paths) > 0 {
+		slog.Info(fmt.Sprintf("Loaded %d extra %s", len(advisories), output.Form(len(advisories), "advisory", "advisories")))
+	}
+
+	return advisories, nil
+}
+
+// readAdvisory reads the OSV advisory at the path, making sure that it can be matched against packages
+func readAdvisory(path string) (osvschema.Vulnerability, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return osvschema.Vulnerability{}, fmt.Errorf("failed to read advisory: %w", err)
+	}
+
+	var advisory osvschema.Vulnerability
+	if err := json.Unmarshal(content, &advisory); err != nil {
+		return osvschema.Vulnerability{}, fmt.Errorf("failed to parse advisory %s, which must be in the OSV format: %w", path, err)
+	}
+
+	if advisory.ID == "" {
+		return osvschema.Vulnerability{}, fmt.Errorf("advisory %s does not have an id", path)
+	}
+
+	for _, affected := range advisory.Affected {
+		// packages that are only identified by CPE are matched against the CPEs of the affected entry instead
+		if affected.Package.Ecosystem == "" && affected.Package.Name == "" && len(cpematcher.AffectedCPEs(affected)) > 0 {
+			continue
+		}
+		if affected.Package.Ecosystem == "" || affected.Package.Name == "" {
+			return osvschema.Vulnerability{}, fmt.Errorf("advisory %s affects a package without an ecosystem and name, or %s in its database_specific field", advisory.ID, cpematcher.CPEsKey)
+		}
+		if _, err := ecosystem.Parse(affected.Package.Ecosystem); err != nil {
+			return osvschema.Vulnerability{}, fmt.Errorf("advisory %s affects a package with an invalid ecosystem: %w", advisory.ID, err)
+		}
+	}
+
+	return advisory, nil
+}
+
+// matchExtraAdvisories adds the advisories that affect each package to its vulnerabilities,
+// unless the package has already been matched against a vulnerability with the same ID
+func matchExtraAdvisories(packages []imodels.PackageScanResult, advisories []osvschema.Vulnerability) {
+	for i := range packages {
+		for _, advisory := range advisories {
+			if vulns.IsAffected(advisory, packages[i].PackageInfo) && !vulns.Include(packages[i].Vulnerabilities, advisory) {
+				packages[i].Vulnerabilities = append(packages[i].Vulnerabilities, &advisory)
+			}
+		}
+	}
+}
+
+// matchCPEOnlyPackages matches the packages that are only identified by CPE against the extra advisories
+// that list the CPEs of the products they affect, as OSV does not index vulnerabilities by CPE.
+//
+// The packages are returned so that they can be added to the results, unless none of the advisories
+// list CPEs, in which case there is nothing to match them against and nil is returned instead
+func matchCPEOnlyPackages(
+	ctx context.Context,
+	packages []imodels.PackageScanResult,
+	advisories []osvschema.Vulnerability,
+	includeWithdrawn bool,
+) ([]imodels.PackageScanResult, error) {
+	if len(packages) == 0 {
+		return nil, nil
+	}
+
+	if !includeWithdrawn {
+		now := time.Now()
+		advisories = slices.DeleteFunc(slices.Clone(advisories), func(advisory osvschema.Vulnerability) bool {
+			return isWithdrawn(&advisory, now)
+		})
+	}
+
+	matcher := cpematcher.New(advisories)
+	if !matcher.HasAdvisories() {
+		var cpes []string
+		for _, psr := range packages {
+			for _, cpe := range psr.PackageInfo.CPEs() {
+				if !slices.Contains(cpes, cpe) {
+					cpes = append(cpes, cpe)
+				}
+			}
+		}
+
+		slog.Warn(fmt.Sprintf(
+			"%d SBOM %s only identified by CPE and could not be scanned, as OSV does not index vulnerabilities by CPE and none of the --extra-advisories list CPEs: %s",
+			len(packages),
+			output.Form(len(packages), "package is", "packages are"),
+			strings.Join(cpes, ", "),
+		))
+
+		return nil, nil
+	}
+
+	slog.Info(fmt.Sprintf("Matching %d SBOM %s only identified by CPE against the extra advisories", len(packages), output.Form(len(packages), "package", "packages")))
+
+	if err := makeVulnRequestWithMatcher(ctx, packages, matcher); err != nil {
+		return nil, err
+	}
+
+	return packages, nil
+}
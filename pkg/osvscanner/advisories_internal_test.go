@@ -0,0 +1,203 @@
+package osvscanner
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/sbom/cdx"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/ecosystemmock"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+func advisoryIDs(advisories []osvschema.Vulnerability) []string {
+	ids := make([]string, 0, len(advisories))
+	for _, advisory := range advisories {
+		ids = append(ids, advisory.ID)
+	}
+
+	return ids
+}
+
+func Test_readExtraAdvisories(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		paths   []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "no paths",
+			paths: nil,
+			want:  []string{},
+		},
+		{
+			name:  "directory",
+			paths: []string{filepath.Join("fixtures", "extra-advisories")},
+			want:  []string{"ACME-2024-0001", "ACME-2024-0002"},
+		},
+		{
+			name:  "file",
+			paths: []string{filepath.Join("fixtures", "extra-advisories", "nested", "ACME-2024-0002.json")},
+			want:  []string{"ACME-2024-0002"},
+		},
+		{
+			name: "same advisory more than once",
+			paths: []string{
+				filepath.Join("fixtures", "extra-advisories"),
+				filepath.Join("fixtures", "extra-advisories", "ACME-2024-0001.json"),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "affected by cpe",
+			paths: []string{filepath.Join("fixtures", "extra-advisories-cpe")},
+			want:  []string{"ACME-2024-0003"},
+		},
+		{
+			name:    "invalid ecosystem",
+			paths:   []string{filepath.Join("fixtures", "extra-advisories-invalid")},
+			wantErr: true,
+		},
+		{
+			name:    "not an advisory",
+			paths:   []string{filepath.Join("fixtures", "extra-advisories", "README.md")},
+			wantErr: true,
+		},
+		{
+			name:    "does not exist",
+			paths:   []string{filepath.Join("fixtures", "does-not-exist")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := readExtraAdvisories(tt.paths)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readExtraAdvisories() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, advisoryIDs(got)); diff != "" {
+				t.Errorf("readExtraAdvisories() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_matchExtraAdvisories(t *testing.T) {
+	t.Parallel()
+
+	advisories, err := readExtraAdvisories([]string{filepath.Join("fixtures", "extra-advisories")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	newPackage := func(name string, version string, eco string, vulns ...*osvschema.Vulnerability) imodels.PackageScanResult {
+		return imodels.PackageScanResult{
+			PackageInfo: imodels.FromInventory(&extractor.Inventory{
+				Name:      name,
+				Version:   version,
+				Extractor: ecosystemmock.Extractor{MockEcosystem: eco},
+			}),
+			Vulnerabilities: vulns,
+		}
+	}
+
+	packages := []imodels.PackageScanResult{
+		newPackage("@acme/utils", "2.0.3", "npm", &osvschema.Vulnerability{ID: "GHSA-public"}),
+		newPackage("@acme/utils", "2.1.0", "npm"),
+		newPackage("acme-client", "1.0.1", "PyPI"),
+		newPackage("acme_client", "1.0.0", "PyPI", &osvschema.Vulnerability{ID: "ACME-2024-0002"}),
+		newPackage("acme-client", "1.0.1", "npm"),
+	}
+
+	matchExtraAdvisories(packages, advisories)
+
+	want := [][]string{
+		{"GHSA-public", "ACME-2024-0001"},
+		{},
+		{"ACME-2024-0002"},
+		// the advisory is not added again, even if the matcher has already matched it
+		{"ACME-2024-0002"},
+		{},
+	}
+
+	got := make([][]string, 0, len(packages))
+	for _, psr := range packages {
+		ids := []string{}
+		for _, vuln := range psr.Vulnerabilities {
+			ids = append(ids, vuln.ID)
+		}
+		got = append(got, ids)
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("matchExtraAdvisories() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_matchCPEOnlyPackages(t *testing.T) {
+	t.Parallel()
+
+	newPackage := func(cpe string) imodels.PackageScanResult {
+		return imodels.PackageScanResult{
+			PackageInfo: imodels.FromInventory(&extractor.Inventory{
+				Name:      cpe,
+				Metadata:  &cdx.Metadata{CPEs: []string{cpe}},
+				Extractor: cdx.Extractor{},
+			}),
+		}
+	}
+
+	advisories, err := readExtraAdvisories([]string{
+		filepath.Join("fixtures", "extra-advisories"),
+		filepath.Join("fixtures", "extra-advisories-cpe"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	packages := []imodels.PackageScanResult{
+		newPackage("cpe:2.3:a:acme:router_firmware:2.9.1:*:*:*:*:*:*:*"),
+		newPackage("cpe:2.3:a:acme:router_firmware:2.10.0:*:*:*:*:*:*:*"),
+	}
+
+	got, err := matchCPEOnlyPackages(context.Background(), packages, advisories, false)
+	if err != nil {
+		t.Fatalf("matchCPEOnlyPackages() error = %v", err)
+	}
+
+	want := [][]string{{"ACME-2024-0003"}, {}}
+	gotIDs := make([][]string, 0, len(got))
+	for _, psr := range got {
+		ids := []string{}
+		for _, vuln := range psr.Vulnerabilities {
+			ids = append(ids, vuln.ID)
+		}
+		gotIDs = append(gotIDs, ids)
+	}
+
+	if diff := cmp.Diff(want, gotIDs); diff != "" {
+		t.Errorf("matchCPEOnlyPackages() mismatch (-want +got):\n%s", diff)
+	}
+
+	// without any advisories that list cpes, there is nothing to match the packages against
+	got, err = matchCPEOnlyPackages(context.Background(), packages, advisories[:2], false)
+	if err != nil {
+		t.Fatalf("matchCPEOnlyPackages() error = %v", err)
+	}
+	if got != nil {
+		t.Errorf("matchCPEOnlyPackages() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,260 @@
+package osvscanner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/output"
+)
+
+// lockfileManifests maps the names of lockfiles to the name of the manifest
+// beside them that declares the direct dependencies that they lock, along
+// with a function to read the names of those dependencies.
+//
+// Go modules are not included, as go.mod records the versions that are used itself
+// and go.sum only has checksums, which are not scanned.
+var lockfileManifests = map[string]struct {
+	name string
+	read func(path string) ([]string, error)
+}{
+	"package-lock.json": {"package.json", readPackageJSONDependencies},
+	"yarn.lock":         {"package.json", readPackageJSONDependencies},
+	"pnpm-lock.yaml":    {"package.json", readPackageJSONDependencies},
+	"bun.lock":          {"package.json", readPackageJSONDependencies},
+	"Cargo.lock":        {"Cargo.toml", readCargoTomlDependencies},
+}
+
+// lockfileDrift is the difference between the dependencies that a manifest declares
+// and those that the lockfile beside it has locked
+type lockfileDrift struct {
+	Lockfile string
+	Manifest string
+	// Unlocked are the dependencies that the manifest declares, but are not in the lockfile
+	Unlocked []string
+	// Undeclared are the dependencies that the lockfile records as being declared by the
+	// manifest, but which the manifest no longer declares
+	Undeclared []string
+}
+
+// checkLockfileDrift compares the packages of each lockfile that was scanned against the
+// dependencies declared by the manifest beside it, returning the lockfiles that are out of date
+func checkLockfileDrift(packages []imodels.PackageScanResult) []lockfileDrift {
+	locked := make(map[string]map[string]bool)
+	for _, psr := range packages {
+		inv := psr.PackageInfo.Inventory
+		if len(inv.Locations) == 0 {
+			continue
+		}
+
+		lockfile := inv.Locations[0]
+		if _, ok := lockfileManifests[filepath.Base(lockfile)]; !ok {
+			continue
+		}
+
+		if locked[lockfile] == nil {
+			locked[lockfile] = make(map[string]bool)
+		}
+		locked[lockfile][inv.Name] = true
+	}
+
+	var drifts []lockfileDrift
+	for _, lockfile := range slices.Sorted(maps.Keys(locked)) {
+		manifest := lockfileManifests[filepath.Base(lockfile)]
+		manifestPath := filepath.Join(filepath.Dir(lockfile), manifest.name)
+
+		declared, err := manifest.read(manifestPath)
+		if err != nil {
+			// lockfiles are often committed without their manifest (e.g. when vendored),
+			// and lockfiles within archives do not have a manifest beside them on disk
+			if !errors.Is(err, fs.ErrNotExist) {
+				slog.Warn(fmt.Sprintf("Could not check %s for drift from %s: %v", lockfile, manifestPath, err))
+			}
+
+			continue
+		}
+
+		drift := lockfileDrift{Lockfile: lockfile, Manifest: manifestPath}
+		for _, name := range declared {
+			if !locked[lockfile][name] {
+				drift.Unlocked = append(drift.Unlocked, name)
+			}
+		}
+
+		// only npm lockfiles record the dependencies of the manifest they were generated from,
+		// so for other lockfiles it is not known if they have dependencies that were removed
+		if filepath.Base(lockfile) == "package-lock.json" {
+			lockedDeclared, err := readPackageLockRootDependencies(lockfile)
+			if err == nil {
+				for _, name := range lockedDeclared {
+					if !slices.Contains(declared, name) {
+						drift.Undeclared = append(drift.Undeclared, name)
+					}
+				}
+			}
+		}
+
+		if len(drift.Unlocked) > 0 || len(drift.Undeclared) > 0 {
+			drifts = append(drifts, drift)
+		}
+	}
+
+	return drifts
+}
+
+// reportLockfileDrift warns about each lockfile that is out of date with its manifest,
+// as the packages that were scanned are not what would be installed from the manifest
+func reportLockfileDrift(drifts []lockfileDrift) {
+	for _, drift := range drifts {
+		var problems []string
+		if len(drift.Unlocked) > 0 {
+			problems = append(problems, fmt.Sprintf(
+				"%d %s declared in the manifest but not locked (%s)",
+				len(drift.Unlocked),
+				output.Form(len(drift.Unlocked), "dependency is", "dependencies are"),
+				strings.Join(drift.Unlocked, ", "),
+			))
+		}
+		if len(drift.Undeclared) > 0 {
+			problems = append(problems, fmt.Sprintf(
+				"%d locked %s no longer declared in the manifest (%s)",
+				len(drift.Undeclared),
+				output.Form(len(drift.Undeclared), "dependency is", "dependencies are"),
+				strings.Join(drift.Undeclared, ", "),
+			))
+		}
+
+		slog.Warn(fmt.Sprintf(
+			"%s is out of date with %s, so might not reflect what will be installed: %s",
+			drift.Lockfile,
+			drift.Manifest,
+			strings.Join(problems, "; "),
+		))
+	}
+}
+
+// npmDependencySections are the sections of a package.json with dependencies that are installed
+var npmDependencySections = []string{"dependencies", "devDependencies", "optionalDependencies"}
+
+// readPackageJSONDependencies reads the names of the dependencies declared by a package.json,
+// skipping those that are local to the project or aliases of another package, as neither are
+// locked under the name that they are declared with
+func readPackageJSONDependencies(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest map[string]json.RawMessage
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, err
+	}
+
+	return npmDependencyNames(manifest)
+}
+
+// readPackageLockRootDependencies reads the names of the dependencies that a package-lock.json
+// records the package.json as declaring when it was generated, which is only done from v2
+func readPackageLockRootDependencies(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var lockfile struct {
+		Packages map[string]map[string]json.RawMessage `json:"packages"`
+	}
+	if err := json.Unmarshal(content, &lockfile); err != nil {
+		return nil, err
+	}
+
+	root, ok := lockfile.Packages[""]
+	if !ok {
+		return nil, nil
+	}
+
+	return npmDependencyNames(root)
+}
+
+func npmDependencyNames(pkg map[string]json.RawMessage) ([]string, error) {
+	seen := make(map[string]bool)
+	for _, section := range npmDependencySections {
+		raw, ok := pkg[section]
+		if !ok {
+			continue
+		}
+
+		var deps map[string]string
+		if err := json.Unmarshal(raw, &deps); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", section, err)
+		}
+
+		for name, spec := range deps {
+			if !isLocalNpmSpec(spec) {
+				seen[name] = true
+			}
+		}
+	}
+
+	return slices.Sorted(maps.Keys(seen)), nil
+}
+
+func isLocalNpmSpec(spec string) bool {
+	for _, prefix := range []string{"file:", "link:", "workspace:", "portal:", "npm:"} {
+		if strings.HasPrefix(spec, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readCargoTomlDependencies reads the names of the crates that a Cargo.toml depends on,
+// skipping path dependencies as they are part of the project
+func readCargoTomlDependencies(path string) ([]string, error) {
+	type dependencies struct {
+		Dependencies      map[string]any `toml:"dependencies"`
+		DevDependencies   map[string]any `toml:"dev-dependencies"`
+		BuildDependencies map[string]any `toml:"build-dependencies"`
+	}
+	var manifest struct {
+		dependencies
+		Target map[string]dependencies `toml:"target"`
+	}
+
+	if _, err := toml.DecodeFile(path, &manifest); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	add := func(deps map[string]any) {
+		for name, dep := range deps {
+			if table, ok := dep.(map[string]any); ok {
+				if _, isPath := table["path"]; isPath {
+					continue
+				}
+				if pkg, ok := table["package"].(string); ok {
+					name = pkg
+				}
+			}
+			seen[name] = true
+		}
+	}
+
+	for _, deps := range append([]dependencies{manifest.dependencies}, slices.Collect(maps.Values(manifest.Target))...) {
+		add(deps.Dependencies)
+		add(deps.DevDependencies)
+		add(deps.BuildDependencies)
+	}
+
+	return slices.Sorted(maps.Keys(seen)), nil
+}
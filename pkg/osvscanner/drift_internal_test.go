@@ -0,0 +1,63 @@
+package osvscanner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/ecosystemmock"
+)
+
+func Test_checkLockfileDrift(t *testing.T) {
+	t.Parallel()
+
+	newPackage := func(name string, lockfile string) imodels.PackageScanResult {
+		return imodels.PackageScanResult{
+			PackageInfo: imodels.FromInventory(&extractor.Inventory{
+				Name:      name,
+				Version:   "1.0.0",
+				Locations: []string{lockfile},
+				Extractor: ecosystemmock.Extractor{MockEcosystem: "npm"},
+			}),
+		}
+	}
+
+	npmLockfile := filepath.Join("fixtures", "lockfile-drift", "npm", "package-lock.json")
+	cargoLockfile := filepath.Join("fixtures", "lockfile-drift", "cargo", "Cargo.lock")
+	inSyncLockfile := filepath.Join("fixtures", "lockfile-drift", "in-sync", "package-lock.json")
+	noManifestLockfile := filepath.Join("fixtures", "lockfile-drift", "no-manifest", "package-lock.json")
+	requirementsTxt := filepath.Join("fixtures", "lockfile-drift", "npm", "requirements.txt")
+
+	packages := []imodels.PackageScanResult{
+		newPackage("express", npmLockfile),
+		newPackage("minimist", npmLockfile),
+		newPackage("my-crate", cargoLockfile),
+		newPackage("serde", cargoLockfile),
+		newPackage("serde_json", cargoLockfile),
+		newPackage("express", inSyncLockfile),
+		newPackage("express", noManifestLockfile),
+		newPackage("requests", requirementsTxt),
+	}
+
+	want := []lockfileDrift{
+		{
+			Lockfile: cargoLockfile,
+			Manifest: filepath.Join("fixtures", "lockfile-drift", "cargo", "Cargo.toml"),
+			Unlocked: []string{"libc", "tempfile"},
+		},
+		{
+			Lockfile:   npmLockfile,
+			Manifest:   filepath.Join("fixtures", "lockfile-drift", "npm", "package.json"),
+			Unlocked:   []string{"jest", "lodash"},
+			Undeclared: []string{"minimist"},
+		},
+	}
+
+	got := checkLockfileDrift(packages)
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("checkLockfileDrift() mismatch (-want +got):\n%s", diff)
+	}
+}
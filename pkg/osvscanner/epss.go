@@ -0,0 +1,58 @@
+package osvscanner
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/v2/internal/clients/clientinterfaces"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+// enrichEPSS sets the EPSS score of each group of vulnerabilities in the results to the score of the
+// CVE among its aliases that is most likely to be exploited, leaving groups without a CVE unscored
+func enrichEPSS(ctx context.Context, results *models.VulnerabilityResults, matcher clientinterfaces.EPSSMatcher) error {
+	cves := groupCVEs(results)
+	if len(cves) == 0 {
+		return nil
+	}
+
+	scores, err := matcher.MatchEPSS(ctx, cves)
+	if err != nil {
+		return err
+	}
+
+	for i, pkgSrc := range results.Results {
+		for j, pkg := range pkgSrc.Packages {
+			for k, group := range pkg.Groups {
+				var best *models.EPSSScore
+				for _, alias := range group.Aliases {
+					if score, ok := scores[alias]; ok && (best == nil || score.Probability > best.Probability) {
+						best = &score
+					}
+				}
+				results.Results[i].Packages[j].Groups[k].ExperimentalEPSS = best
+			}
+		}
+	}
+
+	return nil
+}
+
+// groupCVEs returns the CVEs that are aliases of the groups of vulnerabilities in the results
+func groupCVEs(results *models.VulnerabilityResults) []string {
+	var cves []string
+	for _, pkgSrc := range results.Results {
+		for _, pkg := range pkgSrc.Packages {
+			for _, group := range pkg.Groups {
+				for _, alias := range group.Aliases {
+					if strings.HasPrefix(alias, "CVE-") && !slices.Contains(cves, alias) {
+						cves = append(cves, alias)
+					}
+				}
+			}
+		}
+	}
+
+	return cves
+}
@@ -0,0 +1,104 @@
+package osvscanner
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+// staticEPSSMatcher returns the scores it has for the CVEs, recording which were requested
+type staticEPSSMatcher struct {
+	scores    map[string]models.EPSSScore
+	requested *[]string
+}
+
+func (m staticEPSSMatcher) MatchEPSS(_ context.Context, cves []string) (map[string]models.EPSSScore, error) {
+	*m.requested = append(*m.requested, cves...)
+
+	results := make(map[string]models.EPSSScore)
+	for _, cve := range cves {
+		if score, ok := m.scores[cve]; ok {
+			results[cve] = score
+		}
+	}
+
+	return results, nil
+}
+
+func Test_enrichEPSS(t *testing.T) {
+	t.Parallel()
+
+	low := models.EPSSScore{CVE: "CVE-2021-0001", Probability: 0.01, Percentile: 0.5}
+	high := models.EPSSScore{CVE: "CVE-2021-0002", Probability: 0.6, Percentile: 0.98}
+
+	results := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "path/to/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package: models.PackageInfo{Name: "mine", Version: "1.0.0", Ecosystem: "npm"},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"GHSA-1"}, Aliases: []string{"CVE-2021-0001", "CVE-2021-0002", "GHSA-1"}},
+							{IDs: []string{"GHSA-2"}, Aliases: []string{"GHSA-2"}},
+							{IDs: []string{"CVE-2021-0003"}, Aliases: []string{"CVE-2021-0003"}},
+						},
+					},
+					{
+						Package: models.PackageInfo{Name: "other", Version: "1.0.0", Ecosystem: "npm"},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"CVE-2021-0001"}, Aliases: []string{"CVE-2021-0001"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var requested []string
+	matcher := staticEPSSMatcher{
+		scores:    map[string]models.EPSSScore{low.CVE: low, high.CVE: high},
+		requested: &requested,
+	}
+
+	if err := enrichEPSS(context.Background(), &results, matcher); err != nil {
+		t.Fatalf("enrichEPSS() error = %v", err)
+	}
+
+	slices.Sort(requested)
+	if diff := cmp.Diff([]string{"CVE-2021-0001", "CVE-2021-0002", "CVE-2021-0003"}, requested); diff != "" {
+		t.Errorf("enrichEPSS() requested CVEs mismatch (-want +got):\n%s", diff)
+	}
+
+	var got []*models.EPSSScore
+	for _, pkg := range results.Results[0].Packages {
+		for _, group := range pkg.Groups {
+			got = append(got, group.ExperimentalEPSS)
+		}
+	}
+
+	// the most likely to be exploited CVE of each group is used, and groups without a scored CVE are left alone
+	want := []*models.EPSSScore{&high, nil, nil, &low}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("enrichEPSS() scores mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDoContainerScan_OfflineEPSS(t *testing.T) {
+	t.Parallel()
+
+	_, err := DoContainerScan(ScannerActions{
+		Image: "alpine:3.20",
+		ExperimentalScannerActions: ExperimentalScannerActions{
+			CompareOffline: true,
+			MinEPSS:        0.1,
+		},
+	})
+
+	if err == nil || err.Error() != "cannot retrieve EPSS scores locally" {
+		t.Errorf("DoContainerScan() error = %v, want an error about EPSS scores being unavailable offline", err)
+	}
+}
@@ -0,0 +1,155 @@
+package osvscanner
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scanner/v2/pkg/osvscanner/internal/scanners"
+)
+
+// RegisteredExtractor is an extractor registered with RegisterExtractor
+type RegisteredExtractor struct {
+	// Glob is matched against the name of each file, such as "*.deps.lock",
+	// to determine if the file should be extracted by the Extractor
+	Glob      string
+	Extractor filesystem.Extractor
+}
+
+// ExtractorInfo describes an extractor that is used when scanning lockfiles, SBOMs and directories
+type ExtractorInfo struct {
+	Name string
+	// Glob is the glob the extractor was registered for, which is empty for built-in
+	// extractors as they determine the files they extract themselves
+	Glob string
+	// Builtin is whether the extractor is part of osv-scanner, rather than having
+	// been registered with RegisterExtractor
+	Builtin bool
+}
+
+var registry struct {
+	sync.RWMutex
+	extractors []RegisteredExtractor
+}
+
+// RegisterExtractor registers an extractor for a custom format, so that files with a name
+// matching glob are extracted by it when scanning lockfiles, SBOMs and directories. The extractor
+// can also be requested for lockfiles with `-L <name>:<path>`, using the name of the extractor.
+//
+// Extractors must return inventories that can be matched against OSV, meaning:
+//   - Ecosystem must return the OSV ecosystem of the inventory (e.g. "npm" or "Debian:12")
+//   - the name and version of each inventory must be normalized like they are in the
+//     advisories of the ecosystem, such as using "group:artifact" for Maven packages
+//   - Name must be unique, as it is used to identify the extractor in the output
+//
+// Registering an extractor with the same name as a built-in extractor overrides the built-in
+// extractor, and registering one with the same name as another registered extractor replaces it.
+//
+// An error is returned if the glob is malformed.
+func RegisterExtractor(glob string, ext filesystem.Extractor) error {
+	if _, err := filepath.Match(glob, ""); err != nil {
+		return fmt.Errorf("invalid glob %q for extractor %s: %w", glob, ext.Name(), err)
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.extractors = slices.DeleteFunc(registry.extractors, func(r RegisteredExtractor) bool {
+		return r.Extractor.Name() == ext.Name()
+	})
+	registry.extractors = append(registry.extractors, RegisteredExtractor{Glob: glob, Extractor: ext})
+
+	return nil
+}
+
+// RegisteredExtractors returns the extractors registered with RegisterExtractor,
+// in the order they were registered
+func RegisteredExtractors() []RegisteredExtractor {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return slices.Clone(registry.extractors)
+}
+
+// ListExtractors lists the extractors that are used when scanning lockfiles, SBOMs and directories,
+// including those that have been registered in place of the built-in extractors.
+//
+// Extractors that need clients to be created are not listed, which are the vendored code
+// extractor and the pom.xml extractor that resolves transitive dependencies (used instead
+// of the pom.xml extractor that is listed, unless --no-resolve is set).
+func ListExtractors() []ExtractorInfo {
+	//nolint:prealloc // The number of built-in extractors that have been overridden is not known
+	var infos []ExtractorInfo
+
+	builtin := slices.Concat(
+		scanners.BuildLockfileExtractors(nil, nil),
+		scanners.BuildSBOMExtractors(),
+		scanners.BuildWalkerExtractors(true, nil, nil, nil),
+	)
+
+	registered := RegisteredExtractors()
+	listed := make(map[string]bool, len(builtin))
+	for _, ext := range builtin {
+		if listed[ext.Name()] {
+			continue
+		}
+		listed[ext.Name()] = true
+
+		if slices.ContainsFunc(registered, func(r RegisteredExtractor) bool { return r.Extractor.Name() == ext.Name() }) {
+			continue
+		}
+		infos = append(infos, ExtractorInfo{Name: ext.Name(), Builtin: true})
+	}
+
+	for _, r := range registered {
+		infos = append(infos, ExtractorInfo{Name: r.Extractor.Name(), Glob: r.Glob})
+	}
+
+	return infos
+}
+
+// globExtractor extracts the files with names matching its glob, regardless of
+// which files the extractor it wraps would otherwise require
+type globExtractor struct {
+	filesystem.Extractor
+
+	glob string
+}
+
+func (e globExtractor) FileRequired(api filesystem.FileAPI) bool {
+	matched, _ := filepath.Match(e.glob, filepath.Base(api.Path()))
+
+	return matched
+}
+
+// withRegisteredExtractors returns the extractors with any that have been overridden replaced
+// by the registered extractor of the same name, followed by the rest of the registered extractors
+func withRegisteredExtractors(extractors []filesystem.Extractor) []filesystem.Extractor {
+	registered := RegisteredExtractors()
+	if len(registered) == 0 {
+		return extractors
+	}
+
+	result := make([]filesystem.Extractor, 0, len(extractors)+len(registered))
+	used := make(map[string]bool, len(registered))
+	for _, ext := range extractors {
+		i := slices.IndexFunc(registered, func(r RegisteredExtractor) bool { return r.Extractor.Name() == ext.Name() })
+		if i < 0 {
+			result = append(result, ext)
+			continue
+		}
+
+		result = append(result, globExtractor{Extractor: registered[i].Extractor, glob: registered[i].Glob})
+		used[ext.Name()] = true
+	}
+
+	for _, r := range registered {
+		if !used[r.Extractor.Name()] {
+			result = append(result, globExtractor{Extractor: r.Extractor, glob: r.Glob})
+		}
+	}
+
+	return result
+}
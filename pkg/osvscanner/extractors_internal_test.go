@@ -0,0 +1,138 @@
+package osvscanner
+
+import (
+	"bufio"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/haskell/stacklock"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/sbom/spdx"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/vcs/gitrepo"
+	"github.com/google/osv-scanner/v2/pkg/osvscanner/internal/scanners"
+)
+
+// customExtractor extracts npm packages from files with a "name@version" per line
+type customExtractor struct {
+	name string
+}
+
+func (e customExtractor) Name() string { return e.name }
+
+func (e customExtractor) Version() int { return 0 }
+
+func (e customExtractor) Requirements() *plugin.Capabilities { return &plugin.Capabilities{} }
+
+func (e customExtractor) FileRequired(_ filesystem.FileAPI) bool { return false }
+
+func (e customExtractor) Extract(_ context.Context, input *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	var invs []*extractor.Inventory
+
+	scanner := bufio.NewScanner(input.Reader)
+	for scanner.Scan() {
+		name, version, ok := strings.Cut(scanner.Text(), "@")
+		if !ok {
+			continue
+		}
+		invs = append(invs, &extractor.Inventory{Name: name, Version: version, Locations: []string{input.Path}})
+	}
+
+	return invs, scanner.Err()
+}
+
+func (e customExtractor) ToPURL(_ *extractor.Inventory) *purl.PackageURL { return nil }
+
+func (e customExtractor) Ecosystem(_ *extractor.Inventory) string { return "npm" }
+
+func TestRegisterExtractor(t *testing.T) {
+	t.Parallel()
+
+	if err := RegisterExtractor("*.custom", customExtractor{name: "custom/registered"}); err != nil {
+		t.Fatalf("RegisterExtractor() error = %v", err)
+	}
+
+	if err := RegisterExtractor("[", customExtractor{name: "custom/bad-glob"}); err == nil {
+		t.Errorf("RegisterExtractor() with a malformed glob did not return an error")
+	}
+
+	for _, actions := range []ScannerActions{
+		{DirectoryPaths: []string{"./fixtures/custom"}},
+		{LockfilePaths: []string{"./fixtures/custom/deps.custom"}},
+		{LockfilePaths: []string{"custom/registered:./fixtures/custom/deps.custom"}},
+		{SBOMPaths: []string{"./fixtures/custom/deps.custom"}},
+	} {
+		pkgs, _, err := scan(context.Background(), ExternalAccessors{}, actions)
+		if err != nil {
+			t.Fatalf("scan() error = %v", err)
+		}
+
+		got := make([]string, 0, len(pkgs))
+		for _, pkg := range pkgs {
+			got = append(got, pkg.PackageInfo.Ecosystem().String()+"/"+pkg.PackageInfo.Name()+"@"+pkg.PackageInfo.Version())
+		}
+		slices.Sort(got)
+
+		if diff := cmp.Diff([]string{"npm/left-pad@1.3.0", "npm/minimist@1.2.5"}, got); diff != "" {
+			t.Errorf("scan(%v) mismatch (-want +got):\n%s", actions, diff)
+		}
+	}
+}
+
+func TestListExtractors(t *testing.T) {
+	t.Parallel()
+
+	infos := ListExtractors()
+
+	for _, name := range []string{spdx.Extractor{}.Name(), gitrepo.Extractor{}.Name()} {
+		n := 0
+		for _, info := range infos {
+			if info.Name == name {
+				n++
+				if want := (ExtractorInfo{Name: name, Builtin: true}); info != want {
+					t.Errorf("ListExtractors() has %v, want %v", info, want)
+				}
+			}
+		}
+		if n != 1 {
+			t.Errorf("ListExtractors() has %s %d times, want once", name, n)
+		}
+	}
+}
+
+func TestRegisterExtractor_OverridesBuiltin(t *testing.T) {
+	t.Parallel()
+
+	if err := RegisterExtractor("stack.yaml.lock", customExtractor{name: stacklock.Name}); err != nil {
+		t.Fatalf("RegisterExtractor() error = %v", err)
+	}
+
+	infos := ListExtractors()
+
+	i := slices.IndexFunc(infos, func(info ExtractorInfo) bool { return info.Name == stacklock.Name })
+	if i < 0 {
+		t.Fatalf("ListExtractors() is missing %s", stacklock.Name)
+	}
+	if want := (ExtractorInfo{Name: stacklock.Name, Glob: "stack.yaml.lock"}); infos[i] != want {
+		t.Errorf("ListExtractors() has %v, want %v", infos[i], want)
+	}
+
+	extractors := withRegisteredExtractors(scanners.BuildLockfileExtractors(nil, nil))
+	n := 0
+	for _, ext := range extractors {
+		if ext.Name() == stacklock.Name {
+			n++
+			if _, ok := ext.(globExtractor); !ok {
+				t.Errorf("withRegisteredExtractors() did not override the built-in %s extractor", stacklock.Name)
+			}
+		}
+	}
+	if n != 1 {
+		t.Errorf("withRegisteredExtractors() has %d %s extractors, want 1", n, stacklock.Name)
+	}
+}
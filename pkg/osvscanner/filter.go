@@ -3,18 +3,34 @@ package osvscanner
 import (
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
+	"time"
 
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/javalockfile"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/pomxml"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/pomxmlnet"
 	"github.com/google/osv-scanner/v2/internal/config"
+	"github.com/google/osv-scanner/v2/internal/depgraph"
 	"github.com/google/osv-scanner/v2/internal/imodels"
 	"github.com/google/osv-scanner/v2/internal/imodels/results"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/dotnet/packageslockjson"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/java/mvndependencytree"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/rust/cargotoml"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/sbom/spdx"
+	depgroups "github.com/google/osv-scanner/v2/internal/utility/depgroup"
+	"github.com/google/osv-scanner/v2/internal/vex"
 	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/google/osv-scanner/v2/pkg/osvscanner/internal/scanners"
 	"github.com/ossf/osv-schema/bindings/go/osvschema"
 )
 
 // filterUnscannablePackages removes packages that don't have enough information to be scanned
-// e,g, local packages that specified by path
-func filterUnscannablePackages(scanResults *results.ScanResults) {
+// e,g, local packages that specified by path, returning the removed SBOM packages that are
+// only identified by CPE so that they can be matched separately
+func filterUnscannablePackages(scanResults *results.ScanResults) []imodels.PackageScanResult {
 	packageResults := make([]imodels.PackageScanResult, 0, len(scanResults.PackageScanResults))
+	var cpeOnly []imodels.PackageScanResult
 	for _, psr := range scanResults.PackageScanResults {
 		p := psr.PackageInfo
 
@@ -24,6 +40,10 @@ func filterUnscannablePackages(scanResults *results.ScanResults) {
 		case p.Commit() != "":
 		case p.Ecosystem().Ecosystem == osvschema.EcosystemMaven && p.Name() == "unknown":
 		default:
+			if len(p.CPEs()) > 0 {
+				cpeOnly = append(cpeOnly, psr)
+			}
+
 			continue
 		}
 
@@ -35,6 +55,8 @@ func filterUnscannablePackages(scanResults *results.ScanResults) {
 	}
 
 	scanResults.PackageScanResults = packageResults
+
+	return cpeOnly
 }
 
 // filterNonContainerRelevantPackages removes packages that are not relevant when doing container scanning
@@ -59,6 +81,102 @@ func filterNonContainerRelevantPackages(scanResults *results.ScanResults) {
 	scanResults.PackageScanResults = packageResults
 }
 
+// filterDevPackages removes packages that are only development dependencies,
+// based on the dependency groups that they were extracted with
+func filterDevPackages(scanResults *results.ScanResults) {
+	packageResults := make([]imodels.PackageScanResult, 0, len(scanResults.PackageScanResults))
+	for _, psr := range scanResults.PackageScanResults {
+		p := psr.PackageInfo
+
+		// packages that are also a non-dev dependency are never in a dev group,
+		// as extractors only put packages in the groups that they are exclusively in
+		if depgroups.IsDevGroup(p.Ecosystem().Ecosystem, p.DepGroups()) {
+			continue
+		}
+
+		packageResults = append(packageResults, psr)
+	}
+
+	if len(packageResults) != len(scanResults.PackageScanResults) {
+		slog.Info(fmt.Sprintf("Filtered %d development package/s from the scan.", len(scanResults.PackageScanResults)-len(packageResults)))
+	}
+
+	scanResults.PackageScanResults = packageResults
+}
+
+// isDirectDependency returns whether the package is a direct dependency of the project that
+// it was extracted from, along with false if that cannot be determined from its metadata or
+// the dependency graph of its lockfile
+func isDirectDependency(graphs map[string]*depgraph.Graph, p imodels.PackageInfo) (bool, bool) {
+	// other Java extractors use the same metadata without setting IsTransitive
+	setsIsTransitive := p.Inventory.Extractor != nil && slices.Contains(
+		[]string{pomxml.Name, pomxmlnet.Name, mvndependencytree.Name},
+		p.Inventory.Extractor.Name(),
+	)
+
+	switch m := p.Inventory.Metadata.(type) {
+	case *spdx.Metadata:
+		return !m.IsTransitive, true
+	case *packageslockjson.Metadata:
+		return !m.IsTransitive, true
+	case javalockfile.Metadata:
+		if setsIsTransitive {
+			return !m.IsTransitive, true
+		}
+	case *javalockfile.Metadata:
+		if setsIsTransitive {
+			return !m.IsTransitive, true
+		}
+	case cargotoml.Metadata:
+		// manifests only have the packages that the project itself depends on
+		return true, true
+	}
+
+	if g := dependencyGraph(graphs, p); g != nil {
+		if path := g.ShortestPath(depgraph.Package{Name: p.Name(), Version: p.Version()}); path != nil {
+			return len(path) == 1, true
+		}
+	}
+
+	return false, false
+}
+
+// filterPackagesByDirectness removes packages that are transitive dependencies if direct is true,
+// or that are direct dependencies otherwise. Packages that cannot be determined to be either
+// are kept, as they could be vulnerable in a way that matters either way.
+func filterPackagesByDirectness(scanResults *results.ScanResults, direct bool) {
+	graphs := make(map[string]*depgraph.Graph)
+	undetermined := make(map[string]int)
+
+	packageResults := make([]imodels.PackageScanResult, 0, len(scanResults.PackageScanResults))
+	for _, psr := range scanResults.PackageScanResults {
+		isDirect, ok := isDirectDependency(graphs, psr.PackageInfo)
+		if !ok {
+			undetermined[psr.PackageInfo.Location()]++
+			psr.DirectnessUnknown = true
+		} else if isDirect != direct {
+			continue
+		}
+
+		packageResults = append(packageResults, psr)
+	}
+
+	for _, location := range slices.Sorted(maps.Keys(undetermined)) {
+		slog.Info(fmt.Sprintf("Kept %d package/s from %s as they cannot be determined to be direct or transitive dependencies.", undetermined[location], location))
+	}
+
+	if len(packageResults) != len(scanResults.PackageScanResults) {
+		kind := "transitive"
+		if !direct {
+			kind = "direct"
+		}
+
+		slog.Info(fmt.Sprintf("Filtered %d %s package/s from the scan.", len(scanResults.PackageScanResults)-len(packageResults), kind))
+	}
+
+	scanResults.PackageScanResults = packageResults
+}
+
 // filterIgnoredPackages removes ignore scanned packages according to config. Returns filtered scanned packages.
 func filterIgnoredPackages(scanResults *results.ScanResults) {
 	configManager := &scanResults.ConfigManager
@@ -66,7 +184,7 @@ func filterIgnoredPackages(scanResults *results.ScanResults) {
 	out := make([]imodels.PackageScanResult, 0, len(scanResults.PackageScanResults))
 	for _, psr := range scanResults.PackageScanResults {
 		p := psr.PackageInfo
-		configToUse := configManager.Get(p.Location())
+		configToUse := configManager.Get(scanners.OnDiskPath(p.Location()))
 
 		if ignore, ignoreLine := configToUse.ShouldIgnorePackage(p); ignore {
 			pkgString := fmt.Sprintf("%s/%s/%s", p.Ecosystem().String(), p.Name(), p.Version())
@@ -89,17 +207,44 @@ func filterIgnoredPackages(scanResults *results.ScanResults) {
 	scanResults.PackageScanResults = out
 }
 
+// isWithdrawn returns true if the vulnerability was withdrawn before the given time
+func isWithdrawn(vuln *osvschema.Vulnerability, now time.Time) bool {
+	return !vuln.Withdrawn.IsZero() && !vuln.Withdrawn.After(now)
+}
+
+// filterWithdrawnVulns removes vulnerabilities that were withdrawn before the given time from
+// the packages, returning the number of vulnerabilities that were removed
+func filterWithdrawnVulns(scanResults *results.ScanResults, now time.Time) int {
+	removedCount := 0
+	for i, psr := range scanResults.PackageScanResults {
+		var vulns []*osvschema.Vulnerability
+		for _, vuln := range psr.Vulnerabilities {
+			if isWithdrawn(vuln, now) {
+				removedCount++
+				continue
+			}
+			vulns = append(vulns, vuln)
+		}
+		scanResults.PackageScanResults[i].Vulnerabilities = vulns
+	}
+
+	return removedCount
+}
+
 // Filters results according to config, preserving order. Returns total number of vulnerabilities removed.
-func filterResults(results *models.VulnerabilityResults, configManager *config.Manager, allPackages bool) int {
+//
+// If includeIgnored is true, the vulnerabilities that are removed are kept as the
+// ExperimentalIgnoredVulnerabilities of their package.
+func filterResults(results *models.VulnerabilityResults, configManager *config.Manager, allPackages bool, includeIgnored bool) int {
 	removedCount := 0
 	newResults := []models.PackageSource{} // Want 0 vulnerabilities to show in JSON as an empty list, not null.
 	for _, pkgSrc := range results.Results {
-		configToUse := configManager.Get(pkgSrc.Source.Path)
+		configToUse := configManager.Get(scanners.OnDiskPath(pkgSrc.Source.Path))
 		var newPackages []models.PackageVulns
 		for _, pkgVulns := range pkgSrc.Packages {
-			newVulns := filterPackageVulns(pkgVulns, configToUse)
+			newVulns := filterPackageVulns(pkgVulns, configToUse, includeIgnored)
 			removedCount += len(pkgVulns.Vulnerabilities) - len(newVulns.Vulnerabilities)
-			if allPackages || len(newVulns.Vulnerabilities) > 0 || len(pkgVulns.LicenseViolations) > 0 {
+			if allPackages || len(newVulns.Vulnerabilities) > 0 || len(pkgVulns.LicenseViolations) > 0 || len(pkgVulns.ExperimentalUnaffectedVulnerabilities) > 0 || len(newVulns.ExperimentalIgnoredVulnerabilities) > 0 {
 				newPackages = append(newPackages, newVulns)
 			}
 		}
@@ -115,8 +260,8 @@ func filterResults(results *models.VulnerabilityResults, configManager *config.M
 }
 
 // Filters package-grouped vulnerabilities according to config, preserving ordering. Returns filtered package vulnerabilities.
-func filterPackageVulns(pkgVulns models.PackageVulns, configToUse config.Config) models.PackageVulns {
-	ignoredVulns := map[string]struct{}{}
+func filterPackageVulns(pkgVulns models.PackageVulns, configToUse config.Config, includeIgnored bool) models.PackageVulns {
+	ignoredVulns := map[string]config.IgnoreEntry{}
 
 	// Iterate over groups first to remove all aliases of ignored vulnerabilities.
 	var newGroups []models.GroupInfo
@@ -124,9 +269,9 @@ func filterPackageVulns(pkgVulns models.PackageVulns, configToUse config.Config)
 		ignore := false
 		for _, id := range group.Aliases {
 			var ignoreLine config.IgnoreEntry
-			if ignore, ignoreLine = configToUse.ShouldIgnore(id); ignore {
+			if ignore, ignoreLine = configToUse.ShouldIgnore(id, pkgVulns); ignore {
 				for _, id := range group.Aliases {
-					ignoredVulns[id] = struct{}{}
+					ignoredVulns[id] = ignoreLine
 				}
 
 				reason := ignoreLine.Reason
@@ -162,9 +307,279 @@ func filterPackageVulns(pkgVulns models.PackageVulns, configToUse config.Config)
 		}
 	}
 
+	var ignored []models.IgnoredVulnerability
+	if includeIgnored {
+		for _, vuln := range pkgVulns.Vulnerabilities {
+			if ignoreLine, filtered := ignoredVulns[vuln.ID]; filtered {
+				ignored = append(ignored, models.IgnoredVulnerability{
+					Vulnerability: vuln,
+					Reason:        ignoreLine.Reason,
+					State:         ignoreLine.VEXState(),
+					Justification: ignoreLine.Justification,
+				})
+			}
+		}
+	}
+
 	// Passed by value. We don't want to alter the original PackageVulns.
 	pkgVulns.Groups = newGroups
 	pkgVulns.Vulnerabilities = newVulns
+	pkgVulns.ExperimentalIgnoredVulnerabilities = ignored
 
 	return pkgVulns
 }
+
+// filterVEXVulns removes the vulnerabilities that VEX statements say do not affect their package,
+// preserving order, and keeping them as the ExperimentalIgnoredVulnerabilities of their package
+// along with the statement if includeIgnored is set. Returns the number of vulnerabilities removed.
+func filterVEXVulns(results *models.VulnerabilityResults, statements vex.Statements, allPackages bool, includeIgnored bool) int {
+	removedCount := 0
+	newResults := []models.PackageSource{}
+	for _, pkgSrc := range results.Results {
+		var newPackages []models.PackageVulns
+		for _, pkgVulns := range pkgSrc.Packages {
+			suppressedVulns := map[string]vex.Statement{}
+			var newGroups []models.GroupInfo
+			for _, group := range pkgVulns.Groups {
+				statement, ok := statements.Find(pkgVulns.Package, group.Aliases)
+				if !ok || !statement.Suppresses() {
+					newGroups = append(newGroups, group)
+					continue
+				}
+
+				slog.Info(fmt.Sprintf("%s in %s has been filtered out as it is %s according to %s", statement.Vulnerability, pkgVulns.Package.Name, statement.State, statement.Document))
+				for _, id := range group.Aliases {
+					suppressedVulns[id] = statement
+				}
+			}
+
+			var newVulns []osvschema.Vulnerability
+			for _, vuln := range pkgVulns.Vulnerabilities {
+				statement, suppressed := suppressedVulns[vuln.ID]
+				if !suppressed {
+					newVulns = append(newVulns, vuln)
+					continue
+				}
+				if !includeIgnored {
+					continue
+				}
+
+				pkgVulns.ExperimentalIgnoredVulnerabilities = append(pkgVulns.ExperimentalIgnoredVulnerabilities, models.IgnoredVulnerability{
+					Vulnerability: vuln,
+					Reason:        statement.Detail,
+					State:         statement.State,
+					Justification: statement.Justification,
+					VEXDocument:   statement.Document,
+				})
+			}
+			removedCount += len(pkgVulns.Vulnerabilities) - len(newVulns)
+
+			pkgVulns.Groups = newGroups
+			pkgVulns.Vulnerabilities = newVulns
+			if allPackages || len(pkgVulns.Vulnerabilities) > 0 || len(pkgVulns.LicenseViolations) > 0 || len(pkgVulns.ExperimentalUnaffectedVulnerabilities) > 0 || len(pkgVulns.ExperimentalIgnoredVulnerabilities) > 0 {
+				newPackages = append(newPackages, pkgVulns)
+			}
+		}
+		if len(newPackages) > 0 {
+			pkgSrc.Packages = newPackages
+			newResults = append(newResults, pkgSrc)
+		}
+	}
+	results.Results = newResults
+
+	return removedCount
+}
+
+// filterUncalledVulns removes vulnerabilities that call analysis has determined are
+// not called, preserving order. Returns the number of vulnerabilities removed.
+func filterUncalledVulns(results *models.VulnerabilityResults, allPackages bool) int {
+	removedCount := 0
+	newResults := []models.PackageSource{}
+	for _, pkgSrc := range results.Results {
+		var newPackages []models.PackageVulns
+		for _, pkgVulns := range pkgSrc.Packages {
+			uncalledVulns := map[string]struct{}{}
+			var newGroups []models.GroupInfo
+			for _, group := range pkgVulns.Groups {
+				if group.IsCalled() {
+					newGroups = append(newGroups, group)
+					continue
+				}
+				for _, id := range group.IDs {
+					uncalledVulns[id] = struct{}{}
+				}
+			}
+
+			var newVulns []osvschema.Vulnerability
+			for _, vuln := range pkgVulns.Vulnerabilities {
+				if _, uncalled := uncalledVulns[vuln.ID]; !uncalled {
+					newVulns = append(newVulns, vuln)
+				}
+			}
+			removedCount += len(pkgVulns.Vulnerabilities) - len(newVulns)
+
+			pkgVulns.Groups = newGroups
+			pkgVulns.Vulnerabilities = newVulns
+			if allPackages || len(pkgVulns.Vulnerabilities) > 0 || len(pkgVulns.LicenseViolations) > 0 || len(pkgVulns.ExperimentalUnaffectedVulnerabilities) > 0 {
+				newPackages = append(newPackages, pkgVulns)
+			}
+		}
+		if len(newPackages) > 0 {
+			pkgSrc.Packages = newPackages
+			newResults = append(newResults, pkgSrc)
+		}
+	}
+	results.Results = newResults
+
+	return removedCount
+}
+
+// filterNonKEVVulns removes vulnerabilities that are not in CISA's Known Exploited Vulnerabilities
+// catalog, preserving order. Returns the number of vulnerabilities removed.
+func filterNonKEVVulns(results *models.VulnerabilityResults, allPackages bool) int {
+	removedCount := 0
+	newResults := []models.PackageSource{}
+	for _, pkgSrc := range results.Results {
+		var newPackages []models.PackageVulns
+		for _, pkgVulns := range pkgSrc.Packages {
+			exploitedVulns := map[string]struct{}{}
+			var newGroups []models.GroupInfo
+			for _, group := range pkgVulns.Groups {
+				if !group.IsKnownExploited() {
+					continue
+				}
+				newGroups = append(newGroups, group)
+				for _, id := range group.IDs {
+					exploitedVulns[id] = struct{}{}
+				}
+			}
+
+			var newVulns []osvschema.Vulnerability
+			for _, vuln := range pkgVulns.Vulnerabilities {
+				if _, exploited := exploitedVulns[vuln.ID]; exploited {
+					newVulns = append(newVulns, vuln)
+				}
+			}
+			removedCount += len(pkgVulns.Vulnerabilities) - len(newVulns)
+
+			pkgVulns.Groups = newGroups
+			pkgVulns.Vulnerabilities = newVulns
+			if allPackages || len(pkgVulns.Vulnerabilities) > 0 || len(pkgVulns.LicenseViolations) > 0 || len(pkgVulns.ExperimentalUnaffectedVulnerabilities) > 0 {
+				newPackages = append(newPackages, pkgVulns)
+			}
+		}
+		if len(newPackages) > 0 {
+			pkgSrc.Packages = newPackages
+			newResults = append(newResults, pkgSrc)
+		}
+	}
+	results.Results = newResults
+
+	return removedCount
+}
+
+// baselineVuln identifies a vulnerability of a package regardless of the
+// version of the package or where it was found
+type baselineVuln struct {
+	ecosystem string
+	name      string
+	id        string
+}
+
+// baselineVulns returns every vulnerability (including by its aliases) of every package in the results
+func baselineVulns(results models.VulnerabilityResults) map[baselineVuln]struct{} {
+	vulns := map[baselineVuln]struct{}{}
+	for _, pkgSrc := range results.Results {
+		for _, pkgVulns := range pkgSrc.Packages {
+			for _, group := range pkgVulns.Groups {
+				for _, id := range slices.Concat(group.IDs, group.Aliases) {
+					vulns[baselineVuln{pkgVulns.Package.Ecosystem, pkgVulns.Package.Name, id}] = struct{}{}
+				}
+			}
+		}
+	}
+
+	return vulns
+}
+
+// isInBaseline returns true if any of the ids of the group are of a vulnerability
+// of the same package in the baseline
+func isInBaseline(baseline map[baselineVuln]struct{}, pkg models.PackageInfo, group models.GroupInfo) bool {
+	for _, id := range slices.Concat(group.IDs, group.Aliases) {
+		if _, ok := baseline[baselineVuln{pkg.Ecosystem, pkg.Name, id}]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// filterBaselineVulns removes vulnerabilities that are in the results of a
+// previous scan, returning the number of vulnerabilities that were removed
+func filterBaselineVulns(results *models.VulnerabilityResults, baseline models.VulnerabilityResults, allPackages bool) int {
+	known := baselineVulns(baseline)
+
+	removedCount := 0
+	newResults := []models.PackageSource{}
+	for _, pkgSrc := range results.Results {
+		var newPackages []models.PackageVulns
+		for _, pkgVulns := range pkgSrc.Packages {
+			knownVulns := map[string]struct{}{}
+			var newGroups []models.GroupInfo
+			for _, group := range pkgVulns.Groups {
+				if !isInBaseline(known, pkgVulns.Package, group) {
+					newGroups = append(newGroups, group)
+					continue
+				}
+				for _, id := range group.IDs {
+					knownVulns[id] = struct{}{}
+				}
+			}
+
+			var newVulns []osvschema.Vulnerability
+			for _, vuln := range pkgVulns.Vulnerabilities {
+				if _, isKnown := knownVulns[vuln.ID]; !isKnown {
+					newVulns = append(newVulns, vuln)
+				}
+			}
+			removedCount += len(pkgVulns.Vulnerabilities) - len(newVulns)
+
+			pkgVulns.Groups = newGroups
+			pkgVulns.Vulnerabilities = newVulns
+			if allPackages || len(pkgVulns.Vulnerabilities) > 0 || len(pkgVulns.LicenseViolations) > 0 || len(pkgVulns.ExperimentalUnaffectedVulnerabilities) > 0 {
+				newPackages = append(newPackages, pkgVulns)
+			}
+		}
+		if len(newPackages) > 0 {
+			pkgSrc.Packages = newPackages
+			newResults = append(newResults, pkgSrc)
+		}
+	}
+	results.Results = newResults
+
+	return removedCount
+}
+
+// resolvedBaselineVulns returns the vulnerabilities in the results of a previous
+// scan that are no longer present in the results, described as "<id> (<ecosystem>/<name>)"
+func resolvedBaselineVulns(results models.VulnerabilityResults, baseline models.VulnerabilityResults) []string {
+	current := baselineVulns(results)
+
+	var resolved []string
+	for _, pkgSrc := range baseline.Results {
+		for _, pkgVulns := range pkgSrc.Packages {
+			for _, group := range pkgVulns.Groups {
+				if len(group.IDs) == 0 || isInBaseline(current, pkgVulns.Package, group) {
+					continue
+				}
+
+				desc := fmt.Sprintf("%s (%s/%s)", group.IDs[0], pkgVulns.Package.Ecosystem, pkgVulns.Package.Name)
+				if !slices.Contains(resolved, desc) {
+					resolved = append(resolved, desc)
+				}
+			}
+		}
+	}
+
+	return resolved
+}
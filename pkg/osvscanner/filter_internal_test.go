@@ -2,11 +2,31 @@ package osvscanner
 
 import (
 	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/gradlelockfile"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/javalockfile"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/pomxml"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/pomxmlnet"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/javascript/packagelockjson"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/python/pipfilelock"
+	"github.com/google/osv-scalibr/extractor/filesystem/osv"
 	"github.com/google/osv-scanner/v2/internal/config"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/imodels/results"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/ecosystemmock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/dotnet/packageslockjson"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/rust/cargotoml"
 	"github.com/google/osv-scanner/v2/internal/testutility"
+	"github.com/google/osv-scanner/v2/internal/vex"
 	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"github.com/package-url/packageurl-go"
 )
 
 func Test_filterResults(t *testing.T) {
@@ -44,7 +64,7 @@ func Test_filterResults(t *testing.T) {
 			}
 
 			got := testutility.LoadJSONFixture[models.VulnerabilityResults](t, filepath.Join(tt.path, "input.json"))
-			filtered := filterResults(&got, &configManager, false)
+			filtered := filterResults(&got, &configManager, false, false)
 
 			testutility.NewSnapshot().MatchJSON(t, got)
 
@@ -54,3 +74,491 @@ func Test_filterResults(t *testing.T) {
 		})
 	}
 }
+
+func Test_filterDevPackages(t *testing.T) {
+	t.Parallel()
+
+	newResult := func(name string, ext filesystem.Extractor, groups ...string) imodels.PackageScanResult {
+		if groups == nil {
+			groups = []string{}
+		}
+
+		return imodels.PackageScanResult{
+			PackageInfo: imodels.FromInventory(&extractor.Inventory{
+				Name:      name,
+				Version:   "1.0.0",
+				Locations: []string{"/path/to/lockfile"},
+				Extractor: ext,
+				Metadata:  osv.DepGroupMetadata{DepGroupVals: groups},
+			}),
+		}
+	}
+
+	scanResults := results.ScanResults{
+		PackageScanResults: []imodels.PackageScanResult{
+			newResult("npm-prod", packagelockjson.Extractor{}),
+			newResult("npm-dev", packagelockjson.Extractor{}, "dev"),
+			newResult("npm-optional", packagelockjson.Extractor{}, "optional"),
+			newResult("pypi-dev", pipfilelock.Extractor{}, "dev"),
+			newResult("maven-test", pomxml.Extractor{}, "test"),
+			newResult("maven-runtime", pomxml.Extractor{}, "runtime"),
+		},
+	}
+
+	filterDevPackages(&scanResults)
+
+	got := make([]string, 0, len(scanResults.PackageScanResults))
+	for _, psr := range scanResults.PackageScanResults {
+		got = append(got, psr.PackageInfo.Name())
+	}
+
+	want := []string{"npm-prod", "npm-optional", "maven-runtime"}
+	if !slices.Equal(got, want) {
+		t.Errorf("filterDevPackages() kept %v, want %v", got, want)
+	}
+}
+
+func Test_filterPackagesByDirectness(t *testing.T) {
+	t.Parallel()
+
+	newResult := func(name string, version string, location string, ext filesystem.Extractor, metadata any) imodels.PackageScanResult {
+		return imodels.PackageScanResult{
+			PackageInfo: imodels.FromInventory(&extractor.Inventory{
+				Name:      name,
+				Version:   version,
+				Locations: []string{location},
+				Extractor: ext,
+				Metadata:  metadata,
+			}),
+		}
+	}
+
+	npm := ecosystemmock.Extractor{MockEcosystem: "npm"}
+	newScanResults := func() results.ScanResults {
+		return results.ScanResults{
+			PackageScanResults: []imodels.PackageScanResult{
+				// from the dependency graph of the lockfile
+				newResult("express", "4.18.2", "fixtures/dependency-paths/package-lock.json", npm, nil),
+				newResult("qs", "6.11.0", "fixtures/dependency-paths/package-lock.json", npm, nil),
+				// from the metadata of the package
+				newResult("nuget-direct", "1.0.0", "packages.lock.json", packageslockjson.Extractor{}, &packageslockjson.Metadata{}),
+				newResult("nuget-transitive", "1.0.0", "packages.lock.json", packageslockjson.Extractor{}, &packageslockjson.Metadata{IsTransitive: true}),
+				newResult("maven-direct", "1.0.0", "pom.xml", pomxmlnet.Extractor{}, javalockfile.Metadata{}),
+				newResult("maven-transitive", "1.0.0", "pom.xml", pomxmlnet.Extractor{}, javalockfile.Metadata{IsTransitive: true}),
+				newResult("cargo-direct", "1.0.0", "Cargo.toml", cargotoml.Extractor{}, cargotoml.Metadata{Requirement: "1.0"}),
+				// neither of which say whether the package is direct
+				newResult("gradle-unknown", "1.0.0", "gradle.lockfile", gradlelockfile.Extractor{}, &javalockfile.Metadata{}),
+				newResult("npm-unknown", "1.0.0", "package-lock.json", npm, nil),
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		direct bool
+		want   []string
+	}{
+		{
+			name:   "direct only",
+			direct: true,
+			want:   []string{"express", "nuget-direct", "maven-direct", "cargo-direct", "gradle-unknown", "npm-unknown"},
+		},
+		{
+			name:   "transitive only",
+			direct: false,
+			want:   []string{"qs", "nuget-transitive", "maven-transitive", "gradle-unknown", "npm-unknown"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			scanResults := newScanResults()
+			filterPackagesByDirectness(&scanResults, tt.direct)
+
+			got := make([]string, 0, len(scanResults.PackageScanResults))
+			var unknown []string
+			for _, psr := range scanResults.PackageScanResults {
+				got = append(got, psr.PackageInfo.Name())
+				if psr.DirectnessUnknown {
+					unknown = append(unknown, psr.PackageInfo.Name())
+				}
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("filterPackagesByDirectness() kept %v, want %v", got, tt.want)
+			}
+
+			// the packages that were only kept because their directness is unknown are flagged
+			if want := []string{"gradle-unknown", "npm-unknown"}; !slices.Equal(unknown, want) {
+				t.Errorf("filterPackagesByDirectness() flagged %v, want %v", unknown, want)
+			}
+		})
+	}
+}
+
+func Test_filterWithdrawnVulns(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	active := &osvschema.Vulnerability{ID: "GHSA-0001"}
+	withdrawn := &osvschema.Vulnerability{ID: "GHSA-0002", Withdrawn: now.AddDate(0, -1, 0)}
+	// advisories can be withdrawn in advance, e.g. when they are duplicates that will be merged
+	toBeWithdrawn := &osvschema.Vulnerability{ID: "GHSA-0003", Withdrawn: now.AddDate(0, 0, 1)}
+
+	scanResults := results.ScanResults{
+		PackageScanResults: []imodels.PackageScanResult{
+			{Vulnerabilities: []*osvschema.Vulnerability{active, withdrawn, toBeWithdrawn}},
+			{Vulnerabilities: []*osvschema.Vulnerability{withdrawn}},
+			{},
+		},
+	}
+
+	filtered := filterWithdrawnVulns(&scanResults, now)
+	if filtered != 2 {
+		t.Errorf("filterWithdrawnVulns() = %d, want %d", filtered, 2)
+	}
+
+	want := [][]*osvschema.Vulnerability{{active, toBeWithdrawn}, nil, nil}
+	got := make([][]*osvschema.Vulnerability, 0, len(scanResults.PackageScanResults))
+	for _, psr := range scanResults.PackageScanResults {
+		got = append(got, psr.Vulnerabilities)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("filterWithdrawnVulns() vulnerabilities mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_filterUncalledVulns(t *testing.T) {
+	t.Parallel()
+
+	called := models.GroupInfo{
+		IDs:                  []string{"GO-2024-0001"},
+		Aliases:              []string{"GO-2024-0001"},
+		ExperimentalAnalysis: map[string]models.AnalysisInfo{"GO-2024-0001": {Called: true}},
+	}
+	uncalled := models.GroupInfo{
+		IDs:                  []string{"GO-2024-0002"},
+		Aliases:              []string{"GO-2024-0002"},
+		ExperimentalAnalysis: map[string]models.AnalysisInfo{"GO-2024-0002": {Called: false}},
+	}
+	// vulnerabilities without analysis, e.g. as they have no symbol information, are assumed to be called
+	unanalyzed := models.GroupInfo{
+		IDs:     []string{"GO-2024-0003"},
+		Aliases: []string{"GO-2024-0003"},
+	}
+
+	vr := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/go.mod", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "example.com/mixed", Version: "1.0.0", Ecosystem: "Go"},
+						Vulnerabilities: []osvschema.Vulnerability{{ID: "GO-2024-0001"}, {ID: "GO-2024-0002"}, {ID: "GO-2024-0003"}},
+						Groups:          []models.GroupInfo{called, uncalled, unanalyzed},
+					},
+					{
+						Package:         models.PackageInfo{Name: "example.com/uncalled", Version: "1.0.0", Ecosystem: "Go"},
+						Vulnerabilities: []osvschema.Vulnerability{{ID: "GO-2024-0002"}},
+						Groups:          []models.GroupInfo{uncalled},
+					},
+				},
+			},
+		},
+	}
+
+	filtered := filterUncalledVulns(&vr, false)
+	if filtered != 2 {
+		t.Errorf("filterUncalledVulns() = %d, want %d", filtered, 2)
+	}
+
+	want := []models.PackageSource{
+		{
+			Source: models.SourceInfo{Path: "/path/to/go.mod", Type: "lockfile"},
+			Packages: []models.PackageVulns{
+				{
+					Package:         models.PackageInfo{Name: "example.com/mixed", Version: "1.0.0", Ecosystem: "Go"},
+					Vulnerabilities: []osvschema.Vulnerability{{ID: "GO-2024-0001"}, {ID: "GO-2024-0003"}},
+					Groups:          []models.GroupInfo{called, unanalyzed},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, vr.Results); diff != "" {
+		t.Errorf("filterUncalledVulns() results mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_filterNonKEVVulns(t *testing.T) {
+	t.Parallel()
+
+	exploited := models.GroupInfo{
+		IDs:             []string{"GHSA-jfh8-c2jp-5v3q"},
+		Aliases:         []string{"CVE-2021-44228", "GHSA-jfh8-c2jp-5v3q"},
+		ExperimentalKEV: &models.KEVEntry{CVE: "CVE-2021-44228", DateAdded: "2021-12-10"},
+	}
+	unexploited := models.GroupInfo{
+		IDs:     []string{"GHSA-7rjr-3q55-vv33"},
+		Aliases: []string{"CVE-2021-45046", "GHSA-7rjr-3q55-vv33"},
+	}
+
+	vr := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/pom.xml", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "org.apache.logging.log4j:log4j-core", Version: "2.14.1", Ecosystem: "Maven"},
+						Vulnerabilities: []osvschema.Vulnerability{{ID: "GHSA-jfh8-c2jp-5v3q"}, {ID: "GHSA-7rjr-3q55-vv33"}},
+						Groups:          []models.GroupInfo{exploited, unexploited},
+					},
+					{
+						Package:         models.PackageInfo{Name: "org.apache.logging.log4j:log4j-api", Version: "2.14.1", Ecosystem: "Maven"},
+						Vulnerabilities: []osvschema.Vulnerability{{ID: "GHSA-7rjr-3q55-vv33"}},
+						Groups:          []models.GroupInfo{unexploited},
+					},
+				},
+			},
+		},
+	}
+
+	filtered := filterNonKEVVulns(&vr, false)
+	if filtered != 2 {
+		t.Errorf("filterNonKEVVulns() = %d, want %d", filtered, 2)
+	}
+
+	want := []models.PackageSource{
+		{
+			Source: models.SourceInfo{Path: "/path/to/pom.xml", Type: "lockfile"},
+			Packages: []models.PackageVulns{
+				{
+					Package:         models.PackageInfo{Name: "org.apache.logging.log4j:log4j-core", Version: "2.14.1", Ecosystem: "Maven"},
+					Vulnerabilities: []osvschema.Vulnerability{{ID: "GHSA-jfh8-c2jp-5v3q"}},
+					Groups:          []models.GroupInfo{exploited},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, vr.Results); diff != "" {
+		t.Errorf("filterNonKEVVulns() results mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_filterBaselineVulns(t *testing.T) {
+	t.Parallel()
+
+	existing := models.GroupInfo{
+		IDs:     []string{"GHSA-0001"},
+		Aliases: []string{"GHSA-0001", "CVE-2024-0001"},
+	}
+	introduced := models.GroupInfo{
+		IDs:     []string{"GHSA-0002"},
+		Aliases: []string{"GHSA-0002"},
+	}
+	fixed := models.GroupInfo{
+		IDs:     []string{"GHSA-0003"},
+		Aliases: []string{"GHSA-0003"},
+	}
+
+	baseline := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/old/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						// vulnerabilities are matched regardless of the version and source of the package,
+						// and the ids of their aliases
+						Package:         models.PackageInfo{Name: "lodash", Version: "4.17.0", Ecosystem: "npm"},
+						Vulnerabilities: []osvschema.Vulnerability{{ID: "CVE-2024-0001"}, {ID: "GHSA-0003"}},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"CVE-2024-0001"}, Aliases: []string{"CVE-2024-0001"}},
+							fixed,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	vr := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "lodash", Version: "4.17.1", Ecosystem: "npm"},
+						Vulnerabilities: []osvschema.Vulnerability{{ID: "GHSA-0001"}, {ID: "GHSA-0002"}},
+						Groups:          []models.GroupInfo{existing, introduced},
+					},
+					{
+						// the same vulnerability in another package is new
+						Package:         models.PackageInfo{Name: "underscore", Version: "1.0.0", Ecosystem: "npm"},
+						Vulnerabilities: []osvschema.Vulnerability{{ID: "GHSA-0001"}},
+						Groups:          []models.GroupInfo{existing},
+					},
+					{
+						Package:         models.PackageInfo{Name: "lodash", Version: "4.17.1", Ecosystem: "Maven"},
+						Vulnerabilities: []osvschema.Vulnerability{{ID: "GHSA-0001"}},
+						Groups:          []models.GroupInfo{existing},
+					},
+				},
+			},
+		},
+	}
+
+	resolved := resolvedBaselineVulns(vr, baseline)
+	if diff := cmp.Diff([]string{"GHSA-0003 (npm/lodash)"}, resolved); diff != "" {
+		t.Errorf("resolvedBaselineVulns() mismatch (-want +got):\n%s", diff)
+	}
+
+	filtered := filterBaselineVulns(&vr, baseline, false)
+	if filtered != 1 {
+		t.Errorf("filterBaselineVulns() = %d, want %d", filtered, 1)
+	}
+
+	want := []models.PackageSource{
+		{
+			Source: models.SourceInfo{Path: "/path/to/package-lock.json", Type: "lockfile"},
+			Packages: []models.PackageVulns{
+				{
+					Package:         models.PackageInfo{Name: "lodash", Version: "4.17.1", Ecosystem: "npm"},
+					Vulnerabilities: []osvschema.Vulnerability{{ID: "GHSA-0002"}},
+					Groups:          []models.GroupInfo{introduced},
+				},
+				{
+					Package:         models.PackageInfo{Name: "underscore", Version: "1.0.0", Ecosystem: "npm"},
+					Vulnerabilities: []osvschema.Vulnerability{{ID: "GHSA-0001"}},
+					Groups:          []models.GroupInfo{existing},
+				},
+				{
+					Package:         models.PackageInfo{Name: "lodash", Version: "4.17.1", Ecosystem: "Maven"},
+					Vulnerabilities: []osvschema.Vulnerability{{ID: "GHSA-0001"}},
+					Groups:          []models.GroupInfo{existing},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, vr.Results); diff != "" {
+		t.Errorf("filterBaselineVulns() results mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_filterPackageVulns_IncludeIgnored(t *testing.T) {
+	t.Parallel()
+
+	pkgVulns := models.PackageVulns{
+		Package: models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+		Vulnerabilities: []osvschema.Vulnerability{
+			{ID: "GHSA-1", Aliases: []string{"CVE-1"}},
+			{ID: "GHSA-2"},
+		},
+		Groups: []models.GroupInfo{
+			{IDs: []string{"GHSA-1"}, Aliases: []string{"CVE-1", "GHSA-1"}},
+			{IDs: []string{"GHSA-2"}, Aliases: []string{"GHSA-2"}},
+		},
+	}
+	configToUse := config.Config{
+		IgnoredVulns: []config.IgnoreEntry{
+			{ID: "CVE-1", Reason: "not used in production", Justification: "requires_environment"},
+		},
+	}
+
+	got := filterPackageVulns(pkgVulns, configToUse, false)
+	if len(got.ExperimentalIgnoredVulnerabilities) != 0 {
+		t.Errorf("filterPackageVulns() kept ignored vulnerabilities when they were not requested: %v", got.ExperimentalIgnoredVulnerabilities)
+	}
+
+	got = filterPackageVulns(pkgVulns, configToUse, true)
+
+	want := []models.IgnoredVulnerability{
+		{
+			Vulnerability: osvschema.Vulnerability{ID: "GHSA-1", Aliases: []string{"CVE-1"}},
+			Reason:        "not used in production",
+			State:         "not_affected",
+			Justification: "requires_environment",
+		},
+	}
+	if diff := cmp.Diff(want, got.ExperimentalIgnoredVulnerabilities); diff != "" {
+		t.Errorf("filterPackageVulns() ignored vulnerabilities mismatch (-want +got):\n%s", diff)
+	}
+	if len(got.Vulnerabilities) != 1 || got.Vulnerabilities[0].ID != "GHSA-2" {
+		t.Errorf("filterPackageVulns() vulnerabilities = %v, want only GHSA-2", got.Vulnerabilities)
+	}
+}
+
+func Test_filterVEXVulns(t *testing.T) {
+	t.Parallel()
+
+	lodash := models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"}
+	newResults := func() models.VulnerabilityResults {
+		return models.VulnerabilityResults{
+			Results: []models.PackageSource{
+				{
+					Source: models.SourceInfo{Path: "package-lock.json", Type: "lockfile"},
+					Packages: []models.PackageVulns{
+						{
+							Package: lodash,
+							Vulnerabilities: []osvschema.Vulnerability{
+								{ID: "GHSA-1", Aliases: []string{"CVE-1"}},
+								{ID: "GHSA-2"},
+							},
+							Groups: []models.GroupInfo{
+								{IDs: []string{"GHSA-1"}, Aliases: []string{"CVE-1", "GHSA-1"}},
+								{IDs: []string{"GHSA-2"}, Aliases: []string{"GHSA-2"}},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	statements := vex.Statements{
+		{
+			Vulnerability: "CVE-1",
+			Products:      []packageurl.PackageURL{{Type: "npm", Name: "lodash"}},
+			State:         "not_affected",
+			Justification: "code_not_reachable",
+			Detail:        "template() is never called",
+			Document:      "vex.json",
+		},
+		{
+			Vulnerability: "GHSA-2",
+			Products:      []packageurl.PackageURL{{Type: "npm", Name: "lodash"}},
+			State:         "exploitable",
+			Document:      "vex.json",
+		},
+	}
+
+	results := newResults()
+	if got := filterVEXVulns(&results, statements, false, false); got != 1 {
+		t.Errorf("filterVEXVulns() = %d, want 1", got)
+	}
+	pkgVulns := results.Results[0].Packages[0]
+	if len(pkgVulns.Vulnerabilities) != 1 || pkgVulns.Vulnerabilities[0].ID != "GHSA-2" {
+		t.Errorf("filterVEXVulns() vulnerabilities = %v, want only GHSA-2", pkgVulns.Vulnerabilities)
+	}
+	if len(pkgVulns.Groups) != 1 || pkgVulns.Groups[0].IDs[0] != "GHSA-2" {
+		t.Errorf("filterVEXVulns() groups = %v, want only GHSA-2", pkgVulns.Groups)
+	}
+	if len(pkgVulns.ExperimentalIgnoredVulnerabilities) != 0 {
+		t.Errorf("filterVEXVulns() kept ignored vulnerabilities when they were not requested: %v", pkgVulns.ExperimentalIgnoredVulnerabilities)
+	}
+
+	results = newResults()
+	filterVEXVulns(&results, statements, false, true)
+
+	want := []models.IgnoredVulnerability{
+		{
+			Vulnerability: osvschema.Vulnerability{ID: "GHSA-1", Aliases: []string{"CVE-1"}},
+			Reason:        "template() is never called",
+			State:         "not_affected",
+			Justification: "code_not_reachable",
+			VEXDocument:   "vex.json",
+		},
+	}
+	if diff := cmp.Diff(want, results.Results[0].Packages[0].ExperimentalIgnoredVulnerabilities); diff != "" {
+		t.Errorf("filterVEXVulns() ignored vulnerabilities mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,66 @@
+package osvscanner
+
+import (
+	"context"
+
+	"github.com/google/osv-scanner/v2/internal/cachefile"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/incrementalmatcher"
+	"github.com/google/osv-scanner/v2/internal/clients/clientinterfaces"
+	"github.com/google/osv-scanner/v2/pkg/osvscanner/internal/scanners"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// databaseVersioner is implemented by vulnerability matchers that match against a local
+// copy of the OSV database, which can report when their copy has been updated
+type databaseVersioner interface {
+	DatabaseVersion(ctx context.Context, ecosystem osvschema.Ecosystem) (string, error)
+}
+
+// newIncrementalMatcher wraps the matcher so that the vulnerabilities that previous scans matched
+// for the packages of unchanged source files are reused, rather than being matched again
+func newIncrementalMatcher(actions ScannerActions, matcher clientinterfaces.VulnerabilityMatcher) (clientinterfaces.VulnerabilityMatcher, error) {
+	// the results of the OSV API can only be reused if they came from the same API, rather than a local database
+	matcherID := "osv-api:" + newOSVClient(actions).BaseHostURL
+	if actions.GitHubToken != "" {
+		matcherID += "+github"
+	}
+	if actions.CompareOffline {
+		matcherID = "local-db"
+	}
+
+	incremental, err := incrementalmatcher.NewIncrementalMatcher(incrementalCacheDir(actions), matcher, matcherID)
+	if err != nil {
+		return nil, err
+	}
+
+	if actions.CacheTTL > 0 {
+		incremental.MaxAge = actions.CacheTTL
+	}
+
+	if m, ok := matcher.(databaseVersioner); ok {
+		incremental.DatabaseVersion = m.DatabaseVersion
+	}
+
+	return incremental, nil
+}
+
+// newInventoryCache creates a cache of the packages extracted from source files, so that files which
+// have not changed since previous scans are not extracted again
+func newInventoryCache(actions ScannerActions) (*scanners.InventoryCache, error) {
+	dir, err := cachefile.Dir(incrementalCacheDir(actions), incrementalmatcher.CacheKind)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanners.NewInventoryCache(dir), nil
+}
+
+// incrementalCacheDir returns the directory that incremental scans cache in,
+// with an empty string meaning the user cache directory
+func incrementalCacheDir(actions ScannerActions) string {
+	if actions.CacheDir != "" {
+		return actions.CacheDir
+	}
+
+	return actions.LocalDBPath
+}
@@ -8,14 +8,18 @@ import (
 	"log/slog"
 	"os"
 	"os/exec"
+	"runtime"
 
+	"github.com/google/go-containerregistry/pkg/authn"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/osv-scalibr/artifact/image/layerscanning/image"
 	"github.com/google/osv-scalibr/extractor/filesystem/os/osrelease"
 	"github.com/google/osv-scanner/v2/internal/clients/clientinterfaces"
 	"github.com/google/osv-scanner/v2/pkg/models"
 )
 
-func BuildImageMetadata(img *image.Image, baseImageMatcher clientinterfaces.BaseImageMatcher) (*models.ImageMetadata, error) {
+func BuildImageMetadata(ctx context.Context, img *image.Image, baseImageMatcher clientinterfaces.BaseImageMatcher) (*models.ImageMetadata, error) {
 	chainLayers, err := img.ChainLayers()
 	if err != nil {
 		// This is very unlikely, as if this would error we would have failed the initial scan
@@ -39,7 +43,7 @@ func BuildImageMetadata(img *image.Image, baseImageMatcher clientinterfaces.Base
 	var baseImages [][]models.BaseImageDetails
 
 	if baseImageMatcher != nil {
-		baseImages, err = baseImageMatcher.MatchBaseImages(context.Background(), layerMetadata)
+		baseImages, err = baseImageMatcher.MatchBaseImages(ctx, layerMetadata)
 		if err != nil {
 			return nil, fmt.Errorf("failed to query for container base images: %w", err)
 		}
@@ -66,7 +70,8 @@ func BuildImageMetadata(img *image.Image, baseImageMatcher clientinterfaces.Base
 // cleaned automatically by this function.
 //
 // ExportDockerImage will first try to locate the image locally, and if not found, attempt to pull the image from the docker registry.
-func ExportDockerImage(dockerImageName string) (string, error) {
+// If a platform is given, the image is always pulled for that platform, as the local image may be for a different one.
+func ExportDockerImage(dockerImageName string, platform string) (string, error) {
 	tempImageFile, err := os.CreateTemp("", "docker-image-*.tar")
 	if err != nil {
 		slog.Error(fmt.Sprintf("Failed to create temporary file: %s", err))
@@ -80,11 +85,15 @@ func ExportDockerImage(dockerImageName string) (string, error) {
 		return "", err
 	}
 
-	// Check if image exists locally, if not, pull from the cloud.
-	slog.Info(fmt.Sprintf("Checking if docker image (%q) exists locally...", dockerImageName))
-	cmd := exec.Command("docker", "images", "-q", dockerImageName)
-	output, err := cmd.Output()
-	if err != nil || string(output) == "" {
+	if platform != "" {
+		slog.Info(fmt.Sprintf("Pulling docker image (%q) for platform %q...", dockerImageName, platform))
+		err = runCommandLogError("docker", "pull", "-q", "--platform", platform, dockerImageName)
+		if err != nil {
+			_ = os.RemoveAll(tempImageFile.Name())
+
+			return "", fmt.Errorf("failed to pull container image: %w", err)
+		}
+	} else if !dockerImageExistsLocally(dockerImageName) {
 		slog.Info(fmt.Sprintf("Image not found locally, pulling docker image (%q)...", dockerImageName))
 		err = runCommandLogError("docker", "pull", "-q", dockerImageName)
 		if err != nil {
@@ -105,6 +114,50 @@ func ExportDockerImage(dockerImageName string) (string, error) {
 	return tempImageFile.Name(), nil
 }
 
+func dockerImageExistsLocally(dockerImageName string) bool {
+	slog.Info(fmt.Sprintf("Checking if docker image (%q) exists locally...", dockerImageName))
+	cmd := exec.Command("docker", "images", "-q", dockerImageName)
+	output, err := cmd.Output()
+
+	return err == nil && string(output) != ""
+}
+
+// PullRemoteImage pulls an image directly from its registry without needing docker, authenticating
+// with the credentials in the docker config if there are any.
+//
+// For images that are built for multiple platforms, the image for the given platform is used,
+// or the image for linux on the architecture of the host if no platform is given.
+func PullRemoteImage(imageName string, platform string) (*image.Image, error) {
+	p, err := ParsePlatform(platform)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info(fmt.Sprintf("Pulling image (%q) for platform %q from its registry...", imageName, p))
+
+	return image.FromRemoteName(
+		imageName,
+		image.DefaultConfig(),
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+		remote.WithPlatform(*p),
+	)
+}
+
+// ParsePlatform parses a platform in the form of os/arch[/variant] (e.g. linux/arm64/v8),
+// defaulting to linux on the architecture of the host if the platform is empty
+func ParsePlatform(platform string) (*v1.Platform, error) {
+	if platform == "" {
+		return &v1.Platform{OS: "linux", Architecture: runtime.GOARCH}, nil
+	}
+
+	p, err := v1.ParsePlatform(platform)
+	if err != nil || p.OS == "" || p.Architecture == "" {
+		return nil, fmt.Errorf("invalid platform %q, must be in the form os/arch[/variant]", platform)
+	}
+
+	return p, nil
+}
+
 func runCommandLogError(name string, args ...string) error {
 	cmd := exec.Command(name, args...)
 
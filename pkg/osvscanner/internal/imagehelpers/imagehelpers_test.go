@@ -0,0 +1,54 @@
+package imagehelpers_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/osv-scanner/v2/pkg/osvscanner/internal/imagehelpers"
+)
+
+func TestParsePlatform(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		platform string
+		want     *v1.Platform
+		wantErr  bool
+	}{
+		{
+			platform: "",
+			want:     &v1.Platform{OS: "linux", Architecture: runtime.GOARCH},
+		},
+		{
+			platform: "linux/arm64",
+			want:     &v1.Platform{OS: "linux", Architecture: "arm64"},
+		},
+		{
+			platform: "linux/arm/v7",
+			want:     &v1.Platform{OS: "linux", Architecture: "arm", Variant: "v7"},
+		},
+		{
+			platform: "linux",
+			wantErr:  true,
+		},
+		{
+			platform: "/amd64",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := imagehelpers.ParsePlatform(tt.platform)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePlatform() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParsePlatform() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
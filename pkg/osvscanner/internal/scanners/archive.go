@@ -0,0 +1,356 @@
+package scanners
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing/fstest"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/simplefileapi"
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract"
+)
+
+// ErrArchiveTooLarge is returned when an archive decompresses to more than can be scanned,
+// such as because it is a decompression bomb
+var ErrArchiveTooLarge = errors.New("archive is too large to scan")
+
+// archiveLimits are how much can be decompressed from an archive while scanning it
+type archiveLimits struct {
+	// fileSize is the most that a single file within the archive can decompress to
+	fileSize int64
+	// totalSize is the most that can be decompressed from the archive in total
+	totalSize int64
+}
+
+var defaultArchiveLimits = archiveLimits{
+	fileSize:  100 << 20, // 100 MiB
+	totalSize: 1 << 30,   // 1 GiB
+}
+
+// archiveExtensions are the extensions of the archives that can be scanned,
+// with whether they are compressed with gzip
+var archiveExtensions = map[string]bool{
+	".zip":    false,
+	".tar":    false,
+	".tar.gz": true,
+	".tgz":    true,
+}
+
+// IsArchive returns true if the file at path is an archive that can be scanned, based on its extension
+func IsArchive(path string) bool {
+	return archiveExtension(path) != ""
+}
+
+func archiveExtension(path string) string {
+	lower := strings.ToLower(path)
+	for ext := range archiveExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return ext
+		}
+	}
+
+	return ""
+}
+
+// OnDiskPath returns the path of the file on disk that a package location comes from, which for
+// the packages found by ScanArchive is the archive rather than the file within it
+func OnDiskPath(location string) string {
+	for i, c := range location {
+		if c == ':' && IsArchive(location[:i]) {
+			return location[:i]
+		}
+	}
+
+	return location
+}
+
+// ScanArchive extracts the lockfiles and SBOMs within the zip or tar archive at archivePath,
+// without extracting the archive to disk.
+//
+// Only the files within the archive that are recognised by one of the extractors are read into
+// memory, which are extracted as if the archive was a directory. The packages that are found are
+// located at "<archivePath>:<path within the archive>". Archives within the archive are not scanned.
+//
+// Files that cannot be extracted are returned as FileErrors, while an ErrArchiveTooLarge error
+// is returned if the archive would decompress to more than can be scanned.
+func ScanArchive(ctx context.Context, archivePath string, extractorsToUse []filesystem.Extractor) ([]*extractor.Inventory, []FileError, error) {
+	return scanArchive(ctx, archivePath, extractorsToUse, defaultArchiveLimits)
+}
+
+func scanArchive(ctx context.Context, archivePath string, extractorsToUse []filesystem.Extractor, limits archiveLimits) ([]*extractor.Inventory, []FileError, error) {
+	archivePath, err := filepath.Abs(archivePath)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Failed to resolve path %q with error: %s", archivePath, err))
+		return nil, nil, err
+	}
+
+	fsys, err := readArchive(archivePath, extractorsToUse, limits)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := make([]string, 0, len(fsys))
+	for name, file := range fsys {
+		if file.Mode.IsRegular() {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var inventories []*extractor.Inventory
+	var fileErrs []FileError
+
+	for _, name := range names {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, nil, ctxErr
+		}
+
+		location := archivePath + ":" + name
+
+		invs, err := scalibrextract.ExtractFSWithExtractors(ctx, fsys, name, extractorsToUse)
+		if err != nil {
+			var extractionErr *scalibrextract.ExtractionError
+			if !errors.As(err, &extractionErr) {
+				return nil, nil, err
+			}
+			slog.Info(fmt.Sprintf("Error extracting %s: %v", location, err))
+			fileErrs = append(fileErrs, FileError{Path: location, Err: err})
+
+			continue
+		}
+
+		for _, inv := range invs {
+			for i := range inv.Locations {
+				inv.Locations[i] = archivePath + ":" + inv.Locations[i]
+			}
+		}
+
+		pkgCount := len(invs)
+		slog.Info(fmt.Sprintf(
+			"Scanned %s file and found %d %s",
+			location,
+			pkgCount,
+			output.Form(pkgCount, "package", "packages"),
+		))
+
+		inventories = append(inventories, invs...)
+	}
+
+	return inventories, fileErrs, nil
+}
+
+// readArchive reads the files within the archive that are required by any of the extractors
+// into an in-memory filesystem, along with the directories that they are within
+func readArchive(archivePath string, extractorsToUse []filesystem.Extractor, limits archiveLimits) (fstest.MapFS, error) {
+	ext := archiveExtension(archivePath)
+	if ext == "" {
+		return nil, fmt.Errorf("%s is not a supported archive, which must be one of: .zip, .tar, .tar.gz, .tgz", archivePath)
+	}
+
+	fsys := fstest.MapFS{}
+	budget := &decompressionBudget{archive: archivePath, limit: limits.totalSize, remaining: limits.totalSize}
+
+	// add reads the file within the archive if it is required by an extractor, with open
+	// returning a reader that counts towards the budget of what can be decompressed
+	add := func(name string, info fs.FileInfo, open func() (io.Reader, error)) error {
+		name, ok := archiveEntryName(name)
+		if !ok || !info.Mode().IsRegular() {
+			return nil
+		}
+
+		required := slices.ContainsFunc(extractorsToUse, func(ext filesystem.Extractor) bool {
+			return ext.FileRequired(simplefileapi.New(name, info))
+		})
+		if !required {
+			if IsArchive(name) {
+				slog.Debug(fmt.Sprintf("Skipping %s:%s as nested archives are not scanned", archivePath, name))
+			}
+
+			return nil
+		}
+
+		r, err := open()
+		if err != nil {
+			return fmt.Errorf("could not read %s:%s: %w", archivePath, name, err)
+		}
+
+		data, err := io.ReadAll(io.LimitReader(r, limits.fileSize+1))
+		if errors.Is(err, ErrArchiveTooLarge) {
+			return err
+		}
+		if err != nil {
+			return fmt.Errorf("could not read %s:%s: %w", archivePath, name, err)
+		}
+		if int64(len(data)) > limits.fileSize {
+			return fmt.Errorf("%w: %s:%s decompresses to more than %s, which is the most that a file within an archive can be", ErrArchiveTooLarge, archivePath, name, formatBytes(limits.fileSize))
+		}
+
+		fsys[name] = &fstest.MapFile{Data: data, Mode: info.Mode(), ModTime: info.ModTime()}
+
+		return nil
+	}
+
+	if ext == ".zip" {
+		err := readZip(archivePath, budget, add)
+		return fsys, err
+	}
+
+	err := readTar(archivePath, archiveExtensions[ext], budget, add)
+
+	return fsys, err
+}
+
+type addArchiveEntryFunc func(name string, info fs.FileInfo, open func() (io.Reader, error)) error
+
+func readZip(archivePath string, budget *decompressionBudget, add addArchiveEntryFunc) error {
+	r, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("could not open %s: %w", archivePath, err)
+	}
+	defer r.Close()
+
+	// the entries are compressed individually, so only those that are read count towards the budget
+	for _, f := range r.File {
+		var rc io.ReadCloser
+		err := add(f.Name, f.FileInfo(), func() (io.Reader, error) {
+			var err error
+			rc, err = f.Open()
+			if err != nil {
+				return nil, err
+			}
+
+			return budget.reader(rc), nil
+		})
+		if rc != nil {
+			rc.Close()
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readTar(archivePath string, gzipped bool, budget *decompressionBudget, add addArchiveEntryFunc) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("could not open %s: %w", archivePath, err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	entryReader := budget.reader
+	if gzipped {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("could not decompress %s: %w", archivePath, err)
+		}
+		defer gz.Close()
+
+		// the entries that are skipped still have to be decompressed to get to those after them,
+		// so everything that is decompressed counts towards the budget rather than only what is read
+		r = budget.reader(gz)
+		entryReader = func(r io.Reader) io.Reader { return r }
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			if errors.Is(err, ErrArchiveTooLarge) {
+				return err
+			}
+
+			return fmt.Errorf("could not read %s: %w", archivePath, err)
+		}
+
+		err = add(hdr.Name, hdr.FileInfo(), func() (io.Reader, error) {
+			return entryReader(tr), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// archiveEntryName cleans the name of a file within an archive into a path that can be used
+// within the in-memory filesystem, returning false if it cannot be (such as if it is outside
+// of the archive)
+func archiveEntryName(name string) (string, bool) {
+	name = path.Clean(strings.TrimPrefix(strings.ReplaceAll(name, "\\", "/"), "/"))
+
+	return name, fs.ValidPath(name) && name != "."
+}
+
+// decompressionBudget tracks how much more can be decompressed from an archive
+type decompressionBudget struct {
+	archive   string
+	limit     int64
+	remaining int64
+}
+
+// reader wraps r so that reading from it counts towards the budget,
+// returning an ErrArchiveTooLarge error once the budget has been exceeded
+func (b *decompressionBudget) reader(r io.Reader) io.Reader {
+	return &budgetedReader{r: r, budget: b}
+}
+
+func (b *decompressionBudget) err() error {
+	return fmt.Errorf("%w: %s decompresses to more than %s, which is the most that can be scanned", ErrArchiveTooLarge, b.archive, formatBytes(b.limit))
+}
+
+type budgetedReader struct {
+	r      io.Reader
+	budget *decompressionBudget
+}
+
+func (r *budgetedReader) Read(p []byte) (int, error) {
+	if r.budget.remaining < 0 {
+		return 0, r.budget.err()
+	}
+
+	// read at most one byte past the budget, which is enough to know that it has been exceeded
+	if int64(len(p)) > r.budget.remaining+1 {
+		p = p[:r.budget.remaining+1]
+	}
+
+	n, err := r.r.Read(p)
+	r.budget.remaining -= int64(n)
+	if r.budget.remaining < 0 {
+		return n, r.budget.err()
+	}
+
+	return n, err
+}
+
+// formatBytes formats the number of bytes in the largest unit that it is a whole number of
+func formatBytes(n int64) string {
+	for _, unit := range []struct {
+		size int64
+		name string
+	}{{1 << 30, "GiB"}, {1 << 20, "MiB"}, {1 << 10, "KiB"}} {
+		if n >= unit.size && n%unit.size == 0 {
+			return fmt.Sprintf("%d %s", n/unit.size, unit.name)
+		}
+	}
+
+	return fmt.Sprintf("%d bytes", n)
+}
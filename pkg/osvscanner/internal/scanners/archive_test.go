@@ -0,0 +1,247 @@
+package scanners
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// archiveEntry is a file to write into an archive for testing
+type archiveEntry struct {
+	name    string
+	content string
+}
+
+// setupArchive writes the entries into an archive with the given name, in the format of its extension
+func setupArchive(t *testing.T, name string, entries ...archiveEntry) string {
+	t.Helper()
+
+	archivePath := filepath.Join(t.TempDir(), name)
+	f, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	write := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if strings.HasSuffix(name, ".zip") {
+		zw := zip.NewWriter(f)
+		for _, entry := range entries {
+			w, err := zw.Create(entry.name)
+			write(err)
+			_, err = io.WriteString(w, entry.content)
+			write(err)
+		}
+		write(zw.Close())
+
+		return archivePath
+	}
+
+	var w io.Writer = f
+	if strings.HasSuffix(name, ".gz") || strings.HasSuffix(name, ".tgz") {
+		gz := gzip.NewWriter(f)
+		defer func() { write(gz.Close()) }()
+		w = gz
+	}
+
+	tw := tar.NewWriter(w)
+	for _, entry := range entries {
+		write(tw.WriteHeader(&tar.Header{Name: entry.name, Mode: 0644, Size: int64(len(entry.content)), Typeflag: tar.TypeReg}))
+		_, err := io.WriteString(tw, entry.content)
+		write(err)
+	}
+	write(tw.Close())
+
+	return archivePath
+}
+
+func readFixture(t *testing.T, path string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(content)
+}
+
+func TestScanArchive(t *testing.T) {
+	t.Parallel()
+
+	lockfile := readFixture(t, "testdata/package-lock.prod.json")
+	sbom := readFixture(t, "testdata/ecosystems.cdx.json")
+
+	for _, name := range []string{"inputs.zip", "inputs.tar", "inputs.tar.gz", "inputs.tgz"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			archivePath := setupArchive(t, name,
+				archiveEntry{"package-lock.json", lockfile},
+				archiveEntry{"app/package-lock.json", lockfile},
+				archiveEntry{"sbom/ecosystems.cdx.json", sbom},
+				archiveEntry{"README.md", "not a lockfile"},
+				archiveEntry{"nested.zip", "not scanned"},
+			)
+
+			invs, fileErrs, err := ScanArchive(context.Background(), archivePath, slices.Concat(BuildLockfileExtractors(nil, nil), BuildSBOMExtractors()))
+			if err != nil {
+				t.Fatalf("ScanArchive() error = %v", err)
+			}
+			if len(fileErrs) > 0 {
+				t.Errorf("ScanArchive() returned unexpected file errors: %v", fileErrs)
+			}
+
+			var locations []string
+			for _, inv := range invs {
+				if !slices.Contains(locations, inv.Locations[0]) {
+					locations = append(locations, inv.Locations[0])
+				}
+			}
+
+			want := []string{
+				archivePath + ":app/package-lock.json",
+				archivePath + ":package-lock.json",
+				archivePath + ":sbom/ecosystems.cdx.json",
+			}
+			if diff := cmp.Diff(want, locations); diff != "" {
+				t.Errorf("ScanArchive() locations mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestScanArchive_FileErrors(t *testing.T) {
+	t.Parallel()
+
+	archivePath := setupArchive(t, "inputs.zip",
+		archiveEntry{"package-lock.json", readFixture(t, "testdata/package-lock.prod.json")},
+		archiveEntry{"broken/package-lock.json", "{"},
+	)
+
+	invs, fileErrs, err := ScanArchive(context.Background(), archivePath, BuildLockfileExtractors(nil, nil))
+	if err != nil {
+		t.Fatalf("ScanArchive() error = %v", err)
+	}
+	if len(invs) == 0 {
+		t.Errorf("ScanArchive() found no packages, but the valid lockfile should still be scanned")
+	}
+	if len(fileErrs) != 1 || fileErrs[0].Path != archivePath+":broken/package-lock.json" {
+		t.Errorf("ScanArchive() file errors = %v, want one for broken/package-lock.json", fileErrs)
+	}
+}
+
+func TestScanArchive_Limits(t *testing.T) {
+	t.Parallel()
+
+	lockfile := readFixture(t, "testdata/package-lock.prod.json")
+	padding := strings.Repeat("a", 4096)
+
+	tests := []struct {
+		name    string
+		archive string
+		entries []archiveEntry
+		limits  archiveLimits
+		wantErr bool
+	}{
+		{
+			name:    "within limits",
+			archive: "inputs.tar.gz",
+			entries: []archiveEntry{{"package-lock.json", lockfile}},
+			limits:  archiveLimits{fileSize: int64(len(lockfile)), totalSize: int64(len(lockfile)) + 4096},
+			wantErr: false,
+		},
+		{
+			name:    "lockfile is too large",
+			archive: "inputs.zip",
+			entries: []archiveEntry{{"package-lock.json", lockfile}},
+			limits:  archiveLimits{fileSize: int64(len(lockfile)) - 1, totalSize: 1 << 20},
+			wantErr: true,
+		},
+		{
+			name:    "lockfiles are too large in total",
+			archive: "inputs.zip",
+			entries: []archiveEntry{{"a/package-lock.json", lockfile}, {"b/package-lock.json", lockfile}},
+			limits:  archiveLimits{fileSize: 1 << 20, totalSize: int64(len(lockfile)) + 1},
+			wantErr: true,
+		},
+		{
+			name:    "skipped files of a zip archive are not decompressed",
+			archive: "inputs.zip",
+			entries: []archiveEntry{{"padding.txt", padding}, {"package-lock.json", lockfile}},
+			limits:  archiveLimits{fileSize: 1 << 20, totalSize: int64(len(lockfile))},
+			wantErr: false,
+		},
+		{
+			name:    "skipped files of a compressed tar archive are decompressed",
+			archive: "inputs.tar.gz",
+			entries: []archiveEntry{{"padding.txt", padding}, {"package-lock.json", lockfile}},
+			limits:  archiveLimits{fileSize: 1 << 20, totalSize: 4096},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			archivePath := setupArchive(t, tt.archive, tt.entries...)
+
+			_, _, err := scanArchive(context.Background(), archivePath, BuildLockfileExtractors(nil, nil), tt.limits)
+			if tt.wantErr && !errors.Is(err, ErrArchiveTooLarge) {
+				t.Errorf("scanArchive() error = %v, want ErrArchiveTooLarge", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("scanArchive() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestScanArchive_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := ScanArchive(context.Background(), "testdata/package-lock.prod.json", BuildLockfileExtractors(nil, nil))
+	if err == nil {
+		t.Errorf("ScanArchive() error = nil, want an error as the file is not an archive")
+	}
+}
+
+func TestOnDiskPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		location string
+		want     string
+	}{
+		{location: "/path/to/package-lock.json", want: "/path/to/package-lock.json"},
+		{location: "/path/to/app.zip:package-lock.json", want: "/path/to/app.zip"},
+		{location: "/path/to/app.tar.gz:nested/app.zip:yarn.lock", want: "/path/to/app.tar.gz"},
+		{location: `C:\path\to\app.TGZ:package/package-lock.json`, want: `C:\path\to\app.TGZ`},
+		{location: "/path/to/my:project/package-lock.json", want: "/path/to/my:project/package-lock.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.location, func(t *testing.T) {
+			t.Parallel()
+
+			if got := OnDiskPath(tt.location); got != tt.want {
+				t.Errorf("OnDiskPath(%q) = %q, want %q", tt.location, got, tt.want)
+			}
+		})
+	}
+}
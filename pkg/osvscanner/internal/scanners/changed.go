@@ -0,0 +1,138 @@
+package scanners
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ChangedDirs returns the directories within dir containing files that have been
+// changed by the commits made since ref, which are the directories that need to be
+// scanned to check those changes, as manifests and their lockfiles are kept alongside
+// each other.
+//
+// Changes are compared from the merge base of ref and HEAD, like for a pull request,
+// and only directories which still exist are returned. If recursive is false, only
+// changes to files directly within dir are considered.
+func ChangedDirs(dir string, ref string, recursive bool, skipDirs []string) ([]string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return nil, fmt.Errorf("cannot determine files changed since %s, as %s is not in a git repository", ref, dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := changesSince(repo, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, change := range changes {
+		// renamed files have changed in both their old and new directory
+		for _, name := range []string{change.From.Name, change.To.Name} {
+			if name == "" {
+				continue
+			}
+
+			changedDir := filepath.Dir(filepath.Join(worktree.Filesystem.Root(), filepath.FromSlash(name)))
+
+			if slices.Contains(dirs, changedDir) || !isChangedDirScanned(dir, changedDir, recursive, skipDirs) {
+				continue
+			}
+
+			if info, err := os.Stat(changedDir); err != nil || !info.IsDir() {
+				continue
+			}
+
+			dirs = append(dirs, changedDir)
+		}
+	}
+
+	slices.Sort(dirs)
+
+	return dirs, nil
+}
+
+// changesSince returns the changes made by the commits on HEAD since it and the
+// given ref diverged
+func changesSince(repo *git.Repository, ref string) (object.Changes, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve %s: %w", ref, err)
+	}
+
+	base, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve %s: %w", ref, err)
+	}
+
+	headRef, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve HEAD: %w", err)
+	}
+
+	head, err := repo.CommitObject(headRef.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve HEAD: %w", err)
+	}
+
+	if bases, err := base.MergeBase(head); err == nil && len(bases) > 0 {
+		base = bases[0]
+	}
+
+	baseTree, err := base.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	headTree, err := head.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	return object.DiffTree(baseTree, headTree)
+}
+
+// isChangedDirScanned returns true if the changed directory would be
+// scanned as part of scanning dir
+func isChangedDirScanned(dir string, changedDir string, recursive bool, skipDirs []string) bool {
+	rel, err := filepath.Rel(dir, changedDir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+
+	if rel == "." {
+		return true
+	}
+
+	if !recursive {
+		return false
+	}
+
+	for _, name := range strings.Split(rel, string(filepath.Separator)) {
+		if name == ".git" || matchesSkipDir(name, skipDirs) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,129 @@
+package scanners
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/google/go-cmp/cmp"
+)
+
+// commitAll stages every file in the repository and commits them
+func commitAll(t *testing.T, repo *git.Repository, message string) string {
+	t.Helper()
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := worktree.AddWithOptions(&git.AddOptions{All: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := worktree.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{Name: "osv-scanner", Email: "osv-scanner@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return hash.String()
+}
+
+func TestChangedDirs(t *testing.T) {
+	t.Parallel()
+
+	dir := setupWalkDir(t,
+		"package-lock.json",
+		"app/package-lock.json",
+		"lib/package-lock.json",
+		"old/package-lock.json",
+		"third_party/dep/package-lock.json",
+	)
+
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := commitAll(t, repo, "initial commit")
+
+	if err := os.WriteFile(filepath.Join(dir, "app", "package.json"), []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "third_party", "dep", "package.json"), []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(dir, "old")); err != nil {
+		t.Fatal(err)
+	}
+
+	commitAll(t, repo, "update dependencies")
+
+	// uncommitted changes are not included
+	if err := os.WriteFile(filepath.Join(dir, "lib", "package.json"), []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		recursive bool
+		skipDirs  []string
+		want      []string
+	}{
+		{
+			name:      "recursive",
+			recursive: true,
+			want:      []string{"app", "third_party/dep"},
+		},
+		{
+			name:      "skipped directories",
+			recursive: true,
+			skipDirs:  []string{"third_party"},
+			want:      []string{"app"},
+		},
+		{
+			name:      "not recursive",
+			recursive: false,
+			want:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dirs, err := ChangedDirs(dir, base, tt.recursive, tt.skipDirs)
+			if err != nil {
+				t.Fatalf("ChangedDirs() error = %v", err)
+			}
+
+			var got []string
+			for _, d := range dirs {
+				rel, err := filepath.Rel(dir, d)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, filepath.ToSlash(rel))
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ChangedDirs() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestChangedDirs_NotAGitRepository(t *testing.T) {
+	t.Parallel()
+
+	_, err := ChangedDirs(setupWalkDir(t, "package-lock.json"), "main", true, nil)
+
+	if err == nil || !strings.Contains(err.Error(), "is not in a git repository") {
+		t.Errorf("ChangedDirs() error = %v, want an error about not being in a git repository", err)
+	}
+}
@@ -5,11 +5,9 @@ import (
 	"github.com/google/osv-scalibr/clients/datasource"
 	"github.com/google/osv-scalibr/extractor/filesystem"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/cpp/conanlock"
-	"github.com/google/osv-scalibr/extractor/filesystem/language/dart/pubspec"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/dotnet/depsjson"
-	"github.com/google/osv-scalibr/extractor/filesystem/language/erlang/mixlock"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/dotnet/packagesconfig"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/golang/gobinary"
-	"github.com/google/osv-scalibr/extractor/filesystem/language/golang/gomod"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/haskell/cabal"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/haskell/stacklock"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/java/archive"
@@ -19,25 +17,42 @@ import (
 	"github.com/google/osv-scalibr/extractor/filesystem/language/java/pomxmlnet"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/javascript/bunlock"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/javascript/packagelockjson"
-	"github.com/google/osv-scalibr/extractor/filesystem/language/javascript/pnpmlock"
-	"github.com/google/osv-scalibr/extractor/filesystem/language/javascript/yarnlock"
-	"github.com/google/osv-scalibr/extractor/filesystem/language/php/composerlock"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/python/pdmlock"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/python/pipfilelock"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/python/poetrylock"
-	"github.com/google/osv-scalibr/extractor/filesystem/language/python/requirements"
-	"github.com/google/osv-scalibr/extractor/filesystem/language/python/uvlock"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/python/wheelegg"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/r/renvlock"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/ruby/gemfilelock"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/rust/cargoauditable"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/rust/cargolock"
 	"github.com/google/osv-scalibr/extractor/filesystem/os/apk"
 	"github.com/google/osv-scalibr/extractor/filesystem/os/dpkg"
+	"github.com/google/osv-scalibr/extractor/filesystem/os/rpm"
 	"github.com/google/osv-scalibr/extractor/filesystem/sbom/cdx"
-	"github.com/google/osv-scalibr/extractor/filesystem/sbom/spdx"
 	"github.com/google/osv-scanner/v2/internal/osvdev"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/filesystem/vendored"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/conda/condalock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/conda/environmentyml"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/dart/pubspec"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/dotnet/packageslockjson"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/erlang/mixlock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/golang/gomod"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/golang/gowork"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/java/effectivepom"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/java/gradleversioncatalog"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/java/mvndependencytree"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/javascript/bunlockb"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/javascript/nodemodules"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/javascript/pnpmlock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/javascript/yarnlock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/nix/flakelock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/php/composerlock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/python/requirements"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/python/uvlock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/rust/cargotoml"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/swift/packageresolved"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/terraform/terraformlock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/sbom/spdx"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/vcs/gitrepo"
 	"github.com/ossf/osv-schema/bindings/go/osvschema"
 )
@@ -51,6 +66,10 @@ var lockfileExtractors = []filesystem.Extractor{
 	// C
 	conanlock.Extractor{},
 
+	// Conda
+	environmentyml.Extractor{},
+	condalock.Extractor{},
+
 	// Erlang
 	mixlock.Extractor{},
 
@@ -59,16 +78,21 @@ var lockfileExtractors = []filesystem.Extractor{
 
 	// Go
 	gomod.Extractor{},
+	gowork.Extractor{},
 
 	// Java
 	gradlelockfile.Extractor{},
 	gradleverificationmetadataxml.Extractor{},
+	gradleversioncatalog.Extractor{},
+	effectivepom.Extractor{},
+	mvndependencytree.Extractor{},
 
 	// Javascript
 	packagelockjson.Extractor{},
 	pnpmlock.Extractor{},
 	yarnlock.Extractor{},
 	bunlock.Extractor{},
+	bunlockb.Extractor{},
 
 	// PHP
 	composerlock.Extractor{},
@@ -88,17 +112,25 @@ var lockfileExtractors = []filesystem.Extractor{
 
 	// Rust
 	cargolock.Extractor{},
+	cargotoml.Extractor{},
+
+	// Nix
+	flakelock.Extractor{},
 
 	// NuGet
 	depsjson.Extractor{},
+	packagesconfig.Extractor{},
+	packageslockjson.Extractor{},
 
 	// Haskell
 	cabal.Extractor{},
 	stacklock.Extractor{},
-	// TODO: map the extracted packages to SwiftURL in OSV.dev
-	// The extracted package names do not match the package names of SwiftURL in OSV.dev,
-	// so we need to find a workaround to map the names.
-	// packageresolved.Extractor{},
+
+	// Swift
+	packageresolved.Extractor{},
+
+	// Terraform
+	terraformlock.Extractor{},
 }
 
 // BuildLockfileExtractors returns all relevant extractors for lockfile scanning given the required clients
@@ -173,6 +205,8 @@ func BuildArtifactExtractors() []filesystem.Extractor {
 		gobinary.New(gobinary.DefaultConfig()),
 		// Javascript
 		nodemodules.Extractor{},
+		// Rust
+		cargoauditable.New(cargoauditable.DefaultConfig()),
 
 		// --- OS packages ---
 		// Alpine
@@ -180,6 +214,8 @@ func BuildArtifactExtractors() []filesystem.Extractor {
 		// Debian
 		// TODO: Add tests for debian containers
 		dpkg.New(dpkg.DefaultConfig()),
+		// Red Hat, Rocky Linux and AlmaLinux (both the bdb and sqlite databases)
+		rpm.New(rpm.DefaultConfig()),
 	}
 
 	return extractorsToUse
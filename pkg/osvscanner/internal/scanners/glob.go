@@ -0,0 +1,121 @@
+package scanners
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ExpandLockfileGlob returns the lockfile arguments for each file matched by the path of the
+// lockfile argument, which can be a glob pattern supporting the same wildcards as filepath.Match
+// along with ** to match any number of directories. The format of the lockfile argument (if any)
+// is kept for each of the matched files.
+//
+// Paths that are not patterns, or which are the path of an existing file, are returned as is.
+func ExpandLockfileGlob(scanArg string) ([]string, error) {
+	parseAs, path := parseLockfilePath(scanArg)
+
+	if path == "-" || !strings.ContainsAny(path, "*?[") {
+		return []string{scanArg}, nil
+	}
+
+	// files can have wildcards in their name (e.g. "[id]" directories of Next.js projects)
+	if _, err := os.Stat(path); err == nil {
+		return []string{scanArg}, nil
+	}
+
+	matches, err := globFiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lockfile pattern %q: %w", path, err)
+	}
+
+	args := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if parseAs == "" && !strings.Contains(match, ":") {
+			args = append(args, match)
+		} else {
+			args = append(args, parseAs+":"+match)
+		}
+	}
+
+	return args, nil
+}
+
+// globFiles returns the files matched by the pattern, in lexical order
+func globFiles(pattern string) ([]string, error) {
+	segments := strings.Split(filepath.Clean(pattern), string(filepath.Separator))
+	for _, segment := range segments {
+		if _, err := filepath.Match(segment, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	// the directories before the first wildcard are where the files are searched for
+	i := slices.IndexFunc(segments, func(segment string) bool {
+		return strings.ContainsAny(segment, "*?[")
+	})
+	root := strings.Join(segments[:i], string(filepath.Separator))
+	switch {
+	case i == 0:
+		root = "."
+	case root == "" || filepath.VolumeName(root) == root:
+		root += string(filepath.Separator)
+	}
+
+	// a trailing ** matches every file within the directories it matches
+	segments = segments[i:]
+	if segments[len(segments)-1] == "**" {
+		segments = append(segments, "*")
+	}
+
+	var matches []string
+	globDir(root, segments, &matches)
+	slices.Sort(matches)
+
+	// the same file can be matched more than once by patterns with several **
+	return slices.Compact(matches), nil
+}
+
+// globDir adds the files within dir that are matched by the segments of a pattern to matches,
+// with directories that cannot be read being treated as if they were empty
+func globDir(dir string, segments []string, matches *[]string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	if segments[0] == "**" {
+		globDir(dir, segments[1:], matches)
+
+		for _, entry := range entries {
+			// symlinks are not followed, as they could create a cycle
+			if entry.IsDir() && entry.Name() != ".git" {
+				globDir(filepath.Join(dir, entry.Name()), segments, matches)
+			}
+		}
+
+		return
+	}
+
+	for _, entry := range entries {
+		if matched, _ := filepath.Match(segments[0], entry.Name()); !matched {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		if len(segments) == 1 {
+			if !info.IsDir() {
+				*matches = append(*matches, path)
+			}
+		} else if info.IsDir() {
+			globDir(path, segments[1:], matches)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package scanners
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExpandLockfileGlob(t *testing.T) {
+	t.Parallel()
+
+	dir := setupWalkDir(t,
+		"package-lock.json",
+		"services/api/package-lock.json",
+		"services/web/package-lock.json",
+		"services/web/yarn.lock",
+		"services/legacy/v1/package-lock.json",
+		"app/[id]/package-lock.json",
+		".git/package-lock.json",
+	)
+
+	tests := []struct {
+		name    string
+		arg     string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "not a pattern",
+			arg:  "package-lock.json:" + filepath.Join(dir, "does-not-exist.json"),
+			want: []string{"package-lock.json:" + filepath.Join(dir, "does-not-exist.json")},
+		},
+		{
+			name: "stdin",
+			arg:  "package-lock.json:-",
+			want: []string{"package-lock.json:-"},
+		},
+		{
+			name: "existing file with wildcards in its path",
+			arg:  filepath.Join(dir, "app", "[id]", "package-lock.json"),
+			want: []string{filepath.Join(dir, "app", "[id]", "package-lock.json")},
+		},
+		{
+			name: "wildcard",
+			arg:  filepath.Join(dir, "services", "*", "package-lock.json"),
+			want: []string{
+				filepath.Join(dir, "services", "api", "package-lock.json"),
+				filepath.Join(dir, "services", "web", "package-lock.json"),
+			},
+		},
+		{
+			name: "wildcard with a format",
+			arg:  "package-lock.json:" + filepath.Join(dir, "services", "*", "yarn.lock"),
+			want: []string{"package-lock.json:" + filepath.Join(dir, "services", "web", "yarn.lock")},
+		},
+		{
+			name: "recursive",
+			arg:  filepath.Join(dir, "**", "package-lock.json"),
+			want: []string{
+				filepath.Join(dir, "app", "[id]", "package-lock.json"),
+				filepath.Join(dir, "package-lock.json"),
+				filepath.Join(dir, "services", "api", "package-lock.json"),
+				filepath.Join(dir, "services", "legacy", "v1", "package-lock.json"),
+				filepath.Join(dir, "services", "web", "package-lock.json"),
+			},
+		},
+		{
+			name: "trailing recursive",
+			arg:  filepath.Join(dir, "services", "**"),
+			want: []string{
+				filepath.Join(dir, "services", "api", "package-lock.json"),
+				filepath.Join(dir, "services", "legacy", "v1", "package-lock.json"),
+				filepath.Join(dir, "services", "web", "package-lock.json"),
+				filepath.Join(dir, "services", "web", "yarn.lock"),
+			},
+		},
+		{
+			name: "repeated recursive",
+			arg:  filepath.Join(dir, "**", "services", "**", "yarn.lock"),
+			want: []string{filepath.Join(dir, "services", "web", "yarn.lock")},
+		},
+		{
+			name: "no matches",
+			arg:  filepath.Join(dir, "**", "Cargo.lock"),
+			want: []string{},
+		},
+		{
+			name:    "invalid pattern",
+			arg:     filepath.Join(dir, "[", "*.lock"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ExpandLockfileGlob(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExpandLockfileGlob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ExpandLockfileGlob() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
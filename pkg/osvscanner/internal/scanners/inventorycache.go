@@ -0,0 +1,368 @@
+package scanners
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/dotnet/depsjson"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/javalockfile"
+	scalibrosv "github.com/google/osv-scalibr/extractor/filesystem/osv"
+	"github.com/google/osv-scalibr/extractor/filesystem/sbom/cdx"
+	scalibrspdx "github.com/google/osv-scalibr/extractor/filesystem/sbom/spdx"
+	"github.com/google/osv-scalibr/extractor/filesystem/simplefileapi"
+	"github.com/google/osv-scanner/v2/internal/cachefile"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/conda/condalock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/conda/environmentyml"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/dart/pubspec"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/dotnet/packageslockjson"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/erlang/mixlock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/python/requirements"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/rust/cargotoml"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/terraform/terraformlock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/sbom/spdx"
+	"github.com/google/osv-scanner/v2/internal/version"
+)
+
+const (
+	inventoryCacheFileName = "extracted_packages.json"
+
+	// inventoryCacheVersion is incremented whenever the format of the cache changes,
+	// so that caches written by other versions of the scanner are discarded
+	inventoryCacheVersion = 1
+)
+
+// cacheableMetadata are the types of the metadata of packages that can be cached, by their name,
+// as the metadata of a package is decoded into a new value of the type that it had when cached
+var cacheableMetadata = func() map[string]reflect.Type {
+	types := make(map[string]reflect.Type)
+	for _, m := range []any{
+		scalibrosv.DepGroupMetadata{},
+		javalockfile.Metadata{},
+		&javalockfile.Metadata{},
+		&depsjson.Metadata{},
+		&cdx.Metadata{},
+		&scalibrspdx.Metadata{},
+		&spdx.Metadata{},
+		&condalock.Metadata{},
+		&environmentyml.Metadata{},
+		&requirements.Metadata{},
+		&pubspec.Metadata{},
+		&packageslockjson.Metadata{},
+		&mixlock.Metadata{},
+		cargotoml.Metadata{},
+		&terraformlock.Metadata{},
+	} {
+		types[fmt.Sprintf("%T", m)] = reflect.TypeOf(m)
+	}
+
+	return types
+}()
+
+// InventoryCache persists the packages that were extracted from each file during a scan, so that
+// later scans can reuse them for the files that have not changed since, rather than extracting them again.
+//
+// Files are identified by a hash of their content, and are only reused if they would be extracted by
+// the same extractors. Files whose packages have metadata that cannot be cached, or which are extracted
+// by extractors that need the network (whose results can change without the file changing), are
+// always extracted.
+type InventoryCache struct {
+	// CachePath is the file that the extracted packages are cached in
+	CachePath string
+
+	mu     sync.Mutex
+	cache  inventoryCacheFile
+	reused int
+}
+
+type inventoryCacheFile struct {
+	Version        int                   `json:"version"`
+	ScannerVersion string                `json:"scanner_version"`
+	Files          map[string]cachedFile `json:"files"`
+}
+
+// cachedFile is a file that packages were extracted from, by its absolute path
+type cachedFile struct {
+	Hash string `json:"hash"`
+	// Extractors are the names of the extractors that the file was extracted by
+	Extractors []string          `json:"extractors"`
+	Packages   []cachedInventory `json:"packages"`
+}
+
+// cachedInventory is a package that was extracted from a file, which is always located in that file
+type cachedInventory struct {
+	Name         string                          `json:"name"`
+	Version      string                          `json:"version"`
+	SourceCode   *extractor.SourceCodeIdentifier `json:"source_code,omitempty"`
+	Extractor    string                          `json:"extractor"`
+	Annotations  []extractor.Annotation          `json:"annotations,omitempty"`
+	MetadataType string                          `json:"metadata_type,omitempty"`
+	Metadata     json.RawMessage                 `json:"metadata,omitempty"`
+}
+
+// NewInventoryCache creates a cache of the packages extracted from files within cacheDir,
+// reading the packages that previous scans cached there
+func NewInventoryCache(cacheDir string) *InventoryCache {
+	c := &InventoryCache{CachePath: filepath.Join(cacheDir, inventoryCacheFileName)}
+	c.read()
+
+	return c
+}
+
+// Reused returns the number of files whose cached packages have been reused
+func (c *InventoryCache) Reused() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.reused
+}
+
+// extract extracts the file at path with the extractors that require it, reusing the packages
+// that were cached for it if it has not changed since they were extracted
+func (c *InventoryCache) extract(ctx context.Context, path string, extractorsToUse []filesystem.Extractor) ([]*extractor.Inventory, error) {
+	key, err := filepath.Abs(path)
+	if err != nil {
+		return scalibrextract.ExtractWithExtractors(ctx, path, extractorsToUse)
+	}
+
+	hash := hashFile(key)
+	required := requiredExtractors(path, extractorsToUse)
+	if hash == "" || len(required) == 0 || needsNetworkExtractor(path, extractorsToUse) {
+		return scalibrextract.ExtractWithExtractors(ctx, path, extractorsToUse)
+	}
+
+	if invs, ok := c.lookup(key, hash, required, path, extractorsToUse); ok {
+		slog.Debug("Reusing the packages previously extracted from " + path + ", as it has not changed")
+
+		return invs, nil
+	}
+
+	invs, err := scalibrextract.ExtractWithExtractors(ctx, path, extractorsToUse)
+	if err == nil {
+		c.store(key, hash, required, path, invs, extractorsToUse)
+	}
+
+	return invs, err
+}
+
+// requiredExtractors returns the names of the extractors that require the file at path
+func requiredExtractors(path string, extractorsToUse []filesystem.Extractor) []string {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return nil
+	}
+
+	var names []string
+	for _, ext := range extractorsToUse {
+		if ext.FileRequired(simplefileapi.New(path, info)) {
+			names = append(names, ext.Name())
+		}
+	}
+
+	return names
+}
+
+// hashFile returns a hash of the content of the file, or an empty string if it cannot be read
+func hashFile(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// lookup returns the packages cached for the file, if it has not changed and
+// would be extracted by the same extractors as when they were cached
+func (c *InventoryCache) lookup(key string, hash string, required []string, path string, extractorsToUse []filesystem.Extractor) ([]*extractor.Inventory, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.cache.Files[key]
+	if !ok || cached.Hash != hash || !slices.Equal(cached.Extractors, required) {
+		return nil, false
+	}
+
+	invs, err := restoreInventories(cached.Packages, path, extractorsToUse)
+	if err != nil {
+		slog.Debug(fmt.Sprintf("Could not reuse the packages previously extracted from %s: %v", path, err))
+
+		return nil, false
+	}
+
+	c.reused++
+
+	return invs, true
+}
+
+// store caches the packages extracted from the file, as long as they can be
+// restored exactly as they were extracted
+func (c *InventoryCache) store(key string, hash string, required []string, path string, invs []*extractor.Inventory, extractorsToUse []filesystem.Extractor) {
+	packages := make([]cachedInventory, 0, len(invs))
+	for _, inv := range invs {
+		p, ok := cacheInventory(inv, path)
+		if !ok {
+			return
+		}
+		packages = append(packages, p)
+	}
+
+	restored, err := restoreInventories(packages, path, extractorsToUse)
+	if err != nil || !reflect.DeepEqual(restored, invs) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cache.Files[key] = cachedFile{Hash: hash, Extractors: required, Packages: packages}
+}
+
+// cacheInventory returns the package as it is to be cached, along with false
+// if it cannot be cached
+func cacheInventory(inv *extractor.Inventory, path string) (cachedInventory, bool) {
+	if inv.Extractor == nil || inv.LayerDetails != nil {
+		return cachedInventory{}, false
+	}
+
+	for _, location := range inv.Locations {
+		if location != filepath.Clean(path) {
+			return cachedInventory{}, false
+		}
+	}
+
+	p := cachedInventory{
+		Name:        inv.Name,
+		Version:     inv.Version,
+		SourceCode:  inv.SourceCode,
+		Extractor:   inv.Extractor.Name(),
+		Annotations: inv.Annotations,
+	}
+
+	if inv.Metadata != nil {
+		p.MetadataType = fmt.Sprintf("%T", inv.Metadata)
+		if _, ok := cacheableMetadata[p.MetadataType]; !ok {
+			return cachedInventory{}, false
+		}
+
+		metadata, err := json.Marshal(inv.Metadata)
+		if err != nil {
+			return cachedInventory{}, false
+		}
+		p.Metadata = metadata
+	}
+
+	return p, true
+}
+
+// restoreInventories returns the cached packages of the file at path as if they had just been extracted from it
+func restoreInventories(packages []cachedInventory, path string, extractorsToUse []filesystem.Extractor) ([]*extractor.Inventory, error) {
+	invs := make([]*extractor.Inventory, 0, len(packages))
+	for _, p := range packages {
+		i := slices.IndexFunc(extractorsToUse, func(ext filesystem.Extractor) bool { return ext.Name() == p.Extractor })
+		if i < 0 {
+			return nil, fmt.Errorf("extractor %s is not being used", p.Extractor)
+		}
+
+		inv := &extractor.Inventory{
+			Name:        p.Name,
+			Version:     p.Version,
+			SourceCode:  p.SourceCode,
+			Locations:   []string{filepath.Clean(path)},
+			Extractor:   extractorsToUse[i],
+			Annotations: p.Annotations,
+		}
+
+		if p.MetadataType != "" {
+			t, ok := cacheableMetadata[p.MetadataType]
+			if !ok {
+				return nil, fmt.Errorf("metadata of type %s cannot be cached", p.MetadataType)
+			}
+
+			metadata := reflect.New(t)
+			if t.Kind() == reflect.Pointer {
+				metadata.Elem().Set(reflect.New(t.Elem()))
+			}
+			if err := json.Unmarshal(p.Metadata, metadata.Interface()); err != nil {
+				return nil, err
+			}
+			inv.Metadata = metadata.Elem().Interface()
+		}
+
+		invs = append(invs, inv)
+	}
+
+	return invs, nil
+}
+
+// read reads the cached packages, starting with an empty cache if there is no usable cache
+func (c *InventoryCache) read() {
+	c.cache = inventoryCacheFile{
+		Version:        inventoryCacheVersion,
+		ScannerVersion: version.OSVVersion,
+		Files:          make(map[string]cachedFile),
+	}
+
+	content, err := os.ReadFile(c.CachePath)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			slog.Warn(fmt.Sprintf("Could not read cached packages at %s: %v", c.CachePath, err))
+		}
+
+		return
+	}
+
+	var cache inventoryCacheFile
+	if err := json.Unmarshal(content, &cache); err != nil {
+		slog.Warn(fmt.Sprintf("Cached packages at %s are invalid, so are being discarded: %v", c.CachePath, err))
+
+		return
+	}
+
+	if cache.Version != inventoryCacheVersion || cache.ScannerVersion != version.OSVVersion || cache.Files == nil {
+		slog.Debug("Discarding cached packages at " + c.CachePath + ", as they were extracted differently")
+
+		return
+	}
+
+	c.cache = cache
+}
+
+// Write writes the cached packages, leaving out those of the files that no longer exist
+func (c *InventoryCache) Write() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.cache.Files {
+		if _, err := os.Stat(key); errors.Is(err, fs.ErrNotExist) {
+			delete(c.cache.Files, key)
+		}
+	}
+
+	content, err := json.Marshal(c.cache)
+	if err != nil {
+		return err
+	}
+
+	return cachefile.Write(c.CachePath, content)
+}
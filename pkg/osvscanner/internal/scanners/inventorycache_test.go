@@ -0,0 +1,82 @@
+package scanners
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+)
+
+func scanDirWithCache(t *testing.T, dir string, cacheDir string) ([]*extractor.Inventory, int) {
+	t.Helper()
+
+	cache := NewInventoryCache(cacheDir)
+	invs, _, err := ScanDir(context.Background(), dir, true, false, nil, 0, BuildWalkerExtractors(false, nil, nil, nil), cache)
+	if err != nil {
+		t.Fatalf("ScanDir() error = %v", err)
+	}
+
+	if err := cache.Write(); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	return invs, cache.Reused()
+}
+
+func TestInventoryCache(t *testing.T) {
+	t.Parallel()
+
+	dir := setupWalkDir(t, "package-lock.json", "app/package-lock.json")
+	cacheDir := t.TempDir()
+
+	first, reused := scanDirWithCache(t, dir, cacheDir)
+	if reused != 0 {
+		t.Errorf("first scan reused %d files, want 0", reused)
+	}
+
+	second, reused := scanDirWithCache(t, dir, cacheDir)
+	if reused != 2 {
+		t.Errorf("second scan reused %d files, want 2", reused)
+	}
+
+	opts := cmp.Comparer(func(a, b filesystem.Extractor) bool { return a.Name() == b.Name() })
+	if diff := cmp.Diff(first, second, opts); diff != "" {
+		t.Errorf("reused packages mismatch (-extracted +reused):\n%s", diff)
+	}
+
+	// files that have changed are extracted again
+	content, err := os.ReadFile("testdata/package-lock.prod.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "app", "package-lock.json"), append(content, '\n'), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, reused := scanDirWithCache(t, dir, cacheDir); reused != 1 {
+		t.Errorf("scan after a change reused %d files, want 1", reused)
+	}
+}
+
+func TestInventoryCache_DifferentScannerVersion(t *testing.T) {
+	t.Parallel()
+
+	dir := setupWalkDir(t, "package-lock.json")
+	cacheDir := t.TempDir()
+
+	scanDirWithCache(t, dir, cacheDir)
+
+	cache := NewInventoryCache(cacheDir)
+	cache.cache.ScannerVersion = "0.0.0"
+	if err := cache.Write(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, reused := scanDirWithCache(t, dir, cacheDir); reused != 0 {
+		t.Errorf("scan reused %d files cached by another version, want 0", reused)
+	}
+}
@@ -1,9 +1,15 @@
 package scanners
 
 import (
+	"bytes"
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"maps"
+	"os"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -11,11 +17,8 @@ import (
 	"github.com/google/osv-scalibr/extractor"
 	"github.com/google/osv-scalibr/extractor/filesystem"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/cpp/conanlock"
-	"github.com/google/osv-scalibr/extractor/filesystem/language/dart/pubspec"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/dotnet/depsjson"
-	"github.com/google/osv-scalibr/extractor/filesystem/language/dotnet/packageslockjson"
-	"github.com/google/osv-scalibr/extractor/filesystem/language/erlang/mixlock"
-	"github.com/google/osv-scalibr/extractor/filesystem/language/golang/gomod"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/dotnet/packagesconfig"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/haskell/cabal"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/haskell/stacklock"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/java/gradlelockfile"
@@ -24,22 +27,39 @@ import (
 	"github.com/google/osv-scalibr/extractor/filesystem/language/java/pomxmlnet"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/javascript/bunlock"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/javascript/packagelockjson"
-	"github.com/google/osv-scalibr/extractor/filesystem/language/javascript/pnpmlock"
-	"github.com/google/osv-scalibr/extractor/filesystem/language/javascript/yarnlock"
-	"github.com/google/osv-scalibr/extractor/filesystem/language/php/composerlock"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/python/pdmlock"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/python/pipfilelock"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/python/poetrylock"
-	"github.com/google/osv-scalibr/extractor/filesystem/language/python/requirements"
-	"github.com/google/osv-scalibr/extractor/filesystem/language/python/uvlock"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/r/renvlock"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/ruby/gemfilelock"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/rust/cargoauditable"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/rust/cargolock"
 	"github.com/google/osv-scalibr/extractor/filesystem/os/apk"
 	"github.com/google/osv-scalibr/extractor/filesystem/os/dpkg"
+	"github.com/google/osv-scalibr/plugin"
 	"github.com/google/osv-scanner/v2/internal/output"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/conda/condalock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/conda/environmentyml"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/dart/pubspec"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/dotnet/packageslockjson"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/erlang/mixlock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/golang/gomod"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/golang/gowork"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/java/effectivepom"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/java/gradleversioncatalog"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/java/mvndependencytree"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/javascript/bunlockb"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/javascript/pnpmlock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/javascript/yarnlock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/nix/flakelock"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/osv/osvscannerjson"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/php/composerlock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/python/requirements"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/python/uvlock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/rust/cargotoml"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/swift/packageresolved"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/terraform/terraformlock"
 )
 
 var lockfileExtractorMapping = map[string][]string{
@@ -48,31 +68,42 @@ var lockfileExtractorMapping = map[string][]string{
 	"yarn.lock":                   {yarnlock.Name},
 	"package-lock.json":           {packagelockjson.Name},
 	"pom.xml":                     {pomxmlnet.Name, pomxml.Name},
+	"effective-pom.xml":           {effectivepom.Name},
+	"dependency-tree.txt":         {mvndependencytree.Name},
 	"buildscript-gradle.lockfile": {gradlelockfile.Name},
 	"gradle.lockfile":             {gradlelockfile.Name},
 	"verification-metadata.xml":   {gradleverificationmetadataxml.Name},
+	"libs.versions.toml":          {gradleversioncatalog.Name},
 	"poetry.lock":                 {poetrylock.Name},
 	"Pipfile.lock":                {pipfilelock.Name},
 	"pdm.lock":                    {pdmlock.Name},
 	"requirements.txt":            {requirements.Name},
 	"uv.lock":                     {uvlock.Name},
 	"Cargo.lock":                  {cargolock.Name},
+	"Cargo.toml":                  {cargotoml.Name},
 	"composer.lock":               {composerlock.Name},
 	"mix.lock":                    {mixlock.Name},
 	"renv.lock":                   {renvlock.Name},
 	"deps.json":                   {depsjson.Name},
 	"packages.lock.json":          {packageslockjson.Name},
+	"packages.config":             {packagesconfig.Name},
 	"conan.lock":                  {conanlock.Name},
+	"environment.yml":             {environmentyml.Name},
+	"conda-lock.yml":              {condalock.Name},
 	"go.mod":                      {gomod.Name},
+	"go.work":                     {gowork.Name},
 	"bun.lock":                    {bunlock.Name},
+	"bun.lockb":                   {bunlockb.Name},
 	"Gemfile.lock":                {gemfilelock.Name},
+	"flake.lock":                  {flakelock.Name},
 	"cabal.project.freeze":        {cabal.Name},
 	"stack.yaml.lock":             {stacklock.Name},
-	// "Package.resolved":            {packageresolved.Name},
+	"Package.resolved":            {packageresolved.Name},
+	".terraform.lock.hcl":         {terraformlock.Name},
 }
 
 // ScanSingleFile is similar to ScanSingleFileWithMapping, just without supporting the <lockfileformat>:/path/to/lockfile prefix identifier
-func ScanSingleFile(path string, extractorsToUse []filesystem.Extractor) ([]*extractor.Inventory, error) {
+func ScanSingleFile(ctx context.Context, path string, extractorsToUse []filesystem.Extractor) ([]*extractor.Inventory, error) {
 	// TODO: Update the logging output to stop referring to SBOMs
 	path, err := filepath.Abs(path)
 	if err != nil {
@@ -80,7 +111,7 @@ func ScanSingleFile(path string, extractorsToUse []filesystem.Extractor) ([]*ext
 		return nil, err
 	}
 
-	invs, err := scalibrextract.ExtractWithExtractors(context.Background(), path, extractorsToUse)
+	invs, err := scalibrextract.ExtractWithExtractors(ctx, path, extractorsToUse)
 	if err != nil {
 		slog.Info(fmt.Sprintf("Failed to parse SBOM %q with error: %s", path, err))
 		return nil, err
@@ -99,45 +130,107 @@ func ScanSingleFile(path string, extractorsToUse []filesystem.Extractor) ([]*ext
 	return invs, nil
 }
 
+// stdinPath is the path used for lockfiles that are read from stdin
+const stdinPath = "<stdin>"
+
+// extractFunc extracts the lockfile being scanned with the given extractor
+type extractFunc func(ext filesystem.Extractor) ([]*extractor.Inventory, error)
+
 // ScanSingleFileWithMapping will load, identify, and parse the lockfile path passed in, and add the dependencies specified
 // within to `query`
-func ScanSingleFileWithMapping(scanPath string, extractorsToUse []filesystem.Extractor) ([]*extractor.Inventory, error) {
+//
+// A path of "-" reads the lockfile from stdin.
+func ScanSingleFileWithMapping(ctx context.Context, scanPath string, extractorsToUse []filesystem.Extractor) ([]*extractor.Inventory, error) {
 	var err error
-	var inventories []*extractor.Inventory
 
 	parseAs, path := parseLockfilePath(scanPath)
 
+	if path == "-" {
+		return ScanReaderWithMapping(ctx, os.Stdin, stdinPath, parseAs, extractorsToUse)
+	}
+
 	path, err = filepath.Abs(path)
 	if err != nil {
 		slog.Error(fmt.Sprintf("Failed to resolved path %q with error: %s", path, err))
 		return nil, err
 	}
 
+	extract := func(ext filesystem.Extractor) ([]*extractor.Inventory, error) {
+		return scalibrextract.ExtractWithExtractor(ctx, path, ext)
+	}
+	extractByName := func() ([]*extractor.Inventory, error) {
+		return scalibrextract.ExtractWithExtractors(ctx, path, extractorsToUse)
+	}
+
+	return scanWithMapping(path, parseAs, extractorsToUse, extract, extractByName)
+}
+
+// ScanReaderWithMapping parses the lockfile read from r, which does not exist on disk, as if it was
+// the file at path: parseAs selects the extractor to use, with the format of the lockfile being
+// guessed from its contents if it is empty.
+func ScanReaderWithMapping(ctx context.Context, r io.Reader, path string, parseAs string, extractorsToUse []filesystem.Extractor) ([]*extractor.Inventory, error) {
+	// the contents are buffered so that they can be read by each extractor when guessing the format
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", path, err)
+	}
+
+	extract := func(ext filesystem.Extractor) ([]*extractor.Inventory, error) {
+		return scalibrextract.ExtractReaderWithExtractor(ctx, path, bytes.NewReader(b), ext)
+	}
+
+	return scanWithMapping(path, parseAs, extractorsToUse, extract, nil)
+}
+
+// scanWithMapping parses the lockfile with the extractor of parseAs, or when parseAs is empty
+// by extractByName (which chooses the extractors based on the name of the lockfile) falling back
+// to guessing the format of the lockfile from its contents.
+//
+// extractByName may be nil if the lockfile does not have a name that can be used.
+func scanWithMapping(path string, parseAs string, extractorsToUse []filesystem.Extractor, extract extractFunc, extractByName func() ([]*extractor.Inventory, error)) ([]*extractor.Inventory, error) {
+	var err error
+	var inventories []*extractor.Inventory
+
 	// special case for the APK and DPKG parsers because they have a very generic name while
 	// living at a specific location, so they are not included in the map of parsers
 	// used by lockfile.Parse to avoid false-positives when scanning projects
 	switch parseAs {
 	case "apk-installed":
-		inventories, err = scalibrextract.ExtractWithExtractor(context.Background(), path, apk.New(apk.DefaultConfig()))
+		inventories, err = extract(apk.New(apk.DefaultConfig()))
 	case "dpkg-status":
-		inventories, err = scalibrextract.ExtractWithExtractor(context.Background(), path, dpkg.New(dpkg.DefaultConfig()))
+		inventories, err = extract(dpkg.New(dpkg.DefaultConfig()))
 	case "osv-scanner":
-		inventories, err = scalibrextract.ExtractWithExtractor(context.Background(), path, osvscannerjson.Extractor{})
+		inventories, err = extract(osvscannerjson.Extractor{})
+	// binaries built with cargo-auditable can have any name, so they also need to be explicitly requested
+	case "cargo-auditable":
+		inventories, err = extract(cargoauditable.New(cargoauditable.DefaultConfig()))
 	case "": // No specific parseAs specified
-		inventories, err = scalibrextract.ExtractWithExtractors(context.Background(), path, extractorsToUse)
-	default: // A specific parseAs without a special case is selected
-		// Find and extract with the extractor of parseAs
-		if names, ok := lockfileExtractorMapping[parseAs]; ok && len(names) > 0 {
-			i := slices.IndexFunc(extractorsToUse, func(ext filesystem.Extractor) bool {
-				return slices.Contains(names, ext.Name())
-			})
-			if i < 0 {
-				return nil, fmt.Errorf("could not determine extractor, requested %s", parseAs)
+		err = scalibrextract.ErrExtractorNotFound
+		if extractByName != nil {
+			inventories, err = extractByName()
+		}
+		// fallback to guessing the format of files without a known name from their contents
+		if errors.Is(err, scalibrextract.ErrExtractorNotFound) {
+			var guessed string
+			inventories, guessed, err = extractBySniffing(path, extractorsToUse, extract)
+			if err == nil {
+				parseAs = guessed
 			}
-			inventories, err = scalibrextract.ExtractWithExtractor(context.Background(), path, extractorsToUse[i])
-		} else {
+		}
+	default: // A specific parseAs without a special case is selected
+		// Find and extract with the extractor of parseAs, which can also
+		// be the name of an extractor such as one for a custom format
+		names, ok := lockfileExtractorMapping[parseAs]
+		if !ok {
+			names = []string{parseAs}
+		}
+		i := slices.IndexFunc(extractorsToUse, func(ext filesystem.Extractor) bool {
+			return slices.Contains(names, ext.Name())
+		})
+		if i < 0 {
 			return nil, fmt.Errorf("could not determine extractor, requested %s", parseAs)
 		}
+		inventories, err = extract(extractorsToUse[i])
 	}
 
 	if err != nil {
@@ -163,6 +256,108 @@ func ScanSingleFileWithMapping(scanPath string, extractorsToUse []filesystem.Ext
 	return inventories, nil
 }
 
+// looseExtractors are the extractors of formats that are loose enough for them to
+// successfully extract packages from many files which are not actually in their format
+// (such as pubspec.lock, as any JSON file is also valid YAML)
+var looseExtractors = map[string]struct{}{
+	pubspec.Name:        {},
+	yarnlock.Name:       {},
+	gradlelockfile.Name: {},
+	requirements.Name:   {},
+}
+
+// extractBySniffing extracts the file at path by trying every extractor, for files that
+// do not have a name that is known by any extractor, returning the name of the extractor
+// that the file was guessed to be for.
+//
+// Extractors are only considered to have succeeded if they found at least one package with a
+// name and version (or a commit), and when the file can be extracted by multiple extractors
+// the most specific one is used, which is the one that is not for a loose format and that
+// found the most packages.
+func extractBySniffing(path string, extractorsToUse []filesystem.Extractor, extract extractFunc) ([]*extractor.Inventory, string, error) {
+	type candidate struct {
+		ext   filesystem.Extractor
+		invs  []*extractor.Inventory
+		found int
+	}
+	var candidates []candidate
+
+	for _, ext := range extractorsToUse {
+		// extractors that use the network do more than parse the file,
+		// and should only be used for the files they are for
+		if ext.Requirements().Network == plugin.NetworkOnline {
+			continue
+		}
+
+		invs, err := extract(ext)
+		if err != nil {
+			continue
+		}
+
+		found := 0
+		for _, inv := range invs {
+			if (inv.Name != "" && inv.Version != "") || (inv.SourceCode != nil && inv.SourceCode.Commit != "") {
+				found++
+			}
+		}
+		if found > 0 {
+			candidates = append(candidates, candidate{ext, invs, found})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, "", scalibrextract.ErrExtractorNotFound
+	}
+
+	isLoose := func(c candidate) bool {
+		_, ok := looseExtractors[c.ext.Name()]
+		return ok
+	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		if isLoose(a) != isLoose(b) {
+			if isLoose(a) {
+				return 1
+			}
+
+			return -1
+		}
+
+		return cmp.Compare(b.found, a.found)
+	})
+
+	guess := candidates[0].ext.Name()
+	if len(candidates) > 1 {
+		others := make([]string, 0, len(candidates)-1)
+		for _, c := range candidates[1:] {
+			others = append(others, c.ext.Name())
+		}
+		slog.Warn(fmt.Sprintf(
+			"Guessed that %s is a %s file, though it could also be parsed as %s - pass it as \"%s:%s\" to explicitly choose the format",
+			path,
+			guess,
+			strings.Join(others, ", "),
+			parseAsFor(guess),
+			path,
+		))
+	} else {
+		slog.Warn(fmt.Sprintf("Guessed that %s is a %s file from its contents", path, guess))
+	}
+
+	return candidates[0].invs, guess, nil
+}
+
+// parseAsFor gets the format that can be used to explicitly
+// choose the extractor with the given name for a lockfile
+func parseAsFor(name string) string {
+	for _, parseAs := range slices.Sorted(maps.Keys(lockfileExtractorMapping)) {
+		if slices.Contains(lockfileExtractorMapping[parseAs], name) {
+			return parseAs
+		}
+	}
+
+	return "<format>"
+}
+
 func parseLockfilePath(scanArg string) (string, string) {
 	if !strings.Contains(scanArg, ":") {
 		scanArg = ":" + scanArg
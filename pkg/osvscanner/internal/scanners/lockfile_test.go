@@ -1,8 +1,16 @@
 package scanners
 
 import (
+	"context"
+	"errors"
+	"os"
 	"slices"
+	"strings"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract"
 )
 
 func TestLockfileScalibrMappingExists(t *testing.T) {
@@ -25,3 +33,361 @@ func TestLockfileScalibrMappingExists(t *testing.T) {
 		}
 	}
 }
+
+func TestScanSingleFileWithMapping_Sniffing(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		path          string
+		wantExtractor string
+		wantPackages  []string
+		wantErr       error
+	}{
+		{
+			name:          "renamed package-lock.json",
+			path:          "testdata/package-lock.prod.json",
+			wantExtractor: "javascript/packagelockjson",
+			wantPackages:  []string{"lodash@4.17.20"},
+		},
+		{
+			name:          "renamed pnpm-lock.yaml that other extractors can parse",
+			path:          "testdata/pnpm-lock.prod.yaml",
+			wantExtractor: "javascript/pnpmlock",
+			wantPackages:  []string{"acorn@8.11.3"},
+		},
+		{
+			name:    "not a lockfile",
+			path:    "testdata/unknown.txt",
+			wantErr: scalibrextract.ErrExtractorNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			invs, err := ScanSingleFileWithMapping(context.Background(), tt.path, lockfileExtractors)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ScanSingleFileWithMapping(%q) error = %v, want %v", tt.path, err, tt.wantErr)
+			}
+
+			got := make([]string, 0, len(invs))
+			for _, inv := range invs {
+				if inv.Extractor.Name() != tt.wantExtractor {
+					t.Errorf("ScanSingleFileWithMapping(%q) extracted %s with %s, want %s", tt.path, inv.Name, inv.Extractor.Name(), tt.wantExtractor)
+				}
+				got = append(got, inv.Name+"@"+inv.Version)
+			}
+
+			if !slices.Equal(got, tt.wantPackages) {
+				t.Errorf("ScanSingleFileWithMapping(%q) = %v, want %v", tt.path, got, tt.wantPackages)
+			}
+		})
+	}
+}
+
+func TestScanSingleFileWithMapping_OSPackages(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		path          string
+		wantExtractor string
+		// wantPackages are the names that the packages are matched against, along with their ecosystems
+		wantPackages []string
+	}{
+		{
+			name:          "dpkg status uses the source package name",
+			path:          "dpkg-status:testdata/status",
+			wantExtractor: "os/dpkg",
+			wantPackages:  []string{"Debian/openssl@3.0.11-1~deb12u2", "Debian/tzdata@2024a-0+deb12u1"},
+		},
+		{
+			name:          "apk installed uses the origin package name",
+			path:          "apk-installed:testdata/installed",
+			wantExtractor: "os/apk",
+			wantPackages:  []string{"Alpine/openssl@3.1.4-r5", "Alpine/musl@1.2.4_git20230717-r4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			invs, err := ScanSingleFileWithMapping(context.Background(), tt.path, lockfileExtractors)
+			if err != nil {
+				t.Fatalf("ScanSingleFileWithMapping(%q) error = %v", tt.path, err)
+			}
+
+			got := make([]string, 0, len(invs))
+			for _, inv := range invs {
+				if inv.Extractor.Name() != tt.wantExtractor {
+					t.Errorf("ScanSingleFileWithMapping(%q) extracted %s with %s, want %s", tt.path, inv.Name, inv.Extractor.Name(), tt.wantExtractor)
+				}
+				pkg := imodels.FromInventory(inv)
+				got = append(got, string(pkg.Ecosystem().Ecosystem)+"/"+pkg.Name()+"@"+pkg.Version())
+			}
+
+			if diff := cmp.Diff(tt.wantPackages, got); diff != "" {
+				t.Errorf("ScanSingleFileWithMapping(%q) mismatch (-want +got):\n%s", tt.path, diff)
+			}
+		})
+	}
+}
+
+func TestScanSingleFile_CycloneDXEcosystems(t *testing.T) {
+	t.Parallel()
+
+	invs, err := ScanSingleFile(context.Background(), "testdata/ecosystems.cdx.json", BuildSBOMExtractors())
+	if err != nil {
+		t.Fatalf("ScanSingleFile() error = %v", err)
+	}
+
+	got := make([]string, 0, len(invs))
+	for _, inv := range invs {
+		pkg := imodels.FromInventory(inv)
+		got = append(got, pkg.Ecosystem().String()+"/"+pkg.Name()+"@"+pkg.Version())
+	}
+	slices.Sort(got)
+
+	want := []string{
+		"Go/golang.org/x/mod@v0.14.0",
+		"Maven/org.apache.logging.log4j:log4j-core@2.14.1",
+		"NuGet/Newtonsoft.Json@12.0.1",
+		"OSS-Fuzz/openssl@3.0.7",
+		"Packagist/symfony/yaml@7.0.0",
+		"PyPI/django@4.1.2",
+		"RubyGems/rails@7.0.4",
+		"crates.io/memoffset@0.6.1",
+		"npm/@babel/core@7.20.0",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ScanSingleFile() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestScanReaderWithMapping(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		parseAs       string
+		wantExtractor string
+		wantErr       bool
+	}{
+		{
+			name:          "explicit format",
+			parseAs:       "package-lock.json",
+			wantExtractor: "javascript/packagelockjson",
+		},
+		{
+			name:          "guessed format",
+			parseAs:       "",
+			wantExtractor: "javascript/packagelockjson",
+		},
+		{
+			name:    "wrong format",
+			parseAs: "composer.lock",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := os.Open("testdata/package-lock.prod.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			invs, err := ScanReaderWithMapping(context.Background(), f, "<stdin>", tt.parseAs, lockfileExtractors)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ScanReaderWithMapping(%q) did not error", tt.parseAs)
+				}
+				if !strings.Contains(err.Error(), "<stdin>") {
+					t.Errorf("ScanReaderWithMapping(%q) error = %v, want it to mention <stdin>", tt.parseAs, err)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("ScanReaderWithMapping(%q) error = %v", tt.parseAs, err)
+			}
+
+			if len(invs) != 1 {
+				t.Fatalf("ScanReaderWithMapping(%q) found %d packages, want 1", tt.parseAs, len(invs))
+			}
+			if got := invs[0].Extractor.Name(); got != tt.wantExtractor {
+				t.Errorf("ScanReaderWithMapping(%q) extracted with %s, want %s", tt.parseAs, got, tt.wantExtractor)
+			}
+			if got := invs[0].Locations; !slices.Equal(got, []string{"<stdin>"}) {
+				t.Errorf("ScanReaderWithMapping(%q) locations = %v, want [<stdin>]", tt.parseAs, got)
+			}
+		})
+	}
+}
+
+func TestScanSingleFileWithMapping_PipfileLockGroups(t *testing.T) {
+	t.Parallel()
+
+	invs, err := ScanSingleFileWithMapping(context.Background(), "testdata/Pipfile.lock", lockfileExtractors)
+	if err != nil {
+		t.Fatalf("ScanSingleFileWithMapping() error = %v", err)
+	}
+
+	// packages in both the default and develop sections are not dev dependencies
+	want := map[string][]string{
+		"pytest@8.0.2":    {"dev"},
+		"requests@2.31.0": {},
+		"urllib3@2.0.7":   {},
+	}
+
+	got := make(map[string][]string, len(invs))
+	for _, inv := range invs {
+		got[inv.Name+"@"+inv.Version] = (&imodels.PackageInfo{Inventory: inv}).DepGroups()
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ScanSingleFileWithMapping() groups diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestScanSingleFileWithMapping_CargoAuditable(t *testing.T) {
+	t.Parallel()
+
+	invs, err := ScanSingleFileWithMapping(context.Background(), "cargo-auditable:testdata/uses_serde_json", lockfileExtractors)
+	if err != nil {
+		t.Fatalf("ScanSingleFileWithMapping() error = %v", err)
+	}
+
+	got := make([]string, 0, len(invs))
+	for _, inv := range invs {
+		pkg := imodels.FromInventory(inv)
+		if pkg.SourceType() != imodels.SourceTypeArtifact {
+			t.Errorf("ScanSingleFileWithMapping() package %s has source type %v, want %v", inv.Name, pkg.SourceType(), imodels.SourceTypeArtifact)
+		}
+		got = append(got, string(pkg.Ecosystem().Ecosystem)+"/"+pkg.Name()+"@"+pkg.Version())
+	}
+	slices.Sort(got)
+
+	want := []string{
+		"crates.io/itoa@1.0.14",
+		"crates.io/memchr@2.7.4",
+		"crates.io/proc-macro2@1.0.92",
+		"crates.io/quote@1.0.38",
+		"crates.io/ryu@1.0.18",
+		"crates.io/serde@1.0.217",
+		"crates.io/serde_derive@1.0.217",
+		"crates.io/serde_json@1.0.135",
+		"crates.io/syn@2.0.95",
+		"crates.io/unicode-ident@1.0.14",
+		"crates.io/uses_json@0.1.0",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ScanSingleFileWithMapping() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestScanSingleFileWithMapping_NuGet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{
+			path: "testdata/nuget/packages.config",
+			want: []string{
+				"NuGet/Newtonsoft.Json@12.0.1",
+				"NuGet/log4net@2.0.8",
+			},
+		},
+		{
+			path: "testdata/nuget/packages.lock.json",
+			want: []string{
+				"NuGet/Newtonsoft.Json@13.0.1",
+				"NuGet/System.Text.Encodings.Web@4.7.2",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			invs, err := ScanSingleFileWithMapping(context.Background(), tt.path, lockfileExtractors)
+			if err != nil {
+				t.Fatalf("ScanSingleFileWithMapping() error = %v", err)
+			}
+
+			got := make([]string, 0, len(invs))
+			for _, inv := range invs {
+				pkg := imodels.FromInventory(inv)
+				got = append(got, string(pkg.Ecosystem().Ecosystem)+"/"+pkg.Name()+"@"+pkg.Version())
+			}
+			slices.Sort(got)
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ScanSingleFileWithMapping() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestScanSingleFileWithMapping_Haskell(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{
+			// flags, and settings such as the index state, are not version pins so are skipped
+			path: "testdata/haskell/cabal.project.freeze",
+			want: []string{
+				"Hackage/Cabal@3.10.1.0",
+				"Hackage/OneTuple@0.4.1.1",
+				"Hackage/QuickCheck@2.14.3",
+				"Hackage/aeson@2.1.2.1",
+				"Hackage/base@4.18.1.0",
+				"Hackage/bytestring@0.11.5.2",
+				"Hackage/hashable@1.4.3.0",
+				"Hackage/text@2.0.2",
+				"Hackage/warp@3.3.25",
+			},
+		},
+		{
+			// packages from git, and the packages of snapshots (which are only referred to by
+			// the url of the snapshot), are not pinned to a version from Hackage so are skipped
+			path: "testdata/haskell/stack.yaml.lock",
+			want: []string{
+				"Hackage/aeson@2.1.2.1",
+				"Hackage/http-client@0.7.13.1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			invs, err := ScanSingleFileWithMapping(context.Background(), tt.path, lockfileExtractors)
+			if err != nil {
+				t.Fatalf("ScanSingleFileWithMapping() error = %v", err)
+			}
+
+			got := make([]string, 0, len(invs))
+			for _, inv := range invs {
+				pkg := imodels.FromInventory(inv)
+				got = append(got, string(pkg.Ecosystem().Ecosystem)+"/"+pkg.Name()+"@"+pkg.Version())
+			}
+			slices.Sort(got)
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ScanSingleFileWithMapping() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package scanners
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/python/sitepackages"
+)
+
+// sitePackagesPatterns are where the site-packages directories of an environment are,
+// relative to its prefix, for virtual environments and system installs on each OS
+var sitePackagesPatterns = []string{
+	"lib/python*/site-packages",
+	"lib64/python*/site-packages",
+	"lib/python*/dist-packages",
+	"Lib/site-packages",
+}
+
+// SitePackagesDirs returns the site-packages directories of the Python environment at env,
+// which is either the environment itself (e.g. a virtualenv) or one of its site-packages
+// directories.
+func SitePackagesDirs(env string) ([]string, error) {
+	if sitepackages.IsSitePackages(env) {
+		return []string{env}, nil
+	}
+
+	var dirs []string
+	for _, pattern := range sitePackagesPatterns {
+		matches, err := filepath.Glob(filepath.Join(env, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, match := range matches {
+			// virtualenvs usually have lib64 as a symlink to lib
+			if resolved, err := filepath.EvalSymlinks(match); err == nil {
+				match = resolved
+			}
+			if !slices.Contains(dirs, match) {
+				dirs = append(dirs, match)
+			}
+		}
+	}
+
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("could not find a site-packages directory in the Python environment at %s", env)
+	}
+
+	return dirs, nil
+}
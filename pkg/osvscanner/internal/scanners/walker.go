@@ -7,24 +7,61 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
 	"github.com/google/osv-scalibr/extractor"
 	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/simplefileapi"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
 	"github.com/google/osv-scanner/v2/internal/customgitignore"
 	"github.com/google/osv-scanner/v2/internal/output"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract"
+	"golang.org/x/sync/errgroup"
 )
 
+// walkedPath is a file or directory found while walking, which is to be extracted
+type walkedPath struct {
+	path  string
+	isDir bool
+}
+
+// extractResult is the result of extracting a walked path
+type extractResult struct {
+	inventories []*extractor.Inventory
+	err         error
+}
+
+// FileError is an error that occurred while extracting a file, which does not stop other files from being scanned
+type FileError struct {
+	Path string
+	Err  error
+}
+
 // ScanDir walks through the given directory to try to find any relevant files
 // These include:
 //   - Any lockfiles with scanLockfile
 //   - Any SBOM files with scanSBOMFile
 //   - Any git repositories with scanGit
 //
+// Subdirectories with a name matching any of the skipDirs patterns are not walked.
+// Symlinks are not followed, so the walk cannot get stuck in a symlink loop.
+//
+// The files that are found are extracted concurrently by up to the given number of jobs
+// (or GOMAXPROCS if it is not positive), with the results being in the order they were walked.
+// Files that cannot be extracted are returned as FileErrors, rather than stopping the scan.
+//
+// The walk and any extractions that have not started yet are stopped if ctx is cancelled,
+// in which case the error of the context is returned.
+//
+// If cache is not nil, the packages it has cached for files that have not changed are
+// reused rather than extracting the files again, and it caches those of the other files.
+//
 // TODO(V2 Models): pomExtractor is temporary until V2 Models
-func ScanDir(dir string, recursive bool, useGitIgnore bool, extractorsToUse []filesystem.Extractor) ([]*extractor.Inventory, error) {
+func ScanDir(ctx context.Context, dir string, recursive bool, useGitIgnore bool, skipDirs []string, jobs int, extractorsToUse []filesystem.Extractor, cache *InventoryCache) ([]*extractor.Inventory, []FileError, error) {
 	var ignoreMatcher *gitIgnoreMatcher
 	if useGitIgnore {
 		var err error
@@ -36,10 +73,15 @@ func ScanDir(dir string, recursive bool, useGitIgnore bool, extractorsToUse []fi
 	}
 
 	root := true
+	var walked []walkedPath
 
-	var scannedInventories []*extractor.Inventory
+	defer cmdlogger.ClearProgress()
 
 	err := filepath.WalkDir(dir, func(path string, info os.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
 		if err != nil {
 			slog.Info(fmt.Sprintf("Failed to walk %s: %v", path, err))
 			return err
@@ -58,7 +100,9 @@ func ScanDir(dir string, recursive bool, useGitIgnore bool, extractorsToUse []fi
 				// Don't skip if we can't parse now - potentially noisy for directories with lots of items
 			} else if match {
 				if root { // Don't silently skip if the argument file was ignored.
-					slog.Error(path + " was not scanned because it is excluded by a .gitignore file. Use --no-ignore to scan it.")
+					slog.Error(path + " was not scanned because it is excluded by a .gitignore or .osvscannerignore file. Use --no-ignore to scan it.")
+				} else {
+					slog.Debug("Skipping " + path + " as it is excluded by a .gitignore or .osvscannerignore file")
 				}
 				if info.IsDir() {
 					return filepath.SkipDir
@@ -68,24 +112,12 @@ func ScanDir(dir string, recursive bool, useGitIgnore bool, extractorsToUse []fi
 			}
 		}
 
-		// -------- Perform scanning --------
-		inventories, err := scalibrextract.ExtractWithExtractors(context.Background(), path, extractorsToUse)
-		if err != nil && !errors.Is(err, scalibrextract.ErrExtractorNotFound) {
-			slog.Error(fmt.Sprintf("Error during extraction: %s", err))
-		}
-
-		pkgCount := len(inventories)
-		if pkgCount > 0 {
-			// TODO(v2): Display the name of the extractor used here
-			slog.Info(fmt.Sprintf(
-				"Scanned %s file and found %d %s",
-				path,
-				pkgCount,
-				output.Form(pkgCount, "package", "packages"),
-			))
+		if !root && info.IsDir() && matchesSkipDir(info.Name(), skipDirs) {
+			slog.Info("Skipping dir " + path)
+			return filepath.SkipDir
 		}
 
-		scannedInventories = append(scannedInventories, inventories...)
+		walked = append(walked, walkedPath{path: path, isDir: info.IsDir()})
 
 		// Optimisation to skip git repository .git dirs
 		if info.IsDir() && info.Name() == ".git" {
@@ -100,8 +132,143 @@ func ScanDir(dir string, recursive bool, useGitIgnore bool, extractorsToUse []fi
 
 		return nil
 	})
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, nil, ctxErr
+	}
+
+	// -------- Perform scanning --------
+	results := extractWalkedPaths(ctx, dir, walked, jobs, extractorsToUse, cache)
+	// extractions that were interrupted by the cancellation are not reported as errors of their files
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, nil, ctxErr
+	}
+
+	filesFound := 0
+	filesScanned := 0
+
+	var scannedInventories []*extractor.Inventory
+	var fileErrs []FileError
+
+	for i, wp := range walked {
+		inventories, err := results[i].inventories, results[i].err
+		if err != nil && !errors.Is(err, scalibrextract.ErrExtractorNotFound) {
+			fileErrs = append(fileErrs, FileError{Path: wp.path, Err: err})
+		}
+
+		if !wp.isDir {
+			filesFound++
+			if err == nil {
+				filesScanned++
+			}
+		}
+
+		pkgCount := len(inventories)
+		// TODO(v2): Display the name of the extractor used here
+		message := fmt.Sprintf(
+			"Scanned %s file and found %d %s",
+			wp.path,
+			pkgCount,
+			output.Form(pkgCount, "package", "packages"),
+		)
+		if pkgCount > 0 {
+			slog.Info(message)
+		} else if err == nil {
+			slog.Debug(message)
+		}
+
+		scannedInventories = append(scannedInventories, inventories...)
+	}
+
+	if recursive {
+		slog.Info(fmt.Sprintf(
+			"Scanned %d of the %d %s found in %s",
+			filesScanned,
+			filesFound,
+			output.Form(filesFound, "file", "files"),
+			dir,
+		))
+	}
+
+	return scannedInventories, fileErrs, err
+}
+
+// extractWalkedPaths extracts each of the walked paths using a pool of workers,
+// returning the results in the same order as the paths
+func extractWalkedPaths(ctx context.Context, dir string, walked []walkedPath, jobs int, extractorsToUse []filesystem.Extractor, cache *InventoryCache) []extractResult {
+	if jobs <= 0 {
+		jobs = runtime.GOMAXPROCS(0)
+	}
+
+	results := make([]extractResult, len(walked))
+
+	var mu sync.Mutex
+	// extractors that need the network share their resolution clients between
+	// extractions, which are not safe for concurrent use, so they run one at a time
+	var networkMu sync.Mutex
+	packagesFound := 0
+	pathsExtracted := 0
+
+	var g errgroup.Group
+	g.SetLimit(jobs)
+
+	for i, wp := range walked {
+		g.Go(func() error {
+			// paths that have not been extracted yet are skipped once the scan is cancelled
+			if err := ctx.Err(); err != nil {
+				results[i] = extractResult{err: err}
+				return nil
+			}
+
+			if needsNetworkExtractor(wp.path, extractorsToUse) {
+				networkMu.Lock()
+				defer networkMu.Unlock()
+			}
+
+			var inventories []*extractor.Inventory
+			var err error
+			if cache != nil && !wp.isDir {
+				inventories, err = cache.extract(ctx, wp.path, extractorsToUse)
+			} else {
+				inventories, err = scalibrextract.ExtractWithExtractors(ctx, wp.path, extractorsToUse)
+			}
+			results[i] = extractResult{inventories: inventories, err: err}
+
+			mu.Lock()
+			defer mu.Unlock()
 
-	return scannedInventories, err
+			packagesFound += len(inventories)
+			pathsExtracted++
+
+			cmdlogger.Progress(fmt.Sprintf(
+				"Scanning %s: found %d %s in %d of %d %s so far",
+				dir,
+				packagesFound,
+				output.Form(packagesFound, "package", "packages"),
+				pathsExtracted,
+				len(walked),
+				output.Form(len(walked), "path", "paths"),
+			))
+
+			// errors are reported with the results of each path
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+
+	return results
+}
+
+// matchesSkipDir returns true if the name of a directory matches any of the
+// given patterns, which support the same wildcards as filepath.Match
+func matchesSkipDir(name string, skipDirs []string) bool {
+	for _, pattern := range skipDirs {
+		if matched, err := filepath.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
 }
 
 type gitIgnoreMatcher struct {
@@ -135,3 +302,19 @@ func (m *gitIgnoreMatcher) match(absPath string, isDir bool) (bool, error) {
 
 	return m.matcher.Match(pathInGitSep, isDir), nil
 }
+
+// needsNetworkExtractor returns true if any of the extractors that need the network would extract the path
+func needsNetworkExtractor(path string, extractorsToUse []filesystem.Extractor) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	for _, ext := range extractorsToUse {
+		if reqs := ext.Requirements(); reqs != nil && reqs.Network == plugin.NetworkOnline && ext.FileRequired(simplefileapi.New(path, info)) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,296 @@
+package scanners
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/clients/datasource"
+	"github.com/google/osv-scalibr/clients/resolution"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/javascript/packagelockjson"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract"
+	"github.com/google/osv-scanner/v2/internal/testutility"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// setupWalkDir creates a directory with the given lockfiles, using the contents
+// of the package-lock.json fixture for each of them
+func setupWalkDir(t *testing.T, lockfiles ...string) string {
+	t.Helper()
+
+	content, err := os.ReadFile("testdata/package-lock.prod.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	for _, lockfile := range lockfiles {
+		path := filepath.Join(dir, filepath.FromSlash(lockfile))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, content, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func scanDirLocations(t *testing.T, dir string, useGitIgnore bool, skipDirs []string) []string {
+	t.Helper()
+
+	invs, _, err := ScanDir(context.Background(), dir, true, useGitIgnore, skipDirs, 0, BuildWalkerExtractors(false, nil, nil, nil), nil)
+	if err != nil {
+		t.Fatalf("ScanDir() error = %v", err)
+	}
+
+	var locations []string
+	for _, inv := range invs {
+		rel, err := filepath.Rel(dir, inv.Locations[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Contains(locations, filepath.ToSlash(rel)) {
+			locations = append(locations, filepath.ToSlash(rel))
+		}
+	}
+	slices.Sort(locations)
+
+	return locations
+}
+
+func TestScanDir_SkipDirs(t *testing.T) {
+	t.Parallel()
+
+	dir := setupWalkDir(t,
+		"package-lock.json",
+		"app/package-lock.json",
+		"third_party/dep/package-lock.json",
+		"app/third_party/dep/package-lock.json",
+		"vendor-old/package-lock.json",
+	)
+
+	tests := []struct {
+		name     string
+		skipDirs []string
+		want     []string
+	}{
+		{
+			name:     "nothing skipped",
+			skipDirs: nil,
+			want: []string{
+				"app/package-lock.json",
+				"app/third_party/dep/package-lock.json",
+				"package-lock.json",
+				"third_party/dep/package-lock.json",
+				"vendor-old/package-lock.json",
+			},
+		},
+		{
+			name:     "directories are skipped at any depth",
+			skipDirs: []string{"third_party"},
+			want: []string{
+				"app/package-lock.json",
+				"package-lock.json",
+				"vendor-old/package-lock.json",
+			},
+		},
+		{
+			name:     "patterns",
+			skipDirs: []string{"third_party", "vendor*"},
+			want: []string{
+				"app/package-lock.json",
+				"package-lock.json",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := scanDirLocations(t, dir, false, tt.skipDirs)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ScanDir() locations mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestScanDir_SymlinkLoop(t *testing.T) {
+	t.Parallel()
+
+	dir := setupWalkDir(t, "package-lock.json", "nested/package-lock.json")
+	if err := os.Symlink(dir, filepath.Join(dir, "nested", "loop")); err != nil {
+		t.Skipf("could not create symlink: %v", err)
+	}
+
+	got := scanDirLocations(t, dir, false, nil)
+	want := []string{"nested/package-lock.json", "package-lock.json"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ScanDir() locations mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestScanDir_OsvScannerIgnore(t *testing.T) {
+	t.Parallel()
+
+	dir := setupWalkDir(t, "package-lock.json", "build/package-lock.json", "build/keep/package-lock.json")
+	if err := os.WriteFile(filepath.Join(dir, ".osvscannerignore"), []byte("build/*\n!build/keep\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got := scanDirLocations(t, dir, true, nil)
+	want := []string{"build/keep/package-lock.json", "package-lock.json"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ScanDir() locations mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestScanDir_Jobs(t *testing.T) {
+	t.Parallel()
+
+	var lockfiles []string
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		lockfiles = append(lockfiles, name+"/package-lock.json", name+"/nested/package-lock.json")
+	}
+	dir := setupWalkDir(t, lockfiles...)
+
+	locations := func(jobs int) []string {
+		invs, _, err := ScanDir(context.Background(), dir, true, false, nil, jobs, BuildWalkerExtractors(false, nil, nil, nil), nil)
+		if err != nil {
+			t.Fatalf("ScanDir() error = %v", err)
+		}
+
+		// the locations are not sorted, as they should be in the order that they were walked
+		locations := make([]string, 0, len(invs))
+		for _, inv := range invs {
+			locations = append(locations, inv.Name+"@"+inv.Locations[0])
+		}
+
+		return locations
+	}
+
+	want := locations(1)
+	for _, jobs := range []int{0, 4, 32} {
+		if diff := cmp.Diff(want, locations(jobs)); diff != "" {
+			t.Errorf("ScanDir() with %d jobs mismatch (-want +got):\n%s", jobs, diff)
+		}
+	}
+}
+
+// panickingExtractor is an npm extractor that panics when extracting files named "panic.json"
+type panickingExtractor struct {
+	packagelockjson.Extractor
+}
+
+func (e panickingExtractor) FileRequired(api filesystem.FileAPI) bool {
+	return filepath.Base(api.Path()) == "panic.json"
+}
+
+func (e panickingExtractor) Extract(_ context.Context, _ *filesystem.ScanInput) ([]*extractor.Inventory, error) {
+	panic("malformed file")
+}
+
+func TestScanDir_ExtractorPanics(t *testing.T) {
+	t.Parallel()
+
+	dir := setupWalkDir(t, "package-lock.json", "bad/panic.json", "nested/package-lock.json")
+
+	invs, fileErrs, err := ScanDir(context.Background(), dir, true, false, nil, 2, []filesystem.Extractor{panickingExtractor{}, packagelockjson.NewDefault()}, nil)
+	if err != nil {
+		t.Fatalf("ScanDir() error = %v", err)
+	}
+
+	if len(fileErrs) != 1 || fileErrs[0].Path != filepath.Join(dir, "bad", "panic.json") || !errors.Is(fileErrs[0].Err, scalibrextract.ErrExtractorPanicked) {
+		t.Errorf("ScanDir() file errors = %v, want the panic extracting bad/panic.json", fileErrs)
+	}
+
+	var got []string
+	for _, inv := range invs {
+		rel, err := filepath.Rel(dir, inv.Locations[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Contains(got, filepath.ToSlash(rel)) {
+			got = append(got, filepath.ToSlash(rel))
+		}
+	}
+
+	want := []string{"nested/package-lock.json", "package-lock.json"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ScanDir() locations mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestScanDir_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	dir := setupWalkDir(t, "package-lock.json", "nested/package-lock.json")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := ScanDir(ctx, dir, true, false, nil, 2, BuildWalkerExtractors(false, nil, nil, nil), nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ScanDir() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestScanDir_PomXMLsWithDifferentRepositories(t *testing.T) {
+	t.Parallel()
+
+	srv := testutility.NewMockHTTPServer(t)
+
+	dir := t.TempDir()
+	for _, name := range []string{"a", "b", "c", "d"} {
+		pom := fmt.Sprintf(`<project>
+  <groupId>com.example</groupId>
+  <artifactId>%[1]s</artifactId>
+  <version>1.0.0</version>
+  <repositories>
+    <repository>
+      <id>repo-%[1]s</id>
+      <url>%[2]s/repo-%[1]s/</url>
+    </repository>
+  </repositories>
+</project>
+`, name, srv.URL)
+
+		if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, "pom.xml"), []byte(pom), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mavenAPIClient, err := datasource.NewMavenRegistryAPIClient(datasource.MavenRegistry{URL: srv.URL, ReleasesEnabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mavenClient, err := resolution.NewMavenRegistryClient(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	extractors := BuildWalkerExtractors(false, nil, map[osvschema.Ecosystem]resolve.Client{
+		osvschema.EcosystemMaven: mavenClient,
+	}, mavenAPIClient)
+
+	_, fileErrs, err := ScanDir(context.Background(), dir, true, false, nil, 4, extractors, nil)
+	if err != nil {
+		t.Fatalf("ScanDir() error = %v", err)
+	}
+	if len(fileErrs) != 0 {
+		t.Errorf("ScanDir() file errors = %v, want none", fileErrs)
+	}
+}
@@ -0,0 +1,66 @@
+package osvscanner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/google/osv-scanner/v2/internal/clients/clientinterfaces"
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+// applyKEV flags the findings that are known to be exploited,
+// removing those that are not if only known exploited findings are wanted
+func applyKEV(ctx context.Context, results *models.VulnerabilityResults, matcher clientinterfaces.KEVMatcher, actions ScannerActions) error {
+	if err := enrichKEV(ctx, results, matcher); err != nil {
+		return err
+	}
+
+	if actions.KEVOnly {
+		filtered := filterNonKEVVulns(results, actions.ShowAllPackages)
+		if filtered > 0 {
+			slog.Info(fmt.Sprintf(
+				"Filtered %d %s that are not known to be exploited from output",
+				filtered,
+				output.Form(filtered, "vulnerability", "vulnerabilities"),
+			))
+		}
+	}
+
+	return nil
+}
+
+// enrichKEV flags each group of vulnerabilities in the results that has a CVE alias in CISA's
+// Known Exploited Vulnerabilities catalog, using the first such CVE in the order of its aliases
+func enrichKEV(ctx context.Context, results *models.VulnerabilityResults, matcher clientinterfaces.KEVMatcher) error {
+	cves := groupCVEs(results)
+	if len(cves) == 0 {
+		return nil
+	}
+
+	entries, err := matcher.MatchKEV(ctx, cves)
+	if err != nil {
+		return err
+	}
+
+	for i, pkgSrc := range results.Results {
+		for j, pkg := range pkgSrc.Packages {
+			for k, group := range pkg.Groups {
+				idx := slices.IndexFunc(group.Aliases, func(alias string) bool {
+					_, ok := entries[alias]
+					return ok
+				})
+				if idx < 0 {
+					continue
+				}
+
+				entry := entries[group.Aliases[idx]]
+				results.Results[i].Packages[j].Groups[k].ExperimentalKEV = &entry
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package osvscanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+// staticKEVMatcher returns the entries it has for the CVEs
+type staticKEVMatcher map[string]models.KEVEntry
+
+func (m staticKEVMatcher) MatchKEV(_ context.Context, cves []string) (map[string]models.KEVEntry, error) {
+	results := make(map[string]models.KEVEntry)
+	for _, cve := range cves {
+		if entry, ok := m[cve]; ok {
+			results[cve] = entry
+		}
+	}
+
+	return results, nil
+}
+
+func Test_enrichKEV(t *testing.T) {
+	t.Parallel()
+
+	log4shell := models.KEVEntry{CVE: "CVE-2021-44228", DateAdded: "2021-12-10", KnownRansomwareCampaignUse: true}
+
+	results := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "path/to/pom.xml", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{
+						Package: models.PackageInfo{Name: "org.apache.logging.log4j:log4j-core", Version: "2.14.1", Ecosystem: "Maven"},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"GHSA-jfh8-c2jp-5v3q"}, Aliases: []string{"CVE-2021-44228", "GHSA-jfh8-c2jp-5v3q"}},
+							{IDs: []string{"GHSA-7rjr-3q55-vv33"}, Aliases: []string{"CVE-2021-45046", "GHSA-7rjr-3q55-vv33"}},
+							{IDs: []string{"OSV-1"}, Aliases: []string{"OSV-1"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	matcher := staticKEVMatcher{log4shell.CVE: log4shell}
+	if err := enrichKEV(context.Background(), &results, matcher); err != nil {
+		t.Fatalf("enrichKEV() error = %v", err)
+	}
+
+	var got []*models.KEVEntry
+	for _, group := range results.Results[0].Packages[0].Groups {
+		got = append(got, group.ExperimentalKEV)
+	}
+
+	want := []*models.KEVEntry{&log4shell, nil, nil}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("enrichKEV() mismatch (-want +got):\n%s", diff)
+	}
+}
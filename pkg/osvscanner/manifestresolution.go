@@ -0,0 +1,170 @@
+package osvscanner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"deps.dev/util/semver"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scanner/v2/internal/clients/clientinterfaces"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/python/requirements"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/rust/cargotoml"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The ways that the version requirements of manifests can be resolved to the version that is matched
+const (
+	// ManifestResolutionDeclared matches the lowest version allowed by each requirement, as it is
+	// declared (e.g. 1.2.0 for ^1.2), which does not need the published versions of the package
+	ManifestResolutionDeclared = "declared"
+	// ManifestResolutionLowest matches the lowest published version that satisfies each requirement
+	ManifestResolutionLowest = "lowest"
+	// ManifestResolutionHighest matches the highest published version that satisfies each requirement,
+	// which is the version that would be installed by a fresh install
+	ManifestResolutionHighest = "highest"
+)
+
+// ManifestResolutions are the valid values of ExperimentalScannerActions.ManifestResolution
+var ManifestResolutions = []string{ManifestResolutionDeclared, ManifestResolutionLowest, ManifestResolutionHighest}
+
+// maxConcurrentVersionRequests is the number of packages whose versions are listed at once
+const maxConcurrentVersionRequests = 100
+
+// resolutionSystems are the version systems of the ecosystems whose requirements can be resolved
+var resolutionSystems = map[osvschema.Ecosystem]semver.System{
+	osvschema.EcosystemCratesIO: semver.Cargo,
+	osvschema.EcosystemPyPI:     semver.PyPI,
+}
+
+// versionRequirement returns the version requirement that the package was declared with, or an
+// empty string if it was declared with an exact version (or is not from a manifest)
+func versionRequirement(inv *extractor.Inventory) string {
+	switch m := inv.Metadata.(type) {
+	case cargotoml.Metadata:
+		return m.Requirement
+	case *requirements.Metadata:
+		if m.VersionComparator == "==" || m.VersionComparator == "===" {
+			return ""
+		}
+
+		return m.VersionComparator + inv.Version
+	}
+
+	return ""
+}
+
+// resolveManifestVersions replaces the versions of packages that were declared with a version requirement
+// with the lowest or highest published version that satisfies it, as chosen by resolution.
+//
+// Packages whose versions cannot be listed (e.g. because they are private, or lister is nil as the scan
+// is offline) keep the lowest version allowed by their requirement, as do those without a satisfying version.
+func resolveManifestVersions(ctx context.Context, lister clientinterfaces.VersionLister, packages []imodels.PackageScanResult, resolution string) {
+	if resolution == "" || resolution == ManifestResolutionDeclared {
+		return
+	}
+
+	type packageKey struct {
+		ecosystem osvschema.Ecosystem
+		name      string
+	}
+
+	toResolve := make(map[packageKey][]int)
+	for i, psr := range packages {
+		eco := psr.PackageInfo.Ecosystem().Ecosystem
+		if _, ok := resolutionSystems[eco]; !ok || versionRequirement(psr.PackageInfo.Inventory) == "" {
+			continue
+		}
+
+		key := packageKey{eco, psr.PackageInfo.Name()}
+		toResolve[key] = append(toResolve[key], i)
+	}
+
+	if len(toResolve) == 0 {
+		return
+	}
+
+	if lister == nil {
+		slog.Info(fmt.Sprintf("The versions of packages cannot be resolved when offline, so the lowest versions allowed by their requirements are used instead of the %s", resolution))
+		return
+	}
+
+	var mu sync.Mutex
+	versions := make(map[packageKey][]string, len(toResolve))
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentVersionRequests)
+	for key := range toResolve {
+		g.Go(func() error {
+			vs, err := lister.ListVersions(ctx, key.ecosystem, key.name)
+			if err != nil {
+				if status.Code(err) != codes.NotFound {
+					slog.Warn(fmt.Sprintf("Could not list the versions of %s/%s, so the lowest version allowed by its requirement is used: %v", key.ecosystem, key.name, err))
+				}
+
+				return nil
+			}
+
+			mu.Lock()
+			versions[key] = vs
+			mu.Unlock()
+
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	resolved := 0
+	for key, indices := range toResolve {
+		sys := resolutionSystems[key.ecosystem]
+
+		for _, i := range indices {
+			inv := packages[i].PackageInfo.Inventory
+			version, ok := resolveVersion(sys, versionRequirement(inv), versions[key], resolution)
+			if !ok {
+				continue
+			}
+
+			slog.Debug(fmt.Sprintf("Resolved %s %s of %s to %s", key.name, versionRequirement(inv), packages[i].PackageInfo.Location(), version))
+			inv.Version = version
+			resolved++
+		}
+	}
+
+	slog.Info(fmt.Sprintf("Resolved the requirements of %d %s to the %s version that satisfies them", resolved, output.Form(resolved, "package", "packages"), resolution))
+}
+
+// resolveVersion returns the lowest or highest of the versions that satisfies the requirement,
+// returning false if none of them do
+func resolveVersion(sys semver.System, requirement string, versions []string, resolution string) (string, bool) {
+	constraint, err := sys.ParseConstraint(requirement)
+	if err != nil {
+		return "", false
+	}
+
+	var satisfying []string
+	for _, v := range versions {
+		if constraint.Match(v) {
+			satisfying = append(satisfying, v)
+		}
+	}
+
+	if len(satisfying) == 0 {
+		return "", false
+	}
+
+	slices.SortFunc(satisfying, sys.Compare)
+
+	if resolution == ManifestResolutionHighest {
+		return satisfying[len(satisfying)-1], true
+	}
+
+	return satisfying[0], true
+}
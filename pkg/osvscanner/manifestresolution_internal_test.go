@@ -0,0 +1,132 @@
+package osvscanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scanner/v2/internal/clients/clientinterfaces"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/python/requirements"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/rust/cargotoml"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeVersionLister lists the versions of packages by their name, with packages
+// that it does not have the versions of not being found
+type fakeVersionLister map[string][]string
+
+func (lister fakeVersionLister) ListVersions(_ context.Context, _ osvschema.Ecosystem, name string) ([]string, error) {
+	versions, ok := lister[name]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "package not found")
+	}
+
+	return versions, nil
+}
+
+func Test_resolveManifestVersions(t *testing.T) {
+	t.Parallel()
+
+	lister := fakeVersionLister{
+		"serde":    {"0.9.0", "1.0.0", "1.0.195", "1.0.197", "2.0.0-alpha.1"},
+		"regex":    {"1.9.6", "1.10.0", "1.10.3", "1.11.0"},
+		"requests": {"2.30.0", "2.31.0", "2.32.3"},
+		"django":   {"4.2.0", "4.2.11", "5.0.0"},
+	}
+
+	newPackages := func() []imodels.PackageScanResult {
+		crate := func(name string, requirement string, version string) imodels.PackageScanResult {
+			return imodels.PackageScanResult{
+				PackageInfo: imodels.FromInventory(&extractor.Inventory{
+					Name:      name,
+					Version:   version,
+					Locations: []string{"Cargo.toml"},
+					Extractor: cargotoml.Extractor{},
+					Metadata:  cargotoml.Metadata{Requirement: requirement, DepGroupVals: []string{}},
+				}),
+			}
+		}
+		requirement := func(name string, comparator string, version string) imodels.PackageScanResult {
+			return imodels.PackageScanResult{
+				PackageInfo: imodels.FromInventory(&extractor.Inventory{
+					Name:      name,
+					Version:   version,
+					Locations: []string{"requirements.txt"},
+					Extractor: requirements.Extractor{},
+					Metadata:  &requirements.Metadata{VersionComparator: comparator},
+				}),
+			}
+		}
+
+		return []imodels.PackageScanResult{
+			crate("serde", "1.0.195", "1.0.195"),
+			crate("regex", "~1.10", "1.10.0"),
+			crate("unpublished", "^3.1", "3.1.0"),
+			crate("rand", "=0.8.5", "0.8.5"),
+			requirement("requests", ">=", "2.31"),
+			requirement("django", "==", "4.2.0"),
+		}
+	}
+
+	versions := func(packages []imodels.PackageScanResult) []string {
+		vs := make([]string, 0, len(packages))
+		for _, psr := range packages {
+			vs = append(vs, psr.PackageInfo.Version())
+		}
+
+		return vs
+	}
+
+	tests := []struct {
+		name       string
+		resolution string
+		lister     fakeVersionLister
+		want       []string
+	}{
+		{
+			name:       "declared",
+			resolution: ManifestResolutionDeclared,
+			lister:     lister,
+			want:       []string{"1.0.195", "1.10.0", "3.1.0", "0.8.5", "2.31", "4.2.0"},
+		},
+		{
+			name:       "lowest",
+			resolution: ManifestResolutionLowest,
+			lister:     lister,
+			want:       []string{"1.0.195", "1.10.0", "3.1.0", "0.8.5", "2.31.0", "4.2.0"},
+		},
+		{
+			name:       "highest",
+			resolution: ManifestResolutionHighest,
+			lister:     lister,
+			want:       []string{"1.0.197", "1.10.3", "3.1.0", "0.8.5", "2.32.3", "4.2.0"},
+		},
+		{
+			name:       "offline",
+			resolution: ManifestResolutionHighest,
+			lister:     nil,
+			want:       []string{"1.0.195", "1.10.0", "3.1.0", "0.8.5", "2.31", "4.2.0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var lister clientinterfaces.VersionLister
+			if tt.lister != nil {
+				lister = tt.lister
+			}
+
+			packages := newPackages()
+			resolveManifestVersions(context.Background(), lister, packages, tt.resolution)
+
+			if diff := cmp.Diff(tt.want, versions(packages)); diff != "" {
+				t.Errorf("resolveManifestVersions() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package osvscanner
+
+import (
+	"github.com/google/osv-scalibr/clients/datasource"
+	mavensettings "github.com/google/osv-scanner/v2/internal/datasource"
+)
+
+// mavenSettingsRegistries returns the registry to fetch Maven metadata from by default, along with
+// the registries to also fetch it from, following the mirrors and active profiles of settings.xml.
+//
+// An explicitly given registryURL is used as is, while Maven Central is replaced by its mirror (if any).
+func mavenSettingsRegistries(registryURL string) (datasource.MavenRegistry, []datasource.MavenRegistry) {
+	globalSettings, userSettings := mavensettings.LoadMavenSettings()
+	mirrors := mavensettings.MavenMirrors(globalSettings, userSettings)
+
+	defaultRegistry := mavensettings.MavenRegistry{URL: registryURL, ReleasesEnabled: true}
+	if registryURL == "" {
+		defaultRegistry = mavensettings.MirroredRegistry(mirrors, mavensettings.MavenRegistry{
+			URL:             mavensettings.MavenCentral,
+			ID:              "central",
+			ReleasesEnabled: true,
+		})
+	}
+
+	//nolint:prealloc // there are usually no repositories in settings.xml
+	var registries []datasource.MavenRegistry
+	for _, reg := range mavensettings.MavenProfileRegistries(globalSettings, userSettings) {
+		registries = append(registries, toScalibrMavenRegistry(mavensettings.MirroredRegistry(mirrors, reg)))
+	}
+
+	return toScalibrMavenRegistry(defaultRegistry), registries
+}
+
+func toScalibrMavenRegistry(reg mavensettings.MavenRegistry) datasource.MavenRegistry {
+	return datasource.MavenRegistry{
+		URL:              reg.URL,
+		ID:               reg.ID,
+		ReleasesEnabled:  reg.ReleasesEnabled,
+		SnapshotsEnabled: reg.SnapshotsEnabled,
+	}
+}
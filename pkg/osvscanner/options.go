@@ -0,0 +1,139 @@
+package osvscanner
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+// Results are the results of a scan made with Scan
+type Results = models.VulnerabilityResults
+
+// ScanOptions configures a scan made with Scan.
+//
+// Unlike ScannerActions, which mirrors the flags of the CLI (including experimental ones),
+// the fields of ScanOptions are only ever added to, so that tools embedding osv-scanner
+// can rely on them between releases.
+type ScanOptions struct {
+	// Paths are directories to scan for lockfiles, manifests and SBOMs
+	Paths []string
+	// Lockfiles are lockfiles to scan, which can be prefixed with the format of the
+	// lockfile to parse it as, e.g. "package-lock.json:/path/to/lockfile"
+	Lockfiles []string
+	// SBOMs are SBOMs to scan, or directories to search for SBOMs
+	SBOMs []string
+	// Recursive scans the subdirectories of Paths
+	Recursive bool
+	// NoIgnore also scans files that would be ignored by .gitignore files
+	NoIgnore bool
+	// SkipDirs are patterns of directory names to skip when scanning Paths, e.g. "node_modules"
+	SkipDirs []string
+	// SBOMOnly only scans the SBOMs within Paths, skipping lockfiles and manifests
+	SBOMOnly bool
+	// IncludeGitRoot also scans the git repositories that Paths are the root of, rather than just submodules
+	IncludeGitRoot bool
+	// ChangedSince only scans the directories of Paths with files that have changed since the git ref, e.g. "origin/main"
+	ChangedSince string
+	// Jobs is the number of files to extract at once when scanning Paths, defaulting to GOMAXPROCS if it is not positive
+	Jobs int
+	// PythonEnvs are Python environments (or their site-packages directories) to scan the installed packages of
+	PythonEnvs []string
+	// Archives are zip or tar archives to scan the lockfiles and SBOMs within, without extracting them to disk
+	Archives []string
+	// Commits are full git commit hashes to match against OSV for the repositories they are from
+	Commits []string
+
+	// Offline matches packages against local databases, rather than querying the OSV API
+	Offline bool
+	// DownloadOfflineDatabases downloads the local databases that are needed when Offline
+	DownloadOfflineDatabases bool
+	// LocalDBPath is the directory of the local databases, defaulting to the user cache directory
+	LocalDBPath string
+
+	// ConfigPath is the path of a config file to use in place of any osv-scanner.toml
+	// files that would otherwise be found alongside the files being scanned
+	ConfigPath string
+	// BaseConfigPath is the path of a config file that the osv-scanner.toml files found
+	// alongside the files being scanned are merged on top of
+	BaseConfigPath string
+	// IgnoredVulns are the IDs of vulnerabilities to ignore in every package,
+	// in addition to those ignored by config files
+	IgnoredVulns []string
+
+	// ShowAllPackages includes packages without any vulnerabilities in the results
+	ShowAllPackages bool
+	// NoDevDependencies skips packages that are only used for development
+	NoDevDependencies bool
+	// Strict fails the scan with the error of a file that cannot be parsed, rather than
+	// returning ErrPartialScan with the results of the rest of the files
+	Strict bool
+	// CallAnalysis enables or disables call analysis for each of the languages that support it, e.g. "go"
+	CallAnalysis map[string]bool
+	// FailOnVulnerabilities returns ErrVulnerabilitiesFound (joined with ErrPartialScan if need be)
+	// when vulnerabilities or license violations are found, like the CLI does
+	FailOnVulnerabilities bool
+
+	// Experimental are the options of the CLI that are not stable yet, which may change between releases.
+	// The fields of ScanOptions take precedence over those of Experimental that they also set.
+	Experimental ExperimentalScannerActions
+}
+
+// Scan scans the paths of the options for packages and matches them against known
+// vulnerabilities, returning the results for them to be rendered by the caller.
+//
+// Unlike DoScan, vulnerabilities being found is not an error (unless FailOnVulnerabilities is set): the error is nil unless the scan
+// could not be completed, with ErrNoPackagesFound being returned if there were no packages to
+// scan and ErrPartialScan (along with the results) if any of the files could not be parsed.
+// Progress is logged with log/slog, and nothing is written to stdout.
+func Scan(opts ScanOptions) (Results, error) {
+	return ScanContext(context.Background(), opts)
+}
+
+// ScanContext is like Scan, but stops the scan once ctx is cancelled, returning the error of the context
+func ScanContext(ctx context.Context, opts ScanOptions) (Results, error) {
+	results, err := DoScanContext(ctx, opts.actions())
+
+	if errors.Is(err, ErrVulnerabilitiesFound) && !opts.FailOnVulnerabilities {
+		if errors.Is(err, ErrPartialScan) {
+			err = ErrPartialScan
+		} else {
+			err = nil
+		}
+	}
+
+	return results, err
+}
+
+func (opts ScanOptions) actions() ScannerActions {
+	experimental := opts.Experimental
+	experimental.CompareOffline = experimental.CompareOffline || opts.Offline
+	experimental.DownloadDatabases = experimental.DownloadDatabases || opts.DownloadOfflineDatabases
+	experimental.ShowAllPackages = experimental.ShowAllPackages || opts.ShowAllPackages
+	experimental.NoDevDependencies = experimental.NoDevDependencies || opts.NoDevDependencies
+	if opts.LocalDBPath != "" {
+		experimental.LocalDBPath = opts.LocalDBPath
+	}
+
+	return ScannerActions{
+		DirectoryPaths:             opts.Paths,
+		LockfilePaths:              opts.Lockfiles,
+		SBOMPaths:                  opts.SBOMs,
+		PythonEnvPaths:             opts.PythonEnvs,
+		ArchivePaths:               opts.Archives,
+		GitCommits:                 opts.Commits,
+		Recursive:                  opts.Recursive,
+		IncludeGitRoot:             opts.IncludeGitRoot,
+		NoIgnore:                   opts.NoIgnore,
+		SBOMOnly:                   opts.SBOMOnly,
+		SkipDirs:                   opts.SkipDirs,
+		Jobs:                       opts.Jobs,
+		Strict:                     opts.Strict,
+		ChangedSince:               opts.ChangedSince,
+		ConfigOverridePath:         opts.ConfigPath,
+		BaseConfigPath:             opts.BaseConfigPath,
+		IgnoredVulns:               opts.IgnoredVulns,
+		CallAnalysisStates:         opts.CallAnalysis,
+		ExperimentalScannerActions: experimental,
+	}
+}
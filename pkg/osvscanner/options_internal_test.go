@@ -0,0 +1,152 @@
+package osvscanner
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// writeLocalDB writes a local database of the vulnerabilities for the ecosystem
+// to the directory, in the layout that is expected of offline databases
+func writeLocalDB(t *testing.T, dir string, ecosystem string, vulns ...osvschema.Vulnerability) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Join(dir, "osv-scanner", ecosystem), 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Create(filepath.Join(dir, "osv-scanner", ecosystem, "all.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for _, vuln := range vulns {
+		zf, err := w.Create(vuln.ID + ".json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.NewEncoder(zf).Encode(vuln); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func affectingMinimist(id string) osvschema.Vulnerability {
+	return osvschema.Vulnerability{
+		ID: id,
+		Affected: []osvschema.Affected{{
+			Package: osvschema.Package{Ecosystem: "npm", Name: "minimist"},
+			Ranges: []osvschema.Range{{
+				Type:   osvschema.RangeSemVer,
+				Events: []osvschema.Event{{Introduced: "0"}, {Fixed: "1.2.6"}},
+			}},
+		}},
+	}
+}
+
+func TestScan(t *testing.T) {
+	t.Parallel()
+
+	dbDir := t.TempDir()
+	writeLocalDB(t, dbDir, "npm", affectingMinimist("GHSA-0001"), affectingMinimist("GHSA-0002"))
+
+	results, err := Scan(ScanOptions{
+		Lockfiles:    []string{"./fixtures/sboms/service-b/package-lock.json"},
+		Offline:      true,
+		LocalDBPath:  dbDir,
+		IgnoredVulns: []string{"GHSA-0002"},
+	})
+
+	// vulnerabilities being found is not an error
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	var got []string
+	for _, vf := range results.Flatten() {
+		got = append(got, vf.Package.Name+"@"+vf.Package.Version+": "+vf.Vulnerability.ID)
+	}
+
+	if diff := cmp.Diff([]string{"minimist@1.2.5: GHSA-0001"}, got); diff != "" {
+		t.Errorf("Scan() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestScan_NoPackages(t *testing.T) {
+	t.Parallel()
+
+	_, err := Scan(ScanOptions{Paths: []string{t.TempDir()}, Offline: true, LocalDBPath: t.TempDir()})
+
+	if !errors.Is(err, ErrNoPackagesFound) {
+		t.Errorf("Scan() error = %v, want %v", err, ErrNoPackagesFound)
+	}
+}
+
+func TestScanContext_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ScanContext(ctx, ScanOptions{
+		Lockfiles:   []string{"./fixtures/sboms/service-b/package-lock.json"},
+		Offline:     true,
+		LocalDBPath: t.TempDir(),
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ScanContext() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestScan_FailOnVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	dbDir := t.TempDir()
+	writeLocalDB(t, dbDir, "npm", affectingMinimist("GHSA-0001"))
+
+	_, err := Scan(ScanOptions{
+		Lockfiles:             []string{"./fixtures/sboms/service-b/package-lock.json"},
+		Offline:               true,
+		LocalDBPath:           dbDir,
+		FailOnVulnerabilities: true,
+	})
+
+	if !errors.Is(err, ErrVulnerabilitiesFound) {
+		t.Errorf("Scan() error = %v, want %v", err, ErrVulnerabilitiesFound)
+	}
+}
+
+func TestScanOptions_actions_Experimental(t *testing.T) {
+	t.Parallel()
+
+	actions := ScanOptions{
+		Offline:     true,
+		LocalDBPath: "/path/to/dbs",
+		Experimental: ExperimentalScannerActions{
+			LocalDBPath:    "/path/to/other/dbs",
+			FailOnSeverity: 7,
+		},
+	}.actions()
+
+	want := ExperimentalScannerActions{
+		CompareOffline: true,
+		LocalDBPath:    "/path/to/dbs",
+		FailOnSeverity: 7,
+	}
+	if diff := cmp.Diff(want, actions.ExperimentalScannerActions); diff != "" {
+		t.Errorf("actions() mismatch (-want +got):\n%s", diff)
+	}
+}
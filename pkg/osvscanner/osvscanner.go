@@ -2,6 +2,7 @@ package osvscanner
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -20,9 +21,13 @@ import (
 	"github.com/google/osv-scalibr/clients/resolution"
 	"github.com/google/osv-scalibr/extractor"
 	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/baseimagematcher"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/epssmatcher"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/githubmatcher"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/kevmatcher"
 	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/licensematcher"
 	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/localmatcher"
 	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/osvmatcher"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/versionlister"
 	"github.com/google/osv-scanner/v2/internal/clients/clientinterfaces"
 	"github.com/google/osv-scanner/v2/internal/config"
 	"github.com/google/osv-scanner/v2/internal/depsdev"
@@ -30,7 +35,10 @@ import (
 	"github.com/google/osv-scanner/v2/internal/imodels/results"
 	"github.com/google/osv-scanner/v2/internal/osvdev"
 	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/internal/utility/severity"
+	"github.com/google/osv-scanner/v2/internal/utility/vulns"
 	"github.com/google/osv-scanner/v2/internal/version"
+	"github.com/google/osv-scanner/v2/internal/vex"
 	"github.com/google/osv-scanner/v2/pkg/models"
 	"github.com/google/osv-scanner/v2/pkg/osvscanner/internal/imagehelpers"
 	"github.com/google/osv-scanner/v2/pkg/osvscanner/internal/scanners"
@@ -38,29 +46,124 @@ import (
 )
 
 type ScannerActions struct {
-	LockfilePaths      []string
-	SBOMPaths          []string
-	DirectoryPaths     []string
-	GitCommits         []string
-	Recursive          bool
-	IncludeGitRoot     bool
-	NoIgnore           bool
+	LockfilePaths  []string
+	SBOMPaths      []string
+	DirectoryPaths []string
+	// PythonEnvPaths are Python environments (or their site-packages directories)
+	// to scan the installed packages of
+	PythonEnvPaths []string
+	// ArchivePaths are zip or tar archives to scan the lockfiles and SBOMs within,
+	// without extracting them to disk
+	ArchivePaths   []string
+	GitCommits     []string
+	Recursive      bool
+	IncludeGitRoot bool
+	NoIgnore       bool
+	SBOMOnly       bool
+	SkipDirs       []string
+	// Jobs is the number of files to extract at once when scanning directories,
+	// defaulting to GOMAXPROCS if it is not positive
+	Jobs int
+	// Strict fails the scan with the error of a file that cannot be extracted,
+	// rather than reporting it as a warning along with the results of the rest
+	Strict             bool
+	ChangedSince       string
 	Image              string
 	IsImageArchive     bool
+	IsImageRemote      bool
+	ImagePlatform      string
 	ConfigOverridePath string
+	BaseConfigPath     string
+	// IgnoredVulns are the IDs of vulnerabilities to ignore in every package,
+	// in addition to those ignored by config files
+	IgnoredVulns       []string
 	CallAnalysisStates map[string]bool
 
 	ExperimentalScannerActions
 }
 
 type ExperimentalScannerActions struct {
-	CompareOffline        bool
-	DownloadDatabases     bool
-	ShowAllPackages       bool
+	CompareOffline    bool
+	DownloadDatabases bool
+	ShowAllPackages   bool
+	// IncludeIgnored keeps the vulnerabilities that are ignored by the config or VEX statements in the results, as the
+	// ExperimentalIgnoredVulnerabilities of their package, so that they can be reported as VEX statements
+	IncludeIgnored        bool
+	NoDevDependencies     bool
 	ScanLicensesSummary   bool
 	ScanLicensesAllowlist []string
+	// ScanLicensesAllowUnknown stops packages without any license data from being
+	// reported as violations of the ScanLicensesAllowlist
+	ScanLicensesAllowUnknown bool
+	// FailOnSeverity is the lowest CVSS score that a vulnerability must have to fail
+	// the scan, with 0 meaning that any vulnerability fails the scan regardless of severity
+	FailOnSeverity float64
+	// EPSS enriches the findings with the EPSS scores of their CVEs
+	EPSS bool
+	// MinEPSS is the lowest EPSS probability, from 0 to 1, that a vulnerability must have to fail
+	// the scan, with 0 meaning that any vulnerability fails the scan; setting it implies EPSS
+	MinEPSS float64
+	// KEV flags the findings whose CVEs are in CISA's Known Exploited Vulnerabilities catalog
+	KEV bool
+	// KEVOnly removes the findings that are not in the Known Exploited Vulnerabilities catalog
+	// from the results; setting it implies KEV
+	KEVOnly bool
+	// CollapseSources aggregates identical findings across sources in the results
+	CollapseSources bool
+	// HideUncalled removes vulnerabilities that call analysis determined are not called from the results
+	HideUncalled bool
+	// BaselinePath is the path to the JSON output of a previous scan, whose vulnerabilities
+	// are removed from the results so that only newly introduced vulnerabilities are reported
+	BaselinePath string
+	// VEXPaths are OpenVEX or CycloneDX VEX documents with statements about the vulnerabilities
+	// that do not affect packages, which are suppressed from the results
+	VEXPaths []string
+	// ExtraAdvisoryPaths are OSV advisory files, or directories of them, that packages are matched
+	// against in addition to the OSV database, such as internal advisories of first-party packages
+	ExtraAdvisoryPaths []string
+	// IncludeWithdrawn keeps vulnerabilities whose advisories have been withdrawn in the results,
+	// which are otherwise removed after matching
+	IncludeWithdrawn bool
+	// AllVulns also matches the vulnerabilities of each package that do not affect its version,
+	// which are included in the results separately to the findings for auditing purposes
+	AllVulns bool
+	// ShowDependencyPaths includes the path through which each vulnerable package is depended on
+	// in the results, for lockfiles that record the graph of their dependencies
+	ShowDependencyPaths bool
+	// DirectOnly only scans the packages that are direct dependencies of the project they are from,
+	// along with those that cannot be determined to be either direct or transitive dependencies
+	DirectOnly bool
+	// TransitiveOnly only scans the packages that are transitive dependencies of the project they are
+	// from, along with those that cannot be determined to be either direct or transitive dependencies
+	TransitiveOnly bool
+	// Incremental reuses the packages that previous scans extracted from source files that have not
+	// changed since, and the vulnerabilities matched for them as long as the database has not been updated
+	Incremental bool
+	// CacheDir is the directory that incremental scans cache the packages and vulnerabilities in,
+	// which defaults to the LocalDBPath or otherwise the user cache directory
+	CacheDir string
+	// CacheTTL is how long incremental scans reuse the vulnerabilities matched by the OSV API for,
+	// with zero meaning the default of an hour
+	CacheTTL time.Duration
+	// CheckLockfileDrift warns about lockfiles that do not lock all the dependencies declared by the
+	// manifest beside them, or that lock dependencies which the manifest no longer declares
+	CheckLockfileDrift bool
+	// ManifestResolution is how packages that manifests declare with a version requirement are resolved
+	// to the version that is matched, being one of ManifestResolutions (defaulting to the declared version)
+	ManifestResolution string
 
 	LocalDBPath string
+	// OSVBaseURL overrides the host of the OSV API, e.g. for a self-hosted mirror
+	OSVBaseURL string
+	// OSVHeaders are additional headers to send with each request to the OSV API
+	OSVHeaders map[string]string
+	// OSVBatchSize is the number of packages to query in each batch request to the OSV API
+	OSVBatchSize int
+	// OSVMaxConcurrentBatches is the number of batch requests to make to the OSV API at once
+	OSVMaxConcurrentBatches int
+	// GitHubToken is a token for the GitHub API which, if set, is used to also match packages
+	// against GitHub's security advisory database
+	GitHubToken string
 	TransitiveScanningActions
 }
 
@@ -75,6 +178,11 @@ type ExternalAccessors struct {
 	VulnMatcher      clientinterfaces.VulnerabilityMatcher
 	LicenseMatcher   clientinterfaces.LicenseMatcher
 	BaseImageMatcher clientinterfaces.BaseImageMatcher
+	EPSSMatcher      clientinterfaces.EPSSMatcher
+	KEVMatcher       clientinterfaces.KEVMatcher
+
+	// Required for resolving the version requirements of manifests
+	VersionLister clientinterfaces.VersionLister
 
 	// Required for pomxmlnet Extractor
 	MavenRegistryAPIClient *datasource.MavenRegistryAPIClient
@@ -98,17 +206,107 @@ var ErrVulnerabilitiesFound = errors.New("vulnerabilities found")
 // TODO(v2): Actually use this error
 var ErrAPIFailed = errors.New("API query failed")
 
+// ErrPartialScan for when some of the files being scanned could not be extracted,
+// meaning the results may be missing the packages they contain.
+var ErrPartialScan = errors.New("some files could not be scanned")
+
+// newOSVClient creates a client for the OSV API, using the configured host, headers
+// and batching options if any
+func newOSVClient(actions ScannerActions) *osvdev.OSVClient {
+	client := osvdev.DefaultClient()
+
+	if actions.OSVBaseURL != "" {
+		client.BaseHostURL = strings.TrimSuffix(actions.OSVBaseURL, "/")
+	}
+	client.Config.Headers = actions.OSVHeaders
+
+	if actions.OSVBatchSize > 0 {
+		client.Config.MaxQueriesPerBatchRequest = actions.OSVBatchSize
+	}
+	if actions.OSVMaxConcurrentBatches > 0 {
+		client.Config.MaxConcurrentBatchRequests = actions.OSVMaxConcurrentBatches
+	}
+
+	return client
+}
+
+// applyOSVAPIConfig sets the OSV API options from the config file, with those
+// that have been set directly on the actions (e.g. by flags) taking precedence
+func applyOSVAPIConfig(actions *ScannerActions, osvAPIConfig config.OSVAPIConfig) {
+	if actions.OSVBaseURL == "" {
+		actions.OSVBaseURL = osvAPIConfig.BaseURL
+	}
+
+	if len(osvAPIConfig.Headers) == 0 {
+		return
+	}
+
+	// header names are case-insensitive, so they are canonicalized for those set
+	// by flags to replace the same header in the config regardless of its case
+	headers := make(map[string]string, len(osvAPIConfig.Headers)+len(actions.OSVHeaders))
+	for name, value := range osvAPIConfig.Headers {
+		headers[http.CanonicalHeaderKey(name)] = value
+	}
+	for name, value := range actions.OSVHeaders {
+		headers[http.CanonicalHeaderKey(name)] = value
+	}
+	actions.OSVHeaders = headers
+}
+
+// setupConfig loads the config files that apply to the whole scan, being either
+// the override config which replaces all other config files, or the base config
+// which is merged into them
+func setupConfig(actions *ScannerActions, manager *config.Manager) error {
+	var err error
+	var cfg *config.Config
+
+	switch {
+	case actions.ConfigOverridePath != "":
+		err = manager.UseOverride(actions.ConfigOverridePath)
+		cfg = manager.OverrideConfig
+	case actions.BaseConfigPath != "":
+		err = manager.UseBase(actions.BaseConfigPath)
+		cfg = manager.BaseConfig
+	}
+
+	if err != nil {
+		slog.Error(fmt.Sprintf("Failed to read config file: %s", err))
+		return err
+	}
+
+	if cfg != nil {
+		applyOSVAPIConfig(actions, cfg.OSVAPI)
+	}
+
+	ignores := make([]config.IgnoreEntry, 0, len(actions.IgnoredVulns))
+	for _, id := range actions.IgnoredVulns {
+		ignores = append(ignores, config.IgnoreEntry{ID: id, Reason: "ignored by the options of the scan"})
+	}
+	manager.AddIgnores(ignores...)
+
+	return nil
+}
+
 func initializeExternalAccessors(actions ScannerActions) (ExternalAccessors, error) {
 	externalAccessors := ExternalAccessors{
 		DependencyClients: map[osvschema.Ecosystem]resolve.Client{},
 	}
 	var err error
 
+	// --- KEV Matcher ---
+	// the catalog is cached, so it can still be used when offline if it was downloaded by a previous scan
+	if actions.KEV || actions.KEVOnly {
+		externalAccessors.KEVMatcher, err = kevmatcher.NewCISAKEVMatcher(actions.LocalDBPath, "osv-scanner_scan/"+version.OSVVersion, actions.CompareOffline)
+		if err != nil {
+			return ExternalAccessors{}, err
+		}
+	}
+
 	// Offline Mode
 	// ------------
 	if actions.CompareOffline {
 		// --- Vulnerability Matcher ---
-		externalAccessors.VulnMatcher, err = localmatcher.NewLocalMatcher(actions.LocalDBPath, "osv-scanner_scan/"+version.OSVVersion, actions.DownloadDatabases)
+		externalAccessors.VulnMatcher, err = localmatcher.NewLocalMatcher(actions.LocalDBPath, "osv-scanner_scan/"+version.OSVVersion, actions.DownloadDatabases, actions.IncludeWithdrawn)
 		if err != nil {
 			return ExternalAccessors{}, err
 		}
@@ -120,10 +318,20 @@ func initializeExternalAccessors(actions ScannerActions) (ExternalAccessors, err
 	// -----------
 	// --- Vulnerability Matcher ---
 	externalAccessors.VulnMatcher = &osvmatcher.OSVMatcher{
-		Client:              *osvdev.DefaultClient(),
+		Client:              *newOSVClient(actions),
 		InitialQueryTimeout: 5 * time.Minute,
 	}
 
+	if actions.GitHubToken != "" {
+		externalAccessors.VulnMatcher = &githubmatcher.GitHubMatcher{
+			Matcher:    externalAccessors.VulnMatcher,
+			HTTPClient: http.DefaultClient,
+			Token:      actions.GitHubToken,
+			APIURL:     githubmatcher.DefaultAPIURL,
+			UserAgent:  "osv-scanner_scan/" + version.OSVVersion,
+		}
+	}
+
 	// --- License Matcher ---
 	if len(actions.ScanLicensesAllowlist) > 0 || actions.ScanLicensesSummary {
 		depsDevAPIClient, err := datasource.NewCachedInsightsClient(depsdev.DepsdevAPI, "osv-scanner_scan/"+version.OSVVersion)
@@ -136,6 +344,27 @@ func initializeExternalAccessors(actions ScannerActions) (ExternalAccessors, err
 		}
 	}
 
+	// --- Version Lister ---
+	if actions.ManifestResolution != "" && actions.ManifestResolution != ManifestResolutionDeclared {
+		depsDevAPIClient, err := datasource.NewCachedInsightsClient(depsdev.DepsdevAPI, "osv-scanner_scan/"+version.OSVVersion)
+		if err != nil {
+			return ExternalAccessors{}, err
+		}
+
+		externalAccessors.VersionLister = &versionlister.DepsDevVersionLister{
+			Client: depsDevAPIClient,
+		}
+	}
+
+	// --- EPSS Matcher ---
+	if actions.EPSS || actions.MinEPSS > 0 {
+		externalAccessors.EPSSMatcher = &epssmatcher.FirstEPSSMatcher{
+			HTTPClient: http.DefaultClient,
+			APIURL:     epssmatcher.DefaultAPIURL,
+			UserAgent:  "osv-scanner_scan/" + version.OSVVersion,
+		}
+	}
+
 	// --- Base Image Matcher ---
 	if actions.Image != "" {
 		externalAccessors.BaseImageMatcher = &baseimagematcher.DepsDevBaseImageMatcher{
@@ -146,7 +375,7 @@ func initializeExternalAccessors(actions ScannerActions) (ExternalAccessors, err
 
 	// --- OSV.dev Client ---
 	// We create a separate client from VulnMatcher to keep things clean.
-	externalAccessors.OSVDevClient = osvdev.DefaultClient()
+	externalAccessors.OSVDevClient = newOSVClient(actions)
 
 	// --- No Transitive Scanning ---
 	if actions.TransitiveScanningActions.Disabled {
@@ -154,19 +383,34 @@ func initializeExternalAccessors(actions ScannerActions) (ExternalAccessors, err
 	}
 
 	// --- Transitive Scanning Clients ---
-	externalAccessors.MavenRegistryAPIClient, err = datasource.NewMavenRegistryAPIClient(datasource.MavenRegistry{
-		URL:             actions.TransitiveScanningActions.MavenRegistry,
-		ReleasesEnabled: true,
-	})
+	defaultRegistry, settingsRegistries := mavenSettingsRegistries(actions.TransitiveScanningActions.MavenRegistry)
+	externalAccessors.MavenRegistryAPIClient, err = datasource.NewMavenRegistryAPIClient(defaultRegistry)
 
 	if err != nil {
 		return ExternalAccessors{}, err
 	}
 
+	for _, reg := range settingsRegistries {
+		if err := externalAccessors.MavenRegistryAPIClient.AddRegistry(reg); err != nil {
+			return ExternalAccessors{}, fmt.Errorf("invalid Maven registry %s in settings.xml: %w", reg.ID, err)
+		}
+	}
+
 	if !actions.TransitiveScanningActions.NativeDataSource {
 		externalAccessors.DependencyClients[osvschema.EcosystemMaven], err = resolution.NewDepsDevClient(depsdev.DepsdevAPI, "osv-scanner_scan/"+version.OSVVersion)
 	} else {
-		externalAccessors.DependencyClients[osvschema.EcosystemMaven], err = resolution.NewMavenRegistryClient(actions.TransitiveScanningActions.MavenRegistry)
+		var mavenClient *resolution.MavenRegistryClient
+		// a mirror of Maven Central replaces it as the default registry, so that Maven Central itself is never queried
+		mavenClient, err = resolution.NewMavenRegistryClient(defaultRegistry.URL)
+
+		if err == nil {
+			registries := make([]resolution.Registry, 0, len(settingsRegistries))
+			for _, reg := range settingsRegistries {
+				registries = append(registries, reg)
+			}
+			err = mavenClient.AddRegistries(registries)
+			externalAccessors.DependencyClients[osvschema.EcosystemMaven] = mavenClient
+		}
 	}
 
 	if err != nil {
@@ -178,18 +422,47 @@ func initializeExternalAccessors(actions ScannerActions) (ExternalAccessors, err
 
 // DoScan performs the osv scanner action, with optional reporter to output information
 func DoScan(actions ScannerActions) (models.VulnerabilityResults, error) {
+	return DoScanContext(context.Background(), actions)
+}
+
+// checkOfflineActions returns an error if the actions need data that cannot be retrieved
+// when running in offline mode, rather than silently scanning without it
+func checkOfflineActions(actions ScannerActions) error {
+	if !actions.CompareOffline {
+		return nil
+	}
+
+	if actions.ScanLicensesSummary {
+		return errors.New("cannot retrieve licenses locally")
+	}
+
+	// without EPSS scores, no vulnerability would meet --min-epss and the scan would always pass
+	if actions.EPSS || actions.MinEPSS > 0 {
+		return errors.New("cannot retrieve EPSS scores locally")
+	}
+
+	return nil
+}
+
+// DoScanContext is like DoScan, but stops the extraction of files and any requests
+// that are being made once ctx is cancelled, returning the error of the context
+func DoScanContext(ctx context.Context, actions ScannerActions) (models.VulnerabilityResults, error) {
+	start := time.Now()
+
 	// --- Sanity check flags ----
 	// TODO(v2): Move the logic of the offline flag changing other flags into here from the main.go/scan.go
-	if actions.CompareOffline {
-		if actions.ScanLicensesSummary {
-			return models.VulnerabilityResults{}, errors.New("cannot retrieve licenses locally")
-		}
+	if err := checkOfflineActions(actions); err != nil {
+		return models.VulnerabilityResults{}, err
 	}
 
 	if !actions.CompareOffline && actions.DownloadDatabases {
 		return models.VulnerabilityResults{}, errors.New("databases can only be downloaded when running in offline mode")
 	}
 
+	if !actions.CompareOffline && actions.AllVulns {
+		return models.VulnerabilityResults{}, errors.New("all vulnerabilities can only be listed when running in offline mode")
+	}
+
 	scanResult := results.ScanResults{
 		ConfigManager: config.Manager{
 			DefaultConfig: config.Config{},
@@ -198,12 +471,26 @@ func DoScan(actions ScannerActions) (models.VulnerabilityResults, error) {
 	}
 
 	// --- Setup Config ---
-	if actions.ConfigOverridePath != "" {
-		err := scanResult.ConfigManager.UseOverride(actions.ConfigOverridePath)
-		if err != nil {
-			slog.Error(fmt.Sprintf("Failed to read config file: %s", err))
-			return models.VulnerabilityResults{}, err
-		}
+	if err := setupConfig(&actions, &scanResult.ConfigManager); err != nil {
+		return models.VulnerabilityResults{}, err
+	}
+
+	// --- Setup Baseline ---
+	baseline, err := readBaseline(actions.BaselinePath)
+	if err != nil {
+		return models.VulnerabilityResults{}, err
+	}
+
+	// --- Setup VEX ---
+	vexStatements, err := vex.Load(actions.VEXPaths...)
+	if err != nil {
+		return models.VulnerabilityResults{}, err
+	}
+
+	// --- Setup Extra Advisories ---
+	extraAdvisories, err := readExtraAdvisories(actions.ExtraAdvisoryPaths)
+	if err != nil {
+		return models.VulnerabilityResults{}, err
 	}
 
 	// --- Setup Accessors/Clients ---
@@ -213,44 +500,83 @@ func DoScan(actions ScannerActions) (models.VulnerabilityResults, error) {
 	}
 
 	// ----- Perform Scanning -----
-	packages, err := scan(accessors, actions)
+	packages, fileErrs, err := scan(ctx, accessors, actions)
 	if err != nil {
+		reportFileErrors(ctx, fileErrs)
+
 		return models.VulnerabilityResults{}, err
 	}
 
 	scanResult.PackageScanResults = packages
 
+	if actions.CheckLockfileDrift {
+		reportLockfileDrift(checkLockfileDrift(scanResult.PackageScanResults))
+	}
+
 	// ----- Filtering -----
-	filterUnscannablePackages(&scanResult)
 	filterIgnoredPackages(&scanResult)
+	if actions.NoDevDependencies {
+		filterDevPackages(&scanResult)
+	}
+	if actions.DirectOnly || actions.TransitiveOnly {
+		filterPackagesByDirectness(&scanResult, actions.DirectOnly)
+	}
+	// packages that can only be matched by their CPEs are split out after the other filters, so they apply to them too
+	cpeOnlyPackages := filterUnscannablePackages(&scanResult)
 
 	// ----- Custom Overrides -----
 	overrideGoVersion(&scanResult)
+	resolveManifestVersions(ctx, accessors.VersionLister, scanResult.PackageScanResults, actions.ManifestResolution)
 
 	// --- Make Vulnerability Requests ---
 	if accessors.VulnMatcher != nil {
-		err = makeVulnRequestWithMatcher(scanResult.PackageScanResults, accessors.VulnMatcher)
+		matcher := accessors.VulnMatcher
+		if actions.Incremental {
+			matcher, err = newIncrementalMatcher(actions, accessors.VulnMatcher)
+			if err != nil {
+				return models.VulnerabilityResults{}, err
+			}
+		}
+
+		err = makeVulnRequestWithMatcher(ctx, scanResult.PackageScanResults, matcher)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+
+		matchExtraAdvisories(scanResult.PackageScanResults, extraAdvisories)
+
+		filterWithdrawn(&scanResult, actions.IncludeWithdrawn)
+
+		if actions.AllVulns {
+			err = matchUnaffectedVulns(ctx, scanResult.PackageScanResults, accessors.VulnMatcher, actions.IncludeWithdrawn)
+			if err != nil {
+				return models.VulnerabilityResults{}, err
+			}
+		}
+
+		cpeOnlyPackages, err = matchCPEOnlyPackages(ctx, cpeOnlyPackages, extraAdvisories, actions.IncludeWithdrawn)
 		if err != nil {
 			return models.VulnerabilityResults{}, err
 		}
+		scanResult.PackageScanResults = append(scanResult.PackageScanResults, cpeOnlyPackages...)
 	}
 
 	// --- Make License Requests ---
 	if accessors.LicenseMatcher != nil {
-		err = accessors.LicenseMatcher.MatchLicenses(context.Background(), scanResult.PackageScanResults)
+		err = accessors.LicenseMatcher.MatchLicenses(ctx, scanResult.PackageScanResults)
 		if err != nil {
 			return models.VulnerabilityResults{}, err
 		}
 	}
 
-	results := buildVulnerabilityResults(actions, &scanResult)
+	results := buildVulnerabilityResults(actions, &scanResult, true)
 
 	if actions.ScanLicensesSummary {
 		licenseSummary := buildLicenseSummary(&scanResult)
 		results.LicenseSummary = licenseSummary
 	}
 
-	filtered := filterResults(&results, &scanResult.ConfigManager, actions.ShowAllPackages)
+	filtered := filterResults(&results, &scanResult.ConfigManager, actions.ShowAllPackages, actions.IncludeIgnored)
 	if filtered > 0 {
 		slog.Info(fmt.Sprintf(
 			"Filtered %d %s from output",
@@ -259,10 +585,85 @@ func DoScan(actions ScannerActions) (models.VulnerabilityResults, error) {
 		))
 	}
 
-	return results, determineReturnErr(results)
+	if len(vexStatements) > 0 {
+		filterVEX(&results, vexStatements, actions.ShowAllPackages, actions.IncludeIgnored)
+	}
+
+	if actions.HideUncalled {
+		uncalled := filterUncalledVulns(&results, actions.ShowAllPackages)
+		if uncalled > 0 {
+			slog.Info(fmt.Sprintf(
+				"Filtered %d uncalled %s from output",
+				uncalled,
+				output.Form(uncalled, "vulnerability", "vulnerabilities"),
+			))
+		}
+	}
+
+	if accessors.KEVMatcher != nil {
+		if err := applyKEV(ctx, &results, accessors.KEVMatcher, actions); err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+	}
+
+	if baseline != nil {
+		filterBaseline(&results, *baseline, actions.ShowAllPackages)
+	}
+
+	// done after filtering so that scores are only requested for the findings that are reported
+	if accessors.EPSSMatcher != nil {
+		if err := enrichEPSS(ctx, &results, accessors.EPSSMatcher); err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+	}
+
+	sortResults(&results)
+
+	if actions.CollapseSources {
+		results.ExperimentalAggregatedFindings = results.Aggregate()
+	}
+
+	results.ExperimentalScannerInfo = buildScannerInfo(start, &scanResult, accessors.VulnMatcher)
+
+	err = determineReturnErr(results, actions.FailOnSeverity, actions.MinEPSS)
+	if len(fileErrs) > 0 {
+		reportFileErrors(ctx, fileErrs)
+		err = errors.Join(err, ErrPartialScan)
+	}
+
+	return results, err
+}
+
+// reportFileErrors logs the files that could not be parsed, as errors if warnings are not
+// being logged (such as with --quiet) so that the scan being partial is not hidden
+func reportFileErrors(ctx context.Context, fileErrs []scanners.FileError) {
+	level := slog.LevelWarn
+	if !slog.Default().Enabled(ctx, level) {
+		level = slog.LevelError
+	}
+
+	for _, fileErr := range fileErrs {
+		slog.Log(ctx, level, fmt.Sprintf("Failed to parse %s: %v", fileErr.Path, fileErr.Err))
+	}
 }
 
 func DoContainerScan(actions ScannerActions) (models.VulnerabilityResults, error) {
+	return DoContainerScanContext(context.Background(), actions)
+}
+
+// DoContainerScanContext is like DoContainerScan, but stops the scan of the image and
+// any requests that are being made once ctx is cancelled, returning the error of the context
+func DoContainerScanContext(ctx context.Context, actions ScannerActions) (models.VulnerabilityResults, error) {
+	start := time.Now()
+
+	if err := checkOfflineActions(actions); err != nil {
+		return models.VulnerabilityResults{}, err
+	}
+
+	if !actions.CompareOffline && actions.AllVulns {
+		return models.VulnerabilityResults{}, errors.New("all vulnerabilities can only be listed when running in offline mode")
+	}
+
 	scanResult := results.ScanResults{
 		ConfigManager: config.Manager{
 			DefaultConfig: config.Config{},
@@ -270,12 +671,26 @@ func DoContainerScan(actions ScannerActions) (models.VulnerabilityResults, error
 		},
 	}
 
-	if actions.ConfigOverridePath != "" {
-		err := scanResult.ConfigManager.UseOverride(actions.ConfigOverridePath)
-		if err != nil {
-			slog.Error(fmt.Sprintf("Failed to read config file: %s", err))
-			return models.VulnerabilityResults{}, err
-		}
+	if err := setupConfig(&actions, &scanResult.ConfigManager); err != nil {
+		return models.VulnerabilityResults{}, err
+	}
+
+	// --- Setup Baseline ---
+	baseline, err := readBaseline(actions.BaselinePath)
+	if err != nil {
+		return models.VulnerabilityResults{}, err
+	}
+
+	// --- Setup VEX ---
+	vexStatements, err := vex.Load(actions.VEXPaths...)
+	if err != nil {
+		return models.VulnerabilityResults{}, err
+	}
+
+	// --- Setup Extra Advisories ---
+	extraAdvisories, err := readExtraAdvisories(actions.ExtraAdvisoryPaths)
+	if err != nil {
+		return models.VulnerabilityResults{}, err
 	}
 
 	// --- Setup Accessors/Clients ---
@@ -286,12 +701,21 @@ func DoContainerScan(actions ScannerActions) (models.VulnerabilityResults, error
 
 	// --- Initialize Image To Scan ---'
 
+	if actions.ImagePlatform != "" {
+		if _, err := imagehelpers.ParsePlatform(actions.ImagePlatform); err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+	}
+
 	var img *image.Image
 	if actions.IsImageArchive {
 		slog.Info(fmt.Sprintf("Scanning local image tarball %q", actions.Image))
 		img, err = image.FromTarball(actions.Image, image.DefaultConfig())
+	} else if actions.IsImageRemote {
+		img, err = imagehelpers.PullRemoteImage(actions.Image, actions.ImagePlatform)
+		slog.Info(fmt.Sprintf("Scanning image %q", actions.Image))
 	} else if actions.Image != "" {
-		path, exportErr := imagehelpers.ExportDockerImage(actions.Image)
+		path, exportErr := imagehelpers.ExportDockerImage(actions.Image, actions.ImagePlatform)
 		if exportErr != nil {
 			return models.VulnerabilityResults{}, exportErr
 		}
@@ -313,12 +737,15 @@ func DoContainerScan(actions ScannerActions) (models.VulnerabilityResults, error
 
 	// --- Do Scalibr Scan ---
 	scanner := scalibr.New()
-	scalibrSR, err := scanner.ScanContainer(context.Background(), img, &scalibr.ScanConfig{
+	scalibrSR, err := scanner.ScanContainer(ctx, img, &scalibr.ScanConfig{
 		FilesystemExtractors: scanners.BuildArtifactExtractors(),
 	})
 	if err != nil {
 		return models.VulnerabilityResults{}, fmt.Errorf("failed to scan container image: %w", err)
 	}
+	if err := ctx.Err(); err != nil {
+		return models.VulnerabilityResults{}, err
+	}
 
 	if len(scalibrSR.Inventories) == 0 {
 		return models.VulnerabilityResults{}, ErrNoPackagesFound
@@ -332,27 +759,46 @@ func DoContainerScan(actions ScannerActions) (models.VulnerabilityResults, error
 	}
 
 	// --- Fill Image Metadata ---
-	scanResult.ImageMetadata, err = imagehelpers.BuildImageMetadata(img, accessors.BaseImageMatcher)
+	scanResult.ImageMetadata, err = imagehelpers.BuildImageMetadata(ctx, img, accessors.BaseImageMatcher)
 	if err != nil { // Not getting image metadata is not fatal
 		slog.Error(fmt.Sprintf("Failed to fully get image metadata: %v", err))
 	}
 
 	// ----- Filtering -----
-	filterUnscannablePackages(&scanResult)
-
 	filterNonContainerRelevantPackages(&scanResult)
+	if actions.NoDevDependencies {
+		filterDevPackages(&scanResult)
+	}
+	cpeOnlyPackages := filterUnscannablePackages(&scanResult)
 
 	// --- Make Vulnerability Requests ---
 	if accessors.VulnMatcher != nil {
-		err = makeVulnRequestWithMatcher(scanResult.PackageScanResults, accessors.VulnMatcher)
+		err = makeVulnRequestWithMatcher(ctx, scanResult.PackageScanResults, accessors.VulnMatcher)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+
+		matchExtraAdvisories(scanResult.PackageScanResults, extraAdvisories)
+
+		filterWithdrawn(&scanResult, actions.IncludeWithdrawn)
+
+		if actions.AllVulns {
+			err = matchUnaffectedVulns(ctx, scanResult.PackageScanResults, accessors.VulnMatcher, actions.IncludeWithdrawn)
+			if err != nil {
+				return models.VulnerabilityResults{}, err
+			}
+		}
+
+		cpeOnlyPackages, err = matchCPEOnlyPackages(ctx, cpeOnlyPackages, extraAdvisories, actions.IncludeWithdrawn)
 		if err != nil {
 			return models.VulnerabilityResults{}, err
 		}
+		scanResult.PackageScanResults = append(scanResult.PackageScanResults, cpeOnlyPackages...)
 	}
 
 	// --- Make License Requests ---
 	if accessors.LicenseMatcher != nil {
-		err = accessors.LicenseMatcher.MatchLicenses(context.Background(), scanResult.PackageScanResults)
+		err = accessors.LicenseMatcher.MatchLicenses(ctx, scanResult.PackageScanResults)
 		if err != nil {
 			return models.VulnerabilityResults{}, err
 		}
@@ -369,14 +815,14 @@ func DoContainerScan(actions ScannerActions) (models.VulnerabilityResults, error
 		}
 	}
 
-	results := buildVulnerabilityResults(actions, &scanResult)
+	results := buildVulnerabilityResults(actions, &scanResult, false)
 
 	if actions.ScanLicensesSummary {
 		licenseSummary := buildLicenseSummary(&scanResult)
 		results.LicenseSummary = licenseSummary
 	}
 
-	filtered := filterResults(&results, &scanResult.ConfigManager, actions.ShowAllPackages)
+	filtered := filterResults(&results, &scanResult.ConfigManager, actions.ShowAllPackages, actions.IncludeIgnored)
 	if filtered > 0 {
 		slog.Info(fmt.Sprintf(
 			"Filtered %d %s from output",
@@ -385,7 +831,126 @@ func DoContainerScan(actions ScannerActions) (models.VulnerabilityResults, error
 		))
 	}
 
-	return results, determineReturnErr(results)
+	if len(vexStatements) > 0 {
+		filterVEX(&results, vexStatements, actions.ShowAllPackages, actions.IncludeIgnored)
+	}
+
+	if accessors.KEVMatcher != nil {
+		if err := applyKEV(ctx, &results, accessors.KEVMatcher, actions); err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+	}
+
+	if baseline != nil {
+		filterBaseline(&results, *baseline, actions.ShowAllPackages)
+	}
+
+	if accessors.EPSSMatcher != nil {
+		if err := enrichEPSS(ctx, &results, accessors.EPSSMatcher); err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+	}
+
+	sortResults(&results)
+
+	if actions.CollapseSources {
+		results.ExperimentalAggregatedFindings = results.Aggregate()
+	}
+
+	results.ExperimentalScannerInfo = buildScannerInfo(start, &scanResult, accessors.VulnMatcher)
+
+	return results, determineReturnErr(results, actions.FailOnSeverity, actions.MinEPSS)
+}
+
+// readBaseline reads the JSON output of a previous scan from the given path,
+// returning nil if there is no path
+func readBaseline(path string) (*models.VulnerabilityResults, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	var baseline models.VulnerabilityResults
+	if err := json.Unmarshal(content, &baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s, which must be the json output of a previous scan: %w", path, err)
+	}
+
+	return &baseline, nil
+}
+
+// filterWithdrawn removes the vulnerabilities that have been withdrawn from the results of
+// matching, unless they are to be included in which case they are only logged
+func filterWithdrawn(scanResult *results.ScanResults, includeWithdrawn bool) {
+	now := time.Now()
+
+	if includeWithdrawn {
+		for _, psr := range scanResult.PackageScanResults {
+			for _, vuln := range psr.Vulnerabilities {
+				if isWithdrawn(vuln, now) {
+					slog.Debug(fmt.Sprintf(
+						"%s affecting %s was withdrawn on %s",
+						vuln.ID,
+						psr.PackageInfo.Name(),
+						vuln.Withdrawn.Format(time.DateOnly),
+					))
+				}
+			}
+		}
+
+		return
+	}
+
+	withdrawn := filterWithdrawnVulns(scanResult, now)
+	if withdrawn > 0 {
+		slog.Info(fmt.Sprintf(
+			"Filtered %d withdrawn %s from output",
+			withdrawn,
+			output.Form(withdrawn, "vulnerability", "vulnerabilities"),
+		))
+	}
+}
+
+// filterVEX removes the vulnerabilities that do not affect their package according to the VEX statements
+func filterVEX(results *models.VulnerabilityResults, statements vex.Statements, allPackages bool, includeIgnored bool) {
+	suppressed := filterVEXVulns(results, statements, allPackages, includeIgnored)
+	if suppressed > 0 {
+		slog.Info(fmt.Sprintf(
+			"Filtered %d %s from output that %s not affected according to VEX statements",
+			suppressed,
+			output.Form(suppressed, "vulnerability", "vulnerabilities"),
+			output.Form(suppressed, "is", "are"),
+		))
+	}
+}
+
+// filterBaseline removes the vulnerabilities of the baseline from the results,
+// logging how many were removed and which vulnerabilities have since been resolved
+func filterBaseline(results *models.VulnerabilityResults, baseline models.VulnerabilityResults, allPackages bool) {
+	resolved := resolvedBaselineVulns(*results, baseline)
+
+	known := filterBaselineVulns(results, baseline, allPackages)
+	if known > 0 {
+		slog.Info(fmt.Sprintf(
+			"Filtered %d %s from output that %s already in the baseline",
+			known,
+			output.Form(known, "vulnerability", "vulnerabilities"),
+			output.Form(known, "was", "were"),
+		))
+	}
+
+	if len(resolved) > 0 {
+		slog.Info(fmt.Sprintf(
+			"%d %s in the baseline %s been resolved: %s",
+			len(resolved),
+			output.Form(len(resolved), "vulnerability", "vulnerabilities"),
+			output.Form(len(resolved), "has", "have"),
+			strings.Join(resolved, ", "),
+		))
+	}
 }
 
 func buildLicenseSummary(scanResult *results.ScanResults) []models.LicenseCount {
@@ -432,13 +997,17 @@ func buildLicenseSummary(scanResult *results.ScanResults) []models.LicenseCount
 
 // determineReturnErr determines whether we found a "vulnerability" or not,
 // and therefore whether we should return a ErrVulnerabilityFound error.
-func determineReturnErr(results models.VulnerabilityResults) error {
+//
+// When failOnSeverity is set, only vulnerabilities with a score that is at least as high are
+// counted, meaning vulnerabilities without a known severity never fail the scan. Likewise when
+// minEPSS is set, only vulnerabilities in groups with an EPSS score that is at least as high are counted.
+func determineReturnErr(results models.VulnerabilityResults, failOnSeverity float64, minEPSS float64) error {
 	if len(results.Results) > 0 {
 		var vuln bool
 		onlyUncalledVuln := true
 		var licenseViolation bool
 		for _, vf := range results.Flatten() {
-			if vf.Vulnerability.ID != "" {
+			if vf.Vulnerability.ID != "" && meetsSeverity(vf.Vulnerability, failOnSeverity) && meetsEPSS(vf.GroupInfo, minEPSS) {
 				vuln = true
 				if vf.GroupInfo.IsCalled() {
 					onlyUncalledVuln = false
@@ -461,8 +1030,26 @@ func determineReturnErr(results models.VulnerabilityResults) error {
 	return nil
 }
 
-// TODO(V2): Add context
+// meetsSeverity returns true if the vulnerability is severe enough to fail the scan
+func meetsSeverity(vuln osvschema.Vulnerability, failOnSeverity float64) bool {
+	if failOnSeverity <= 0 {
+		return true
+	}
+
+	return severity.CalculateVulnerabilityScore(vuln) >= failOnSeverity
+}
+
+// meetsEPSS returns true if the group is likely enough to be exploited to fail the scan
+func meetsEPSS(group models.GroupInfo, minEPSS float64) bool {
+	if minEPSS <= 0 {
+		return true
+	}
+
+	return group.ExperimentalEPSS != nil && group.ExperimentalEPSS.Probability >= minEPSS
+}
+
 func makeVulnRequestWithMatcher(
+	ctx context.Context,
 	packages []imodels.PackageScanResult,
 	matcher clientinterfaces.VulnerabilityMatcher) error {
 	invs := make([]*extractor.Inventory, 0, len(packages))
@@ -470,7 +1057,11 @@ func makeVulnRequestWithMatcher(
 		invs = append(invs, pkgs.PackageInfo.Inventory)
 	}
 
-	res, err := matcher.MatchVulnerabilities(context.Background(), invs)
+	res, err := matcher.MatchVulnerabilities(ctx, invs)
+	// the results of a cancelled scan are incomplete, even if some were returned
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
 	if err != nil {
 		slog.Error(fmt.Sprintf("error when retrieving vulns: %v", err))
 		if res == nil {
@@ -485,12 +1076,54 @@ func makeVulnRequestWithMatcher(
 	return nil
 }
 
+// allVulnsMatcher is implemented by vulnerability matchers that can return every
+// vulnerability of a package, regardless of whether it affects the version of the package
+type allVulnsMatcher interface {
+	MatchAllVulnerabilities(ctx context.Context, invs []*extractor.Inventory) ([][]*osvschema.Vulnerability, error)
+}
+
+// matchUnaffectedVulns finds the vulnerabilities of each package that do not affect its version,
+// which are all of its vulnerabilities other than those that it has already been matched against
+func matchUnaffectedVulns(
+	ctx context.Context,
+	packages []imodels.PackageScanResult,
+	matcher clientinterfaces.VulnerabilityMatcher,
+	includeWithdrawn bool,
+) error {
+	m, ok := matcher.(allVulnsMatcher)
+	if !ok {
+		return errors.New("all vulnerabilities can only be listed when running in offline mode")
+	}
+
+	invs := make([]*extractor.Inventory, 0, len(packages))
+	for _, pkgs := range packages {
+		invs = append(invs, pkgs.PackageInfo.Inventory)
+	}
+
+	res, err := m.MatchAllVulnerabilities(ctx, invs)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i, allVulns := range res {
+		for _, vuln := range allVulns {
+			if vulns.Include(packages[i].Vulnerabilities, *vuln) || (!includeWithdrawn && isWithdrawn(vuln, now)) {
+				continue
+			}
+			packages[i].UnaffectedVulnerabilities = append(packages[i].UnaffectedVulnerabilities, vuln)
+		}
+	}
+
+	return nil
+}
+
 // Overrides Go version using osv-scanner.toml
 func overrideGoVersion(scanResults *results.ScanResults) {
 	for i, psr := range scanResults.PackageScanResults {
 		pkg := psr.PackageInfo
 		if pkg.Name() == "stdlib" && pkg.Ecosystem().Ecosystem == osvschema.EcosystemGo {
-			configToUse := scanResults.ConfigManager.Get(pkg.Location())
+			configToUse := scanResults.ConfigManager.Get(scanners.OnDiskPath(pkg.Location()))
 			if configToUse.GoVersionOverride != "" {
 				scanResults.PackageScanResults[i].PackageInfo.Inventory.Version = configToUse.GoVersionOverride
 			}
@@ -0,0 +1,438 @@
+package osvscanner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scanner/v2/internal/config"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/osvdev"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/ecosystemmock"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+func Test_determineReturnErr_FailOnSeverity(t *testing.T) {
+	t.Parallel()
+
+	newResults := func(vulns ...osvschema.Vulnerability) models.VulnerabilityResults {
+		var ids []string
+		for _, v := range vulns {
+			ids = append(ids, v.ID)
+		}
+
+		return models.VulnerabilityResults{
+			Results: []models.PackageSource{
+				{
+					Source: models.SourceInfo{Path: "path/to/package-lock.json", Type: "lockfile"},
+					Packages: []models.PackageVulns{
+						{
+							Package:         models.PackageInfo{Name: "mine", Version: "1.0.0", Ecosystem: "npm"},
+							Vulnerabilities: vulns,
+							Groups:          []models.GroupInfo{{IDs: ids}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	high := osvschema.Vulnerability{
+		ID: "OSV-HIGH",
+		Severity: []osvschema.Severity{
+			{Type: osvschema.SeverityCVSSV3, Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H"},
+		},
+	}
+	moderate := osvschema.Vulnerability{
+		ID:               "OSV-MODERATE",
+		DatabaseSpecific: map[string]any{"severity": "MODERATE"},
+	}
+	unknown := osvschema.Vulnerability{ID: "OSV-UNKNOWN"}
+
+	withEPSS := func(results models.VulnerabilityResults, probability float64) models.VulnerabilityResults {
+		results.Results[0].Packages[0].Groups[0].ExperimentalEPSS = &models.EPSSScore{CVE: "CVE-1", Probability: probability}
+
+		return results
+	}
+
+	tests := []struct {
+		name           string
+		results        models.VulnerabilityResults
+		failOnSeverity float64
+		minEPSS        float64
+		wantErr        error
+	}{
+		{
+			name:           "any vulnerability fails without a threshold",
+			results:        newResults(unknown),
+			failOnSeverity: 0,
+			wantErr:        ErrVulnerabilitiesFound,
+		},
+		{
+			name:           "vulnerability meets the threshold",
+			results:        newResults(high),
+			failOnSeverity: 7.5,
+			wantErr:        ErrVulnerabilitiesFound,
+		},
+		{
+			name:           "vulnerability is below the threshold",
+			results:        newResults(moderate),
+			failOnSeverity: 7,
+			wantErr:        nil,
+		},
+		{
+			name:           "qualitative severity meets the threshold",
+			results:        newResults(moderate),
+			failOnSeverity: 4,
+			wantErr:        ErrVulnerabilitiesFound,
+		},
+		{
+			name:           "vulnerability without a severity does not meet the threshold",
+			results:        newResults(unknown),
+			failOnSeverity: 0.1,
+			wantErr:        nil,
+		},
+		{
+			name:           "one of many vulnerabilities meets the threshold",
+			results:        newResults(unknown, moderate, high),
+			failOnSeverity: 7,
+			wantErr:        ErrVulnerabilitiesFound,
+		},
+		{
+			name:    "vulnerability meets the EPSS threshold",
+			results: withEPSS(newResults(unknown), 0.2),
+			minEPSS: 0.1,
+			wantErr: ErrVulnerabilitiesFound,
+		},
+		{
+			name:    "vulnerability is below the EPSS threshold",
+			results: withEPSS(newResults(high), 0.05),
+			minEPSS: 0.1,
+			wantErr: nil,
+		},
+		{
+			name:    "vulnerability without an EPSS score does not meet the threshold",
+			results: newResults(high),
+			minEPSS: 0.1,
+			wantErr: nil,
+		},
+		{
+			name:           "vulnerability must meet both thresholds",
+			results:        withEPSS(newResults(moderate), 0.5),
+			failOnSeverity: 7,
+			minEPSS:        0.1,
+			wantErr:        nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := determineReturnErr(tt.results, tt.failOnSeverity, tt.minEPSS)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("determineReturnErr() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_applyOSVAPIConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		actions     ExperimentalScannerActions
+		config      config.OSVAPIConfig
+		wantBaseURL string
+		wantHeaders map[string]string
+	}{
+		{
+			name:        "nothing configured",
+			wantBaseURL: "",
+			wantHeaders: nil,
+		},
+		{
+			name: "only config",
+			config: config.OSVAPIConfig{
+				BaseURL: "https://osv.example.com",
+				Headers: map[string]string{"Authorization": "Bearer config"},
+			},
+			wantBaseURL: "https://osv.example.com",
+			wantHeaders: map[string]string{"Authorization": "Bearer config"},
+		},
+		{
+			name: "flags take precedence over config",
+			actions: ExperimentalScannerActions{
+				OSVBaseURL: "https://osv.flag.example.com",
+				OSVHeaders: map[string]string{"Authorization": "Bearer flag"},
+			},
+			config: config.OSVAPIConfig{
+				BaseURL: "https://osv.example.com",
+				Headers: map[string]string{"Authorization": "Bearer config", "X-Team": "security"},
+			},
+			wantBaseURL: "https://osv.flag.example.com",
+			wantHeaders: map[string]string{"Authorization": "Bearer flag", "X-Team": "security"},
+		},
+		{
+			name: "flags take precedence over config regardless of case",
+			actions: ExperimentalScannerActions{
+				OSVHeaders: map[string]string{"authorization": "Bearer flag"},
+			},
+			config: config.OSVAPIConfig{
+				Headers: map[string]string{"AUTHORIZATION": "Bearer config", "x-team": "security"},
+			},
+			wantBaseURL: "",
+			wantHeaders: map[string]string{"Authorization": "Bearer flag", "X-Team": "security"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actions := ScannerActions{ExperimentalScannerActions: tt.actions}
+			applyOSVAPIConfig(&actions, tt.config)
+
+			if actions.OSVBaseURL != tt.wantBaseURL {
+				t.Errorf("OSVBaseURL = %q, want %q", actions.OSVBaseURL, tt.wantBaseURL)
+			}
+			if diff := cmp.Diff(tt.wantHeaders, actions.OSVHeaders); diff != "" {
+				t.Errorf("OSVHeaders mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_newOSVClient(t *testing.T) {
+	t.Parallel()
+
+	client := newOSVClient(ScannerActions{})
+	if client.BaseHostURL != osvdev.DefaultBaseURL {
+		t.Errorf("BaseHostURL = %q, want %q", client.BaseHostURL, osvdev.DefaultBaseURL)
+	}
+
+	client = newOSVClient(ScannerActions{
+		ExperimentalScannerActions: ExperimentalScannerActions{
+			OSVBaseURL: "https://osv.example.com/",
+			OSVHeaders: map[string]string{"Authorization": "Bearer secret"},
+		},
+	})
+	if client.BaseHostURL != "https://osv.example.com" {
+		t.Errorf("BaseHostURL = %q, want %q", client.BaseHostURL, "https://osv.example.com")
+	}
+	if client.Config.Headers["Authorization"] != "Bearer secret" {
+		t.Errorf("expected the Authorization header to be set, got %v", client.Config.Headers)
+	}
+
+	client = newOSVClient(ScannerActions{
+		ExperimentalScannerActions: ExperimentalScannerActions{
+			OSVBatchSize:            250,
+			OSVMaxConcurrentBatches: 2,
+		},
+	})
+	if client.Config.MaxQueriesPerBatchRequest != 250 {
+		t.Errorf("MaxQueriesPerBatchRequest = %d, want %d", client.Config.MaxQueriesPerBatchRequest, 250)
+	}
+	if client.Config.MaxConcurrentBatchRequests != 2 {
+		t.Errorf("MaxConcurrentBatchRequests = %d, want %d", client.Config.MaxConcurrentBatchRequests, 2)
+	}
+}
+
+func Test_scan_SBOMDirectories(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		actions ScannerActions
+		want    []string
+	}{
+		{
+			name:    "sbom paths that are directories are searched for sboms",
+			actions: ScannerActions{SBOMPaths: []string{"./fixtures/sboms"}},
+			want: []string{
+				"fixtures/sboms/service-a/bom.cdx.json: lodash@4.17.20",
+				"fixtures/sboms/service-b/app.spdx.json: lodash@4.17.20",
+			},
+		},
+		{
+			name:    "directories are scanned for both sboms and lockfiles by default",
+			actions: ScannerActions{DirectoryPaths: []string{"./fixtures/sboms"}, Recursive: true},
+			want: []string{
+				"fixtures/sboms/service-a/bom.cdx.json: lodash@4.17.20",
+				"fixtures/sboms/service-b/app.spdx.json: lodash@4.17.20",
+				"fixtures/sboms/service-b/package-lock.json: minimist@1.2.5",
+			},
+		},
+		{
+			name:    "directories are only scanned for sboms with SBOMOnly",
+			actions: ScannerActions{DirectoryPaths: []string{"./fixtures/sboms"}, Recursive: true, SBOMOnly: true},
+			want: []string{
+				"fixtures/sboms/service-a/bom.cdx.json: lodash@4.17.20",
+				"fixtures/sboms/service-b/app.spdx.json: lodash@4.17.20",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			wd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pkgs, _, err := scan(context.Background(), ExternalAccessors{}, tt.actions)
+			if err != nil {
+				t.Fatalf("scan() error = %v", err)
+			}
+
+			got := make([]string, 0, len(pkgs))
+			for _, pkg := range pkgs {
+				location, err := filepath.Rel(wd, pkg.PackageInfo.Location())
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, filepath.ToSlash(location)+": "+pkg.PackageInfo.Name()+"@"+pkg.PackageInfo.Version())
+			}
+			slices.Sort(got)
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("scan() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_scan_UnparseableFiles(t *testing.T) {
+	t.Parallel()
+
+	actions := ScannerActions{DirectoryPaths: []string{"./fixtures/unparseable"}, Recursive: true}
+
+	pkgs, fileErrs, err := scan(context.Background(), ExternalAccessors{}, actions)
+	if err != nil {
+		t.Fatalf("scan() error = %v", err)
+	}
+
+	if len(pkgs) != 1 || pkgs[0].PackageInfo.Name() != "minimist" {
+		t.Errorf("scan() packages = %v, want only minimist", pkgs)
+	}
+
+	if len(fileErrs) != 1 || filepath.ToSlash(filepath.Base(filepath.Dir(fileErrs[0].Path))) != "invalid" {
+		t.Fatalf("scan() file errors = %v, want one for the invalid lockfile", fileErrs)
+	}
+
+	actions.Strict = true
+	_, _, err = scan(context.Background(), ExternalAccessors{}, actions)
+	var extractionErr *scalibrextract.ExtractionError
+	if !errors.As(err, &extractionErr) {
+		t.Errorf("scan() with Strict error = %v, want %v", err, fileErrs[0].Err)
+	}
+}
+
+func Test_scan_PythonEnvs(t *testing.T) {
+	t.Parallel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, env := range []string{"./fixtures/python-env/venv", "./fixtures/python-env/venv/lib/python3.12/site-packages"} {
+		pkgs, _, err := scan(context.Background(), ExternalAccessors{}, ScannerActions{PythonEnvPaths: []string{env}})
+		if err != nil {
+			t.Fatalf("scan(%s) error = %v", env, err)
+		}
+
+		got := make([]string, 0, len(pkgs))
+		for _, pkg := range pkgs {
+			location, err := filepath.Rel(wd, pkg.PackageInfo.Location())
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, filepath.ToSlash(location)+": "+pkg.PackageInfo.Name()+"@"+pkg.PackageInfo.Version()+" "+filepath.ToSlash(pkg.PackageInfo.EditableSource()))
+		}
+		slices.Sort(got)
+
+		want := []string{
+			"fixtures/python-env/venv/lib/python3.12/site-packages/Flask-2.2.2.dist-info/METADATA: flask@2.2.2 ",
+			"fixtures/python-env/venv/lib/python3.12/site-packages/myapp-0.1.0.dist-info/METADATA: myapp@0.1.0 /src/myapp",
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("scan(%s) mismatch (-want +got):\n%s", env, diff)
+		}
+	}
+
+	_, _, err = scan(context.Background(), ExternalAccessors{}, ScannerActions{PythonEnvPaths: []string{"./fixtures/sboms"}})
+	if err == nil || !strings.Contains(err.Error(), "could not find a site-packages directory") {
+		t.Errorf("scan() error = %v, want it to not find site-packages", err)
+	}
+}
+
+// allVulnsMatcherStub matches each package against all of the vulnerabilities in vulns
+type allVulnsMatcherStub struct {
+	vulns []*osvschema.Vulnerability
+}
+
+func (m allVulnsMatcherStub) MatchVulnerabilities(_ context.Context, invs []*extractor.Inventory) ([][]*osvschema.Vulnerability, error) {
+	return make([][]*osvschema.Vulnerability, len(invs)), nil
+}
+
+func (m allVulnsMatcherStub) MatchAllVulnerabilities(_ context.Context, invs []*extractor.Inventory) ([][]*osvschema.Vulnerability, error) {
+	res := make([][]*osvschema.Vulnerability, len(invs))
+	for i := range invs {
+		res[i] = m.vulns
+	}
+
+	return res, nil
+}
+
+func Test_matchUnaffectedVulns(t *testing.T) {
+	t.Parallel()
+
+	affecting := &osvschema.Vulnerability{ID: "OSV-1"}
+	unaffecting := &osvschema.Vulnerability{ID: "OSV-2"}
+	withdrawn := &osvschema.Vulnerability{ID: "OSV-3", Withdrawn: time.Now().Add(-time.Hour)}
+
+	newPackages := func() []imodels.PackageScanResult {
+		return []imodels.PackageScanResult{
+			{
+				PackageInfo: imodels.FromInventory(&extractor.Inventory{
+					Name:      "lodash",
+					Version:   "4.17.21",
+					Extractor: ecosystemmock.Extractor{MockEcosystem: "npm"},
+				}),
+				Vulnerabilities: []*osvschema.Vulnerability{affecting},
+			},
+		}
+	}
+	matcher := allVulnsMatcherStub{vulns: []*osvschema.Vulnerability{affecting, unaffecting, withdrawn}}
+
+	packages := newPackages()
+	if err := matchUnaffectedVulns(context.Background(), packages, matcher, false); err != nil {
+		t.Fatalf("matchUnaffectedVulns() error = %v", err)
+	}
+	if diff := cmp.Diff([]*osvschema.Vulnerability{unaffecting}, packages[0].UnaffectedVulnerabilities); diff != "" {
+		t.Errorf("matchUnaffectedVulns() mismatch (-want +got):\n%s", diff)
+	}
+
+	packages = newPackages()
+	if err := matchUnaffectedVulns(context.Background(), packages, matcher, true); err != nil {
+		t.Fatalf("matchUnaffectedVulns() error = %v", err)
+	}
+	if diff := cmp.Diff([]*osvschema.Vulnerability{unaffecting, withdrawn}, packages[0].UnaffectedVulnerabilities); diff != "" {
+		t.Errorf("matchUnaffectedVulns() with withdrawn mismatch (-want +got):\n%s", diff)
+	}
+
+	// matchers that query the OSV API cannot list all of the vulnerabilities of a package
+	if err := matchUnaffectedVulns(context.Background(), newPackages(), offlineMatcher{}, false); err == nil {
+		t.Errorf("matchUnaffectedVulns() error = nil, want an error")
+	}
+}
@@ -1,55 +1,210 @@
 package osvscanner
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"os"
+	"slices"
 
 	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
 	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/ecosystemmock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/python/sitepackages"
 	"github.com/google/osv-scanner/v2/pkg/osvscanner/internal/scanners"
 )
 
 // scan essentially converts ScannerActions into PackageScanResult by performing the extractions
-func scan(accessors ExternalAccessors, actions ScannerActions) ([]imodels.PackageScanResult, error) {
+//
+// Files that fail to be extracted are returned as FileErrors so that the rest of the files can
+// still be scanned, unless the scan is strict in which case the first such error is returned.
+// The error of ctx is returned if it is cancelled before all of the files have been extracted.
+func scan(ctx context.Context, accessors ExternalAccessors, actions ScannerActions) ([]imodels.PackageScanResult, []scanners.FileError, error) {
 	//nolint:prealloc // We don't know how many inventories we will retrieve
 	var scannedInventories []*extractor.Inventory
+	var fileErrs []scanners.FileError
+
+	// addFileErrors records the errors of files that could not be extracted,
+	// returning the first one if the scan is strict
+	addFileErrors := func(errs ...scanners.FileError) error {
+		if actions.Strict && len(errs) > 0 {
+			return errs[0].Err
+		}
+		fileErrs = append(fileErrs, errs...)
+
+		return nil
+	}
 
 	// --- Lockfiles ---
-	lockfileExtractors := scanners.BuildLockfileExtractors(accessors.DependencyClients, accessors.MavenRegistryAPIClient)
-	for _, lockfileElem := range actions.LockfilePaths {
-		invs, err := scanners.ScanSingleFileWithMapping(lockfileElem, lockfileExtractors)
+	lockfileExtractors := withRegisteredExtractors(scanners.BuildLockfileExtractors(accessors.DependencyClients, accessors.MavenRegistryAPIClient))
+	for _, lockfileArg := range actions.LockfilePaths {
+		lockfileElems, err := scanners.ExpandLockfileGlob(lockfileArg)
 		if err != nil {
-			return nil, err
+			return nil, fileErrs, err
+		}
+		if len(lockfileElems) == 0 {
+			slog.Warn(fmt.Sprintf("No lockfiles matched %s", lockfileArg))
+			continue
 		}
 
-		scannedInventories = append(scannedInventories, invs...)
+		for _, lockfileElem := range lockfileElems {
+			invs, err := scanners.ScanSingleFileWithMapping(ctx, lockfileElem, lockfileExtractors)
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, fileErrs, ctxErr
+			}
+			if err != nil {
+				var extractionErr *scalibrextract.ExtractionError
+				if !errors.As(err, &extractionErr) {
+					return nil, fileErrs, err
+				}
+				if err := addFileErrors(scanners.FileError{Path: lockfileElem, Err: err}); err != nil {
+					return nil, fileErrs, err
+				}
+			}
+
+			scannedInventories = append(scannedInventories, invs...)
+		}
 	}
 
 	// --- SBOMs ---
-	sbomExtractors := scanners.BuildSBOMExtractors()
+	sbomExtractors := withRegisteredExtractors(scanners.BuildSBOMExtractors())
 	for _, sbomPath := range actions.SBOMPaths {
-		invs, err := scanners.ScanSingleFile(sbomPath, sbomExtractors)
+		var invs []*extractor.Inventory
+		var err error
+
+		// directories are searched for SBOMs, which must follow the naming of the relevant spec
+		if info, statErr := os.Stat(sbomPath); statErr == nil && info.IsDir() {
+			slog.Info("Scanning dir " + sbomPath + " for SBOMs")
+			var dirFileErrs []scanners.FileError
+			invs, dirFileErrs, err = scanners.ScanDir(ctx, sbomPath, true, !actions.NoIgnore, actions.SkipDirs, actions.Jobs, sbomExtractors, nil)
+			if err == nil {
+				err = addFileErrors(dirFileErrs...)
+			}
+		} else {
+			invs, err = scanners.ScanSingleFile(ctx, sbomPath, sbomExtractors)
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, fileErrs, ctxErr
+			}
+			var extractionErr *scalibrextract.ExtractionError
+			if errors.As(err, &extractionErr) {
+				err = addFileErrors(scanners.FileError{Path: sbomPath, Err: err})
+			}
+		}
+		if err != nil {
+			return nil, fileErrs, err
+		}
+
+		scannedInventories = append(scannedInventories, invs...)
+	}
+
+	// --- Archives ---
+	// the registered extractors are only added once, rather than with both the lockfile and SBOM extractors
+	archiveExtractors := withRegisteredExtractors(slices.Concat(
+		scanners.BuildLockfileExtractors(accessors.DependencyClients, accessors.MavenRegistryAPIClient),
+		scanners.BuildSBOMExtractors(),
+	))
+	for _, archivePath := range actions.ArchivePaths {
+		slog.Info("Scanning archive " + archivePath)
+		invs, archiveFileErrs, err := scanners.ScanArchive(ctx, archivePath, archiveExtractors)
+		if err == nil {
+			err = addFileErrors(archiveFileErrs...)
+		}
 		if err != nil {
-			return nil, err
+			return nil, fileErrs, err
 		}
 
 		scannedInventories = append(scannedInventories, invs...)
 	}
 
 	// --- Directories ---
-	dirExtractors := scanners.BuildWalkerExtractors(
-		actions.IncludeGitRoot,
-		accessors.OSVDevClient,
-		accessors.DependencyClients,
-		accessors.MavenRegistryAPIClient,
-	)
+	dirExtractors := sbomExtractors
+	if !actions.SBOMOnly {
+		dirExtractors = withRegisteredExtractors(scanners.BuildWalkerExtractors(
+			actions.IncludeGitRoot,
+			accessors.OSVDevClient,
+			accessors.DependencyClients,
+			accessors.MavenRegistryAPIClient,
+		))
+	}
+	var inventoryCache *scanners.InventoryCache
+	if actions.Incremental && len(actions.DirectoryPaths) > 0 {
+		var err error
+		inventoryCache, err = newInventoryCache(actions)
+		if err != nil {
+			return nil, fileErrs, err
+		}
+	}
 	for _, dir := range actions.DirectoryPaths {
-		slog.Info("Scanning dir " + dir)
-		pkgs, err := scanners.ScanDir(dir, actions.Recursive, !actions.NoIgnore, dirExtractors)
+		dirsToScan, recursive := []string{dir}, actions.Recursive
+
+		// only the directories with changes are scanned, which are not walked into
+		// as their subdirectories will have been included if they have changed too
+		if actions.ChangedSince != "" {
+			changed, err := scanners.ChangedDirs(dir, actions.ChangedSince, actions.Recursive, actions.SkipDirs)
+			if err != nil {
+				return nil, fileErrs, err
+			}
+
+			slog.Info(fmt.Sprintf(
+				"Found %d %s with changes since %s in %s",
+				len(changed),
+				output.Form(len(changed), "directory", "directories"),
+				actions.ChangedSince,
+				dir,
+			))
+
+			dirsToScan, recursive = changed, false
+		}
+
+		for _, dir := range dirsToScan {
+			slog.Info("Scanning dir " + dir)
+			pkgs, dirFileErrs, err := scanners.ScanDir(ctx, dir, recursive, !actions.NoIgnore, actions.SkipDirs, actions.Jobs, dirExtractors, inventoryCache)
+			if err == nil {
+				err = addFileErrors(dirFileErrs...)
+			}
+			if err != nil {
+				return nil, fileErrs, err
+			}
+			scannedInventories = append(scannedInventories, pkgs...)
+		}
+	}
+	if inventoryCache != nil {
+		reused := inventoryCache.Reused()
+		slog.Info(fmt.Sprintf(
+			"Reused the packages previously extracted from %d unchanged %s",
+			reused,
+			output.Form(reused, "file", "files"),
+		))
+
+		if err := inventoryCache.Write(); err != nil {
+			slog.Warn(fmt.Sprintf("Failed to cache extracted packages at %s: %v", inventoryCache.CachePath, err))
+		}
+	}
+
+	// --- Python environments ---
+	// the whole of site-packages is walked regardless of any ignore files, as it is installed rather than source code
+	pythonEnvExtractors := []filesystem.Extractor{sitepackages.Extractor{}}
+	for _, env := range actions.PythonEnvPaths {
+		sitePackagesDirs, err := scanners.SitePackagesDirs(env)
 		if err != nil {
-			return nil, err
+			return nil, fileErrs, err
+		}
+
+		for _, dir := range sitePackagesDirs {
+			slog.Info("Scanning Python packages installed in " + dir)
+			pkgs, dirFileErrs, err := scanners.ScanDir(ctx, dir, true, false, nil, actions.Jobs, pythonEnvExtractors, nil)
+			if err == nil {
+				err = addFileErrors(dirFileErrs...)
+			}
+			if err != nil {
+				return nil, fileErrs, err
+			}
+			scannedInventories = append(scannedInventories, pkgs...)
 		}
-		scannedInventories = append(scannedInventories, pkgs...)
 	}
 
 	// Add on additional direct dependencies passed straight from ScannerActions:
@@ -63,7 +218,7 @@ func scan(accessors ExternalAccessors, actions ScannerActions) ([]imodels.Packag
 	}
 
 	if len(scannedInventories) == 0 {
-		return nil, ErrNoPackagesFound
+		return nil, fileErrs, ErrNoPackagesFound
 	}
 
 	// Convert to imodels.PackageScanResult for use in the rest of osv-scanner
@@ -76,5 +231,5 @@ func scan(accessors ExternalAccessors, actions ScannerActions) ([]imodels.Packag
 		})
 	}
 
-	return packages, nil
+	return packages, fileErrs, nil
 }
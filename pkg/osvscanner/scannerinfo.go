@@ -0,0 +1,40 @@
+package osvscanner
+
+import (
+	"time"
+
+	"github.com/google/osv-scanner/v2/internal/clients/clientinterfaces"
+	"github.com/google/osv-scanner/v2/internal/imodels/results"
+	"github.com/google/osv-scanner/v2/internal/version"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+// databaseTimestamper is implemented by vulnerability matchers that match
+// against a copy of the OSV database, rather than querying it live
+type databaseTimestamper interface {
+	DatabaseTimestamp() time.Time
+}
+
+// buildScannerInfo describes the scan that started at start, which matched the packages of scanResult
+// against matcher, so that consumers of the results know where they came from and how fresh they are
+func buildScannerInfo(start time.Time, scanResult *results.ScanResults, matcher clientinterfaces.VulnerabilityMatcher) *models.ScannerInfo {
+	sources := make(map[string]struct{})
+	for _, psr := range scanResult.PackageScanResults {
+		sources[psr.PackageInfo.Location()] = struct{}{}
+	}
+
+	info := &models.ScannerInfo{
+		Version:         version.OSVVersion,
+		PackagesScanned: len(scanResult.PackageScanResults),
+		SourcesScanned:  len(sources),
+		ElapsedSeconds:  time.Since(start).Seconds(),
+	}
+
+	if m, ok := matcher.(databaseTimestamper); ok {
+		if timestamp := m.DatabaseTimestamp(); !timestamp.IsZero() {
+			info.OSVDatabaseTimestamp = &timestamp
+		}
+	}
+
+	return info
+}
@@ -0,0 +1,79 @@
+package osvscanner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/imodels/results"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/ecosystemmock"
+	"github.com/google/osv-scanner/v2/internal/version"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// offlineMatcher is a matcher that claims to match against a database last modified at timestamp
+type offlineMatcher struct {
+	timestamp time.Time
+}
+
+func (m offlineMatcher) MatchVulnerabilities(_ context.Context, invs []*extractor.Inventory) ([][]*osvschema.Vulnerability, error) {
+	return make([][]*osvschema.Vulnerability, len(invs)), nil
+}
+
+func (m offlineMatcher) DatabaseTimestamp() time.Time {
+	return m.timestamp
+}
+
+func Test_buildScannerInfo(t *testing.T) {
+	t.Parallel()
+
+	newResult := func(name, location string) imodels.PackageScanResult {
+		return imodels.PackageScanResult{
+			PackageInfo: imodels.FromInventory(&extractor.Inventory{
+				Name:      name,
+				Version:   "1.0.0",
+				Locations: []string{location},
+				Extractor: ecosystemmock.Extractor{MockEcosystem: "npm"},
+			}),
+		}
+	}
+
+	scanResult := &results.ScanResults{
+		PackageScanResults: []imodels.PackageScanResult{
+			newResult("lodash", "a/package-lock.json"),
+			newResult("minimist", "a/package-lock.json"),
+			newResult("lodash", "b/package-lock.json"),
+		},
+	}
+
+	timestamp := time.Date(2025, time.March, 4, 12, 30, 0, 0, time.UTC)
+	start := time.Now().Add(-2 * time.Second)
+
+	info := buildScannerInfo(start, scanResult, offlineMatcher{timestamp: timestamp})
+
+	if info.Version != version.OSVVersion {
+		t.Errorf("Version = %q, want %q", info.Version, version.OSVVersion)
+	}
+	if info.PackagesScanned != 3 {
+		t.Errorf("PackagesScanned = %d, want 3", info.PackagesScanned)
+	}
+	if info.SourcesScanned != 2 {
+		t.Errorf("SourcesScanned = %d, want 2", info.SourcesScanned)
+	}
+	if info.ElapsedSeconds < 2 {
+		t.Errorf("ElapsedSeconds = %f, want at least 2", info.ElapsedSeconds)
+	}
+	if info.OSVDatabaseTimestamp == nil || !info.OSVDatabaseTimestamp.Equal(timestamp) {
+		t.Errorf("OSVDatabaseTimestamp = %v, want %v", info.OSVDatabaseTimestamp, timestamp)
+	}
+
+	// matchers that query the OSV API do not have a database timestamp
+	if info := buildScannerInfo(start, scanResult, nil); info.OSVDatabaseTimestamp != nil {
+		t.Errorf("OSVDatabaseTimestamp = %v, want nil", info.OSVDatabaseTimestamp)
+	}
+	if info := buildScannerInfo(start, scanResult, offlineMatcher{}); info.OSVDatabaseTimestamp != nil {
+		t.Errorf("OSVDatabaseTimestamp = %v, want nil when no databases were loaded", info.OSVDatabaseTimestamp)
+	}
+}
@@ -1,6 +1,8 @@
 package osvscanner
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
 	"log/slog"
 	"path/filepath"
@@ -9,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scanner/v2/internal/depgraph"
 	"github.com/google/osv-scanner/v2/internal/grouper"
 	"github.com/google/osv-scanner/v2/internal/imodels"
 	"github.com/google/osv-scanner/v2/internal/imodels/results"
@@ -16,16 +19,20 @@ import (
 	"github.com/google/osv-scanner/v2/internal/sourceanalysis"
 	"github.com/google/osv-scanner/v2/internal/spdx"
 	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/google/osv-scanner/v2/pkg/osvscanner/internal/scanners"
 	"github.com/ossf/osv-schema/bindings/go/osvschema"
 )
 
 // buildVulnerabilityResults takes the responses from the OSV API and the deps.dev API
 // and converts this into a VulnerabilityResults. As part is this, it groups
 // vulnerability information by source location.
+//
+// sourceScan is false for container scans, whose packages are not located on disk.
 // TODO: This function is getting long, we should refactor it
 func buildVulnerabilityResults(
 	actions ScannerActions,
 	scanResults *results.ScanResults,
+	sourceScan bool,
 ) models.VulnerabilityResults {
 	results := models.VulnerabilityResults{
 		Results:       []models.PackageSource{},
@@ -38,6 +45,7 @@ func buildVulnerabilityResults(
 	}
 
 	groupedBySource := map[models.SourceInfo]*packageVulnsGroup{}
+	graphs := map[string]*depgraph.Graph{}
 	for _, psr := range scanResults.PackageScanResults {
 		p := psr.PackageInfo
 		includePackage := actions.ShowAllPackages
@@ -63,7 +71,10 @@ func buildVulnerabilityResults(
 			}
 		}
 		pkg.DepGroups = p.DepGroups()
-		configToUse := scanResults.ConfigManager.Get(p.Location())
+		pkg.CPEs = p.CPEs()
+		pkg.EditableSource = p.EditableSource()
+		pkg.ExperimentalDirectnessUnknown = psr.DirectnessUnknown
+		configToUse := scanResults.ConfigManager.Get(scanners.OnDiskPath(p.Location()))
 
 		if len(psr.Vulnerabilities) > 0 {
 			if !configToUse.ShouldIgnorePackageVulnerabilities(p) {
@@ -74,7 +85,18 @@ func buildVulnerabilityResults(
 				pkg.Groups = grouper.Group(grouper.ConvertVulnerabilityToIDAliases(pkg.Vulnerabilities))
 				for i, group := range pkg.Groups {
 					pkg.Groups[i].MaxSeverity = output.MaxSeverity(group, pkg)
+					pkg.Groups[i].FixedVersion = output.FixedVersion(group, pkg)
 				}
+				if actions.ShowDependencyPaths {
+					pkg.DependencyPath = dependencyPath(graphs, p)
+				}
+			}
+		}
+
+		if len(psr.UnaffectedVulnerabilities) > 0 && !configToUse.ShouldIgnorePackageVulnerabilities(p) {
+			includePackage = true
+			for _, vuln := range psr.UnaffectedVulnerabilities {
+				pkg.ExperimentalUnaffectedVulnerabilities = append(pkg.ExperimentalUnaffectedVulnerabilities, *vuln)
 			}
 		}
 
@@ -93,7 +115,7 @@ func buildVulnerabilityResults(
 				} else {
 					overrideLicenses := make([]models.License, len(entry.License.Override))
 					for j, license := range entry.License.Override {
-						overrideLicenses[j] = models.License(license)
+						overrideLicenses[j] = spdx.Normalize(models.License(license))
 					}
 					slog.Info(fmt.Sprintf("overriding license for package %s/%s/%s with %s", pkg.Package.Ecosystem, pkg.Package.Name, pkg.Package.Version, strings.Join(entry.License.Override, ",")))
 					psr.Licenses = overrideLicenses
@@ -102,6 +124,10 @@ func buildVulnerabilityResults(
 			if len(actions.ScanLicensesAllowlist) > 0 {
 				pkg.Licenses = psr.Licenses
 				for _, license := range pkg.Licenses {
+					if license == "UNKNOWN" && actions.ScanLicensesAllowUnknown {
+						continue
+					}
+
 					satisfies, err := spdx.Satisfies(license, actions.ScanLicensesAllowlist)
 
 					if err != nil {
@@ -134,6 +160,8 @@ func buildVulnerabilityResults(
 				sourceType = "sbom"
 			case imodels.SourceTypeGit:
 				sourceType = "git"
+			case imodels.SourceTypeManifest:
+				sourceType = "manifest"
 			case imodels.SourceTypeUnknown:
 				sourceType = "unknown"
 			default:
@@ -156,7 +184,7 @@ func buildVulnerabilityResults(
 
 	// TODO(v2): Move source analysis out of here.
 	for source, packages := range groupedBySource {
-		sourceanalysis.Run(source, packages.pvs, actions.CallAnalysisStates)
+		sourceanalysis.Run(source, packages.pvs, actions.CallAnalysisStates, sourceScan)
 		results.Results = append(results.Results, models.PackageSource{
 			Source:                  source,
 			ExperimentalAnnotations: packages.annotations,
@@ -184,6 +212,80 @@ func buildVulnerabilityResults(
 	return results
 }
 
+// sortResults sorts the sources, packages and vulnerabilities of the results (along with the aliases and
+// affected packages of each vulnerability), so that the output of scans of the same packages is identical
+// regardless of the order in which they were extracted and matched
+func sortResults(results *models.VulnerabilityResults) {
+	slices.SortStableFunc(results.Results, func(a, b models.PackageSource) int {
+		return cmp.Or(
+			cmp.Compare(a.Source.Path, b.Source.Path),
+			cmp.Compare(a.Source.Type, b.Source.Type),
+		)
+	})
+
+	for i := range results.Results {
+		packages := results.Results[i].Packages
+		slices.SortStableFunc(packages, func(a, b models.PackageVulns) int {
+			return cmp.Or(
+				cmp.Compare(a.Package.Ecosystem, b.Package.Ecosystem),
+				cmp.Compare(a.Package.Name, b.Package.Name),
+				cmp.Compare(a.Package.Version, b.Package.Version),
+				cmp.Compare(a.Package.Commit, b.Package.Commit),
+			)
+		})
+
+		for j := range packages {
+			pkg := &packages[j]
+
+			sortVulnerabilities(pkg.Vulnerabilities)
+			sortVulnerabilities(pkg.ExperimentalUnaffectedVulnerabilities)
+
+			slices.SortStableFunc(pkg.ExperimentalIgnoredVulnerabilities, func(a, b models.IgnoredVulnerability) int {
+				return cmp.Compare(a.Vulnerability.ID, b.Vulnerability.ID)
+			})
+			for k := range pkg.ExperimentalIgnoredVulnerabilities {
+				sortVulnerability(&pkg.ExperimentalIgnoredVulnerabilities[k].Vulnerability)
+			}
+
+			// the IDs of each group are already sorted with the canonical ID first
+			slices.SortStableFunc(pkg.Groups, func(a, b models.GroupInfo) int {
+				return slices.Compare(a.IDs, b.IDs)
+			})
+		}
+	}
+}
+
+// sortVulnerabilities sorts the vulnerabilities by their ID, along with the aliases
+// and affected packages of each vulnerability
+func sortVulnerabilities(vs []osvschema.Vulnerability) {
+	slices.SortStableFunc(vs, func(a, b osvschema.Vulnerability) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	for i := range vs {
+		sortVulnerability(&vs[i])
+	}
+}
+
+// sortVulnerability sorts the aliases and affected packages of the vulnerability, which are
+// copied first as they can be shared with the vulnerabilities of other packages
+func sortVulnerability(v *osvschema.Vulnerability) {
+	if len(v.Aliases) > 1 {
+		v.Aliases = slices.Sorted(slices.Values(v.Aliases))
+	}
+
+	if len(v.Affected) > 1 {
+		v.Affected = slices.Clone(v.Affected)
+		slices.SortStableFunc(v.Affected, func(a, b osvschema.Affected) int {
+			return cmp.Or(
+				cmp.Compare(a.Package.Ecosystem, b.Package.Ecosystem),
+				cmp.Compare(a.Package.Name, b.Package.Name),
+				cmp.Compare(a.Package.Purl, b.Package.Purl),
+			)
+		})
+	}
+}
+
 // setUnimportant marks vulnerabilities in a PackageVulns as unimportant
 // within their respective groups' experimental analysis.
 func setUnimportant(pkg *models.PackageVulns) {
@@ -226,3 +328,45 @@ func isUnimportant(affectedPackages []osvschema.Affected) bool {
 
 	return false
 }
+
+// dependencyGraph returns the graph of the lockfile that the package was extracted from,
+// or nil if it does not record one. The graph of each lockfile is only read once.
+func dependencyGraph(graphs map[string]*depgraph.Graph, p imodels.PackageInfo) *depgraph.Graph {
+	if p.SourceType() != imodels.SourceTypeProjectPackage {
+		return nil
+	}
+
+	g, ok := graphs[p.Location()]
+	if !ok {
+		var err error
+		g, err = depgraph.Parse(p.Location())
+		if err != nil && !errors.Is(err, depgraph.ErrUnsupported) {
+			slog.Debug(fmt.Sprintf("Could not read the dependency graph of %s: %v", p.Location(), err))
+		}
+		graphs[p.Location()] = g
+	}
+
+	return g
+}
+
+// dependencyPath returns the path through which the package is depended on, if the
+// lockfile it was extracted from records the graph of its dependencies and the package
+// is depended on transitively.
+func dependencyPath(graphs map[string]*depgraph.Graph, p imodels.PackageInfo) []string {
+	g := dependencyGraph(graphs, p)
+	if g == nil {
+		return nil
+	}
+
+	path := g.ShortestPath(depgraph.Package{Name: p.Name(), Version: p.Version()})
+	if len(path) < 2 {
+		return nil
+	}
+
+	names := make([]string, 0, len(path))
+	for _, pkg := range path {
+		names = append(names, pkg.String())
+	}
+
+	return names
+}
@@ -3,8 +3,10 @@ package osvscanner
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/osv-scalibr/extractor"
 	"github.com/google/osv-scanner/v2/internal/config"
+	"github.com/google/osv-scanner/v2/internal/depgraph"
 	"github.com/google/osv-scanner/v2/internal/imodels"
 	"github.com/google/osv-scanner/v2/internal/imodels/results"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/ecosystemmock"
@@ -175,6 +177,19 @@ func Test_assembleResult(t *testing.T) {
 					},
 				},
 			},
+		}, {
+			name: "group_vulnerabilities_with_license_allowlist_allowing_unknown",
+			args: args{
+				scanResults: makeScanResults(),
+				actions: ScannerActions{
+					ExperimentalScannerActions: ExperimentalScannerActions{
+						ShowAllPackages:          false,
+						ScanLicensesAllowlist:    []string{"MIT", "0BSD"},
+						ScanLicensesAllowUnknown: true,
+					},
+					CallAnalysisStates: callAnalysisStates,
+				},
+			},
 		}, {
 			name: "group_vulnerabilities_with_license_allowlist_and_all_packages",
 			args: args{
@@ -193,8 +208,149 @@ func Test_assembleResult(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			tt.args.scanResults.ConfigManager = tt.args.config
-			got := buildVulnerabilityResults(tt.args.actions, tt.args.scanResults)
+			got := buildVulnerabilityResults(tt.args.actions, tt.args.scanResults, true)
 			testutility.NewSnapshot().MatchJSON(t, got)
 		})
 	}
 }
+
+func Test_dependencyPath(t *testing.T) {
+	t.Parallel()
+
+	newPackage := func(name, version, location string) imodels.PackageInfo {
+		return imodels.PackageInfo{
+			Inventory: &extractor.Inventory{
+				Name:      name,
+				Version:   version,
+				Extractor: ecosystemmock.Extractor{MockEcosystem: "npm"},
+				Locations: []string{location},
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		pkg  imodels.PackageInfo
+		want []string
+	}{
+		{
+			name: "transitive dependency",
+			pkg:  newPackage("qs", "6.11.0", "fixtures/dependency-paths/package-lock.json"),
+			want: []string{"body-parser@1.20.2", "qs@6.11.0"},
+		},
+		{
+			name: "direct dependency",
+			pkg:  newPackage("express", "4.18.2", "fixtures/dependency-paths/package-lock.json"),
+			want: nil,
+		},
+		{
+			name: "lockfile without a graph",
+			pkg:  newPackage("requests", "2.31.0", "fixtures/dependency-paths/requirements.txt"),
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := dependencyPath(map[string]*depgraph.Graph{}, tt.pkg)
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("dependencyPath() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_sortResults(t *testing.T) {
+	t.Parallel()
+
+	affected := func(ecosystem string, name string) osvschema.Affected {
+		return osvschema.Affected{Package: osvschema.Package{Ecosystem: ecosystem, Name: name}}
+	}
+
+	lodashAliases := []string{"GHSA-2", "CVE-2"}
+	results := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "b/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{
+					{Package: models.PackageInfo{Name: "minimist", Version: "1.2.5", Ecosystem: "npm"}},
+					{
+						Package: models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+						Vulnerabilities: []osvschema.Vulnerability{
+							{ID: "GHSA-3"},
+							{
+								ID:       "GHSA-1",
+								Aliases:  lodashAliases,
+								Affected: []osvschema.Affected{affected("npm", "lodash"), affected("npm", "lodash-es")},
+							},
+						},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"GHSA-3"}},
+							{IDs: []string{"GHSA-1"}},
+						},
+						ExperimentalIgnoredVulnerabilities: []models.IgnoredVulnerability{
+							{Vulnerability: osvschema.Vulnerability{ID: "GHSA-5"}},
+							{Vulnerability: osvschema.Vulnerability{ID: "GHSA-4"}},
+						},
+					},
+					{Package: models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "Go"}},
+				},
+			},
+			{
+				Source:   models.SourceInfo{Path: "a/package-lock.json", Type: "lockfile"},
+				Packages: []models.PackageVulns{},
+			},
+		},
+	}
+
+	sortResults(&results)
+
+	var got []string
+	for _, source := range results.Results {
+		got = append(got, source.Source.Path)
+		for _, pkg := range source.Packages {
+			got = append(got, pkg.Package.Ecosystem+"/"+pkg.Package.Name)
+			for _, vuln := range pkg.Vulnerabilities {
+				got = append(got, vuln.ID)
+			}
+			for _, group := range pkg.Groups {
+				got = append(got, "group "+group.IDs[0])
+			}
+			for _, ignored := range pkg.ExperimentalIgnoredVulnerabilities {
+				got = append(got, "ignored "+ignored.Vulnerability.ID)
+			}
+		}
+	}
+
+	want := []string{
+		"a/package-lock.json",
+		"b/package-lock.json",
+		"Go/lodash",
+		"npm/lodash",
+		"GHSA-1",
+		"GHSA-3",
+		"group GHSA-1",
+		"group GHSA-3",
+		"ignored GHSA-4",
+		"ignored GHSA-5",
+		"npm/minimist",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("sortResults() mismatch (-want +got):\n%s", diff)
+	}
+
+	vuln := results.Results[1].Packages[1].Vulnerabilities[0]
+	if diff := cmp.Diff([]string{"CVE-2", "GHSA-2"}, vuln.Aliases); diff != "" {
+		t.Errorf("sortResults() aliases mismatch (-want +got):\n%s", diff)
+	}
+	if vuln.Affected[0].Package.Name != "lodash" || vuln.Affected[1].Package.Name != "lodash-es" {
+		t.Errorf("sortResults() affected = %v, want lodash then lodash-es", vuln.Affected)
+	}
+
+	// the aliases are copied rather than being sorted in place
+	if diff := cmp.Diff([]string{"GHSA-2", "CVE-2"}, lodashAliases); diff != "" {
+		t.Errorf("sortResults() modified the original aliases (-want +got):\n%s", diff)
+	}
+}